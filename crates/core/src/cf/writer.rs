@@ -26,7 +26,7 @@ pub struct Buffer {
 	pub b: HashMap<ChangeKey, TableMutations>,
 }
 
-#[derive(Hash, Eq, PartialEq, Debug)]
+#[derive(Clone, Hash, Eq, PartialEq, Debug)]
 #[non_exhaustive]
 pub struct ChangeKey {
 	pub ns: String,
@@ -120,6 +120,24 @@ impl Writer {
 		)
 	}
 
+	// marks returns the number of mutations buffered for each table,
+	// so that any mutations buffered afterwards can later be discarded.
+	pub(crate) fn marks(&self) -> HashMap<ChangeKey, usize> {
+		self.buf.b.iter().map(|(k, v)| (k.clone(), v.1.len())).collect()
+	}
+
+	// truncate discards all the mutations which were buffered after
+	// the specified marks were taken.
+	pub(crate) fn truncate(&mut self, marks: &HashMap<ChangeKey, usize>) {
+		self.buf.b.retain(|k, v| match marks.get(k) {
+			Some(len) => {
+				v.1.truncate(*len);
+				true
+			}
+			None => false,
+		});
+	}
+
 	// get returns all the mutations buffered for this transaction,
 	// that are to be written onto the key composed of the specified prefix + the current timestamp + the specified suffix.
 	pub(crate) fn get(&self) -> Vec<PreparedWrite> {
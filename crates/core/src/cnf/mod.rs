@@ -41,6 +41,22 @@ pub static TRANSACTION_CACHE_SIZE: LazyLock<usize> =
 pub static DATASTORE_CACHE_SIZE: LazyLock<usize> =
 	lazy_env_parse!("SURREAL_DATASTORE_CACHE_SIZE", usize, 1_000);
 
+/// Specifies the number of prepared statements which can be cached for RPC connections.
+pub static PREPARED_STATEMENT_CACHE_SIZE: LazyLock<usize> =
+	lazy_env_parse!("SURREAL_PREPARED_STATEMENT_CACHE_SIZE", usize, 1_000);
+
+/// The default number of records which are returned in each batch from an RPC query cursor.
+pub static CURSOR_BATCH_SIZE: LazyLock<usize> =
+	lazy_env_parse!("SURREAL_CURSOR_BATCH_SIZE", usize, 1_000);
+
+/// The maximum number of query cursors which can be open at once on a single RPC connection.
+pub static CURSOR_MAX_OPEN: LazyLock<usize> =
+	lazy_env_parse!("SURREAL_CURSOR_MAX_OPEN", usize, 100);
+
+/// The number of seconds after which an RPC query cursor which has not been fetched is closed.
+pub static CURSOR_IDLE_TIMEOUT: LazyLock<u64> =
+	lazy_env_parse!("SURREAL_CURSOR_IDLE_TIMEOUT", u64, 600);
+
 /// The maximum number of keys that should be scanned at once in general queries.
 pub static NORMAL_FETCH_SIZE: LazyLock<u32> = lazy_env_parse!("SURREAL_NORMAL_FETCH_SIZE", u32, 50);
 
@@ -48,6 +64,43 @@ pub static NORMAL_FETCH_SIZE: LazyLock<u32> = lazy_env_parse!("SURREAL_NORMAL_FE
 pub static EXPORT_BATCH_SIZE: LazyLock<u32> =
 	lazy_env_parse!("SURREAL_EXPORT_BATCH_SIZE", u32, 1000);
 
+/// The maximum number of records which are scanned for the statistics of a table.
+pub static INFO_STATISTICS_SCAN_LIMIT: LazyLock<usize> =
+	lazy_env_parse!("SURREAL_INFO_STATISTICS_SCAN_LIMIT", usize, 100_000);
+
+/// The maximum number of records that should be inserted at once for CSV imports.
+pub static CSV_IMPORT_BATCH_SIZE: LazyLock<usize> =
+	lazy_env_parse!("SURREAL_CSV_IMPORT_BATCH_SIZE", usize, 1000);
+
+/// The maximum number of slow queries which are kept in the slow query log.
+pub static SLOW_LOG_CAPACITY: LazyLock<usize> =
+	lazy_env_parse!("SURREAL_SLOW_LOG_CAPACITY", usize, 100);
+
+/// The number of times that a statement is retried when its transaction conflicts with another.
+/// Statements in an explicit transaction, and statements which make HTTP requests or sleep, are not retried.
+pub static TRANSACTION_CONFLICT_RETRIES: LazyLock<u32> =
+	lazy_env_parse!("SURREAL_TRANSACTION_CONFLICT_RETRIES", u32, 5);
+
+/// The number of milliseconds to wait before first retrying a statement, which doubles on each retry.
+pub static TRANSACTION_CONFLICT_BACKOFF: LazyLock<u64> =
+	lazy_env_parse!("SURREAL_TRANSACTION_CONFLICT_BACKOFF_MILLIS", u64, 10);
+
+/// The maximum number of expired records that should be deleted at once for each table.
+pub static EXPIRY_BATCH_SIZE: LazyLock<u32> =
+	lazy_env_parse!("SURREAL_EXPIRY_BATCH_SIZE", u32, 1000);
+
+/// The maximum number of queued webhooks that should be delivered at once for each database.
+pub static WEBHOOK_BATCH_SIZE: LazyLock<u32> =
+	lazy_env_parse!("SURREAL_WEBHOOK_BATCH_SIZE", u32, 100);
+
+/// The number of times that delivery of a queued webhook is attempted before it is dead-lettered.
+pub static WEBHOOK_MAX_ATTEMPTS: LazyLock<u32> =
+	lazy_env_parse!("SURREAL_WEBHOOK_MAX_ATTEMPTS", u32, 10);
+
+/// The number of seconds to wait for a response when delivering a queued webhook.
+pub static WEBHOOK_TIMEOUT: LazyLock<u64> =
+	lazy_env_parse!("SURREAL_WEBHOOK_TIMEOUT_SECONDS", u64, 30);
+
 /// The maximum number of keys that should be fetched when streaming range scans in a Scanner.
 pub static MAX_STREAM_BATCH_SIZE: LazyLock<u32> =
 	lazy_env_parse!("SURREAL_MAX_STREAM_BATCH_SIZE", u32, 1000);
@@ -64,15 +117,33 @@ pub static SCRIPTING_MAX_STACK_SIZE: LazyLock<usize> =
 pub static SCRIPTING_MAX_MEMORY_LIMIT: LazyLock<usize> =
 	lazy_env_parse!("SURREAL_SCRIPTING_MAX_MEMORY_LIMIT", usize, 2 << 20);
 
+/// The maximum size in bytes of a response body which can be received by the http functions (defaults to 64 MiB).
+pub static HTTP_MAX_RESPONSE_SIZE: LazyLock<usize> =
+	lazy_env_parse!("SURREAL_HTTP_MAX_RESPONSE_SIZE", usize, 64 << 20);
+
 /// Forward all signup/signin/authenticate query errors to a client performing authentication. Do not use in production.
 pub static INSECURE_FORWARD_ACCESS_ERRORS: LazyLock<bool> =
 	lazy_env_parse!("SURREAL_INSECURE_FORWARD_ACCESS_ERRORS", bool, false);
 
+/// The number of seconds of clock skew which is tolerated when validating the time claims of a token.
+pub static TOKEN_CLOCK_SKEW: LazyLock<u64> =
+	lazy_env_parse!("SURREAL_TOKEN_CLOCK_SKEW_SECONDS", u64, 60);
+
 #[cfg(storage)]
 /// Specifies the buffer limit for external sorting.
 pub static EXTERNAL_SORTING_BUFFER_LIMIT: LazyLock<usize> =
 	lazy_env_parse!("SURREAL_EXTERNAL_SORTING_BUFFER_LIMIT", usize, 50_000);
 
+#[cfg(storage)]
+/// Specifies the number of records above which a sort is moved to the temporary directory.
+pub static EXTERNAL_SORTING_ROW_THRESHOLD: LazyLock<usize> =
+	lazy_env_parse!("SURREAL_EXTERNAL_SORTING_ROW_THRESHOLD", usize, 100_000);
+
+#[cfg(storage)]
+/// Specifies the approximate size in bytes above which a sort is moved to the temporary directory (defaults to 256 MiB).
+pub static EXTERNAL_SORTING_BYTE_THRESHOLD: LazyLock<usize> =
+	lazy_env_parse!("SURREAL_EXTERNAL_SORTING_BYTE_THRESHOLD", usize, 256 << 20);
+
 /// Specifies whether GraphQL querying and schema definition is enabled.
 pub static GRAPHQL_ENABLE: LazyLock<bool> =
 	lazy_env_parse!("SURREAL_EXPERIMENTAL_GRAPHQL", bool, false);
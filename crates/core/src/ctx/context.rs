@@ -3,16 +3,24 @@ use crate::ctx::canceller::Canceller;
 use crate::ctx::reason::Reason;
 #[cfg(feature = "http")]
 use crate::dbs::capabilities::NetTarget;
+use crate::dbs::connections::Connections;
+use crate::dbs::memory::MemoryBudget;
+use crate::dbs::quota::Namespaces;
+use crate::dbs::slowlog::Recorder;
 use crate::dbs::{Capabilities, Notification};
 use crate::err::Error;
 use crate::idx::planner::executor::QueryExecutor;
 use crate::idx::planner::{IterationStage, QueryPlanner};
 use crate::idx::trees::store::IndexStores;
 use crate::kvs::cache::ds::Cache;
+use crate::kvs::encryption::Keyring;
+#[cfg(not(target_arch = "wasm32"))]
+use crate::kvs::FieldMigrator;
 #[cfg(not(target_arch = "wasm32"))]
 use crate::kvs::IndexBuilder;
 use crate::kvs::Transaction;
 use crate::sql::value::Value;
+use crate::testing::Generator;
 use async_channel::Sender;
 use std::borrow::Cow;
 use std::collections::HashMap;
@@ -36,6 +44,8 @@ pub struct MutableContext {
 	deadline: Option<Instant>,
 	// Whether or not this context is cancelled.
 	cancelled: Arc<AtomicBool>,
+	// Whether a side effect which can not be undone has been performed.
+	side_effects: Arc<AtomicBool>,
 	// A collection of read only values stored in this context.
 	values: HashMap<Cow<'static, str>, Arc<Value>>,
 	// Stores the notification channel if available
@@ -53,8 +63,23 @@ pub struct MutableContext {
 	// The index concurrent builders
 	#[cfg(not(target_arch = "wasm32"))]
 	index_builder: Option<IndexBuilder>,
+	// The field concurrent migrators
+	#[cfg(not(target_arch = "wasm32"))]
+	field_migrator: Option<FieldMigrator>,
 	// Capabilities
 	capabilities: Arc<Capabilities>,
+	// The keys used to encrypt fields
+	encryption: Option<Arc<Keyring>>,
+	// The slow query log recorder for this query
+	slow_log: Option<Recorder>,
+	// The registry of active client connections
+	connections: Option<Arc<Connections>>,
+	// The queries running in each namespace
+	namespaces: Option<Arc<Namespaces>>,
+	// The memory budgets of the intermediate results
+	memory_budget: Option<Arc<MemoryBudget>>,
+	// The generator of the current time and random ids
+	generator: Option<Arc<dyn Generator>>,
 	#[cfg(storage)]
 	// The temporary directory
 	temporary_directory: Option<Arc<PathBuf>>,
@@ -97,15 +122,24 @@ impl MutableContext {
 			parent: None,
 			deadline: None,
 			cancelled: Arc::new(AtomicBool::new(false)),
+			side_effects: Arc::new(AtomicBool::new(false)),
 			notifications: None,
 			query_planner: None,
 			query_executor: None,
 			iteration_stage: None,
 			capabilities: Arc::new(Capabilities::default()),
+			encryption: None,
+			slow_log: None,
+			connections: None,
+			namespaces: None,
+			memory_budget: None,
+			generator: None,
 			index_stores: IndexStores::default(),
 			cache: None,
 			#[cfg(not(target_arch = "wasm32"))]
 			index_builder: None,
+			#[cfg(not(target_arch = "wasm32"))]
+			field_migrator: None,
 			#[cfg(storage)]
 			temporary_directory: None,
 			transaction: None,
@@ -119,15 +153,24 @@ impl MutableContext {
 			values: HashMap::default(),
 			deadline: parent.deadline,
 			cancelled: Arc::new(AtomicBool::new(false)),
+			side_effects: parent.side_effects.clone(),
 			notifications: parent.notifications.clone(),
 			query_planner: parent.query_planner.clone(),
 			query_executor: parent.query_executor.clone(),
 			iteration_stage: parent.iteration_stage.clone(),
 			capabilities: parent.capabilities.clone(),
+			encryption: parent.encryption.clone(),
+			slow_log: parent.slow_log.clone(),
+			connections: parent.connections.clone(),
+			namespaces: parent.namespaces.clone(),
+			memory_budget: parent.memory_budget.clone(),
+			generator: parent.generator.clone(),
 			index_stores: parent.index_stores.clone(),
 			cache: parent.cache.clone(),
 			#[cfg(not(target_arch = "wasm32"))]
 			index_builder: parent.index_builder.clone(),
+			#[cfg(not(target_arch = "wasm32"))]
+			field_migrator: parent.field_migrator.clone(),
 			#[cfg(storage)]
 			temporary_directory: parent.temporary_directory.clone(),
 			transaction: parent.transaction.clone(),
@@ -144,15 +187,24 @@ impl MutableContext {
 			values: HashMap::default(),
 			deadline: parent.deadline,
 			cancelled: Arc::new(AtomicBool::new(false)),
+			side_effects: parent.side_effects.clone(),
 			notifications: parent.notifications.clone(),
 			query_planner: parent.query_planner.clone(),
 			query_executor: parent.query_executor.clone(),
 			iteration_stage: parent.iteration_stage.clone(),
 			capabilities: parent.capabilities.clone(),
+			encryption: parent.encryption.clone(),
+			slow_log: parent.slow_log.clone(),
+			connections: parent.connections.clone(),
+			namespaces: parent.namespaces.clone(),
+			memory_budget: parent.memory_budget.clone(),
+			generator: parent.generator.clone(),
 			index_stores: parent.index_stores.clone(),
 			cache: parent.cache.clone(),
 			#[cfg(not(target_arch = "wasm32"))]
 			index_builder: parent.index_builder.clone(),
+			#[cfg(not(target_arch = "wasm32"))]
+			field_migrator: parent.field_migrator.clone(),
 			#[cfg(storage)]
 			temporary_directory: parent.temporary_directory.clone(),
 			transaction: parent.transaction.clone(),
@@ -169,15 +221,24 @@ impl MutableContext {
 			values: HashMap::default(),
 			deadline: None,
 			cancelled: Arc::new(AtomicBool::new(false)),
+			side_effects: from.side_effects.clone(),
 			notifications: from.notifications.clone(),
 			query_planner: from.query_planner.clone(),
 			query_executor: from.query_executor.clone(),
 			iteration_stage: from.iteration_stage.clone(),
 			capabilities: from.capabilities.clone(),
+			encryption: from.encryption.clone(),
+			slow_log: from.slow_log.clone(),
+			connections: from.connections.clone(),
+			namespaces: from.namespaces.clone(),
+			memory_budget: from.memory_budget.clone(),
+			generator: from.generator.clone(),
 			index_stores: from.index_stores.clone(),
 			cache: from.cache.clone(),
 			#[cfg(not(target_arch = "wasm32"))]
 			index_builder: from.index_builder.clone(),
+			#[cfg(not(target_arch = "wasm32"))]
+			field_migrator: from.field_migrator.clone(),
 			#[cfg(storage)]
 			temporary_directory: from.temporary_directory.clone(),
 			transaction: None,
@@ -193,6 +254,7 @@ impl MutableContext {
 		index_stores: IndexStores,
 		cache: Arc<Cache>,
 		#[cfg(not(target_arch = "wasm32"))] index_builder: IndexBuilder,
+		#[cfg(not(target_arch = "wasm32"))] field_migrator: FieldMigrator,
 		#[cfg(storage)] temporary_directory: Option<Arc<PathBuf>>,
 	) -> Result<MutableContext, Error> {
 		let mut ctx = Self {
@@ -200,15 +262,24 @@ impl MutableContext {
 			parent: None,
 			deadline: None,
 			cancelled: Arc::new(AtomicBool::new(false)),
+			side_effects: Arc::new(AtomicBool::new(false)),
 			notifications: None,
 			query_planner: None,
 			query_executor: None,
 			iteration_stage: None,
 			capabilities: Arc::new(capabilities),
+			encryption: None,
+			slow_log: None,
+			connections: None,
+			namespaces: None,
+			memory_budget: None,
+			generator: None,
 			index_stores,
 			cache: Some(cache),
 			#[cfg(not(target_arch = "wasm32"))]
 			index_builder: Some(index_builder),
+			#[cfg(not(target_arch = "wasm32"))]
+			field_migrator: Some(field_migrator),
 			#[cfg(storage)]
 			temporary_directory,
 			transaction: None,
@@ -334,6 +405,12 @@ impl MutableContext {
 		self.index_builder.as_ref()
 	}
 
+	/// Get the field_migrator for this context/ds
+	#[cfg(not(target_arch = "wasm32"))]
+	pub(crate) fn get_field_migrator(&self) -> Option<&FieldMigrator> {
+		self.field_migrator.as_ref()
+	}
+
 	// Get the current datastore cache
 	pub(crate) fn get_cache(&self) -> Option<Arc<Cache>> {
 		self.cache.clone()
@@ -367,6 +444,22 @@ impl MutableContext {
 		matches!(self.done(), Some(Reason::Timedout))
 	}
 
+	/// Record that a side effect which can not be undone, such as a HTTP
+	/// request, has been performed, so that the statement is not retried.
+	pub(crate) fn add_side_effect(&self) {
+		self.side_effects.store(true, Ordering::Relaxed);
+	}
+
+	/// Check if a side effect which can not be undone has been performed.
+	pub(crate) fn has_side_effects(&self) -> bool {
+		self.side_effects.load(Ordering::Relaxed)
+	}
+
+	/// Forget any side effects, before a statement is run again.
+	pub(crate) fn clear_side_effects(&self) {
+		self.side_effects.store(false, Ordering::Relaxed);
+	}
+
 	#[cfg(storage)]
 	/// Return the location of the temporary directory if any
 	pub(crate) fn temporary_directory(&self) -> Option<&Arc<PathBuf>> {
@@ -386,6 +479,22 @@ impl MutableContext {
 		}
 	}
 
+	/// Get the names of the values which are accessible from this
+	/// context, excluding the protected session parameters.
+	pub(crate) fn value_names(&self) -> Vec<String> {
+		let mut names: Vec<String> = match (&self.parent, self.isolated) {
+			(Some(p), false) => p.value_names(),
+			_ => Vec::new(),
+		};
+		for key in self.values.keys() {
+			if !PROTECTED_PARAM_NAMES.contains(&key.as_ref()) && !names.iter().any(|v| v == key) {
+				names.push(key.to_string());
+			}
+		}
+		names.sort();
+		names
+	}
+
 	/// Get a 'static view into the cancellation status.
 	#[cfg(feature = "scripting")]
 	pub(crate) fn cancellation(&self) -> crate::ctx::cancellation::Cancellation {
@@ -406,6 +515,66 @@ impl MutableContext {
 		self.capabilities = Arc::new(caps);
 	}
 
+	/// Set the keys used to encrypt fields for this context
+	pub(crate) fn add_encryption(&mut self, keyring: Option<Arc<Keyring>>) {
+		self.encryption = keyring;
+	}
+
+	/// Get the keys used to encrypt fields for this context
+	pub(crate) fn get_encryption(&self) -> Option<&Arc<Keyring>> {
+		self.encryption.as_ref()
+	}
+
+	/// Set the slow query log recorder for this context
+	pub(crate) fn add_slow_log(&mut self, recorder: Option<Recorder>) {
+		self.slow_log = recorder;
+	}
+
+	/// Get the slow query log recorder for this context
+	pub(crate) fn get_slow_log(&self) -> Option<&Recorder> {
+		self.slow_log.as_ref()
+	}
+
+	/// Set the registry of active client connections for this context
+	pub(crate) fn add_connections(&mut self, connections: Option<Arc<Connections>>) {
+		self.connections = connections;
+	}
+
+	/// Get the registry of active client connections for this context
+	pub(crate) fn get_connections(&self) -> Option<&Arc<Connections>> {
+		self.connections.as_ref()
+	}
+
+	/// Set the queries running in each namespace for this context
+	pub(crate) fn add_namespaces(&mut self, namespaces: Option<Arc<Namespaces>>) {
+		self.namespaces = namespaces;
+	}
+
+	/// Get the queries running in each namespace for this context
+	pub(crate) fn get_namespaces(&self) -> Option<&Arc<Namespaces>> {
+		self.namespaces.as_ref()
+	}
+
+	/// Set the memory budgets of the intermediate results for this context
+	pub(crate) fn add_memory_budget(&mut self, budget: Option<Arc<MemoryBudget>>) {
+		self.memory_budget = budget;
+	}
+
+	/// Get the memory budgets of the intermediate results for this context
+	pub(crate) fn get_memory_budget(&self) -> Option<&Arc<MemoryBudget>> {
+		self.memory_budget.as_ref()
+	}
+
+	/// Set the generator of the current time and random ids for this context
+	pub(crate) fn add_generator(&mut self, generator: Option<Arc<dyn Generator>>) {
+		self.generator = generator;
+	}
+
+	/// Get the generator of the current time and random ids for this context
+	pub(crate) fn get_generator(&self) -> Option<&Arc<dyn Generator>> {
+		self.generator.as_ref()
+	}
+
 	/// Get the capabilities for this context
 	#[allow(dead_code)]
 	pub(crate) fn get_capabilities(&self) -> Arc<Capabilities> {
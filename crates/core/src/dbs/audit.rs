@@ -0,0 +1,246 @@
+//! Records authentication attempts, permission failures, and changes to the
+//! schema or data, as JSON lines written to an append-only audit log.
+use crate::ctx::MutableContext;
+use crate::dbs::{Options, Session};
+use crate::err::Error;
+use crate::iam::Auth;
+use crate::sql::Value;
+use chrono::Utc;
+use serde_json::json;
+use std::fmt::{self, Display};
+use std::io::Write;
+use std::str::FromStr;
+use std::sync::Mutex;
+
+const TARGET: &str = "surrealdb::core::dbs::audit";
+
+/// The categories of events which can be recorded in the audit log
+#[derive(Clone, Copy, Debug, Eq, PartialEq)]
+#[non_exhaustive]
+pub enum Category {
+	/// Sign in, sign up, and authentication attempts
+	Auth,
+	/// Statements which were denied due to insufficient permissions
+	Permission,
+	/// Statements which define, alter, or remove the schema
+	Schema,
+	/// Statements which create, modify, or delete data
+	Data,
+}
+
+impl Category {
+	/// The category of a statement, from the kind of the statement
+	pub(crate) fn of(kind: &str) -> Option<Self> {
+		match kind {
+			"access" => Some(Self::Auth),
+			"alter" | "define" | "rebuild" | "remove" => Some(Self::Schema),
+			"create" | "delete" | "insert" | "relate" | "update" | "upsert" => Some(Self::Data),
+			_ => None,
+		}
+	}
+}
+
+impl Display for Category {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		match self {
+			Self::Auth => f.write_str("auth"),
+			Self::Permission => f.write_str("permission"),
+			Self::Schema => f.write_str("schema"),
+			Self::Data => f.write_str("data"),
+		}
+	}
+}
+
+impl FromStr for Category {
+	type Err = Error;
+	fn from_str(s: &str) -> Result<Self, Self::Err> {
+		match s.to_ascii_lowercase().as_str() {
+			"auth" => Ok(Self::Auth),
+			"permission" => Ok(Self::Permission),
+			"schema" => Ok(Self::Schema),
+			"data" => Ok(Self::Data),
+			_ => Err(Error::Ds(format!("'{s}' is not a valid audit log category"))),
+		}
+	}
+}
+
+/// Where an audited event originated from
+#[derive(Debug, Default)]
+pub(crate) struct Origin {
+	/// The id of the connection
+	id: Option<String>,
+	/// The IP address of the connection
+	ip: Option<String>,
+	/// The selected namespace
+	ns: Option<String>,
+	/// The selected database
+	db: Option<String>,
+}
+
+impl From<&Session> for Origin {
+	fn from(v: &Session) -> Self {
+		Self {
+			id: v.id.clone(),
+			ip: v.ip.clone(),
+			ns: v.ns.clone(),
+			db: v.db.clone(),
+		}
+	}
+}
+
+impl Origin {
+	/// The origin of a statement which is run within a context
+	pub(crate) fn new(ctx: &MutableContext, opt: &Options) -> Self {
+		let session = ctx.value("session").unwrap_or(&Value::None);
+		let field = |key: &str| match session.pick(&[key.into()]) {
+			Value::Strand(v) => Some(v.0),
+			_ => None,
+		};
+		Self {
+			id: field("id"),
+			ip: field("ip"),
+			ns: opt.ns().ok().map(str::to_owned),
+			db: opt.db().ok().map(str::to_owned),
+		}
+	}
+}
+
+/// An append-only log of audited events
+pub struct AuditLog {
+	/// The categories of events which are recorded
+	categories: Vec<Category>,
+	/// The destination of the audit log
+	writer: Mutex<Box<dyn Write + Send>>,
+}
+
+impl fmt::Debug for AuditLog {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		f.debug_struct("AuditLog").field("categories", &self.categories).finish()
+	}
+}
+
+impl AuditLog {
+	/// Create a new audit log, which writes events
+	/// in the specified categories to the writer
+	pub fn new<W>(categories: Vec<Category>, writer: W) -> Self
+	where
+		W: Write + Send + 'static,
+	{
+		Self {
+			categories,
+			writer: Mutex::new(Box::new(writer)),
+		}
+	}
+
+	/// Whether events in this category are recorded
+	pub(crate) fn enabled(&self, category: Category) -> bool {
+		self.categories.contains(&category)
+	}
+
+	/// Record an event in the audit log
+	pub(crate) fn record(
+		&self,
+		category: Category,
+		action: &str,
+		auth: &Auth,
+		origin: Origin,
+		statement: Option<String>,
+		result: Result<(), &Error>,
+	) {
+		if !self.enabled(category) {
+			return;
+		}
+		let mut event = json!({
+			"time": Utc::now().to_rfc3339(),
+			"category": category.to_string(),
+			"action": action,
+			"actor": {
+				"id": auth.id(),
+				"level": auth.level().to_string(),
+			},
+			"session": origin.id,
+			"ip": origin.ip,
+			"ns": origin.ns,
+			"db": origin.db,
+			"success": result.is_ok(),
+		});
+		if let Some(v) = statement {
+			event["statement"] = v.into();
+		}
+		if let Err(e) = result {
+			event["error"] = e.to_string().into();
+		}
+		let mut writer = self.writer.lock().unwrap_or_else(|e| e.into_inner());
+		if let Err(e) = writeln!(writer, "{event}").and_then(|_| writer.flush()) {
+			error!(target: TARGET, "Unable to write to the audit log: {e}");
+		}
+	}
+}
+
+/// Whether an error was caused by insufficient permissions
+pub(crate) fn is_permission_error(err: &Error) -> bool {
+	matches!(
+		err,
+		Error::IamError(_)
+			| Error::NsNotAllowed { .. }
+			| Error::DbNotAllowed { .. }
+			| Error::TablePermissions { .. }
+			| Error::ParamPermissions { .. }
+			| Error::FunctionPermissions { .. }
+	)
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+	use std::sync::Arc;
+
+	#[derive(Clone, Default)]
+	struct Buffer(Arc<Mutex<Vec<u8>>>);
+
+	impl Write for Buffer {
+		fn write(&mut self, buf: &[u8]) -> std::io::Result<usize> {
+			self.0.lock().unwrap().write(buf)
+		}
+		fn flush(&mut self) -> std::io::Result<()> {
+			Ok(())
+		}
+	}
+
+	#[test]
+	fn records_enabled_categories() {
+		let buf = Buffer::default();
+		let log = AuditLog::new(vec![Category::Schema], buf.clone());
+		let auth = Auth::for_root(crate::iam::Role::Owner);
+		let origin = || Origin {
+			ip: Some("127.0.0.1".to_owned()),
+			..Default::default()
+		};
+		log.record(Category::Data, "create", &auth, origin(), None, Ok(()));
+		log.record(
+			Category::Schema,
+			"define",
+			&auth,
+			origin(),
+			Some("DEFINE TABLE person".to_owned()),
+			Ok(()),
+		);
+		let out = String::from_utf8(buf.0.lock().unwrap().clone()).unwrap();
+		let lines: Vec<_> = out.lines().collect();
+		assert_eq!(lines.len(), 1);
+		let event: serde_json::Value = serde_json::from_str(lines[0]).unwrap();
+		assert_eq!(event["category"], "schema");
+		assert_eq!(event["action"], "define");
+		assert_eq!(event["ip"], "127.0.0.1");
+		assert_eq!(event["statement"], "DEFINE TABLE person");
+		assert_eq!(event["success"], true);
+	}
+
+	#[test]
+	fn parses_categories() {
+		assert_eq!("AUTH".parse::<Category>().unwrap(), Category::Auth);
+		assert_eq!(Category::of("define"), Some(Category::Schema));
+		assert_eq!(Category::of("select"), None);
+		assert!("other".parse::<Category>().is_err());
+	}
+}
@@ -244,9 +244,11 @@ pub enum RouteTarget {
 	Sql,
 	Signin,
 	Signup,
+	Refresh,
 	Key,
 	Ml,
 	GraphQL,
+	Metrics,
 }
 
 // impl display
@@ -262,9 +264,11 @@ impl fmt::Display for RouteTarget {
 			RouteTarget::Sql => write!(f, "sql"),
 			RouteTarget::Signin => write!(f, "signin"),
 			RouteTarget::Signup => write!(f, "signup"),
+			RouteTarget::Refresh => write!(f, "refresh"),
 			RouteTarget::Key => write!(f, "key"),
 			RouteTarget::Ml => write!(f, "ml"),
 			RouteTarget::GraphQL => write!(f, "graphql"),
+			RouteTarget::Metrics => write!(f, "metrics"),
 		}
 	}
 }
@@ -299,9 +303,11 @@ impl std::str::FromStr for RouteTarget {
 			"sql" => Ok(RouteTarget::Sql),
 			"signin" => Ok(RouteTarget::Signin),
 			"signup" => Ok(RouteTarget::Signup),
+			"refresh" => Ok(RouteTarget::Refresh),
 			"key" => Ok(RouteTarget::Key),
 			"ml" => Ok(RouteTarget::Ml),
 			"graphql" => Ok(RouteTarget::GraphQL),
+			"metrics" => Ok(RouteTarget::Metrics),
 			_ => Err(ParseRouteTargetError),
 		}
 	}
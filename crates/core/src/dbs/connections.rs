@@ -0,0 +1,241 @@
+//! Tracks the active client connections, and the queries which they are
+//! running, so that they can be inspected with `INFO FOR CONNECTIONS` and
+//! terminated with `KILL CONNECTION`.
+use crate::ctx::canceller::Canceller;
+use crate::dbs::Session;
+use crate::sql::{Datetime, Value};
+use std::collections::HashMap;
+use std::fmt;
+use std::sync::atomic::{AtomicU64, Ordering};
+use std::sync::{Arc, Mutex};
+use uuid::Uuid;
+
+const TARGET: &str = "surrealdb::core::dbs::connections";
+
+/// A registry of the client connections which are currently active
+#[derive(Default)]
+pub struct Connections {
+	/// The active connections, keyed by their id
+	entries: Mutex<HashMap<String, Connection>>,
+	/// The id assigned to the next tracked query
+	counter: AtomicU64,
+}
+
+impl fmt::Debug for Connections {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		let entries = self.entries.lock().unwrap_or_else(|e| e.into_inner());
+		f.debug_struct("Connections").field("entries", &entries.len()).finish()
+	}
+}
+
+/// A single active client connection
+struct Connection {
+	/// The protocol used by the connection
+	protocol: String,
+	/// The IP address of the client
+	ip: Option<String>,
+	/// When the connection was opened
+	started: Datetime,
+	/// When the connection last ran a query
+	active: Option<Datetime>,
+	/// The namespace selected by the connection
+	ns: Option<String>,
+	/// The database selected by the connection
+	db: Option<String>,
+	/// The actor which the connection is authenticated as
+	actor: Option<(String, String)>,
+	/// The queries which are currently running
+	queries: HashMap<u64, Canceller>,
+	/// Closes a persistent connection, such as a WebSocket
+	close: Option<Box<dyn Fn() + Send + Sync>>,
+}
+
+impl Connection {
+	fn new(protocol: &str, ip: Option<String>) -> Self {
+		Self {
+			protocol: protocol.to_owned(),
+			ip,
+			started: Datetime::default(),
+			active: None,
+			ns: None,
+			db: None,
+			actor: None,
+			queries: HashMap::new(),
+			close: None,
+		}
+	}
+
+	/// Cancel the running queries, and close the connection if it is persistent
+	fn kill(&self, id: &str) {
+		debug!(target: TARGET, "Killing connection {id}");
+		for canceller in self.queries.values() {
+			canceller.cancel();
+		}
+		if let Some(close) = &self.close {
+			close();
+		}
+	}
+}
+
+impl Connections {
+	/// Register a persistent connection, which is closed by calling `close`
+	pub fn register<F>(&self, id: &str, protocol: &str, ip: Option<String>, close: F)
+	where
+		F: Fn() + Send + Sync + 'static,
+	{
+		let mut entries = self.entries.lock().unwrap_or_else(|e| e.into_inner());
+		let entry = entries.entry(id.to_owned()).or_insert_with(|| Connection::new(protocol, ip));
+		entry.close = Some(Box::new(close));
+	}
+
+	/// Remove a persistent connection, once it has been closed
+	pub fn unregister(&self, id: &str) {
+		self.entries.lock().unwrap_or_else(|e| e.into_inner()).remove(id);
+	}
+
+	/// Track a query which is run by a session, until the returned guard is
+	/// dropped. Sessions without a connection id are not tracked. Only the
+	/// realtime sessions of a registered connection are tracked under their
+	/// connection id, as any other session id is specified by the client.
+	/// Other sessions are tracked as short-lived HTTP connections, with an
+	/// id which is generated by the server.
+	pub(crate) fn track(self: &Arc<Self>, sess: &Session, canceller: Canceller) -> Option<Query> {
+		let id = sess.id.as_ref()?;
+		let query = self.counter.fetch_add(1, Ordering::Relaxed);
+		let mut entries = self.entries.lock().unwrap_or_else(|e| e.into_inner());
+		let id = match sess.rt && entries.contains_key(id) {
+			true => id.clone(),
+			false => Uuid::now_v7().to_string(),
+		};
+		let entry =
+			entries.entry(id.clone()).or_insert_with(|| Connection::new("http", sess.ip.clone()));
+		entry.active = Some(Datetime::default());
+		entry.ns.clone_from(&sess.ns);
+		entry.db.clone_from(&sess.db);
+		entry.actor = match sess.au.is_anon() {
+			true => None,
+			false => Some((sess.au.id().to_owned(), sess.au.level().to_string())),
+		};
+		entry.queries.insert(query, canceller);
+		Some(Query {
+			registry: self.clone(),
+			id,
+			query,
+		})
+	}
+
+	/// Cancel the running queries of a connection, and close the connection
+	/// if it is persistent, returning false if the connection does not exist
+	pub(crate) fn kill(&self, id: &str) -> bool {
+		let Some(entry) = self.entries.lock().unwrap_or_else(|e| e.into_inner()).remove(id) else {
+			return false;
+		};
+		entry.kill(id);
+		true
+	}
+
+	/// Cancel the running queries of every connection, and close
+	/// every persistent connection, when the server is shutting down
+	pub fn kill_all(&self) {
+		let entries = std::mem::take(&mut *self.entries.lock().unwrap_or_else(|e| e.into_inner()));
+		for (id, entry) in entries {
+			entry.kill(&id);
+		}
+	}
+
+	/// The active connections, ordered by when they were opened
+	pub(crate) fn list(&self) -> Value {
+		let entries = self.entries.lock().unwrap_or_else(|e| e.into_inner());
+		let mut list: Vec<_> = entries.iter().collect();
+		list.sort_by(|a, b| a.1.started.cmp(&b.1.started).then_with(|| a.0.cmp(b.0)));
+		Value::Array(
+			list.into_iter()
+				.map(|(id, v)| {
+					Value::from(map! {
+						"id".to_string() => id.clone().into(),
+						"protocol".to_string() => v.protocol.clone().into(),
+						"ip".to_string(), if let Some(ip) = &v.ip => ip.clone().into(),
+						"started".to_string() => v.started.clone().into(),
+						"active".to_string(), if let Some(at) = &v.active => at.clone().into(),
+						"ns".to_string(), if let Some(ns) = &v.ns => ns.clone().into(),
+						"db".to_string(), if let Some(db) = &v.db => db.clone().into(),
+						"actor".to_string(), if let Some((id, level)) = &v.actor => Value::from(map! {
+							"id".to_string() => id.clone().into(),
+							"level".to_string() => level.clone().into(),
+						}),
+						"queries".to_string() => v.queries.len().into(),
+					})
+				})
+				.collect(),
+		)
+	}
+}
+
+/// A query which is being tracked in the connection registry
+pub(crate) struct Query {
+	registry: Arc<Connections>,
+	/// The id of the connection running the query
+	id: String,
+	/// The id of the query within the registry
+	query: u64,
+}
+
+impl Drop for Query {
+	fn drop(&mut self) {
+		let mut entries = self.registry.entries.lock().unwrap_or_else(|e| e.into_inner());
+		if let Some(entry) = entries.get_mut(&self.id) {
+			entry.queries.remove(&self.query);
+			// Short-lived connections end with their last query
+			if entry.close.is_none() && entry.queries.is_empty() {
+				entries.remove(&self.id);
+			}
+		}
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+	use std::sync::atomic::AtomicBool;
+
+	#[test]
+	fn tracks_and_kills_connections() {
+		let registry = Arc::new(Connections::default());
+		let closed = Arc::new(AtomicBool::new(false));
+		let flag = closed.clone();
+		registry.register("one", "ws", None, move || flag.store(true, Ordering::Relaxed));
+		// Queries from unregistered sessions are tracked until they finish
+		let mut sess = Session::owner().with_ns("test");
+		sess.id = Some("two".to_owned());
+		let query = registry.track(&sess, Canceller::default());
+		assert!(query.is_some());
+		let Value::Array(list) = registry.list() else {
+			panic!("expected an array");
+		};
+		assert_eq!(list.len(), 2);
+		drop(query);
+		let Value::Array(list) = registry.list() else {
+			panic!("expected an array");
+		};
+		assert_eq!(list.len(), 1);
+		// Sessions without an id are not tracked
+		sess.id = None;
+		assert!(registry.track(&sess, Canceller::default()).is_none());
+		// Sessions which are not realtime can not use a registered connection
+		sess.id = Some("one".to_owned());
+		let query = registry.track(&sess, Canceller::default());
+		assert!(query.as_ref().is_some_and(|q| q.id != "one"));
+		drop(query);
+		// Killing a connection cancels its queries and closes it
+		let cancelled = Arc::new(AtomicBool::new(false));
+		sess.rt = true;
+		let query = registry.track(&sess, Canceller::new(cancelled.clone()));
+		assert!(query.as_ref().is_some_and(|q| q.id == "one"));
+		assert!(registry.kill("one"));
+		assert!(cancelled.load(Ordering::Relaxed));
+		assert!(closed.load(Ordering::Relaxed));
+		assert!(!registry.kill("one"));
+		drop(query);
+		assert_eq!(registry.list(), Value::Array(Default::default()));
+	}
+}
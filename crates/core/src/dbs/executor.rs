@@ -1,7 +1,11 @@
+use crate::cnf::{TRANSACTION_CONFLICT_BACKOFF, TRANSACTION_CONFLICT_RETRIES};
 use crate::ctx::reason::Reason;
 use crate::ctx::Context;
+use crate::dbs::audit::{self, Category, Origin};
+use crate::dbs::metrics::{self, Event};
 use crate::dbs::response::Response;
 use crate::dbs::Force;
+use crate::dbs::Notification;
 use crate::dbs::Options;
 use crate::dbs::QueryType;
 use crate::err::Error;
@@ -14,9 +18,10 @@ use crate::sql::paths::DB;
 use crate::sql::paths::NS;
 use crate::sql::query::Query;
 use crate::sql::statement::Statement;
-use crate::sql::statements::{OptionStatement, UseStatement};
+use crate::sql::statements::{BeginStatement, OptionStatement, UseStatement};
 use crate::sql::value::Value;
 use crate::sql::Base;
+use async_channel::Receiver;
 use futures::{Stream, StreamExt};
 use reblessive::TreeStack;
 use std::pin::{pin, Pin};
@@ -31,6 +36,16 @@ use wasm_bindgen_futures::spawn_local as spawn;
 
 const TARGET: &str = "surrealdb::core::dbs";
 
+/// A savepoint created within a transaction block
+struct Savepoint {
+	/// The name of the savepoint
+	name: String,
+	/// The number of results when the savepoint was created
+	results: usize,
+	/// The number of buffered notifications when the savepoint was created
+	notifications: usize,
+}
+
 pub struct Executor {
 	stack: TreeStack,
 	results: Vec<Response>,
@@ -98,8 +113,54 @@ impl Executor {
 		Ok(())
 	}
 
+	/// Prepares to record a statement in the slow query log, returning
+	/// the text of the statement if it has been sampled for logging.
+	fn start_slow_log(&self, stmt: &Statement) -> Option<String> {
+		self.ctx.get_slow_log().filter(|v| v.start()).map(|_| stmt.to_string())
+	}
+
+	/// Records a statement in the slow query log, if it took too long to run.
+	fn finish_slow_log(&self, slow: Option<String>, time: Duration, res: &Result<Value, Error>) {
+		if let (Some(log), Some(text)) = (self.ctx.get_slow_log(), slow) {
+			log.finish(&self.ctx, &self.opt, text, time, res);
+		}
+	}
+
+	/// Prepares to record a statement in the audit log, returning the
+	/// text of the statement if it may need to be recorded.
+	fn start_audit(kvs: &Datastore, kind: &str, stmt: &Statement) -> Option<String> {
+		let log = kvs.audit_log()?;
+		let audited =
+			log.enabled(Category::Permission) || Category::of(kind).is_some_and(|c| log.enabled(c));
+		audited.then(|| stmt.to_string())
+	}
+
+	/// Records a statement in the audit log, if its category is audited.
+	fn finish_audit(
+		&self,
+		kvs: &Datastore,
+		kind: &str,
+		text: Option<String>,
+		res: &Result<Value, Error>,
+	) {
+		let (Some(log), Some(text)) = (kvs.audit_log(), text) else {
+			return;
+		};
+		let category = match res {
+			Err(e) if audit::is_permission_error(e) && log.enabled(Category::Permission) => {
+				Category::Permission
+			}
+			_ => match Category::of(kind) {
+				Some(v) => v,
+				None => return,
+			},
+		};
+		let origin = Origin::new(&self.ctx, &self.opt);
+		log.record(category, kind, &self.opt.auth, origin, Some(text), res.as_ref().map(|_| ()));
+	}
+
 	/// Executes a statement which needs a transaction with the supplied transaction.
-	#[instrument(level = "debug", name = "executor", target = "surrealdb::core::dbs", skip_all)]
+	#[instrument(level = "debug", name = "executor", target = "surrealdb::core::dbs", skip_all, fields(statement = stmt.kind()))]
 	async fn execute_transaction_statement(
 		&mut self,
 		txn: Arc<Transaction>,
@@ -158,6 +219,67 @@ impl Executor {
 		return res;
 	}
 
+	/// Executes a savepoint statement within a transaction block.
+	async fn execute_savepoint_statement(
+		&mut self,
+		txn: &Transaction,
+		savepoints: &mut Vec<Savepoint>,
+		receiver: Option<&Receiver<Notification>>,
+		pending: &mut Vec<Notification>,
+		stmt: Statement,
+	) -> Result<Value, Error> {
+		// Find the most recent savepoint with the specified name
+		let find =
+			|savepoints: &[Savepoint], name: &str| savepoints.iter().rposition(|v| v.name == name);
+		match stmt {
+			Statement::Savepoint(stm) => {
+				// Buffer the notifications sent so far, so
+				// that any sent afterwards can be discarded
+				if let Some(recv) = receiver {
+					while let Ok(x) = recv.try_recv() {
+						pending.push(x);
+					}
+				}
+				txn.lock().await.new_user_save_point(&stm.name);
+				// The result of this statement is pushed afterwards
+				savepoints.push(Savepoint {
+					name: stm.name.0,
+					results: self.results.len() + 1,
+					notifications: pending.len(),
+				});
+			}
+			Statement::Rollback(stm) => {
+				// The savepoint remains after rolling back to it
+				txn.lock().await.rollback_to_user_save_point(&stm.name).await?;
+				// Any cached definitions may have been reverted
+				txn.clear();
+				// Savepoints created within a block are not tracked here,
+				// so the results of earlier statements are left unchanged
+				if let Some(depth) = find(savepoints, stm.name.as_str()) {
+					// Discard the notifications sent since the savepoint
+					if let Some(recv) = receiver {
+						while recv.try_recv().is_ok() {}
+					}
+					pending.truncate(savepoints[depth].notifications);
+					// Update the results indicating cancelation
+					for res in &mut self.results[savepoints[depth].results..] {
+						res.query_type = QueryType::Other;
+						res.result = Err(Error::QueryCancelled);
+					}
+					savepoints.truncate(depth + 1);
+				}
+			}
+			Statement::Release(stm) => {
+				txn.lock().await.release_user_save_point(&stm.name)?;
+				if let Some(depth) = find(savepoints, stm.name.as_str()) {
+					savepoints.truncate(depth);
+				}
+			}
+			_ => return Err(fail!("Unexpected statement type encountered: {stmt:?}")),
+		}
+		Ok(Value::None)
+	}
+
 	/// Execute a query not wrapped in a transaction block.
 	async fn execute_bare_statement(
 		&mut self,
@@ -178,65 +300,113 @@ impl Executor {
 		match stmt {
 			// These statements don't need a transaction.
 			Statement::Use(stmt) => self.execute_use_statement(stmt).map(|_| Value::None),
+			// These statements can only be used within a transaction.
+			Statement::Savepoint(_) | Statement::Rollback(_) | Statement::Release(_) => {
+				Err(Error::SavepointOutsideTransaction)
+			}
 			stmt => {
 				let writeable = stmt.writeable();
-				let txn = Arc::new(kvs.transaction(writeable.into(), LockType::Optimistic).await?);
-				let receiver = self.ctx.has_notifications().then(|| {
-					let (send, recv) = async_channel::unbounded();
-					self.opt.sender = Some(send);
-					recv
-				});
-
-				match self.execute_transaction_statement(txn.clone(), stmt).await {
-					Ok(value)
-					| Err(Error::Return {
-						value,
-					}) => {
-						let mut lock = txn.lock().await;
-
-						// non-writable transactions might return an error on commit.
-						// So cancel them instead. This is fine since a non-writable transaction
-						// has nothing to commit anyway.
-						if !writeable {
-							let _ = lock.cancel().await;
-							return Ok(value);
+				// Statements can not modify data in read-only mode
+				if writeable && kvs.is_read_only() {
+					return Err(Error::ReadOnly);
+				}
+				// Only writes can conflict with other transactions
+				if !writeable || !stmt.retryable() {
+					return self.execute_bare_transaction(kvs, stmt, writeable).await;
+				}
+				// Retry the statement when its transaction conflicts
+				let mut backoff = Duration::from_millis(*TRANSACTION_CONFLICT_BACKOFF);
+				let mut attempt = 0;
+				loop {
+					self.ctx.clear_side_effects();
+					match self.execute_bare_transaction(kvs, stmt.clone(), writeable).await {
+						// Statements with side effects are not run again
+						Err(Error::TxRetryable)
+							if attempt < *TRANSACTION_CONFLICT_RETRIES
+								&& self.ctx.done().is_none()
+								&& !self.ctx.has_side_effects() =>
+						{
+							attempt += 1;
+							trace!(target: TARGET, "Retrying statement after a transaction conflict, attempt {attempt}");
+							#[cfg(target_arch = "wasm32")]
+							wasmtimer::tokio::sleep(backoff).await;
+							#[cfg(not(target_arch = "wasm32"))]
+							tokio::time::sleep(backoff).await;
+							backoff = backoff.saturating_mul(2);
 						}
+						res => return res,
+					}
+				}
+			}
+		}
+	}
 
-						if let Err(e) = lock.complete_changes(false).await {
-							let _ = lock.cancel().await;
+	/// Execute a single statement within its own transaction.
+	async fn execute_bare_transaction(
+		&mut self,
+		kvs: &Datastore,
+		stmt: Statement,
+		writeable: bool,
+	) -> Result<Value, Error> {
+		let txn = Arc::new(kvs.transaction(writeable.into(), LockType::Optimistic).await?);
+		let receiver = self.ctx.has_notifications().then(|| {
+			let (send, recv) = async_channel::unbounded();
+			self.opt.sender = Some(send);
+			recv
+		});
 
-							return Err(Error::QueryNotExecutedDetail {
-								message: e.to_string(),
-							});
-						}
+		match self.execute_transaction_statement(txn.clone(), stmt).await {
+			Ok(value)
+			| Err(Error::Return {
+				value,
+			}) => {
+				let mut lock = txn.lock().await;
+
+				// non-writable transactions might return an error on commit.
+				// So cancel them instead. This is fine since a non-writable transaction
+				// has nothing to commit anyway.
+				if !writeable {
+					let _ = lock.cancel().await;
+					return Ok(value);
+				}
 
-						if let Err(e) = lock.commit().await {
-							return Err(Error::QueryNotExecutedDetail {
-								message: e.to_string(),
-							});
-						}
+				if let Err(e) = lock.complete_changes(false).await {
+					let _ = lock.cancel().await;
 
-						// flush notifications.
-						if let Some(recv) = receiver {
-							self.opt.sender = None;
-							if let Some(sink) = self.ctx.notifications() {
-								spawn(async move {
-									while let Ok(x) = recv.recv().await {
-										if sink.send(x).await.is_err() {
-											break;
-										}
-									}
-								});
-							}
-						}
+					return Err(Error::QueryNotExecutedDetail {
+						message: e.to_string(),
+					});
+				}
 
-						Ok(value)
+				if let Err(e) = lock.commit().await {
+					// Conflicts are surfaced so that they can be retried
+					if matches!(e, Error::TxRetryable) {
+						return Err(e);
 					}
-					Err(e) => {
-						let _ = txn.cancel().await;
-						Err(e)
+					return Err(Error::QueryNotExecutedDetail {
+						message: e.to_string(),
+					});
+				}
+
+				// flush notifications.
+				if let Some(recv) = receiver {
+					self.opt.sender = None;
+					if let Some(sink) = self.ctx.notifications() {
+						spawn(async move {
+							while let Ok(x) = recv.recv().await {
+								if sink.send(x).await.is_err() {
+									break;
+								}
+							}
+						});
 					}
 				}
+
+				Ok(value)
+			}
+			Err(e) => {
+				let _ = txn.cancel().await;
+				Err(e)
 			}
 		}
 	}
@@ -245,12 +415,19 @@ impl Executor {
 	async fn execute_begin_statement<S>(
 		&mut self,
 		kvs: &Datastore,
+		stmt: BeginStatement,
 		mut stream: Pin<&mut S>,
 	) -> Result<(), Error>
 	where
 		S: Stream<Item = Result<Statement, Error>>,
 	{
-		let Ok(txn) = kvs.transaction(TransactionType::Write, LockType::Optimistic).await else {
+		// Read-only transactions can not modify data
+		let readonly = stmt.readonly || kvs.is_read_only();
+		let kind = match readonly {
+			true => TransactionType::Read,
+			false => TransactionType::Write,
+		};
+		let Ok(txn) = kvs.transaction(kind, LockType::Optimistic).await else {
 			// couldn't create a transaction.
 			// Fast forward until we hit CANCEL or COMMIT
 			while let Some(stmt) = stream.next().await {
@@ -282,6 +459,9 @@ impl Executor {
 		let txn = Arc::new(txn);
 		let start_results = self.results.len();
 		let mut skip_remaining = false;
+		let mut savepoints = Vec::new();
+		let mut pending = Vec::new();
+		let mut failed = false;
 
 		// loop over the statements until we hit a cancel or a commit statement.
 		while let Some(stmt) = stream.next().await {
@@ -329,6 +509,21 @@ impl Executor {
 				continue;
 			}
 
+			// After a statement fails while a savepoint is open, the
+			// transaction can only be rolled back to a savepoint, or ended
+			if failed
+				&& !matches!(
+					stmt,
+					Statement::Cancel(_) | Statement::Commit(_) | Statement::Rollback(_)
+				) {
+				self.results.push(Response {
+					time: Duration::ZERO,
+					result: Err(Error::QueryNotExecuted),
+					query_type: QueryType::Other,
+				});
+				continue;
+			}
+
 			trace!(target: TARGET, statement = %stmt, "Executing statement");
 
 			let query_type = match stmt {
@@ -337,6 +532,9 @@ impl Executor {
 				_ => QueryType::Other,
 			};
 
+			let kind = stmt.kind();
+			let slow = self.start_slow_log(&stmt);
+			let audit = Self::start_audit(kvs, kind, &stmt);
 			let before = Instant::now();
 			let value = match stmt {
 				Statement::Begin(_) => {
@@ -389,6 +587,29 @@ impl Executor {
 
 					return Ok(());
 				}
+				Statement::Commit(_) if failed => {
+					// A failed statement was not rolled back
+					let _ = txn.cancel().await;
+
+					for res in &mut self.results[start_results..] {
+						if res.result.is_ok() {
+							res.query_type = QueryType::Other;
+							res.result = Err(Error::QueryNotExecuted);
+						}
+					}
+
+					self.opt.sender = None;
+
+					return Ok(());
+				}
+				Statement::Commit(_) if readonly => {
+					// Read-only transactions have nothing to commit
+					let _ = txn.cancel().await;
+
+					self.opt.sender = None;
+
+					return Ok(());
+				}
 				Statement::Commit(_) => {
 					let mut lock = txn.lock().await;
 
@@ -407,6 +628,11 @@ impl Executor {
 							self.opt.sender = None;
 							if let Some(sink) = self.ctx.notifications() {
 								spawn(async move {
+									for x in pending {
+										if sink.send(x).await.is_err() {
+											return;
+										}
+									}
 									while let Ok(x) = recv.recv().await {
 										if sink.send(x).await.is_err() {
 											break;
@@ -422,8 +648,12 @@ impl Executor {
 					// failed to commit
 					for res in &mut self.results[start_results..] {
 						res.query_type = QueryType::Other;
-						res.result = Err(Error::QueryNotExecutedDetail {
-							message: e.to_string(),
+						res.result = Err(match &e {
+							// The client can retry a transaction which conflicted
+							Error::TxRetryable => Error::TxRetryable,
+							e => Error::QueryNotExecutedDetail {
+								message: e.to_string(),
+							},
 						});
 					}
 
@@ -443,7 +673,34 @@ impl Executor {
 				stmt => {
 					skip_remaining = matches!(stmt, Statement::Output(_));
 
-					let r = match self.execute_transaction_statement(txn.clone(), stmt).await {
+					let r = match stmt {
+						stmt if readonly && stmt.writeable() => Err(Error::ReadOnly),
+						Statement::Savepoint(_)
+						| Statement::Rollback(_)
+						| Statement::Release(_) => {
+							let rollback = matches!(stmt, Statement::Rollback(_));
+							let r = self
+								.execute_savepoint_statement(
+									&txn,
+									&mut savepoints,
+									receiver.as_ref(),
+									&mut pending,
+									stmt,
+								)
+								.await;
+							// Rolling back undoes any failed statements
+							if rollback && r.is_ok() {
+								failed = false;
+							}
+							r
+						}
+						stmt => self.execute_transaction_statement(txn.clone(), stmt).await,
+					};
+
+					// A failed statement can be rolled back while a savepoint is open
+					let open = r.is_err() && txn.lock().await.has_user_save_points();
+
+					let r = match r {
 						Ok(x) => Ok(x),
 						Err(Error::Return {
 							value,
@@ -451,6 +708,10 @@ impl Executor {
 							skip_remaining = true;
 							Ok(value)
 						}
+						Err(e) if open => {
+							failed = true;
+							Err(e)
+						}
 						Err(e) => {
 							for res in &mut self.results[start_results..] {
 								res.query_type = QueryType::Other;
@@ -497,6 +758,13 @@ impl Executor {
 				}
 			};
 
+			metrics::record(Event::Statement {
+				kind,
+				duration: before.elapsed(),
+				success: value.is_ok(),
+			});
+			self.finish_slow_log(slow, before.elapsed(), &value);
+			self.finish_audit(kvs, kind, audit, &value);
 			self.results.push(Response {
 				time: before.elapsed(),
 				result: value,
@@ -561,8 +829,8 @@ impl Executor {
 			match stmt {
 				Statement::Option(stmt) => this.execute_option_statement(stmt)?,
 				// handle option here because it doesn't produce a result.
-				Statement::Begin(_) => {
-					if let Err(e) = this.execute_begin_statement(kvs, stream.as_mut()).await {
+				Statement::Begin(stmt) => {
+					if let Err(e) = this.execute_begin_statement(kvs, stmt, stream.as_mut()).await {
 						this.results.push(Response {
 							time: Duration::ZERO,
 							result: Err(e),
@@ -579,8 +847,18 @@ impl Executor {
 						_ => QueryType::Other,
 					};
 
+					let kind = stmt.kind();
+					let slow = this.start_slow_log(&stmt);
+					let audit = Self::start_audit(kvs, kind, &stmt);
 					let now = Instant::now();
 					let result = this.execute_bare_statement(kvs, stmt).await;
+					metrics::record(Event::Statement {
+						kind,
+						duration: now.elapsed(),
+						success: result.is_ok(),
+					});
+					this.finish_slow_log(slow, now.elapsed(), &result);
+					this.finish_audit(kvs, kind, audit, &result);
 					this.results.push(Response {
 						time: now.elapsed(),
 						result,
@@ -1,4 +1,5 @@
 use crate::ctx::Context;
+use crate::dbs::memory;
 use crate::dbs::plan::Explanation;
 use crate::dbs::store::MemoryCollector;
 use crate::dbs::{Options, Statement};
@@ -8,12 +9,16 @@ use crate::sql::value::{TryAdd, TryFloatDiv, Value};
 use crate::sql::{Array, Field, Function, Idiom};
 use reblessive::tree::Stk;
 use std::borrow::Cow;
+use std::collections::btree_map::Entry;
 use std::collections::{BTreeMap, HashMap};
+use std::mem::size_of;
 
 pub(super) struct GroupsCollector {
 	base: Vec<Aggregator>,
 	idioms: Vec<Idiom>,
 	grp: BTreeMap<Array, Vec<Aggregator>>,
+	/// The bytes used by the groups created since they were last counted
+	added: usize,
 }
 
 #[derive(Default)]
@@ -56,6 +61,7 @@ impl GroupsCollector {
 			base,
 			idioms,
 			grp: Default::default(),
+			added: 0,
 		}
 	}
 
@@ -78,10 +84,15 @@ impl GroupsCollector {
 				arr.push(val);
 			}
 			// Add to grouped collection
-			let agr = self
-				.grp
-				.entry(arr)
-				.or_insert_with(|| self.base.iter().map(|a| a.new_instance()).collect());
+			let agr = match self.grp.entry(arr) {
+				Entry::Occupied(e) => e.into_mut(),
+				Entry::Vacant(e) => {
+					// Each new group holds its key, and the state of its aggregates
+					self.added += e.key().iter().map(memory::size).sum::<usize>()
+						+ self.base.len() * size_of::<Aggregator>();
+					e.insert(self.base.iter().map(|a| a.new_instance()).collect())
+				}
+			};
 			Self::pushes(stk, ctx, opt, agr, &self.idioms, obj).await?
 		}
 		Ok(())
@@ -106,6 +117,11 @@ impl GroupsCollector {
 		self.grp.len()
 	}
 
+	/// The bytes used by the groups created since this was last called
+	pub(super) fn take_added(&mut self) -> usize {
+		std::mem::take(&mut self.added)
+	}
+
 	pub(super) async fn output(
 		&mut self,
 		stk: &mut Stk,
@@ -1,9 +1,14 @@
+#[cfg(storage)]
+use crate::cnf::{EXTERNAL_SORTING_BYTE_THRESHOLD, EXTERNAL_SORTING_ROW_THRESHOLD};
 use crate::ctx::Context;
 use crate::ctx::{Canceller, MutableContext};
 #[cfg(not(target_arch = "wasm32"))]
 use crate::dbs::distinct::AsyncDistinct;
 use crate::dbs::distinct::SyncDistinct;
-use crate::dbs::plan::Plan;
+#[cfg(storage)]
+use crate::dbs::memory;
+use crate::dbs::memory::MemoryTracker;
+use crate::dbs::plan::{self, Plan};
 #[cfg(not(target_arch = "wasm32"))]
 use crate::dbs::processor::Collected;
 #[cfg(not(target_arch = "wasm32"))]
@@ -37,6 +42,7 @@ use std::future::Future;
 use std::mem;
 use std::sync::Arc;
 use std::thread::available_parallelism;
+use tracing::instrument;
 
 const TARGET: &str = "surrealdb::core::dbs";
 
@@ -85,6 +91,10 @@ pub(crate) enum Iterable {
 	/// table, which then fetches the correesponding records
 	/// which are matched within the index.
 	Index(Table, IteratorRef),
+	/// An iterable over a table in another namespace and
+	/// database, which is processed with the options of
+	/// that namespace and database.
+	Qualified(Arc<Options>, Box<Iterable>),
 }
 
 #[derive(Debug)]
@@ -127,6 +137,11 @@ pub(crate) struct Iterator {
 	error: Option<Error>,
 	/// Iterator output results
 	results: Results,
+	/// The memory used by the output results
+	memory: Option<MemoryTracker>,
+	/// The approximate size of the values collected for a sort
+	#[cfg(storage)]
+	sort_size: usize,
 	/// Iterator input values
 	entries: Vec<Iterable>,
 	/// Should we always return a record?
@@ -144,6 +159,9 @@ impl Clone for Iterator {
 			start: self.start,
 			error: None,
 			results: Results::default(),
+			memory: None,
+			#[cfg(storage)]
+			sort_size: 0,
 			entries: self.entries.clone(),
 			guaranteed: None,
 			cancel_on_limit: None,
@@ -162,6 +180,14 @@ impl Iterator {
 		self.entries.push(val)
 	}
 
+	/// Ingests the iterables of another iterator, which are
+	/// processed with the options of another namespace and database
+	pub(crate) fn ingest_qualified(&mut self, opt: &Arc<Options>, ite: Iterator) {
+		for v in ite.entries {
+			self.entries.push(Iterable::Qualified(opt.clone(), Box::new(v)))
+		}
+	}
+
 	/// Prepares a value for processing
 	pub(crate) fn prepare(&mut self, stm: &Statement<'_>, val: Value) -> Result<(), Error> {
 		// Match the values
@@ -306,6 +332,7 @@ impl Iterator {
 	}
 
 	/// Process the records and output
+	#[instrument(level = "debug", name = "iterator", target = "surrealdb::core::dbs", skip_all)]
 	pub async fn output(
 		&mut self,
 		stk: &mut Stk,
@@ -329,6 +356,14 @@ impl Iterator {
 			ctx,
 			stm,
 		)?;
+		// Count the results against the memory budgets
+		self.memory = ctx.get_memory_budget().cloned().map(MemoryTracker::new);
+		// Record the operations for the slow query log
+		if let Some(log) = ctx.get_slow_log() {
+			for v in plan::summarise(ctx, &self.entries) {
+				log.track(v);
+			}
+		}
 		// Extract the expected behaviour depending on the presence of EXPLAIN with or without FULL
 		let mut plan = Plan::new(ctx, stm, &self.entries, &self.results);
 		if plan.do_iterate {
@@ -491,7 +526,7 @@ impl Iterator {
 								// Set the value at the path
 								obj.set(stk, ctx, opt, split, val).await?;
 								// Add the object to the results
-								self.results.push(stk, ctx, opt, stm, obj).await?;
+								self.push(stk, ctx, opt, stm, obj).await?;
 							}
 						}
 						_ => {
@@ -500,7 +535,7 @@ impl Iterator {
 							// Set the value at the path
 							obj.set(stk, ctx, opt, split, val).await?;
 							// Add the object to the results
-							self.results.push(stk, ctx, opt, stm, obj).await?;
+							self.push(stk, ctx, opt, stm, obj).await?;
 						}
 					}
 				}
@@ -538,10 +573,14 @@ impl Iterator {
 			}
 			for i in &idioms {
 				let mut values = self.results.take().await?;
-				// Loop over each result value
-				for obj in &mut values {
-					// Fetch the value at the path
-					stk.run(|stk| obj.fetch(stk, ctx, opt, i)).await?;
+				// Fetch the values at the path of every result
+				stk.run(|stk| Value::fetch_all(&mut values, stk, ctx, opt, i)).await?;
+				// Count the fetched documents against the memory budgets
+				if let Some(memory) = &mut self.memory {
+					memory.release();
+					for v in values.iter() {
+						memory.track(v)?;
+					}
 				}
 				self.results = values.into();
 			}
@@ -581,8 +620,12 @@ impl Iterator {
 		self.compute_start_limit(ctx, stm);
 		// Prevent deep recursion
 		let opt = opt.dive(4)?;
+		// Tables in other databases are processed with their own
+		// options, which are not passed through the parallel stages
+		let parallel =
+			stm.parallel() && !self.entries.iter().any(|v| matches!(v, Iterable::Qualified(..)));
 		// Check if iterating in parallel
-		match stm.parallel() {
+		match parallel {
 			// Run statements sequentially
 			false => {
 				// If any iterator requires distinct, we need to create a global distinct instance
@@ -753,7 +796,7 @@ impl Iterator {
 				return;
 			}
 			Ok(v) => {
-				if let Err(e) = self.results.push(stk, ctx, opt, stm, v).await {
+				if let Err(e) = self.push(stk, ctx, opt, stm, v).await {
 					self.error = Some(e);
 					self.run.cancel();
 					return;
@@ -768,6 +811,57 @@ impl Iterator {
 		}
 	}
 
+	/// Add a value to the results, counting it against the memory budgets
+	async fn push(
+		&mut self,
+		stk: &mut Stk,
+		ctx: &Context,
+		opt: &Options,
+		stm: &Statement<'_>,
+		val: Value,
+	) -> Result<(), Error> {
+		// Large sorts are moved to the temporary directory
+		#[cfg(storage)]
+		if self.exceeds_sort_threshold(ctx, &val) && self.results.spill(ctx).await? {
+			// The results are no longer held in memory
+			self.memory = None;
+		}
+		// Grouped records are counted by group, and files are not held in memory
+		let memory = self.memory.as_mut().filter(|_| self.results.is_memory());
+		if let Some(memory) = memory {
+			if let Err(e) = memory.track(&val) {
+				// Sorts can be moved to the temporary directory instead
+				#[cfg(storage)]
+				if memory.spill() && self.results.spill(ctx).await? {
+					// The results are no longer held in memory
+					self.memory = None;
+					return self.results.push(stk, ctx, opt, stm, val).await;
+				}
+				return Err(e);
+			}
+		}
+		self.results.push(stk, ctx, opt, stm, val).await?;
+		// Count each new group, rather than each grouped record
+		if let Some(memory) = self.memory.as_mut() {
+			let size = self.results.take_group_size();
+			if size > 0 {
+				memory.track_size(size)?;
+			}
+		}
+		Ok(())
+	}
+
+	#[cfg(storage)]
+	/// Whether the values collected for a sort are too large to be sorted in memory
+	fn exceeds_sort_threshold(&mut self, ctx: &Context, val: &Value) -> bool {
+		if ctx.temporary_directory().is_none() || !self.results.is_memory_sort() {
+			return false;
+		}
+		self.sort_size += memory::size(val);
+		self.results.len() >= *EXTERNAL_SORTING_ROW_THRESHOLD
+			|| self.sort_size >= *EXTERNAL_SORTING_BYTE_THRESHOLD
+	}
+
 	#[cfg(not(target_arch = "wasm32"))]
 	fn execute(
 		max_threads: usize,
@@ -0,0 +1,159 @@
+//! Tracks the approximate memory used by the intermediate results of each
+//! statement which are held in memory, such as sort buffers, collected results,
+//! and fetched documents, and aborts statements which exceed the per-query or
+//! the global budget.
+use crate::err::Error;
+use crate::sql::{Id, Value};
+use std::mem::size_of;
+use std::sync::atomic::{AtomicUsize, Ordering};
+use std::sync::Arc;
+
+/// The memory budgets applied to the intermediate results of statements
+#[derive(Debug, Default)]
+pub struct MemoryBudget {
+	/// The maximum number of bytes used by a single statement
+	query: Option<usize>,
+	/// The maximum number of bytes used by all running statements
+	global: Option<usize>,
+	/// Whether sorts which exceed a budget are spilled to temporary files
+	spill: bool,
+	/// The number of bytes currently used by all running statements
+	used: AtomicUsize,
+}
+
+impl MemoryBudget {
+	/// Create new memory budgets, for each statement and for all statements
+	pub fn new(query: Option<usize>, global: Option<usize>) -> Self {
+		Self {
+			query,
+			global,
+			spill: false,
+			used: AtomicUsize::new(0),
+		}
+	}
+
+	/// Spill sorts which exceed a budget to the temporary directory of the
+	/// datastore, instead of aborting the statement
+	pub fn with_spill(mut self, spill: bool) -> Self {
+		self.spill = spill;
+		self
+	}
+
+	/// The number of bytes currently used by all running statements
+	pub fn used(&self) -> usize {
+		self.used.load(Ordering::Relaxed)
+	}
+}
+
+/// The memory used by the intermediate results of a single statement,
+/// which is released from the global budget once the statement finishes
+pub(crate) struct MemoryTracker {
+	budget: Arc<MemoryBudget>,
+	used: usize,
+}
+
+impl MemoryTracker {
+	pub(crate) fn new(budget: Arc<MemoryBudget>) -> Self {
+		Self {
+			budget,
+			used: 0,
+		}
+	}
+
+	/// Whether sorts which exceed a budget are spilled to temporary files
+	pub(crate) fn spill(&self) -> bool {
+		self.budget.spill
+	}
+
+	/// Count a value against the budgets, returning an error if a budget is exceeded
+	pub(crate) fn track(&mut self, val: &Value) -> Result<(), Error> {
+		self.track_size(size(val))
+	}
+
+	/// Count a number of bytes against the budgets, returning an error if a budget is exceeded
+	pub(crate) fn track_size(&mut self, size: usize) -> Result<(), Error> {
+		self.used += size;
+		let total = self.budget.used.fetch_add(size, Ordering::Relaxed) + size;
+		if let Some(limit) = self.budget.query {
+			if self.used > limit {
+				return Err(Error::MemoryBudgetExceeded {
+					scope: "the query",
+					limit,
+				});
+			}
+		}
+		if let Some(limit) = self.budget.global {
+			if total > limit {
+				return Err(Error::MemoryBudgetExceeded {
+					scope: "all running queries",
+					limit,
+				});
+			}
+		}
+		Ok(())
+	}
+
+	/// Release the memory which is counted against the budgets
+	pub(crate) fn release(&mut self) {
+		self.budget.used.fetch_sub(self.used, Ordering::Relaxed);
+		self.used = 0;
+	}
+}
+
+impl Drop for MemoryTracker {
+	fn drop(&mut self) {
+		self.release();
+	}
+}
+
+/// The approximate number of bytes used by a value
+pub(crate) fn size(val: &Value) -> usize {
+	size_of::<Value>()
+		+ match val {
+			Value::Strand(v) => v.0.len(),
+			Value::Bytes(v) => v.0.len(),
+			Value::Array(v) => v.iter().map(size).sum(),
+			Value::Object(v) => v.iter().map(|(k, v)| k.len() + size(v)).sum(),
+			Value::Thing(v) => {
+				v.tb.len()
+					+ match &v.id {
+						Id::String(v) => v.len(),
+						Id::Array(v) => v.iter().map(size).sum(),
+						Id::Object(v) => v.iter().map(|(k, v)| k.len() + size(v)).sum(),
+						_ => 0,
+					}
+			}
+			_ => 0,
+		}
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+
+	#[test]
+	fn exceeds_query_budget() {
+		let budget = Arc::new(MemoryBudget::new(Some(256), None));
+		let mut tracker = MemoryTracker::new(budget.clone());
+		assert!(tracker.track(&Value::from("one")).is_ok());
+		assert!(budget.used() > 0);
+		let val = Value::from("x".repeat(256));
+		assert!(matches!(tracker.track(&val), Err(Error::MemoryBudgetExceeded { .. })));
+		// The memory is released once the statement finishes
+		drop(tracker);
+		assert_eq!(budget.used(), 0);
+	}
+
+	#[test]
+	fn exceeds_global_budget() {
+		let budget = Arc::new(MemoryBudget::new(None, Some(256)));
+		let mut one = MemoryTracker::new(budget.clone());
+		let mut two = MemoryTracker::new(budget.clone());
+		let val = Value::from("x".repeat(128));
+		assert!(one.track(&val).is_ok());
+		assert!(matches!(two.track(&val), Err(Error::MemoryBudgetExceeded { .. })));
+		one.release();
+		two.release();
+		assert_eq!(budget.used(), 0);
+	}
+}
@@ -0,0 +1,49 @@
+//! Events which are observed as the datastore processes queries and
+//! transactions, so that they can be recorded as metrics by the server.
+use std::sync::OnceLock;
+use std::time::Duration;
+
+/// An event which has been observed by the datastore
+#[derive(Debug)]
+#[non_exhaustive]
+pub enum Event {
+	/// A statement has finished processing
+	Statement {
+		/// The type of statement which was processed
+		kind: &'static str,
+		/// How long the statement took to process
+		duration: Duration,
+		/// Whether the statement completed without an error
+		success: bool,
+	},
+	/// A writeable transaction was committed
+	Commit,
+	/// A transaction could not be committed due to a conflicting transaction
+	Conflict,
+	/// A definition was found in the transaction cache
+	CacheHit,
+	/// A definition was not found in the transaction cache
+	CacheMiss,
+}
+
+type Observer = Box<dyn Fn(&Event) + Send + Sync>;
+
+/// The function which is called for each observed event
+static OBSERVER: OnceLock<Observer> = OnceLock::new();
+
+/// Sets the function which is called for each observed event. This can
+/// only be set once, and returns false if an observer was already set.
+pub fn observe<F>(f: F) -> bool
+where
+	F: Fn(&Event) + Send + Sync + 'static,
+{
+	OBSERVER.set(Box::new(f)).is_ok()
+}
+
+/// Passes an event to the observer, if one has been set
+#[inline]
+pub(crate) fn record(event: Event) {
+	if let Some(f) = OBSERVER.get() {
+		f(&event)
+	}
+}
@@ -12,13 +12,20 @@ mod plan;
 mod processor;
 mod response;
 mod result;
+mod schedule;
 mod session;
 mod statement;
 mod store;
 mod variables;
 
+pub mod audit;
 pub mod capabilities;
+pub mod connections;
+pub mod memory;
+pub mod metrics;
 pub mod node;
+pub mod quota;
+pub mod slowlog;
 
 pub use self::capabilities::Capabilities;
 pub(crate) use self::executor::*;
@@ -26,6 +33,7 @@ pub(crate) use self::iterator::*;
 pub use self::notification::*;
 pub use self::options::*;
 pub use self::response::*;
+pub use self::schedule::*;
 pub use self::session::*;
 pub(crate) use self::statement::*;
 pub(crate) use self::variables::*;
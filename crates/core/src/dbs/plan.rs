@@ -39,6 +39,15 @@ impl Plan {
 	}
 }
 
+/// Summarises the operations which are performed
+/// for each of the iterables, for the slow query log
+pub(super) fn summarise<'a>(
+	ctx: &'a Context,
+	iterables: &'a [Iterable],
+) -> impl Iterator<Item = String> + 'a {
+	iterables.iter().map(|i| ExplainItem::new_iter(ctx, i).summary())
+}
+
 #[derive(Default)]
 pub(super) struct Explanation(Vec<ExplainItem>);
 
@@ -150,16 +159,39 @@ impl ExplainItem {
 			},
 			Iterable::Index(t, ir) => {
 				let mut details = vec![("table", Value::from(t.0.to_owned()))];
+				let mut keys_only = false;
 				if let Some(qp) = ctx.get_query_planner() {
 					if let Some(exe) = qp.get_query_executor(&t.0) {
 						details.push(("plan", exe.explain(*ir)));
+						keys_only = exe.is_keys_only(*ir);
 					}
 				}
 				Self {
-					name: "Iterate Index".into(),
+					name: if keys_only {
+						"Iterate Index Keys"
+					} else {
+						"Iterate Index"
+					}
+					.into(),
 					details,
 				}
 			}
+			Iterable::Qualified(opt, v) => {
+				let mut item = Self::new_iter(ctx, v);
+				if let (Ok(ns), Ok(db)) = (opt.ns(), opt.db()) {
+					item.details.push(("namespace", Value::from(ns)));
+					item.details.push(("database", Value::from(db)));
+				}
+				item
+			}
+		}
+	}
+
+	fn summary(self) -> String {
+		let name = self.name.as_raw_string();
+		match self.details.into_iter().find(|(k, _)| matches!(*k, "table" | "thing" | "from")) {
+			Some((_, v)) => format!("{name} {}", v.as_raw_string()),
+			None => name,
 		}
 	}
 
@@ -30,7 +30,16 @@ impl Iterable {
 		ite: &mut Iterator,
 		dis: Option<&mut SyncDistinct>,
 	) -> Result<(), Error> {
-		if self.iteration_stage_check(ctx) {
+		// Tables in other databases use their own options
+		let qualified;
+		let (opt, iterable) = match self {
+			Iterable::Qualified(o, v) => {
+				qualified = o;
+				(qualified.as_ref(), *v)
+			}
+			v => (opt, v),
+		};
+		if iterable.iteration_stage_check(ctx) {
 			let txn = ctx.tx();
 			let mut coll = ConcurrentCollector {
 				stk,
@@ -45,9 +54,9 @@ impl Iterable {
 					coll,
 					dis,
 				};
-				coll.collect_iterable(ctx, opt, self).await?;
+				coll.collect_iterable(ctx, opt, iterable).await?;
 			} else {
-				coll.collect_iterable(ctx, opt, self).await?;
+				coll.collect_iterable(ctx, opt, iterable).await?;
 			}
 		}
 		Ok(())
@@ -461,6 +470,11 @@ pub(super) trait Collector {
 					})
 					.await?
 				}
+				Iterable::Qualified(..) => {
+					return Err(fail!(
+						"Tables in other databases are iterated with their own options"
+					))
+				}
 			}
 		}
 		Ok(())
@@ -716,13 +730,23 @@ pub(super) trait Collector {
 		if let Some(exe) = ctx.get_query_executor() {
 			if let Some(mut iterator) = exe.new_iterator(opt, irf).await? {
 				let txn = ctx.tx();
+				// Check if the records need to be fetched
+				let keys_only = exe.is_keys_only(irf);
 				// Collect by batches
 				while !ctx.is_done() {
-					let records: Vec<CollectorRecord> =
+					let mut records: Vec<CollectorRecord> =
 						iterator.next_batch(ctx, &txn, *NORMAL_FETCH_SIZE).await?;
 					if records.is_empty() {
 						break;
 					}
+					if keys_only {
+						for r in records.iter_mut() {
+							r.2 = Some(Value::Null.into());
+						}
+					} else {
+						// Fetch the records of the whole batch at once
+						Iterable::fetch_things(&txn, opt, &mut records).await?;
+					}
 					for r in records {
 						self.collect(Collected::IndexItem(r)).await?;
 					}
@@ -755,4 +779,31 @@ impl Iterable {
 		// Return the result
 		Ok(val)
 	}
+
+	/// Fetches the values of the index records which have not already been
+	/// fetched, with a single request to the store for the whole batch.
+	async fn fetch_things(
+		txn: &Transaction,
+		opt: &Options,
+		records: &mut [CollectorRecord],
+	) -> Result<(), Error> {
+		let ns = opt.ns()?;
+		let db = opt.db()?;
+		// Get the keys of the records which need to be fetched
+		let keys: Vec<_> = records
+			.iter()
+			.filter(|r| r.2.is_none())
+			.map(|r| thing::new(ns, db, &r.0.tb, &r.0.id))
+			.collect();
+		if keys.is_empty() {
+			return Ok(());
+		}
+		// Fetch and parse the data from the store
+		let mut vals = txn.getm(keys).await?.into_iter();
+		for r in records.iter_mut().filter(|r| r.2.is_none()) {
+			let val = vals.next().flatten().map(Value::from).unwrap_or(Value::None);
+			r.2 = Some(val.into());
+		}
+		Ok(())
+	}
 }
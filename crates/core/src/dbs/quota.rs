@@ -0,0 +1,194 @@
+//! Limits the number of concurrent queries, and the size of the results, of
+//! the record users which sign in through each access method, and enforces
+//! the limits which are defined on each namespace.
+use crate::dbs::{Response, Session};
+use crate::err::Error;
+use crate::kvs::Transaction;
+use crate::sql::statements::NamespaceLimits;
+use crate::sql::Value;
+use std::collections::HashMap;
+use std::sync::{Arc, Mutex};
+use std::time::Duration;
+use trice::Instant;
+
+/// The query quotas applied to each access method
+#[derive(Debug, Default)]
+pub struct Quotas {
+	/// The maximum number of queries running at once
+	concurrent: Option<usize>,
+	/// The maximum number of records returned by a statement
+	result_size: Option<usize>,
+	/// The number of queries currently running for each access method
+	active: Mutex<HashMap<String, usize>>,
+}
+
+impl Quotas {
+	/// Create new query quotas, which are applied separately to the
+	/// record users of each access method
+	pub fn new(concurrent: Option<usize>, result_size: Option<usize>) -> Self {
+		Self {
+			concurrent,
+			result_size,
+			active: Mutex::default(),
+		}
+	}
+
+	/// The access method which the quotas of a session are counted against
+	fn scope(sess: &Session) -> Option<String> {
+		match (sess.au.is_record(), &sess.ns, &sess.db, &sess.ac) {
+			(true, Some(ns), Some(db), Some(ac)) => Some(format!("{ns}/{db}/{ac}")),
+			_ => None,
+		}
+	}
+
+	/// Start a query which is run by a session, returning an error if
+	/// the access method of the session is already running too many
+	pub(crate) fn acquire(self: &Arc<Self>, sess: &Session) -> Result<Option<Permit>, Error> {
+		let (Some(limit), Some(scope)) = (self.concurrent, Self::scope(sess)) else {
+			return Ok(None);
+		};
+		let mut active = self.active.lock().unwrap_or_else(|e| e.into_inner());
+		let count = active.entry(scope.clone()).or_default();
+		if *count >= limit {
+			return Err(Error::QuotaExceeded {
+				scope,
+				message: format!("no more than {limit} queries can run at once"),
+			});
+		}
+		*count += 1;
+		Ok(Some(Permit {
+			quotas: self.clone(),
+			scope,
+		}))
+	}
+
+	/// Replace any statement results which return too many records
+	pub(crate) fn check(&self, sess: &Session, responses: &mut [Response]) {
+		let (Some(limit), Some(scope)) = (self.result_size, Self::scope(sess)) else {
+			return;
+		};
+		for res in responses.iter_mut() {
+			if matches!(&res.result, Ok(Value::Array(v)) if v.len() > limit) {
+				res.result = Err(Error::QuotaExceeded {
+					scope: scope.clone(),
+					message: format!("no more than {limit} records can be returned"),
+				});
+			}
+		}
+	}
+}
+
+/// A query which is counted against the quotas of an access method
+pub(crate) struct Permit {
+	quotas: Arc<Quotas>,
+	scope: String,
+}
+
+impl Drop for Permit {
+	fn drop(&mut self) {
+		let mut active = self.quotas.active.lock().unwrap_or_else(|e| e.into_inner());
+		if let Some(count) = active.get_mut(&self.scope) {
+			*count = count.saturating_sub(1);
+			if *count == 0 {
+				active.remove(&self.scope);
+			}
+		}
+	}
+}
+
+/// The queries which are running in each namespace
+#[derive(Debug, Default)]
+pub struct Namespaces {
+	usage: Mutex<HashMap<String, Usage>>,
+}
+
+#[derive(Debug)]
+struct Usage {
+	/// The number of queries currently running
+	active: usize,
+	/// When the current rate window started
+	window: Instant,
+	/// The number of queries started in the current rate window
+	started: u32,
+}
+
+impl Namespaces {
+	/// Start a query which is run in a namespace, returning an error
+	/// if the query would exceed the limits of the namespace
+	pub(crate) fn acquire(
+		self: &Arc<Self>,
+		ns: &str,
+		limits: &NamespaceLimits,
+	) -> Result<NamespacePermit, Error> {
+		let now = Instant::now();
+		let mut usage = self.usage.lock().unwrap_or_else(|e| e.into_inner());
+		let usage = usage.entry(ns.to_owned()).or_insert(Usage {
+			active: 0,
+			window: now,
+			started: 0,
+		});
+		// Start a new rate window every second
+		if usage.window.elapsed() >= Duration::from_secs(1) {
+			usage.window = now;
+			usage.started = 0;
+		}
+		if let Some(limit) = limits.rate {
+			if usage.started >= limit {
+				return Err(Error::QuotaExceeded {
+					scope: ns.to_owned(),
+					message: format!("no more than {limit} queries can be started each second"),
+				});
+			}
+		}
+		if let Some(limit) = limits.queries {
+			if usage.active >= limit as usize {
+				return Err(Error::QuotaExceeded {
+					scope: ns.to_owned(),
+					message: format!("no more than {limit} queries can run at once"),
+				});
+			}
+		}
+		usage.active += 1;
+		usage.started += 1;
+		Ok(NamespacePermit {
+			namespaces: self.clone(),
+			ns: ns.to_owned(),
+		})
+	}
+
+	/// The number of queries which are currently running in a namespace
+	pub(crate) fn active(&self, ns: &str) -> usize {
+		let usage = self.usage.lock().unwrap_or_else(|e| e.into_inner());
+		usage.get(ns).map(|u| u.active).unwrap_or_default()
+	}
+}
+
+/// A query which is counted against the limits of a namespace
+pub(crate) struct NamespacePermit {
+	namespaces: Arc<Namespaces>,
+	ns: String,
+}
+
+impl Drop for NamespacePermit {
+	fn drop(&mut self) {
+		let mut usage = self.namespaces.usage.lock().unwrap_or_else(|e| e.into_inner());
+		if let Some(u) = usage.get_mut(&self.ns) {
+			u.active = u.active.saturating_sub(1);
+			// Forget the namespace once its rate window has passed
+			if u.active == 0 && u.window.elapsed() >= Duration::from_secs(1) {
+				usage.remove(&self.ns);
+			}
+		}
+	}
+}
+
+/// The number of live queries which are registered in a namespace
+pub(crate) async fn live_queries(txn: &Transaction, ns: &str) -> Result<usize, Error> {
+	let mut count = 0;
+	for db in txn.all_db(ns).await?.iter() {
+		for tb in txn.all_tb(ns, &db.name, None).await?.iter() {
+			count += txn.all_tb_lives(ns, &db.name, &tb.name).await?.len();
+		}
+	}
+	Ok(count)
+}
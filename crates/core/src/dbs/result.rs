@@ -114,6 +114,50 @@ impl Results {
 		}
 	}
 
+	/// Whether the values are held in memory until the statement completes
+	pub(super) fn is_memory(&self) -> bool {
+		match self {
+			Self::Memory(_) | Self::MemoryRandom(_) | Self::MemoryOrdered(_) => true,
+			#[cfg(not(target_arch = "wasm32"))]
+			Self::AsyncMemoryOrdered(_) => true,
+			_ => false,
+		}
+	}
+
+	/// The bytes used by the groups created since this was last called
+	pub(super) fn take_group_size(&mut self) -> usize {
+		match self {
+			Self::Groups(g) => g.take_added(),
+			_ => 0,
+		}
+	}
+
+	#[cfg(storage)]
+	/// Whether the values are being collected in memory for a sort
+	pub(super) fn is_memory_sort(&self) -> bool {
+		matches!(self, Self::MemoryOrdered(_) | Self::MemoryRandom(_))
+	}
+
+	#[cfg(storage)]
+	/// Move the values collected for a sort to a file in the temporary
+	/// directory, returning whether the values were moved
+	pub(super) async fn spill(&mut self, ctx: &Context) -> Result<bool, Error> {
+		let Some(temp_dir) = ctx.temporary_directory() else {
+			return Ok(false);
+		};
+		let values = match self {
+			Self::MemoryOrdered(c) => c.take_unsorted(),
+			Self::MemoryRandom(c) => c.take_unsorted(),
+			_ => return Ok(false),
+		};
+		let mut file = FileCollector::new(temp_dir)?;
+		for val in values {
+			file.push(val).await?;
+		}
+		*self = Self::File(Box::new(file));
+		Ok(true)
+	}
+
 	pub(super) async fn start_limit(
 		&mut self,
 		start: Option<u32>,
@@ -0,0 +1,204 @@
+//! Cron expressions which define when a scheduled event should run.
+use chrono::{DateTime, Datelike, Duration, NaiveDate, Timelike, Utc};
+use std::str::FromStr;
+
+/// The maximum number of steps used when searching for the next run time
+const MAX_SEARCH_STEPS: usize = 100_000;
+
+const MONTHS: [&str; 12] =
+	["JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"];
+
+const WEEKDAYS: [&str; 7] = ["SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"];
+
+/// A cron schedule with minute, hour, day of month, month, and day of week
+/// fields, where each field is stored as a bitmask of the matching values.
+#[derive(Clone, Debug, Eq, PartialEq)]
+#[non_exhaustive]
+pub struct Schedule {
+	minutes: u64,
+	hours: u64,
+	days: u64,
+	months: u64,
+	weekdays: u64,
+	/// Whether the day of month field matches any day
+	days_any: bool,
+	/// Whether the day of week field matches any day
+	weekdays_any: bool,
+}
+
+impl FromStr for Schedule {
+	type Err = String;
+
+	fn from_str(s: &str) -> Result<Self, Self::Err> {
+		let s = match s.trim().to_ascii_lowercase().as_str() {
+			"@yearly" | "@annually" => "0 0 1 1 *".to_owned(),
+			"@monthly" => "0 0 1 * *".to_owned(),
+			"@weekly" => "0 0 * * 0".to_owned(),
+			"@daily" | "@midnight" => "0 0 * * *".to_owned(),
+			"@hourly" => "0 * * * *".to_owned(),
+			v if v.starts_with('@') => return Err(format!("unknown schedule macro `{v}`")),
+			_ => s.trim().to_ascii_uppercase(),
+		};
+		let fields: Vec<&str> = s.split_whitespace().collect();
+		let &[minutes, hours, days, months, weekdays] = fields.as_slice() else {
+			return Err(format!("expected 5 fields but found {}", fields.len()));
+		};
+		// The day of week field accepts both 0 and 7 as Sunday
+		let mut weekdays_mask = field(weekdays, 0, 7, &WEEKDAYS)?;
+		if weekdays_mask & (1 << 7) != 0 {
+			weekdays_mask = (weekdays_mask | 1) & !(1 << 7);
+		}
+		Ok(Schedule {
+			minutes: field(minutes, 0, 59, &[])?,
+			hours: field(hours, 0, 23, &[])?,
+			days: field(days, 1, 31, &[])?,
+			months: field(months, 1, 12, &MONTHS)?,
+			weekdays: weekdays_mask,
+			days_any: days == "*",
+			weekdays_any: weekdays == "*",
+		})
+	}
+}
+
+/// Parses a single cron field into a bitmask of the matching values
+fn field(src: &str, min: u32, max: u32, names: &[&str]) -> Result<u64, String> {
+	let value = |v: &str| -> Result<u32, String> {
+		match names.iter().position(|n| *n == v) {
+			Some(i) => Ok(i as u32 + min),
+			None => v.parse::<u32>().map_err(|_| format!("invalid value `{v}`")),
+		}
+	};
+	let mut mask = 0u64;
+	for part in src.split(',') {
+		let (range, step) = match part.split_once('/') {
+			Some((range, step)) => match step.parse::<u32>() {
+				Ok(step) if step > 0 => (range, Some(step)),
+				_ => return Err(format!("invalid step `{step}`")),
+			},
+			None => (part, None),
+		};
+		let (beg, end) = match range {
+			"*" => (min, max),
+			v => match v.split_once('-') {
+				Some((beg, end)) => (value(beg)?, value(end)?),
+				None if step.is_some() => (value(v)?, max),
+				None => (value(v)?, value(v)?),
+			},
+		};
+		if beg < min || end > max || beg > end {
+			return Err(format!("value `{part}` is out of range {min}-{max}"));
+		}
+		for v in (beg..=end).step_by(step.unwrap_or(1) as usize) {
+			mask |= 1 << v;
+		}
+	}
+	Ok(mask)
+}
+
+impl Schedule {
+	/// Returns the first time strictly after the specified time which matches this schedule
+	pub fn next_after(&self, time: DateTime<Utc>) -> Option<DateTime<Utc>> {
+		// Start at the beginning of the following minute
+		let mut next = time.with_second(0)?.with_nanosecond(0)? + Duration::minutes(1);
+		for _ in 0..MAX_SEARCH_STEPS {
+			if self.months & (1 << next.month()) == 0 {
+				// Skip to the start of the next month
+				let (y, m) = match next.month() {
+					12 => (next.year() + 1, 1),
+					m => (next.year(), m + 1),
+				};
+				next = NaiveDate::from_ymd_opt(y, m, 1)?.and_hms_opt(0, 0, 0)?.and_utc();
+			} else if !self.matches_day(&next) {
+				// Skip to the start of the next day
+				next = next.date_naive().succ_opt()?.and_hms_opt(0, 0, 0)?.and_utc();
+			} else if self.hours & (1 << next.hour()) == 0 {
+				// Skip to the start of the next hour
+				next = next.with_minute(0)? + Duration::hours(1);
+			} else if self.minutes & (1 << next.minute()) == 0 {
+				// Skip to the next minute
+				next += Duration::minutes(1);
+			} else {
+				return Some(next);
+			}
+		}
+		None
+	}
+
+	fn matches_day(&self, time: &DateTime<Utc>) -> bool {
+		let day = self.days & (1 << time.day()) != 0;
+		let weekday = self.weekdays & (1 << time.weekday().num_days_from_sunday()) != 0;
+		// When both day fields are restricted, either may match
+		if self.days_any || self.weekdays_any {
+			day && weekday
+		} else {
+			day || weekday
+		}
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+	use chrono::TimeZone;
+
+	fn time(y: i32, m: u32, d: u32, h: u32, i: u32) -> DateTime<Utc> {
+		Utc.with_ymd_and_hms(y, m, d, h, i, 0).unwrap()
+	}
+
+	#[test]
+	fn parse_invalid() {
+		assert!(Schedule::from_str("* * * *").is_err());
+		assert!(Schedule::from_str("60 * * * *").is_err());
+		assert!(Schedule::from_str("* * 0 * *").is_err());
+		assert!(Schedule::from_str("*/0 * * * *").is_err());
+		assert!(Schedule::from_str("5-1 * * * *").is_err());
+		assert!(Schedule::from_str("* * * FOO *").is_err());
+		assert!(Schedule::from_str("@sometimes").is_err());
+	}
+
+	#[test]
+	fn next_every_minute() {
+		let s = Schedule::from_str("* * * * *").unwrap();
+		let t = time(2024, 1, 1, 10, 30) + Duration::seconds(15);
+		assert_eq!(s.next_after(t), Some(time(2024, 1, 1, 10, 31)));
+	}
+
+	#[test]
+	fn next_with_steps() {
+		let s = Schedule::from_str("*/15 * * * *").unwrap();
+		assert_eq!(s.next_after(time(2024, 1, 1, 10, 31)), Some(time(2024, 1, 1, 10, 45)));
+		assert_eq!(s.next_after(time(2024, 1, 1, 10, 45)), Some(time(2024, 1, 1, 11, 0)));
+	}
+
+	#[test]
+	fn next_with_names() {
+		let s = Schedule::from_str("30 2 * feb mon-wed").unwrap();
+		assert_eq!(s.next_after(time(2024, 1, 15, 0, 0)), Some(time(2024, 2, 5, 2, 30)));
+	}
+
+	#[test]
+	fn next_with_macro() {
+		let s = Schedule::from_str("@monthly").unwrap();
+		assert_eq!(s.next_after(time(2024, 12, 1, 0, 0)), Some(time(2025, 1, 1, 0, 0)));
+	}
+
+	#[test]
+	fn next_with_day_or_weekday() {
+		// Runs on the 13th of the month, or on any Friday
+		let s = Schedule::from_str("0 0 13 * 5").unwrap();
+		assert_eq!(s.next_after(time(2024, 10, 1, 0, 0)), Some(time(2024, 10, 4, 0, 0)));
+		assert_eq!(s.next_after(time(2024, 10, 12, 0, 0)), Some(time(2024, 10, 13, 0, 0)));
+	}
+
+	#[test]
+	fn next_sunday_as_seven() {
+		let s = Schedule::from_str("0 12 * * 7").unwrap();
+		assert_eq!(s.next_after(time(2024, 9, 2, 0, 0)), Some(time(2024, 9, 8, 12, 0)));
+	}
+
+	#[test]
+	fn next_never_matches() {
+		let s = Schedule::from_str("0 0 30 2 *").unwrap();
+		assert_eq!(s.next_after(time(2024, 1, 1, 0, 0)), None);
+	}
+}
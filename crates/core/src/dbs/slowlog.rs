@@ -0,0 +1,167 @@
+//! Records the statements which take longer than a configured threshold to
+//! run, so that they can be inspected with `INFO FOR SLOW QUERIES`.
+use crate::cnf::SLOW_LOG_CAPACITY;
+use crate::ctx::MutableContext;
+use crate::dbs::Options;
+use crate::err::Error;
+use crate::sql::{Datetime, Value};
+use std::collections::VecDeque;
+use std::sync::{Arc, Mutex};
+use std::time::Duration;
+
+const TARGET: &str = "surrealdb::core::dbs::slowlog";
+
+/// The configuration, and the most recent entries, of the slow query log
+#[derive(Debug)]
+pub struct SlowLog {
+	/// Statements which run for at least this long are logged
+	threshold: Duration,
+	/// The proportion of slow statements which are logged
+	sample: f64,
+	/// The most recently logged slow statements
+	entries: Mutex<VecDeque<SlowQuery>>,
+}
+
+impl SlowLog {
+	/// Create a new slow query log, which logs the given proportion
+	/// of the statements which take at least `threshold` to run
+	pub fn new(threshold: Duration, sample: f64) -> Self {
+		Self {
+			threshold,
+			sample: sample.clamp(0.0, 1.0),
+			entries: Mutex::new(VecDeque::new()),
+		}
+	}
+
+	/// Whether the next statement should be considered for logging
+	fn sampled(&self) -> bool {
+		self.sample >= 1.0 || rand::random::<f64>() < self.sample
+	}
+
+	/// Add a slow statement to the log
+	fn push(&self, query: SlowQuery) {
+		warn!(
+			target: TARGET,
+			duration = ?query.duration,
+			rows = query.rows,
+			statement = %query.statement,
+			"Slow query detected"
+		);
+		let mut entries = self.entries.lock().unwrap_or_else(|e| e.into_inner());
+		while entries.len() >= *SLOW_LOG_CAPACITY {
+			entries.pop_front();
+		}
+		if *SLOW_LOG_CAPACITY > 0 {
+			entries.push_back(query);
+		}
+	}
+
+	/// The logged slow statements, from the oldest to the most recent
+	pub(crate) fn entries(&self) -> Value {
+		let entries = self.entries.lock().unwrap_or_else(|e| e.into_inner());
+		Value::Array(entries.iter().cloned().map(Value::from).collect())
+	}
+}
+
+/// Records the statements run by a single query into the slow query log
+#[derive(Clone, Debug)]
+pub(crate) struct Recorder {
+	log: Arc<SlowLog>,
+	/// The operations performed by the current statement
+	plan: Arc<Mutex<Vec<String>>>,
+}
+
+impl Recorder {
+	pub(crate) fn new(log: &Arc<SlowLog>) -> Self {
+		Self {
+			log: log.clone(),
+			plan: Arc::default(),
+		}
+	}
+
+	/// The slow query log which this records into
+	pub(crate) fn log(&self) -> &SlowLog {
+		&self.log
+	}
+
+	/// Prepare to record the next statement, returning
+	/// false if the statement has not been sampled
+	pub(crate) fn start(&self) -> bool {
+		self.plan.lock().unwrap_or_else(|e| e.into_inner()).clear();
+		self.log.sampled()
+	}
+
+	/// Record an operation performed by the current statement
+	pub(crate) fn track(&self, operation: String) {
+		self.plan.lock().unwrap_or_else(|e| e.into_inner()).push(operation);
+	}
+
+	/// Log the current statement, if it took too long to run
+	pub(crate) fn finish(
+		&self,
+		ctx: &MutableContext,
+		opt: &Options,
+		statement: String,
+		duration: Duration,
+		result: &Result<Value, Error>,
+	) {
+		if duration < self.log.threshold {
+			return;
+		}
+		let rows = match result {
+			Ok(Value::Array(v)) => v.len(),
+			Ok(Value::None) | Err(_) => 0,
+			Ok(_) => 1,
+		};
+		self.log.push(SlowQuery {
+			time: Datetime::default(),
+			duration,
+			ns: opt.ns().ok().map(str::to_owned),
+			db: opt.db().ok().map(str::to_owned),
+			statement,
+			params: ctx.value_names(),
+			plan: std::mem::take(&mut *self.plan.lock().unwrap_or_else(|e| e.into_inner())),
+			rows,
+			success: result.is_ok(),
+		});
+	}
+}
+
+/// A statement which was recorded in the slow query log
+#[derive(Clone, Debug)]
+struct SlowQuery {
+	/// When the statement finished running
+	time: Datetime,
+	/// How long the statement took to run
+	duration: Duration,
+	/// The selected namespace
+	ns: Option<String>,
+	/// The selected database
+	db: Option<String>,
+	/// The text of the statement
+	statement: String,
+	/// The names of the parameters which were defined, without their values
+	params: Vec<String>,
+	/// A summary of the operations performed by the statement
+	plan: Vec<String>,
+	/// The number of records which were returned
+	rows: usize,
+	/// Whether the statement ran without error
+	success: bool,
+}
+
+impl From<SlowQuery> for Value {
+	fn from(v: SlowQuery) -> Self {
+		Value::from(map! {
+			"time".to_string() => v.time.into(),
+			"duration".to_string() => crate::sql::Duration::from(v.duration).into(),
+			"ns".to_string(), if let Some(ns) = v.ns => ns.into(),
+			"db".to_string(), if let Some(db) = v.db => db.into(),
+			"statement".to_string() => v.statement.into(),
+			"params".to_string() => v.params.into(),
+			"plan".to_string() => v.plan.into(),
+			"rows".to_string() => v.rows.into(),
+			"success".to_string() => v.success.into(),
+		})
+	}
+}
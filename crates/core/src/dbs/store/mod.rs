@@ -170,6 +170,15 @@ impl MemoryRandom {
 		self.result.take().unwrap_or_default()
 	}
 
+	#[cfg(storage)]
+	/// Take the collected values, before they are sorted
+	pub(in crate::dbs) fn take_unsorted(&mut self) -> Vec<Value> {
+		self.ordered.clear();
+		let mut values = mem::take(&mut self.values);
+		values.append(&mut self.batch);
+		values
+	}
+
 	pub(in crate::dbs) fn explain(&self, exp: &mut Explanation) {
 		exp.add_collector("MemoryRandom", vec![]);
 	}
@@ -272,6 +281,15 @@ impl MemoryOrdered {
 		self.result.take().unwrap_or_default()
 	}
 
+	#[cfg(storage)]
+	/// Take the collected values, before they are sorted
+	pub(in crate::dbs) fn take_unsorted(&mut self) -> Vec<Value> {
+		self.ordered.clear();
+		let mut values = mem::take(&mut self.values);
+		values.append(&mut self.batch);
+		values
+	}
+
 	pub(in crate::dbs) fn explain(&self, exp: &mut Explanation) {
 		exp.add_collector("MemoryOrdered", vec![]);
 	}
@@ -11,6 +11,7 @@ use crate::sql::paths::EDGE;
 use crate::sql::paths::IN;
 use crate::sql::paths::OUT;
 use crate::sql::value::Value;
+use crate::sql::{TableType, TimeSeries};
 use reblessive::tree::Stk;
 use std::sync::Arc;
 
@@ -31,17 +32,26 @@ impl Document {
 		if let Some(tb) = &self.gen {
 			// This is a CREATE, UPSERT, UPDATE statement
 			if let Workable::Normal = &self.extras {
+				// Time series tables generate ids from the record timestamp
+				let timeseries = TimeSeries::fetch(ctx, opt, tb).await?;
 				// Fetch the record id if specified
 				let id = match stm.data() {
 					// There is a data clause so fetch a record id
-					Some(data) => match data.rid(stk, ctx, opt).await? {
+					Some(data) => match (data.rid(stk, ctx, opt).await?, &timeseries) {
 						// Generate a new id from the id field
-						Some(id) => id.generate(tb, false)?,
+						(Some(id), _) => id.generate(ctx, tb, false)?,
+						// Generate a new id from the timestamp field
+						(None, Some(ts)) => {
+							ts.generate(ctx, tb, data.pick(stk, ctx, opt, &ts.timestamp).await?)?
+						}
 						// Generate a new random table id
-						None => tb.generate(),
+						(None, None) => tb.generate(ctx),
 					},
 					// There is no data clause so create a record id
-					None => tb.generate(),
+					None => match &timeseries {
+						Some(ts) => ts.generate(ctx, tb, None)?,
+						None => tb.generate(ctx),
+					},
 				};
 				// The id field can not be a record range
 				if id.is_range() {
@@ -84,16 +94,24 @@ impl Document {
 	/// hidden `edge` field are always present. This
 	/// ensures that any user modifications of these
 	/// fields are reset back to the original state.
+	/// For time series records, the timestamp field
+	/// is always set to the timestamp of the id.
 	pub(super) async fn default_record_data(
 		&mut self,
-		_ctx: &Context,
-		_opt: &Options,
+		ctx: &Context,
+		opt: &Options,
 		_stm: &Statement<'_>,
 	) -> Result<(), Error> {
 		// Get the record id
 		let rid = self.id()?;
 		// Set default field values
 		self.current.doc.to_mut().def(&rid);
+		// This is a time series record, so reset the timestamp
+		if let TableType::TimeSeries(ts) = &self.tb(ctx, opt).await?.kind {
+			if let Some(time) = TimeSeries::timestamp(&rid.id) {
+				self.current.doc.to_mut().put(&ts.timestamp, time.clone().into());
+			}
+		}
 		// This is a RELATE statement, so reset fields
 		if let Workable::Relate(l, r, _) = &self.extras {
 			// Mark that this is an edge node
@@ -233,6 +251,16 @@ impl Document {
 					let data = data.compute(stk, ctx, opt, Some(current)).await?;
 					self.current.doc.to_mut().merge(data)?
 				}
+				Data::MergeWithExpression(data, strategy) => {
+					// Process the permitted documents
+					let current = match self.reduced(stk, ctx, opt, Current).await? {
+						true => &self.current_reduced,
+						false => &self.current,
+					};
+					// Process the MERGE data clause
+					let data = data.compute(stk, ctx, opt, Some(current)).await?;
+					self.current.doc.to_mut().merge_with(data, strategy)?
+				}
 				Data::ReplaceExpression(data) => {
 					// Process the permitted documents
 					let current = match self.reduced(stk, ctx, opt, Current).await? {
@@ -3,6 +3,7 @@ use crate::dbs::Options;
 use crate::dbs::Statement;
 use crate::doc::Document;
 use crate::err::Error;
+use std::borrow::Cow;
 
 impl Document {
 	pub async fn process_changefeeds(
@@ -33,13 +34,22 @@ impl Document {
 		if let Some(cf) = dbcf.or(tbcf) {
 			// Create the changefeed entry
 			if let Some(id) = &self.id {
+				// Don't store the values of encrypted fields unencrypted
+				let initial = match self.encrypt_fields(ctx, opt, &self.initial.doc).await? {
+					Cow::Borrowed(_) => self.initial.doc.clone(),
+					Cow::Owned(v) => v.into(),
+				};
+				let current = match self.encrypt_fields(ctx, opt, &self.current.doc).await? {
+					Cow::Borrowed(_) => self.current.doc.clone(),
+					Cow::Owned(v) => v.into(),
+				};
 				txn.lock().await.record_change(
 					ns,
 					db,
 					tb.name.as_str(),
 					id.as_ref(),
-					self.initial.doc.clone(),
-					self.current.doc.clone(),
+					initial,
+					current,
 					cf.store_diff,
 				);
 			}
@@ -1,4 +1,4 @@
-use crate::ctx::Context;
+use crate::ctx::{Context, MutableContext};
 use crate::dbs::Options;
 use crate::dbs::Statement;
 use crate::dbs::Workable;
@@ -11,6 +11,7 @@ use crate::sql::paths::IN;
 use crate::sql::paths::OUT;
 use crate::sql::permission::Permission;
 use crate::sql::value::Value;
+use crate::sql::{TableType, TimeSeries};
 use reblessive::tree::Stk;
 
 impl Document {
@@ -19,7 +20,9 @@ impl Document {
 	/// an edge or relation, we check that the table
 	/// type is `ANY` or `RELATION`. When inserting
 	/// a node or normal record, we check that the
-	/// table type is `ANY` or `NORMAL`.
+	/// table type is `ANY`, `NORMAL`, or `TIMESERIES`,
+	/// and that the id of a time series record starts
+	/// with its timestamp.
 	pub(super) async fn check_table_type(
 		&mut self,
 		ctx: &Context,
@@ -79,6 +82,17 @@ impl Document {
 			},
 			_ => {}
 		}
+		// Records in a time series table are ordered by their timestamp
+		if let TableType::TimeSeries(_) = &tb.kind {
+			if let Statement::Create(_) | Statement::Upsert(_) | Statement::Insert(_) = stm {
+				let rid = self.id()?;
+				if TimeSeries::timestamp(&rid.id).is_none() {
+					return Err(Error::TimeSeriesId {
+						thing: rid.to_string(),
+					});
+				}
+			}
+		}
 		// Carry on
 		Ok(())
 	}
@@ -305,6 +319,16 @@ impl Document {
 	) -> Result<(), Error> {
 		// Check where condition
 		if let Some(cond) = stm.cond() {
+			// Check if the index keys already match the condition
+			if let Some(ir) = &self.current.ir {
+				if let Some(qp) = ctx.get_query_planner() {
+					if let Some(exe) = qp.get_query_executor(&self.id()?.tb) {
+						if exe.is_keys_only(ir.irf()) {
+							return Ok(());
+						}
+					}
+				}
+			}
 			// Process the permitted documents
 			let current = match self.reduced(stk, ctx, opt, Current).await? {
 				true => &self.current_reduced,
@@ -319,6 +343,45 @@ impl Document {
 		// Carry on
 		Ok(())
 	}
+	/// Checks the `ASSERT` clause of the table that
+	/// this record belongs to. This function is run
+	/// after all of the field definitions have been
+	/// processed, so that the clause can check the
+	/// final values of any of the fields together.
+	pub(super) async fn check_table_assert(
+		&mut self,
+		stk: &mut Stk,
+		ctx: &Context,
+		opt: &Options,
+		_stm: &Statement<'_>,
+	) -> Result<(), Error> {
+		// Check import
+		if opt.import {
+			return Ok(());
+		}
+		// Get the table for this document
+		let tb = self.tb(ctx, opt).await?;
+		// Check for an ASSERT clause
+		if let Some(expr) = &tb.assert {
+			// Configure the context
+			let mut ctx = MutableContext::new(ctx);
+			ctx.add_value("after", self.current.doc.as_arc());
+			ctx.add_value("before", self.initial.doc.as_arc());
+			// Freeze the context
+			let ctx = ctx.freeze();
+			// Process the ASSERT clause
+			let res = expr.compute(stk, &ctx, opt, Some(&self.current)).await?;
+			// Check the ASSERT clause result
+			if !res.is_truthy() {
+				return Err(Error::TableAssert {
+					thing: self.id()?.to_string(),
+					check: expr.to_string(),
+				});
+			}
+		}
+		// Carry on
+		Ok(())
+	}
 	/// Checks the `PERMISSIONS` clause for viewing a
 	/// record, based on the `select` permissions for
 	/// the table that this record belongs to. This
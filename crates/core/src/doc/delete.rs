@@ -20,7 +20,9 @@ impl Document {
 		self.check_permissions_table(stk, ctx, opt, stm).await?;
 		self.clear_record_data(ctx, opt, stm).await?;
 		self.store_index_data(stk, ctx, opt, stm).await?;
+		self.store_record_references(ctx, opt, stm).await?;
 		self.purge(stk, ctx, opt, stm).await?;
+		self.process_record_references(stk, ctx, opt, stm).await?;
 		self.process_table_views(stk, ctx, opt, stm).await?;
 		self.process_table_lives(stk, ctx, opt, stm).await?;
 		self.process_table_events(stk, ctx, opt, stm).await?;
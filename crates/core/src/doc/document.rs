@@ -31,6 +31,8 @@ pub(crate) struct Document {
 	/// Whether this is the second iteration of the processing
 	pub(super) retry: bool,
 	pub(super) extras: Workable,
+	/// Whether the record must be rewritten to re-encrypt its fields
+	pub(super) rotate: bool,
 	pub(super) initial: CursorDoc,
 	pub(super) current: CursorDoc,
 	pub(super) initial_reduced: CursorDoc,
@@ -186,6 +188,7 @@ impl Document {
 			gen,
 			retry,
 			extras,
+			rotate: false,
 			current: CursorDoc::new(id.clone(), ir.clone(), val.clone()),
 			initial: CursorDoc::new(id.clone(), ir.clone(), val.clone()),
 			current_reduced: CursorDoc::new(id.clone(), ir.clone(), val.clone()),
@@ -0,0 +1,151 @@
+use crate::ctx::Context;
+use crate::dbs::Options;
+use crate::doc::Document;
+use crate::err::Error;
+use crate::kvs::encryption::{is_encrypted, DataKeys};
+use crate::sql::statements::DefineFieldStatement;
+use crate::sql::value::Value;
+use crate::sql::Thing;
+use std::borrow::Cow;
+use std::sync::Arc;
+
+impl Document {
+	/// Decrypts the values of any encrypted fields in a record which
+	/// has been loaded from storage. If any of the values were stored
+	/// unencrypted, or were encrypted with a previous key, then the
+	/// record is marked so that it is rewritten when it is updated.
+	pub(super) async fn decrypt_fields(
+		&mut self,
+		ctx: &Context,
+		opt: &Options,
+	) -> Result<(), Error> {
+		// Records which are not yet stored are not encrypted
+		if self.id.is_none() || self.is_new() {
+			return Ok(());
+		}
+		// Get the encrypted field definitions
+		let fds = self.fd(ctx, opt).await?;
+		let mut fds = fds.iter().filter(|fd| fd.encrypted).peekable();
+		if fds.peek().is_none() {
+			return Ok(());
+		}
+		// Decrypt the value of each encrypted field
+		let mut val = self.initial.doc.as_ref().clone();
+		for fd in fds {
+			match val.pick(&fd.name) {
+				Value::None => continue,
+				Value::Bytes(v) if is_encrypted(&v) => {
+					let keys = data_keys(ctx, fd, false).await?;
+					let (v, rotate) = keys.decrypt(&v, &aad(&self.id()?, fd))?;
+					self.rotate |= rotate;
+					val.put(&fd.name, v);
+				}
+				_ => self.rotate = true,
+			}
+		}
+		// Process the decrypted record
+		let val = Arc::new(val);
+		self.initial.doc = val.clone().into();
+		self.current.doc = val.clone().into();
+		self.initial_reduced.doc = val.clone().into();
+		self.current_reduced.doc = val.into();
+		Ok(())
+	}
+
+	/// Returns the value which is written to storage, with the values of
+	/// any encrypted fields replaced by their encrypted representation.
+	pub(super) async fn encrypt_fields<'a>(
+		&self,
+		ctx: &Context,
+		opt: &Options,
+		val: &'a Value,
+	) -> Result<Cow<'a, Value>, Error> {
+		let mut out = Cow::Borrowed(val);
+		for fd in self.fd(ctx, opt).await?.iter().filter(|fd| fd.encrypted) {
+			let v = val.pick(&fd.name);
+			if v.is_none() {
+				continue;
+			}
+			let keys = data_keys(ctx, fd, true).await?;
+			let v = keys.encrypt(&v, &aad(&self.id()?, fd))?;
+			out.to_mut().put(&fd.name, v);
+		}
+		Ok(out)
+	}
+}
+
+/// Decrypts the values of any encrypted fields in a record which has been
+/// loaded from storage without being processed as a document, such as when
+/// a record is fetched to check the condition of a query.
+pub(crate) async fn decrypt_record(
+	ctx: &Context,
+	opt: &Options,
+	rid: &Thing,
+	mut val: Value,
+) -> Result<Value, Error> {
+	let fds = ctx.tx().all_tb_fields(opt.ns()?, opt.db()?, &rid.tb, opt.version).await?;
+	for fd in fds.iter().filter(|fd| fd.encrypted) {
+		if let Value::Bytes(v) = val.pick(&fd.name) {
+			if is_encrypted(&v) {
+				let keys = data_keys(ctx, fd, false).await?;
+				let (v, _) = keys.decrypt(&v, &aad(rid, fd))?;
+				val.put(&fd.name, v);
+			}
+		}
+	}
+	Ok(val)
+}
+
+/// Re-encrypts the values of any encrypted fields in a stored record which
+/// were encrypted with a previous data key, returning whether any changed.
+pub(crate) fn reencrypt_record(
+	keys: &DataKeys,
+	rid: &Thing,
+	fds: &[DefineFieldStatement],
+	val: &mut Value,
+) -> Result<bool, Error> {
+	let mut changed = false;
+	for fd in fds.iter().filter(|fd| fd.encrypted) {
+		if let Value::Bytes(v) = val.pick(&fd.name) {
+			if is_encrypted(&v) {
+				let aad = aad(rid, fd);
+				let (v, rotate) = keys.decrypt(&v, &aad)?;
+				if rotate {
+					val.put(&fd.name, keys.encrypt(&v, &aad)?);
+					changed = true;
+				}
+			}
+		}
+	}
+	Ok(changed)
+}
+
+/// Fetches the data keys of the datastore within the current transaction,
+/// so that data keys which are generated by a transaction which is later
+/// cancelled are never used. The data keys are generated when the first
+/// encrypted value is written.
+async fn data_keys(
+	ctx: &Context,
+	fd: &DefineFieldStatement,
+	create: bool,
+) -> Result<Arc<DataKeys>, Error> {
+	let keyring = ctx.get_encryption().ok_or_else(|| Error::EncryptionKeyMissing {
+		name: fd.name.to_string(),
+	})?;
+	let txn = ctx.tx();
+	let key = crate::key::datakey::new();
+	match txn.get(key.clone(), None).await? {
+		Some(v) => keyring.data_keys(&v),
+		None if create => {
+			let (keys, v) = keyring.generate()?;
+			txn.put(key, v, None).await?;
+			Ok(keys)
+		}
+		None => Err(Error::Decryption(fd.name.to_string())),
+	}
+}
+
+/// The additional data which binds an encrypted value to a record and field
+fn aad(rid: &Thing, fd: &DefineFieldStatement) -> String {
+	format!("{rid}/{}", fd.name)
+}
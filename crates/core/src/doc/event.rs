@@ -31,6 +31,10 @@ impl Document {
 		let opt = &opt.new_with_perms(false);
 		// Loop through all event statements
 		for ev in self.ev(ctx, opt).await?.iter() {
+			// Scheduled events don't run on document changes
+			if ev.schedule.is_some() {
+				continue;
+			}
 			// Get the event action
 			let evt = if stm.is_delete() {
 				Value::from("DELETE")
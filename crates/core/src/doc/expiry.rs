@@ -0,0 +1,71 @@
+use crate::ctx::Context;
+use crate::dbs::Options;
+use crate::doc::Document;
+use crate::err::Error;
+use crate::sql::Duration;
+use chrono::Utc;
+
+impl Document {
+	/// Stores the time at which this record expires, replacing
+	/// any expiry time which was previously stored for the record.
+	/// The expiry is therefore measured from the last time that
+	/// the record was created or updated, and not from when the
+	/// record was first created.
+	pub(super) async fn store_record_expiry(
+		&self,
+		ctx: &Context,
+		opt: &Options,
+		expire: &Duration,
+	) -> Result<(), Error> {
+		// Remove any existing expiry entry
+		self.purge_record_expiry(ctx, opt).await?;
+		// Get the record id
+		let rid = self.id()?;
+		// Get the namespace
+		let ns = opt.ns()?;
+		// Get the database
+		let db = opt.db()?;
+		// Calculate the expiry time in seconds
+		let ts = (Utc::now().timestamp() as u64).saturating_add(expire.secs());
+		// Get the transaction
+		let txn = ctx.tx();
+		// Store the expiry queue entry
+		let key = crate::key::table::ex::new(ns, db, &rid.tb, ts, &rid.id);
+		txn.set(key, Vec::<u8>::new(), None).await?;
+		// Store the record expiry time
+		let key = crate::key::table::er::new(ns, db, &rid.tb, &rid.id);
+		txn.set(key, ts.to_be_bytes().to_vec(), None).await?;
+		// Carry on
+		Ok(())
+	}
+	/// Removes the expiry time which is stored for this record.
+	pub(super) async fn purge_record_expiry(
+		&self,
+		ctx: &Context,
+		opt: &Options,
+	) -> Result<(), Error> {
+		// Get the record id
+		let rid = self.id()?;
+		// Get the namespace
+		let ns = opt.ns()?;
+		// Get the database
+		let db = opt.db()?;
+		// Get the transaction
+		let txn = ctx.tx();
+		// Fetch the record expiry time
+		let key = crate::key::table::er::new(ns, db, &rid.tb, &rid.id);
+		if let Some(val) = txn.get(key.clone(), None).await? {
+			// Decode the stored expiry time
+			let ts = <[u8; 8]>::try_from(val.as_slice())
+				.map(u64::from_be_bytes)
+				.map_err(|_| fail!("Invalid record expiry time for {rid}"))?;
+			// Purge the expiry queue entry
+			let ex = crate::key::table::ex::new(ns, db, &rid.tb, ts, &rid.id);
+			txn.del(ex).await?;
+			// Purge the record expiry time
+			txn.del(key).await?;
+		}
+		// Carry on
+		Ok(())
+	}
+}
@@ -365,8 +365,11 @@ impl<'a> FieldEditContext<'a> {
 			};
 			// Freeze the new context
 			let ctx = ctx.freeze();
-			// Process the VALUE clause
-			let val = expr.compute(self.stk, &ctx, self.opt, doc).await?;
+			// Disable permissions, as the DEFAULT
+			// clause is part of the field definition
+			let opt = &self.opt.new_with_perms(false);
+			// Process the DEFAULT clause
+			let val = expr.compute(self.stk, &ctx, opt, doc).await?;
 			// Unfreeze the new context
 			self.context = Some(MutableContext::unfreeze(ctx)?);
 			// Return the modified value
@@ -6,10 +6,9 @@ use crate::err::Error;
 use crate::idx::ft::FtIndex;
 use crate::idx::trees::mtree::MTreeIndex;
 use crate::idx::IndexKeyBase;
-use crate::key;
 #[cfg(not(target_arch = "wasm32"))]
 use crate::kvs::ConsumeResult;
-use crate::kvs::TransactionType;
+use crate::kvs::{Key, TransactionType};
 use crate::sql::array::Array;
 use crate::sql::index::{HnswParams, Index, MTreeParams, SearchParams};
 use crate::sql::statements::DefineIndexStatement;
@@ -89,7 +88,7 @@ impl Document {
 
 		// Index operation dispatching
 		match &ix.index {
-			Index::Uniq => ic.index_unique(ctx).await?,
+			Index::Uniq | Index::UniqNullsNotDistinct => ic.index_unique(ctx).await?,
 			Index::Idx => ic.index_non_unique(ctx).await?,
 			Index::Search(p) => ic.index_full_text(stk, ctx, p).await?,
 			Index::MTree(p) => ic.index_mtree(stk, ctx, p).await?,
@@ -287,26 +286,36 @@ impl<'a> IndexOperation<'a> {
 		}
 	}
 
-	fn get_unique_index_key(&self, v: &'a Array) -> Result<key::index::Index, Error> {
-		Ok(crate::key::index::Index::new(
+	fn get_unique_index_key(&self, v: &'a Array) -> Result<Key, Error> {
+		let key = crate::key::index::Index::new(
 			self.opt.ns()?,
 			self.opt.db()?,
 			&self.ix.what,
 			&self.ix.name,
 			v,
 			None,
-		))
+		);
+		// Descending indexes store their entries in inverted byte order
+		match self.ix.desc {
+			true => key.encode_desc(),
+			false => key.encode(),
+		}
 	}
 
-	fn get_non_unique_index_key(&self, v: &'a Array) -> Result<key::index::Index, Error> {
-		Ok(crate::key::index::Index::new(
+	fn get_non_unique_index_key(&self, v: &'a Array) -> Result<Key, Error> {
+		let key = crate::key::index::Index::new(
 			self.opt.ns()?,
 			self.opt.db()?,
 			&self.ix.what,
 			&self.ix.name,
 			v,
 			Some(&self.rid.id),
-		))
+		);
+		// Descending indexes store their entries in inverted byte order
+		match self.ix.desc {
+			true => key.encode_desc(),
+			false => key.encode(),
+		}
 	}
 
 	async fn index_unique(&mut self, ctx: &Context) -> Result<(), Error> {
@@ -330,7 +339,8 @@ impl<'a> IndexOperation<'a> {
 		if let Some(n) = self.n.take() {
 			let i = Indexable::new(n, self.ix);
 			for n in i {
-				if !n.is_all_none_or_null() {
+				// Empty values are only unique if the index requires it
+				if self.ix.index == Index::UniqNullsNotDistinct || !n.is_all_none_or_null() {
 					let key = self.get_unique_index_key(&n)?;
 					if txn.putc(key, self.rid, None).await.is_err() {
 						let key = self.get_unique_index_key(&n)?;
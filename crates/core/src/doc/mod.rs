@@ -24,11 +24,14 @@ mod alter; // Modifies and updates the fields in this document
 mod changefeeds; // Processes any change feeds relevant for this document
 mod check; // Data and condition checking for this document
 mod edges; // Attempts to store the edge data for this document
+pub(crate) mod encrypt; // Encrypts and decrypts the encrypted fields of this document
 mod event; // Processes any table events relevant for this document
+mod expiry; // Stores or removes the expiry time for this document
 mod field; // Processes any schema-defined fields for this document
 mod index; // Attempts to store the index data for this document
 mod lives; // Processes any live queries relevant for this document
 mod pluck; // Pulls the projected expressions from the document
 mod purge; // Deletes this document, and any edges or indexes
+pub(crate) mod reference; // Stores or enforces the record references for this document
 mod store; // Writes the document content to the storage engine
 mod table; // Processes any foreign tables relevant for this document
@@ -37,6 +37,8 @@ impl Document {
 			let mut doc = Document::new(pro.rid, pro.ir, pro.generate, ins.0, ins.1, retry);
 			// Generate a new document id if necessary
 			doc.generate_record_id(stk, ctx, opt, stm).await?;
+			// Decrypt any encrypted fields
+			doc.decrypt_fields(ctx, opt).await?;
 			// Optionally create a save point so we can roll back any upcoming changes
 			let is_save_point = if stm.is_retryable() {
 				ctx.tx().lock().await.new_save_point().await;
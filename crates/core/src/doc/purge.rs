@@ -25,6 +25,10 @@ impl Document {
 		if !self.changed() {
 			return Ok(());
 		}
+		// Purge the record expiry time
+		if self.tb(ctx, opt).await?.expire.is_some() {
+			self.purge_record_expiry(ctx, opt).await?;
+		}
 		// Get the transaction
 		let txn = ctx.tx();
 		// Lock the transaction
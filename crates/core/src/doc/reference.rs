@@ -0,0 +1,162 @@
+use crate::ctx::Context;
+use crate::dbs::Options;
+use crate::dbs::Statement;
+use crate::doc::Document;
+use crate::err::Error;
+use crate::sql::data::Data;
+use crate::sql::operator::Operator;
+use crate::sql::output::Output;
+use crate::sql::reference::ReferenceDeleteStrategy;
+use crate::sql::statements::{DeleteStatement, UpdateStatement};
+use crate::sql::thing::Thing;
+use crate::sql::value::{Value, Values};
+use reblessive::tree::Stk;
+
+impl Document {
+	/// Stores a reverse pointer on every record which is linked
+	/// to from a field defined with a REFERENCE clause, so that
+	/// the referencing records can be found when it is deleted.
+	pub(super) async fn store_record_references(
+		&self,
+		ctx: &Context,
+		opt: &Options,
+		_stm: &Statement<'_>,
+	) -> Result<(), Error> {
+		// Check if changed
+		if !self.changed() {
+			return Ok(());
+		}
+		// Get the record id
+		let Some(rid) = &self.id else {
+			return Ok(());
+		};
+		// Get the namespace
+		let ns = opt.ns()?;
+		// Get the database
+		let db = opt.db()?;
+		// Get the transaction
+		let txn = ctx.tx();
+		// Loop through all reference fields
+		for fd in self.fd(ctx, opt).await?.iter().filter(|fd| fd.reference.is_some()) {
+			// Get the field name
+			let ff = fd.name.to_string();
+			// Get the record links before and after the change
+			let old = links(&self.initial.doc.as_ref().pick(&fd.name));
+			let new = links(&self.current.doc.as_ref().pick(&fd.name));
+			// Remove the pointers for links which were removed
+			for v in old.iter().filter(|v| !new.contains(v)) {
+				let key = crate::key::reference::new(ns, db, &v.tb, &v.id, rid, &ff);
+				txn.del(key).await?;
+			}
+			// Store the pointers for links which were added
+			for v in new.iter().filter(|v| !old.contains(v)) {
+				let key = crate::key::reference::new(ns, db, &v.tb, &v.id, rid, &ff);
+				txn.set(key, Vec::<u8>::new(), None).await?;
+			}
+		}
+		// Carry on
+		Ok(())
+	}
+	/// Processes the ON DELETE clause of each field which references
+	/// this record. If any of the fields reject the deletion, then
+	/// an error is returned, and the whole statement is cancelled.
+	pub(super) async fn process_record_references(
+		&self,
+		stk: &mut Stk,
+		ctx: &Context,
+		opt: &Options,
+		_stm: &Statement<'_>,
+	) -> Result<(), Error> {
+		// Check if changed
+		if !self.changed() {
+			return Ok(());
+		}
+		// Get the record id
+		let Some(rid) = &self.id else {
+			return Ok(());
+		};
+		// Get the namespace
+		let ns = opt.ns()?;
+		// Get the database
+		let db = opt.db()?;
+		// Get the transaction
+		let txn = ctx.tx();
+		// The referencing records are changed by the system,
+		// whether or not the user who deleted this record is
+		// allowed to change the referencing records directly.
+		let opt = &opt.new_with_perms(false);
+		// Fetch the records which reference this record
+		let beg = crate::key::reference::prefix(ns, db, &rid.tb, &rid.id);
+		let end = crate::key::reference::suffix(ns, db, &rid.tb, &rid.id);
+		let keys = txn.keys(beg..end, u32::MAX, None).await?;
+		// Loop over the referencing records
+		for key in keys.iter() {
+			let rf = crate::key::reference::Reference::decode(key)?;
+			// Fetch the referencing field definition
+			let fd = match txn.get_tb_field(ns, db, rf.ft, rf.ff).await {
+				Ok(fd) => fd,
+				Err(Error::FdNotFound {
+					..
+				}) => continue,
+				Err(e) => return Err(e),
+			};
+			// The field is no longer defined as a reference
+			let Some(reference) = &fd.reference else {
+				continue;
+			};
+			// Get the referencing record
+			let fk = Thing::from((rf.ft, rf.fk));
+			match reference.on_delete {
+				// Leave the referencing record untouched
+				ReferenceDeleteStrategy::Ignore => continue,
+				// Prevent this record from being deleted
+				ReferenceDeleteStrategy::Reject => {
+					return Err(Error::DeleteReferenced {
+						thing: rid.to_string(),
+						referrer: fk.to_string(),
+						field: fd.name.to_string(),
+					})
+				}
+				// Delete the referencing record
+				ReferenceDeleteStrategy::Cascade => {
+					let stm = DeleteStatement {
+						what: Values(vec![Value::from(fk)]),
+						output: Some(Output::None),
+						..DeleteStatement::default()
+					};
+					stm.compute(stk, ctx, opt, None).await?;
+				}
+				// Remove the link from the referencing record
+				ReferenceDeleteStrategy::Unset => {
+					// A list of links has just this record removed
+					let data = match fd.kind.as_ref().and_then(|k| k.inner_kind()) {
+						Some(_) => Data::SetExpression(vec![(
+							fd.name.clone(),
+							Operator::Dec,
+							Value::from(rid.as_ref().clone()),
+						)]),
+						None => Data::UnsetExpression(vec![fd.name.clone()]),
+					};
+					let stm = UpdateStatement {
+						what: Values(vec![Value::from(fk)]),
+						data: Some(data),
+						output: Some(Output::None),
+						..UpdateStatement::default()
+					};
+					stm.compute(stk, ctx, opt, None).await?;
+				}
+			}
+		}
+		// Carry on
+		Ok(())
+	}
+}
+
+/// Collects the record links which are stored in a field value
+pub(crate) fn links(v: &Value) -> Vec<Thing> {
+	match v {
+		Value::Thing(v) => vec![v.clone()],
+		Value::Array(v) => v.iter().flat_map(links).collect(),
+		_ => vec![],
+	}
+}
@@ -11,18 +11,22 @@ impl Document {
 		opt: &Options,
 		stm: &Statement<'_>,
 	) -> Result<(), Error> {
-		// Check if changed
-		if !self.changed() {
+		// Check if changed, or if the fields need to be re-encrypted
+		if !self.changed() && !self.rotate {
 			return Ok(());
 		}
+		// Get the table definition
+		let tb = self.tb(ctx, opt).await?;
 		// Check if the table is a view
-		if self.tb(ctx, opt).await?.drop {
+		if tb.drop {
 			return Ok(());
 		}
 		// Get the record id
 		let rid = self.id()?;
 		// Store the record data
 		let key = crate::key::thing::new(opt.ns()?, opt.db()?, &rid.tb, &rid.id);
+		// Encrypt any encrypted fields
+		let val = self.encrypt_fields(ctx, opt, &self.current.doc).await?;
 		// Match the statement type
 		match stm {
 			// This is a INSERT statement so try to insert the key.
@@ -35,7 +39,7 @@ impl Document {
 			// set and update the key, without checking if the key
 			// already exists in the storage engine.
 			Statement::Insert(_) if self.is_iteration_initial() => {
-				match ctx.tx().put(key, self, opt.version).await {
+				match ctx.tx().put(key, val.as_ref(), opt.version).await {
 					// The key already exists, so return an error
 					Err(Error::TxKeyAlreadyExists) => Err(Error::RecordExists {
 						thing: rid.as_ref().to_owned(),
@@ -53,7 +57,7 @@ impl Document {
 			// key does not exist.  If the record value exists then we
 			// retry and attempt to update the record which exists.
 			Statement::Upsert(_) if self.is_iteration_initial() => {
-				match ctx.tx().put(key, self, opt.version).await {
+				match ctx.tx().put(key, val.as_ref(), opt.version).await {
 					// The key already exists, so return an error
 					Err(Error::TxKeyAlreadyExists) => Err(Error::RecordExists {
 						thing: rid.as_ref().to_owned(),
@@ -71,7 +75,7 @@ impl Document {
 			// key does not exist. If it already exists, then we
 			// return an error, and the statement fails.
 			Statement::Create(_) => {
-				match ctx.tx().put(key, self, opt.version).await {
+				match ctx.tx().put(key, val.as_ref(), opt.version).await {
 					// The key already exists, so return an error
 					Err(Error::TxKeyAlreadyExists) => Err(Error::RecordExists {
 						thing: rid.as_ref().to_owned(),
@@ -83,8 +87,13 @@ impl Document {
 				}
 			}
 			// Let's update the stored value for the specified key
-			_ => ctx.tx().set(key, self, opt.version).await,
+			_ => ctx.tx().set(key, val.as_ref(), opt.version).await,
 		}?;
+		// Store the record expiry time, unless the
+		// records expire after their timestamp
+		if let (false, Some(expire)) = (tb.is_timeseries(), &tb.expire) {
+			self.store_record_expiry(ctx, opt, expire).await?;
+		}
 		// Carry on
 		Ok(())
 	}
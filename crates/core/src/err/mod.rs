@@ -78,6 +78,10 @@ pub enum Error {
 	#[error("Couldn't write to a read only transaction")]
 	TxReadonly,
 
+	/// A statement which modifies data was run in read-only mode
+	#[error("Unable to run a statement which modifies data in read-only mode")]
+	ReadOnly,
+
 	/// The conditional value in the request was not equal
 	#[error("Value being checked was not correct")]
 	TxConditionNotMet,
@@ -297,6 +301,16 @@ pub enum Error {
 		message: String,
 	},
 
+	/// A savepoint statement was used outside of a transaction
+	#[error("Savepoints can only be used within a transaction")]
+	SavepointOutsideTransaction,
+
+	/// The requested savepoint does not exist in the current transaction
+	#[error("The savepoint '{value}' does not exist")]
+	SavepointNotFound {
+		value: String,
+	},
+
 	/// The permissions do not allow for changing to the specified namespace
 	#[error("You don't have permission to change to the {ns} namespace")]
 	NsNotAllowed {
@@ -369,6 +383,12 @@ pub enum Error {
 		value: String,
 	},
 
+	/// The requested sequence does not exist
+	#[error("The sequence '{value}' does not exist")]
+	SqNotFound {
+		value: String,
+	},
+
 	/// The requested config does not exist
 	#[error("The config for {value} does not exist")]
 	CgNotFound {
@@ -537,6 +557,14 @@ pub enum Error {
 	#[error("Expected a single result output when using the ONLY keyword")]
 	SingleOnlyOutput,
 
+	/// Only root and namespace users can select from other databases
+	#[error("Only root and namespace users can select from tables in other databases")]
+	QualifiedPermissions,
+
+	/// A table in another database was used outside of a SELECT statement
+	#[error("Tables in other databases can only be used as the target of a SELECT statement")]
+	QualifiedStatement,
+
 	/// The permissions do not allow this query to be run on this table
 	#[error("You don't have permission to run this query on the `{table}` table")]
 	TablePermissions {
@@ -567,6 +595,14 @@ pub enum Error {
 		thing: Thing,
 	},
 
+	/// The specified record is referenced by a field which rejects its deletion
+	#[error("Cannot delete `{thing}` as it is referenced by the field `{field}` on `{referrer}`")]
+	DeleteReferenced {
+		thing: String,
+		referrer: String,
+		field: String,
+	},
+
 	/// A database index entry for the specified record already exists
 	#[error("Database index `{index}` already contains {value}, with record `{thing}`")]
 	IndexExists {
@@ -583,6 +619,26 @@ pub enum Error {
 		target_type: String,
 	},
 
+	/// The id of a record in a time series table does not start with a timestamp
+	#[error("Found record: `{thing}`, but the record id of a time series table must be an array starting with a datetime")]
+	TimeSeriesId {
+		thing: String,
+	},
+
+	/// The timestamp of a record in a time series table is not a datetime
+	#[error("Found {value} for the timestamp field `{field}`, but expected a datetime")]
+	TimeSeriesTimestamp {
+		field: String,
+		value: String,
+	},
+
+	/// The specified record did not conform to the table ASSERT clause
+	#[error("Found record: `{thing}`, but the record must conform to: {check}")]
+	TableAssert {
+		thing: String,
+		check: String,
+	},
+
 	/// The specified field did not conform to the field type check
 	#[error("Found {value} for field `{field}`, with record `{thing}`, but expected a {check}")]
 	FieldCheck {
@@ -956,6 +1012,12 @@ pub enum Error {
 		value: String,
 	},
 
+	/// The requested sequence already exists
+	#[error("The sequence '{value}' already exists")]
+	SqAlreadyExists {
+		value: String,
+	},
+
 	/// The requested config already exists
 	#[error("The config for {value} already exists")]
 	CgAlreadyExists {
@@ -1007,6 +1069,24 @@ pub enum Error {
 		index: String,
 	},
 
+	/// The existing records are already being rewritten for the specified field
+	#[error("The records of field `{field}` are currently being migrated")]
+	FieldAlreadyMigrating {
+		field: String,
+	},
+
+	/// The records of the table are still being moved to its new name
+	#[error("The records of table `{table}` are currently being moved")]
+	TbAlreadyMigrating {
+		table: String,
+	},
+
+	/// The keys of the database are still being moved to its new name
+	#[error("The keys of database `{db}` are currently being moved")]
+	DbAlreadyMigrating {
+		db: String,
+	},
+
 	/// The token has expired
 	#[error("The token has expired")]
 	ExpiredToken,
@@ -1015,6 +1095,24 @@ pub enum Error {
 	#[error("The session has expired")]
 	ExpiredSession,
 
+	/// The password of the user has expired
+	#[error("The password has expired, and must be changed")]
+	ExpiredPassword,
+
+	/// A query quota of an access method, or a limit of a namespace, has been exceeded
+	#[error("The query quota for '{scope}' has been exceeded: {message}")]
+	QuotaExceeded {
+		scope: String,
+		message: String,
+	},
+
+	/// The intermediate results of a statement have used too much memory
+	#[error("The memory budget of {limit} bytes for {scope} has been exceeded")]
+	MemoryBudgetExceeded {
+		scope: &'static str,
+		limit: usize,
+	},
+
 	/// A node task has failed
 	#[error("A node task has failed: {0}")]
 	NodeAgent(&'static str),
@@ -1193,12 +1291,45 @@ pub enum Error {
 	#[error("Received error while streaming query: {0}.")]
 	QueryStream(String),
 
+	/// The CSV input could not be imported
+	#[error("Failed to import the CSV input on line {line}: {message}")]
+	CsvImport {
+		line: usize,
+		message: String,
+	},
+
+	/// The CSV column type was not specified as `column:kind`
+	#[error("Invalid CSV column type '{0}', expected a type in the form 'column:kind'")]
+	CsvColumnType(String),
+
+	/// The encryption key which was specified is not valid
+	#[error("The encryption key is invalid: {0}")]
+	EncryptionKeyInvalid(String),
+
+	/// A field is encrypted, but no encryption key was configured
+	#[error("The field '{name}' is encrypted, but no encryption key has been configured")]
+	EncryptionKeyMissing {
+		name: String,
+	},
+
+	/// An encrypted value could not be decrypted with the configured keys
+	#[error("Unable to decrypt the value of '{0}' with the configured encryption keys")]
+	Decryption(String),
+
+	/// The datastore was not completely rewritten with a new encryption key
+	#[error("The storage is being rewritten with a new encryption key. Run the rekey command again to complete it")]
+	RekeyInProgress,
+
 	#[error("Error while ordering a result: {0}.")]
 	OrderingError(String),
 
 	#[error("Encountered an issue while processed export config: found {0}, but expected {1}.")]
 	InvalidExportConfig(Value, String),
 
+	/// The record versions were requested for an export at a point in time
+	#[error("An export at a point in time can not include the versions of the records")]
+	ExportVersionsAt,
+
 	/// Found an unexpected value in a range
 	#[error("Found {found} for bound but expected {expected}.")]
 	InvalidBound {
@@ -1373,6 +1504,7 @@ impl Error {
 			self,
 			Error::FieldCheck { .. }
 				| Error::FieldValue { .. }
+				| Error::TableAssert { .. }
 				| Error::FieldReadonly { .. }
 				| Error::FieldUndefined { .. }
 		)
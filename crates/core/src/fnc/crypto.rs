@@ -1,4 +1,5 @@
 use crate::err::Error;
+use crate::fnc::args::FromArg;
 use crate::sql::value::Value;
 use md5::Digest;
 use md5::Md5;
@@ -6,37 +7,49 @@ use sha1::Sha1;
 use sha2::Sha256;
 use sha2::Sha512;
 
-pub fn blake3((arg,): (String,)) -> Result<Value, Error> {
-	Ok(blake3::hash(arg.as_bytes()).to_string().into())
+/// The data which is hashed, specified as either a string or bytes
+pub struct Data(Vec<u8>);
+
+impl FromArg for Data {
+	fn from_arg(arg: Value) -> Result<Self, Error> {
+		match arg {
+			Value::Bytes(v) => Ok(Data(v.into_inner())),
+			v => v.coerce_to_string().map(|v| Data(v.into_bytes())),
+		}
+	}
+}
+
+pub fn blake3((arg,): (Data,)) -> Result<Value, Error> {
+	Ok(blake3::hash(&arg.0).to_string().into())
 }
 
-pub fn md5((arg,): (String,)) -> Result<Value, Error> {
+pub fn md5((arg,): (Data,)) -> Result<Value, Error> {
 	let mut hasher = Md5::new();
-	hasher.update(arg.as_str());
+	hasher.update(&arg.0);
 	let val = hasher.finalize();
 	let val = format!("{val:x}");
 	Ok(val.into())
 }
 
-pub fn sha1((arg,): (String,)) -> Result<Value, Error> {
+pub fn sha1((arg,): (Data,)) -> Result<Value, Error> {
 	let mut hasher = Sha1::new();
-	hasher.update(arg.as_str());
+	hasher.update(&arg.0);
 	let val = hasher.finalize();
 	let val = format!("{val:x}");
 	Ok(val.into())
 }
 
-pub fn sha256((arg,): (String,)) -> Result<Value, Error> {
+pub fn sha256((arg,): (Data,)) -> Result<Value, Error> {
 	let mut hasher = Sha256::new();
-	hasher.update(arg.as_str());
+	hasher.update(&arg.0);
 	let val = hasher.finalize();
 	let val = format!("{val:x}");
 	Ok(val.into())
 }
 
-pub fn sha512((arg,): (String,)) -> Result<Value, Error> {
+pub fn sha512((arg,): (Data,)) -> Result<Value, Error> {
 	let mut hasher = Sha512::new();
-	hasher.update(arg.as_str());
+	hasher.update(&arg.0);
 	let val = hasher.finalize();
 	let val = format!("{val:x}");
 	Ok(val.into())
@@ -42,6 +42,10 @@ pub fn centroid((arg,): (Geometry,)) -> Result<Value, Error> {
 	Ok(centroid.map(Into::into).unwrap_or(Value::None))
 }
 
+pub fn contains((v, w): (Geometry, Geometry)) -> Result<Value, Error> {
+	Ok(v.contains(&w).into())
+}
+
 pub fn distance((v, w): (Geometry, Geometry)) -> Result<Value, Error> {
 	Ok(match (v, w) {
 		(Geometry::Point(v), Geometry::Point(w)) => v.haversine_distance(&w).into(),
@@ -80,6 +84,10 @@ pub mod hash {
 	}
 }
 
+pub fn intersects((v, w): (Geometry, Geometry)) -> Result<Value, Error> {
+	Ok(v.intersects(&w).into())
+}
+
 pub mod is {
 
 	use crate::err::Error;
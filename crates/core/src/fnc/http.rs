@@ -1,4 +1,5 @@
 use crate::ctx::Context;
+use crate::dbs::Options;
 use crate::err::Error;
 use crate::sql::value::Value;
 
@@ -41,6 +42,14 @@ pub async fn delete(_: &Context, (_, _): (Value, Option<Value>)) -> Result<Value
 	Err(Error::HttpDisabled)
 }
 
+#[cfg(not(feature = "http"))]
+pub async fn webhook(
+	_: (&Context, &Options),
+	(_, _, _): (Value, Option<Value>, Option<Value>),
+) -> Result<Value, Error> {
+	Err(Error::HttpDisabled)
+}
+
 #[cfg(feature = "http")]
 fn try_as_uri(fn_name: &str, value: Value) -> Result<crate::sql::Strand, Error> {
 	match value {
@@ -120,3 +129,13 @@ pub async fn delete(ctx: &Context, (uri, opts): (Value, Option<Value>)) -> Resul
 	let opts = try_as_opts("http::delete", "The second argument should be an object.", opts)?;
 	crate::fnc::util::http::delete(ctx, uri, opts).await
 }
+
+#[cfg(feature = "http")]
+pub async fn webhook(
+	(ctx, opt): (&Context, &Options),
+	(uri, body, opts): (Value, Option<Value>, Option<Value>),
+) -> Result<Value, Error> {
+	let uri = try_as_uri("http::webhook", uri)?;
+	let opts = try_as_opts("http::webhook", "The third argument should be an object.", opts)?;
+	crate::fnc::util::http::webhook(ctx, opt, uri, body.unwrap_or(Value::Null), opts).await
+}
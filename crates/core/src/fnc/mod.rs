@@ -26,6 +26,7 @@ pub mod rand;
 pub mod record;
 pub mod script;
 pub mod search;
+pub mod sequence;
 pub mod session;
 pub mod sleep;
 pub mod string;
@@ -44,6 +45,10 @@ pub async fn run(
 	name: &str,
 	args: Vec<Value>,
 ) -> Result<Value, Error> {
+	// Requests and delays can not be undone if the statement is retried
+	if name.starts_with("http::") || name.eq("sleep") {
+		ctx.add_side_effect();
+	}
 	if name.eq("sleep")
 		|| name.eq("array::all")
 		|| name.eq("array::any")
@@ -59,12 +64,14 @@ pub async fn run(
 		|| name.eq("array::reduce")
 		|| name.eq("array::some")
 		|| name.eq("record::exists")
+		|| name.eq("record::history")
 		|| name.eq("type::field")
 		|| name.eq("type::fields")
 		|| name.eq("value::diff")
 		|| name.eq("value::patch")
 		|| name.starts_with("http")
 		|| name.starts_with("search")
+		|| name.starts_with("sequence")
 		|| name.starts_with("crypto::argon2")
 		|| name.starts_with("crypto::bcrypt")
 		|| name.starts_with("crypto::pbkdf2")
@@ -193,9 +200,11 @@ pub fn synchronous(
 		"geo::area" => geo::area,
 		"geo::bearing" => geo::bearing,
 		"geo::centroid" => geo::centroid,
+		"geo::contains" => geo::contains,
 		"geo::distance" => geo::distance,
 		"geo::hash::decode" => geo::hash::decode,
 		"geo::hash::encode" => geo::hash::encode,
+		"geo::intersects" => geo::intersects,
 		"geo::is::valid" => geo::is::valid,
 		//
 		"math::abs" => math::abs,
@@ -270,10 +279,10 @@ pub fn synchronous(
 		"rand::int" => rand::int,
 		"rand::string" => rand::string,
 		"rand::time" => rand::time,
-		"rand::ulid" => rand::ulid,
+		"rand::ulid" => rand::ulid(ctx),
 		"rand::uuid::v4" => rand::uuid::v4,
 		"rand::uuid::v7" => rand::uuid::v7,
-		"rand::uuid" => rand::uuid,
+		"rand::uuid" => rand::uuid(ctx),
 		//
 		"record::id" => record::id,
 		"record::table" => record::tb,
@@ -364,7 +373,7 @@ pub fn synchronous(
 		"time::nano" => time::nano,
 		"time::micros" => time::micros,
 		"time::millis" => time::millis,
-		"time::now" => time::now,
+		"time::now" => time::now(ctx),
 		"time::round" => time::round,
 		"time::second" => time::second,
 		"time::timezone" => time::timezone,
@@ -508,14 +517,18 @@ pub async fn asynchronous(
 		"http::post" =>  http::post(ctx).await,
 		"http::patch" => http::patch(ctx).await,
 		"http::delete" => http::delete(ctx).await,
+		"http::webhook" => http::webhook((ctx, opt)).await,
 		//
 		"record::exists" => record::exists((stk, ctx, Some(opt), doc)).await,
+		"record::history" => record::history((ctx, opt)).await,
 		//
 		"search::analyze" => search::analyze((stk, ctx, Some(opt))).await,
 		"search::score" => search::score((ctx, doc)).await,
 		"search::highlight" => search::highlight((ctx, doc)).await,
 		"search::offsets" => search::offsets((ctx, doc)).await,
 		//
+		"sequence::nextval" => sequence::nextval((ctx, opt)).await,
+		//
 		"sleep" => sleep::sleep(ctx).await,
 		//
 		"type::field" => r#type::field((stk, ctx, Some(opt), doc)).await,
@@ -660,9 +673,11 @@ pub async fn idiom(
 				"area" => geo::area,
 				"bearing" => geo::bearing,
 				"centroid" => geo::centroid,
+				"contains" => geo::contains,
 				"distance" => geo::distance,
 				"hash_decode" => geo::hash::decode,
 				"hash_encode" => geo::hash::encode,
+				"intersects" => geo::intersects,
 				"is_valid" => geo::is::valid,
 			)
 		}
@@ -1,4 +1,5 @@
 use crate::cnf::ID_CHARS;
+use crate::ctx::Context;
 use crate::err::Error;
 use crate::sql::uuid::Uuid;
 use crate::sql::value::Value;
@@ -181,7 +182,7 @@ pub fn time((range,): (Option<(Value, Value)>,)) -> Result<Value, Error> {
 	Err(fail!("Expected a valid datetime, but were unable to generate one"))
 }
 
-pub fn ulid((timestamp,): (Option<Datetime>,)) -> Result<Value, Error> {
+pub fn ulid(ctx: &Context, (timestamp,): (Option<Datetime>,)) -> Result<Value, Error> {
 	let ulid = match timestamp {
 		Some(timestamp) => {
 			#[cfg(target_arch = "wasm32")]
@@ -196,13 +197,16 @@ pub fn ulid((timestamp,): (Option<Datetime>,)) -> Result<Value, Error> {
 
 			Ulid::from_datetime(timestamp.0.into())
 		}
-		None => Ulid::new(),
+		None => match ctx.get_generator() {
+			Some(v) => return Ok(v.ulid().into()),
+			None => Ulid::new(),
+		},
 	};
 
 	Ok(ulid.to_string().into())
 }
 
-pub fn uuid((timestamp,): (Option<Datetime>,)) -> Result<Value, Error> {
+pub fn uuid(ctx: &Context, (timestamp,): (Option<Datetime>,)) -> Result<Value, Error> {
 	let uuid = match timestamp {
 		Some(timestamp) => {
 			#[cfg(target_arch = "wasm32")]
@@ -217,7 +221,10 @@ pub fn uuid((timestamp,): (Option<Datetime>,)) -> Result<Value, Error> {
 
 			Uuid::new_v7_from_datetime(timestamp)
 		}
-		None => Uuid::new(),
+		None => match ctx.get_generator() {
+			Some(v) => v.uuid(),
+			None => Uuid::new(),
+		},
 	};
 	Ok(uuid.into())
 }
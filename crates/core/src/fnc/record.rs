@@ -1,10 +1,15 @@
+use crate::cnf::NORMAL_FETCH_SIZE;
 use crate::ctx::Context;
 use crate::dbs::Options;
+use crate::doc::encrypt::decrypt_record;
 use crate::doc::CursorDoc;
 use crate::err::Error;
+use crate::iam::{Action, ResourceKind};
 use crate::sql::paths::ID;
 use crate::sql::thing::Thing;
 use crate::sql::value::Value;
+use crate::sql::{Base, Datetime};
+use chrono::{TimeZone, Utc};
 use reblessive::tree::Stk;
 
 pub async fn exists(
@@ -21,6 +26,37 @@ pub async fn exists(
 	}
 }
 
+/// The stored versions of a record, from the oldest to the newest
+pub async fn history((ctx, opt): (&Context, &Options), (arg,): (Thing,)) -> Result<Value, Error> {
+	// Allowed to run?
+	opt.is_allowed(Action::View, ResourceKind::Table, &Base::Db)?;
+	// Fetch the transaction
+	let txn = ctx.tx();
+	let (ns, db) = (opt.ns()?, opt.db()?);
+	// Scan every version of the record key
+	let beg = crate::key::thing::new(ns, db, &arg.tb, &arg.id).encode()?;
+	let mut end = beg.clone();
+	end.push(0x00);
+	let mut next = Some(beg..end);
+	let mut out = Vec::new();
+	while let Some(rng) = next {
+		let batch = txn.batch_versions(rng, *NORMAL_FETCH_SIZE).await?;
+		next = batch.next;
+		for (_, v, version, deleted) in batch.versioned_values {
+			let value = match v.is_empty() {
+				true => Value::None,
+				false => decrypt_record(ctx, opt, &arg, Value::from(&v)).await?,
+			};
+			out.push(Value::from(map! {
+				"at".to_string() => Datetime::from(Utc.timestamp_nanos(version as i64)).into(),
+				"deleted".to_string() => deleted.into(),
+				"value".to_string() => value,
+			}));
+		}
+	}
+	Ok(out.into())
+}
+
 pub fn id((arg,): (Thing,)) -> Result<Value, Error> {
 	Ok(arg.id.into())
 }
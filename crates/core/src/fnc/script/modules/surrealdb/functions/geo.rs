@@ -13,7 +13,9 @@ impl_module_def!(
 	"area" => run,
 	"bearing" => run,
 	"centroid" => run,
+	"contains" => run,
 	"distance" => run,
 	"hash" => (hash::Package),
+	"intersects" => run,
 	"is" => (is::Package)
 );
@@ -13,5 +13,6 @@ impl_module_def!(
 	"put" => fut Async,
 	"post" => fut Async,
 	"patch" => fut Async,
-	"delete" => fut Async
+	"delete" => fut Async,
+	"webhook" => fut Async
 );
@@ -10,6 +10,7 @@ impl_module_def!(
 	Package,
 	"record",
 	"exists" => fut Async,
+	"history" => fut Async,
 	"id" => run,
 	"table" => run,
 	"tb" => run
@@ -0,0 +1,34 @@
+use crate::ctx::Context;
+use crate::dbs::Options;
+use crate::err::Error;
+use crate::iam::{Action, ResourceKind};
+use crate::sql::value::Value;
+use crate::sql::Base;
+
+/// Returns the next value of a sequence, after which the
+/// sequence is incremented within the current transaction
+pub async fn nextval((ctx, opt): (&Context, &Options), (name,): (String,)) -> Result<Value, Error> {
+	// Allowed to run? Sequences advanced by the clauses of
+	// a definition are allowed, as permissions are disabled.
+	if opt.check_perms(Action::Edit)? {
+		opt.is_allowed(Action::Edit, ResourceKind::Sequence, &Base::Db)?;
+	}
+	// Fetch the transaction
+	let txn = ctx.tx();
+	let (ns, db) = (opt.ns()?, opt.db()?);
+	// Fetch the sequence definition
+	let sq = txn.get_db_sequence(ns, db, &name).await?;
+	// Fetch the last value which was returned
+	let key = crate::key::database::sv::new(ns, db, &sq.name);
+	let next = match txn.get(key.clone(), None).await? {
+		Some(v) => <[u8; 8]>::try_from(v.as_slice())
+			.map(i64::from_be_bytes)
+			.map_err(|_| fail!("Invalid value for sequence {name}"))?
+			.checked_add(1)
+			.ok_or_else(|| Error::ArithmeticOverflow(format!("sequence::nextval('{name}')")))?,
+		None => sq.start,
+	};
+	// Store the value which is being returned
+	txn.set(key, next.to_be_bytes().to_vec(), None).await?;
+	Ok(next.into())
+}
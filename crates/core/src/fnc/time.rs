@@ -1,3 +1,4 @@
+use crate::ctx::Context;
 use crate::err::Error;
 use crate::sql::datetime::Datetime;
 use crate::sql::duration::Duration;
@@ -167,8 +168,11 @@ pub fn micros((val,): (Option<Datetime>,)) -> Result<Value, Error> {
 	})
 }
 
-pub fn now(_: ()) -> Result<Value, Error> {
-	Ok(Datetime::default().into())
+pub fn now(ctx: &Context, _: ()) -> Result<Value, Error> {
+	match ctx.get_generator() {
+		Some(v) => Ok(v.now().into()),
+		None => Ok(Datetime::default().into()),
+	}
 }
 
 pub fn round((val, duration): (Datetime, Duration)) -> Result<Value, Error> {
@@ -1,8 +1,12 @@
+use crate::cnf::HTTP_MAX_RESPONSE_SIZE;
 use crate::ctx::Context;
+use crate::dbs::Options;
 use crate::err::Error;
+use crate::kvs::Webhook;
 use crate::sql::{Bytes, Object, Strand, Value};
 use crate::syn;
 
+use encoding_rs::{Encoding, UTF_8};
 use reqwest::header::CONTENT_TYPE;
 use reqwest::{Client, RequestBuilder, Response};
 use url::Url;
@@ -19,21 +23,61 @@ fn encode_body(req: RequestBuilder, body: Value) -> RequestBuilder {
 	}
 }
 
+async fn read_body(mut res: Response) -> Result<Vec<u8>, Error> {
+	let limit = *HTTP_MAX_RESPONSE_SIZE;
+	let error = || Error::Http(format!("The response body exceeds the limit of {limit} bytes"));
+	// Check the advertised size of the response body
+	if res.content_length().is_some_and(|v| v > limit as u64) {
+		return Err(error());
+	}
+	// Check the received size of the response body as it
+	// arrives, so that a larger body is never fully buffered
+	let mut body = Vec::new();
+	while let Some(chunk) = res.chunk().await? {
+		if body.len() + chunk.len() > limit {
+			return Err(error());
+		}
+		body.extend_from_slice(&chunk);
+	}
+	Ok(body)
+}
+
+async fn read_text(res: Response) -> Result<String, Error> {
+	// Get the character set of the response body
+	let encoding = res
+		.headers()
+		.get(CONTENT_TYPE)
+		.and_then(|v| v.to_str().ok())
+		.and_then(|v| {
+			v.split(';').skip(1).find_map(|p| match p.split_once('=') {
+				Some((k, v)) if k.trim().eq_ignore_ascii_case("charset") => {
+					Encoding::for_label(v.trim().trim_matches('"').as_bytes())
+				}
+				_ => None,
+			})
+		})
+		.unwrap_or(UTF_8);
+	// Decode the response body
+	let body = read_body(res).await?;
+	let (txt, _, _) = encoding.decode(&body);
+	Ok(txt.into_owned())
+}
+
 async fn decode_response(res: Response) -> Result<Value, Error> {
 	match res.status() {
 		s if s.is_success() => match res.headers().get(CONTENT_TYPE) {
 			Some(mime) => match mime.to_str() {
 				Ok(v) if v.starts_with("application/json") => {
-					let txt = res.text().await?;
+					let txt = read_text(res).await?;
 					let val = syn::json(&txt)?;
 					Ok(val)
 				}
 				Ok(v) if v.starts_with("application/octet-stream") => {
-					let bytes = res.bytes().await?;
-					Ok(Value::Bytes(Bytes(bytes.into())))
+					let bytes = read_body(res).await?;
+					Ok(Value::Bytes(Bytes(bytes)))
 				}
 				Ok(v) if v.starts_with("text") => {
-					let txt = res.text().await?;
+					let txt = read_text(res).await?;
 					let val = txt.into();
 					Ok(val)
 				}
@@ -224,3 +268,58 @@ pub async fn delete(ctx: &Context, uri: Strand, opts: impl Into<Object>) -> Resu
 	// Receive the response as a value
 	decode_response(res).await
 }
+
+pub async fn webhook(
+	ctx: &Context,
+	opt: &Options,
+	uri: Strand,
+	body: Value,
+	opts: impl Into<Object>,
+) -> Result<Value, Error> {
+	// Check if the URI is valid and allowed
+	let url = Url::parse(&uri).map_err(|_| Error::InvalidUrl(uri.to_string()))?;
+	ctx.check_allowed_net(&url)?;
+	// Get the NS and DB
+	let ns = opt.ns()?;
+	let db = opt.db()?;
+	// Queue the request, so that it is only
+	// delivered if the transaction succeeds
+	let id = uuid::Uuid::now_v7();
+	let val = Webhook {
+		url: url.to_string(),
+		body,
+		headers: opts.into(),
+		..Webhook::default()
+	};
+	let key = crate::key::database::wh::new(ns, db, val.next, id);
+	ctx.tx().set(key, val, None).await?;
+	// Return the identifier of the request
+	Ok(Value::from(id))
+}
+
+/// Sends a queued webhook request, checking that it was received successfully
+pub(crate) async fn deliver(url: Url, hook: &Webhook) -> Result<(), Error> {
+	// Set a default client
+	let cli = Client::builder().build()?;
+	// Start a new POST request
+	let mut req = cli.post(url);
+	// Add the User-Agent header
+	if cfg!(not(target_arch = "wasm32")) {
+		req = req.header("User-Agent", "SurrealDB");
+	}
+	// Add specified header values
+	for (k, v) in hook.headers.iter() {
+		req = req.header(k.as_str(), v.to_raw_string());
+	}
+	// Submit the request body
+	req = encode_body(req, hook.body.clone());
+	// Send the request and wait
+	#[cfg(not(target_arch = "wasm32"))]
+	let req = req.timeout(std::time::Duration::from_secs(*crate::cnf::WEBHOOK_TIMEOUT));
+	let res = req.send().await?;
+	// Check the response status
+	match res.status() {
+		s if s.is_success() => Ok(()),
+		s => Err(Error::Http(s.canonical_reason().unwrap_or_default().to_owned())),
+	}
+}
@@ -9,7 +9,7 @@ use super::Level;
 use cedar_policy::{Entity, EntityId, EntityTypeName, EntityUid, RestrictedExpression};
 use serde::{Deserialize, Serialize};
 
-#[revisioned(revision = 2)]
+#[revisioned(revision = 3)]
 #[derive(Clone, Default, Debug, Eq, PartialEq, PartialOrd, Hash, Serialize, Deserialize)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -35,6 +35,8 @@ pub enum ResourceKind {
 
 	// IAM
 	Actor,
+	#[revision(start = 3)]
+	Sequence,
 }
 
 #[revisioned(revision = 1)]
@@ -64,6 +66,7 @@ impl std::fmt::Display for ResourceKind {
 			ResourceKind::Index => write!(f, "Index"),
 			ResourceKind::Access => write!(f, "Access"),
 			ResourceKind::Actor => write!(f, "Actor"),
+			ResourceKind::Sequence => write!(f, "Sequence"),
 			ResourceKind::Config(c) => write!(f, "Config::{c}"),
 		}
 	}
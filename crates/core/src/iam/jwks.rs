@@ -1,3 +1,4 @@
+use crate::cnf::TOKEN_CLOCK_SKEW;
 use crate::dbs::capabilities::NetTarget;
 use crate::err::Error;
 use crate::kvs::Datastore;
@@ -211,6 +212,8 @@ pub(super) async fn config(
 			// Now that the audience claim is validated by default, we could allow users to leverage this.
 			// This will most likely involve defining an audience string via "DEFINE ACCESS ... TYPE JWT".
 			val.validate_aud = false;
+			// Tolerate the configured amount of clock skew
+			val.leeway = *TOKEN_CLOCK_SKEW;
 
 			Ok((dec, val))
 		}
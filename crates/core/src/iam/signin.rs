@@ -3,6 +3,7 @@ use super::verify::{
 };
 use super::{Actor, Level, Role};
 use crate::cnf::{EXPERIMENTAL_BEARER_ACCESS, INSECURE_FORWARD_ACCESS_ERRORS, SERVER_NAME};
+use crate::dbs::audit::{Category, Origin};
 use crate::dbs::Session;
 use crate::err::Error;
 use crate::iam::issue::{config, expiration};
@@ -28,7 +29,7 @@ pub async fn signin(kvs: &Datastore, session: &mut Session, vars: Object) -> Res
 	let db = vars.get("DB").or_else(|| vars.get("db"));
 	let ac = vars.get("AC").or_else(|| vars.get("ac"));
 	// Check if the parameters exist
-	match (ns, db, ac) {
+	let res = match (ns, db, ac) {
 		// DB signin with access method
 		(Some(ns), Some(db), Some(ac)) => {
 			// Process the provided values
@@ -104,7 +105,13 @@ pub async fn signin(kvs: &Datastore, session: &mut Session, vars: Object) -> Res
 			}
 		}
 		_ => Err(Error::NoSigninTarget),
+	};
+	// Record the attempt in the audit log
+	if let Some(log) = kvs.audit_log() {
+		let res = res.as_ref().map(|_| ());
+		log.record(Category::Auth, "signin", &session.au, Origin::from(&*session), None, res);
 	}
+	res
 }
 
 pub async fn db_access(
@@ -385,6 +392,7 @@ pub async fn db_user(
 			session.ns = Some(ns.to_owned());
 			session.db = Some(db.to_owned());
 			session.exp = expiration(u.duration.session)?;
+			session.ac = None;
 			session.au = Arc::new((&u, Level::Database(ns.to_owned(), db.to_owned())).try_into()?);
 			// Check the authentication token
 			match enc {
@@ -393,6 +401,8 @@ pub async fn db_user(
 				_ => Err(Error::TokenMakingFailed),
 			}
 		}
+		// The password verified, but has expired
+		Err(Error::ExpiredPassword) => Err(Error::ExpiredPassword),
 		// The password did not verify
 		Err(e) => {
 			debug!("Failed to verify signin credentials for user `{user}` in database `{ns}/{db}`: {e}");
@@ -565,6 +575,7 @@ pub async fn ns_user(
 			session.tk = Some((&val).into());
 			session.ns = Some(ns.to_owned());
 			session.exp = expiration(u.duration.session)?;
+			session.ac = None;
 			session.au = Arc::new((&u, Level::Namespace(ns.to_owned())).try_into()?);
 			// Check the authentication token
 			match enc {
@@ -573,6 +584,8 @@ pub async fn ns_user(
 				_ => Err(Error::TokenMakingFailed),
 			}
 		}
+		// The password verified, but has expired
+		Err(Error::ExpiredPassword) => Err(Error::ExpiredPassword),
 		// The password did not verify
 		Err(e) => {
 			debug!(
@@ -610,6 +623,7 @@ pub async fn root_user(
 			// Set the authentication on the session
 			session.tk = Some(val.into());
 			session.exp = expiration(u.duration.session)?;
+			session.ac = None;
 			session.au = Arc::new((&u, Level::Root).try_into()?);
 			// Check the authentication token
 			match enc {
@@ -618,6 +632,8 @@ pub async fn root_user(
 				_ => Err(Error::TokenMakingFailed),
 			}
 		}
+		// The password verified, but has expired
+		Err(Error::ExpiredPassword) => Err(Error::ExpiredPassword),
 		// The password did not verify
 		Err(e) => {
 			debug!("Failed to verify signin credentials for user `{user}` in root: {e}");
@@ -626,6 +642,76 @@ pub async fn root_user(
 	}
 }
 
+/// Issue a new token for the system user which a session is authenticated as,
+/// so that clients can stay signed in without sending their credentials again
+pub async fn refresh(kvs: &Datastore, session: &mut Session) -> Result<String, Error> {
+	// Only sessions which signed in as a system user, or authenticated with
+	// the token of a system user, can refresh their token. The sessions of
+	// an access method can have the same id as a system user, so are rejected.
+	if session.ac.is_some() || session.tk.is_none() {
+		return Err(Error::InvalidAuth);
+	}
+	let level = session.au.level().clone();
+	let user = session.au.id().to_owned();
+	// Create a new readonly transaction
+	let tx = kvs.transaction(Read, Optimistic).await?;
+	// Fetch the authenticated system user from storage
+	let res = match &level {
+		Level::Root => tx.get_root_user(&user).await,
+		Level::Namespace(ns) => tx.get_ns_user(ns, &user).await,
+		Level::Database(ns, db) => tx.get_db_user(ns, db, &user).await,
+		_ => Err(Error::InvalidAuth),
+	};
+	// Ensure that the transaction is cancelled
+	tx.cancel().await?;
+	// Only defined system users can refresh their tokens
+	let u = res.map_err(|e| {
+		debug!("Failed to refresh the token of user `{user}`: {e}");
+		Error::InvalidAuth
+	})?;
+	// The password must be changed before a new token is issued
+	if u.is_password_expired() {
+		return Err(Error::ExpiredPassword);
+	}
+	// Sessions which authenticated before the password was changed can not be refreshed
+	let iat = match &session.tk {
+		Some(Value::Object(tk)) => match tk.get("iat") {
+			Some(Value::Number(iat)) => iat.as_int(),
+			_ => 0,
+		},
+		_ => 0,
+	};
+	if u.changed.as_ref().is_some_and(|changed| iat < changed.timestamp()) {
+		debug!("Failed to refresh the token of user `{user}`: the password has changed");
+		return Err(Error::InvalidAuth);
+	}
+	// Create the authentication key
+	let key = EncodingKey::from_secret(u.code.as_ref());
+	// Create the authentication claim
+	let val = Claims {
+		iss: Some(SERVER_NAME.to_owned()),
+		iat: Some(Utc::now().timestamp()),
+		nbf: Some(Utc::now().timestamp()),
+		exp: expiration(u.duration.token)?,
+		jti: Some(Uuid::new_v4().to_string()),
+		ns: level.ns().map(str::to_owned),
+		db: level.db().map(str::to_owned),
+		id: Some(user),
+		..Claims::default()
+	};
+	// Create the authentication token
+	let enc = encode(&HEADER, &val, &key);
+	// Set the authentication on the session
+	session.tk = Some((&val).into());
+	session.exp = expiration(u.duration.session)?;
+	// Check the authentication token
+	match enc {
+		// The auth token was created successfully
+		Ok(tk) => Ok(tk),
+		_ => Err(Error::TokenMakingFailed),
+	}
+}
+
 pub async fn root_access(
 	kvs: &Datastore,
 	session: &mut Session,
@@ -3657,6 +3743,7 @@ dn/RsYEONbwQSjIfMPkvxF+8HQ==
 				comment: None,
 				if_not_exists: false,
 				overwrite: false,
+				changed: None,
 			};
 
 			// Use pre-parsed definition, which bypasses the existent role check during parsing.
@@ -3705,4 +3792,128 @@ dn/RsYEONbwQSjIfMPkvxF+8HQ==
 			}
 		}
 	}
+
+	#[tokio::test]
+	async fn test_signin_user_expired_password() {
+		let ds = Datastore::new("memory").await.unwrap();
+		let sess = Session::owner();
+		ds.execute(
+			"DEFINE USER user ON ROOT PASSWORD 'pass' DURATION FOR PASSWORD 1ns",
+			&sess,
+			None,
+		)
+		.await
+		.unwrap();
+
+		// The password has expired since the user was defined
+		let mut sess = Session::default();
+		let res = root_user(&ds, &mut sess, "user".to_string(), "pass".to_string()).await;
+		match res {
+			Err(Error::ExpiredPassword) => {} // ok
+			res => panic!("Expected an expired password error, but instead received: {:?}", res),
+		}
+
+		// Changing the password resets the password expiration
+		let sess = Session::owner();
+		ds.execute("ALTER USER user ON ROOT PASSWORD 'new' DURATION FOR PASSWORD 90d", &sess, None)
+			.await
+			.unwrap();
+
+		let mut sess = Session::default();
+		let res = root_user(&ds, &mut sess, "user".to_string(), "pass".to_string()).await;
+		assert!(res.is_err(), "Unexpected successful signin with the previous password");
+		let res = root_user(&ds, &mut sess, "user".to_string(), "new".to_string()).await;
+		assert!(res.is_ok(), "Failed to signin with the changed password: {:?}", res);
+	}
+
+	#[tokio::test]
+	async fn test_refresh_user() {
+		let ds = Datastore::new("memory").await.unwrap();
+		let sess = Session::owner().with_ns("test");
+		ds.execute(
+			"DEFINE USER user ON NS PASSWORD 'pass' ROLES EDITOR DURATION FOR TOKEN 1h, FOR SESSION 2h",
+			&sess,
+			None,
+		)
+		.await
+		.unwrap();
+
+		// Sessions which are not authenticated can not be refreshed
+		let mut sess = Session::default().with_ns("test");
+		let res = refresh(&ds, &mut sess).await;
+		assert!(matches!(res, Err(Error::InvalidAuth)), "Unexpected result: {:?}", res);
+
+		// Sessions of an access method can not be refreshed, even with the id of a system user
+		let mut sess = Session::default().with_ns("test");
+		sess.ac = Some("api".to_string());
+		sess.tk = Some(Value::from("token"));
+		sess.au = Arc::new(Auth::new(Actor::new(
+			"user".to_string(),
+			vec![Role::Owner],
+			Level::Namespace("test".to_string()),
+		)));
+		let res = refresh(&ds, &mut sess).await;
+		assert!(matches!(res, Err(Error::InvalidAuth)), "Unexpected result: {:?}", res);
+
+		// Sessions which were not authenticated with a password or token can not be refreshed
+		sess.ac = None;
+		sess.tk = None;
+		let res = refresh(&ds, &mut sess).await;
+		assert!(matches!(res, Err(Error::InvalidAuth)), "Unexpected result: {:?}", res);
+
+		// Authenticated system users receive a new token
+		sess.ac = Some("api".to_string());
+		ns_user(&ds, &mut sess, "test".to_string(), "user".to_string(), "pass".to_string())
+			.await
+			.unwrap();
+		let tk = refresh(&ds, &mut sess).await.unwrap();
+		let key = DecodingKey::from_secret(ds_user_code(&ds).await.as_ref());
+		let token = decode::<Claims>(&tk, &key, &Validation::new(Algorithm::HS512)).unwrap();
+		assert_eq!(token.claims.ns, Some("test".to_string()));
+		assert_eq!(token.claims.id, Some("user".to_string()));
+		// Expiration should match the current time plus session duration with some margin
+		let exp = sess.exp.unwrap();
+		let min_exp = (Utc::now() + Duration::hours(2) - Duration::seconds(10)).timestamp();
+		let max_exp = (Utc::now() + Duration::hours(2) + Duration::seconds(10)).timestamp();
+		assert!(
+			exp > min_exp && exp < max_exp,
+			"Session expiration is expected to follow the defined duration"
+		);
+	}
+
+	#[tokio::test]
+	async fn test_refresh_user_after_password_change() {
+		let ds = Datastore::new("memory").await.unwrap();
+		let sess = Session::owner().with_ns("test");
+		ds.execute("DEFINE USER user ON NS PASSWORD 'pass' ROLES EDITOR", &sess, None)
+			.await
+			.unwrap();
+		let mut sess = Session::default().with_ns("test");
+		let tk =
+			ns_user(&ds, &mut sess, "test".to_string(), "user".to_string(), "pass".to_string())
+				.await
+				.unwrap();
+		let code = ds_user_code(&ds).await;
+		// The claims are issued in whole seconds
+		tokio::time::sleep(std::time::Duration::from_secs(1)).await;
+		// Changing the password generates a new signing key for the user
+		ds.execute("ALTER USER user ON NS PASSWORD 'new'", &Session::owner().with_ns("test"), None)
+			.await
+			.unwrap();
+		assert_ne!(ds_user_code(&ds).await, code);
+		// The token which was issued with the previous password is no longer valid
+		let mut other = Session::default();
+		let res = crate::iam::verify::token(&ds, &mut other, &tk).await;
+		assert!(res.is_err(), "Unexpected successful authentication with the previous token");
+		// The session which signed in with the previous password can not be refreshed
+		let res = refresh(&ds, &mut sess).await;
+		assert!(matches!(res, Err(Error::InvalidAuth)), "Unexpected result: {:?}", res);
+	}
+
+	async fn ds_user_code(ds: &Datastore) -> String {
+		let tx = ds.transaction(Read, Optimistic).await.unwrap();
+		let user = tx.get_ns_user("test", "user").await.unwrap();
+		tx.cancel().await.unwrap();
+		user.code.clone()
+	}
 }
@@ -1,5 +1,6 @@
 use super::verify::authenticate_record;
 use crate::cnf::{INSECURE_FORWARD_ACCESS_ERRORS, SERVER_NAME};
+use crate::dbs::audit::{Category, Origin};
 use crate::dbs::Session;
 use crate::err::Error;
 use crate::iam::issue::{config, expiration};
@@ -27,7 +28,7 @@ pub async fn signup(
 	let db = vars.get("DB").or_else(|| vars.get("db"));
 	let ac = vars.get("AC").or_else(|| vars.get("ac"));
 	// Check if the parameters exist
-	match (ns, db, ac) {
+	let res = match (ns, db, ac) {
 		(Some(ns), Some(db), Some(ac)) => {
 			// Process the provided values
 			let ns = ns.to_raw_string();
@@ -38,7 +39,13 @@ pub async fn signup(
 			super::signup::db_access(kvs, session, ns, db, ac, vars).await
 		}
 		_ => Err(Error::InvalidSignup),
+	};
+	// Record the attempt in the audit log
+	if let Some(log) = kvs.audit_log() {
+		let res = res.as_ref().map(|_| ());
+		log.record(Category::Auth, "signup", &session.au, Origin::from(&*session), None, res);
 	}
+	res
 }
 
 pub async fn db_access(
@@ -288,6 +295,55 @@ mod tests {
 
 			assert!(res.is_err(), "Unexpected successful signup: {:?}", res);
 		}
+
+		// Test in read-only mode
+		{
+			let ds = Datastore::new("memory").await.unwrap();
+			let sess = Session::owner().with_ns("test").with_db("test");
+			ds.execute(
+				r#"
+				DEFINE ACCESS user ON DATABASE TYPE RECORD
+					SIGNUP (
+						CREATE user CONTENT {
+							name: $user,
+							pass: crypto::argon2::generate($pass)
+						}
+					)
+					DURATION FOR SESSION 2h
+				;
+				"#,
+				&sess,
+				None,
+			)
+			.await
+			.unwrap();
+			let ds = ds.with_read_only(true);
+
+			// Signup with the user
+			let mut sess = Session {
+				ns: Some("test".to_string()),
+				db: Some("test".to_string()),
+				..Default::default()
+			};
+			let mut vars: HashMap<&str, Value> = HashMap::new();
+			vars.insert("user", "user".into());
+			vars.insert("pass", "pass".into());
+			let res = db_access(
+				&ds,
+				&mut sess,
+				"test".to_string(),
+				"test".to_string(),
+				"user".to_string(),
+				vars.into(),
+			)
+			.await;
+
+			assert!(res.is_err(), "Unexpected successful signup: {:?}", res);
+			// The user was not created
+			let sess = Session::owner().with_ns("test").with_db("test");
+			let res = &mut ds.execute("SELECT * FROM user", &sess, None).await.unwrap();
+			assert_eq!(res.remove(0).result.unwrap(), Value::Array(Default::default()));
+		}
 	}
 
 	#[tokio::test]
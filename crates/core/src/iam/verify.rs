@@ -1,4 +1,5 @@
-use crate::cnf::INSECURE_FORWARD_ACCESS_ERRORS;
+use crate::cnf::{INSECURE_FORWARD_ACCESS_ERRORS, TOKEN_CLOCK_SKEW};
+use crate::dbs::audit::{Category, Origin};
 use crate::dbs::Session;
 use crate::err::Error;
 #[cfg(feature = "jwks")]
@@ -63,6 +64,8 @@ fn config(alg: Algorithm, key: &[u8]) -> Result<(DecodingKey, Validation), Error
 	// Now that the audience claim is validated by default, we could allow users to leverage this.
 	// This will most likely involve defining an audience string via "DEFINE ACCESS ... TYPE JWT".
 	val.validate_aud = false;
+	// Tolerate the configured amount of clock skew
+	val.leeway = *TOKEN_CLOCK_SKEW;
 
 	Ok((dec, val))
 }
@@ -89,7 +92,7 @@ pub async fn basic(
 	// Log the authentication type
 	trace!("Attempting basic authentication");
 	// Check if the parameters exist
-	match (ns, db) {
+	let res = match (ns, db) {
 		// DB signin
 		(Some(ns), Some(db)) => match verify_db_creds(kvs, ns, db, user, pass).await {
 			Ok(u) => {
@@ -127,10 +130,70 @@ pub async fn basic(
 			);
 			Err(Error::InvalidAuth)
 		}
+	};
+	// Record the attempt in the audit log
+	if let Some(log) = kvs.audit_log() {
+		let res = res.as_ref().map(|_| ());
+		log.record(Category::Auth, "authenticate", &session.au, Origin::from(&*session), None, res);
+	}
+	res
+}
+
+/// Authenticate a connection with the name of a client certificate,
+/// which has already been verified by the TLS listener. The name is the
+/// name of a system user, defined at the level of the specified namespace
+/// and database, so that no password is needed.
+pub async fn certificate(
+	kvs: &Datastore,
+	session: &mut Session,
+	user: &str,
+	ns: Option<&str>,
+	db: Option<&str>,
+) -> Result<(), Error> {
+	// Log the authentication type
+	trace!("Attempting client certificate authentication");
+	// Check if the parameters exist
+	let level = match (ns, db) {
+		(Some(ns), Some(db)) => Level::Database(ns.to_owned(), db.to_owned()),
+		(Some(ns), None) => Level::Namespace(ns.to_owned()),
+		(None, None) => Level::Root,
+		(None, Some(db)) => {
+			debug!("Attempted certificate authentication in database '{db}' without a namespace");
+			return Err(Error::InvalidAuth);
+		}
+	};
+	let res = match verify_certificate_user(kvs, &level, user).await {
+		Ok(u) => {
+			debug!("Authenticated with a client certificate as user '{}'", user);
+			session.exp = expiration(u.duration.session)?;
+			session.au = Arc::new((&u, level).try_into()?);
+			Ok(())
+		}
+		Err(err) => Err(err),
+	};
+	// Record the attempt in the audit log
+	if let Some(log) = kvs.audit_log() {
+		let res = res.as_ref().map(|_| ());
+		log.record(Category::Auth, "authenticate", &session.au, Origin::from(&*session), None, res);
 	}
+	res
 }
 
 pub async fn token(kvs: &Datastore, session: &mut Session, token: &str) -> Result<(), Error> {
+	let res = authenticate_token(kvs, session, token).await;
+	// Record the attempt in the audit log
+	if let Some(log) = kvs.audit_log() {
+		let res = res.as_ref().map(|_| ());
+		log.record(Category::Auth, "authenticate", &session.au, Origin::from(&*session), None, res);
+	}
+	res
+}
+
+async fn authenticate_token(
+	kvs: &Datastore,
+	session: &mut Session,
+	token: &str,
+) -> Result<(), Error> {
 	// Log the authentication type
 	trace!("Attempting token authentication");
 	// Decode the token without verifying
@@ -364,6 +427,7 @@ pub async fn token(kvs: &Datastore, session: &mut Session, token: &str) -> Resul
 			session.ns = Some(ns.to_owned());
 			session.db = Some(db.to_owned());
 			session.exp = expiration(de.duration.session)?;
+			session.ac = None;
 			session.au = Arc::new(Auth::new(Actor::new(
 				id.to_string(),
 				de.roles.iter().map(Role::try_from).collect::<Result<_, _>>()?,
@@ -466,6 +530,7 @@ pub async fn token(kvs: &Datastore, session: &mut Session, token: &str) -> Resul
 			session.tk = Some(value);
 			session.ns = Some(ns.to_owned());
 			session.exp = expiration(de.duration.session)?;
+			session.ac = None;
 			session.au = Arc::new(Auth::new(Actor::new(
 				id.to_string(),
 				de.roles.iter().map(Role::try_from).collect::<Result<_, _>>()?,
@@ -560,6 +625,7 @@ pub async fn token(kvs: &Datastore, session: &mut Session, token: &str) -> Resul
 			// Set the session
 			session.tk = Some(value);
 			session.exp = expiration(de.duration.session)?;
+			session.ac = None;
 			session.au = Arc::new(Auth::new(Actor::new(
 				id.to_string(),
 				de.roles.iter().map(Role::try_from).collect::<Result<_, _>>()?,
@@ -588,6 +654,36 @@ pub async fn verify_root_creds(
 	tx.cancel().await?;
 	// Verify the specified password for the user
 	verify_pass(pass, user.hash.as_ref())?;
+	// Ensure that the password of the user has not expired
+	if user.is_password_expired() {
+		return Err(Error::ExpiredPassword);
+	}
+	// Clone the cached user object
+	let user = (*user).clone();
+	// Return the verified user object
+	Ok(user)
+}
+
+async fn verify_certificate_user(
+	ds: &Datastore,
+	level: &Level,
+	user: &str,
+) -> Result<DefineUserStatement, Error> {
+	// Create a new readonly transaction
+	let tx = ds.transaction(Read, Optimistic).await?;
+	// Fetch the specified user from storage
+	let res = match level {
+		Level::Root => tx.get_root_user(user).await,
+		Level::Namespace(ns) => tx.get_ns_user(ns, user).await,
+		Level::Database(ns, db) => tx.get_db_user(ns, db, user).await,
+		_ => Err(Error::InvalidAuth),
+	};
+	// Ensure that the transaction is cancelled
+	tx.cancel().await?;
+	let user = res.map_err(|e| {
+		debug!("Error retrieving user for certificate authentication: {e}");
+		Error::InvalidAuth
+	})?;
 	// Clone the cached user object
 	let user = (*user).clone();
 	// Return the verified user object
@@ -611,6 +707,10 @@ pub async fn verify_ns_creds(
 	tx.cancel().await?;
 	// Verify the specified password for the user
 	verify_pass(pass, user.hash.as_ref())?;
+	// Ensure that the password of the user has not expired
+	if user.is_password_expired() {
+		return Err(Error::ExpiredPassword);
+	}
 	// Clone the cached user object
 	let user = (*user).clone();
 	// Return the verified user object
@@ -635,6 +735,10 @@ pub async fn verify_db_creds(
 	tx.cancel().await?;
 	// Verify the specified password for the user
 	verify_pass(pass, user.hash.as_ref())?;
+	// Ensure that the password of the user has not expired
+	if user.is_password_expired() {
+		return Err(Error::ExpiredPassword);
+	}
 	// Clone the cached user object
 	let user = (*user).clone();
 	// Return the verified user object
@@ -951,6 +1055,7 @@ mod tests {
 				comment: None,
 				if_not_exists: false,
 				overwrite: false,
+				changed: None,
 			};
 
 			// Use pre-parsed definition, which bypasses the existent role check during parsing.
@@ -975,6 +1080,49 @@ mod tests {
 		}
 	}
 
+	#[tokio::test]
+	async fn test_certificate() {
+		let test_levels = vec![
+			TestLevel {
+				level: "ROOT",
+				ns: None,
+				db: None,
+			},
+			TestLevel {
+				level: "NS",
+				ns: Some("test"),
+				db: None,
+			},
+			TestLevel {
+				level: "DB",
+				ns: Some("test"),
+				db: Some("test"),
+			},
+		];
+
+		for level in &test_levels {
+			let ds = Datastore::new("memory").await.unwrap();
+			let sess = Session::owner().with_ns("test").with_db("test");
+			let sql =
+				format!("DEFINE USER service ON {} PASSWORD 'pass' ROLES EDITOR", level.level);
+			ds.execute(&sql, &sess, None).await.unwrap();
+
+			// Certificate authentication with the defined user
+			let mut sess = Session::default();
+			let res = certificate(&ds, &mut sess, "service", level.ns, level.db).await;
+			assert!(res.is_ok(), "Failed to authenticate with certificate: {:?}", res);
+			assert_eq!(sess.au.id(), "service");
+			assert_eq!(sess.au.level().ns(), level.ns);
+			assert_eq!(sess.au.level().db(), level.db);
+			assert!(sess.au.has_role(Role::Editor), "Auth user expected to have Editor role");
+
+			// Certificate authentication with an undefined user
+			let mut sess = Session::default();
+			let res = certificate(&ds, &mut sess, "other", level.ns, level.db).await;
+			assert!(matches!(res, Err(Error::InvalidAuth)), "Unexpected result: {:?}", res);
+		}
+	}
+
 	#[tokio::test]
 	async fn test_token() {
 		#[derive(Debug)]
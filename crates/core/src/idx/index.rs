@@ -7,7 +7,7 @@ use crate::idx::ft::FtIndex;
 use crate::idx::trees::mtree::MTreeIndex;
 use crate::idx::IndexKeyBase;
 use crate::key;
-use crate::kvs::TransactionType;
+use crate::kvs::{Key, TransactionType};
 use crate::sql::index::{HnswParams, MTreeParams, SearchParams};
 use crate::sql::statements::DefineIndexStatement;
 use crate::sql::{Array, Index, Part, Thing, Value};
@@ -46,7 +46,7 @@ impl<'a> IndexOperation<'a> {
 	pub(crate) async fn compute(&mut self, stk: &mut Stk) -> Result<(), Error> {
 		// Index operation dispatching
 		match &self.ix.index {
-			Index::Uniq => self.index_unique().await,
+			Index::Uniq | Index::UniqNullsNotDistinct => self.index_unique().await,
 			Index::Idx => self.index_non_unique().await,
 			Index::Search(p) => self.index_full_text(stk, p).await,
 			Index::MTree(p) => self.index_mtree(stk, p).await,
@@ -54,26 +54,36 @@ impl<'a> IndexOperation<'a> {
 		}
 	}
 
-	fn get_unique_index_key(&self, v: &'a Array) -> Result<key::index::Index, Error> {
-		Ok(key::index::Index::new(
+	fn get_unique_index_key(&self, v: &'a Array) -> Result<Key, Error> {
+		let key = key::index::Index::new(
 			self.opt.ns()?,
 			self.opt.db()?,
 			&self.ix.what,
 			&self.ix.name,
 			v,
 			None,
-		))
+		);
+		// Descending indexes store their entries in inverted byte order
+		match self.ix.desc {
+			true => key.encode_desc(),
+			false => key.encode(),
+		}
 	}
 
-	fn get_non_unique_index_key(&self, v: &'a Array) -> Result<key::index::Index, Error> {
-		Ok(key::index::Index::new(
+	fn get_non_unique_index_key(&self, v: &'a Array) -> Result<Key, Error> {
+		let key = key::index::Index::new(
 			self.opt.ns()?,
 			self.opt.db()?,
 			&self.ix.what,
 			&self.ix.name,
 			v,
 			Some(&self.rid.id),
-		))
+		);
+		// Descending indexes store their entries in inverted byte order
+		match self.ix.desc {
+			true => key.encode_desc(),
+			false => key.encode(),
+		}
 	}
 
 	async fn index_unique(&mut self) -> Result<(), Error> {
@@ -96,7 +106,8 @@ impl<'a> IndexOperation<'a> {
 		if let Some(n) = self.n.take() {
 			let i = Indexable::new(n, self.ix);
 			for n in i {
-				if !n.is_all_none_or_null() {
+				// Empty values are only unique if the index requires it
+				if self.ix.index == Index::UniqNullsNotDistinct || !n.is_all_none_or_null() {
 					let key = self.get_unique_index_key(&n)?;
 					if txn.putc(key, self.rid, None).await.is_err() {
 						let key = self.get_unique_index_key(&n)?;
@@ -1,5 +1,6 @@
 use crate::ctx::Context;
 use crate::dbs::{Iterable, Options};
+use crate::doc::encrypt::decrypt_record;
 use crate::doc::CursorDoc;
 use crate::err::Error;
 use crate::idx::docids::{DocId, DocIds};
@@ -185,6 +186,7 @@ impl CheckerCacheEntry {
 			let rid = Arc::new(rid);
 			let txn = ctx.tx();
 			let val = Iterable::fetch_thing(&txn, opt, &rid).await?;
+			let val = decrypt_record(ctx, opt, &rid, val).await?;
 			if !val.is_none_or_null() {
 				let (value, truthy) = {
 					let mut cursor_doc = CursorDoc {
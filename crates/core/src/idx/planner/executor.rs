@@ -70,6 +70,8 @@ pub(super) struct InnerQueryExecutor {
 	mr_entries: HashMap<MatchRef, FtEntry>,
 	exp_entries: HashMap<Arc<Expression>, FtEntry>,
 	it_entries: Vec<IteratorEntry>,
+	/// The iterators whose records are not fetched
+	it_keys_only: HashSet<IteratorRef>,
 	mt_entries: HashMap<Arc<Expression>, MtEntry>,
 	hnsw_entries: HashMap<Arc<Expression>, HnswEntry>,
 	knn_bruteforce_entries: HashMap<Arc<Expression>, KnnBruteForceEntry>,
@@ -242,6 +244,7 @@ impl InnerQueryExecutor {
 			mr_entries,
 			exp_entries,
 			it_entries: Vec::new(),
+			it_keys_only: HashSet::new(),
 			mt_entries,
 			hnsw_entries,
 			knn_bruteforce_entries,
@@ -253,6 +256,11 @@ impl InnerQueryExecutor {
 		self.it_entries.push(it_entry);
 		ir as IteratorRef
 	}
+
+	/// Only the record ids are collected by this iterator
+	pub(super) fn set_keys_only(&mut self, ir: IteratorRef) {
+		self.it_keys_only.insert(ir);
+	}
 }
 
 impl QueryExecutor {
@@ -300,6 +308,12 @@ impl QueryExecutor {
 		!self.0.knn_bruteforce_entries.is_empty()
 	}
 
+	/// Returns `true` if the records of this iterator are not fetched,
+	/// because the index keys already answer the whole condition.
+	pub(crate) fn is_keys_only(&self, ir: IteratorRef) -> bool {
+		self.0.it_keys_only.contains(&ir)
+	}
+
 	/// Returns `true` if the expression is matching the current iterator.
 	pub(crate) fn is_iterator_expression(&self, ir: IteratorRef, exp: &Expression) -> bool {
 		match self.0.it_entries.get(ir) {
@@ -351,7 +365,9 @@ impl QueryExecutor {
 		let ixr = io.ix_ref();
 		match ixr.index {
 			Index::Idx => Ok(self.new_index_iterator(opt, irf, ixr, io.clone()).await?),
-			Index::Uniq => Ok(self.new_unique_index_iterator(opt, irf, ixr, io.clone()).await?),
+			Index::Uniq | Index::UniqNullsNotDistinct => {
+				Ok(self.new_unique_index_iterator(opt, irf, ixr, io.clone()).await?)
+			}
 			Index::Search {
 				..
 			} => self.new_search_index_iterator(irf, io.clone()).await,
@@ -702,7 +718,7 @@ impl QueryExecutor {
 					&IteratorRange::new_ref(ValueType::None, from, to),
 				)?));
 			}
-			Index::Uniq => {
+			Index::Uniq | Index::UniqNullsNotDistinct => {
 				let ranges = Self::get_ranges_variants(from, to);
 				if let Some(ranges) = ranges {
 					if ranges.len() == 1 {
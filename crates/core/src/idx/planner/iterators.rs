@@ -366,6 +366,14 @@ impl IndexRangeThingIterator {
 		db: &str,
 		ix: &DefineIndexStatement,
 	) -> Self {
+		if ix.desc {
+			let beg = Index::prefix_desc_beg(ns, db, &ix.what, &ix.name);
+			let end = Index::prefix_desc_end(ns, db, &ix.what, &ix.name);
+			return Self {
+				irf,
+				r: RangeScan::new(beg, true, end, false),
+			};
+		}
 		let full_range = RangeValue {
 			value: Value::None,
 			inclusive: true,
@@ -684,6 +692,15 @@ impl UniqueRangeThingIterator {
 		db: &str,
 		ix: &DefineIndexStatement,
 	) -> Self {
+		if ix.desc {
+			let beg = Index::prefix_desc_beg(ns, db, &ix.what, &ix.name);
+			let end = Index::prefix_desc_end(ns, db, &ix.what, &ix.name);
+			return Self {
+				irf,
+				r: RangeScan::new(beg, true, end, false),
+				done: false,
+			};
+		}
 		let value = RangeValue {
 			value: Value::None,
 			inclusive: true,
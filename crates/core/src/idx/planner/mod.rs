@@ -4,6 +4,7 @@ pub(crate) mod iterators;
 pub(in crate::idx) mod knn;
 pub(crate) mod plan;
 pub(in crate::idx) mod rewriter;
+pub(crate) mod statistics;
 pub(in crate::idx) mod tree;
 
 use crate::ctx::Context;
@@ -12,10 +13,10 @@ use crate::err::Error;
 use crate::idx::planner::executor::{InnerQueryExecutor, IteratorEntry, QueryExecutor};
 use crate::idx::planner::iterators::IteratorRef;
 use crate::idx::planner::knn::KnnBruteForceResults;
-use crate::idx::planner::plan::{Plan, PlanBuilder};
+use crate::idx::planner::plan::{IndexOperator, IndexOption, Plan, PlanBuilder};
 use crate::idx::planner::tree::Tree;
 use crate::sql::with::With;
-use crate::sql::{order::Ordering, Cond, Fields, Groups, Table};
+use crate::sql::{order::Ordering, Cond, Expression, Fields, Groups, Kind, Operator, Table, Value};
 use reblessive::tree::Stk;
 use std::collections::HashMap;
 use std::sync::atomic::{self, AtomicU8};
@@ -67,6 +68,53 @@ impl<'a> StatementContext<'a> {
 		if self.cond.is_some() {
 			return Ok(false);
 		}
+		self.is_count_only(tb).await
+	}
+
+	/// Check if the records matched by an index can be counted from the index
+	/// keys alone. This is only the case when the statement counts records,
+	/// and the index keys match the whole condition exactly.
+	async fn is_index_keys_only(
+		&self,
+		tb: &str,
+		exp: &Expression,
+		io: &IndexOption,
+	) -> Result<bool, Error> {
+		match self.fields {
+			Some(fields) if fields.is_count_all_only() => {}
+			_ => return Ok(false),
+		}
+		// The index must match the whole condition
+		match self.cond {
+			Some(Cond(Value::Expression(e))) if e.as_ref() == exp => {}
+			_ => return Ok(false),
+		}
+		// Only an equality is answered exactly by the index keys
+		if !matches!(exp.operator(), Operator::Equal | Operator::Exact) {
+			return Ok(false);
+		}
+		match io.op() {
+			IndexOperator::Equality(v) if !v.iter().any(|v| v.is_array()) => {}
+			_ => return Ok(false),
+		}
+		// Arrays are indexed as one entry per value, so the field must be a scalar
+		if let Some(id) = io.id_ref() {
+			match self.ctx.tx().get_tb_field(self.ns, self.db, tb, &id.to_string()).await {
+				Ok(fd) if fd.kind.as_ref().is_some_and(Kind::is_scalar) => {}
+				Ok(_) => return Ok(false),
+				Err(Error::FdNotFound {
+					..
+				}) => return Ok(false),
+				Err(e) => return Err(e),
+			}
+		} else {
+			return Ok(false);
+		}
+		self.is_count_only(tb).await
+	}
+
+	/// Check that nothing but the number of records is required
+	async fn is_count_only(&self, tb: &str) -> Result<bool, Error> {
 		if let Some(g) = self.group {
 			if !g.is_empty() {
 				return Ok(false);
@@ -177,7 +225,14 @@ impl QueryPlanner {
 					self.requires_distinct = true;
 				}
 				let is_order = exp.is_none();
+				let keys_only = match &exp {
+					Some(e) => ctx.is_index_keys_only(&t, e, &io).await?,
+					None => false,
+				};
 				let ir = exe.add_iterator(IteratorEntry::Single(exp, io));
+				if keys_only {
+					exe.set_keys_only(ir);
+				}
 				self.add(t.clone(), Some(ir), exe, it);
 				if is_order {
 					self.orders.push(ir);
@@ -1,5 +1,6 @@
 use crate::err::Error;
 use crate::idx::ft::MatchRef;
+use crate::idx::planner::statistics::TableStatistics;
 use crate::idx::planner::tree::{
 	CompoundIndexes, GroupRef, IdiomCol, IdiomPosition, IndexReference, Node,
 };
@@ -10,6 +11,7 @@ use crate::sql::{Operator, Value};
 use std::collections::hash_map::Entry;
 use std::collections::{BTreeMap, HashMap, HashSet};
 use std::hash::Hash;
+use std::ops::Bound;
 use std::sync::Arc;
 
 /// The `PlanBuilder` struct represents a builder for constructing query plans.
@@ -83,8 +85,9 @@ impl PlanBuilder {
 				}
 			}
 
-			// Otherwise, we try to find the most interesting (todo: TBD) single index option
-			if let Some((e, i)) = b.non_range_indexes.pop() {
+			// Otherwise, we try to find the most selective single index option
+			if let Some((e, i)) = Self::take_single_index(&mut b.non_range_indexes, ctx, tb).await?
+			{
 				return Ok(Plan::SingleIndex(Some(e), i));
 			}
 			// If there is an order option
@@ -118,6 +121,31 @@ impl PlanBuilder {
 		Ok(Plan::TableIterator(reason, keys_only))
 	}
 
+	/// Take the single index option which is estimated to match the fewest
+	/// records. If the table has not been analyzed, the last option is taken.
+	async fn take_single_index(
+		ios: &mut Vec<(Arc<Expression>, IndexOption)>,
+		ctx: &StatementContext<'_>,
+		tb: &str,
+	) -> Result<Option<(Arc<Expression>, IndexOption)>, Error> {
+		if ios.len() > 1 {
+			if let Some(st) = TableStatistics::get(&ctx.ctx.tx(), ctx.ns, ctx.db, tb).await? {
+				let mut best: Option<(usize, f64)> = None;
+				for (i, (_, io)) in ios.iter().enumerate() {
+					if let Some(rows) = st.estimate(io) {
+						if best.map_or(true, |(_, r)| rows < r) {
+							best = Some((i, rows));
+						}
+					}
+				}
+				if let Some((i, _)) = best {
+					return Ok(Some(ios.remove(i)));
+				}
+			}
+		}
+		Ok(ios.pop())
+	}
+
 	/// Check if we have an explicit list of index that we should use
 	fn filter_index_option(&self, io: Option<&IndexOption>) -> Option<IndexOption> {
 		if let Some(io) = io {
@@ -188,7 +216,7 @@ impl PlanBuilder {
 	}
 
 	fn add_index_option(&mut self, group_ref: GroupRef, exp: Arc<Expression>, io: IndexOption) {
-		if let IndexOperator::RangePart(_, _) = io.op() {
+		if let IndexOperator::RangePart(_, _) | IndexOperator::Range(_) = io.op() {
 			let level = self.groups.entry(group_ref).or_default();
 			match level.ranges.entry(io.ixr.clone()) {
 				Entry::Occupied(mut e) => {
@@ -234,6 +262,7 @@ pub(super) enum IndexOperator {
 	Union(Arc<Value>),
 	Join(Vec<IndexOption>),
 	RangePart(Operator, Arc<Value>),
+	Range(Arc<Value>),
 	Matches(String, Option<MatchRef>),
 	Knn(Arc<Vec<Number>>, u32),
 	Ann(Arc<Vec<Number>>, u32, u32),
@@ -316,6 +345,10 @@ impl IndexOption {
 				e.insert("operator", Value::from(op.to_string()));
 				e.insert("value", v.as_ref().to_owned());
 			}
+			IndexOperator::Range(v) => {
+				e.insert("operator", Value::from(Operator::Inside.to_string()));
+				e.insert("value", v.as_ref().to_owned());
+			}
 			IndexOperator::Knn(a, k) => {
 				let op = Value::from(Operator::Knn(*k, None).to_string());
 				let val = Value::Array(Array::from(a.as_ref().clone()));
@@ -469,15 +502,34 @@ impl UnionRangeQueryBuilder {
 	}
 
 	fn add(&mut self, exp: Arc<Expression>, io: IndexOption) -> bool {
-		if let IndexOperator::RangePart(op, val) = io.op() {
-			match op {
-				Operator::LessThan => self.to.set_to(val),
-				Operator::LessThanOrEqual => self.to.set_to_inclusive(val),
-				Operator::MoreThan => self.from.set_from(val),
-				Operator::MoreThanOrEqual => self.from.set_from_inclusive(val),
-				_ => return false,
+		match io.op() {
+			IndexOperator::RangePart(op, val) => {
+				match op {
+					Operator::LessThan => self.to.set_to(val),
+					Operator::LessThanOrEqual => self.to.set_to_inclusive(val),
+					Operator::MoreThan => self.from.set_from(val),
+					Operator::MoreThanOrEqual => self.from.set_from_inclusive(val),
+					_ => return false,
+				}
+				self.exps.insert(exp);
+			}
+			IndexOperator::Range(val) => {
+				let Value::Range(r) = val.as_ref() else {
+					return false;
+				};
+				match &r.beg {
+					Bound::Included(v) => self.from.set_from_inclusive(v),
+					Bound::Excluded(v) => self.from.set_from(v),
+					Bound::Unbounded => {}
+				}
+				match &r.end {
+					Bound::Included(v) => self.to.set_to_inclusive(v),
+					Bound::Excluded(v) => self.to.set_to(v),
+					Bound::Unbounded => {}
+				}
+				self.exps.insert(exp);
 			}
-			self.exps.insert(exp);
+			_ => {}
 		}
 		true
 	}
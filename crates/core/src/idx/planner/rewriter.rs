@@ -30,6 +30,7 @@ impl<'a> KnnConditionRewriter<'a> {
 			| Value::Block(_)
 			| Value::Future(_)
 			| Value::Subquery(_)
+			| Value::Qualified(_)
 			| Value::Query(_) => None,
 			Value::Function(f) => self.eval_value_function(f),
 			Value::Expression(e) => self.eval_value_expression(e),
@@ -156,7 +157,7 @@ impl<'a> KnnConditionRewriter<'a> {
 			| Part::Field(_)
 			| Part::Index(_)
 			| Part::Optional
-			| Part::Recurse(_, None)
+			| Part::Recurse(_, None, _)
 			| Part::Doc
 			| Part::RepeatRecurse => Some(p.clone()),
 			Part::Where(v) => self.eval_value(v).map(Part::Where),
@@ -165,8 +166,8 @@ impl<'a> KnnConditionRewriter<'a> {
 			Part::Start(v) => self.eval_value(v).map(Part::Start),
 			Part::Method(n, p) => self.eval_values(p).map(|v| Part::Method(n.clone(), v)),
 			Part::Destructure(p) => self.eval_destructure_parts(p).map(Part::Destructure),
-			Part::Recurse(r, Some(v)) => {
-				self.eval_idiom(v).map(|v| Part::Recurse(r.to_owned(), Some(v)))
+			Part::Recurse(r, Some(v), i) => {
+				self.eval_idiom(v).map(|v| Part::Recurse(r.to_owned(), Some(v), i.to_owned()))
 			}
 		}
 	}
@@ -0,0 +1,157 @@
+use crate::cnf::NORMAL_FETCH_SIZE;
+use crate::ctx::Context;
+use crate::dbs::Options;
+use crate::err::Error;
+use crate::idx::planner::plan::{IndexOperator, IndexOption};
+use crate::key::thing;
+use crate::kvs::Transaction;
+use crate::sql::index::Index;
+use crate::sql::{Array, Object, Value};
+use derive::Store;
+use revision::revisioned;
+use serde::{Deserialize, Serialize};
+use std::collections::BTreeMap;
+
+/// The statistics of a table, collected by the `ANALYZE TABLE` statement
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Default, Eq, PartialEq, Serialize, Deserialize, Store)]
+#[non_exhaustive]
+pub(crate) struct TableStatistics {
+	/// The number of records in the table
+	pub(crate) count: u64,
+	/// The statistics of each unique and non-unique index
+	pub(crate) indexes: BTreeMap<String, IndexStatistics>,
+}
+
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Default, Eq, PartialEq, Serialize, Deserialize)]
+#[non_exhaustive]
+pub(crate) struct IndexStatistics {
+	/// The number of entries in the index
+	pub(crate) entries: u64,
+	/// The number of distinct values in the index
+	pub(crate) distinct: u64,
+}
+
+impl IndexStatistics {
+	/// The average number of records matching each value
+	fn rows_per_value(&self) -> f64 {
+		match self.distinct {
+			0 => 0.0,
+			d => self.entries as f64 / d as f64,
+		}
+	}
+}
+
+impl TableStatistics {
+	/// Scan the records and the indexes of a table to collect its statistics
+	pub(crate) async fn analyze(ctx: &Context, opt: &Options, tb: &str) -> Result<Self, Error> {
+		let ns = opt.ns()?;
+		let db = opt.db()?;
+		let txn = ctx.tx();
+		let mut stats = Self::default();
+		// Count the records of the table
+		let beg = thing::prefix(ns, db, tb);
+		let end = thing::suffix(ns, db, tb);
+		let mut next = Some(beg..end);
+		while let Some(rng) = next {
+			let batch = txn.batch(rng, *NORMAL_FETCH_SIZE, false, None).await?;
+			next = batch.next;
+			stats.count += batch.values.len() as u64;
+		}
+		// Count the entries and distinct values of each index
+		for ix in txn.all_tb_indexes(ns, db, tb).await?.iter() {
+			if !matches!(ix.index, Index::Idx | Index::Uniq | Index::UniqNullsNotDistinct) {
+				continue;
+			}
+			let mut st = IndexStatistics::default();
+			let (beg, end) = match ix.desc {
+				true => (
+					crate::key::index::Index::prefix_desc_beg(ns, db, tb, &ix.name),
+					crate::key::index::Index::prefix_desc_end(ns, db, tb, &ix.name),
+				),
+				false => (
+					crate::key::index::Index::prefix_beg(ns, db, tb, &ix.name),
+					crate::key::index::Index::prefix_end(ns, db, tb, &ix.name),
+				),
+			};
+			let mut next = Some(beg..end);
+			let mut last: Option<Array> = None;
+			while let Some(rng) = next {
+				let batch = txn.batch(rng, *NORMAL_FETCH_SIZE, false, None).await?;
+				next = batch.next;
+				for (k, _) in batch.values.iter() {
+					// Descending entries are inverted back before they are decoded
+					let k = match ix.desc {
+						true => crate::key::index::Index::invert(ns, db, tb, &ix.name, k.clone()),
+						false => k.clone(),
+					};
+					let key = crate::key::index::Index::decode(&k)?;
+					st.entries += 1;
+					// The entries are ordered by value, so equal values are adjacent
+					if last.as_ref() != Some(key.fd.as_ref()) {
+						st.distinct += 1;
+						last = Some(key.fd.into_owned());
+					}
+				}
+			}
+			stats.indexes.insert(ix.name.to_raw(), st);
+		}
+		Ok(stats)
+	}
+
+	/// Retrieve the statistics of a table, if it has been analyzed
+	pub(crate) async fn get(
+		txn: &Transaction,
+		ns: &str,
+		db: &str,
+		tb: &str,
+	) -> Result<Option<Self>, Error> {
+		let key = crate::key::table::st::new(ns, db, tb);
+		Ok(txn.get(key, None).await?.map(Self::from))
+	}
+
+	/// Store the statistics of a table
+	pub(crate) async fn set(
+		&self,
+		txn: &Transaction,
+		ns: &str,
+		db: &str,
+		tb: &str,
+	) -> Result<(), Error> {
+		let key = crate::key::table::st::new(ns, db, tb);
+		txn.set(key, self.clone(), None).await
+	}
+
+	/// The estimated number of records matched by an index option
+	pub(super) fn estimate(&self, io: &IndexOption) -> Option<f64> {
+		let ix = self.indexes.get(io.ix_ref().name.as_str())?;
+		match io.op() {
+			IndexOperator::Equality(_) => Some(ix.rows_per_value()),
+			IndexOperator::Union(v) => match v.as_ref() {
+				Value::Array(a) => Some(ix.rows_per_value() * a.len() as f64),
+				_ => None,
+			},
+			_ => None,
+		}
+	}
+}
+
+impl From<TableStatistics> for Value {
+	fn from(st: TableStatistics) -> Self {
+		let mut indexes = Object::default();
+		for (name, ix) in st.indexes {
+			indexes.insert(
+				name,
+				Value::from(map! {
+					"distinct".to_string() => ix.distinct.into(),
+					"entries".to_string() => ix.entries.into(),
+				}),
+			);
+		}
+		Value::from(map! {
+			"count".to_string() => st.count.into(),
+			"indexes".to_string() => indexes.into(),
+		})
+	}
+}
@@ -144,18 +144,17 @@ impl<'a> TreeBuilder<'a> {
 
 	async fn eval_order(&mut self) -> Result<(), Error> {
 		if let Some(o) = self.first_order {
-			if o.direction {
-				if let Node::IndexedField(id, irf) = self.resolve_idiom(&o.value).await? {
-					for (ixr, id_col) in &irf {
-						if *id_col == 0 {
-							self.index_map.order_limit = Some(IndexOption::new(
-								ixr.clone(),
-								Some(id),
-								IdiomPosition::None,
-								IndexOperator::Order,
-							));
-							break;
-						}
+			if let Node::IndexedField(id, irf) = self.resolve_idiom(&o.value).await? {
+				for (ixr, id_col) in &irf {
+					// A descending index is scanned forwards for a descending order
+					if *id_col == 0 && ixr.desc != o.direction {
+						self.index_map.order_limit = Some(IndexOption::new(
+							ixr.clone(),
+							Some(id),
+							IdiomPosition::None,
+							IndexOperator::Order,
+						));
+						break;
 					}
 				}
 			}
@@ -192,6 +191,7 @@ impl<'a> TreeBuilder<'a> {
 			| Value::Geometry(_)
 			| Value::Datetime(_)
 			| Value::Param(_)
+			| Value::Range(_)
 			| Value::Null
 			| Value::None
 			| Value::Function(_) => {
@@ -493,8 +493,12 @@ impl<'a> TreeBuilder<'a> {
 		let mut res = None;
 		for (ixr, col) in irs.iter() {
 			let op = match &ixr.index {
+				// Descending indexes are only used to order the records
+				_ if ixr.desc => None,
 				Index::Idx => self.eval_index_operator(ixr, op, n, p, *col),
-				Index::Uniq => self.eval_index_operator(ixr, op, n, p, *col),
+				Index::Uniq | Index::UniqNullsNotDistinct => {
+					self.eval_index_operator(ixr, op, n, p, *col)
+				}
 				Index::Search {
 					..
 				} if *col == 0 => Self::eval_matches_operator(op, n),
@@ -514,9 +518,11 @@ impl<'a> TreeBuilder<'a> {
 	}
 
 	fn lookup_join_index_ref(&self, irs: &LocalIndexRefs) -> Option<(IndexReference, IdiomCol)> {
-		for (ixr, id_col) in irs.iter().filter(|(_, id_col)| 0.eq(id_col)) {
+		for (ixr, id_col) in irs.iter().filter(|(ixr, id_col)| 0.eq(id_col) && !ixr.desc) {
 			match &ixr.index {
-				Index::Idx | Index::Uniq => return Some((ixr.clone(), *id_col)),
+				Index::Idx | Index::Uniq | Index::UniqNullsNotDistinct => {
+					return Some((ixr.clone(), *id_col))
+				}
 				_ => {}
 			};
 		}
@@ -615,8 +621,12 @@ impl<'a> TreeBuilder<'a> {
 					IdiomPosition::Left,
 				) => {
 					if col == 0 {
-						if let Value::Array(_) = v.as_ref() {
-							return Some(IndexOperator::Union(v));
+						match v.as_ref() {
+							Value::Array(_) => return Some(IndexOperator::Union(v)),
+							Value::Range(_) if matches!(op, Operator::Inside) => {
+								return Some(IndexOperator::Range(v))
+							}
+							_ => {}
 						}
 					}
 				}
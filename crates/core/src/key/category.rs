@@ -12,6 +12,14 @@ pub(crate) trait Categorise {
 pub enum Category {
 	/// crate::key::storage::version         /sv
 	Version,
+	/// crate::key::rekey                    !rk
+	Rekey,
+	/// crate::key::compression              !cp
+	Compression,
+	/// crate::key::datakey                  !dk
+	DataKey,
+	/// crate::key::reencrypt                !re
+	Reencrypt,
 	/// crate::key::root::all                /
 	Root,
 	/// crate::key::root::access::ac         /!ac{ac}
@@ -74,8 +82,14 @@ pub enum Category {
 	DatabaseFunction,
 	/// crate::key::database::ml             /*{ns}*{db}!ml{ml}{vn}
 	DatabaseModel,
+	/// crate::key::database::mv             /*{ns}*{db}!mv
+	DatabaseMove,
 	/// crate::key::database::pa             /*{ns}*{db}!pa{pa}
 	DatabaseParameter,
+	/// crate::key::database::sq             /*{ns}*{db}!sq{sq}
+	DatabaseSequence,
+	/// crate::key::database::sv             /*{ns}*{db}!sv{sq}
+	DatabaseSequenceValue,
 	/// crate::key::database::tb             /*{ns}*{db}!tb{tb}
 	DatabaseTable,
 	/// crate::key::database::ts             /*{ns}*{db}!ts{ts}
@@ -86,6 +100,10 @@ pub enum Category {
 	DatabaseVersionstamp,
 	/// crate::key::database::cg             /*{ns}*{db}!cg{ty}
 	DatabaseConfig,
+	/// crate::key::database::wd             /*{ns}*{db}!wd{id}
+	DatabaseWebhookDeadLetter,
+	/// crate::key::database::wh             /*{ns}*{db}!wh{ts}{id}
+	DatabaseWebhook,
 	///
 	/// ------------------------------
 	///
@@ -93,14 +111,26 @@ pub enum Category {
 	TableRoot,
 	/// crate::key::table::ev                /*{ns}*{db}*{tb}!ev{ev}
 	TableEvent,
+	/// crate::key::table::es                /*{ns}*{db}*{tb}!es{ev}
+	TableEventSchedule,
 	/// crate::key::table::fd                /*{ns}*{db}*{tb}!fd{fd}
 	TableField,
+	/// crate::key::table::fm                /*{ns}*{db}*{tb}!fm{fd}
+	TableFieldMigration,
 	/// crate::key::table::ft                /*{ns}*{db}*{tb}!ft{ft}
 	TableView, // (ft = foreign table = view)
 	/// crate::key::table::ix                /*{ns}*{db}*{tb}!ix{ix}
 	IndexDefinition,
 	/// crate::key::table::lq                /*{ns}*{db}*{tb}!lq{lq}
 	TableLiveQuery,
+	/// crate::key::table::mv                /*{ns}*{db}*{tb}!mv
+	TableMove,
+	/// crate::key::table::st                /*{ns}*{db}*{tb}!st
+	TableStatistics,
+	/// crate::key::table::ex                /*{ns}*{db}*{tb}!ex{ts}{id}
+	TableExpiry,
+	/// crate::key::table::er                /*{ns}*{db}*{tb}!er{id}
+	TableExpiryRecord,
 	///
 	/// ------------------------------
 	///
@@ -157,12 +187,21 @@ pub enum Category {
 	///
 	/// crate::key::graph                    /*{ns}*{db}*{tb}~{id}{eg}{fk}
 	Graph,
+	///
+	/// ------------------------------
+	///
+	/// crate::key::reference                /*{ns}*{db}*{tb}&{id}{ft}{fk}{ff}
+	Reference,
 }
 
 impl Display for Category {
 	fn fmt(&self, f: &mut Formatter<'_>) -> std::fmt::Result {
 		let name = match self {
 			Self::Version => "StorageVersion",
+			Self::Rekey => "StorageRekey",
+			Self::Compression => "StorageCompression",
+			Self::DataKey => "StorageDataKey",
+			Self::Reencrypt => "StorageReencrypt",
 			Self::Root => "Root",
 			Self::Access => "Access",
 			Self::AccessRoot => "AccessRoot",
@@ -187,19 +226,30 @@ impl Display for Category {
 			Self::DatabaseAnalyzer => "DatabaseAnalyzer",
 			Self::DatabaseFunction => "DatabaseFunction",
 			Self::DatabaseModel => "DatabaseModel",
+			Self::DatabaseMove => "DatabaseMove",
 			Self::DatabaseParameter => "DatabaseParameter",
+			Self::DatabaseSequence => "DatabaseSequence",
+			Self::DatabaseSequenceValue => "DatabaseSequenceValue",
 			Self::DatabaseTable => "DatabaseTable",
 			Self::DatabaseTableIdentifier => "DatabaseTableIdentifier",
 			Self::DatabaseTimestamp => "DatabaseTimestamp",
 			Self::DatabaseUser => "DatabaseUser",
 			Self::DatabaseVersionstamp => "DatabaseVersionstamp",
 			Self::DatabaseConfig => "DatabaseConfig",
+			Self::DatabaseWebhookDeadLetter => "DatabaseWebhookDeadLetter",
+			Self::DatabaseWebhook => "DatabaseWebhook",
 			Self::TableRoot => "TableRoot",
 			Self::TableEvent => "TableEvent",
+			Self::TableEventSchedule => "TableEventSchedule",
 			Self::TableField => "TableField",
+			Self::TableFieldMigration => "TableFieldMigration",
 			Self::TableView => "TableView",
 			Self::IndexDefinition => "IndexDefinition",
 			Self::TableLiveQuery => "TableLiveQuery",
+			Self::TableMove => "TableMove",
+			Self::TableStatistics => "TableStatistics",
+			Self::TableExpiry => "TableExpiry",
+			Self::TableExpiryRecord => "TableExpiryRecord",
 			Self::IndexRoot => "IndexRoot",
 			Self::IndexTermDocList => "IndexTermDocList",
 			Self::IndexBTreeNode => "IndexBTreeNode",
@@ -222,6 +272,7 @@ impl Display for Category {
 			Self::ChangeFeed => "ChangeFeed",
 			Self::Thing => "Thing",
 			Self::Graph => "Graph",
+			Self::Reference => "Reference",
 		};
 		write!(f, "{}", name)
 	}
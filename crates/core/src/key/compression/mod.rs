@@ -0,0 +1,54 @@
+//! Marks a datastore which stores compressed values
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct Compression {
+	__: u8,
+	_a: u8,
+	_b: u8,
+}
+
+pub fn new() -> Compression {
+	Compression::new()
+}
+
+impl Categorise for Compression {
+	fn categorise(&self) -> Category {
+		Category::Compression
+	}
+}
+
+impl Compression {
+	pub fn new() -> Self {
+		Self {
+			__: b'!',
+			_a: b'c',
+			_b: b'p',
+		}
+	}
+}
+
+impl Default for Compression {
+	fn default() -> Self {
+		Self::new()
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		#[rustfmt::skip]
+		let val = Compression::new();
+		let enc = Compression::encode(&val).unwrap();
+		assert_eq!(enc, b"!cp");
+
+		let dec = Compression::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+	}
+}
@@ -20,6 +20,20 @@ pub fn new<'a>(ns: &'a str, db: &'a str, ac: &'a str) -> Access<'a> {
 	Access::new(ns, db, ac)
 }
 
+#[allow(unused)]
+pub fn prefix(ns: &str, db: &str, ac: &str) -> Vec<u8> {
+	let mut k = new(ns, db, ac).encode().unwrap();
+	k.extend_from_slice(&[0x00]);
+	k
+}
+
+#[allow(unused)]
+pub fn suffix(ns: &str, db: &str, ac: &str) -> Vec<u8> {
+	let mut k = new(ns, db, ac).encode().unwrap();
+	k.extend_from_slice(&[0xff]);
+	k
+}
+
 impl Categorise for Access<'_> {
 	fn categorise(&self) -> Category {
 		Category::DatabaseAccessGrant
@@ -57,4 +71,13 @@ mod tests {
 		let dec = Access::decode(&enc).unwrap();
 		assert_eq!(val, dec);
 	}
+
+	#[test]
+	fn range() {
+		use super::*;
+		let beg = prefix("testns", "testdb", "testac");
+		assert_eq!(beg, b"/*testns\0*testdb\0&testac\0\x00");
+		let end = suffix("testns", "testdb", "testac");
+		assert_eq!(end, b"/*testns\0*testdb\0&testac\0\xff");
+	}
 }
@@ -18,6 +18,46 @@ pub fn new<'a>(ns: &'a str, db: &'a str) -> All<'a> {
 	All::new(ns, db)
 }
 
+pub fn prefix(ns: &str, db: &str) -> Vec<u8> {
+	let mut k = new(ns, db).encode().unwrap();
+	k.extend_from_slice(&[0x00]);
+	k
+}
+
+pub fn suffix(ns: &str, db: &str) -> Vec<u8> {
+	let mut k = new(ns, db).encode().unwrap();
+	k.extend_from_slice(&[0xff]);
+	k
+}
+
+/// Returns the prefix for the definitions in a database
+pub fn defprefix(ns: &str, db: &str) -> Vec<u8> {
+	let mut k = new(ns, db).encode().unwrap();
+	k.extend_from_slice(b"!\x00");
+	k
+}
+
+/// Returns the suffix for the definitions in a database
+pub fn defsuffix(ns: &str, db: &str) -> Vec<u8> {
+	let mut k = new(ns, db).encode().unwrap();
+	k.extend_from_slice(b"!\xff");
+	k
+}
+
+/// Returns the prefix for the access grants in a database
+pub fn acprefix(ns: &str, db: &str) -> Vec<u8> {
+	let mut k = new(ns, db).encode().unwrap();
+	k.extend_from_slice(b"&\x00");
+	k
+}
+
+/// Returns the suffix for the access grants in a database
+pub fn acsuffix(ns: &str, db: &str) -> Vec<u8> {
+	let mut k = new(ns, db).encode().unwrap();
+	k.extend_from_slice(b"&\xff");
+	k
+}
+
 impl Categorise for All<'_> {
 	fn categorise(&self) -> Category {
 		Category::DatabaseRoot
@@ -52,4 +92,21 @@ mod tests {
 		let dec = All::decode(&enc).unwrap();
 		assert_eq!(val, dec);
 	}
+
+	#[test]
+	fn range() {
+		use super::*;
+		let beg = prefix("testns", "testdb");
+		assert_eq!(beg, b"/*testns\0*testdb\0\x00");
+		let end = suffix("testns", "testdb");
+		assert_eq!(end, b"/*testns\0*testdb\0\xff");
+		let beg = defprefix("testns", "testdb");
+		assert_eq!(beg, b"/*testns\0*testdb\0!\x00");
+		let end = defsuffix("testns", "testdb");
+		assert_eq!(end, b"/*testns\0*testdb\0!\xff");
+		let beg = acprefix("testns", "testdb");
+		assert_eq!(beg, b"/*testns\0*testdb\0&\x00");
+		let end = acsuffix("testns", "testdb");
+		assert_eq!(end, b"/*testns\0*testdb\0&\xff");
+	}
 }
@@ -5,9 +5,14 @@ pub mod az;
 pub mod cg;
 pub mod fc;
 pub mod ml;
+pub mod mv;
 pub mod pa;
+pub mod sq;
+pub mod sv;
 pub mod tb;
 pub mod ti;
 pub mod ts;
 pub mod us;
 pub mod vs;
+pub mod wd;
+pub mod wh;
@@ -0,0 +1,61 @@
+//! Stores the progress of the keys which are moved to a renamed database
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct Mv<'a> {
+	__: u8,
+	_a: u8,
+	pub ns: &'a str,
+	_b: u8,
+	pub db: &'a str,
+	_c: u8,
+	_d: u8,
+	_e: u8,
+}
+
+pub fn new<'a>(ns: &'a str, db: &'a str) -> Mv<'a> {
+	Mv::new(ns, db)
+}
+
+impl Categorise for Mv<'_> {
+	fn categorise(&self) -> Category {
+		Category::DatabaseMove
+	}
+}
+
+impl<'a> Mv<'a> {
+	pub fn new(ns: &'a str, db: &'a str) -> Self {
+		Self {
+			__: b'/',
+			_a: b'*',
+			ns,
+			_b: b'*',
+			db,
+			_c: b'!',
+			_d: b'm',
+			_e: b'v',
+		}
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		#[rustfmt::skip]
+		let val = Mv::new(
+			"testns",
+			"testdb",
+		);
+		let enc = Mv::encode(&val).unwrap();
+		assert_eq!(enc, b"/*testns\0*testdb\0!mv");
+
+		let dec = Mv::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+	}
+}
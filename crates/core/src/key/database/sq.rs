@@ -0,0 +1,76 @@
+//! Stores a DEFINE SEQUENCE definition
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct Sq<'a> {
+	__: u8,
+	_a: u8,
+	pub ns: &'a str,
+	_b: u8,
+	pub db: &'a str,
+	_c: u8,
+	_d: u8,
+	_e: u8,
+	pub sq: &'a str,
+}
+
+pub fn new<'a>(ns: &'a str, db: &'a str, sq: &'a str) -> Sq<'a> {
+	Sq::new(ns, db, sq)
+}
+
+pub fn prefix(ns: &str, db: &str) -> Vec<u8> {
+	let mut k = super::all::new(ns, db).encode().unwrap();
+	k.extend_from_slice(b"!sq\x00");
+	k
+}
+
+pub fn suffix(ns: &str, db: &str) -> Vec<u8> {
+	let mut k = super::all::new(ns, db).encode().unwrap();
+	k.extend_from_slice(b"!sq\xff");
+	k
+}
+
+impl Categorise for Sq<'_> {
+	fn categorise(&self) -> Category {
+		Category::DatabaseSequence
+	}
+}
+
+impl<'a> Sq<'a> {
+	pub fn new(ns: &'a str, db: &'a str, sq: &'a str) -> Self {
+		Self {
+			__: b'/',
+			_a: b'*',
+			ns,
+			_b: b'*',
+			db,
+			_c: b'!',
+			_d: b's',
+			_e: b'q',
+			sq,
+		}
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		#[rustfmt::skip]
+		let val = Sq::new(
+			"testns",
+			"testdb",
+			"testsq",
+		);
+		let enc = Sq::encode(&val).unwrap();
+		assert_eq!(enc, b"/*testns\0*testdb\0!sqtestsq\0");
+
+		let dec = Sq::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+	}
+}
@@ -0,0 +1,64 @@
+//! Stores the current value of a sequence
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct Sv<'a> {
+	__: u8,
+	_a: u8,
+	pub ns: &'a str,
+	_b: u8,
+	pub db: &'a str,
+	_c: u8,
+	_d: u8,
+	_e: u8,
+	pub sq: &'a str,
+}
+
+pub fn new<'a>(ns: &'a str, db: &'a str, sq: &'a str) -> Sv<'a> {
+	Sv::new(ns, db, sq)
+}
+
+impl Categorise for Sv<'_> {
+	fn categorise(&self) -> Category {
+		Category::DatabaseSequenceValue
+	}
+}
+
+impl<'a> Sv<'a> {
+	pub fn new(ns: &'a str, db: &'a str, sq: &'a str) -> Self {
+		Self {
+			__: b'/',
+			_a: b'*',
+			ns,
+			_b: b'*',
+			db,
+			_c: b'!',
+			_d: b's',
+			_e: b'v',
+			sq,
+		}
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		#[rustfmt::skip]
+		let val = Sv::new(
+			"testns",
+			"testdb",
+			"testsq",
+		);
+		let enc = Sv::encode(&val).unwrap();
+		assert_eq!(enc, b"/*testns\0*testdb\0!svtestsq\0");
+
+		let dec = Sv::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+	}
+}
@@ -0,0 +1,77 @@
+//! Stores a webhook delivery which failed all of its attempts
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+use uuid::Uuid;
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct Wd<'a> {
+	__: u8,
+	_a: u8,
+	pub ns: &'a str,
+	_b: u8,
+	pub db: &'a str,
+	_c: u8,
+	_d: u8,
+	_e: u8,
+	#[serde(with = "uuid::serde::compact")]
+	pub id: Uuid,
+}
+
+pub fn new<'a>(ns: &'a str, db: &'a str, id: Uuid) -> Wd<'a> {
+	Wd::new(ns, db, id)
+}
+
+pub fn prefix(ns: &str, db: &str) -> Vec<u8> {
+	let mut k = super::all::new(ns, db).encode().unwrap();
+	k.extend_from_slice(b"!wd\x00");
+	k
+}
+
+pub fn suffix(ns: &str, db: &str) -> Vec<u8> {
+	let mut k = super::all::new(ns, db).encode().unwrap();
+	k.extend_from_slice(b"!wd\xff");
+	k
+}
+
+impl Categorise for Wd<'_> {
+	fn categorise(&self) -> Category {
+		Category::DatabaseWebhookDeadLetter
+	}
+}
+
+impl<'a> Wd<'a> {
+	pub fn new(ns: &'a str, db: &'a str, id: Uuid) -> Self {
+		Self {
+			__: b'/',
+			_a: b'*',
+			ns,
+			_b: b'*',
+			db,
+			_c: b'!',
+			_d: b'w',
+			_e: b'd',
+			id,
+		}
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		#[rustfmt::skip]
+		let val = Wd::new(
+			"testns",
+			"testdb",
+			Uuid::from_u128(1),
+		);
+		let enc = Wd::encode(&val).unwrap();
+		assert_eq!(enc, b"/*testns\0*testdb\0!wd\0\0\0\0\0\0\0\0\0\0\0\0\0\0\0\x01");
+		let dec = Wd::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+	}
+}
@@ -0,0 +1,98 @@
+//! Stores a queued webhook delivery, ordered by the time at which it is due
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+use uuid::Uuid;
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct Wh<'a> {
+	__: u8,
+	_a: u8,
+	pub ns: &'a str,
+	_b: u8,
+	pub db: &'a str,
+	_c: u8,
+	_d: u8,
+	_e: u8,
+	pub ts: u64,
+	#[serde(with = "uuid::serde::compact")]
+	pub id: Uuid,
+}
+
+pub fn new<'a>(ns: &'a str, db: &'a str, ts: u64, id: Uuid) -> Wh<'a> {
+	Wh::new(ns, db, ts, id)
+}
+
+/// Returns the prefix for all of the queued webhooks in a database
+pub fn prefix(ns: &str, db: &str) -> Vec<u8> {
+	let mut k = super::all::new(ns, db).encode().unwrap();
+	k.extend_from_slice(b"!wh\x00");
+	k
+}
+
+/// Returns the suffix for the queued webhooks which are due at or before the timestamp
+pub fn suffix(ns: &str, db: &str, ts: u64) -> Vec<u8> {
+	let mut k = super::all::new(ns, db).encode().unwrap();
+	k.extend_from_slice(b"!wh");
+	k.extend_from_slice(&ts.saturating_add(1).to_be_bytes());
+	k
+}
+
+impl Categorise for Wh<'_> {
+	fn categorise(&self) -> Category {
+		Category::DatabaseWebhook
+	}
+}
+
+impl<'a> Wh<'a> {
+	pub fn new(ns: &'a str, db: &'a str, ts: u64, id: Uuid) -> Self {
+		Self {
+			__: b'/',
+			_a: b'*',
+			ns,
+			_b: b'*',
+			db,
+			_c: b'!',
+			_d: b'w',
+			_e: b'h',
+			ts,
+			id,
+		}
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		#[rustfmt::skip]
+		let val = Wh::new(
+			"testns",
+			"testdb",
+			2,
+			Uuid::from_u128(1),
+		);
+		let enc = Wh::encode(&val).unwrap();
+		assert_eq!(
+			enc,
+			b"/*testns\0*testdb\0!wh\0\0\0\0\0\0\0\x02\0\0\0\0\0\0\0\0\0\0\0\0\0\0\0\x01"
+		);
+		let dec = Wh::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+	}
+
+	#[test]
+	fn range() {
+		use super::*;
+		let key =
+			|ts| Wh::new("testns", "testdb", ts, Uuid::from_u128(u128::MAX)).encode().unwrap();
+		let beg = prefix("testns", "testdb");
+		let end = suffix("testns", "testdb", 2);
+		assert!(beg <= key(0) && key(0) < end);
+		assert!(beg <= key(2) && key(2) < end);
+		assert!(key(3) >= end);
+	}
+}
@@ -0,0 +1,54 @@
+//! Stores the data keys which encrypt the values of encrypted fields
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct DataKey {
+	__: u8,
+	_a: u8,
+	_b: u8,
+}
+
+pub fn new() -> DataKey {
+	DataKey::new()
+}
+
+impl Categorise for DataKey {
+	fn categorise(&self) -> Category {
+		Category::DataKey
+	}
+}
+
+impl DataKey {
+	pub fn new() -> Self {
+		Self {
+			__: b'!',
+			_a: b'd',
+			_b: b'k',
+		}
+	}
+}
+
+impl Default for DataKey {
+	fn default() -> Self {
+		Self::new()
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		#[rustfmt::skip]
+		let val = DataKey::new();
+		let enc = DataKey::encode(&val).unwrap();
+		assert_eq!(enc, b"!dk");
+
+		let dec = DataKey::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+	}
+}
@@ -128,6 +128,20 @@ pub fn new<'a>(
 	Graph::new(ns, db, tb, id.to_owned(), eg.to_owned(), fk)
 }
 
+/// Returns the prefix for the graph edges of every record in a table
+pub fn tbprefix(ns: &str, db: &str, tb: &str) -> Vec<u8> {
+	let mut k = crate::key::table::all::new(ns, db, tb).encode().unwrap();
+	k.extend_from_slice(b"~\x00");
+	k
+}
+
+/// Returns the suffix for the graph edges of every record in a table
+pub fn tbsuffix(ns: &str, db: &str, tb: &str) -> Vec<u8> {
+	let mut k = crate::key::table::all::new(ns, db, tb).encode().unwrap();
+	k.extend_from_slice(b"~\xff");
+	k
+}
+
 pub fn prefix(ns: &str, db: &str, tb: &str, id: &Id) -> Vec<u8> {
 	let mut k = Prefix::new(ns, db, tb, id).encode().unwrap();
 	k.extend_from_slice(&[0x00]);
@@ -22,6 +22,20 @@ pub fn new<'a>(ns: &'a str, db: &'a str, tb: &'a str, ix: &'a str) -> All<'a> {
 	All::new(ns, db, tb, ix)
 }
 
+#[allow(unused)]
+pub fn prefix(ns: &str, db: &str, tb: &str, ix: &str) -> Vec<u8> {
+	let mut k = new(ns, db, tb, ix).encode().unwrap();
+	k.extend_from_slice(&[0x00]);
+	k
+}
+
+#[allow(unused)]
+pub fn suffix(ns: &str, db: &str, tb: &str, ix: &str) -> Vec<u8> {
+	let mut k = new(ns, db, tb, ix).encode().unwrap();
+	k.extend_from_slice(&[0xff]);
+	k
+}
+
 impl Categorise for All<'_> {
 	fn categorise(&self) -> Category {
 		Category::IndexRoot
@@ -62,4 +76,13 @@ mod tests {
 		let dec = All::decode(&enc).unwrap();
 		assert_eq!(val, dec);
 	}
+
+	#[test]
+	fn range() {
+		use super::*;
+		let beg = prefix("testns", "testdb", "testtb", "testix");
+		assert_eq!(beg, b"/*testns\0*testdb\0*testtb\0+testix\0\x00");
+		let end = suffix("testns", "testdb", "testtb", "testix");
+		assert_eq!(end, b"/*testns\0*testdb\0*testtb\0+testix\0\xff");
+	}
 }
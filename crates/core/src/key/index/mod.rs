@@ -21,6 +21,7 @@ pub mod ia;
 pub mod ip;
 pub mod vm;
 
+use crate::err::Error;
 use crate::key::category::Categorise;
 use crate::key::category::Category;
 use crate::sql::array::Array;
@@ -184,6 +185,34 @@ impl<'a> Index<'a> {
 		*beg.last_mut().unwrap() = 0xff;
 		beg
 	}
+
+	/// Encodes the index entry with the field values and the record id
+	/// stored in inverted byte order. A forward range scan over keys
+	/// encoded this way returns the entries in descending order.
+	pub fn encode_desc(&self) -> Result<Vec<u8>, Error> {
+		Ok(Self::invert(self.ns, self.db, self.tb, self.ix, self.encode()?))
+	}
+
+	/// Flips every byte after the index prefix. As the key encoding is
+	/// prefix-free, this reverses the ordering of the entries, and as the
+	/// inversion is its own inverse, it also converts a descending entry
+	/// back into an ascending entry which can be decoded.
+	pub fn invert(ns: &str, db: &str, tb: &str, ix: &str, mut k: Vec<u8>) -> Vec<u8> {
+		let n = Self::prefix(ns, db, tb, ix).len();
+		k[n..].iter_mut().for_each(|b| *b = !*b);
+		k
+	}
+
+	pub fn prefix_desc_beg(ns: &str, db: &str, tb: &str, ix: &str) -> Vec<u8> {
+		Self::prefix(ns, db, tb, ix)
+	}
+
+	pub fn prefix_desc_end(ns: &str, db: &str, tb: &str, ix: &str) -> Vec<u8> {
+		// The inverted entries can start with any byte
+		let mut end = Self::prefix(ns, db, tb, ix);
+		*end.last_mut().unwrap() += 1;
+		end
+	}
 }
 
 #[cfg(test)]
@@ -227,4 +256,33 @@ mod tests {
 		let enc = Index::prefix_ids_composite_end("testns", "testdb", "testtb", "testix", &fd);
 		assert_eq!(enc, b"/*testns\0*testdb\0*testtb\0+testix\0*\0\0\0\x04testfd1\0\xff");
 	}
+
+	#[test]
+	fn key_desc() {
+		let fd = vec!["a"].into();
+		let id = "testid".into();
+		let val = Index::new("testns", "testdb", "testtb", "testix", &fd, Some(&id));
+		let enc = val.encode_desc().unwrap();
+		let pre = Index::prefix("testns", "testdb", "testtb", "testix");
+		assert!(enc.starts_with(&pre));
+		// The inverted entry decodes once it is inverted back
+		let asc = Index::invert("testns", "testdb", "testtb", "testix", enc);
+		assert_eq!(Index::decode(&asc).unwrap(), val);
+	}
+
+	#[test]
+	fn key_desc_order() {
+		let id = "testid".into();
+		let fds: Vec<Array> = vec![vec!["a"].into(), vec!["ab"].into(), vec!["b"].into()];
+		let desc: Vec<Vec<u8>> = fds
+			.iter()
+			.map(|fd| Index::new("testns", "testdb", "testtb", "testix", fd, Some(&id)))
+			.map(|k| k.encode_desc().unwrap())
+			.collect();
+		assert!(desc[0] > desc[1] && desc[1] > desc[2]);
+		// Every descending entry is within the descending range
+		let beg = Index::prefix_desc_beg("testns", "testdb", "testtb", "testix");
+		let end = Index::prefix_desc_end("testns", "testdb", "testtb", "testix");
+		assert!(desc.iter().all(|k| beg < *k && *k < end));
+	}
 }
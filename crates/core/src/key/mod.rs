@@ -1,6 +1,10 @@
 //! How the keys are structured in the key value store
 ///
 /// crate::key::version                  !v
+/// crate::key::rekey                    !rk
+/// crate::key::compression              !cp
+/// crate::key::datakey                  !dk
+/// crate::key::reencrypt                !re
 ///
 /// crate::key::root::all                /
 /// crate::key::root::ac                 /!ac{ac}
@@ -30,23 +34,34 @@
 /// crate::key::database::az             /*{ns}*{db}!az{az}
 /// crate::key::database::fc             /*{ns}*{db}!fn{fc}
 /// crate::key::database::ml             /*{ns}*{db}!ml{ml}{vn}
+/// crate::key::database::mv             /*{ns}*{db}!mv
 /// crate::key::database::pa             /*{ns}*{db}!pa{pa}
+/// crate::key::database::sq             /*{ns}*{db}!sq{sq}
+/// crate::key::database::sv             /*{ns}*{db}!sv{sq}
 /// crate::key::database::tb             /*{ns}*{db}!tb{tb}
 /// crate::key::database::ti             /+{ns id}*{db id}!ti
 /// crate::key::database::ts             /*{ns}*{db}!ts{ts}
 /// crate::key::database::us             /*{ns}*{db}!us{us}
 /// crate::key::database::vs             /*{ns}*{db}!vs
 /// crate::key::database::cg             /*{ns}*{db}!cg{ty}
+/// crate::key::database::wd             /*{ns}*{db}!wd{id}
+/// crate::key::database::wh             /*{ns}*{db}!wh{ts}{id}
 ///
 /// crate::key::database::access::all    /*{ns}*{db}&{ac}
 /// crate::key::database::access::gr     /*{ns}*{db}&{ac}!gr{gr}
 ///
 /// crate::key::table::all               /*{ns}*{db}*{tb}
+/// crate::key::table::er                /*{ns}*{db}*{tb}!er{id}
+/// crate::key::table::es                /*{ns}*{db}*{tb}!es{ev}
 /// crate::key::table::ev                /*{ns}*{db}*{tb}!ev{ev}
+/// crate::key::table::ex                /*{ns}*{db}*{tb}!ex{ts}{id}
 /// crate::key::table::fd                /*{ns}*{db}*{tb}!fd{fd}
+/// crate::key::table::fm                /*{ns}*{db}*{tb}!fm{fd}
 /// crate::key::table::ft                /*{ns}*{db}*{tb}!ft{ft}
 /// crate::key::table::ix                /*{ns}*{db}*{tb}!ix{ix}
 /// crate::key::table::lq                /*{ns}*{db}*{tb}!lq{lq}
+/// crate::key::table::mv                /*{ns}*{db}*{tb}!mv
+/// crate::key::table::st                /*{ns}*{db}*{tb}!st
 ///
 /// crate::key::index::all               /*{ns}*{db}*{tb}+{ix}
 /// crate::key::index::bc                /*{ns}*{db}*{tb}+{ix}!bc{id}
@@ -68,14 +83,21 @@
 ///
 /// crate::key::graph                    /*{ns}*{db}*{tb}~{id}{eg}{ft}{fk}
 ///
+/// crate::key::reference                /*{ns}*{db}*{tb}&{id}{ft}{fk}{ff}
+///
 pub(crate) mod category;
 pub(crate) mod change;
+pub(crate) mod compression;
 pub(crate) mod database;
+pub(crate) mod datakey;
 pub(crate) mod debug;
 pub(crate) mod graph;
 pub(crate) mod index;
 pub(crate) mod namespace;
 pub(crate) mod node;
+pub(crate) mod reencrypt;
+pub(crate) mod reference;
+pub(crate) mod rekey;
 pub(crate) mod root;
 pub(crate) mod table;
 pub(crate) mod thing;
@@ -16,6 +16,20 @@ pub fn new(ns: &str) -> All<'_> {
 	All::new(ns)
 }
 
+#[allow(unused)]
+pub fn prefix(ns: &str) -> Vec<u8> {
+	let mut k = new(ns).encode().unwrap();
+	k.extend_from_slice(&[0x00]);
+	k
+}
+
+#[allow(unused)]
+pub fn suffix(ns: &str) -> Vec<u8> {
+	let mut k = new(ns).encode().unwrap();
+	k.extend_from_slice(&[0xff]);
+	k
+}
+
 impl Categorise for All<'_> {
 	fn categorise(&self) -> Category {
 		Category::NamespaceRoot
@@ -47,4 +61,13 @@ mod tests {
 		let dec = All::decode(&enc).unwrap();
 		assert_eq!(val, dec);
 	}
+
+	#[test]
+	fn range() {
+		use super::*;
+		let beg = prefix("testns");
+		assert_eq!(beg, b"/*testns\0\x00");
+		let end = suffix("testns");
+		assert_eq!(end, b"/*testns\0\xff");
+	}
 }
@@ -18,6 +18,20 @@ pub fn new(nd: Uuid) -> All {
 	All::new(nd)
 }
 
+#[allow(unused)]
+pub fn prefix(nd: Uuid) -> Vec<u8> {
+	let mut k = new(nd).encode().unwrap();
+	k.extend_from_slice(&[0x00]);
+	k
+}
+
+#[allow(unused)]
+pub fn suffix(nd: Uuid) -> Vec<u8> {
+	let mut k = new(nd).encode().unwrap();
+	k.extend_from_slice(&[0xff]);
+	k
+}
+
 impl Categorise for All {
 	fn categorise(&self) -> Category {
 		Category::NodeRoot
@@ -51,4 +65,15 @@ mod tests {
 		let dec = All::decode(&enc).unwrap();
 		assert_eq!(val, dec);
 	}
+
+	#[test]
+	fn range() {
+		use super::*;
+		#[rustfmt::skip]
+		let nd = Uuid::from_bytes([0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10]);
+		let beg = prefix(nd);
+		assert_eq!(beg, b"/$\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c\x0d\x0e\x0f\x10\x00");
+		let end = suffix(nd);
+		assert_eq!(end, b"/$\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c\x0d\x0e\x0f\x10\xff");
+	}
 }
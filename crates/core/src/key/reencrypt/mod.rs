@@ -0,0 +1,54 @@
+//! Stores the progress of re-encrypting the values of encrypted fields
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct Reencrypt {
+	__: u8,
+	_a: u8,
+	_b: u8,
+}
+
+pub fn new() -> Reencrypt {
+	Reencrypt::new()
+}
+
+impl Categorise for Reencrypt {
+	fn categorise(&self) -> Category {
+		Category::Reencrypt
+	}
+}
+
+impl Reencrypt {
+	pub fn new() -> Self {
+		Self {
+			__: b'!',
+			_a: b'r',
+			_b: b'e',
+		}
+	}
+}
+
+impl Default for Reencrypt {
+	fn default() -> Self {
+		Self::new()
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		#[rustfmt::skip]
+		let val = Reencrypt::new();
+		let enc = Reencrypt::encode(&val).unwrap();
+		assert_eq!(enc, b"!re");
+
+		let dec = Reencrypt::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+	}
+}
@@ -0,0 +1,142 @@
+//! Stores a reverse pointer from a record to a record which references it
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use crate::sql::id::Id;
+use crate::sql::thing::Thing;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+struct Prefix<'a> {
+	__: u8,
+	_a: u8,
+	pub ns: &'a str,
+	_b: u8,
+	pub db: &'a str,
+	_c: u8,
+	pub tb: &'a str,
+	_d: u8,
+	pub id: Id,
+}
+
+impl<'a> Prefix<'a> {
+	fn new(ns: &'a str, db: &'a str, tb: &'a str, id: &Id) -> Self {
+		Self {
+			__: b'/',
+			_a: b'*',
+			ns,
+			_b: b'*',
+			db,
+			_c: b'*',
+			tb,
+			_d: b'&',
+			id: id.to_owned(),
+		}
+	}
+}
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct Reference<'a> {
+	__: u8,
+	_a: u8,
+	pub ns: &'a str,
+	_b: u8,
+	pub db: &'a str,
+	_c: u8,
+	pub tb: &'a str,
+	_d: u8,
+	pub id: Id,
+	pub ft: &'a str,
+	pub fk: Id,
+	pub ff: &'a str,
+}
+
+pub fn new<'a>(
+	ns: &'a str,
+	db: &'a str,
+	tb: &'a str,
+	id: &Id,
+	fk: &'a Thing,
+	ff: &'a str,
+) -> Reference<'a> {
+	Reference::new(ns, db, tb, id.to_owned(), fk, ff)
+}
+
+/// Returns the prefix for the references of every record in a table
+pub fn tbprefix(ns: &str, db: &str, tb: &str) -> Vec<u8> {
+	let mut k = crate::key::table::all::new(ns, db, tb).encode().unwrap();
+	k.extend_from_slice(b"&\x00");
+	k
+}
+
+/// Returns the suffix for the references of every record in a table
+pub fn tbsuffix(ns: &str, db: &str, tb: &str) -> Vec<u8> {
+	let mut k = crate::key::table::all::new(ns, db, tb).encode().unwrap();
+	k.extend_from_slice(b"&\xff");
+	k
+}
+
+pub fn prefix(ns: &str, db: &str, tb: &str, id: &Id) -> Vec<u8> {
+	let mut k = Prefix::new(ns, db, tb, id).encode().unwrap();
+	k.extend_from_slice(&[0x00]);
+	k
+}
+
+pub fn suffix(ns: &str, db: &str, tb: &str, id: &Id) -> Vec<u8> {
+	let mut k = Prefix::new(ns, db, tb, id).encode().unwrap();
+	k.extend_from_slice(&[0xff]);
+	k
+}
+
+impl Categorise for Reference<'_> {
+	fn categorise(&self) -> Category {
+		Category::Reference
+	}
+}
+
+impl<'a> Reference<'a> {
+	pub fn new(ns: &'a str, db: &'a str, tb: &'a str, id: Id, fk: &'a Thing, ff: &'a str) -> Self {
+		Self {
+			__: b'/',
+			_a: b'*',
+			ns,
+			_b: b'*',
+			db,
+			_c: b'*',
+			tb,
+			_d: b'&',
+			id,
+			ft: &fk.tb,
+			fk: fk.id.to_owned(),
+			ff,
+		}
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		use crate::syn::Parse;
+		let fk = Thing::parse("other:test");
+		#[rustfmt::skip]
+		let val = Reference::new(
+			"testns",
+			"testdb",
+			"testtb",
+			"testid".into(),
+			&fk,
+			"author",
+		);
+		let enc = Reference::encode(&val).unwrap();
+		assert_eq!(
+			enc,
+			b"/*testns\0*testdb\0*testtb\x00&\0\0\0\x01testid\0other\0\0\0\0\x01test\0author\0"
+		);
+
+		let dec = Reference::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+	}
+}
@@ -0,0 +1,60 @@
+//! Stores the progress of re-encrypting the datastore
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct Rekey {
+	__: u8,
+	_a: u8,
+	_b: u8,
+}
+
+pub fn new() -> Rekey {
+	Rekey::new()
+}
+
+/// Checks whether an encoded key is the rekey progress key
+pub fn is(key: &[u8]) -> bool {
+	key == b"!rk"
+}
+
+impl Categorise for Rekey {
+	fn categorise(&self) -> Category {
+		Category::Rekey
+	}
+}
+
+impl Rekey {
+	pub fn new() -> Self {
+		Self {
+			__: b'!',
+			_a: b'r',
+			_b: b'k',
+		}
+	}
+}
+
+impl Default for Rekey {
+	fn default() -> Self {
+		Self::new()
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		#[rustfmt::skip]
+		let val = Rekey::new();
+		let enc = Rekey::encode(&val).unwrap();
+		assert_eq!(enc, b"!rk");
+
+		let dec = Rekey::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+		assert!(is(&enc));
+	}
+}
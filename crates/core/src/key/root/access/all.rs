@@ -16,6 +16,20 @@ pub fn new(ac: &str) -> Access {
 	Access::new(ac)
 }
 
+#[allow(unused)]
+pub fn prefix(ac: &str) -> Vec<u8> {
+	let mut k = new(ac).encode().unwrap();
+	k.extend_from_slice(&[0x00]);
+	k
+}
+
+#[allow(unused)]
+pub fn suffix(ac: &str) -> Vec<u8> {
+	let mut k = new(ac).encode().unwrap();
+	k.extend_from_slice(&[0xff]);
+	k
+}
+
 impl Categorise for Access<'_> {
 	fn categorise(&self) -> Category {
 		Category::AccessRoot
@@ -47,4 +61,13 @@ mod tests {
 		let dec = Access::decode(&enc).unwrap();
 		assert_eq!(val, dec);
 	}
+
+	#[test]
+	fn range() {
+		use super::*;
+		let beg = prefix("testac");
+		assert_eq!(beg, b"/&testac\0\x00");
+		let end = suffix("testac");
+		assert_eq!(end, b"/&testac\0\xff");
+	}
 }
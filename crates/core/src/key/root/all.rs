@@ -14,6 +14,20 @@ pub fn new() -> Kv {
 	Kv::new()
 }
 
+#[allow(unused)]
+pub fn prefix() -> Vec<u8> {
+	let mut k = new().encode().unwrap();
+	k.extend_from_slice(&[0x00]);
+	k
+}
+
+#[allow(unused)]
+pub fn suffix() -> Vec<u8> {
+	let mut k = new().encode().unwrap();
+	k.extend_from_slice(&[0xff]);
+	k
+}
+
 impl Default for Kv {
 	fn default() -> Self {
 		Self::new()
@@ -47,4 +61,13 @@ mod tests {
 		let dec = Kv::decode(&enc).unwrap();
 		assert_eq!(val, dec);
 	}
+
+	#[test]
+	fn range() {
+		use super::*;
+		let beg = prefix();
+		assert_eq!(beg, b"/\x00");
+		let end = suffix();
+		assert_eq!(end, b"/\xff");
+	}
 }
@@ -20,6 +20,32 @@ pub fn new<'a>(ns: &'a str, db: &'a str, tb: &'a str) -> Table<'a> {
 	Table::new(ns, db, tb)
 }
 
+pub fn prefix(ns: &str, db: &str, tb: &str) -> Vec<u8> {
+	let mut k = new(ns, db, tb).encode().unwrap();
+	k.extend_from_slice(&[0x00]);
+	k
+}
+
+pub fn suffix(ns: &str, db: &str, tb: &str) -> Vec<u8> {
+	let mut k = new(ns, db, tb).encode().unwrap();
+	k.extend_from_slice(&[0xff]);
+	k
+}
+
+/// Returns the prefix for the definitions in a table
+pub fn defprefix(ns: &str, db: &str, tb: &str) -> Vec<u8> {
+	let mut k = new(ns, db, tb).encode().unwrap();
+	k.extend_from_slice(b"!\x00");
+	k
+}
+
+/// Returns the suffix for the definitions in a table
+pub fn defsuffix(ns: &str, db: &str, tb: &str) -> Vec<u8> {
+	let mut k = new(ns, db, tb).encode().unwrap();
+	k.extend_from_slice(b"!\xff");
+	k
+}
+
 impl Categorise for Table<'_> {
 	fn categorise(&self) -> Category {
 		Category::TableRoot
@@ -57,4 +83,17 @@ mod tests {
 		let dec = Table::decode(&enc).unwrap();
 		assert_eq!(val, dec);
 	}
+
+	#[test]
+	fn range() {
+		use super::*;
+		let beg = prefix("testns", "testdb", "testtb");
+		assert_eq!(beg, b"/*testns\0*testdb\0*testtb\0\x00");
+		let end = suffix("testns", "testdb", "testtb");
+		assert_eq!(end, b"/*testns\0*testdb\0*testtb\0\xff");
+		let beg = defprefix("testns", "testdb", "testtb");
+		assert_eq!(beg, b"/*testns\0*testdb\0*testtb\0!\x00");
+		let end = defsuffix("testns", "testdb", "testtb");
+		assert_eq!(end, b"/*testns\0*testdb\0*testtb\0!\xff");
+	}
 }
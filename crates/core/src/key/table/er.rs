@@ -0,0 +1,82 @@
+//! Stores the time at which a record expires
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use crate::sql::Id;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct Er<'a> {
+	__: u8,
+	_a: u8,
+	pub ns: &'a str,
+	_b: u8,
+	pub db: &'a str,
+	_c: u8,
+	pub tb: &'a str,
+	_d: u8,
+	_e: u8,
+	_f: u8,
+	pub id: Id,
+}
+
+pub fn new<'a>(ns: &'a str, db: &'a str, tb: &'a str, id: &Id) -> Er<'a> {
+	Er::new(ns, db, tb, id.to_owned())
+}
+
+pub fn prefix(ns: &str, db: &str, tb: &str) -> Vec<u8> {
+	let mut k = super::all::new(ns, db, tb).encode().unwrap();
+	k.extend_from_slice(b"!er\x00");
+	k
+}
+
+pub fn suffix(ns: &str, db: &str, tb: &str) -> Vec<u8> {
+	let mut k = super::all::new(ns, db, tb).encode().unwrap();
+	k.extend_from_slice(b"!er\xff");
+	k
+}
+
+impl Categorise for Er<'_> {
+	fn categorise(&self) -> Category {
+		Category::TableExpiryRecord
+	}
+}
+
+impl<'a> Er<'a> {
+	pub fn new(ns: &'a str, db: &'a str, tb: &'a str, id: Id) -> Self {
+		Self {
+			__: b'/',
+			_a: b'*',
+			ns,
+			_b: b'*',
+			db,
+			_c: b'*',
+			tb,
+			_d: b'!',
+			_e: b'e',
+			_f: b'r',
+			id,
+		}
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		#[rustfmt::skip]
+		let val = Er::new(
+			"testns",
+			"testdb",
+			"testtb",
+			"testid".into(),
+		);
+		let enc = Er::encode(&val).unwrap();
+		assert_eq!(enc, b"/*testns\0*testdb\0*testtb\0!er\0\0\0\x01testid\0");
+
+		let dec = Er::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+	}
+}
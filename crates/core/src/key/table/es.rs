@@ -0,0 +1,69 @@
+//! Stores the last time at which a scheduled event was run
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct Es<'a> {
+	__: u8,
+	_a: u8,
+	pub ns: &'a str,
+	_b: u8,
+	pub db: &'a str,
+	_c: u8,
+	pub tb: &'a str,
+	_d: u8,
+	_e: u8,
+	_f: u8,
+	pub ev: &'a str,
+}
+
+pub fn new<'a>(ns: &'a str, db: &'a str, tb: &'a str, ev: &'a str) -> Es<'a> {
+	Es::new(ns, db, tb, ev)
+}
+
+impl Categorise for Es<'_> {
+	fn categorise(&self) -> Category {
+		Category::TableEventSchedule
+	}
+}
+
+impl<'a> Es<'a> {
+	pub fn new(ns: &'a str, db: &'a str, tb: &'a str, ev: &'a str) -> Self {
+		Self {
+			__: b'/',
+			_a: b'*',
+			ns,
+			_b: b'*',
+			db,
+			_c: b'*',
+			tb,
+			_d: b'!',
+			_e: b'e',
+			_f: b's',
+			ev,
+		}
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		#[rustfmt::skip]
+		let val = Es::new(
+			"testns",
+			"testdb",
+			"testtb",
+			"testev",
+		);
+		let enc = Es::encode(&val).unwrap();
+		assert_eq!(enc, b"/*testns\x00*testdb\x00*testtb\x00!estestev\x00");
+
+		let dec = Es::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+	}
+}
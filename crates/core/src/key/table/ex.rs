@@ -0,0 +1,102 @@
+//! Stores a record expiry, ordered by the time at which the record expires
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use crate::sql::Id;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct Ex<'a> {
+	__: u8,
+	_a: u8,
+	pub ns: &'a str,
+	_b: u8,
+	pub db: &'a str,
+	_c: u8,
+	pub tb: &'a str,
+	_d: u8,
+	_e: u8,
+	_f: u8,
+	pub ts: u64,
+	pub id: Id,
+}
+
+pub fn new<'a>(ns: &'a str, db: &'a str, tb: &'a str, ts: u64, id: &Id) -> Ex<'a> {
+	Ex::new(ns, db, tb, ts, id.to_owned())
+}
+
+/// Returns the prefix for all of the record expiries on a table
+pub fn prefix(ns: &str, db: &str, tb: &str) -> Vec<u8> {
+	let mut k = super::all::new(ns, db, tb).encode().unwrap();
+	k.extend_from_slice(b"!ex\x00");
+	k
+}
+
+/// Returns the suffix for the record expiries which expire before the timestamp
+pub fn suffix(ns: &str, db: &str, tb: &str, ts: u64) -> Vec<u8> {
+	let mut k = super::all::new(ns, db, tb).encode().unwrap();
+	k.extend_from_slice(b"!ex");
+	k.extend_from_slice(&ts.to_be_bytes());
+	k
+}
+
+impl Categorise for Ex<'_> {
+	fn categorise(&self) -> Category {
+		Category::TableExpiry
+	}
+}
+
+impl<'a> Ex<'a> {
+	pub fn new(ns: &'a str, db: &'a str, tb: &'a str, ts: u64, id: Id) -> Self {
+		Self {
+			__: b'/',
+			_a: b'*',
+			ns,
+			_b: b'*',
+			db,
+			_c: b'*',
+			tb,
+			_d: b'!',
+			_e: b'e',
+			_f: b'x',
+			ts,
+			id,
+		}
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		#[rustfmt::skip]
+		let val = Ex::new(
+			"testns",
+			"testdb",
+			"testtb",
+			1,
+			"testid".into(),
+		);
+		let enc = Ex::encode(&val).unwrap();
+		assert_eq!(enc, b"/*testns\0*testdb\0*testtb\0!ex\0\0\0\0\0\0\0\x01\0\0\0\x01testid\0");
+
+		let dec = Ex::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+	}
+
+	#[test]
+	fn range() {
+		use super::*;
+		let val = prefix("testns", "testdb", "testtb");
+		assert_eq!(val, b"/*testns\0*testdb\0*testtb\0!ex\0");
+		let val = suffix("testns", "testdb", "testtb", 2);
+		assert_eq!(val, b"/*testns\0*testdb\0*testtb\0!ex\0\0\0\0\0\0\0\x02");
+		// Records expiring before the timestamp sort before the suffix
+		let enc = Ex::new("testns", "testdb", "testtb", 1, "testid".into()).encode().unwrap();
+		assert!(enc < val);
+		let enc = Ex::new("testns", "testdb", "testtb", 2, "testid".into()).encode().unwrap();
+		assert!(enc > val);
+	}
+}
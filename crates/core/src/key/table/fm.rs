@@ -0,0 +1,93 @@
+//! Stores the status of a field migration
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct Fm<'a> {
+	__: u8,
+	_a: u8,
+	pub ns: &'a str,
+	_b: u8,
+	pub db: &'a str,
+	_c: u8,
+	pub tb: &'a str,
+	_d: u8,
+	_e: u8,
+	_f: u8,
+	pub fd: &'a str,
+}
+
+pub fn new<'a>(ns: &'a str, db: &'a str, tb: &'a str, fd: &'a str) -> Fm<'a> {
+	Fm::new(ns, db, tb, fd)
+}
+
+pub fn prefix(ns: &str, db: &str, tb: &str) -> Vec<u8> {
+	let mut k = super::all::new(ns, db, tb).encode().unwrap();
+	k.extend_from_slice(b"!fm\x00");
+	k
+}
+
+pub fn suffix(ns: &str, db: &str, tb: &str) -> Vec<u8> {
+	let mut k = super::all::new(ns, db, tb).encode().unwrap();
+	k.extend_from_slice(b"!fm\xff");
+	k
+}
+
+impl Categorise for Fm<'_> {
+	fn categorise(&self) -> Category {
+		Category::TableFieldMigration
+	}
+}
+
+impl<'a> Fm<'a> {
+	pub fn new(ns: &'a str, db: &'a str, tb: &'a str, fd: &'a str) -> Self {
+		Self {
+			__: b'/',
+			_a: b'*',
+			ns,
+			_b: b'*',
+			db,
+			_c: b'*',
+			tb,
+			_d: b'!',
+			_e: b'f',
+			_f: b'm',
+			fd,
+		}
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		#[rustfmt::skip]
+		let val = Fm::new(
+			"testns",
+			"testdb",
+			"testtb",
+			"testfd",
+		);
+		let enc = Fm::encode(&val).unwrap();
+		assert_eq!(enc, b"/*testns\x00*testdb\x00*testtb\x00!fmtestfd\x00");
+
+		let dec = Fm::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+	}
+
+	#[test]
+	fn test_prefix() {
+		let val = super::prefix("testns", "testdb", "testtb");
+		assert_eq!(val, b"/*testns\0*testdb\0*testtb\0!fm\0");
+	}
+
+	#[test]
+	fn test_suffix() {
+		let val = super::suffix("testns", "testdb", "testtb");
+		assert_eq!(val, b"/*testns\0*testdb\0*testtb\0!fm\xff");
+	}
+}
@@ -1,6 +1,12 @@
 pub mod all;
+pub mod er;
+pub mod es;
 pub mod ev;
+pub mod ex;
 pub mod fd;
+pub mod fm;
 pub mod ft;
 pub mod ix;
 pub mod lq;
+pub mod mv;
+pub mod st;
@@ -0,0 +1,66 @@
+//! Stores the statistics of a table, used for planning queries
+use crate::key::category::Categorise;
+use crate::key::category::Category;
+use derive::Key;
+use serde::{Deserialize, Serialize};
+
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Key)]
+#[non_exhaustive]
+pub struct St<'a> {
+	__: u8,
+	_a: u8,
+	pub ns: &'a str,
+	_b: u8,
+	pub db: &'a str,
+	_c: u8,
+	pub tb: &'a str,
+	_d: u8,
+	_e: u8,
+	_f: u8,
+}
+
+pub fn new<'a>(ns: &'a str, db: &'a str, tb: &'a str) -> St<'a> {
+	St::new(ns, db, tb)
+}
+
+impl Categorise for St<'_> {
+	fn categorise(&self) -> Category {
+		Category::TableStatistics
+	}
+}
+
+impl<'a> St<'a> {
+	pub fn new(ns: &'a str, db: &'a str, tb: &'a str) -> Self {
+		Self {
+			__: b'/',
+			_a: b'*',
+			ns,
+			_b: b'*',
+			db,
+			_c: b'*',
+			tb,
+			_d: b'!',
+			_e: b's',
+			_f: b't',
+		}
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	#[test]
+	fn key() {
+		use super::*;
+		#[rustfmt::skip]
+		let val = St::new(
+			"testns",
+			"testdb",
+			"testtb",
+		);
+		let enc = St::encode(&val).unwrap();
+		assert_eq!(enc, b"/*testns\0*testdb\0*testtb\0!st");
+
+		let dec = St::decode(&enc).unwrap();
+		assert_eq!(val, dec);
+	}
+}
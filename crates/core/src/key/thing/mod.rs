@@ -102,4 +102,28 @@ mod tests {
 		assert_eq!(val, dec);
 		println!("---");
 	}
+
+	#[test]
+	fn key_datetime_order() {
+		use super::*;
+		// Datetimes with and without fractional seconds
+		let ids = [
+			"foo:[d'2020-01-01T09:59:59.5Z']",
+			"foo:[d'2020-01-01T10:00:00Z']",
+			"foo:[d'2020-01-01T10:00:00.5Z']",
+			"foo:[d'2020-01-01T10:00:01Z']",
+		];
+		let enc = ids
+			.iter()
+			.map(|id| {
+				let thing = syn::thing(id).expect("Failed to parse the ID");
+				let val = Thing::new("testns", "testdb", "testtb", thing.id);
+				let enc = Thing::encode(&val).unwrap();
+				assert_eq!(val, Thing::decode(&enc).unwrap());
+				enc
+			})
+			.collect::<Vec<_>>();
+		// The keys sort in chronological order
+		assert!(enc.windows(2).all(|w| w[0] < w[1]));
+	}
 }
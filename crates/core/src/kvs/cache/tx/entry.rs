@@ -12,6 +12,7 @@ use crate::sql::statements::DefineIndexStatement;
 use crate::sql::statements::DefineModelStatement;
 use crate::sql::statements::DefineNamespaceStatement;
 use crate::sql::statements::DefineParamStatement;
+use crate::sql::statements::DefineSequenceStatement;
 use crate::sql::statements::DefineTableStatement;
 use crate::sql::statements::DefineUserStatement;
 use crate::sql::statements::LiveStatement;
@@ -62,6 +63,8 @@ pub(crate) enum Entry {
 	Cgs(Arc<[DefineConfigStatement]>),
 	/// A slice of DefineParamStatement specified on a database.
 	Pas(Arc<[DefineParamStatement]>),
+	/// A slice of DefineSequenceStatement specified on a database.
+	Sqs(Arc<[DefineSequenceStatement]>),
 	/// A slice of DefineEventStatement specified on a table.
 	Evs(Arc<[DefineEventStatement]>),
 	/// A slice of DefineFieldStatement specified on a table.
@@ -205,6 +208,14 @@ impl Entry {
 			_ => Err(fail!("Unable to convert type into Entry::Pas")),
 		}
 	}
+	/// Converts this cache entry into a slice of [`DefineSequenceStatement`].
+	/// This panics if called on a cache entry that is not an [`Entry::Sqs`].
+	pub(crate) fn try_into_sqs(self) -> Result<Arc<[DefineSequenceStatement]>, Error> {
+		match self {
+			Entry::Sqs(v) => Ok(v),
+			_ => Err(fail!("Unable to convert type into Entry::Sqs")),
+		}
+	}
 	/// Converts this cache entry into a slice of [`DefineModelStatement`].
 	/// This panics if called on a cache entry that is not an [`Entry::Mls`].
 	pub(crate) fn try_into_mls(self) -> Result<Arc<[DefineModelStatement]>, Error> {
@@ -39,6 +39,8 @@ pub(crate) enum Lookup<'a> {
 	Cgs(&'a str, &'a str),
 	/// A cache key for parameters (on a database)
 	Pas(&'a str, &'a str),
+	/// A cache key for sequences (on a database)
+	Sqs(&'a str, &'a str),
 	/// A cache key for tables
 	Tbs(&'a str, &'a str),
 	/// A cache key for events (on a table)
@@ -85,6 +87,8 @@ pub(crate) enum Lookup<'a> {
 	Cg(&'a str, &'a str, &'a str),
 	/// A cache key for a parameter (on a database)
 	Pa(&'a str, &'a str, &'a str),
+	/// A cache key for a sequence (on a database)
+	Sq(&'a str, &'a str, &'a str),
 	/// A cache key for a table
 	Tb(&'a str, &'a str, &'a str),
 	/// A cache key for an event (on a table)
@@ -119,6 +123,7 @@ impl Equivalent<Key> for Lookup<'_> {
 			(Self::Mls(la, lb), Key::Mls(ka, kb)) => la == ka && lb == kb,
 			(Self::Cgs(la, lb), Key::Cgs(ka, kb)) => la == ka && lb == kb,
 			(Self::Pas(la, lb), Key::Pas(ka, kb)) => la == ka && lb == kb,
+			(Self::Sqs(la, lb), Key::Sqs(ka, kb)) => la == ka && lb == kb,
 			(Self::Tbs(la, lb), Key::Tbs(ka, kb)) => la == ka && lb == kb,
 			(Self::Evs(la, lb, lc), Key::Evs(ka, kb, kc)) => la == ka && lb == kb && lc == kc,
 			(Self::Fds(la, lb, lc), Key::Fds(ka, kb, kc)) => la == ka && lb == kb && lc == kc,
@@ -143,6 +148,7 @@ impl Equivalent<Key> for Lookup<'_> {
 			(Self::Ml(la, lb, lc, ld), Key::Ml(ka, kb, kc, kd)) => la == ka && lb == kb && lc == kc && ld == kd,
 			(Self::Cg(la, lb, lc), Key::Cg(ka, kb, kc)) => la == ka && lb == kb && lc == kc,
 			(Self::Pa(la, lb, lc), Key::Pa(ka, kb, kc)) => la == ka && lb == kb && lc == kc,
+			(Self::Sq(la, lb, lc), Key::Sq(ka, kb, kc)) => la == ka && lb == kb && lc == kc,
 			(Self::Tb(la, lb, lc), Key::Tb(ka, kb, kc)) => la == ka && lb == kb && lc == kc,
 			(Self::Ev(la, lb, lc, ld), Key::Ev(ka, kb, kc, kd)) => la == ka && lb == kb && lc == kc && ld == kd,
 			(Self::Fd(la, lb, lc, ld), Key::Fd(ka, kb, kc, kd)) => la == ka && lb == kb && lc == kc && ld == kd,
@@ -0,0 +1,158 @@
+//! Compresses values before they are written to the storage engine, and
+//! decompresses them when read. While compression is enabled, every value
+//! starts with a header byte which identifies the algorithm used, or which
+//! marks the value as uncompressed, so that compressed and uncompressed
+//! values can be stored alongside each other without any ambiguity.
+use crate::err::Error;
+use crate::kvs::Val;
+use std::fmt::{self, Display};
+use std::str::FromStr;
+
+/// The header byte of a value which was not compressed
+const UNCOMPRESSED: u8 = 0;
+/// The length of the header before the compressed data
+const HEADER_LEN: usize = 1;
+/// The compression level used for zstd
+#[cfg(all(not(target_arch = "wasm32"), feature = "compression-zstd"))]
+const ZSTD_LEVEL: i32 = 3;
+
+/// The algorithm used to compress values
+#[derive(Clone, Copy, Debug, Eq, PartialEq)]
+#[non_exhaustive]
+pub enum Algorithm {
+	Snappy,
+	#[cfg(all(not(target_arch = "wasm32"), feature = "compression-zstd"))]
+	Zstd,
+}
+
+impl Algorithm {
+	/// The header byte which identifies this algorithm
+	fn id(&self) -> u8 {
+		match self {
+			Algorithm::Snappy => 1,
+			#[cfg(all(not(target_arch = "wasm32"), feature = "compression-zstd"))]
+			Algorithm::Zstd => 2,
+		}
+	}
+}
+
+impl Display for Algorithm {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		match self {
+			Algorithm::Snappy => f.write_str("snappy"),
+			#[cfg(all(not(target_arch = "wasm32"), feature = "compression-zstd"))]
+			Algorithm::Zstd => f.write_str("zstd"),
+		}
+	}
+}
+
+impl FromStr for Algorithm {
+	type Err = Error;
+	fn from_str(s: &str) -> Result<Self, Self::Err> {
+		match s.to_ascii_lowercase().as_str() {
+			"snappy" => Ok(Algorithm::Snappy),
+			#[cfg(all(not(target_arch = "wasm32"), feature = "compression-zstd"))]
+			"zstd" => Ok(Algorithm::Zstd),
+			_ => Err(Error::Ds(format!("The compression algorithm '{s}' is not supported"))),
+		}
+	}
+}
+
+/// The configuration for compressing values which are written to storage
+#[derive(Clone, Copy, Debug)]
+pub struct Compression {
+	/// The algorithm used to compress values
+	algorithm: Algorithm,
+	/// The minimum size of a value before it is compressed
+	threshold: usize,
+}
+
+impl Compression {
+	/// Compress values of at least `threshold` bytes with the specified algorithm
+	pub fn new(algorithm: Algorithm, threshold: usize) -> Self {
+		Compression {
+			algorithm,
+			threshold,
+		}
+	}
+
+	/// Compresses a value, if it is large enough, and if compression reduces
+	/// its size. The value is always prefixed with the header byte.
+	pub(crate) fn compress(&self, val: Val) -> Result<Val, Error> {
+		if val.len() >= self.threshold {
+			let data = match self.algorithm {
+				Algorithm::Snappy => snap::raw::Encoder::new().compress_vec(&val).map_err(error)?,
+				#[cfg(all(not(target_arch = "wasm32"), feature = "compression-zstd"))]
+				Algorithm::Zstd => zstd::bulk::compress(&val, ZSTD_LEVEL).map_err(error)?,
+			};
+			if data.len() < val.len() {
+				return Ok(envelope(self.algorithm.id(), &data));
+			}
+		}
+		Ok(envelope(UNCOMPRESSED, &val))
+	}
+}
+
+/// Prefixes the data with the header byte
+fn envelope(id: u8, data: &[u8]) -> Val {
+	let mut out = Vec::with_capacity(HEADER_LEN + data.len());
+	out.push(id);
+	out.extend_from_slice(data);
+	out
+}
+
+/// Decompresses a value which was written while compression was enabled
+pub(crate) fn decompress(val: Val) -> Result<Val, Error> {
+	let Some((&id, data)) = val.split_first() else {
+		return Err(Error::Ds("Unable to decompress a value without a header".to_owned()));
+	};
+	match id {
+		UNCOMPRESSED => Ok(data.to_vec()),
+		1 => snap::raw::Decoder::new().decompress_vec(data).map_err(error),
+		#[cfg(all(not(target_arch = "wasm32"), feature = "compression-zstd"))]
+		2 => zstd::stream::decode_all(data).map_err(error),
+		v => Err(Error::Ds(format!("Unable to decompress a value with the unknown algorithm {v}"))),
+	}
+}
+
+fn error<E: Display>(e: E) -> Error {
+	Error::Ds(format!("Unable to compress or decompress a value: {e}"))
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+
+	#[test]
+	fn compress_and_decompress() {
+		let val = b"SurrealDB ".repeat(100);
+		#[cfg(feature = "compression-zstd")]
+		let algorithms = [Algorithm::Snappy, Algorithm::Zstd];
+		#[cfg(not(feature = "compression-zstd"))]
+		let algorithms = [Algorithm::Snappy];
+		for algorithm in algorithms {
+			let cmp = Compression::new(algorithm, 64);
+			let out = cmp.compress(val.clone()).unwrap();
+			assert!(out.len() < val.len());
+			assert_eq!(decompress(out).unwrap(), val);
+		}
+	}
+
+	#[test]
+	fn small_values_are_not_compressed() {
+		let cmp = Compression::new(Algorithm::Snappy, 64);
+		let val = b"SurrealDB".to_vec();
+		let out = cmp.compress(val.clone()).unwrap();
+		assert_eq!(out, [&[UNCOMPRESSED], val.as_slice()].concat());
+		assert_eq!(decompress(out).unwrap(), val);
+	}
+
+	#[test]
+	fn values_are_not_ambiguous() {
+		let cmp = Compression::new(Algorithm::Snappy, 64);
+		// A value which looks like a header is stored unchanged
+		let val = b"\x01SurrealDB".to_vec();
+		assert_eq!(decompress(cmp.compress(val.clone()).unwrap()).unwrap(), val);
+		assert!(decompress(Vec::new()).is_err());
+	}
+}
@@ -0,0 +1,428 @@
+//! Parses CSV files into records which can be inserted into a table.
+use crate::cnf::CSV_IMPORT_BATCH_SIZE;
+use crate::dbs::{Response, Session};
+use crate::err::Error;
+use crate::kvs::Datastore;
+use crate::sql::statements::{InsertStatement, SelectStatement};
+use crate::sql::{
+	Data, Field, Fields, Kind, Number, Object, Output, Subquery, Table, Value, Values,
+};
+use crate::syn;
+use bytes::Bytes;
+use futures::{Stream, StreamExt};
+use std::collections::BTreeMap;
+use std::mem;
+use std::pin::pin;
+
+/// The options used when importing a CSV file into a table
+#[derive(Clone, Debug)]
+#[non_exhaustive]
+pub struct Config {
+	/// The table into which the records are inserted
+	pub table: String,
+	/// The character which separates the fields in each record
+	pub delimiter: u8,
+	/// Whether the first record contains the column names
+	pub header: bool,
+	/// The column names, which are used instead of the header record
+	pub columns: Option<Vec<String>>,
+	/// The types to which the values in specific columns are converted
+	pub types: BTreeMap<String, Kind>,
+	/// An expression which is evaluated against each record before it is inserted
+	pub transform: Option<Value>,
+}
+
+impl Config {
+	pub fn new(table: impl Into<String>) -> Self {
+		Self {
+			table: table.into(),
+			delimiter: b',',
+			header: true,
+			columns: None,
+			types: BTreeMap::new(),
+			transform: None,
+		}
+	}
+}
+
+/// Parses a column type, specified as `column:kind`
+pub fn column_type(v: &str) -> Result<(String, Kind), Error> {
+	match v.split_once(':') {
+		Some((column, kind)) => Ok((column.trim().to_owned(), syn::kind(kind.trim())?)),
+		None => Err(Error::CsvColumnType(v.to_owned())),
+	}
+}
+
+#[derive(Clone, Copy, Debug, Eq, PartialEq)]
+enum State {
+	/// At the start of a field
+	Start,
+	/// Within an unquoted field
+	Unquoted,
+	/// Within a quoted field
+	Quoted,
+	/// After a quote within a quoted field
+	Quote,
+}
+
+/// Splits CSV input into records, without requiring the whole input at once
+#[derive(Debug)]
+struct Reader {
+	delimiter: u8,
+	state: State,
+	field: Vec<u8>,
+	record: Vec<String>,
+	/// The line which is currently being read
+	line: usize,
+	/// The line on which the current record started
+	begin: usize,
+}
+
+impl Reader {
+	fn new(delimiter: u8) -> Self {
+		Self {
+			delimiter,
+			state: State::Start,
+			field: Vec::new(),
+			record: Vec::new(),
+			line: 1,
+			begin: 1,
+		}
+	}
+
+	fn error(&self, message: impl Into<String>) -> Error {
+		Error::CsvImport {
+			line: self.begin,
+			message: message.into(),
+		}
+	}
+
+	/// Reads a chunk of input, returning the records which it completes
+	fn push(&mut self, chunk: &[u8]) -> Result<Vec<(usize, Vec<String>)>, Error> {
+		let mut out = Vec::new();
+		for &b in chunk {
+			// A quote within a quoted field is either an escaped quote, or the end of the field
+			if self.state == State::Quote {
+				if b == b'"' {
+					self.field.push(b);
+					self.state = State::Quoted;
+					continue;
+				}
+				self.state = State::Unquoted;
+			}
+			match (self.state, b) {
+				(State::Quoted, b'"') => self.state = State::Quote,
+				(State::Quoted, b) => {
+					if b == b'\n' {
+						self.line += 1;
+					}
+					self.field.push(b);
+				}
+				(State::Start, b'"') => self.state = State::Quoted,
+				(_, b'\r') => continue,
+				(_, b'\n') => {
+					self.line += 1;
+					if let Some(record) = self.record()? {
+						out.push(record);
+					}
+				}
+				(_, b) if b == self.delimiter => self.field()?,
+				(_, b) => {
+					self.field.push(b);
+					self.state = State::Unquoted;
+				}
+			}
+		}
+		Ok(out)
+	}
+
+	/// Reads the end of the input, returning the final record if there is one
+	fn finish(&mut self) -> Result<Option<(usize, Vec<String>)>, Error> {
+		match self.state {
+			State::Quoted => Err(self.error("Unexpected end of input within a quoted field")),
+			State::Start if self.record.is_empty() => Ok(None),
+			_ => self.record(),
+		}
+	}
+
+	fn field(&mut self) -> Result<(), Error> {
+		let field = String::from_utf8(mem::take(&mut self.field))
+			.map_err(|_| self.error("The field is not valid UTF-8"))?;
+		self.record.push(field);
+		self.state = State::Start;
+		Ok(())
+	}
+
+	fn record(&mut self) -> Result<Option<(usize, Vec<String>)>, Error> {
+		self.field()?;
+		let line = mem::replace(&mut self.begin, self.line);
+		let record = mem::take(&mut self.record);
+		// Skip any empty lines
+		if record.len() == 1 && record[0].is_empty() {
+			return Ok(None);
+		}
+		Ok(Some((line, record)))
+	}
+}
+
+/// Converts CSV input into records which can be inserted into a table
+#[derive(Debug)]
+#[non_exhaustive]
+pub struct Importer {
+	cfg: Config,
+	reader: Reader,
+	/// Whether the beginning of the input has yet to be read
+	start: bool,
+	/// Whether the header record has yet to be read
+	header: bool,
+	columns: Option<Vec<String>>,
+}
+
+impl Importer {
+	pub fn new(cfg: Config) -> Self {
+		Self {
+			reader: Reader::new(cfg.delimiter),
+			start: true,
+			header: cfg.header,
+			columns: cfg.columns.clone(),
+			cfg,
+		}
+	}
+
+	/// Parses a chunk of CSV input, returning the records which it completes
+	pub fn push(&mut self, mut chunk: &[u8]) -> Result<Vec<Value>, Error> {
+		// Skip the byte order mark at the beginning of the input
+		if mem::take(&mut self.start) {
+			chunk = chunk.strip_prefix(b"\xEF\xBB\xBF").unwrap_or(chunk);
+		}
+		let mut out = Vec::new();
+		for (line, fields) in self.reader.push(chunk)? {
+			if let Some(v) = self.convert(line, fields)? {
+				out.push(v);
+			}
+		}
+		Ok(out)
+	}
+
+	/// Parses the end of the CSV input, returning the final record if there is one
+	pub fn finish(&mut self) -> Result<Option<Value>, Error> {
+		match self.reader.finish()? {
+			Some((line, fields)) => self.convert(line, fields),
+			None => Ok(None),
+		}
+	}
+
+	/// Returns the statement which inserts the records into the table
+	pub fn statement(&self, records: Vec<Value>) -> InsertStatement {
+		let data = match &self.cfg.transform {
+			// Evaluate the transform against each of the records
+			Some(transform) => Value::Subquery(Box::new(Subquery::Select(SelectStatement {
+				expr: Fields(
+					vec![Field::Single {
+						expr: transform.clone(),
+						alias: None,
+					}],
+					true,
+				),
+				what: Values(vec![Value::from(records)]),
+				..Default::default()
+			}))),
+			None => Value::from(records),
+		};
+		InsertStatement {
+			into: Some(Value::Table(Table::from(self.cfg.table.as_str()))),
+			data: Data::SingleExpression(data),
+			output: Some(Output::None),
+			..Default::default()
+		}
+	}
+
+	/// Converts the fields of a CSV record into an object
+	fn convert(&mut self, line: usize, fields: Vec<String>) -> Result<Option<Value>, Error> {
+		let error = |message: String| Error::CsvImport {
+			line,
+			message,
+		};
+		// The first record contains the column names
+		if mem::take(&mut self.header) {
+			if self.columns.is_none() {
+				self.columns = Some(fields);
+			}
+			return Ok(None);
+		}
+		let Some(columns) = &self.columns else {
+			return Err(error("No column names were specified for the CSV input".to_owned()));
+		};
+		if fields.len() != columns.len() {
+			return Err(error(format!(
+				"Expected {} fields but found {}",
+				columns.len(),
+				fields.len()
+			)));
+		}
+		let mut obj = Object::default();
+		for (column, field) in columns.iter().zip(fields) {
+			// Empty fields are not stored on the record
+			if field.is_empty() {
+				continue;
+			}
+			let value = match self.cfg.types.get(column) {
+				Some(kind) => Value::from(field)
+					.convert_to(kind)
+					.map_err(|e| error(format!("Unable to convert the `{column}` column: {e}")))?,
+				None => infer(field),
+			};
+			obj.insert(column.clone(), value);
+		}
+		Ok(Some(Value::from(obj)))
+	}
+}
+
+/// Infers the type of a field which has no specified type
+fn infer(field: String) -> Value {
+	match field.as_str() {
+		"true" => Value::Bool(true),
+		"false" => Value::Bool(false),
+		// Keep leading zeros, as in postal codes or phone numbers
+		v if v.len() > 1 && v.starts_with('0') && !v.starts_with("0.") => Value::from(field),
+		v => match v.parse::<i64>() {
+			Ok(v) => Value::Number(Number::Int(v)),
+			Err(_) => match v.parse::<f64>() {
+				Ok(v) if v.is_finite() => Value::Number(Number::Float(v)),
+				_ => Value::from(field),
+			},
+		},
+	}
+}
+
+impl Datastore {
+	/// Imports a CSV file into a table, inserting the records in batches
+	#[instrument(level = "debug", target = "surrealdb::core::kvs::ds", skip_all)]
+	pub async fn import_csv<S>(
+		&self,
+		sess: &Session,
+		cfg: Config,
+		stream: S,
+	) -> Result<Vec<Response>, Error>
+	where
+		S: Stream<Item = Result<Bytes, Error>>,
+	{
+		let mut importer = Importer::new(cfg);
+		let mut stream = pin!(stream);
+		let mut records = Vec::new();
+		let mut out = Vec::new();
+		let mut complete = false;
+		while !complete {
+			match stream.next().await {
+				Some(chunk) => records.extend(importer.push(&chunk?)?),
+				None => {
+					records.extend(importer.finish()?);
+					complete = true;
+				}
+			}
+			// Insert each full batch of records
+			while records.len() >= *CSV_IMPORT_BATCH_SIZE || (complete && !records.is_empty()) {
+				let size = records.len().min(*CSV_IMPORT_BATCH_SIZE);
+				let batch = records.drain(..size).collect();
+				let stm = importer.statement(batch);
+				for res in self.process(stm.into(), sess, None).await? {
+					let failed = res.result.is_err();
+					out.push(res);
+					// Stop at the first batch which fails
+					if failed {
+						return Ok(out);
+					}
+				}
+			}
+		}
+		Ok(out)
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+
+	fn import(cfg: Config, chunks: &[&str]) -> Result<Value, Error> {
+		let mut importer = Importer::new(cfg);
+		let mut out = Vec::new();
+		for chunk in chunks {
+			out.extend(importer.push(chunk.as_bytes())?);
+		}
+		out.extend(importer.finish()?);
+		Ok(Value::from(out))
+	}
+
+	#[test]
+	fn import_with_header() {
+		let res =
+			import(Config::new("person"), &["id,name,age,score\n1,Tobie,33,1.5\n", "2,Jaime,,007"]);
+		let val = syn::value(
+			"[{ id: 1, name: 'Tobie', age: 33, score: 1.5f }, { id: 2, name: 'Jaime', score: '007' }]",
+		);
+		assert_eq!(res.unwrap(), val.unwrap());
+	}
+
+	#[test]
+	fn import_with_quotes() {
+		let res =
+			import(Config::new("person"), &["name,bio\r\n\"Doe, \"", "\"John\"\"\",\"a\nb\"\r\n"]);
+		let val = syn::value(r#"[{ name: 'Doe, "John"', bio: 'a\nb' }]"#);
+		assert_eq!(res.unwrap(), val.unwrap());
+	}
+
+	#[test]
+	fn import_with_columns_and_types() {
+		let mut cfg = Config::new("person");
+		cfg.delimiter = b';';
+		cfg.header = false;
+		cfg.columns = Some(vec!["name".to_owned(), "age".to_owned()]);
+		cfg.types.extend([column_type("name: string").unwrap(), column_type("age:float").unwrap()]);
+		let res = import(cfg, &["1;33\n"]);
+		let val = syn::value("[{ name: '1', age: 33f }]");
+		assert_eq!(res.unwrap(), val.unwrap());
+	}
+
+	#[test]
+	fn import_invalid() {
+		let res = import(Config::new("person"), &["name,age\nTobie\n"]);
+		assert!(matches!(
+			res,
+			Err(Error::CsvImport {
+				line: 2,
+				..
+			})
+		));
+		let res = import(Config::new("person"), &["name\n\"Tobie"]);
+		assert!(matches!(
+			res,
+			Err(Error::CsvImport {
+				line: 2,
+				..
+			})
+		));
+		let mut cfg = Config::new("person");
+		cfg.types.extend([column_type("age:int").unwrap()]);
+		let res = import(cfg, &["age\nabc\n"]);
+		assert!(matches!(
+			res,
+			Err(Error::CsvImport {
+				line: 2,
+				..
+			})
+		));
+		assert!(column_type("age").is_err());
+	}
+
+	#[test]
+	fn import_statement() {
+		let mut cfg = Config::new("person");
+		cfg.transform = Some(syn::value("{ name: string::uppercase(name) }").unwrap());
+		let importer = Importer::new(cfg);
+		let stm = importer.statement(vec![syn::value("{ name: 'tobie' }").unwrap()]);
+		assert_eq!(
+			stm.to_string(),
+			"INSERT INTO person (SELECT VALUE { name: string::uppercase(name) } FROM [{ name: 'tobie' }]) RETURN NONE"
+		);
+	}
+}
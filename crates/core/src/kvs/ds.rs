@@ -3,11 +3,17 @@ use super::tr::Transactor;
 use super::tx::Transaction;
 use super::version::Version;
 use crate::cf;
+use crate::cnf::EXPORT_BATCH_SIZE;
 use crate::ctx::MutableContext;
-#[cfg(feature = "jwks")]
+use crate::dbs::audit::AuditLog;
+#[cfg(any(feature = "jwks", feature = "http"))]
 use crate::dbs::capabilities::NetTarget;
 use crate::dbs::capabilities::{MethodTarget, RouteTarget};
+use crate::dbs::connections::Connections;
+use crate::dbs::memory::MemoryBudget;
 use crate::dbs::node::Timestamp;
+use crate::dbs::quota::{NamespacePermit, Namespaces, Quotas};
+use crate::dbs::slowlog::{Recorder, SlowLog};
 use crate::dbs::{
 	Attach, Capabilities, Executor, Notification, Options, Response, Session, Variables,
 };
@@ -21,12 +27,18 @@ use crate::kvs::cache::ds::Cache;
 use crate::kvs::clock::SizedClock;
 #[allow(unused_imports)]
 use crate::kvs::clock::SystemClock;
+use crate::kvs::compression::Compression;
+use crate::kvs::encryption::{Keyring, StorageKey};
 #[cfg(not(target_arch = "wasm32"))]
 use crate::kvs::index::IndexBuilder;
+#[cfg(not(target_arch = "wasm32"))]
+use crate::kvs::migrate::FieldMigrator;
+use crate::kvs::reencrypt::Reencryption;
 use crate::kvs::{LockType, LockType::*, TransactionType, TransactionType::*};
 use crate::sql::{statements::DefineUserStatement, Base, Query, Value};
 use crate::syn;
 use crate::syn::parser::{Parser, PartialResult};
+use crate::testing::{Faults, Generator};
 use async_channel::{Receiver, Sender};
 use bytes::Bytes;
 use futures::{Future, Stream};
@@ -67,12 +79,30 @@ pub struct Datastore {
 	strict: bool,
 	/// Whether authentication is enabled on this datastore.
 	auth_enabled: bool,
+	/// Whether queries on this datastore are prevented from modifying data.
+	read_only: bool,
 	/// The maximum duration timeout for running multiple statements in a query.
 	query_timeout: Option<Duration>,
 	/// The maximum duration timeout for running multiple statements in a transaction.
 	transaction_timeout: Option<Duration>,
 	/// The security and feature capabilities for this datastore.
 	capabilities: Capabilities,
+	/// The keys used to encrypt the data keys of encrypted fields.
+	encryption: Option<Arc<Keyring>>,
+	/// The log of statements which took too long to run.
+	slow_log: Option<Arc<SlowLog>>,
+	/// The log of audited authentication, schema, and data events.
+	audit_log: Option<Arc<AuditLog>>,
+	/// The registry of active client connections.
+	connections: Arc<Connections>,
+	/// The query quotas applied to the record users of each access method.
+	quotas: Option<Arc<Quotas>>,
+	/// The queries running in each namespace, checked against its limits.
+	namespaces: Arc<Namespaces>,
+	/// The memory budgets of the intermediate results of statements.
+	memory_budget: Option<Arc<MemoryBudget>>,
+	/// The generator of the current time and random ids of statements.
+	generator: Option<Arc<dyn Generator>>,
 	// Whether this datastore enables live query notifications to subscribers.
 	notification_channel: Option<(Sender<Notification>, Receiver<Notification>)>,
 	// The index store cache
@@ -82,6 +112,9 @@ pub struct Datastore {
 	// The index asynchronous builder
 	#[cfg(not(target_arch = "wasm32"))]
 	index_builder: IndexBuilder,
+	// The asynchronous field migrator
+	#[cfg(not(target_arch = "wasm32"))]
+	field_migrator: FieldMigrator,
 	#[cfg(feature = "jwks")]
 	// The JWKS object cache
 	jwks_cache: Arc<RwLock<JwksCache>>,
@@ -96,6 +129,12 @@ pub(super) struct TransactionFactory {
 	clock: Arc<SizedClock>,
 	// The inner datastore type
 	flavor: Arc<DatastoreFlavor>,
+	// The key used to encrypt all values written to storage
+	cipher: Option<Arc<StorageKey>>,
+	// The compression applied to values written to storage
+	compression: Option<Compression>,
+	// The faults injected into transactions
+	faults: Option<Arc<Faults>>,
 }
 
 impl TransactionFactory {
@@ -161,8 +200,12 @@ impl TransactionFactory {
 		Ok(Transaction::new(Transactor {
 			inner,
 			stash: super::stash::Stash::default(),
+			savepoints: super::savepoint::UserSavePoints::default(),
 			cf: cf::Writer::new(),
 			clock: self.clock.clone(),
+			cipher: self.cipher.clone(),
+			compression: self.compression,
+			faults: self.faults.clone(),
 		}))
 	}
 }
@@ -259,13 +302,24 @@ impl Datastore {
 			id: self.id,
 			strict: self.strict,
 			auth_enabled: self.auth_enabled,
+			read_only: self.read_only,
 			query_timeout: self.query_timeout,
 			transaction_timeout: self.transaction_timeout,
 			capabilities: self.capabilities,
+			encryption: self.encryption,
+			slow_log: self.slow_log,
+			audit_log: self.audit_log,
+			connections: self.connections,
+			quotas: self.quotas,
+			namespaces: self.namespaces,
+			memory_budget: self.memory_budget,
+			generator: self.generator,
 			notification_channel: self.notification_channel,
 			index_stores: Default::default(),
 			#[cfg(not(target_arch = "wasm32"))]
 			index_builder: IndexBuilder::new(self.transaction_factory.clone()),
+			#[cfg(not(target_arch = "wasm32"))]
+			field_migrator: FieldMigrator::new(self.transaction_factory.clone()),
 			#[cfg(feature = "jwks")]
 			jwks_cache: Arc::new(Default::default()),
 			#[cfg(storage)]
@@ -415,19 +469,33 @@ impl Datastore {
 			let tf = TransactionFactory {
 				clock,
 				flavor: Arc::new(flavor),
+				cipher: None,
+				compression: None,
+				faults: None,
 			};
 			Self {
 				id: Uuid::new_v4(),
 				transaction_factory: tf.clone(),
 				strict: false,
 				auth_enabled: false,
+				read_only: false,
 				query_timeout: None,
 				transaction_timeout: None,
 				notification_channel: None,
 				capabilities: Capabilities::default(),
+				encryption: None,
+				slow_log: None,
+				audit_log: None,
+				connections: Arc::default(),
+				quotas: None,
+				namespaces: Arc::default(),
+				memory_budget: None,
+				generator: None,
 				index_stores: IndexStores::default(),
 				#[cfg(not(target_arch = "wasm32"))]
-				index_builder: IndexBuilder::new(tf),
+				index_builder: IndexBuilder::new(tf.clone()),
+				#[cfg(not(target_arch = "wasm32"))]
+				field_migrator: FieldMigrator::new(tf),
 				#[cfg(feature = "jwks")]
 				jwks_cache: Arc::new(RwLock::new(JwksCache::new())),
 				#[cfg(storage)]
@@ -473,12 +541,87 @@ impl Datastore {
 		self
 	}
 
+	/// Set whether queries are prevented from modifying data on this Datastore
+	pub fn with_read_only(mut self, read_only: bool) -> Self {
+		self.read_only = read_only;
+		self
+	}
+
 	/// Set specific capabilities for this Datastore
 	pub fn with_capabilities(mut self, caps: Capabilities) -> Self {
 		self.capabilities = caps;
 		self
 	}
 
+	/// Set the keys used to encrypt the data keys of encrypted fields
+	pub fn with_encryption(mut self, keyring: Option<Keyring>) -> Self {
+		self.encryption = keyring.map(Arc::new);
+		self
+	}
+
+	/// Set the log of statements which take too long to run
+	pub fn with_slow_log(mut self, log: Option<SlowLog>) -> Self {
+		self.slow_log = log.map(Arc::new);
+		self
+	}
+
+	/// Set the log of audited authentication, schema, and data events
+	pub fn with_audit_log(mut self, log: Option<AuditLog>) -> Self {
+		self.audit_log = log.map(Arc::new);
+		self
+	}
+
+	/// Set the query quotas applied to the record users of each access method
+	pub fn with_quotas(mut self, quotas: Option<Quotas>) -> Self {
+		self.quotas = quotas.map(Arc::new);
+		self
+	}
+
+	/// Set the memory budgets of the intermediate results of statements
+	pub fn with_memory_budget(mut self, budget: Option<MemoryBudget>) -> Self {
+		self.memory_budget = budget.map(Arc::new);
+		self
+	}
+
+	/// Set the key used to encrypt all values which are written to storage
+	pub fn with_storage_encryption(mut self, key: Option<StorageKey>) -> Self {
+		self.transaction_factory.cipher = key.map(Arc::new);
+		#[cfg(not(target_arch = "wasm32"))]
+		{
+			self.index_builder = IndexBuilder::new(self.transaction_factory.clone());
+			self.field_migrator = FieldMigrator::new(self.transaction_factory.clone());
+		}
+		self
+	}
+
+	/// Set the compression applied to values which are written to storage
+	pub fn with_compression(mut self, compression: Option<Compression>) -> Self {
+		self.transaction_factory.compression = compression;
+		#[cfg(not(target_arch = "wasm32"))]
+		{
+			self.index_builder = IndexBuilder::new(self.transaction_factory.clone());
+			self.field_migrator = FieldMigrator::new(self.transaction_factory.clone());
+		}
+		self
+	}
+
+	/// Set the faults which are injected into the transactions of the datastore
+	pub fn with_faults(mut self, faults: Option<Arc<Faults>>) -> Self {
+		self.transaction_factory.faults = faults;
+		#[cfg(not(target_arch = "wasm32"))]
+		{
+			self.index_builder = IndexBuilder::new(self.transaction_factory.clone());
+			self.field_migrator = FieldMigrator::new(self.transaction_factory.clone());
+		}
+		self
+	}
+
+	/// Set the generator of the current time and random ids of statements
+	pub fn with_generator(mut self, generator: Option<Arc<dyn Generator>>) -> Self {
+		self.generator = generator;
+		self
+	}
+
 	#[cfg(storage)]
 	/// Set a temporary directory for ordering of large result sets
 	pub fn with_temporary_directory(mut self, path: Option<PathBuf>) -> Self {
@@ -495,6 +638,11 @@ impl Datastore {
 		self.auth_enabled
 	}
 
+	/// Are queries prevented from modifying data on this Datastore?
+	pub fn is_read_only(&self) -> bool {
+		self.read_only
+	}
+
 	pub fn id(&self) -> Uuid {
 		self.id
 	}
@@ -511,11 +659,21 @@ impl Datastore {
 	}
 
 	/// Does the datastore allow connections to a network target?
-	#[cfg(feature = "jwks")]
+	#[cfg(any(feature = "jwks", feature = "http"))]
 	pub(crate) fn allows_network_target(&self, net_target: &NetTarget) -> bool {
 		self.capabilities.allows_network_target(net_target)
 	}
 
+	/// The log of audited events, if one is configured
+	pub(crate) fn audit_log(&self) -> Option<&AuditLog> {
+		self.audit_log.as_deref()
+	}
+
+	/// The registry of active client connections
+	pub fn connections(&self) -> &Connections {
+		&self.connections
+	}
+
 	#[cfg(feature = "jwks")]
 	pub(crate) fn jwks_cache(&self) -> &Arc<RwLock<JwksCache>> {
 		&self.jwks_cache
@@ -528,6 +686,15 @@ impl Datastore {
 	// Initialise the cluster and run bootstrap utilities
 	#[instrument(err, level = "trace", target = "surrealdb::core::kvs::ds", skip_all)]
 	pub async fn check_version(&self) -> Result<Version, Error> {
+		// Check that a rekey has not been interrupted
+		let txn = self.transaction(Read, Optimistic).await?;
+		let rekey = catch!(txn, txn.exists(crate::key::rekey::new(), None).await);
+		txn.cancel().await?;
+		if rekey {
+			return Err(Error::RekeyInProgress);
+		}
+		// Check that the storage is read with compression if necessary
+		self.check_compression().await?;
 		let version = self.get_version().await?;
 		// Check we are running the latest version
 		if !version.is_latest() {
@@ -537,6 +704,140 @@ impl Datastore {
 		Ok(version)
 	}
 
+	/// Check that compression is enabled if, and only if, the storage was
+	/// created with compression enabled. Compressed values can not otherwise
+	/// be distinguished from uncompressed values, so compression can only be
+	/// enabled when the storage is first created.
+	async fn check_compression(&self) -> Result<(), Error> {
+		let txn = self.transaction(Write, Pessimistic).await?.enclose();
+		let key = crate::key::compression::new();
+		let compressed = catch!(txn, txn.exists(key.clone(), None).await);
+		match (compressed, self.transaction_factory.compression.is_some()) {
+			// The storage is compressed, but compression is not enabled
+			(true, false) => {
+				catch!(txn, txn.cancel().await);
+				Err(Error::Ds(
+					"The storage is compressed, so a compression algorithm must be specified"
+						.to_owned(),
+				))
+			}
+			// Compression is enabled, so check that the storage is new
+			(false, true) => {
+				let rng = crate::key::version::proceeding();
+				let keys = catch!(txn, txn.keys(rng, 1, None).await);
+				if !keys.is_empty() {
+					catch!(txn, txn.cancel().await);
+					return Err(Error::Ds(
+						"Compression can only be enabled when the storage is created".to_owned(),
+					));
+				}
+				catch!(txn, txn.replace(key, Vec::<u8>::new()).await);
+				catch!(txn, txn.commit().await);
+				Ok(())
+			}
+			// The storage is read as it was written
+			_ => {
+				catch!(txn, txn.cancel().await);
+				Ok(())
+			}
+		}
+	}
+
+	/// Rewrite every value in the datastore with a new storage encryption key.
+	///
+	/// Values are read using the current storage encryption key, if one is
+	/// set, and are written using the new key, or are written unencrypted if
+	/// no new key is specified. This should only be run while the datastore
+	/// is not being used by any other process.
+	///
+	/// The values are rewritten in batches, and the last key which has been
+	/// rewritten is stored along with each batch. If the rekey is interrupted
+	/// it continues from that key when run again with the same keys, and the
+	/// datastore can not otherwise be used until the rekey has completed.
+	#[instrument(err, level = "trace", target = "surrealdb::core::kvs::ds", skip_all)]
+	pub async fn rekey(&self, key: Option<StorageKey>) -> Result<u64, Error> {
+		// The progress records which key the values are rewritten with
+		let id = key.as_ref().map(|k| k.id().to_vec()).unwrap_or_default();
+		// Writes are encrypted with the new key
+		let tf = TransactionFactory {
+			cipher: key.map(Arc::new),
+			..self.transaction_factory.clone()
+		};
+		// Check if a previous rekey was interrupted
+		let txn = self.transaction(Read, Optimistic).await?;
+		let progress = catch!(txn, txn.get(crate::key::rekey::new(), None).await);
+		txn.cancel().await?;
+		let beg = match progress {
+			// Continue after the last key which was rewritten
+			Some(v) => match v.split_first() {
+				Some((&len, v)) if v.len() >= len as usize && v[..len as usize] == id[..] => {
+					[&v[len as usize..], &[0x00]].concat()
+				}
+				_ => {
+					return Err(Error::Ds(
+						"A rekey with a different storage encryption key is in progress".to_owned(),
+					))
+				}
+			},
+			// Start a new rekey from the beginning
+			None => {
+				self.check_version().await?;
+				vec![0x00]
+			}
+		};
+		let mut next = Some(beg..vec![0xff]);
+		let mut count = 0;
+		while let Some(rng) = next {
+			// Read the next batch with the current key
+			let txn = self.transaction(Read, Optimistic).await?;
+			let batch = catch!(txn, txn.batch(rng, *EXPORT_BATCH_SIZE, true, None).await);
+			txn.cancel().await?;
+			next = batch.next;
+			// Write the batch with the new key
+			let txn = tf.transaction(Write, Optimistic).await?;
+			let mut last = None;
+			for (k, v) in batch.values {
+				if crate::key::rekey::is(&k) {
+					continue;
+				}
+				catch!(txn, txn.set(k.clone(), v, None).await);
+				last = Some(k);
+				count += 1;
+			}
+			// Store the progress along with the batch
+			if let Some(last) = last {
+				let val = [&[id.len() as u8], id.as_slice(), last.as_slice()].concat();
+				catch!(txn, txn.set(crate::key::rekey::new(), val, None).await);
+			}
+			txn.commit().await?;
+		}
+		// The rekey has completed
+		let txn = tf.transaction(Write, Optimistic).await?;
+		catch!(txn, txn.del(crate::key::rekey::new()).await);
+		txn.commit().await?;
+		Ok(count)
+	}
+
+	/// Re-encrypt the values of encrypted fields with a new data key.
+	///
+	/// When the field encryption key is rotated, the data keys of the datastore
+	/// are re-encrypted with the new key, and a new data key is generated. The
+	/// field values which were encrypted with a previous data key are then
+	/// re-encrypted with the new data key, after which the previous data keys
+	/// are removed. This is run in the background when the datastore starts,
+	/// and returns the number of records which were re-encrypted.
+	#[instrument(err, level = "trace", target = "surrealdb::core::kvs::ds", skip_all)]
+	pub async fn reencrypt(&self) -> Result<u64, Error> {
+		let Some(keyring) = self.encryption.clone() else {
+			return Ok(0);
+		};
+		let task = Reencryption::new(self.transaction_factory.clone(), keyring);
+		match task.rotate().await? {
+			true => task.compute().await,
+			false => Ok(0),
+		}
+	}
+
 	// Initialise the cluster and run bootstrap utilities
 	#[instrument(err, level = "trace", target = "surrealdb::core::kvs::ds", skip_all)]
 	pub async fn get_version(&self) -> Result<Version, Error> {
@@ -633,10 +934,85 @@ impl Datastore {
 		self.expire_nodes().await?;
 		// Remove archived nodes
 		self.remove_nodes().await?;
+		// Resume any interrupted renames
+		#[cfg(not(target_arch = "wasm32"))]
+		self.resume_moves().await?;
+		// Rotate the data keys of encrypted fields
+		self.rotate_encryption().await?;
 		// Everything ok
 		Ok(())
 	}
 
+	/// Re-encrypt the data keys of encrypted fields with the current field
+	/// encryption key, if they were encrypted with a previous key, and then
+	/// re-encrypt the field values with a new data key in the background.
+	async fn rotate_encryption(&self) -> Result<(), Error> {
+		let Some(keyring) = self.encryption.clone() else {
+			return Ok(());
+		};
+		let task = Reencryption::new(self.transaction_factory.clone(), keyring);
+		if !task.rotate().await? {
+			return Ok(());
+		}
+		info!(target: TARGET, "Re-encrypting the values of encrypted fields");
+		#[cfg(not(target_arch = "wasm32"))]
+		tokio::task::spawn(async move {
+			match task.compute().await {
+				Ok(count) => info!(target: TARGET, "Re-encrypted the values of {count} records"),
+				Err(err) => error!(target: TARGET, "Unable to re-encrypt encrypted fields: {err}"),
+			}
+		});
+		#[cfg(target_arch = "wasm32")]
+		task.compute().await?;
+		Ok(())
+	}
+
+	/// Resume moving the records of renamed tables, and the keys of renamed
+	/// databases, where the move was interrupted before it had completed.
+	#[cfg(not(target_arch = "wasm32"))]
+	async fn resume_moves(&self) -> Result<(), Error> {
+		// Find the moves which have not completed
+		let mut tables = Vec::new();
+		let mut databases = Vec::new();
+		let txn = self.transaction(Read, Optimistic).await?;
+		for ns in catch!(txn, txn.all_ns().await).iter() {
+			let ns = &ns.name;
+			for db in catch!(txn, txn.all_db(ns).await).iter() {
+				let db = &db.name;
+				let key = crate::key::database::mv::new(ns, db);
+				if let Some(v) = catch!(txn, txn.get(key, None).await) {
+					if let Some((from, count)) = crate::kvs::move_remaining(&Value::from(&v)) {
+						databases.push((ns.clone(), from, db.clone(), count));
+					}
+				}
+				for tb in catch!(txn, txn.all_tb(ns, db, None).await).iter() {
+					let tb = &tb.name;
+					let key = crate::key::table::mv::new(ns, db, tb);
+					if let Some(v) = catch!(txn, txn.get(key, None).await) {
+						if let Some((from, count)) = crate::kvs::move_remaining(&Value::from(&v)) {
+							tables.push((ns.clone(), db.clone(), from, tb.clone(), count));
+						}
+					}
+				}
+			}
+		}
+		txn.cancel().await?;
+		// Continue each move in the background
+		for (ns, from, name, count) in databases {
+			info!(target: TARGET, "Resuming moving the keys of database `{from}` to `{name}`");
+			self.field_migrator.migrate_database(&ns, &from, &name, count)?;
+		}
+		for (ns, db, from, name, count) in tables {
+			info!(target: TARGET, "Resuming moving the records of table `{from}` to `{name}`");
+			let sess = Session::owner().with_ns(&ns).with_db(&db);
+			let opt = self.setup_options(&sess);
+			let mut ctx = self.setup_ctx()?;
+			sess.context(&mut ctx);
+			self.field_migrator.migrate_table(&ctx.freeze(), opt, &from, &name, count)?;
+		}
+		Ok(())
+	}
+
 	/// Run the background task to update node registration information
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::ds", skip(self))]
 	pub async fn node_membership_update(&self) -> Result<(), Error> {
@@ -703,6 +1079,93 @@ impl Datastore {
 		Ok(())
 	}
 
+	/// Run the background task to delete expired records
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::ds", skip(self))]
+	pub async fn record_expiry_process(&self) -> Result<(), Error> {
+		// Output function invocation details to logs
+		trace!(target: TARGET, "Running record expiry cleanup");
+		// Calculate the current system time
+		let ts = SystemTime::now()
+			.duration_since(UNIX_EPOCH)
+			.map_err(|e| {
+				Error::Internal(format!("Clock may have gone backwards: {:?}", e.duration()))
+			})?
+			.as_secs();
+		// Delete expired records from all tables
+		self.record_expiry_cleanup(ts).await?;
+		// Everything ok
+		Ok(())
+	}
+
+	/// Run the background task to delete expired records
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::ds", skip(self))]
+	pub async fn record_expiry_process_at(&self, ts: u64) -> Result<(), Error> {
+		// Output function invocation details to logs
+		trace!(target: TARGET, "Running record expiry cleanup");
+		// Delete expired records from all tables
+		self.record_expiry_cleanup(ts).await?;
+		// Everything ok
+		Ok(())
+	}
+
+	/// Run the background task to process scheduled events
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::ds", skip(self))]
+	pub async fn event_schedule_process(&self) -> Result<(), Error> {
+		// Output function invocation details to logs
+		trace!(target: TARGET, "Running scheduled events");
+		// Calculate the current system time
+		let ts = SystemTime::now()
+			.duration_since(UNIX_EPOCH)
+			.map_err(|e| {
+				Error::Internal(format!("Clock may have gone backwards: {:?}", e.duration()))
+			})?
+			.as_secs();
+		// Run any scheduled events which are due
+		self.event_schedule_run(ts).await?;
+		// Everything ok
+		Ok(())
+	}
+
+	/// Run the background task to process scheduled events
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::ds", skip(self))]
+	pub async fn event_schedule_process_at(&self, ts: u64) -> Result<(), Error> {
+		// Output function invocation details to logs
+		trace!(target: TARGET, "Running scheduled events");
+		// Run any scheduled events which are due
+		self.event_schedule_run(ts).await?;
+		// Everything ok
+		Ok(())
+	}
+
+	/// Run the background task to deliver queued webhooks
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::ds", skip(self))]
+	pub async fn webhook_delivery_process(&self) -> Result<(), Error> {
+		// Output function invocation details to logs
+		trace!(target: TARGET, "Running webhook delivery");
+		// Calculate the current system time
+		let ts = SystemTime::now()
+			.duration_since(UNIX_EPOCH)
+			.map_err(|e| {
+				Error::Internal(format!("Clock may have gone backwards: {:?}", e.duration()))
+			})?
+			.as_secs();
+		// Deliver any queued webhooks which are due
+		self.webhook_delivery_run(ts).await?;
+		// Everything ok
+		Ok(())
+	}
+
+	/// Run the background task to deliver queued webhooks
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::ds", skip(self))]
+	pub async fn webhook_delivery_process_at(&self, ts: u64) -> Result<(), Error> {
+		// Output function invocation details to logs
+		trace!(target: TARGET, "Running webhook delivery");
+		// Deliver any queued webhooks which are due
+		self.webhook_delivery_run(ts).await?;
+		// Everything ok
+		Ok(())
+	}
+
 	/// Run the datastore shutdown tasks, perfoming any necessary cleanup
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::ds", skip(self))]
 	pub async fn shutdown(&self) -> Result<(), Error> {
@@ -815,6 +1278,10 @@ impl Datastore {
 		sess.context(&mut ctx);
 		// Store the query variables
 		vars.attach(&mut ctx)?;
+		// Count the query against the limits of the namespace
+		let _namespace = self.acquire_namespace(sess).await?;
+		// Track the query against the connection which is running it
+		let _query = self.connections.track(sess, ctx.add_cancel());
 		// Process all statements
 
 		let mut offset = 0;
@@ -940,8 +1407,44 @@ impl Datastore {
 		sess.context(&mut ctx);
 		// Store the query variables
 		vars.attach(&mut ctx)?;
+		// Count the query against the quotas of the session
+		let _permit = match &self.quotas {
+			Some(quotas) => quotas.acquire(sess)?,
+			None => None,
+		};
+		// Count the query against the limits of the namespace
+		let _namespace = self.acquire_namespace(sess).await?;
+		// Track the query against the connection which is running it
+		let _query = self.connections.track(sess, ctx.add_cancel());
 		// Process all statements
-		Executor::execute(self, ctx.freeze(), opt, ast).await
+		let mut res = Executor::execute(self, ctx.freeze(), opt, ast).await?;
+		// Limit the size of the results
+		if let Some(quotas) = &self.quotas {
+			quotas.check(sess, &mut res);
+		}
+		Ok(res)
+	}
+
+	/// Count a query against the limits of the namespace of a session
+	async fn acquire_namespace(&self, sess: &Session) -> Result<Option<NamespacePermit>, Error> {
+		// Queries without a namespace are not limited
+		let Some(ns) = sess.ns.as_deref() else {
+			return Ok(None);
+		};
+		// Fetch the limits of the namespace
+		let txn = self.transaction(Read, Optimistic).await?;
+		let limits = match txn.get_ns(ns).await {
+			Ok(v) => v.limits.clone(),
+			Err(Error::NsNotFound {
+				..
+			}) => Default::default(),
+			Err(e) => {
+				txn.cancel().await?;
+				return Err(e);
+			}
+		};
+		txn.cancel().await?;
+		self.namespaces.acquire(ns, &limits).map(Some)
 	}
 
 	/// Ensure a SQL [`Value`] is fully computed
@@ -989,6 +1492,8 @@ impl Datastore {
 		let mut ctx = MutableContext::default();
 		// Set context capabilities
 		ctx.add_capabilities(self.capabilities.clone());
+		// Set the field encryption keys
+		ctx.add_encryption(self.encryption.clone());
 		// Set the global query timeout
 		if let Some(timeout) = self.query_timeout {
 			ctx.add_timeout(timeout)?;
@@ -1001,6 +1506,10 @@ impl Datastore {
 		sess.context(&mut ctx);
 		// Store the query variables
 		vars.attach(&mut ctx)?;
+		// Values which modify data can not be computed in read-only mode
+		if val.writeable() && self.read_only {
+			return Err(Error::ReadOnly);
+		}
 		// Start a new transaction
 		let txn = self.transaction(val.writeable().into(), Optimistic).await?.enclose();
 		// Store the transaction
@@ -1061,6 +1570,8 @@ impl Datastore {
 		let mut ctx = MutableContext::default();
 		// Set context capabilities
 		ctx.add_capabilities(self.capabilities.clone());
+		// Set the field encryption keys
+		ctx.add_encryption(self.encryption.clone());
 		// Set the global query timeout
 		if let Some(timeout) = self.query_timeout {
 			ctx.add_timeout(timeout)?;
@@ -1073,6 +1584,10 @@ impl Datastore {
 		sess.context(&mut ctx);
 		// Store the query variables
 		vars.attach(&mut ctx)?;
+		// Values which modify data can not be computed in read-only mode
+		if val.writeable() && self.read_only {
+			return Err(Error::ReadOnly);
+		}
 		// Start a new transaction
 		let txn = self.transaction(val.writeable().into(), Optimistic).await?.enclose();
 		// Store the transaction
@@ -1212,9 +1727,23 @@ impl Datastore {
 			self.cache.clone(),
 			#[cfg(not(target_arch = "wasm32"))]
 			self.index_builder.clone(),
+			#[cfg(not(target_arch = "wasm32"))]
+			self.field_migrator.clone(),
 			#[cfg(storage)]
 			self.temporary_directory.clone(),
 		)?;
+		// Set the field encryption keys
+		ctx.add_encryption(self.encryption.clone());
+		// Set the slow query log
+		ctx.add_slow_log(self.slow_log.as_ref().map(Recorder::new));
+		// Set the registry of active connections
+		ctx.add_connections(Some(self.connections.clone()));
+		// Set the queries running in each namespace
+		ctx.add_namespaces(Some(self.namespaces.clone()));
+		// Set the memory budgets of the intermediate results
+		ctx.add_memory_budget(self.memory_budget.clone());
+		// Set the generator of the current time and random ids
+		ctx.add_generator(self.generator.clone());
 		// Setup the notification channel
 		if let Some(channel) = &self.notification_channel {
 			ctx.add_notifications(Some(&channel.0));
@@ -0,0 +1,519 @@
+//! Encrypts the values of fields which are defined as ENCRYPTED, before the
+//! record is written to the storage engine, and decrypts them when read. The
+//! values are encrypted with a data key, which is itself encrypted with the
+//! master key which is specified when the datastore is started.
+//! Also encrypts every value which is written to the storage engine, when
+//! the datastore is started with a storage encryption key. Only the values
+//! are encrypted: the keys, which contain the namespace, database, and table
+//! names, the record ids, and the indexed values, are stored in plaintext so
+//! that they can still be scanned in order.
+use crate::err::Error;
+use crate::key::debug::Sprintable;
+use crate::kvs::Val;
+use crate::sql::{Bytes, Value};
+use revision::Revisioned;
+use ring::aead::{Aad, LessSafeKey, Nonce, UnboundKey, AES_256_GCM, MAX_TAG_LEN, NONCE_LEN};
+use ring::hkdf::{Prk, Salt, HKDF_SHA256};
+use ring::rand::{SecureRandom, SystemRandom};
+use sha2::{Digest, Sha256};
+use std::fmt::{self, Debug};
+use std::sync::{Arc, Mutex};
+
+/// The prefix which identifies an encrypted value
+const PREFIX: &[u8] = b"SENC";
+/// The version of the encrypted value format
+const VERSION: u8 = 1;
+/// The length of the identifier of the key used to encrypt a value
+const KEY_ID_LEN: usize = 8;
+/// The length of the header before the encrypted data
+const HEADER_LEN: usize = PREFIX.len() + 1 + KEY_ID_LEN + NONCE_LEN;
+/// The length of the envelope header before the data encrypted in storage
+const STORAGE_HEADER_LEN: usize = 1 + KEY_ID_LEN + NONCE_LEN;
+/// The length of each data key
+const DATA_KEY_LEN: usize = 32;
+/// The length of each stored data key, encrypted with a master key
+const WRAPPED_LEN: usize = KEY_ID_LEN * 2 + NONCE_LEN + DATA_KEY_LEN + MAX_TAG_LEN;
+/// The salt used when deriving the field keys from the secret
+const FIELD_SALT: &[u8] = b"surrealdb-field-encryption";
+/// The salt used when deriving the storage keys from the secret
+const STORAGE_SALT: &[u8] = b"surrealdb-storage-encryption";
+
+/// Derives keys from a secret of 16, 24, or 32 bytes, which is the
+/// same format for both field encryption and on-disk encryption.
+fn derive(secret: &str, salt: &[u8]) -> Result<Prk, Error> {
+	if ![16, 24, 32].contains(&secret.len()) {
+		return Err(Error::EncryptionKeyInvalid(
+			"The key must be 16, 24, or 32 bytes long".to_owned(),
+		));
+	}
+	Ok(Salt::new(HKDF_SHA256, salt).extract(secret.as_bytes()))
+}
+
+/// An AES-256-GCM key, along with the identifier which
+/// is stored with each value which it is used to encrypt.
+struct Key {
+	id: [u8; KEY_ID_LEN],
+	key: LessSafeKey,
+	/// The bytes of the key, if it is a data key
+	secret: [u8; DATA_KEY_LEN],
+}
+
+impl Key {
+	/// Creates a master key from a secret of 16, 24, or 32 bytes
+	fn new(secret: &str) -> Result<Self, Error> {
+		let prk = derive(secret, FIELD_SALT)?;
+		let invalid = |_| Error::EncryptionKeyInvalid("The key is not valid".to_owned());
+		let key: UnboundKey =
+			prk.expand(&[b"key".as_slice()], &AES_256_GCM).map_err(invalid)?.into();
+		let mut id = [0; KEY_ID_LEN];
+		id.copy_from_slice(&Sha256::digest(secret.as_bytes())[..KEY_ID_LEN]);
+		Ok(Key {
+			id,
+			key: LessSafeKey::new(key),
+			secret: [0; DATA_KEY_LEN],
+		})
+	}
+
+	/// Generates a random data key, with a random identifier
+	fn generate(random: &SystemRandom) -> Result<Self, Error> {
+		let mut id = [0; KEY_ID_LEN];
+		random.fill(&mut id).map_err(|_| fail!("Unable to generate a data key"))?;
+		let mut secret = [0; DATA_KEY_LEN];
+		random.fill(&mut secret).map_err(|_| fail!("Unable to generate a data key"))?;
+		Self::from_secret(id, &secret)
+	}
+
+	/// Creates a data key from its identifier and bytes
+	fn from_secret(id: [u8; KEY_ID_LEN], secret: &[u8]) -> Result<Self, Error> {
+		let invalid = |_| Error::EncryptionKeyInvalid("The data key is not valid".to_owned());
+		let key = UnboundKey::new(&AES_256_GCM, secret).map_err(invalid)?;
+		let mut bytes = [0; DATA_KEY_LEN];
+		bytes.copy_from_slice(secret);
+		Ok(Key {
+			id,
+			key: LessSafeKey::new(key),
+			secret: bytes,
+		})
+	}
+}
+
+/// The master keys which encrypt the data keys of the datastore. Field values
+/// are encrypted with a data key, which is generated for each datastore, and
+/// which is stored encrypted with the current master key. The data keys can
+/// be decrypted with either the current master key or any of the previous
+/// master keys, so that the master key can be rotated. When the master key
+/// is rotated a new data key is generated, and the field values which were
+/// encrypted with a previous data key are re-encrypted in the background.
+pub struct Keyring {
+	current: Key,
+	previous: Vec<Key>,
+	random: SystemRandom,
+	/// The data keys which were last decrypted, along with their stored value
+	cache: Mutex<Option<(Val, Arc<DataKeys>)>>,
+}
+
+impl Debug for Keyring {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		f.debug_struct("Keyring").finish_non_exhaustive()
+	}
+}
+
+impl Keyring {
+	/// Creates a keyring from the current key, and any previous keys,
+	/// each specified as a secret of 16, 24, or 32 bytes.
+	pub fn new(current: &str, previous: &[String]) -> Result<Self, Error> {
+		Ok(Keyring {
+			current: Key::new(current)?,
+			previous: previous.iter().map(|v| Key::new(v)).collect::<Result<_, _>>()?,
+			random: SystemRandom::new(),
+			cache: Mutex::new(None),
+		})
+	}
+
+	/// Generates the data keys for a datastore, returning
+	/// the data keys, and the value which is stored.
+	pub(crate) fn generate(&self) -> Result<(Arc<DataKeys>, Val), Error> {
+		let keys = DataKeys {
+			current: Key::generate(&self.random)?,
+			previous: Vec::new(),
+			random: SystemRandom::new(),
+		};
+		let val = self.wrap(&keys)?;
+		Ok((Arc::new(keys), val))
+	}
+
+	/// Decrypts the stored data keys of a datastore
+	pub(crate) fn data_keys(&self, val: &[u8]) -> Result<Arc<DataKeys>, Error> {
+		let mut cache = self.cache.lock().unwrap_or_else(|e| e.into_inner());
+		if let Some((v, keys)) = cache.as_ref() {
+			if v == val {
+				return Ok(keys.clone());
+			}
+		}
+		let (keys, _) = self.unwrap(val)?;
+		let keys = Arc::new(keys);
+		*cache = Some((val.to_vec(), keys.clone()));
+		Ok(keys)
+	}
+
+	/// Checks whether the stored data keys were encrypted with a previous
+	/// master key. If so, a new data key is generated, and the data keys are
+	/// returned encrypted with the current master key, so that they can be
+	/// stored. The previous data keys are kept until the field values which
+	/// they encrypt have been re-encrypted.
+	pub(crate) fn rotate(&self, val: &[u8]) -> Result<Option<Val>, Error> {
+		let (mut keys, rotate) = self.unwrap(val)?;
+		if !rotate {
+			return Ok(None);
+		}
+		let current = Key::generate(&self.random)?;
+		keys.previous.insert(0, std::mem::replace(&mut keys.current, current));
+		self.wrap(&keys).map(Some)
+	}
+
+	/// Removes the previous data keys, once the field values
+	/// which they encrypt have been re-encrypted.
+	pub(crate) fn retire(&self, val: &[u8]) -> Result<Val, Error> {
+		let (mut keys, _) = self.unwrap(val)?;
+		keys.previous.clear();
+		self.wrap(&keys)
+	}
+
+	/// Encrypts the data keys with the current master key. Each data key is
+	/// stored as its identifier, followed by the identifier of the master key,
+	/// the nonce, and the encrypted data key. The current data key is first.
+	fn wrap(&self, keys: &DataKeys) -> Result<Val, Error> {
+		let mut out = Vec::with_capacity(WRAPPED_LEN * (keys.previous.len() + 1));
+		for key in std::iter::once(&keys.current).chain(keys.previous.iter()) {
+			let mut nonce = [0; NONCE_LEN];
+			self.random.fill(&mut nonce).map_err(|_| fail!("Unable to generate a nonce"))?;
+			let mut data = key.secret.to_vec();
+			self.current
+				.key
+				.seal_in_place_append_tag(
+					Nonce::assume_unique_for_key(nonce),
+					Aad::from(key.id),
+					&mut data,
+				)
+				.map_err(|_| fail!("Unable to encrypt the data key"))?;
+			out.extend_from_slice(&key.id);
+			out.extend_from_slice(&self.current.id);
+			out.extend_from_slice(&nonce);
+			out.extend_from_slice(&data);
+		}
+		Ok(out)
+	}
+
+	/// Decrypts the data keys, returning the data keys, and
+	/// whether any were encrypted with a previous master key.
+	fn unwrap(&self, val: &[u8]) -> Result<(DataKeys, bool), Error> {
+		let invalid = || {
+			Error::EncryptionKeyInvalid(
+				"The data keys can not be decrypted with the encryption key, or any of the previous encryption keys".to_owned(),
+			)
+		};
+		if val.is_empty() || val.len() % WRAPPED_LEN != 0 {
+			return Err(invalid());
+		}
+		let mut keys = Vec::with_capacity(val.len() / WRAPPED_LEN);
+		let mut rotate = false;
+		for v in val.chunks(WRAPPED_LEN) {
+			let (id, v) = v.split_at(KEY_ID_LEN);
+			let (master, v) = v.split_at(KEY_ID_LEN);
+			let (nonce, data) = v.split_at(NONCE_LEN);
+			// Find the master key which was used to encrypt the data key
+			let master = match master == self.current.id {
+				true => &self.current,
+				false => match self.previous.iter().find(|k| k.id == master) {
+					Some(key) => {
+						rotate = true;
+						key
+					}
+					None => return Err(invalid()),
+				},
+			};
+			let mut id_bytes = [0; KEY_ID_LEN];
+			id_bytes.copy_from_slice(id);
+			let mut nonce_bytes = [0; NONCE_LEN];
+			nonce_bytes.copy_from_slice(nonce);
+			let mut data = data.to_vec();
+			let secret = master
+				.key
+				.open_in_place(
+					Nonce::assume_unique_for_key(nonce_bytes),
+					Aad::from(id_bytes),
+					&mut data,
+				)
+				.map_err(|_| invalid())?;
+			keys.push(Key::from_secret(id_bytes, secret)?);
+		}
+		let current = keys.remove(0);
+		let keys = DataKeys {
+			current,
+			previous: keys,
+			random: SystemRandom::new(),
+		};
+		Ok((keys, rotate))
+	}
+}
+
+/// The data keys which are used to encrypt and decrypt field values. Values
+/// are always encrypted with the current data key, and can be decrypted with
+/// either the current data key or any of the previous data keys.
+pub(crate) struct DataKeys {
+	current: Key,
+	previous: Vec<Key>,
+	random: SystemRandom,
+}
+
+impl DataKeys {
+	/// Checks whether there are previous data keys, which
+	/// encrypt values which have not yet been re-encrypted.
+	pub(crate) fn has_previous(&self) -> bool {
+		!self.previous.is_empty()
+	}
+
+	/// Encrypts a value with the current key. The additional data
+	/// identifies where the value is stored, so that an encrypted
+	/// value can not be moved to another field or record.
+	pub(crate) fn encrypt(&self, val: &Value, aad: &str) -> Result<Value, Error> {
+		let mut nonce = [0; NONCE_LEN];
+		self.random.fill(&mut nonce).map_err(|_| fail!("Unable to generate a nonce"))?;
+		let mut data = Vec::new();
+		val.serialize_revisioned(&mut data)?;
+		self.current
+			.key
+			.seal_in_place_append_tag(
+				Nonce::assume_unique_for_key(nonce),
+				Aad::from(aad.as_bytes()),
+				&mut data,
+			)
+			.map_err(|_| fail!("Unable to encrypt the value"))?;
+		let mut out = Vec::with_capacity(HEADER_LEN + data.len());
+		out.extend_from_slice(PREFIX);
+		out.push(VERSION);
+		out.extend_from_slice(&self.current.id);
+		out.extend_from_slice(&nonce);
+		out.extend_from_slice(&data);
+		Ok(Value::Bytes(Bytes(out)))
+	}
+
+	/// Decrypts a value which was encrypted with the same additional data,
+	/// returning the value, and whether it was encrypted with a previous key.
+	pub(crate) fn decrypt(&self, val: &[u8], aad: &str) -> Result<(Value, bool), Error> {
+		// Check the format of the encrypted value
+		if val.len() < HEADER_LEN || !val.starts_with(PREFIX) || val[PREFIX.len()] != VERSION {
+			return Err(Error::Decryption(aad.to_owned()));
+		}
+		let (head, data) = val.split_at(HEADER_LEN);
+		let (id, nonce) = head[PREFIX.len() + 1..].split_at(KEY_ID_LEN);
+		// Find the key which was used to encrypt the value
+		let (key, rotate) = match id == self.current.id {
+			true => (&self.current, false),
+			false => match self.previous.iter().find(|k| k.id == id) {
+				Some(key) => (key, true),
+				None => return Err(Error::Decryption(aad.to_owned())),
+			},
+		};
+		let mut nonce_bytes = [0; NONCE_LEN];
+		nonce_bytes.copy_from_slice(nonce);
+		let mut data = data.to_vec();
+		let data = key
+			.key
+			.open_in_place(
+				Nonce::assume_unique_for_key(nonce_bytes),
+				Aad::from(aad.as_bytes()),
+				&mut data,
+			)
+			.map_err(|_| Error::Decryption(aad.to_owned()))?;
+		let val = Value::deserialize_revisioned(&mut &data[..])?;
+		Ok((val, rotate))
+	}
+}
+
+/// The key used to encrypt all values which are written to the storage
+/// engine. Each value is encrypted with a random nonce, and is stored in an
+/// envelope which starts with the version of the format, followed by the
+/// identifier of the key, and the nonce. Once a datastore is encrypted every
+/// value is stored in an envelope, so a value which was not encrypted is
+/// never mistaken for an encrypted value, or the other way around. The keys
+/// themselves are not encrypted, so this protects the record contents, but
+/// not the record ids or the contents of indexes.
+pub struct StorageKey {
+	id: [u8; KEY_ID_LEN],
+	key: LessSafeKey,
+	random: SystemRandom,
+}
+
+impl Debug for StorageKey {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		f.debug_struct("StorageKey").finish_non_exhaustive()
+	}
+}
+
+impl StorageKey {
+	/// Creates a storage key from a secret of 16, 24, or 32 bytes
+	pub fn new(secret: &str) -> Result<Self, Error> {
+		let prk = derive(secret, STORAGE_SALT)?;
+		let invalid = |_| Error::EncryptionKeyInvalid("The key is not valid".to_owned());
+		let key: UnboundKey =
+			prk.expand(&[b"key".as_slice()], &AES_256_GCM).map_err(invalid)?.into();
+		let mut id = [0; KEY_ID_LEN];
+		id.copy_from_slice(
+			&Sha256::digest([STORAGE_SALT, secret.as_bytes()].concat())[..KEY_ID_LEN],
+		);
+		Ok(StorageKey {
+			id,
+			key: LessSafeKey::new(key),
+			random: SystemRandom::new(),
+		})
+	}
+
+	/// The identifier which is stored with each value encrypted by this key
+	pub(crate) fn id(&self) -> &[u8] {
+		&self.id
+	}
+
+	/// Encrypts a value which is stored at the specified key. The key is
+	/// used as the additional data, so that an encrypted value can not be
+	/// moved to, or copied over, the value stored at another key.
+	pub(crate) fn seal(&self, key: &[u8], val: Val) -> Result<Val, Error> {
+		let mut nonce = [0; NONCE_LEN];
+		self.random.fill(&mut nonce).map_err(|_| fail!("Unable to generate a nonce"))?;
+		// Encrypt the value
+		let mut data = val;
+		self.key
+			.seal_in_place_append_tag(
+				Nonce::assume_unique_for_key(nonce),
+				Aad::from(key),
+				&mut data,
+			)
+			.map_err(|_| fail!("Unable to encrypt the value"))?;
+		let mut out = Vec::with_capacity(STORAGE_HEADER_LEN + data.len());
+		out.push(VERSION);
+		out.extend_from_slice(&self.id);
+		out.extend_from_slice(&nonce);
+		out.extend_from_slice(&data);
+		Ok(out)
+	}
+
+	/// Decrypts a value which is stored at the specified key. Values which
+	/// were not encrypted with this key can not be decrypted, and return an
+	/// error, so a datastore must be rekeyed to enable or change encryption.
+	pub(crate) fn open(&self, key: &[u8], val: Val) -> Result<Val, Error> {
+		// Check the value was encrypted with this key
+		if val.len() < STORAGE_HEADER_LEN || val[0] != VERSION {
+			return Err(Error::Decryption(key.sprint()));
+		}
+		let (id, nonce) = val[1..STORAGE_HEADER_LEN].split_at(KEY_ID_LEN);
+		if id != self.id {
+			return Err(Error::Decryption(key.sprint()));
+		}
+		let mut nonce_bytes = [0; NONCE_LEN];
+		nonce_bytes.copy_from_slice(nonce);
+		// Decrypt the value
+		let mut data = val[STORAGE_HEADER_LEN..].to_vec();
+		let len = self
+			.key
+			.open_in_place(Nonce::assume_unique_for_key(nonce_bytes), Aad::from(key), &mut data)
+			.map_err(|_| Error::Decryption(key.sprint()))?
+			.len();
+		data.truncate(len);
+		Ok(data)
+	}
+}
+
+/// Checks whether a value has been encrypted by a keyring
+pub(crate) fn is_encrypted(val: &[u8]) -> bool {
+	val.len() >= HEADER_LEN && val.starts_with(PREFIX)
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+
+	const KEY: &str = "0123456789abcdef0123456789abcdef";
+	const OLD: &str = "fedcba9876543210fedcba9876543210";
+
+	#[test]
+	fn encrypt_and_decrypt() {
+		let (keys, _) = Keyring::new(KEY, &[]).unwrap().generate().unwrap();
+		let val = Value::from("123-45-6789");
+		let enc = keys.encrypt(&val, "person:one/ssn").unwrap();
+		let Value::Bytes(enc) = enc else {
+			panic!("Expected an encrypted value");
+		};
+		assert!(is_encrypted(&enc));
+		assert_eq!(keys.decrypt(&enc, "person:one/ssn").unwrap(), (val, false));
+		assert!(keys.decrypt(&enc, "person:two/ssn").is_err());
+	}
+
+	#[test]
+	fn data_keys() {
+		let keyring = Keyring::new(KEY, &[]).unwrap();
+		let (keys, val) = keyring.generate().unwrap();
+		// The data keys are not stored in plaintext
+		assert!(!val.windows(DATA_KEY_LEN).any(|w| w == keys.current.secret));
+		let enc = keys.encrypt(&Value::from(true), "person:one/ssn").unwrap();
+		let Value::Bytes(enc) = enc else {
+			panic!("Expected an encrypted value");
+		};
+		let keys = keyring.data_keys(&val).unwrap();
+		assert_eq!(keys.decrypt(&enc, "person:one/ssn").unwrap(), (Value::from(true), false));
+		assert!(keyring.rotate(&val).unwrap().is_none());
+		// The data keys can not be decrypted with another master key
+		assert!(Keyring::new(OLD, &[]).unwrap().data_keys(&val).is_err());
+		assert!(keyring.data_keys(&val[1..]).is_err());
+	}
+
+	#[test]
+	fn rotate_data_keys() {
+		let old = Keyring::new(OLD, &[]).unwrap();
+		let (keys, val) = old.generate().unwrap();
+		let enc = keys.encrypt(&Value::from(true), "person:one/ssn").unwrap();
+		let Value::Bytes(enc) = enc else {
+			panic!("Expected an encrypted value");
+		};
+		// The data keys can be decrypted with a previous master key
+		let keyring = Keyring::new(KEY, &[OLD.to_owned()]).unwrap();
+		assert!(keyring.data_keys(&val).is_ok());
+		// Rotating adds a new data key, encrypted with the current master key
+		let val = keyring.rotate(&val).unwrap().unwrap();
+		assert!(keyring.rotate(&val).unwrap().is_none());
+		let keyring = Keyring::new(KEY, &[]).unwrap();
+		let keys = keyring.data_keys(&val).unwrap();
+		assert!(keys.has_previous());
+		assert_eq!(keys.decrypt(&enc, "person:one/ssn").unwrap(), (Value::from(true), true));
+		// Retiring removes the previous data keys
+		let val = keyring.retire(&val).unwrap();
+		let keys = keyring.data_keys(&val).unwrap();
+		assert!(!keys.has_previous());
+		assert!(keys.decrypt(&enc, "person:one/ssn").is_err());
+	}
+
+	#[test]
+	fn invalid_keys() {
+		assert!(Keyring::new("short", &[]).is_err());
+		assert!(Keyring::new("0123456789abcdef0123456789abcdef0", &[]).is_err());
+		assert!(StorageKey::new("short").is_err());
+	}
+
+	#[test]
+	fn seal_and_open() {
+		let key = StorageKey::new("0123456789abcdef0123456789abcdef").unwrap();
+		let enc = key.seal(b"/!v", b"value".to_vec()).unwrap();
+		assert!(!enc.windows(5).any(|w| w == b"value"));
+		// The same value encrypts differently each time
+		let two = key.seal(b"/!v", b"value".to_vec()).unwrap();
+		assert_ne!(enc, two);
+		assert_eq!(key.open(b"/!v", enc.clone()).unwrap(), b"value");
+		assert_eq!(key.open(b"/!v", two).unwrap(), b"value");
+		// Values can not be opened at another key
+		assert!(key.open(b"/!w", enc.clone()).is_err());
+		// Values which were not encrypted are not returned
+		assert!(key.open(b"/!v", b"plain".to_vec()).is_err());
+		assert!(key.open(b"/!v", Vec::new()).is_err());
+		let other = StorageKey::new("fedcba9876543210fedcba9876543210").unwrap();
+		assert!(other.open(b"/!v", enc).is_err());
+	}
+}
@@ -0,0 +1,164 @@
+use crate::cnf::EXPIRY_BATCH_SIZE;
+use crate::dbs::Session;
+use crate::err::Error;
+use crate::kvs::Datastore;
+use crate::kvs::Transaction;
+use crate::kvs::{LockType::*, TransactionType::*};
+use crate::sql::statements::DeleteStatement;
+use crate::sql::{Array, Id, Output, Thing, Value, Values};
+use chrono::{TimeZone, Utc};
+use reblessive::TreeStack;
+
+impl Datastore {
+	/// Fetches the databases which contain records which expired before the timestamp.
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::ds", skip(self))]
+	pub(crate) async fn record_expiry_scan(&self, ts: u64) -> Result<Vec<(String, String)>, Error> {
+		// Store the databases with expired records
+		let mut out = Vec::new();
+		// Create a new transaction
+		let txn = self.transaction(Read, Optimistic).await?;
+		// Fetch all namespaces
+		let nss = catch!(txn, txn.all_ns().await);
+		// Loop over all namespaces
+		for ns in nss.iter() {
+			// Get the namespace name
+			let ns = &ns.name;
+			// Fetch all databases
+			let dbs = catch!(txn, txn.all_db(ns).await);
+			// Loop over all databases
+			for db in dbs.iter() {
+				// Get the database name
+				let db = &db.name;
+				// Check if there are expired records
+				let ids = catch!(txn, Self::record_expiry_ids(&txn, ns, db, ts).await);
+				if !ids.is_empty() {
+					out.push((ns.to_string(), db.to_string()));
+				}
+			}
+		}
+		// Cancel the transaction
+		catch!(txn, txn.cancel().await);
+		// Return the databases
+		Ok(out)
+	}
+
+	/// Deletes all records which expired before the timestamp.
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::ds", skip(self))]
+	pub(crate) async fn record_expiry_cleanup(&self, ts: u64) -> Result<(), Error> {
+		// Fetch the databases with expired records
+		let expired = self.record_expiry_scan(ts).await?;
+		// Delete the expired records in each database
+		for (ns, db) in expired {
+			self.record_expiry_delete(&ns, &db, ts).await?;
+		}
+		// Everything ok
+		Ok(())
+	}
+
+	/// Deletes the records in a database which expired before the timestamp.
+	///
+	/// The expired records are fetched again within the transaction which
+	/// deletes them, so that a record which was updated since the scan, and
+	/// which has therefore not yet expired, is kept. A record which is updated
+	/// concurrently causes this transaction to conflict, rather than to delete
+	/// the updated record.
+	async fn record_expiry_delete(&self, ns: &str, db: &str, ts: u64) -> Result<(), Error> {
+		// Delete the records as the database owner, so
+		// that table events, live queries, and change
+		// feeds are processed for every deleted record.
+		let sess = Session::owner().with_ns(ns).with_db(db);
+		// Create a new query options
+		let mut opt = self.setup_options(&sess);
+		// Create a new context
+		let mut ctx = self.setup_ctx()?;
+		// Start an execution context
+		sess.context(&mut ctx);
+		// Create a sender for the notifications of this transaction
+		let receiver = ctx.has_notifications().then(|| {
+			let (send, recv) = async_channel::unbounded();
+			opt = opt.new_with_sender(send);
+			recv
+		});
+		// Start a new transaction
+		let txn = self.transaction(Write, Optimistic).await?.enclose();
+		// Fetch the records which have expired
+		let ids = catch!(txn, Self::record_expiry_ids(&txn, ns, db, ts).await);
+		// Check if there are still expired records
+		if ids.is_empty() {
+			return txn.cancel().await;
+		}
+		// Store the transaction
+		ctx.set_transaction(txn.clone());
+		// Freeze the context
+		let ctx = ctx.freeze();
+		// Setup the delete statement
+		let stm = DeleteStatement {
+			what: Values(ids),
+			output: Some(Output::None),
+			..DeleteStatement::default()
+		};
+		// Process the delete statement
+		let res = TreeStack::new().enter(|stk| stm.compute(stk, &ctx, &opt, None)).finish().await;
+		catch!(txn, res);
+		// Complete the change feeds, and commit the transaction
+		catch!(txn, txn.lock().await.complete_changes(false).await);
+		txn.commit().await?;
+		// Flush the notifications
+		if let (Some(recv), Some(sink)) = (receiver, ctx.notifications()) {
+			while let Ok(x) = recv.try_recv() {
+				if sink.send(x).await.is_err() {
+					break;
+				}
+			}
+		}
+		// Everything ok
+		Ok(())
+	}
+
+	/// Fetches the records in a database which expired before the timestamp.
+	async fn record_expiry_ids(
+		txn: &Transaction,
+		ns: &str,
+		db: &str,
+		ts: u64,
+	) -> Result<Vec<Value>, Error> {
+		// Store the expired records
+		let mut ids = Vec::new();
+		// Fetch all tables
+		let tbs = txn.all_tb(ns, db, None).await?;
+		// Loop over all tables with an expiry
+		for tb in tbs.iter().filter(|tb| tb.expire.is_some()) {
+			// Time series records expire after their timestamp
+			if let (true, Some(expire)) = (tb.is_timeseries(), &tb.expire) {
+				// Get the table name
+				let tb = &tb.name;
+				// Fetch the records which are older than the expiry
+				let time = ts.saturating_sub(expire.secs()) as i64;
+				let time = Utc.timestamp_opt(time, 0).earliest().unwrap_or_default();
+				let id = Id::Array(Array::from(vec![Value::Datetime(time.into())]));
+				let beg = crate::key::thing::prefix(ns, db, tb);
+				let end = crate::key::thing::new(ns, db, tb, &id).encode()?;
+				let keys = txn.keys(beg..end, *EXPIRY_BATCH_SIZE, None).await?;
+				// Loop over the expired records
+				for key in keys.iter() {
+					let th = crate::key::thing::Thing::decode(key)?;
+					ids.push(Value::from(Thing::from((th.tb, th.id))));
+				}
+				continue;
+			}
+			// Get the table name
+			let tb = &tb.name;
+			// Fetch the records which have expired
+			let beg = crate::key::table::ex::prefix(ns, db, tb);
+			let end = crate::key::table::ex::suffix(ns, db, tb, ts);
+			let keys = txn.keys(beg..end, *EXPIRY_BATCH_SIZE, None).await?;
+			// Loop over the expired records
+			for key in keys.iter() {
+				let ex = crate::key::table::ex::Ex::decode(key)?;
+				ids.push(Value::from(Thing::from((ex.tb, ex.id))));
+			}
+		}
+		// Return the expired records
+		Ok(ids)
+	}
+}
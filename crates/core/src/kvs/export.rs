@@ -1,11 +1,23 @@
+use super::Convert;
 use super::Transaction;
+use super::Val;
 use crate::cnf::EXPORT_BATCH_SIZE;
 use crate::err::Error;
 use crate::key::thing;
 use crate::sql::paths::EDGE;
 use crate::sql::paths::IN;
 use crate::sql::paths::OUT;
+use crate::sql::statements::DefineAccessStatement;
+use crate::sql::statements::DefineAnalyzerStatement;
+use crate::sql::statements::DefineEventStatement;
+use crate::sql::statements::DefineFieldStatement;
+use crate::sql::statements::DefineFunctionStatement;
+use crate::sql::statements::DefineIndexStatement;
+use crate::sql::statements::DefineParamStatement;
+use crate::sql::statements::DefineSequenceStatement;
 use crate::sql::statements::DefineTableStatement;
+use crate::sql::statements::DefineUserStatement;
+use crate::sql::Datetime;
 use crate::sql::Value;
 use async_channel::Sender;
 use chrono::prelude::Utc;
@@ -16,11 +28,14 @@ pub struct Config {
 	pub users: bool,
 	pub accesses: bool,
 	pub params: bool,
+	pub sequences: bool,
 	pub functions: bool,
 	pub analyzers: bool,
 	pub tables: TableConfig,
 	pub versions: bool,
 	pub records: bool,
+	/// Export the definitions and records as they were stored at this point in time
+	pub at: Option<Datetime>,
 }
 
 impl Default for Config {
@@ -29,29 +44,41 @@ impl Default for Config {
 			users: true,
 			accesses: true,
 			params: true,
+			sequences: true,
 			functions: true,
 			analyzers: true,
 			tables: TableConfig::default(),
 			versions: false,
 			records: true,
+			at: None,
 		}
 	}
 }
 
+impl Config {
+	/// The version at which the definitions and records are read
+	fn version(&self) -> Option<u64> {
+		self.at.as_ref().and_then(Datetime::to_u64)
+	}
+}
+
 impl From<Config> for Value {
 	fn from(config: Config) -> Value {
 		let obj = map!(
 			"users" => config.users.into(),
 			"accesses" => config.accesses.into(),
 			"params" => config.params.into(),
+			"sequences" => config.sequences.into(),
 			"functions" => config.functions.into(),
 			"analyzers" => config.analyzers.into(),
 			"versions" => config.versions.into(),
+			"records" => config.records.into(),
 			"tables" => match config.tables {
 				TableConfig::All => true.into(),
 				TableConfig::None => false.into(),
 				TableConfig::Some(v) => v.into()
-			}
+			},
+			"at", if let Some(at) = config.at => at.into(),
 		);
 
 		obj.into()
@@ -85,6 +112,7 @@ impl TryFrom<&Value> for Config {
 				bool_prop!(users);
 				bool_prop!(accesses);
 				bool_prop!(params);
+				bool_prop!(sequences);
 				bool_prop!(functions);
 				bool_prop!(analyzers);
 				bool_prop!(versions);
@@ -94,6 +122,16 @@ impl TryFrom<&Value> for Config {
 					config.tables = v.try_into()?;
 				}
 
+				match obj.get("at") {
+					Some(Value::Datetime(v)) => {
+						config.at = Some(v.to_owned());
+					}
+					Some(Value::None | Value::Null) | None => (),
+					Some(v) => {
+						return Err(Error::InvalidExportConfig(v.to_owned(), "a datetime".into()))
+					}
+				}
+
 				Ok(config)
 			}
 			v => Err(Error::InvalidExportConfig(v.to_owned(), "an object".into())),
@@ -180,7 +218,11 @@ impl Transaction {
 		cfg: Config,
 		chn: Sender<Vec<u8>>,
 	) -> Result<(), Error> {
-		// Output USERS, ACCESSES, PARAMS, FUNCTIONS, ANALYZERS
+		// The versions of the records can not be exported at a point in time
+		if cfg.versions && cfg.at.is_some() {
+			return Err(Error::ExportVersionsAt);
+		}
+		// Output USERS, ACCESSES, PARAMS, SEQUENCES, FUNCTIONS, ANALYZERS
 		self.export_metadata(&cfg, &chn, ns, db).await?;
 		// Output TABLES
 		self.export_tables(ns, db, &cfg, &chn).await?;
@@ -199,37 +241,86 @@ impl Transaction {
 
 		// Output USERS
 		if cfg.users {
-			let users = self.all_db_users(ns, db).await?;
-			self.export_section("USERS", users.to_vec(), chn).await?;
+			let beg = crate::key::database::us::prefix(ns, db);
+			let end = crate::key::database::us::suffix(ns, db);
+			let users = self.export_definitions::<DefineUserStatement>(beg, end, cfg).await?;
+			self.export_section("USERS", users, chn).await?;
 		}
 
 		// Output ACCESSES
 		if cfg.accesses {
-			let accesses = self.all_db_accesses(ns, db).await?;
-			self.export_section("ACCESSES", accesses.to_vec(), chn).await?;
+			let beg = crate::key::database::ac::prefix(ns, db);
+			let end = crate::key::database::ac::suffix(ns, db);
+			let accesses = self.export_definitions::<DefineAccessStatement>(beg, end, cfg).await?;
+			self.export_section("ACCESSES", accesses, chn).await?;
 		}
 
 		// Output PARAMS
 		if cfg.params {
-			let params = self.all_db_params(ns, db).await?;
-			self.export_section("PARAMS", params.to_vec(), chn).await?;
+			let beg = crate::key::database::pa::prefix(ns, db);
+			let end = crate::key::database::pa::suffix(ns, db);
+			let params = self.export_definitions::<DefineParamStatement>(beg, end, cfg).await?;
+			self.export_section("PARAMS", params, chn).await?;
+		}
+
+		// Output SEQUENCES
+		if cfg.sequences {
+			let beg = crate::key::database::sq::prefix(ns, db);
+			let end = crate::key::database::sq::suffix(ns, db);
+			let mut sequences = Vec::new();
+			for sq in self.export_definitions::<DefineSequenceStatement>(beg, end, cfg).await? {
+				// Resume the sequence from the next value after import
+				let key = crate::key::database::sv::new(ns, db, &sq.name);
+				let start = match self.get(key, cfg.version()).await? {
+					Some(v) => <[u8; 8]>::try_from(v.as_slice())
+						.map(i64::from_be_bytes)
+						.map_err(|_| fail!("Invalid value for sequence {}", sq.name))?
+						.saturating_add(1),
+					None => sq.start,
+				};
+				sequences.push(DefineSequenceStatement {
+					start,
+					..sq
+				});
+			}
+			self.export_section("SEQUENCES", sequences, chn).await?;
 		}
 
 		// Output FUNCTIONS
 		if cfg.functions {
-			let functions = self.all_db_functions(ns, db).await?;
-			self.export_section("FUNCTIONS", functions.to_vec(), chn).await?;
+			let beg = crate::key::database::fc::prefix(ns, db);
+			let end = crate::key::database::fc::suffix(ns, db);
+			let functions =
+				self.export_definitions::<DefineFunctionStatement>(beg, end, cfg).await?;
+			self.export_section("FUNCTIONS", functions, chn).await?;
 		}
 
 		// Output ANALYZERS
 		if cfg.analyzers {
-			let analyzers = self.all_db_analyzers(ns, db).await?;
-			self.export_section("ANALYZERS", analyzers.to_vec(), chn).await?;
+			let beg = crate::key::database::az::prefix(ns, db);
+			let end = crate::key::database::az::suffix(ns, db);
+			let analyzers =
+				self.export_definitions::<DefineAnalyzerStatement>(beg, end, cfg).await?;
+			self.export_section("ANALYZERS", analyzers, chn).await?;
 		}
 
 		Ok(())
 	}
 
+	/// Fetches the definitions within a key range, as
+	/// they were stored at the point in time of the export
+	async fn export_definitions<T>(
+		&self,
+		beg: Vec<u8>,
+		end: Vec<u8>,
+		cfg: &Config,
+	) -> Result<Vec<T>, Error>
+	where
+		T: From<Val>,
+	{
+		Ok(self.getr(beg..end, cfg.version()).await?.convert())
+	}
+
 	async fn export_section<T: ToString>(
 		&self,
 		title: &str,
@@ -264,13 +355,15 @@ impl Transaction {
 			return Ok(());
 		}
 
-		let tables = self.all_tb(ns, db, None).await?;
+		let beg = crate::key::database::tb::prefix(ns, db);
+		let end = crate::key::database::tb::suffix(ns, db);
+		let tables = self.export_definitions::<DefineTableStatement>(beg, end, cfg).await?;
 		for table in tables.iter() {
 			if !cfg.tables.includes(&table.name) {
 				continue;
 			}
 
-			self.export_table_structure(ns, db, table, chn).await?;
+			self.export_table_structure(ns, db, table, cfg, chn).await?;
 
 			if cfg.records {
 				self.export_table_data(ns, db, table, cfg, chn).await?;
@@ -285,6 +378,7 @@ impl Transaction {
 		ns: &str,
 		db: &str,
 		table: &DefineTableStatement,
+		cfg: &Config,
 		chn: &Sender<Vec<u8>>,
 	) -> Result<(), Error> {
 		chn.send(bytes!("-- ------------------------------")).await?;
@@ -294,19 +388,25 @@ impl Transaction {
 		chn.send(bytes!(format!("{};", table))).await?;
 		chn.send(bytes!("")).await?;
 
-		let fields = self.all_tb_fields(ns, db, &table.name, None).await?;
+		let beg = crate::key::table::fd::prefix(ns, db, &table.name);
+		let end = crate::key::table::fd::suffix(ns, db, &table.name);
+		let fields = self.export_definitions::<DefineFieldStatement>(beg, end, cfg).await?;
 		for field in fields.iter() {
 			chn.send(bytes!(format!("{};", field))).await?;
 		}
 		chn.send(bytes!("")).await?;
 
-		let indexes = self.all_tb_indexes(ns, db, &table.name).await?;
+		let beg = crate::key::table::ix::prefix(ns, db, &table.name);
+		let end = crate::key::table::ix::suffix(ns, db, &table.name);
+		let indexes = self.export_definitions::<DefineIndexStatement>(beg, end, cfg).await?;
 		for index in indexes.iter() {
 			chn.send(bytes!(format!("{};", index))).await?;
 		}
 		chn.send(bytes!("")).await?;
 
-		let events = self.all_tb_events(ns, db, &table.name).await?;
+		let beg = crate::key::table::ev::prefix(ns, db, &table.name);
+		let end = crate::key::table::ev::suffix(ns, db, &table.name);
+		let events = self.export_definitions::<DefineEventStatement>(beg, end, cfg).await?;
 		for event in events.iter() {
 			chn.send(bytes!(format!("{};", event))).await?;
 		}
@@ -343,7 +443,7 @@ impl Transaction {
 				}
 				self.export_versioned_data(values, chn).await?;
 			} else {
-				let batch = self.batch(rng, *EXPORT_BATCH_SIZE, true, None).await?;
+				let batch = self.batch(rng, *EXPORT_BATCH_SIZE, true, cfg.version()).await?;
 				next = batch.next;
 				// If there are no values, return early.
 				let values = batch.values;
@@ -12,6 +12,7 @@ use foundationdb::options::MutationType;
 use foundationdb::Database;
 use foundationdb::RangeOption;
 use foundationdb::Transaction as Tx;
+use futures::future::try_join_all;
 use futures::StreamExt;
 use std::fmt::Debug;
 use std::ops::Range;
@@ -255,6 +256,29 @@ impl super::api::Transaction for Transaction {
 		Ok(res)
 	}
 
+	/// Fetch many keys from the datastore.
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::api", skip(self), fields(keys = keys.sprint()))]
+	async fn getm<K>(&mut self, keys: Vec<K>) -> Result<Vec<Option<Val>>, Error>
+	where
+		K: Into<Key> + Sprintable + Debug,
+	{
+		// Check to see if transaction is closed
+		if self.done {
+			return Err(Error::TxFinished);
+		}
+		// Get the transaction
+		let inner = self.inner.as_ref().unwrap();
+		let snapshot = self.snapshot();
+		// Get the keys concurrently
+		let res = keys.into_iter().map(|key| {
+			let key: Key = key.into();
+			async move { inner.get(&key, snapshot).await.map(|v| v.map(|v| v.to_vec())) }
+		});
+		let res = try_join_all(res).await?;
+		// Return result
+		Ok(res)
+	}
+
 	/// Inserts or update a key in the database
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::api", skip(self), fields(key = key.sprint()))]
 	async fn set<K, V>(&mut self, key: K, val: V, version: Option<u64>) -> Result<(), Error>
@@ -0,0 +1,708 @@
+use crate::cnf::INDEXING_BATCH_SIZE;
+use crate::ctx::{Context, MutableContext};
+use crate::dbs::{Force, Options};
+use crate::err::Error;
+use crate::key::database;
+use crate::key::table::{self, er, ex, mv};
+use crate::key::thing;
+use crate::kvs::ds::TransactionFactory;
+use crate::kvs::LockType::Optimistic;
+use crate::kvs::{Transaction, TransactionType};
+use crate::sql::paths::ID;
+use crate::sql::statements::{DefineFieldStatement, DefineTableStatement, UpdateStatement};
+use crate::sql::{Data, Expression, Idiom, Object, Operator, Output, Thing, Value, Values};
+use dashmap::mapref::entry::Entry;
+use dashmap::DashMap;
+use reblessive::TreeStack;
+use std::ops::Range;
+use std::sync::Arc;
+use std::time::{Duration, Instant};
+use tokio::sync::Mutex;
+use tokio::task;
+use tokio::task::JoinHandle;
+use tokio::time::sleep;
+use uuid::Uuid;
+
+/// How long to wait for an altered field definition to be committed
+const MIGRATION_START_TIMEOUT: Duration = Duration::from_secs(30);
+
+#[derive(Clone)]
+pub(crate) enum MigrationStatus {
+	Started,
+	Migrating(usize),
+	Error(Arc<Error>),
+	Migrated(usize),
+}
+
+impl MigrationStatus {
+	fn is_error(&self) -> bool {
+		matches!(self, Self::Error(_))
+	}
+}
+
+impl From<MigrationStatus> for Value {
+	fn from(st: MigrationStatus) -> Self {
+		let mut o = Object::default();
+		let s = match st {
+			MigrationStatus::Started => "started",
+			MigrationStatus::Migrating(count) => {
+				o.insert("count".to_string(), count.into());
+				"migrating"
+			}
+			MigrationStatus::Error(error) => {
+				o.insert("error".to_string(), error.to_string().into());
+				"error"
+			}
+			MigrationStatus::Migrated(count) => {
+				o.insert("count".to_string(), count.into());
+				"migrated"
+			}
+		};
+		o.insert("status".to_string(), s.into());
+		o.into()
+	}
+}
+
+/// The progress of moving the records of a renamed table, or the keys of a
+/// renamed database, which is stored with the new table or database, along
+/// with the name of the table or database which they are moved from.
+pub(crate) fn move_progress(from: &str, status: MigrationStatus) -> Value {
+	let mut v = Value::from(status);
+	if let Value::Object(o) = &mut v {
+		o.insert("from".to_string(), from.into());
+	}
+	v
+}
+
+/// Read where a move started from, and how many records or keys have been
+/// moved so far, from its stored progress. Returns None if it has completed.
+pub(crate) fn move_remaining(v: &Value) -> Option<(String, usize)> {
+	let Value::Object(o) = v else {
+		return None;
+	};
+	if matches!(o.get("status"), Some(Value::Strand(s)) if s.as_str() == "migrated") {
+		return None;
+	}
+	let from = match o.get("from") {
+		Some(Value::Strand(s)) => s.as_str().to_owned(),
+		_ => return None,
+	};
+	let count = match o.get("count") {
+		Some(Value::Number(n)) => n.as_usize(),
+		_ => 0,
+	};
+	Some((from, count))
+}
+
+/// The changes which are applied to the existing records of a table
+#[derive(Clone, Debug, PartialEq)]
+pub(crate) struct FieldChanges {
+	/// The name of the field before it was altered
+	pub(crate) from: Idiom,
+	/// The name of the field after it was altered
+	pub(crate) name: Idiom,
+	/// The value which is set on records where the field is empty
+	pub(crate) default: Option<Value>,
+	/// The field definition which the records are migrated to
+	pub(crate) field: DefineFieldStatement,
+}
+
+impl FieldChanges {
+	/// The data which rewrites each record. Records are always rewritten,
+	/// so that values are coerced to the type of the field definition.
+	fn data(&self) -> Data {
+		let value = Value::Idiom(self.from.clone());
+		let value = match &self.default {
+			Some(default) => Value::Expression(Box::new(Expression::Binary {
+				l: value,
+				o: Operator::Nco,
+				r: default.clone(),
+			})),
+			None => value,
+		};
+		let mut data = vec![(self.name.clone(), Operator::Equal, value)];
+		if self.from != self.name {
+			data.push((self.from.clone(), Operator::Equal, Value::None));
+		}
+		Data::SetExpression(data)
+	}
+}
+
+/// The migrations of each field, keyed by namespace, database, table, and field
+type FieldMigrations = DashMap<(String, String, String, String), (Arc<Migration>, JoinHandle<()>)>;
+
+/// The records of each renamed table, keyed by namespace, database, and previous table
+type TableMigrations = DashMap<(String, String, String), (Arc<TableMigration>, JoinHandle<()>)>;
+
+/// The keys of each renamed database, keyed by namespace and previous database
+type DatabaseMigrations = DashMap<(String, String), (Arc<DatabaseMigration>, JoinHandle<()>)>;
+
+#[derive(Clone)]
+pub(crate) struct FieldMigrator {
+	tf: TransactionFactory,
+	migrations: Arc<FieldMigrations>,
+	tables: Arc<TableMigrations>,
+	databases: Arc<DatabaseMigrations>,
+}
+
+impl FieldMigrator {
+	pub(super) fn new(tf: TransactionFactory) -> Self {
+		Self {
+			tf,
+			migrations: Default::default(),
+			tables: Default::default(),
+			databases: Default::default(),
+		}
+	}
+
+	/// Move the records of a renamed table, in batches, to the new table. The
+	/// progress is stored with the new table, so that an interrupted move is
+	/// resumed, with the number of records which were already moved, when the
+	/// datastore is next started.
+	pub(crate) fn migrate_table(
+		&self,
+		ctx: &Context,
+		opt: Options,
+		from: &str,
+		name: &str,
+		count: usize,
+	) -> Result<(), Error> {
+		let key = (opt.ns()?.to_owned(), opt.db()?.to_owned(), from.to_owned());
+		let migration = TableMigration::new(ctx, self.tf.clone(), opt, from, name, count);
+		let migration = Arc::new(migration);
+		let m = migration.clone();
+		let jh = task::spawn(async move {
+			if let Err(err) = m.compute().await {
+				error!("Unable to move the records of table `{}` to `{}`: {err}", m.from, m.name);
+				// Store the error, so that it is visible with INFO FOR TABLE
+				if let Err(e) = m.store_status(MigrationStatus::Error(err.into())).await {
+					warn!("Unable to store the progress of moving table `{}`: {e}", m.from);
+				}
+			}
+		});
+		self.tables.insert(key, (migration, jh));
+		Ok(())
+	}
+
+	/// Move the keys of a renamed database, in batches, to the new database.
+	/// The progress is stored with the new database, so that an interrupted
+	/// move is resumed when the datastore is next started.
+	pub(crate) fn migrate_database(
+		&self,
+		ns: &str,
+		from: &str,
+		name: &str,
+		count: usize,
+	) -> Result<(), Error> {
+		let key = (ns.to_owned(), from.to_owned());
+		let migration = Arc::new(DatabaseMigration::new(self.tf.clone(), ns, from, name, count));
+		let m = migration.clone();
+		let jh = task::spawn(async move {
+			if let Err(err) = m.compute().await {
+				error!("Unable to move the keys of database `{}` to `{}`: {err}", m.from, m.name);
+				// Store the error, so that it is visible with INFO FOR DB
+				if let Err(e) = m.store_status(MigrationStatus::Error(err.into())).await {
+					warn!("Unable to store the progress of moving database `{}`: {e}", m.from);
+				}
+			}
+		});
+		self.databases.insert(key, (migration, jh));
+		Ok(())
+	}
+
+	/// Check whether the keys of a database are being moved, to or from the database
+	pub(crate) fn is_migrating_database(&self, ns: &str, db: &str) -> bool {
+		self.databases.iter().any(|e| {
+			let (ref n, _) = *e.key();
+			let (ref m, ref jh) = *e.value();
+			n == ns && (m.from == db || m.name == db) && !jh.is_finished()
+		})
+	}
+
+	/// Check whether the records of a table are being moved, to or from the table
+	pub(crate) fn is_migrating_table(&self, ns: &str, db: &str, tb: &str) -> bool {
+		self.tables.iter().any(|e| {
+			let (ref n, ref d, _) = *e.key();
+			let (ref m, ref jh) = *e.value();
+			n == ns && d == db && (m.from == tb || m.name == tb) && !jh.is_finished()
+		})
+	}
+
+	pub(crate) fn migrate(
+		&self,
+		ctx: &Context,
+		opt: Options,
+		tb: &str,
+		changes: FieldChanges,
+	) -> Result<(), Error> {
+		let key =
+			(opt.ns()?.to_owned(), opt.db()?.to_owned(), tb.to_owned(), changes.name.to_string());
+		match self.migrations.entry(key) {
+			Entry::Occupied(mut e) => {
+				// If the migration is currently running we return error
+				if !e.get().1.is_finished() {
+					// Unless the same statement is being retried
+					if e.get().0.changes == changes {
+						return Ok(());
+					}
+					return Err(Error::FieldAlreadyMigrating {
+						field: e.key().3.clone(),
+					});
+				}
+				let migration = Migration::new(ctx, self.tf.clone(), opt, tb, changes);
+				e.insert(Self::spawn(Arc::new(migration)));
+			}
+			Entry::Vacant(e) => {
+				let migration = Migration::new(ctx, self.tf.clone(), opt, tb, changes);
+				e.insert(Self::spawn(Arc::new(migration)));
+			}
+		}
+		Ok(())
+	}
+
+	fn spawn(migration: Arc<Migration>) -> (Arc<Migration>, JoinHandle<()>) {
+		let m = migration.clone();
+		let jh = task::spawn(async move {
+			if let Err(err) = m.compute().await {
+				m.set_status(MigrationStatus::Error(err.into())).await;
+			}
+		});
+		(migration, jh)
+	}
+}
+
+struct Migration {
+	ctx: Context,
+	opt: Options,
+	tf: TransactionFactory,
+	tb: String,
+	changes: FieldChanges,
+	status: Mutex<MigrationStatus>,
+}
+
+impl Migration {
+	fn new(
+		ctx: &Context,
+		tf: TransactionFactory,
+		opt: Options,
+		tb: &str,
+		changes: FieldChanges,
+	) -> Self {
+		Self {
+			ctx: MutableContext::new_concurrent(ctx).freeze(),
+			opt,
+			tf,
+			tb: tb.to_owned(),
+			changes,
+			status: Mutex::new(MigrationStatus::Started),
+		}
+	}
+
+	async fn set_status(&self, status: MigrationStatus) {
+		let mut s = self.status.lock().await;
+		// We want to keep only the first error
+		if !s.is_error() {
+			*s = status;
+			// Store the status, so that it is visible with INFO FOR TABLE
+			if let Err(e) = self.store_status(&s).await {
+				warn!("Unable to store the migration status of field `{}`: {e}", self.changes.name);
+			}
+		}
+	}
+
+	async fn store_status(&self, status: &MigrationStatus) -> Result<(), Error> {
+		let fd = self.changes.name.to_string();
+		let key = crate::key::table::fm::new(self.opt.ns()?, self.opt.db()?, &self.tb, &fd);
+		let tx = self.tf.transaction(TransactionType::Write, Optimistic).await?;
+		catch!(tx, tx.set(key, Value::from(status.clone()), None).await);
+		tx.commit().await
+	}
+
+	/// Remove the definition of the field before it was renamed, which
+	/// is kept until every record has been migrated to the new field
+	async fn remove_previous(&self, ns: &str, db: &str) -> Result<(), Error> {
+		let fd = self.changes.from.to_string();
+		let tx = self.tf.transaction(TransactionType::Write, Optimistic).await?;
+		let key = crate::key::table::fd::new(ns, db, &self.tb, &fd);
+		catch!(tx, tx.del(key).await);
+		// Refresh the table cache
+		let tb = catch!(tx, tx.get_tb(ns, db, &self.tb).await);
+		let key = crate::key::database::tb::new(ns, db, &self.tb);
+		let tb = DefineTableStatement {
+			cache_fields_ts: Uuid::now_v7(),
+			..tb.as_ref().clone()
+		};
+		catch!(tx, tx.set(key, tb, None).await);
+		tx.commit().await
+	}
+
+	async fn new_read_tx(&self) -> Result<Transaction, Error> {
+		self.tf.transaction(TransactionType::Read, Optimistic).await
+	}
+
+	async fn new_write_tx_ctx(&self) -> Result<Context, Error> {
+		let tx = self.tf.transaction(TransactionType::Write, Optimistic).await?.into();
+		let mut ctx = MutableContext::new(&self.ctx);
+		ctx.set_transaction(tx);
+		Ok(ctx.freeze())
+	}
+
+	/// Wait until the altered field definition has been committed
+	async fn wait_for_definition(&self, ns: &str, db: &str) -> Result<(), Error> {
+		let fd = self.changes.name.to_string();
+		let deadline = Instant::now() + MIGRATION_START_TIMEOUT;
+		loop {
+			let tx = self.new_read_tx().await?;
+			let res = tx.get_tb_field(ns, db, &self.tb, &fd).await;
+			tx.cancel().await?;
+			match res {
+				Ok(df) if *df == self.changes.field => return Ok(()),
+				Ok(_) | Err(Error::FdNotFound {
+					..
+				}) if Instant::now() < deadline => sleep(Duration::from_millis(100)).await,
+				Ok(_) | Err(Error::FdNotFound {
+					..
+				}) => {
+					return Err(Error::Thrown(format!(
+						"The definition of field `{fd}` was not committed, or was changed, before the migration started"
+					)))
+				}
+				Err(e) => return Err(e),
+			}
+		}
+	}
+
+	async fn compute(&self) -> Result<(), Error> {
+		let ns = self.opt.ns()?;
+		let db = self.opt.db()?;
+		// The records are rewritten using the altered definition
+		self.wait_for_definition(ns, db).await?;
+		// Set the initial status
+		self.set_status(MigrationStatus::Migrating(0)).await;
+		// Rewrite the records in batches, each within its own transaction
+		let beg = thing::prefix(ns, db, &self.tb);
+		let end = thing::suffix(ns, db, &self.tb);
+		let mut next = Some(beg..end);
+		let mut count = 0;
+		let mut stack = TreeStack::new();
+		while let Some(rng) = next {
+			// Get the next batch of record ids
+			let tx = self.new_read_tx().await?;
+			let batch = catch!(tx, tx.batch(rng, *INDEXING_BATCH_SIZE, false, None).await);
+			// We can release the read transaction
+			tx.cancel().await?;
+			// Set the next scan range
+			next = batch.next;
+			// Check there are records
+			if batch.values.is_empty() {
+				break;
+			}
+			let what = batch
+				.values
+				.iter()
+				.map(|(k, _)| {
+					let key: thing::Thing = k.into();
+					Value::from(Thing::from((key.tb, key.id)))
+				})
+				.collect::<Vec<_>>();
+			let size = what.len();
+			// Rewrite the records through the document pipeline
+			let stm = UpdateStatement {
+				what: Values(what),
+				data: Some(self.changes.data()),
+				output: Some(Output::None),
+				..Default::default()
+			};
+			let ctx = self.new_write_tx_ctx().await?;
+			let tx = ctx.tx();
+			catch!(tx, stack.enter(|stk| stm.compute(stk, &ctx, &self.opt, None)).finish().await);
+			tx.commit().await?;
+			// Increment the count and update the status
+			count += size;
+			self.set_status(MigrationStatus::Migrating(count)).await;
+		}
+		// The records no longer use the previous field
+		if self.changes.from != self.changes.name {
+			self.remove_previous(ns, db).await?;
+		}
+		self.set_status(MigrationStatus::Migrated(count)).await;
+		Ok(())
+	}
+}
+
+/// Moves the records of a renamed table, each batch within its own transaction
+struct TableMigration {
+	ctx: Context,
+	opt: Options,
+	tf: TransactionFactory,
+	from: String,
+	name: String,
+	/// The number of records which were moved before the move was resumed
+	count: usize,
+}
+
+impl TableMigration {
+	fn new(
+		ctx: &Context,
+		tf: TransactionFactory,
+		opt: Options,
+		from: &str,
+		name: &str,
+		count: usize,
+	) -> Self {
+		Self {
+			ctx: MutableContext::new_concurrent(ctx).freeze(),
+			opt,
+			tf,
+			from: from.to_owned(),
+			name: name.to_owned(),
+			count,
+		}
+	}
+
+	/// Store the progress of the move, so that it is visible with INFO FOR TABLE
+	async fn store_status(&self, status: MigrationStatus) -> Result<(), Error> {
+		let key = mv::new(self.opt.ns()?, self.opt.db()?, &self.name);
+		let tx = self.tf.transaction(TransactionType::Write, Optimistic).await?;
+		catch!(tx, tx.set(key, move_progress(&self.from, status), None).await);
+		tx.commit().await
+	}
+
+	async fn new_write_tx_ctx(&self) -> Result<Context, Error> {
+		let tx = self.tf.transaction(TransactionType::Write, Optimistic).await?.into();
+		let mut ctx = MutableContext::new(&self.ctx);
+		ctx.set_transaction(tx);
+		Ok(ctx.freeze())
+	}
+
+	/// Wait until the renamed table definition has been committed
+	async fn wait_for_definition(&self, ns: &str, db: &str) -> Result<(), Error> {
+		let deadline = Instant::now() + MIGRATION_START_TIMEOUT;
+		loop {
+			let tx = self.tf.transaction(TransactionType::Read, Optimistic).await?;
+			let res = tx.get_tb(ns, db, &self.name).await;
+			tx.cancel().await?;
+			match res {
+				Ok(_) => return Ok(()),
+				Err(Error::TbNotFound {
+					..
+				}) if Instant::now() < deadline => sleep(Duration::from_millis(100)).await,
+				Err(Error::TbNotFound {
+					..
+				}) => {
+					return Err(Error::Thrown(format!(
+						"The table `{}` was not committed before its records were moved",
+						self.name
+					)))
+				}
+				Err(e) => return Err(e),
+			}
+		}
+	}
+
+	async fn compute(&self) -> Result<(), Error> {
+		let ns = self.opt.ns()?;
+		let db = self.opt.db()?;
+		// The records are moved once the renamed table has been committed
+		self.wait_for_definition(ns, db).await?;
+		// The moved records are indexed with the indexes of the new table
+		let tx = self.tf.transaction(TransactionType::Read, Optimistic).await?;
+		let indexes = catch!(tx, tx.all_tb_indexes(ns, db, &self.name).await);
+		tx.cancel().await?;
+		let opt = self.opt.new_with_force(Force::Index(indexes.clone()));
+		// Move the records. Moved records are deleted, so each batch starts
+		// at the beginning of the table, which includes any records which
+		// have been written to the previous table since it was renamed.
+		let beg = thing::prefix(ns, db, &self.from);
+		let end = thing::suffix(ns, db, &self.from);
+		let mut count = self.count;
+		let mut stack = TreeStack::new();
+		loop {
+			let ctx = self.new_write_tx_ctx().await?;
+			let tx = ctx.tx();
+			let batch = catch!(
+				tx,
+				tx.batch(beg.clone()..end.clone(), *INDEXING_BATCH_SIZE, true, None).await
+			);
+			if batch.values.is_empty() {
+				tx.cancel().await?;
+				break;
+			}
+			count += batch.values.len();
+			let mut what = Vec::with_capacity(batch.values.len());
+			for (k, v) in batch.values.into_iter() {
+				let key: thing::Thing = (&k).into();
+				let rid = Thing::from((self.name.clone(), key.id));
+				// Records written to the new table since the rename are kept
+				let key = thing::new(ns, db, &self.name, &rid.id);
+				if !catch!(tx, tx.exists(key.clone(), None).await) {
+					let mut val: Value = (&v).into();
+					val.put(&*ID, rid.clone().into());
+					catch!(tx, tx.set(key, &val, None).await);
+					what.push(Value::from(rid));
+				}
+				catch!(tx, tx.del(k).await);
+			}
+			// Index the moved records
+			if !indexes.is_empty() && !what.is_empty() {
+				let stm = UpdateStatement {
+					what: Values(what),
+					output: Some(Output::None),
+					..Default::default()
+				};
+				catch!(tx, stack.enter(|stk| stm.compute(stk, &ctx, &opt, None)).finish().await);
+			}
+			// Store the progress along with the moved records
+			let key = mv::new(ns, db, &self.name);
+			let val = move_progress(&self.from, MigrationStatus::Migrating(count));
+			catch!(tx, tx.set(key, val, None).await);
+			tx.commit().await?;
+		}
+		// Move the record expiries
+		let beg = er::prefix(ns, db, &self.from);
+		let end = er::suffix(ns, db, &self.from);
+		self.move_keys(beg..end, |k| er::new(ns, db, &self.name, &er::Er::decode(k)?.id).encode())
+			.await?;
+		// The expiries are ordered by time, and none expire at the maximum time
+		let beg = ex::prefix(ns, db, &self.from);
+		let end = ex::suffix(ns, db, &self.from, u64::MAX);
+		self.move_keys(beg..end, |k| {
+			let k = ex::Ex::decode(k)?;
+			ex::new(ns, db, &self.name, k.ts, &k.id).encode()
+		})
+		.await?;
+		// Remove what remains of the previous table, such as its index data
+		let beg = table::all::prefix(ns, db, &self.from);
+		let end = table::all::suffix(ns, db, &self.from);
+		loop {
+			let tx = self.tf.transaction(TransactionType::Write, Optimistic).await?;
+			let keys =
+				catch!(tx, tx.keys(beg.clone()..end.clone(), *INDEXING_BATCH_SIZE, None).await);
+			if keys.is_empty() {
+				tx.cancel().await?;
+				break;
+			}
+			for k in keys.into_iter() {
+				catch!(tx, tx.del(k).await);
+			}
+			tx.commit().await?;
+		}
+		self.store_status(MigrationStatus::Migrated(count)).await
+	}
+
+	/// Move each key in a range of the previous table to the new table
+	async fn move_keys<F>(&self, rng: Range<Vec<u8>>, rekey: F) -> Result<(), Error>
+	where
+		F: Fn(&[u8]) -> Result<Vec<u8>, Error>,
+	{
+		loop {
+			let tx = self.tf.transaction(TransactionType::Write, Optimistic).await?;
+			let batch = catch!(tx, tx.batch(rng.clone(), *INDEXING_BATCH_SIZE, true, None).await);
+			if batch.values.is_empty() {
+				tx.cancel().await?;
+				break;
+			}
+			for (k, v) in batch.values.into_iter() {
+				let key = catch!(tx, rekey(&k));
+				catch!(tx, tx.set(key, v, None).await);
+				catch!(tx, tx.del(k).await);
+			}
+			tx.commit().await?;
+		}
+		Ok(())
+	}
+}
+
+/// Moves the keys of a renamed database, each batch within its own transaction
+struct DatabaseMigration {
+	tf: TransactionFactory,
+	ns: String,
+	from: String,
+	name: String,
+	/// The number of keys which were moved before the move was resumed
+	count: usize,
+}
+
+impl DatabaseMigration {
+	fn new(tf: TransactionFactory, ns: &str, from: &str, name: &str, count: usize) -> Self {
+		Self {
+			tf,
+			ns: ns.to_owned(),
+			from: from.to_owned(),
+			name: name.to_owned(),
+			count,
+		}
+	}
+
+	/// Store the progress of the move, so that it is visible with INFO FOR DB
+	async fn store_status(&self, status: MigrationStatus) -> Result<(), Error> {
+		let key = database::mv::new(&self.ns, &self.name);
+		let tx = self.tf.transaction(TransactionType::Write, Optimistic).await?;
+		catch!(tx, tx.set(key, move_progress(&self.from, status), None).await);
+		tx.commit().await
+	}
+
+	/// Wait until the renamed database definition has been committed
+	async fn wait_for_definition(&self) -> Result<(), Error> {
+		let deadline = Instant::now() + MIGRATION_START_TIMEOUT;
+		loop {
+			let tx = self.tf.transaction(TransactionType::Read, Optimistic).await?;
+			let res = tx.get_db(&self.ns, &self.name).await;
+			tx.cancel().await?;
+			match res {
+				Ok(_) => return Ok(()),
+				Err(Error::DbNotFound {
+					..
+				}) if Instant::now() < deadline => sleep(Duration::from_millis(100)).await,
+				Err(Error::DbNotFound {
+					..
+				}) => {
+					return Err(Error::Thrown(format!(
+						"The database `{}` was not committed before its keys were moved",
+						self.name
+					)))
+				}
+				Err(e) => return Err(e),
+			}
+		}
+	}
+
+	async fn compute(&self) -> Result<(), Error> {
+		// The keys are moved once the renamed database has been committed
+		self.wait_for_definition().await?;
+		// Move the keys. Moved keys are deleted, so each batch starts at the
+		// beginning of the database, which includes any keys which have been
+		// written to the previous database since it was renamed.
+		let from = database::all::new(&self.ns, &self.from).encode()?;
+		let to = database::all::new(&self.ns, &self.name).encode()?;
+		let beg = database::all::prefix(&self.ns, &self.from);
+		let end = database::all::suffix(&self.ns, &self.from);
+		let mut count = self.count;
+		loop {
+			let tx = self.tf.transaction(TransactionType::Write, Optimistic).await?;
+			let batch = catch!(
+				tx,
+				tx.batch(beg.clone()..end.clone(), *INDEXING_BATCH_SIZE, true, None).await
+			);
+			if batch.values.is_empty() {
+				tx.cancel().await?;
+				break;
+			}
+			count += batch.values.len();
+			for (k, v) in batch.values.into_iter() {
+				// Keys written to the new database since the rename are kept
+				let key = [to.as_slice(), &k[from.len()..]].concat();
+				if !catch!(tx, tx.exists(key.clone(), None).await) {
+					catch!(tx, tx.set(key, v, None).await);
+				}
+				catch!(tx, tx.del(k).await);
+			}
+			// Store the progress along with the moved keys
+			let key = database::mv::new(&self.ns, &self.name);
+			let val = move_progress(&self.from, MigrationStatus::Migrating(count));
+			catch!(tx, tx.set(key, val, None).await);
+			tx.commit().await?;
+		}
+		self.store_status(MigrationStatus::Migrated(count)).await
+	}
+}
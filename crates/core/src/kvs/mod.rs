@@ -17,15 +17,22 @@ mod api;
 mod batch;
 mod cf;
 mod clock;
+pub mod compression;
+pub mod csv;
 mod ds;
+pub mod encryption;
+mod expiry;
 pub mod export;
 mod live;
 mod node;
+mod reencrypt;
 mod scanner;
+mod schedule;
 mod stash;
 mod tr;
 mod tx;
 mod version;
+mod webhook;
 
 mod fdb;
 mod indxdb;
@@ -40,6 +47,8 @@ pub(crate) mod cache;
 
 #[cfg(not(target_arch = "wasm32"))]
 mod index;
+#[cfg(not(target_arch = "wasm32"))]
+mod migrate;
 #[cfg(any(
 	feature = "kv-tikv",
 	feature = "kv-fdb",
@@ -55,5 +64,8 @@ pub use self::ds::*;
 pub(crate) use self::index::*;
 pub use self::kv::*;
 pub use self::live::*;
+#[cfg(not(target_arch = "wasm32"))]
+pub(crate) use self::migrate::*;
 pub use self::tr::*;
 pub use self::tx::*;
+pub(crate) use self::webhook::Webhook;
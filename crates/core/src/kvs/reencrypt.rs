@@ -0,0 +1,137 @@
+//! Re-encrypts the values of encrypted fields which were encrypted with a
+//! previous data key, once the master key has been rotated, and the data
+//! keys have been re-encrypted with the current master key.
+use crate::cnf::EXPORT_BATCH_SIZE;
+use crate::doc::encrypt::reencrypt_record;
+use crate::err::Error;
+use crate::key::{datakey, reencrypt, thing};
+use crate::kvs::ds::TransactionFactory;
+use crate::kvs::encryption::Keyring;
+use crate::kvs::{LockType::*, TransactionType::*};
+use crate::sql::statements::DefineFieldStatement;
+use crate::sql::{Thing, Value};
+use std::sync::Arc;
+
+pub(crate) struct Reencryption {
+	tf: TransactionFactory,
+	keyring: Arc<Keyring>,
+}
+
+impl Reencryption {
+	pub(crate) fn new(tf: TransactionFactory, keyring: Arc<Keyring>) -> Self {
+		Self {
+			tf,
+			keyring,
+		}
+	}
+
+	/// Re-encrypts the data keys with the current master key, if they were
+	/// encrypted with a previous master key, adding a new data key. Returns
+	/// whether there are field values to re-encrypt with the new data key,
+	/// including when a previous re-encryption was interrupted.
+	pub(crate) async fn rotate(&self) -> Result<bool, Error> {
+		let txn = self.tf.transaction(Write, Pessimistic).await?;
+		let key = datakey::new();
+		let Some(val) = catch!(txn, txn.get(key.clone(), None).await) else {
+			txn.cancel().await?;
+			return Ok(false);
+		};
+		let val = match catch!(txn, self.keyring.rotate(&val)) {
+			Some(val) => {
+				catch!(txn, txn.set(key, val.clone(), None).await);
+				txn.commit().await?;
+				val
+			}
+			None => {
+				txn.cancel().await?;
+				val
+			}
+		};
+		Ok(self.keyring.data_keys(&val)?.has_previous())
+	}
+
+	/// Re-encrypts the values of encrypted fields which were encrypted with
+	/// a previous data key, and then removes the previous data keys. The
+	/// records are rewritten in batches, and the last record which has been
+	/// rewritten is stored along with each batch, so that an interrupted
+	/// re-encryption continues from that record. Returns the number of
+	/// records which were re-encrypted.
+	pub(crate) async fn compute(&self) -> Result<u64, Error> {
+		// Fetch the tables which have encrypted fields
+		let txn = self.tf.transaction(Read, Optimistic).await?;
+		let mut tables = Vec::new();
+		for ns in catch!(txn, txn.all_ns().await).iter() {
+			let ns = &ns.name;
+			for db in catch!(txn, txn.all_db(ns).await).iter() {
+				let db = &db.name;
+				for tb in catch!(txn, txn.all_tb(ns, db, None).await).iter() {
+					let tb = &tb.name;
+					let fds = catch!(txn, txn.all_tb_fields(ns, db, tb, None).await);
+					let fds: Vec<DefineFieldStatement> =
+						fds.iter().filter(|fd| fd.encrypted).cloned().collect();
+					if !fds.is_empty() {
+						tables.push((ns.clone(), db.clone(), tb.clone(), fds));
+					}
+				}
+			}
+		}
+		// Check if a previous re-encryption was interrupted
+		let progress = catch!(txn, txn.get(reencrypt::new(), None).await);
+		txn.cancel().await?;
+		let mut count = 0;
+		for (ns, db, tb, fds) in tables {
+			let beg = thing::prefix(&ns, &db, &tb);
+			let end = thing::suffix(&ns, &db, &tb);
+			// Continue after the last record which was re-encrypted
+			let beg = match &progress {
+				Some(p) if *p >= end => continue,
+				Some(p) if *p >= beg => [p.as_slice(), &[0x00]].concat(),
+				_ => beg,
+			};
+			let mut next = Some(beg..end);
+			while let Some(rng) = next.clone() {
+				let txn = self.tf.transaction(Write, Optimistic).await?;
+				let Some(val) = catch!(txn, txn.get(datakey::new(), None).await) else {
+					txn.cancel().await?;
+					return Ok(count);
+				};
+				let keys = catch!(txn, self.keyring.data_keys(&val));
+				let batch = catch!(txn, txn.batch(rng, *EXPORT_BATCH_SIZE, true, None).await);
+				let mut last = None;
+				let mut rewritten = 0;
+				for (k, v) in batch.values {
+					let key: thing::Thing = (&k).into();
+					let rid = Thing::from((key.tb, key.id));
+					let mut val: Value = (&v).into();
+					if catch!(txn, reencrypt_record(&keys, &rid, &fds, &mut val)) {
+						catch!(txn, txn.set(k.clone(), &val, None).await);
+						rewritten += 1;
+					}
+					last = Some(k);
+				}
+				// Store the progress along with the batch
+				if let Some(last) = last {
+					catch!(txn, txn.set(reencrypt::new(), last, None).await);
+				}
+				match txn.commit().await {
+					// The records were updated concurrently, so retry the batch
+					Err(Error::TxRetryable) => continue,
+					Err(e) => return Err(e),
+					Ok(()) => {
+						count += rewritten;
+						next = batch.next;
+					}
+				}
+			}
+		}
+		// The previous data keys are no longer used
+		let txn = self.tf.transaction(Write, Optimistic).await?;
+		if let Some(val) = catch!(txn, txn.get(datakey::new(), None).await) {
+			let val = catch!(txn, self.keyring.retire(&val));
+			catch!(txn, txn.set(datakey::new(), val, None).await);
+		}
+		catch!(txn, txn.del(reencrypt::new()).await);
+		txn.commit().await?;
+		Ok(count)
+	}
+}
@@ -166,7 +166,19 @@ impl Datastore {
 	}
 	/// Shutdown the database
 	pub(crate) async fn shutdown(&self) -> Result<(), Error> {
-		// Nothing to do here
+		// Create new flush options
+		let mut opts = FlushOptions::default();
+		// Wait for the flush to finish
+		opts.set_wait(true);
+		// Sync the write-ahead log to disk
+		if let Err(e) = self.db.flush_wal(true) {
+			error!(target: TARGET, "An error occured flushing the WAL buffer to disk: {e}");
+		}
+		// Flush the memtables to SST files
+		if let Err(e) = self.db.flush_opt(&opts) {
+			error!(target: TARGET, "An error occured flushing memtables to SST files: {e}");
+		}
+		// All good
 		Ok(())
 	}
 	/// Start a new transaction
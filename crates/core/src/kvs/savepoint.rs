@@ -1,7 +1,9 @@
+use crate::cf::writer::ChangeKey;
 use crate::err::Error;
 use crate::kvs::api::Transaction;
 use crate::kvs::{Key, Val};
 use std::collections::{HashMap, VecDeque};
+use std::ops::Range;
 
 type SavePoint = HashMap<Key, SavedValue>;
 
@@ -150,3 +152,119 @@ pub(super) trait SavePointImpl: Transaction + Sized {
 		Ok(r)
 	}
 }
+
+/// A savepoint created with a `SAVEPOINT` statement. This
+/// stores the value which each key had before it was first
+/// modified after the savepoint was created, alongside the
+/// number of change feed mutations which had been buffered.
+pub(super) struct UserSavePoint {
+	name: String,
+	saved: HashMap<Key, Option<Val>>,
+	changes: HashMap<ChangeKey, usize>,
+}
+
+/// The stack of savepoints created with `SAVEPOINT`
+/// statements. These are tracked separately from the
+/// savepoints which are used when retrying document
+/// processing, so that they behave identically on all
+/// of the underlying storage engines.
+#[derive(Default)]
+pub(super) struct UserSavePoints {
+	stack: Vec<UserSavePoint>,
+	/// The values which have been read since a savepoint was
+	/// created, and which have not been modified since. These
+	/// are used as the original value of a key when it is then
+	/// modified, so that the key does not need to be fetched.
+	reads: HashMap<Key, Option<Val>>,
+}
+
+impl UserSavePoints {
+	pub(super) fn push(&mut self, name: &str, changes: HashMap<ChangeKey, usize>) {
+		self.stack.push(UserSavePoint {
+			name: name.to_owned(),
+			saved: HashMap::new(),
+			changes,
+		});
+	}
+
+	pub(super) fn is_empty(&self) -> bool {
+		self.stack.is_empty()
+	}
+
+	/// Records the value of a key which was read from the datastore
+	pub(super) fn read(&mut self, key: &Key, val: &Option<Val>) {
+		if self.stack.last().is_some_and(|current| !current.saved.contains_key(key)) {
+			self.reads.entry(key.clone()).or_insert_with(|| val.clone());
+		}
+	}
+
+	/// Returns the value of a key, if it has been read since it was
+	/// last modified, and forgets it, as the key is being modified.
+	pub(super) fn take(&mut self, key: &Key) -> Option<Option<Val>> {
+		self.reads.remove(key)
+	}
+
+	/// Forgets the values read within a range, as the keys are being modified.
+	pub(super) fn forget(&mut self, rng: &Range<Key>) {
+		self.reads.retain(|key, _| !rng.contains(key));
+	}
+
+	/// Checks whether the original value of a key needs to be saved.
+	pub(super) fn needs(&self, key: &Key) -> bool {
+		self.stack.last().is_some_and(|current| !current.saved.contains_key(key))
+	}
+
+	pub(super) fn save(&mut self, key: Key, val: Option<Val>) {
+		if let Some(current) = self.stack.last_mut() {
+			current.saved.entry(key).or_insert(val);
+		}
+	}
+
+	/// Finds the most recent savepoint with the specified name
+	fn find(&self, name: &str) -> Result<usize, Error> {
+		self.stack.iter().rposition(|v| v.name == name).ok_or_else(|| Error::SavepointNotFound {
+			value: name.to_owned(),
+		})
+	}
+
+	/// Removes all of the savepoints created after the named savepoint,
+	/// returning the original value of every key which was modified,
+	/// and the change feed marks, since the named savepoint was created.
+	/// The named savepoint remains, so that it can be rolled back again.
+	pub(super) fn rollback(
+		&mut self,
+		name: &str,
+	) -> Result<(HashMap<Key, Option<Val>>, HashMap<ChangeKey, usize>), Error> {
+		let depth = self.find(name)?;
+		let mut saved = HashMap::new();
+		let mut changes = HashMap::new();
+		// Older savepoints hold older values, so they take precedence
+		for sp in self.stack.drain(depth..).rev() {
+			saved.extend(sp.saved);
+			changes = sp.changes;
+		}
+		self.push(name, changes.clone());
+		// The reverted keys may have been read since
+		self.reads.clear();
+		Ok((saved, changes))
+	}
+
+	/// Removes the named savepoint, and all of the savepoints created
+	/// after it, merging any saved values into the savepoint which was
+	/// created before it.
+	pub(super) fn release(&mut self, name: &str) -> Result<(), Error> {
+		let depth = self.find(name)?;
+		let released: Vec<UserSavePoint> = self.stack.drain(depth..).collect();
+		match self.stack.last_mut() {
+			Some(current) => {
+				for sp in released {
+					for (key, val) in sp.saved {
+						current.saved.entry(key).or_insert(val);
+					}
+				}
+			}
+			None => self.reads.clear(),
+		}
+		Ok(())
+	}
+}
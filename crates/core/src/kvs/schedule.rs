@@ -0,0 +1,141 @@
+use crate::dbs::{Schedule, Session};
+use crate::err::Error;
+use crate::kvs::Datastore;
+use crate::kvs::{LockType::*, TransactionType::*};
+use crate::sql::statements::DefineEventStatement;
+use crate::sql::{Query, Statement, Statements, Value};
+use chrono::{DateTime, Utc};
+
+const TARGET: &str = "surrealdb::core::kvs::schedule";
+
+impl Datastore {
+	/// Fetches all of the scheduled events which are defined in the datastore.
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::ds", skip(self))]
+	pub(crate) async fn event_schedule_scan(
+		&self,
+	) -> Result<Vec<(String, String, DefineEventStatement)>, Error> {
+		// Store the scheduled events
+		let mut out = Vec::new();
+		// Create a new transaction
+		let txn = self.transaction(Read, Optimistic).await?;
+		// Fetch all namespaces
+		let nss = catch!(txn, txn.all_ns().await);
+		// Loop over all namespaces
+		for ns in nss.iter() {
+			// Get the namespace name
+			let ns = &ns.name;
+			// Fetch all databases
+			let dbs = catch!(txn, txn.all_db(ns).await);
+			// Loop over all databases
+			for db in dbs.iter() {
+				// Get the database name
+				let db = &db.name;
+				// Fetch all tables
+				let tbs = catch!(txn, txn.all_tb(ns, db, None).await);
+				// Loop over all tables
+				for tb in tbs.iter() {
+					// Fetch all events
+					let evs = catch!(txn, txn.all_tb_events(ns, db, &tb.name).await);
+					// Store the scheduled events
+					for ev in evs.iter().filter(|ev| ev.schedule.is_some()) {
+						out.push((ns.to_string(), db.to_string(), ev.clone()));
+					}
+				}
+			}
+		}
+		// Cancel the transaction
+		catch!(txn, txn.cancel().await);
+		// Return the scheduled events
+		Ok(out)
+	}
+
+	/// Claims the run of a scheduled event, if the event is due at the specified time.
+	///
+	/// The time of the last run is stored in the datastore, so when there
+	/// are multiple nodes in a cluster, only the node which successfully
+	/// commits the claim will go on to run the event.
+	async fn event_schedule_claim(
+		&self,
+		ns: &str,
+		db: &str,
+		ev: &DefineEventStatement,
+		schedule: &Schedule,
+		now: DateTime<Utc>,
+	) -> Result<bool, Error> {
+		// Create a new transaction
+		let txn = self.transaction(Write, Optimistic).await?;
+		// Fetch the time of the last run
+		let key = crate::key::table::es::new(ns, db, &ev.what, &ev.name);
+		let last = catch!(txn, txn.get(key.clone(), None).await);
+		// Check if the event is due to run
+		let due = match last {
+			// The event has not been seen yet, so start the schedule from now
+			None => false,
+			Some(v) => {
+				let last = <[u8; 8]>::try_from(v.as_slice())
+					.ok()
+					.map(i64::from_be_bytes)
+					.and_then(|v| DateTime::from_timestamp(v, 0))
+					.unwrap_or(now);
+				match schedule.next_after(last) {
+					Some(next) if next <= now => true,
+					_ => {
+						catch!(txn, txn.cancel().await);
+						return Ok(false);
+					}
+				}
+			}
+		};
+		// Record the time of this run
+		catch!(txn, txn.set(key, now.timestamp().to_be_bytes().to_vec(), None).await);
+		// Commit the claim, which fails if another node claimed the run first
+		match txn.commit().await {
+			Ok(_) => Ok(due),
+			Err(Error::TxRetryable) => Ok(false),
+			Err(e) => Err(e),
+		}
+	}
+
+	/// Runs all of the scheduled events which are due at the specified time.
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::ds", skip(self))]
+	pub(crate) async fn event_schedule_run(&self, ts: u64) -> Result<(), Error> {
+		// Get the current time
+		let now = DateTime::from_timestamp(ts as i64, 0)
+			.ok_or_else(|| Error::Internal(format!("Invalid schedule timestamp: {ts}")))?;
+		// Loop over all of the scheduled events
+		for (ns, db, ev) in self.event_schedule_scan().await? {
+			// Parse the event schedule
+			let Some(Ok(schedule)) = ev.schedule.as_ref().map(|v| v.parse::<Schedule>()) else {
+				continue;
+			};
+			// Check if this node should run the event
+			if !self.event_schedule_claim(&ns, &db, &ev, &schedule, now).await? {
+				continue;
+			}
+			// Run the event as the database owner
+			let sess = Session::owner().with_ns(&ns).with_db(&db);
+			let vars = Some(map! {
+				"event".to_string() => Value::from("SCHEDULE"),
+			});
+			let query = Query(Statements(ev.then.iter().cloned().map(Statement::Value).collect()));
+			// Log the outcome of the run
+			let res = self.process(query, &sess, vars).await;
+			match res.and_then(|res| res.into_iter().try_for_each(|r| r.result.map(|_| ()))) {
+				Ok(_) => info!(
+					target: TARGET,
+					"Scheduled event {} on table {} in {ns}/{db} ran successfully",
+					ev.name,
+					ev.what
+				),
+				Err(e) => warn!(
+					target: TARGET,
+					"Scheduled event {} on table {} in {ns}/{db} failed: {e}",
+					ev.name,
+					ev.what
+				),
+			}
+		}
+		// Everything ok
+		Ok(())
+	}
+}
@@ -75,7 +75,11 @@ impl Datastore {
 	}
 	/// Shutdown the database
 	pub(crate) async fn shutdown(&self) -> Result<(), Error> {
-		// Nothing to do here
+		// Sync and close the database files
+		if let Err(e) = self.db.close().await {
+			error!("An error occured closing the database: {e}");
+		}
+		// All good
 		Ok(())
 	}
 	/// Start a new transaction
@@ -0,0 +1,33 @@
+#[tokio::test]
+#[serial]
+async fn compressed() {
+	use crate::kvs::compression::{Algorithm, Compression};
+	let val = b"SurrealDB ".repeat(1000);
+	// Create a new datastore
+	let node_id = Uuid::parse_str("5d6a0a6f-0f3b-4bd5-9e51-2c1f0b9c7e2a").unwrap();
+	let clock = Arc::new(SizedClock::Fake(FakeClock::new(Timestamp::default())));
+	let (ds, _) = new_ds(node_id, clock).await;
+	let ds = ds.with_compression(Some(Compression::new(Algorithm::Snappy, 64)));
+	ds.check_version().await.unwrap();
+	// Create a writeable transaction
+	let mut tx = ds.transaction(Write, Optimistic).await.unwrap().inner();
+	assert!(tx.put("test", val.clone(), None).await.is_ok());
+	assert!(tx.putc("test", val.repeat(2), Some(val.clone())).await.is_ok());
+	assert!(tx.putc("test", val.clone(), Some(val.clone())).await.is_err());
+	assert!(tx.put("small", "ok", None).await.is_ok());
+	tx.commit().await.unwrap();
+	// Values are decompressed, whichever algorithm is enabled
+	let ds = ds.with_compression(Some(Compression::new(Algorithm::Snappy, usize::MAX)));
+	let mut tx = ds.transaction(Read, Optimistic).await.unwrap().inner();
+	let res = tx.scan("small".."tesu", 10, None).await.unwrap();
+	assert_eq!(res, vec![(b"small".to_vec(), b"ok".to_vec()), (b"test".to_vec(), val.repeat(2))]);
+	tx.cancel().await.unwrap();
+	// Every value is stored with a header when compression is enabled
+	let ds = ds.with_compression(None);
+	let mut tx = ds.transaction(Read, Optimistic).await.unwrap().inner();
+	let res = tx.get("small", None).await.unwrap();
+	assert!(matches!(res.as_deref(), Some(b"\x00ok")));
+	tx.cancel().await.unwrap();
+	// The storage can not be read without compression
+	assert!(ds.check_version().await.is_err());
+}
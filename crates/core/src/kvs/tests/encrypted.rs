@@ -0,0 +1,56 @@
+#[tokio::test]
+#[serial]
+async fn encrypted() {
+	use crate::kvs::encryption::StorageKey;
+	let key = || StorageKey::new("0123456789abcdef0123456789abcdef").unwrap();
+	// Create a new datastore
+	let node_id = Uuid::parse_str("b2a6b8a3-6ee0-4c4c-8b4d-5d2f3b0cbd41").unwrap();
+	let clock = Arc::new(SizedClock::Fake(FakeClock::new(Timestamp::default())));
+	let (ds, _) = new_ds(node_id, clock).await;
+	// The datastore is encrypted with the key
+	assert_eq!(ds.rekey(Some(key())).await.unwrap(), 1);
+	let ds = ds.with_storage_encryption(Some(key()));
+	// Create a writeable transaction
+	let mut tx = ds.transaction(Write, Optimistic).await.unwrap().inner();
+	assert!(tx.put("test", "ok", None).await.is_ok());
+	assert!(tx.putc("test", "updated", Some("ok")).await.is_ok());
+	assert!(tx.putc("test", "other", Some("ok")).await.is_err());
+	tx.commit().await.unwrap();
+	// The value is encrypted in storage
+	let ds = ds.with_storage_encryption(None);
+	let mut tx = ds.transaction(Read, Optimistic).await.unwrap().inner();
+	let val = tx.get("test", None).await.unwrap().unwrap();
+	assert!(!val.windows(7).any(|w| w == b"updated"));
+	tx.cancel().await.unwrap();
+	// The value is decrypted with the key
+	let ds = ds.with_storage_encryption(Some(key()));
+	let mut tx = ds.transaction(Read, Optimistic).await.unwrap().inner();
+	let val = tx.scan("test".."tesu", 10, None).await.unwrap();
+	assert_eq!(val, vec![(b"test".to_vec(), b"updated".to_vec())]);
+	tx.cancel().await.unwrap();
+	// Versionstamped values are sealed at their final key
+	let mut tx = ds.transaction(Write, Optimistic).await.unwrap().inner();
+	tx.set_versionstamped("ts", "vs", "/", "stamped").await.unwrap();
+	tx.commit().await.unwrap();
+	let mut tx = ds.transaction(Read, Optimistic).await.unwrap().inner();
+	let val = tx.scan("vs".."vt", 10, None).await.unwrap();
+	assert_eq!(val.len(), 1);
+	assert_eq!(val[0].1, b"stamped".to_vec());
+	tx.cancel().await.unwrap();
+	// Simulate a rekey which was interrupted before rewriting any values
+	let mut tx = ds.transaction(Write, Optimistic).await.unwrap().inner();
+	tx.set(crate::key::rekey::new(), vec![0x00, 0x00], None).await.unwrap();
+	tx.commit().await.unwrap();
+	assert!(matches!(ds.check_version().await, Err(crate::err::Error::RekeyInProgress)));
+	// The rekey can only be continued with the same key
+	let other = StorageKey::new("fedcba9876543210fedcba9876543210").unwrap();
+	assert!(ds.rekey(Some(other)).await.is_err());
+	// The datastore can be decrypted
+	assert_eq!(ds.rekey(None).await.unwrap(), 2);
+	let ds = ds.with_storage_encryption(None);
+	ds.check_version().await.unwrap();
+	let mut tx = ds.transaction(Read, Optimistic).await.unwrap().inner();
+	let val = tx.get("test", None).await.unwrap();
+	assert!(matches!(val.as_deref(), Some(b"updated")));
+	tx.cancel().await.unwrap();
+}
@@ -49,6 +49,8 @@ mod mem {
 
 	include!("helper.rs");
 	include!("raw.rs");
+	include!("encrypted.rs");
+	include!("compressed.rs");
 	include!("snapshot.rs");
 	include!("multireader.rs");
 	include!("multiwriter_different_keys.rs");
@@ -79,6 +81,8 @@ mod rocksdb {
 
 	include!("helper.rs");
 	include!("raw.rs");
+	include!("encrypted.rs");
+	include!("compressed.rs");
 	include!("snapshot.rs");
 	include!("multireader.rs");
 	include!("multiwriter_different_keys.rs");
@@ -109,6 +113,8 @@ mod surrealkv {
 	}
 
 	include!("raw.rs");
+	include!("encrypted.rs");
+	include!("compressed.rs");
 	include!("helper.rs");
 	include!("snapshot.rs");
 	include!("multireader.rs");
@@ -0,0 +1,10 @@
+use std::sync::LazyLock;
+
+pub static TIKV_REQUEST_TIMEOUT: LazyLock<u64> =
+	lazy_env_parse!("SURREAL_TIKV_REQUEST_TIMEOUT", u64, 10);
+
+pub static TIKV_ASYNC_COMMIT: LazyLock<bool> =
+	lazy_env_parse!("SURREAL_TIKV_ASYNC_COMMIT", bool, true);
+
+pub static TIKV_ONE_PHASE_COMMIT: LazyLock<bool> =
+	lazy_env_parse!("SURREAL_TIKV_ONE_PHASE_COMMIT", bool, true);
@@ -1,5 +1,7 @@
 #![cfg(feature = "kv-tikv")]
 
+mod cnf;
+
 use crate::err::Error;
 use crate::key::debug::Sprintable;
 use crate::kvs::savepoint::{SaveOperation, SavePointImpl, SavePoints, SavePrepare};
@@ -7,11 +9,14 @@ use crate::kvs::Check;
 use crate::kvs::Key;
 use crate::kvs::Val;
 use crate::vs::Versionstamp;
+use std::collections::HashMap;
 use std::fmt::Debug;
 use std::ops::Range;
 use std::pin::Pin;
 use std::sync::Arc;
+use std::time::Duration;
 use tikv::CheckLevel;
+use tikv::Config;
 use tikv::TimestampExt;
 use tikv::TransactionOptions;
 
@@ -58,7 +63,13 @@ impl Drop for Transaction {
 impl Datastore {
 	/// Open a new database
 	pub(crate) async fn new(path: &str) -> Result<Datastore, Error> {
-		match tikv::TransactionClient::new(vec![path]).await {
+		// Multiple placement driver endpoints can be separated by commas
+		let endpoints: Vec<&str> =
+			path.split(',').map(str::trim).filter(|s| !s.is_empty()).collect();
+		// Configure the client connection
+		let config =
+			Config::default().with_timeout(Duration::from_secs(*cnf::TIKV_REQUEST_TIMEOUT));
+		match tikv::TransactionClient::new_with_config(endpoints, config).await {
 			Ok(db) => Ok(Datastore {
 				db: Arc::pin(db),
 			}),
@@ -79,9 +90,13 @@ impl Datastore {
 			TransactionOptions::new_optimistic()
 		};
 		// Use async commit to determine transaction state earlier
-		opt = opt.use_async_commit();
+		if *cnf::TIKV_ASYNC_COMMIT {
+			opt = opt.use_async_commit();
+		}
 		// Try to use one-phase commit if writing to only one region
-		opt = opt.try_one_pc();
+		if *cnf::TIKV_ONE_PHASE_COMMIT {
+			opt = opt.try_one_pc();
+		}
 		// Set the behaviour when dropping an unfinished transaction
 		opt = opt.drop_check(CheckLevel::Warn);
 		// Set this transaction as read only if possible
@@ -205,6 +220,25 @@ impl super::api::Transaction for Transaction {
 		Ok(res)
 	}
 
+	/// Fetch many keys from the datastore.
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::api", skip(self), fields(keys = keys.sprint()))]
+	async fn getm<K>(&mut self, keys: Vec<K>) -> Result<Vec<Option<Val>>, Error>
+	where
+		K: Into<Key> + Sprintable + Debug,
+	{
+		// Check to see if transaction is closed
+		if self.done {
+			return Err(Error::TxFinished);
+		}
+		// Get the arguments
+		let keys: Vec<Key> = keys.into_iter().map(Into::into).collect();
+		// Get the keys in a single request
+		let mut res: HashMap<Key, Val> =
+			self.inner.batch_get(keys.clone()).await?.map(|kv| (Key::from(kv.0), kv.1)).collect();
+		// Return the values in the order of the keys
+		Ok(keys.iter().map(|k| res.remove(k)).collect())
+	}
+
 	/// Insert or update a key in the database
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::api", skip(self), fields(key = key.sprint()))]
 	async fn set<K, V>(&mut self, key: K, val: V, version: Option<u64>) -> Result<(), Error>
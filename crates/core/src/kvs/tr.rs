@@ -3,6 +3,7 @@ use super::api::Transaction;
 use super::Key;
 use super::Val;
 use crate::cf;
+use crate::dbs::metrics::{self, Event};
 use crate::dbs::node::Timestamp;
 use crate::doc::CursorValue;
 use crate::err::Error;
@@ -10,6 +11,8 @@ use crate::idg::u32::U32;
 use crate::key::debug::Sprintable;
 use crate::kvs::batch::Batch;
 use crate::kvs::clock::SizedClock;
+use crate::kvs::compression::{self, Compression};
+use crate::kvs::encryption::StorageKey;
 #[cfg(any(
 	feature = "kv-tikv",
 	feature = "kv-fdb",
@@ -17,9 +20,11 @@ use crate::kvs::clock::SizedClock;
 	feature = "kv-surrealcs",
 ))]
 use crate::kvs::savepoint::SavePointImpl;
+use crate::kvs::savepoint::UserSavePoints;
 use crate::kvs::stash::Stash;
 use crate::sql;
 use crate::sql::thing::Thing;
+use crate::testing::Faults;
 use crate::vs::Versionstamp;
 use sql::statements::DefineTableStatement;
 use std::fmt;
@@ -76,8 +81,12 @@ impl From<bool> for LockType {
 pub struct Transactor {
 	pub(super) inner: Inner,
 	pub(super) stash: Stash,
+	pub(super) savepoints: UserSavePoints,
 	pub(super) cf: cf::Writer,
 	pub(super) clock: Arc<SizedClock>,
+	pub(super) cipher: Option<Arc<StorageKey>>,
+	pub(super) compression: Option<Compression>,
+	pub(super) faults: Option<Arc<Faults>>,
 }
 
 #[allow(clippy::large_enum_variant)]
@@ -202,7 +211,21 @@ impl Transactor {
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tr", skip_all)]
 	pub async fn commit(&mut self) -> Result<(), Error> {
 		trace!(target: TARGET, "Commit");
-		expand_inner!(&mut self.inner, v => { v.commit().await })
+		self.delay().await;
+		let res = match self.faults.as_ref().map(|v| v.commit()) {
+			// Fail the commit with an injected fault
+			Some(Err(e)) => {
+				let _ = expand_inner!(&mut self.inner, v => { v.cancel().await });
+				Err(e)
+			}
+			_ => expand_inner!(&mut self.inner, v => { v.commit().await }),
+		};
+		match &res {
+			Ok(_) => metrics::record(Event::Commit),
+			Err(Error::TxRetryable) => metrics::record(Event::Conflict),
+			Err(_) => (),
+		}
+		res
 	}
 
 	/// Check if a key exists in the datastore.
@@ -213,7 +236,12 @@ impl Transactor {
 	{
 		let key = key.into();
 		trace!(target: TARGET, key = key.sprint(), version = version, "Exists");
-		expand_inner!(&mut self.inner, v => { v.exists(key, version).await })
+		self.delay().await;
+		let res = expand_inner!(&mut self.inner, v => { v.exists(key.clone(), version).await })?;
+		if !res && version.is_none() {
+			self.savepoints.read(&key, &None);
+		}
+		Ok(res)
 	}
 
 	/// Fetch a key from the datastore.
@@ -224,7 +252,13 @@ impl Transactor {
 	{
 		let key = key.into();
 		trace!(target: TARGET, key = key.sprint(), version = version, "Get");
-		expand_inner!(&mut self.inner, v => { v.get(key, version).await })
+		self.delay().await;
+		let val = expand_inner!(&mut self.inner, v => { v.get(key.clone(), version).await })?;
+		let val = val.map(|v| self.open(&key, v)).transpose()?;
+		if version.is_none() {
+			self.savepoints.read(&key, &val);
+		}
+		Ok(val)
 	}
 
 	/// Fetch many keys from the datastore.
@@ -235,7 +269,17 @@ impl Transactor {
 	{
 		let keys = keys.into_iter().map(Into::into).collect::<Vec<Key>>();
 		trace!(target: TARGET, keys = keys.sprint(), "GetM");
-		expand_inner!(&mut self.inner, v => { v.getm(keys).await })
+		self.delay().await;
+		let vals = expand_inner!(&mut self.inner, v => { v.getm(keys.clone()).await })?;
+		let vals = keys
+			.iter()
+			.zip(vals)
+			.map(|(k, v)| v.map(|v| self.open(k, v)).transpose())
+			.collect::<Result<Vec<_>, Error>>()?;
+		for (key, val) in keys.iter().zip(vals.iter()) {
+			self.savepoints.read(key, val);
+		}
+		Ok(vals)
 	}
 
 	/// Retrieve a specific range of keys from the datastore.
@@ -254,7 +298,9 @@ impl Transactor {
 		let end: Key = rng.end.into();
 		let rng = beg.as_slice()..end.as_slice();
 		trace!(target: TARGET, rng = rng.sprint(), version = version, "GetR");
-		expand_inner!(&mut self.inner, v => { v.getr(beg..end, version).await })
+		self.delay().await;
+		let res = expand_inner!(&mut self.inner, v => { v.getr(beg..end, version).await })?;
+		self.open_pairs(res)
 	}
 
 	/// Retrieve a specific prefixed range of keys from the datastore.
@@ -267,7 +313,9 @@ impl Transactor {
 	{
 		let key: Key = key.into();
 		trace!(target: TARGET, key = key.sprint(), "GetP");
-		expand_inner!(&mut self.inner, v => { v.getp(key).await })
+		self.delay().await;
+		let res = expand_inner!(&mut self.inner, v => { v.getp(key).await })?;
+		self.open_pairs(res)
 	}
 
 	/// Insert or update a key in the datastore.
@@ -279,6 +327,9 @@ impl Transactor {
 	{
 		let key = key.into();
 		trace!(target: TARGET, key = key.sprint(), version = version, "Set");
+		self.delay().await;
+		self.save_key(&key).await?;
+		let val = self.seal(&key, val)?;
 		expand_inner!(&mut self.inner, v => { v.set(key, val, version).await })
 	}
 
@@ -291,6 +342,9 @@ impl Transactor {
 	{
 		let key = key.into();
 		trace!(target: TARGET, key = key.sprint(), "Replace");
+		self.delay().await;
+		self.save_key(&key).await?;
+		let val = self.seal(&key, val)?;
 		expand_inner!(&mut self.inner, v => { v.replace(key, val).await })
 	}
 
@@ -303,7 +357,12 @@ impl Transactor {
 	{
 		let key = key.into();
 		trace!(target: TARGET, key = key.sprint(), version = version, "Put");
-		expand_inner!(&mut self.inner, v => { v.put(key, val, version).await })
+		self.delay().await;
+		let val = self.seal(&key, val)?;
+		expand_inner!(&mut self.inner, v => { v.put(key.clone(), val, version).await })?;
+		// The put only succeeds if the key did not exist
+		self.save_value(key, None);
+		Ok(())
 	}
 
 	/// Update a key in the datastore if the current value matches a condition.
@@ -315,7 +374,14 @@ impl Transactor {
 	{
 		let key = key.into();
 		trace!(target: TARGET, key = key.sprint(), "PutC");
-		expand_inner!(&mut self.inner, v => { v.putc(key, val, chk).await })
+		self.delay().await;
+		let val = self.seal(&key, val)?;
+		let old: Option<Val> = chk.map(Into::into);
+		let chk = self.condition(&key, old.clone()).await?;
+		expand_inner!(&mut self.inner, v => { v.putc(key.clone(), val, chk).await })?;
+		// The put only succeeds if the key matched the check
+		self.save_value(key, old);
+		Ok(())
 	}
 
 	/// Delete a key from the datastore.
@@ -326,6 +392,8 @@ impl Transactor {
 	{
 		let key = key.into();
 		trace!(target: TARGET, key = key.sprint(), "Del");
+		self.delay().await;
+		self.save_key(&key).await?;
 		expand_inner!(&mut self.inner, v => { v.del(key).await })
 	}
 
@@ -338,7 +406,13 @@ impl Transactor {
 	{
 		let key = key.into();
 		trace!(target: TARGET, key = key.sprint(), "DelC");
-		expand_inner!(&mut self.inner, v => { v.delc(key, chk).await })
+		self.delay().await;
+		let old: Option<Val> = chk.map(Into::into);
+		let chk = self.condition(&key, old.clone()).await?;
+		expand_inner!(&mut self.inner, v => { v.delc(key.clone(), chk).await })?;
+		// The delete only succeeds if the key matched the check
+		self.save_value(key, old);
+		Ok(())
 	}
 
 	/// Delete a range of keys from the datastore.
@@ -353,6 +427,8 @@ impl Transactor {
 		let end: Key = rng.end.into();
 		let rng = beg.as_slice()..end.as_slice();
 		trace!(target: TARGET, rng = rng.sprint(), "DelR");
+		self.delay().await;
+		self.save_range(beg.clone()..end.clone()).await?;
 		expand_inner!(&mut self.inner, v => { v.delr(beg..end).await })
 	}
 
@@ -366,6 +442,8 @@ impl Transactor {
 	{
 		let key: Key = key.into();
 		trace!(target: TARGET, key = key.sprint(), "DelP");
+		self.delay().await;
+		self.save_prefix(&key).await?;
 		expand_inner!(&mut self.inner, v => { v.delp(key).await })
 	}
 
@@ -377,6 +455,8 @@ impl Transactor {
 	{
 		let key = key.into();
 		trace!(target: TARGET, key = key.sprint(), "Clr");
+		self.delay().await;
+		self.save_key(&key).await?;
 		expand_inner!(&mut self.inner, v => { v.clr(key).await })
 	}
 
@@ -389,7 +469,13 @@ impl Transactor {
 	{
 		let key = key.into();
 		trace!(target: TARGET, key = key.sprint(), "ClrC");
-		expand_inner!(&mut self.inner, v => { v.clrc(key, chk).await })
+		self.delay().await;
+		let old: Option<Val> = chk.map(Into::into);
+		let chk = self.condition(&key, old.clone()).await?;
+		expand_inner!(&mut self.inner, v => { v.clrc(key.clone(), chk).await })?;
+		// The delete only succeeds if the key matched the check
+		self.save_value(key, old);
+		Ok(())
 	}
 
 	/// Delete all versions of a range of keys from the datastore.
@@ -404,6 +490,8 @@ impl Transactor {
 		let end: Key = rng.end.into();
 		let rng = beg.as_slice()..end.as_slice();
 		trace!(target: TARGET, rng = rng.sprint(), "ClrR");
+		self.delay().await;
+		self.save_range(beg.clone()..end.clone()).await?;
 		expand_inner!(&mut self.inner, v => { v.clrr(beg..end).await })
 	}
 
@@ -417,6 +505,8 @@ impl Transactor {
 	{
 		let key: Key = key.into();
 		trace!(target: TARGET, key = key.sprint(), "ClrP");
+		self.delay().await;
+		self.save_prefix(&key).await?;
 		expand_inner!(&mut self.inner, v => { v.clrp(key).await })
 	}
 
@@ -437,6 +527,7 @@ impl Transactor {
 		let end: Key = rng.end.into();
 		let rng = beg.as_slice()..end.as_slice();
 		trace!(target: TARGET, rng = rng.sprint(), limit = limit, version = version, "Keys");
+		self.delay().await;
 		if beg > end {
 			return Ok(vec![]);
 		}
@@ -460,10 +551,12 @@ impl Transactor {
 		let end: Key = rng.end.into();
 		let rng = beg.as_slice()..end.as_slice();
 		trace!(target: TARGET, rng = rng.sprint(), limit = limit, version = version, "Scan");
+		self.delay().await;
 		if beg > end {
 			return Ok(vec![]);
 		}
-		expand_inner!(&mut self.inner, v => { v.scan(beg..end, limit, version).await })
+		let res = expand_inner!(&mut self.inner, v => { v.scan(beg..end, limit, version).await })?;
+		self.open_pairs(res)
 	}
 
 	/// Retrieve a batched scan over a specific range of keys in the datastore.
@@ -484,7 +577,9 @@ impl Transactor {
 		let end: Key = rng.end.into();
 		let rng = beg.as_slice()..end.as_slice();
 		trace!(target: TARGET, rng = rng.sprint(), values = values, version = version, "Batch");
-		expand_inner!(&mut self.inner, v => { v.batch(beg..end, batch, values, version).await })
+		self.delay().await;
+		let res = expand_inner!(&mut self.inner, v => { v.batch(beg..end, batch, values, version).await })?;
+		self.open_batch(res)
 	}
 
 	/// Retrieve a batched scan of all versions over a specific range of keys in the datastore.
@@ -499,7 +594,9 @@ impl Transactor {
 		let end: Key = rng.end.into();
 		let rng = beg.as_slice()..end.as_slice();
 		trace!(target: TARGET, rng = rng.sprint(), "BatchVersions");
-		expand_inner!(&mut self.inner, v => { v.batch_versions(beg..end, batch).await })
+		self.delay().await;
+		let res = expand_inner!(&mut self.inner, v => { v.batch_versions(beg..end, batch).await })?;
+		self.open_batch(res)
 	}
 
 	/// Obtain a new change timestamp for a key
@@ -530,6 +627,15 @@ impl Transactor {
 		let ts_key = ts_key.into();
 		let prefix = prefix.into();
 		let suffix = suffix.into();
+		// Encrypted values are bound to the key at which they are stored,
+		// so the timestamp is fetched and the final key is built before
+		// the value is sealed, instead of when the transaction commits.
+		if self.cipher.is_some() {
+			let ts = self.get_timestamp(ts_key).await?;
+			let key = [prefix.as_slice(), ts.as_slice(), suffix.as_slice()].concat();
+			return self.set(key, val, None).await;
+		}
+		let val = self.seal(&prefix, val)?;
 		expand_inner!(&mut self.inner, v => { v.set_versionstamp(ts_key, prefix, suffix, val).await })
 	}
 
@@ -765,4 +871,174 @@ impl Transactor {
 	pub(crate) async fn release_last_save_point(&mut self) -> Result<(), Error> {
 		expand_inner!(&mut self.inner, v => { v.release_last_save_point() })
 	}
+
+	/// Create a new savepoint for a `SAVEPOINT` statement.
+	pub(crate) fn new_user_save_point(&mut self, name: &str) {
+		let changes = self.cf.marks();
+		self.savepoints.push(name, changes)
+	}
+
+	/// Check if a savepoint has been created with a `SAVEPOINT` statement.
+	pub(crate) fn has_user_save_points(&self) -> bool {
+		!self.savepoints.is_empty()
+	}
+
+	/// Revert all changes made since the named savepoint was created,
+	/// removing any savepoints which were created after it.
+	pub(crate) async fn rollback_to_user_save_point(&mut self, name: &str) -> Result<(), Error> {
+		let (saved, changes) = self.savepoints.rollback(name)?;
+		// Restore the keys directly, so that the reverted
+		// values are not saved into an earlier savepoint
+		for (key, val) in saved {
+			match val {
+				Some(val) => {
+					let val = self.seal(&key, val)?;
+					expand_inner!(&mut self.inner, v => { v.set(key, val, None).await })?
+				}
+				None => expand_inner!(&mut self.inner, v => { v.del(key).await })?,
+			}
+		}
+		// Discard any change feed mutations recorded since
+		self.cf.truncate(&changes);
+		// The cached sequences may have been reverted
+		self.stash.0.clear();
+		Ok(())
+	}
+
+	/// Release the named savepoint, and any savepoints created
+	/// after it, keeping all changes made since it was created.
+	pub(crate) fn release_user_save_point(&mut self, name: &str) -> Result<(), Error> {
+		self.savepoints.release(name)
+	}
+
+	/// Save the current value of a key, if a `SAVEPOINT` is active
+	/// and the key has not yet been modified since it was created.
+	/// The key is only fetched if it has not been read since it was
+	/// last modified.
+	async fn save_key(&mut self, key: &Key) -> Result<(), Error> {
+		if self.savepoints.is_empty() {
+			return Ok(());
+		}
+		let read = self.savepoints.take(key);
+		if self.savepoints.needs(key) {
+			let val = match read {
+				Some(val) => val,
+				None => {
+					let val =
+						expand_inner!(&mut self.inner, v => { v.get(key.clone(), None).await })?;
+					val.map(|v| self.open(key, v)).transpose()?
+				}
+			};
+			self.savepoints.save(key.clone(), val);
+		}
+		Ok(())
+	}
+
+	/// Save the known previous value of a key which has been
+	/// modified, if a `SAVEPOINT` is active and the key has not
+	/// yet been modified since it was created.
+	fn save_value(&mut self, key: Key, val: Option<Val>) {
+		if !self.savepoints.is_empty() {
+			self.savepoints.take(&key);
+			if self.savepoints.needs(&key) {
+				self.savepoints.save(key, val);
+			}
+		}
+	}
+
+	/// Save the current values of a range of keys, if a `SAVEPOINT` is active.
+	async fn save_range(&mut self, rng: Range<Key>) -> Result<(), Error> {
+		if !self.savepoints.is_empty() {
+			self.savepoints.forget(&rng);
+			for (key, val) in self.getr(rng, None).await? {
+				self.savepoints.save(key, Some(val));
+			}
+		}
+		Ok(())
+	}
+
+	/// Save the current values of a prefixed range of keys, if a `SAVEPOINT` is active.
+	async fn save_prefix(&mut self, key: &Key) -> Result<(), Error> {
+		if !self.savepoints.is_empty() {
+			for (key, val) in self.getp(key.clone()).await? {
+				self.savepoints.take(&key);
+				self.savepoints.save(key, Some(val));
+			}
+		}
+		Ok(())
+	}
+
+	/// Wait for the latency which is injected into storage operations
+	async fn delay(&self) {
+		if let Some(faults) = &self.faults {
+			faults.delay().await;
+		}
+	}
+
+	/// Compress and encrypt a value before it is written, if enabled.
+	/// The progress of a rekey is never encrypted, so that it can be
+	/// read whichever storage encryption key the datastore is using.
+	fn seal<V>(&self, key: &[u8], val: V) -> Result<Val, Error>
+	where
+		V: Into<Val>,
+	{
+		let mut val = val.into();
+		if let Some(c) = &self.compression {
+			val = c.compress(val)?;
+		}
+		match &self.cipher {
+			Some(c) if !crate::key::rekey::is(key) => c.seal(key, val),
+			_ => Ok(val),
+		}
+	}
+
+	/// Decrypt and decompress a value after it is read, if necessary.
+	fn open(&self, key: &[u8], val: Val) -> Result<Val, Error> {
+		let val = match &self.cipher {
+			Some(c) if !crate::key::rekey::is(key) => c.open(key, val)?,
+			_ => val,
+		};
+		match &self.compression {
+			Some(_) => compression::decompress(val),
+			None => Ok(val),
+		}
+	}
+
+	/// Convert the expected value of a conditional write into the value
+	/// which is compared with the stored value. Encrypted values are sealed
+	/// with a random nonce, and a value may be stored compressed or not, so
+	/// the stored value is fetched and decoded, and if it matches, the stored
+	/// value is used as the condition.
+	async fn condition(&mut self, key: &Key, chk: Option<Val>) -> Result<Option<Val>, Error> {
+		let Some(chk) = chk else {
+			return Ok(None);
+		};
+		if self.cipher.is_none() && self.compression.is_none() {
+			return Ok(Some(chk));
+		}
+		let val = expand_inner!(&mut self.inner, v => { v.get(key.clone(), None).await })?;
+		match val {
+			Some(val) if self.open(key, val.clone())? == chk => Ok(Some(val)),
+			_ => Err(Error::TxConditionNotMet),
+		}
+	}
+
+	/// Decrypt and decompress the values of fetched key-value pairs.
+	fn open_pairs(&self, res: Vec<(Key, Val)>) -> Result<Vec<(Key, Val)>, Error> {
+		res.into_iter()
+			.map(|(k, v)| {
+				let v = self.open(&k, v)?;
+				Ok((k, v))
+			})
+			.collect()
+	}
+
+	/// Decrypt and decompress the values of a batched scan.
+	fn open_batch(&self, mut res: Batch) -> Result<Batch, Error> {
+		res.values = self.open_pairs(res.values)?;
+		for (k, v, _, _) in res.versioned_values.iter_mut() {
+			*v = self.open(k, std::mem::take(v))?;
+		}
+		Ok(res)
+	}
 }
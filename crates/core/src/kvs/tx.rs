@@ -4,6 +4,7 @@ use super::Convert;
 use super::Key;
 use super::Val;
 use crate::cnf::NORMAL_FETCH_SIZE;
+use crate::dbs::metrics::{self, Event};
 use crate::dbs::node::Node;
 use crate::err::Error;
 use crate::kvs::cache;
@@ -22,6 +23,7 @@ use crate::sql::statements::DefineIndexStatement;
 use crate::sql::statements::DefineModelStatement;
 use crate::sql::statements::DefineNamespaceStatement;
 use crate::sql::statements::DefineParamStatement;
+use crate::sql::statements::DefineSequenceStatement;
 use crate::sql::statements::DefineTableStatement;
 use crate::sql::statements::DefineUserStatement;
 use crate::sql::statements::LiveStatement;
@@ -394,11 +396,21 @@ impl Transaction {
 	// Cache methods
 	// --------------------------------------------------
 
+	/// Fetch an entry from the transaction cache, recording whether it was found.
+	fn cached(&self, qey: &cache::tx::Lookup) -> Option<cache::tx::Entry> {
+		let val = self.cache.get(qey);
+		metrics::record(match val {
+			Some(_) => Event::CacheHit,
+			None => Event::CacheMiss,
+		});
+		val
+	}
+
 	/// Retrieve all nodes belonging to this cluster.
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn all_nodes(&self) -> Result<Arc<[Node]>, Error> {
 		let qey = cache::tx::Lookup::Nds;
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::root::nd::prefix();
@@ -417,7 +429,7 @@ impl Transaction {
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn all_root_users(&self) -> Result<Arc<[DefineUserStatement]>, Error> {
 		let qey = cache::tx::Lookup::Rus;
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::root::us::prefix();
@@ -436,7 +448,7 @@ impl Transaction {
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn all_root_accesses(&self) -> Result<Arc<[DefineAccessStatement]>, Error> {
 		let qey = cache::tx::Lookup::Ras;
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::root::ac::prefix();
@@ -455,7 +467,7 @@ impl Transaction {
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn all_root_access_grants(&self, ra: &str) -> Result<Arc<[AccessGrant]>, Error> {
 		let qey = cache::tx::Lookup::Rgs(ra);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::root::access::gr::prefix(ra);
@@ -474,7 +486,7 @@ impl Transaction {
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn all_ns(&self) -> Result<Arc<[DefineNamespaceStatement]>, Error> {
 		let qey = cache::tx::Lookup::Nss;
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::root::ns::prefix();
@@ -493,7 +505,7 @@ impl Transaction {
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn all_ns_users(&self, ns: &str) -> Result<Arc<[DefineUserStatement]>, Error> {
 		let qey = cache::tx::Lookup::Nus(ns);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::namespace::us::prefix(ns);
@@ -512,7 +524,7 @@ impl Transaction {
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn all_ns_accesses(&self, ns: &str) -> Result<Arc<[DefineAccessStatement]>, Error> {
 		let qey = cache::tx::Lookup::Nas(ns);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::namespace::ac::prefix(ns);
@@ -535,7 +547,7 @@ impl Transaction {
 		na: &str,
 	) -> Result<Arc<[AccessGrant]>, Error> {
 		let qey = cache::tx::Lookup::Ngs(ns, na);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::namespace::access::gr::prefix(ns, na);
@@ -554,7 +566,7 @@ impl Transaction {
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn all_db(&self, ns: &str) -> Result<Arc<[DefineDatabaseStatement]>, Error> {
 		let qey = cache::tx::Lookup::Dbs(ns);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::namespace::db::prefix(ns);
@@ -577,7 +589,7 @@ impl Transaction {
 		db: &str,
 	) -> Result<Arc<[DefineUserStatement]>, Error> {
 		let qey = cache::tx::Lookup::Dus(ns, db);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::database::us::prefix(ns, db);
@@ -600,7 +612,7 @@ impl Transaction {
 		db: &str,
 	) -> Result<Arc<[DefineAccessStatement]>, Error> {
 		let qey = cache::tx::Lookup::Das(ns, db);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::database::ac::prefix(ns, db);
@@ -624,7 +636,7 @@ impl Transaction {
 		da: &str,
 	) -> Result<Arc<[AccessGrant]>, Error> {
 		let qey = cache::tx::Lookup::Dgs(ns, db, da);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::database::access::gr::prefix(ns, db, da);
@@ -647,7 +659,7 @@ impl Transaction {
 		db: &str,
 	) -> Result<Arc<[DefineAnalyzerStatement]>, Error> {
 		let qey = cache::tx::Lookup::Azs(ns, db);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::database::az::prefix(ns, db);
@@ -670,7 +682,7 @@ impl Transaction {
 		db: &str,
 	) -> Result<Arc<[DefineFunctionStatement]>, Error> {
 		let qey = cache::tx::Lookup::Fcs(ns, db);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::database::fc::prefix(ns, db);
@@ -693,7 +705,7 @@ impl Transaction {
 		db: &str,
 	) -> Result<Arc<[DefineParamStatement]>, Error> {
 		let qey = cache::tx::Lookup::Pas(ns, db);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::database::pa::prefix(ns, db);
@@ -708,6 +720,29 @@ impl Transaction {
 		.try_into_pas()
 	}
 
+	/// Retrieve all sequence definitions for a specific database.
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
+	pub async fn all_db_sequences(
+		&self,
+		ns: &str,
+		db: &str,
+	) -> Result<Arc<[DefineSequenceStatement]>, Error> {
+		let qey = cache::tx::Lookup::Sqs(ns, db);
+		match self.cached(&qey) {
+			Some(val) => val,
+			None => {
+				let beg = crate::key::database::sq::prefix(ns, db);
+				let end = crate::key::database::sq::suffix(ns, db);
+				let val = self.getr(beg..end, None).await?;
+				let val = val.convert().into();
+				let val = cache::tx::Entry::Sqs(Arc::clone(&val));
+				self.cache.insert(qey.into(), val.clone());
+				val
+			}
+		}
+		.try_into_sqs()
+	}
+
 	/// Retrieve all model definitions for a specific database.
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn all_db_models(
@@ -716,7 +751,7 @@ impl Transaction {
 		db: &str,
 	) -> Result<Arc<[DefineModelStatement]>, Error> {
 		let qey = cache::tx::Lookup::Mls(ns, db);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::database::ml::prefix(ns, db);
@@ -739,7 +774,7 @@ impl Transaction {
 		db: &str,
 	) -> Result<Arc<[DefineConfigStatement]>, Error> {
 		let qey = cache::tx::Lookup::Cgs(ns, db);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::database::cg::prefix(ns, db);
@@ -763,7 +798,7 @@ impl Transaction {
 		version: Option<u64>,
 	) -> Result<Arc<[DefineTableStatement]>, Error> {
 		let qey = cache::tx::Lookup::Tbs(ns, db);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::database::tb::prefix(ns, db);
@@ -787,7 +822,7 @@ impl Transaction {
 		tb: &str,
 	) -> Result<Arc<[DefineEventStatement]>, Error> {
 		let qey = cache::tx::Lookup::Evs(ns, db, tb);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::table::ev::prefix(ns, db, tb);
@@ -812,7 +847,7 @@ impl Transaction {
 		version: Option<u64>,
 	) -> Result<Arc<[DefineFieldStatement]>, Error> {
 		let qey = cache::tx::Lookup::Fds(ns, db, tb);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::table::fd::prefix(ns, db, tb);
@@ -836,7 +871,7 @@ impl Transaction {
 		tb: &str,
 	) -> Result<Arc<[DefineIndexStatement]>, Error> {
 		let qey = cache::tx::Lookup::Ixs(ns, db, tb);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::table::ix::prefix(ns, db, tb);
@@ -860,7 +895,7 @@ impl Transaction {
 		tb: &str,
 	) -> Result<Arc<[DefineTableStatement]>, Error> {
 		let qey = cache::tx::Lookup::Fts(ns, db, tb);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::table::ft::prefix(ns, db, tb);
@@ -884,7 +919,7 @@ impl Transaction {
 		tb: &str,
 	) -> Result<Arc<[LiveStatement]>, Error> {
 		let qey = cache::tx::Lookup::Lvs(ns, db, tb);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let beg = crate::key::table::lq::prefix(ns, db, tb);
@@ -903,7 +938,7 @@ impl Transaction {
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn get_node(&self, id: Uuid) -> Result<Arc<Node>, Error> {
 		let qey = cache::tx::Lookup::Nd(id);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::root::nd::new(id).encode()?;
@@ -923,7 +958,7 @@ impl Transaction {
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn get_root_user(&self, us: &str) -> Result<Arc<DefineUserStatement>, Error> {
 		let qey = cache::tx::Lookup::Ru(us);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::root::us::new(us).encode()?;
@@ -943,7 +978,7 @@ impl Transaction {
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn get_root_access(&self, ra: &str) -> Result<Arc<DefineAccessStatement>, Error> {
 		let qey = cache::tx::Lookup::Ra(ra);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::root::ac::new(ra).encode()?;
@@ -967,7 +1002,7 @@ impl Transaction {
 		gr: &str,
 	) -> Result<Arc<AccessGrant>, Error> {
 		let qey = cache::tx::Lookup::Rg(ac, gr);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::root::access::gr::new(ac, gr).encode()?;
@@ -989,7 +1024,7 @@ impl Transaction {
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn get_ns(&self, ns: &str) -> Result<Arc<DefineNamespaceStatement>, Error> {
 		let qey = cache::tx::Lookup::Ns(ns);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::root::ns::new(ns).encode()?;
@@ -1009,7 +1044,7 @@ impl Transaction {
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn get_ns_user(&self, ns: &str, us: &str) -> Result<Arc<DefineUserStatement>, Error> {
 		let qey = cache::tx::Lookup::Nu(ns, us);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::namespace::us::new(ns, us).encode()?;
@@ -1034,7 +1069,7 @@ impl Transaction {
 		na: &str,
 	) -> Result<Arc<DefineAccessStatement>, Error> {
 		let qey = cache::tx::Lookup::Na(ns, na);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::namespace::ac::new(ns, na).encode()?;
@@ -1060,7 +1095,7 @@ impl Transaction {
 		gr: &str,
 	) -> Result<Arc<AccessGrant>, Error> {
 		let qey = cache::tx::Lookup::Ng(ns, ac, gr);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::namespace::access::gr::new(ns, ac, gr).encode()?;
@@ -1083,7 +1118,7 @@ impl Transaction {
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn get_db(&self, ns: &str, db: &str) -> Result<Arc<DefineDatabaseStatement>, Error> {
 		let qey = cache::tx::Lookup::Db(ns, db);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::namespace::db::new(ns, db).encode()?;
@@ -1108,7 +1143,7 @@ impl Transaction {
 		us: &str,
 	) -> Result<Arc<DefineUserStatement>, Error> {
 		let qey = cache::tx::Lookup::Du(ns, db, us);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::database::us::new(ns, db, us).encode()?;
@@ -1135,7 +1170,7 @@ impl Transaction {
 		da: &str,
 	) -> Result<Arc<DefineAccessStatement>, Error> {
 		let qey = cache::tx::Lookup::Da(ns, db, da);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::database::ac::new(ns, db, da).encode()?;
@@ -1163,7 +1198,7 @@ impl Transaction {
 		gr: &str,
 	) -> Result<Arc<AccessGrant>, Error> {
 		let qey = cache::tx::Lookup::Dg(ns, db, ac, gr);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::database::access::gr::new(ns, db, ac, gr).encode()?;
@@ -1193,7 +1228,7 @@ impl Transaction {
 		vn: &str,
 	) -> Result<Arc<DefineModelStatement>, Error> {
 		let qey = cache::tx::Lookup::Ml(ns, db, ml, vn);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::database::ml::new(ns, db, ml, vn).encode()?;
@@ -1218,7 +1253,7 @@ impl Transaction {
 		az: &str,
 	) -> Result<Arc<DefineAnalyzerStatement>, Error> {
 		let qey = cache::tx::Lookup::Az(ns, db, az);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::database::az::new(ns, db, az).encode()?;
@@ -1243,7 +1278,7 @@ impl Transaction {
 		fc: &str,
 	) -> Result<Arc<DefineFunctionStatement>, Error> {
 		let qey = cache::tx::Lookup::Fc(ns, db, fc);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::database::fc::new(ns, db, fc).encode()?;
@@ -1268,7 +1303,7 @@ impl Transaction {
 		pa: &str,
 	) -> Result<Arc<DefineParamStatement>, Error> {
 		let qey = cache::tx::Lookup::Pa(ns, db, pa);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::database::pa::new(ns, db, pa).encode()?;
@@ -1284,6 +1319,31 @@ impl Transaction {
 		.try_into_type()
 	}
 
+	/// Retrieve a specific sequence definition from a database.
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
+	pub async fn get_db_sequence(
+		&self,
+		ns: &str,
+		db: &str,
+		sq: &str,
+	) -> Result<Arc<DefineSequenceStatement>, Error> {
+		let qey = cache::tx::Lookup::Sq(ns, db, sq);
+		match self.cached(&qey) {
+			Some(val) => val,
+			None => {
+				let key = crate::key::database::sq::new(ns, db, sq).encode()?;
+				let val = self.get(key, None).await?.ok_or_else(|| Error::SqNotFound {
+					value: sq.to_owned(),
+				})?;
+				let val: DefineSequenceStatement = val.into();
+				let val = cache::tx::Entry::Any(Arc::new(val));
+				self.cache.insert(qey.into(), val.clone());
+				val
+			}
+		}
+		.try_into_type()
+	}
+
 	/// Retrieve a specific config definition from a database.
 	#[instrument(level = "trace", target = "surrealdb::core::kvs::tx", skip(self))]
 	pub async fn get_db_config(
@@ -1293,7 +1353,7 @@ impl Transaction {
 		cg: &str,
 	) -> Result<Arc<DefineConfigStatement>, Error> {
 		let qey = cache::tx::Lookup::Cg(ns, db, cg);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::database::cg::new(ns, db, cg).encode()?;
@@ -1318,7 +1378,7 @@ impl Transaction {
 		tb: &str,
 	) -> Result<Arc<DefineTableStatement>, Error> {
 		let qey = cache::tx::Lookup::Tb(ns, db, tb);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::database::tb::new(ns, db, tb).encode()?;
@@ -1344,7 +1404,7 @@ impl Transaction {
 		ev: &str,
 	) -> Result<Arc<DefineEventStatement>, Error> {
 		let qey = cache::tx::Lookup::Ev(ns, db, tb, ev);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::table::ev::new(ns, db, tb, ev).encode()?;
@@ -1370,7 +1430,7 @@ impl Transaction {
 		fd: &str,
 	) -> Result<Arc<DefineFieldStatement>, Error> {
 		let qey = cache::tx::Lookup::Fd(ns, db, tb, fd);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::table::fd::new(ns, db, tb, fd).encode()?;
@@ -1396,7 +1456,7 @@ impl Transaction {
 		ix: &str,
 	) -> Result<Arc<DefineIndexStatement>, Error> {
 		let qey = cache::tx::Lookup::Ix(ns, db, tb, ix);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			Some(val) => val,
 			None => {
 				let key = crate::key::table::ix::new(ns, db, tb, ix).encode()?;
@@ -1422,7 +1482,7 @@ impl Transaction {
 		id: &Id,
 	) -> Result<Arc<Value>, Error> {
 		let qey = cache::tx::Lookup::Record(ns, db, tb, id);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			// The entry is in the cache
 			Some(val) => val.try_into_val(),
 			// The entry is not in the cache
@@ -1585,7 +1645,7 @@ impl Transaction {
 		_upwards: bool,
 	) -> Result<Arc<DefineNamespaceStatement>, Error> {
 		let qey = cache::tx::Lookup::Ns(ns);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			// The entry is in the cache
 			Some(val) => val,
 			// The entry is not in the cache
@@ -1637,7 +1697,7 @@ impl Transaction {
 		upwards: bool,
 	) -> Result<Arc<DefineDatabaseStatement>, Error> {
 		let qey = cache::tx::Lookup::Db(ns, db);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			// The entry is in the cache
 			Some(val) => val,
 			// The entry is not in the cache
@@ -1704,7 +1764,7 @@ impl Transaction {
 		upwards: bool,
 	) -> Result<Arc<DefineTableStatement>, Error> {
 		let qey = cache::tx::Lookup::Tb(ns, db, tb);
-		match self.cache.get(&qey) {
+		match self.cached(&qey) {
 			// The entry is in the cache
 			Some(val) => val,
 			// The entry is not in the cache
@@ -70,10 +70,8 @@ async fn migrate_tb_records(
 
 async fn migrate_tb_edges(tx: Arc<Transaction>, ns: &str, db: &str, tb: &str) -> Result<(), Error> {
 	// mutable beg, as we update it each iteration to the last record id + a null byte
-	let mut beg = crate::key::table::all::new(ns, db, tb).encode()?;
-	beg.extend_from_slice(&[b'~', 0x00]);
-	let mut end = crate::key::table::all::new(ns, db, tb).encode()?;
-	end.extend_from_slice(&[b'~', 0xff]);
+	let mut beg = crate::key::graph::tbprefix(ns, db, tb);
+	let end = crate::key::graph::tbsuffix(ns, db, tb);
 
 	// We need to scan ALL keys and queue them first,
 	// because if we fix them as we iterate, the pagination is off
@@ -0,0 +1,227 @@
+use crate::cnf::{WEBHOOK_BATCH_SIZE, WEBHOOK_MAX_ATTEMPTS, WEBHOOK_TIMEOUT};
+use crate::err::Error;
+use crate::kvs::Datastore;
+use crate::kvs::{LockType::*, TransactionType::*};
+use crate::sql::{Object, Value};
+use derive::Store;
+use revision::revisioned;
+use serde::{Deserialize, Serialize};
+use uuid::Uuid;
+
+const TARGET: &str = "surrealdb::core::kvs::webhook";
+
+/// The longest delay between two delivery attempts of a webhook, in seconds
+const MAX_RETRY_DELAY: u64 = 3600;
+
+/// A webhook request which is queued for delivery in the background
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Default, Eq, PartialEq, Serialize, Deserialize, Store)]
+#[non_exhaustive]
+pub struct Webhook {
+	/// The URL to which the request is sent
+	pub url: String,
+	/// The body of the request
+	pub body: Value,
+	/// The headers of the request
+	pub headers: Object,
+	/// The number of failed delivery attempts so far
+	pub attempts: u32,
+	/// The time at which the next delivery attempt is due
+	pub next: u64,
+	/// The error of the last failed delivery attempt
+	pub error: Option<String>,
+}
+
+impl Webhook {
+	/// Returns the time of the next attempt, after a failed attempt at the specified time
+	fn retry_at(&self, ts: u64) -> u64 {
+		ts + 2u64.saturating_pow(self.attempts).min(MAX_RETRY_DELAY)
+	}
+}
+
+impl Datastore {
+	/// Fetches the queued webhooks in each database which are due at or before the timestamp.
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::ds", skip(self))]
+	pub(crate) async fn webhook_delivery_scan(
+		&self,
+		ts: u64,
+	) -> Result<Vec<(String, String, u64, Uuid)>, Error> {
+		// Store the due webhooks
+		let mut out = Vec::new();
+		// Create a new transaction
+		let txn = self.transaction(Read, Optimistic).await?;
+		// Fetch all namespaces
+		let nss = catch!(txn, txn.all_ns().await);
+		// Loop over all namespaces
+		for ns in nss.iter() {
+			// Get the namespace name
+			let ns = &ns.name;
+			// Fetch all databases
+			let dbs = catch!(txn, txn.all_db(ns).await);
+			// Loop over all databases
+			for db in dbs.iter() {
+				// Get the database name
+				let db = &db.name;
+				// Fetch the webhooks which are due
+				let beg = crate::key::database::wh::prefix(ns, db);
+				let end = crate::key::database::wh::suffix(ns, db, ts);
+				let keys = catch!(txn, txn.keys(beg..end, *WEBHOOK_BATCH_SIZE, None).await);
+				// Loop over the due webhooks
+				for key in keys.iter() {
+					let wh = catch!(txn, crate::key::database::wh::Wh::decode(key));
+					out.push((ns.to_string(), db.to_string(), wh.ts, wh.id));
+				}
+			}
+		}
+		// Cancel the transaction
+		catch!(txn, txn.cancel().await);
+		// Return the due webhooks
+		Ok(out)
+	}
+
+	/// Claims the delivery of a queued webhook, which was due at the specified time.
+	///
+	/// The next attempt is pushed back for the duration of the request timeout,
+	/// so when there are multiple nodes in a cluster, only the node which
+	/// successfully commits the claim will go on to deliver the webhook.
+	async fn webhook_delivery_claim(
+		&self,
+		ns: &str,
+		db: &str,
+		due: u64,
+		id: Uuid,
+		ts: u64,
+	) -> Result<Option<Webhook>, Error> {
+		// Create a new transaction
+		let txn = self.transaction(Write, Optimistic).await?;
+		// Fetch the queued webhook
+		let key = crate::key::database::wh::new(ns, db, due, id);
+		let mut hook = match catch!(txn, txn.get(key.clone(), None).await) {
+			Some(val) => Webhook::from(val),
+			None => {
+				catch!(txn, txn.cancel().await);
+				return Ok(None);
+			}
+		};
+		// Push back the next attempt
+		hook.next = ts + *WEBHOOK_TIMEOUT + 1;
+		catch!(txn, txn.del(key).await);
+		let key = crate::key::database::wh::new(ns, db, hook.next, id);
+		catch!(txn, txn.set(key, hook.clone(), None).await);
+		// Commit the claim, which fails if another node claimed the delivery first
+		match txn.commit().await {
+			Ok(_) => Ok(Some(hook)),
+			Err(Error::TxRetryable) => Ok(None),
+			Err(e) => Err(e),
+		}
+	}
+
+	/// Sends a queued webhook request
+	#[cfg(feature = "http")]
+	async fn webhook_delivery_send(&self, hook: &Webhook) -> Result<(), Error> {
+		use crate::dbs::capabilities::NetTarget;
+		// Check that the target is still allowed
+		let url = url::Url::parse(&hook.url).map_err(|_| Error::InvalidUrl(hook.url.clone()))?;
+		let Some(host) = url.host() else {
+			return Err(Error::InvalidUrl(hook.url.clone()));
+		};
+		let target = NetTarget::Host(host.to_owned(), url.port_or_known_default());
+		if !self.allows_network_target(&target) {
+			return Err(Error::NetTargetNotAllowed(target.to_string()));
+		}
+		// Send the request
+		crate::fnc::util::http::deliver(url, hook).await
+	}
+
+	/// Sends a queued webhook request
+	#[cfg(not(feature = "http"))]
+	async fn webhook_delivery_send(&self, _: &Webhook) -> Result<(), Error> {
+		Err(Error::HttpDisabled)
+	}
+
+	/// Delivers all of the queued webhooks which are due at the specified time.
+	///
+	/// A webhook which fails to be delivered is retried with an exponential
+	/// backoff. Once it has failed the maximum number of attempts, it is
+	/// moved to the dead letter keyspace of the database, so that it is
+	/// retained for inspection, but is no longer retried.
+	#[instrument(level = "trace", target = "surrealdb::core::kvs::ds", skip(self))]
+	pub(crate) async fn webhook_delivery_run(&self, ts: u64) -> Result<(), Error> {
+		// Loop over all of the due webhooks
+		for (ns, db, due, id) in self.webhook_delivery_scan(ts).await? {
+			// A failure to deliver one webhook does not hold up the others,
+			// as the webhook is delivered again once its claim has lapsed
+			if let Err(e) = self.webhook_delivery_attempt(&ns, &db, due, id, ts).await {
+				error!(target: TARGET, "Webhook {id} in {ns}/{db} could not be processed: {e}");
+			}
+		}
+		// Everything ok
+		Ok(())
+	}
+
+	/// Delivers a queued webhook, and stores the outcome of the delivery.
+	async fn webhook_delivery_attempt(
+		&self,
+		ns: &str,
+		db: &str,
+		due: u64,
+		id: Uuid,
+		ts: u64,
+	) -> Result<(), Error> {
+		// Check if this node should deliver the webhook
+		let Some(mut hook) = self.webhook_delivery_claim(ns, db, due, id, ts).await? else {
+			return Ok(());
+		};
+		// Attempt to deliver the webhook
+		let res = self.webhook_delivery_send(&hook).await;
+		// Store the outcome of the delivery
+		let txn = self.transaction(Write, Optimistic).await?;
+		let key = crate::key::database::wh::new(ns, db, hook.next, id);
+		catch!(txn, txn.del(key).await);
+		match res {
+			Ok(_) => {
+				trace!(target: TARGET, "Webhook {id} to {} in {ns}/{db} was delivered", hook.url);
+			}
+			Err(e) => {
+				hook.attempts += 1;
+				hook.error = Some(e.to_string());
+				if hook.attempts >= *WEBHOOK_MAX_ATTEMPTS {
+					warn!(
+						target: TARGET,
+						"Webhook {id} to {} in {ns}/{db} failed after {} attempts: {e}",
+						hook.url,
+						hook.attempts
+					);
+					let key = crate::key::database::wd::new(ns, db, id);
+					catch!(txn, txn.set(key, hook, None).await);
+				} else {
+					debug!(
+						target: TARGET,
+						"Webhook {id} to {} in {ns}/{db} failed, and will be retried: {e}",
+						hook.url
+					);
+					hook.next = hook.retry_at(ts);
+					let key = crate::key::database::wh::new(ns, db, hook.next, id);
+					catch!(txn, txn.set(key, hook, None).await);
+				}
+			}
+		}
+		txn.commit().await
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+
+	#[test]
+	fn retry_backoff() {
+		let hook = |attempts| Webhook {
+			attempts,
+			..Webhook::default()
+		};
+		assert_eq!(hook(1).retry_at(100), 102);
+		assert_eq!(hook(5).retry_at(100), 132);
+		assert_eq!(hook(100).retry_at(100), 100 + MAX_RETRY_DELAY);
+	}
+}
@@ -43,6 +43,7 @@ pub mod rpc;
 pub mod sql;
 pub mod syn;
 pub mod sys;
+pub mod testing;
 
 #[cfg(feature = "ml")]
 pub use surrealml as ml;
@@ -10,6 +10,9 @@ pub struct EngineOptions {
 	pub node_membership_check_interval: Duration,
 	pub node_membership_cleanup_interval: Duration,
 	pub changefeed_gc_interval: Duration,
+	pub record_expiry_interval: Duration,
+	pub event_schedule_interval: Duration,
+	pub webhook_delivery_interval: Duration,
 }
 
 impl Default for EngineOptions {
@@ -19,6 +22,9 @@ impl Default for EngineOptions {
 			node_membership_check_interval: Duration::from_secs(15),
 			node_membership_cleanup_interval: Duration::from_secs(300),
 			changefeed_gc_interval: Duration::from_secs(10),
+			record_expiry_interval: Duration::from_secs(10),
+			event_schedule_interval: Duration::from_secs(10),
+			webhook_delivery_interval: Duration::from_secs(1),
 		}
 	}
 }
@@ -40,4 +46,16 @@ impl EngineOptions {
 		self.changefeed_gc_interval = interval;
 		self
 	}
+	pub fn with_record_expiry_interval(mut self, interval: Duration) -> Self {
+		self.record_expiry_interval = interval;
+		self
+	}
+	pub fn with_event_schedule_interval(mut self, interval: Duration) -> Self {
+		self.event_schedule_interval = interval;
+		self
+	}
+	pub fn with_webhook_delivery_interval(mut self, interval: Duration) -> Self {
+		self.webhook_delivery_interval = interval;
+		self
+	}
 }
@@ -0,0 +1,240 @@
+//! Stores the query cursors which are open on an RPC connection.
+use crate::cnf::{CURSOR_IDLE_TIMEOUT, CURSOR_MAX_OPEN};
+use crate::dbs::Session;
+use crate::sql::statements::SelectStatement;
+use crate::sql::{Limit, Start, Value};
+use std::collections::{BTreeMap, HashMap};
+use std::sync::Mutex;
+use std::time::Duration;
+use trice::Instant;
+use uuid::Uuid;
+
+use super::rpc_error::RpcError;
+
+/// A SELECT statement which is fetched from the database in batches
+#[derive(Clone, Debug)]
+#[non_exhaustive]
+pub struct Cursor {
+	/// The statement which is being fetched
+	stm: SelectStatement,
+	/// The variables which were specified for the statement
+	pub(crate) vars: BTreeMap<String, Value>,
+	/// The number of records to fetch in each batch
+	batch: usize,
+	/// The START clause of the original statement
+	start: usize,
+	/// The LIMIT clause of the original statement
+	limit: Option<usize>,
+	/// The number of records which have been fetched so far
+	offset: usize,
+	/// The namespace in which the cursor was opened
+	ns: Option<String>,
+	/// The database in which the cursor was opened
+	db: Option<String>,
+	/// The time at which the cursor was last fetched
+	used: Instant,
+}
+
+impl Cursor {
+	pub(crate) fn new(
+		stm: SelectStatement,
+		vars: BTreeMap<String, Value>,
+		batch: usize,
+		sess: &Session,
+	) -> Result<Self, RpcError> {
+		// A SELECT ONLY statement does not return a list of records
+		if stm.only || batch == 0 {
+			return Err(RpcError::InvalidParams);
+		}
+		// Only fixed START and LIMIT clauses can be paginated
+		let start = match &stm.start {
+			None => 0,
+			Some(Start(Value::Number(v))) => v.as_usize(),
+			Some(_) => return Err(RpcError::InvalidParams),
+		};
+		let limit = match &stm.limit {
+			None => None,
+			Some(Limit(Value::Number(v))) => Some(v.as_usize()),
+			Some(_) => return Err(RpcError::InvalidParams),
+		};
+		Ok(Self {
+			stm,
+			vars,
+			batch,
+			start,
+			limit,
+			offset: 0,
+			ns: sess.ns.clone(),
+			db: sess.db.clone(),
+			used: Instant::now(),
+		})
+	}
+	/// Checks that the session is still using the namespace
+	/// and database in which the cursor was opened, so that
+	/// the statement is not run against a different database.
+	pub(crate) fn check(&self, sess: &Session) -> Result<(), RpcError> {
+		if self.ns != sess.ns || self.db != sess.db {
+			return Err(RpcError::CursorMismatch);
+		}
+		Ok(())
+	}
+	/// Checks if the cursor has not been fetched for too long
+	fn expired(&self) -> bool {
+		self.used.elapsed() >= Duration::from_secs(*CURSOR_IDLE_TIMEOUT)
+	}
+	/// Returns the statement which fetches the next batch of records. One
+	/// more record than the batch size is requested, so that we can tell
+	/// if there are any records remaining after this batch.
+	pub(crate) fn next_statement(&self) -> SelectStatement {
+		let size = match self.limit {
+			Some(limit) => self.batch.min(limit.saturating_sub(self.offset)),
+			None => self.batch,
+		};
+		SelectStatement {
+			start: Some(Start(Value::from(self.start + self.offset))),
+			limit: Some(Limit(Value::from(size + 1))),
+			..self.stm.clone()
+		}
+	}
+	/// Processes the fetched records, returning the records in this batch,
+	/// and whether there are any further records still to be fetched.
+	pub(crate) fn advance(&mut self, mut records: Vec<Value>) -> (Vec<Value>, bool) {
+		let size = match self.limit {
+			Some(limit) => self.batch.min(limit.saturating_sub(self.offset)),
+			None => self.batch,
+		};
+		let more = records.len() > size;
+		records.truncate(size);
+		self.offset += records.len();
+		// Stop once the LIMIT clause has been reached
+		let more = more && self.limit.map_or(true, |limit| self.offset < limit);
+		(records, more)
+	}
+}
+
+/// Converts a method argument into a cursor identifier
+pub(crate) fn cursor_id(v: Value) -> Result<Uuid, RpcError> {
+	match v {
+		Value::Uuid(v) => Ok(v.0),
+		Value::Strand(v) => Uuid::parse_str(&v).map_err(|_| RpcError::InvalidParams),
+		_ => Err(RpcError::InvalidParams),
+	}
+}
+
+/// The query cursors which are open on a single RPC connection
+#[derive(Debug, Default)]
+#[non_exhaustive]
+pub struct Cursors(Mutex<HashMap<Uuid, Cursor>>);
+
+impl Cursors {
+	/// Stores an open cursor, closing any cursors which have been idle
+	/// for too long, and failing if too many cursors are already open.
+	pub(crate) fn insert(&self, id: Uuid, mut cursor: Cursor) -> Result<(), RpcError> {
+		let mut cursors = self.0.lock().unwrap_or_else(|e| e.into_inner());
+		// Close the cursors which have expired
+		cursors.retain(|_, v| !v.expired());
+		// Check the number of open cursors
+		if !cursors.contains_key(&id) && cursors.len() >= *CURSOR_MAX_OPEN {
+			return Err(RpcError::TooManyCursors);
+		}
+		// Store the cursor
+		cursor.used = Instant::now();
+		cursors.insert(id, cursor);
+		Ok(())
+	}
+	/// Removes an open cursor, returning it if it exists and has not expired
+	pub(crate) fn remove(&self, id: &Uuid) -> Option<Cursor> {
+		let cursor = self.0.lock().unwrap_or_else(|e| e.into_inner()).remove(id)?;
+		(!cursor.expired()).then_some(cursor)
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+	use crate::sql::Statement;
+	use crate::syn;
+
+	fn select(sql: &str) -> SelectStatement {
+		match syn::parse(sql).unwrap().0 .0.remove(0) {
+			Statement::Select(v) => v,
+			v => panic!("Unexpected statement: {v:?}"),
+		}
+	}
+
+	#[test]
+	fn cursor_batches() {
+		let mut cursor =
+			Cursor::new(select("SELECT * FROM test"), BTreeMap::new(), 2, &Session::default())
+				.unwrap();
+		let stm = cursor.next_statement();
+		assert_eq!(stm.start, Some(Start(Value::from(0))));
+		assert_eq!(stm.limit, Some(Limit(Value::from(3))));
+		let (res, more) = cursor.advance(vec![1.into(), 2.into(), 3.into()]);
+		assert_eq!(res, vec![Value::from(1), Value::from(2)]);
+		assert!(more);
+		let stm = cursor.next_statement();
+		assert_eq!(stm.start, Some(Start(Value::from(2))));
+		let (res, more) = cursor.advance(vec![3.into()]);
+		assert_eq!(res, vec![Value::from(3)]);
+		assert!(!more);
+	}
+
+	#[test]
+	fn cursor_with_start_and_limit() {
+		let sql = "SELECT * FROM test START 10 LIMIT 3";
+		let mut cursor = Cursor::new(select(sql), BTreeMap::new(), 2, &Session::default()).unwrap();
+		let stm = cursor.next_statement();
+		assert_eq!(stm.start, Some(Start(Value::from(10))));
+		assert_eq!(stm.limit, Some(Limit(Value::from(3))));
+		let (_, more) = cursor.advance(vec![1.into(), 2.into(), 3.into()]);
+		assert!(more);
+		let stm = cursor.next_statement();
+		assert_eq!(stm.start, Some(Start(Value::from(12))));
+		assert_eq!(stm.limit, Some(Limit(Value::from(2))));
+		let (res, more) = cursor.advance(vec![3.into(), 4.into()]);
+		assert_eq!(res, vec![Value::from(3)]);
+		assert!(!more);
+	}
+
+	#[test]
+	fn cursor_session() {
+		let sess = Session::owner().with_ns("test").with_db("test");
+		let cursor = Cursor::new(select("SELECT * FROM test"), BTreeMap::new(), 2, &sess).unwrap();
+		assert!(cursor.check(&sess).is_ok());
+		assert!(cursor.check(&sess.clone().with_db("other")).is_err());
+	}
+
+	#[test]
+	fn cursors_limit() {
+		let cursors = Cursors::default();
+		let sess = Session::default();
+		for _ in 0..*CURSOR_MAX_OPEN {
+			let cursor =
+				Cursor::new(select("SELECT * FROM test"), BTreeMap::new(), 2, &sess).unwrap();
+			assert!(cursors.insert(Uuid::new_v4(), cursor).is_ok());
+		}
+		let cursor = Cursor::new(select("SELECT * FROM test"), BTreeMap::new(), 2, &sess).unwrap();
+		assert!(matches!(cursors.insert(Uuid::new_v4(), cursor), Err(RpcError::TooManyCursors)));
+	}
+
+	#[test]
+	fn cursor_invalid() {
+		assert!(Cursor::new(
+			select("SELECT * FROM ONLY test:1"),
+			BTreeMap::new(),
+			2,
+			&Session::default()
+		)
+		.is_err());
+		assert!(Cursor::new(
+			select("SELECT * FROM test LIMIT $l"),
+			BTreeMap::new(),
+			2,
+			&Session::default()
+		)
+		.is_err());
+		assert!(Cursor::new(select("SELECT * FROM test"), BTreeMap::new(), 0, &Session::default())
+			.is_err());
+	}
+}
@@ -28,6 +28,13 @@ pub enum Method {
 	Run,
 	GraphQL,
 	InsertRelation,
+	Changes,
+	Prepare,
+	Execute,
+	Cursor,
+	Fetch,
+	CloseCursor,
+	Cancel,
 }
 
 impl Method {
@@ -62,6 +69,13 @@ impl Method {
 			"run" => Self::Run,
 			"graphql" => Self::GraphQL,
 			"insert_relation" => Self::InsertRelation,
+			"changes" => Self::Changes,
+			"prepare" => Self::Prepare,
+			"execute" => Self::Execute,
+			"cursor" => Self::Cursor,
+			"fetch" => Self::Fetch,
+			"close_cursor" => Self::CloseCursor,
+			"cancel" => Self::Cancel,
 			_ => Self::Unknown,
 		}
 	}
@@ -97,6 +111,13 @@ impl Method {
 			Self::Run => "run",
 			Self::GraphQL => "graphql",
 			Self::InsertRelation => "insert_relation",
+			Self::Changes => "changes",
+			Self::Prepare => "prepare",
+			Self::Execute => "execute",
+			Self::Cursor => "cursor",
+			Self::Fetch => "fetch",
+			Self::CloseCursor => "close_cursor",
+			Self::Cancel => "cancel",
 		}
 	}
 }
@@ -126,6 +147,13 @@ impl Method {
 				| Method::Run
 				| Method::GraphQL
 				| Method::InsertRelation
+				| Method::Changes
+				| Method::Prepare
+				| Method::Execute
+				| Method::Cursor
+				| Method::Fetch
+				| Method::CloseCursor
+				| Method::Cancel
 				| Method::Unknown
 		)
 	}
@@ -1,6 +1,8 @@
 pub mod args;
+pub mod cursor;
 pub mod format;
 pub mod method;
+mod prepared;
 pub mod request;
 mod response;
 pub mod rpc_context;
@@ -0,0 +1,47 @@
+//! Stores the parsed queries of prepared statements, keyed by the hash of the query text.
+use crate::cnf::PREPARED_STATEMENT_CACHE_SIZE;
+use crate::err::Error;
+use crate::sql::Query;
+use crate::syn;
+use quick_cache::sync::Cache;
+use sha2::{Digest, Sha256};
+use std::sync::LazyLock;
+
+static CACHE: LazyLock<Cache<String, Query>> =
+	LazyLock::new(|| Cache::new(PREPARED_STATEMENT_CACHE_SIZE.max(10)));
+
+/// Parses a query and stores it in the cache, returning the
+/// identifier which can be used to execute the query later.
+pub(super) fn prepare(sql: &str) -> Result<String, Error> {
+	// Generate the identifier from the query text
+	let id = format!("{:x}", Sha256::digest(sql));
+	// Only parse the query if it is not already cached
+	if CACHE.get(&id).is_none() {
+		CACHE.insert(id.clone(), syn::parse(sql)?);
+	}
+	Ok(id)
+}
+
+/// Fetches a prepared query from the cache. This returns `None` if
+/// the query was never prepared or has since been evicted from the cache.
+pub(super) fn get(id: &str) -> Option<Query> {
+	CACHE.get(id)
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+
+	#[test]
+	fn prepare_and_get() {
+		let id = prepare("SELECT * FROM person WHERE age > $age").unwrap();
+		assert_eq!(id, prepare("SELECT * FROM person WHERE age > $age").unwrap());
+		assert_eq!(get(&id), Some(syn::parse("SELECT * FROM person WHERE age > $age").unwrap()));
+	}
+
+	#[test]
+	fn prepare_invalid() {
+		assert!(prepare("SELECT * FROM").is_err());
+		assert!(get("unknown").is_none());
+	}
+}
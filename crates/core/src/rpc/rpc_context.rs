@@ -8,19 +8,23 @@ use uuid::Uuid;
 #[cfg(all(not(target_arch = "wasm32"), surrealdb_unstable))]
 use crate::gql::SchemaCache;
 use crate::{
+	cnf::CURSOR_BATCH_SIZE,
 	dbs::{capabilities::MethodTarget, QueryType, Response, Session},
 	kvs::Datastore,
 	rpc::args::Take,
+	rpc::cursor::{self, Cursor, Cursors},
 	sql::{
 		statements::{
-			CreateStatement, DeleteStatement, InsertStatement, KillStatement, LiveStatement,
-			RelateStatement, SelectStatement, UpdateStatement, UpsertStatement,
+			show::ShowSince, CreateStatement, DeleteStatement, InsertStatement, KillStatement,
+			LiveStatement, RelateStatement, SelectStatement, ShowStatement, UpdateStatement,
+			UpsertStatement,
 		},
-		Array, Fields, Function, Model, Output, Query, Strand, Value,
+		Array, Fields, Function, Model, Number, Output, Query, Statement, Strand, Table, Value,
 	},
+	syn,
 };
 
-use super::{method::Method, response::Data, rpc_error::RpcError};
+use super::{method::Method, prepared, response::Data, rpc_error::RpcError};
 
 #[allow(async_fn_in_trait)]
 pub trait RpcContext {
@@ -71,6 +75,31 @@ pub trait RpcContext {
 		unimplemented!("graphql_schema_cache function must be implemented if GQL_SUPPORT = true")
 	}
 
+	// ------------------------------
+	// Cursors
+	// ------------------------------
+
+	/// Query cursors are disabled by default
+	const CURSOR_SUPPORT: bool = false;
+
+	/// Returns the query cursors which are open on this RPC context
+	fn cursors(&self) -> &Cursors {
+		unimplemented!("cursors function must be implemented if CURSOR_SUPPORT = true")
+	}
+
+	// ------------------------------
+	// Cancellation
+	// ------------------------------
+
+	/// Request cancellation is disabled by default
+	const CANCEL_SUPPORT: bool = false;
+
+	/// Cancels an in-flight request on this RPC context, returning false if
+	/// there is no request with the specified id
+	fn cancel_request(&self, _id: &Value) -> bool {
+		unimplemented!("cancel_request function must be implemented if CANCEL_SUPPORT = true")
+	}
+
 	// ------------------------------
 	// Method execution
 	// ------------------------------
@@ -112,6 +141,13 @@ pub trait RpcContext {
 			Method::Run => self.run(params).await,
 			Method::GraphQL => self.graphql(params).await,
 			Method::InsertRelation => self.insert_relation(params).await,
+			Method::Changes => self.changes(params).await,
+			Method::Prepare => self.prepare(params).await,
+			Method::Execute => self.execute(params).await,
+			Method::Cursor => self.cursor(params).await,
+			Method::Fetch => self.fetch(params).await,
+			Method::CloseCursor => self.close_cursor(params).await,
+			Method::Cancel => self.cancel(params).await,
 			Method::Unknown => Err(RpcError::MethodNotFound),
 		}
 	}
@@ -143,6 +179,13 @@ pub trait RpcContext {
 			Method::Run => self.run(params).await,
 			Method::GraphQL => self.graphql(params).await,
 			Method::InsertRelation => self.insert_relation(params).await,
+			Method::Changes => self.changes(params).await,
+			Method::Prepare => self.prepare(params).await,
+			Method::Execute => self.execute(params).await,
+			Method::Cursor => self.cursor(params).await,
+			Method::Fetch => self.fetch(params).await,
+			Method::CloseCursor => self.close_cursor(params).await,
+			Method::Cancel => self.cancel(params).await,
 			Method::Unknown => Err(RpcError::MethodNotFound),
 			_ => Err(RpcError::MethodNotFound),
 		}
@@ -320,6 +363,7 @@ pub trait RpcContext {
 		// Specify the SQL query string
 		let sql = KillStatement {
 			id,
+			connection: false,
 		}
 		.into();
 		// Specify the query parameters
@@ -711,6 +755,51 @@ pub trait RpcContext {
 		}
 	}
 
+	// ------------------------------
+	// Methods for reading change feeds
+	// ------------------------------
+
+	async fn changes(&self, params: Array) -> Result<Data, RpcError> {
+		// Process the method arguments
+		let Ok((table, since, limit)) = params.needs_one_two_or_three() else {
+			return Err(RpcError::InvalidParams);
+		};
+		// Parse the table argument, or read the whole database
+		let table = match table {
+			Value::Strand(Strand(v)) => Some(Table(v)),
+			Value::Table(v) => Some(v),
+			Value::None | Value::Null => None,
+			_ => return Err(RpcError::InvalidParams),
+		};
+		// Parse the starting point of the change feed
+		let since = match since {
+			Value::Datetime(v) => ShowSince::Timestamp(v),
+			Value::Number(Number::Int(v)) if v >= 0 => ShowSince::Versionstamp(v as u64),
+			_ => return Err(RpcError::InvalidParams),
+		};
+		// Parse the maximum number of changes to return
+		let limit = match limit {
+			Value::Number(Number::Int(v)) if v > 0 => {
+				Some(u32::try_from(v).map_err(|_| RpcError::InvalidParams)?)
+			}
+			Value::None | Value::Null => None,
+			_ => return Err(RpcError::InvalidParams),
+		};
+		// Specify the SQL query string
+		let sql: Query = Statement::Show(ShowStatement {
+			table,
+			since,
+			limit,
+		})
+		.into();
+		// Specify the query parameters
+		let var = Some(self.vars().clone());
+		// Execute the query on the database
+		let mut res = self.kvs().process(sql, self.session(), var).await?;
+		// Extract the first query result
+		Ok(res.remove(0).result?.into())
+	}
+
 	// ------------------------------
 	// Methods for querying
 	// ------------------------------
@@ -734,6 +823,133 @@ pub trait RpcContext {
 		self.query_inner(query, vars).await.map(Into::into)
 	}
 
+	async fn prepare(&self, params: Array) -> Result<Data, RpcError> {
+		// Process the method arguments
+		let Ok(Value::Strand(sql)) = params.needs_one() else {
+			return Err(RpcError::InvalidParams);
+		};
+		// Parse the query and store it in the cache
+		let id = prepared::prepare(&sql)?;
+		// Return the prepared statement identifier
+		Ok(Value::from(id).into())
+	}
+
+	async fn execute(&self, params: Array) -> Result<Data, RpcError> {
+		// Process the method arguments
+		let Ok((Value::Strand(id), vars)) = params.needs_one_or_two() else {
+			return Err(RpcError::InvalidParams);
+		};
+		// Fetch the parsed query from the cache
+		let Some(query) = prepared::get(&id) else {
+			return Err(RpcError::StatementNotFound);
+		};
+		// Specify the query variables
+		let vars = match vars {
+			Value::Object(mut v) => Some(mrg! {v.0, &self.vars()}),
+			Value::None | Value::Null => Some(self.vars().clone()),
+			_ => return Err(RpcError::InvalidParams),
+		};
+		// Execute the prepared query
+		self.query_inner(Value::Query(query), vars).await.map(Into::into)
+	}
+
+	// ------------------------------
+	// Methods for query cursors
+	// ------------------------------
+
+	async fn cursor(&self, params: Array) -> Result<Data, RpcError> {
+		// Check if cursors are supported
+		if !Self::CURSOR_SUPPORT {
+			return Err(RpcError::BadCursorConfig);
+		}
+		// Process the method arguments
+		let Ok((query, vars, batch)) = params.needs_one_two_or_three() else {
+			return Err(RpcError::InvalidParams);
+		};
+		// Parse the query if necessary
+		let query = match query {
+			Value::Query(v) => v,
+			Value::Strand(v) => syn::parse(&v)?,
+			_ => return Err(RpcError::InvalidParams),
+		};
+		// A cursor can only be opened for a single SELECT statement
+		let stm = match <[Statement; 1]>::try_from(query.0 .0) {
+			Ok([Statement::Select(v)]) => v,
+			_ => return Err(RpcError::InvalidParams),
+		};
+		// Specify the query variables
+		let vars = match vars {
+			Value::Object(mut v) => mrg! {v.0, &self.vars()},
+			Value::None | Value::Null => self.vars().clone(),
+			_ => return Err(RpcError::InvalidParams),
+		};
+		// Specify the batch size
+		let batch = match batch {
+			Value::Number(v) => v.as_usize(),
+			Value::None | Value::Null => *CURSOR_BATCH_SIZE,
+			_ => return Err(RpcError::InvalidParams),
+		};
+		// Open the cursor and fetch the first batch
+		let cursor = Cursor::new(stm, vars, batch, self.session())?;
+		self.fetch_inner(Uuid::new_v4(), cursor).await
+	}
+
+	async fn fetch(&self, params: Array) -> Result<Data, RpcError> {
+		// Check if cursors are supported
+		if !Self::CURSOR_SUPPORT {
+			return Err(RpcError::BadCursorConfig);
+		}
+		// Process the method arguments
+		let id = cursor::cursor_id(params.needs_one()?)?;
+		// Fetch the open cursor
+		let Some(cursor) = self.cursors().remove(&id) else {
+			return Err(RpcError::CursorNotFound);
+		};
+		// Check the cursor was opened in this database
+		if let Err(e) = cursor.check(self.session()) {
+			self.cursors().insert(id, cursor)?;
+			return Err(e);
+		}
+		// Fetch the next batch
+		self.fetch_inner(id, cursor).await
+	}
+
+	async fn close_cursor(&self, params: Array) -> Result<Data, RpcError> {
+		// Check if cursors are supported
+		if !Self::CURSOR_SUPPORT {
+			return Err(RpcError::BadCursorConfig);
+		}
+		// Process the method arguments
+		let id = cursor::cursor_id(params.needs_one()?)?;
+		// Close the open cursor
+		match self.cursors().remove(&id) {
+			Some(_) => Ok(Value::None.into()),
+			None => Err(RpcError::CursorNotFound),
+		}
+	}
+
+	// ------------------------------
+	// Methods for cancelling requests
+	// ------------------------------
+
+	async fn cancel(&self, params: Array) -> Result<Data, RpcError> {
+		// Check if cancellation is supported
+		if !Self::CANCEL_SUPPORT {
+			return Err(RpcError::BadCancelConfig);
+		}
+		// Process the method arguments
+		let id = params.needs_one()?;
+		// Only requests with a string or numeric id can be cancelled
+		if !(id.is_strand() || id.is_number()) {
+			return Err(RpcError::InvalidParams);
+		}
+		// Cancel the in-flight request
+		match self.cancel_request(&id) {
+			true => Ok(Value::None.into()),
+			false => Err(RpcError::RequestNotFound),
+		}
+	}
+
 	// ------------------------------
 	// Methods for running functions
 	// ------------------------------
@@ -926,6 +1142,32 @@ pub trait RpcContext {
 		Ok(res)
 	}
 
+	async fn fetch_inner(&self, id: Uuid, mut cursor: Cursor) -> Result<Data, RpcError> {
+		// Fetch the next batch of records
+		let sql = cursor.next_statement().into();
+		let var = Some(cursor.vars.clone());
+		let mut res = self.kvs().process(sql, self.session(), var).await?;
+		// Extract the first query result
+		let records = match res.remove(0).result? {
+			Value::Array(v) => v.0,
+			v => vec![v],
+		};
+		// Keep the cursor open if there are more records
+		let (records, more) = cursor.advance(records);
+		if more {
+			self.cursors().insert(id, cursor)?;
+		}
+		// Return the batch and the cursor identifier
+		Ok(Value::from(map! {
+			"cursor".to_string() => match more {
+				true => Value::from(id.to_string()),
+				false => Value::Null,
+			},
+			"result".to_string() => Value::from(records),
+		})
+		.into())
+	}
+
 	async fn handle_live_query_results(&self, res: &Response) {
 		match &res.query_type {
 			QueryType::Live => {
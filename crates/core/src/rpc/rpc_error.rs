@@ -23,6 +23,20 @@ pub enum RpcError {
 	BadLQConfig,
 	#[error("A GraphQL request was made, but GraphQL is not supported by the context")]
 	BadGQLConfig,
+	#[error("A query cursor was requested, but cursors are not supported by the context")]
+	BadCursorConfig,
+	#[error("The prepared statement was not found")]
+	StatementNotFound,
+	#[error("The query cursor was not found")]
+	CursorNotFound,
+	#[error("The query cursor was opened in a different namespace or database")]
+	CursorMismatch,
+	#[error("Too many query cursors are open on this connection")]
+	TooManyCursors,
+	#[error("A request was cancelled, but cancellation is not supported by the context")]
+	BadCancelConfig,
+	#[error("The request was not found, or has already finished")]
+	RequestNotFound,
 	#[error("Error: {0}")]
 	Thrown(String),
 }
@@ -8,8 +8,8 @@ use crate::sql::statements::rebuild::RebuildStatement;
 use crate::sql::statements::{
 	AlterStatement, BreakStatement, ContinueStatement, CreateStatement, DefineStatement,
 	DeleteStatement, ForeachStatement, IfelseStatement, InsertStatement, OutputStatement,
-	RelateStatement, RemoveStatement, SelectStatement, SetStatement, ThrowStatement,
-	UpdateStatement, UpsertStatement,
+	RelateStatement, ReleaseStatement, RemoveStatement, RollbackStatement, SavepointStatement,
+	SelectStatement, SetStatement, ThrowStatement, UpdateStatement, UpsertStatement,
 };
 use crate::sql::value::Value;
 use reblessive::tree::Stk;
@@ -119,6 +119,15 @@ impl Block {
 				Entry::Alter(v) => {
 					v.compute(stk, &ctx, opt, doc).await?;
 				}
+				Entry::Savepoint(v) => {
+					v.compute(&ctx, opt, doc).await?;
+				}
+				Entry::Rollback(v) => {
+					v.compute(&ctx, opt, doc).await?;
+				}
+				Entry::Release(v) => {
+					v.compute(&ctx, opt, doc).await?;
+				}
 				Entry::Value(v) => {
 					if i == self.len() - 1 {
 						// If the last entry then return the value
@@ -186,7 +195,7 @@ impl InfoStructure for Block {
 	}
 }
 
-#[revisioned(revision = 4)]
+#[revisioned(revision = 5)]
 #[derive(Clone, Debug, Eq, PartialEq, Serialize, Deserialize, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -213,6 +222,12 @@ pub enum Entry {
 	Upsert(UpsertStatement),
 	#[revision(start = 4)]
 	Alter(AlterStatement),
+	#[revision(start = 5)]
+	Savepoint(SavepointStatement),
+	#[revision(start = 5)]
+	Rollback(RollbackStatement),
+	#[revision(start = 5)]
+	Release(ReleaseStatement),
 }
 
 impl PartialOrd for Entry {
@@ -245,6 +260,9 @@ impl Entry {
 			Self::Continue(v) => v.writeable(),
 			Self::Foreach(v) => v.writeable(),
 			Self::Alter(v) => v.writeable(),
+			Self::Savepoint(_) => false,
+			Self::Rollback(_) => false,
+			Self::Release(_) => false,
 		}
 	}
 }
@@ -271,6 +289,9 @@ impl Display for Entry {
 			Self::Continue(v) => write!(f, "{v}"),
 			Self::Foreach(v) => write!(f, "{v}"),
 			Self::Alter(v) => write!(f, "{v}"),
+			Self::Savepoint(v) => write!(f, "{v}"),
+			Self::Rollback(v) => write!(f, "{v}"),
+			Self::Release(v) => write!(f, "{v}"),
 		}
 	}
 }
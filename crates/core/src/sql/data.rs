@@ -12,7 +12,7 @@ use revision::revisioned;
 use serde::{Deserialize, Serialize};
 use std::fmt::{self, Display, Formatter};
 
-#[revisioned(revision = 1)]
+#[revisioned(revision = 2)]
 #[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -27,6 +27,8 @@ pub enum Data {
 	SingleExpression(Value),
 	ValuesExpression(Vec<Vec<(Idiom, Value)>>),
 	UpdateExpression(Vec<(Idiom, Operator, Value)>),
+	#[revision(start = 2)]
+	MergeWithExpression(Value, MergeStrategy),
 }
 
 impl Default for Data {
@@ -54,7 +56,7 @@ impl Data {
 		path: &[Part],
 	) -> Result<Option<Value>, Error> {
 		match self {
-			Self::MergeExpression(v) => match v {
+			Self::MergeExpression(v) | Self::MergeWithExpression(v, _) => match v {
 				Value::Param(v) => Ok(v.compute(stk, ctx, opt, None).await?.pick(path).some()),
 				Value::Object(_) => Ok(v.pick(path).compute(stk, ctx, opt, None).await?.some()),
 				_ => Ok(None),
@@ -101,6 +103,7 @@ impl Display for Data {
 			),
 			Self::PatchExpression(v) => write!(f, "PATCH {v}"),
 			Self::MergeExpression(v) => write!(f, "MERGE {v}"),
+			Self::MergeWithExpression(v, s) => write!(f, "MERGE {v} ARRAYS {s}"),
 			Self::ReplaceExpression(v) => write!(f, "REPLACE {v}"),
 			Self::ContentExpression(v) => write!(f, "CONTENT {v}"),
 			Self::SingleExpression(v) => Display::fmt(v, f),
@@ -124,3 +127,30 @@ impl Display for Data {
 		}
 	}
 }
+
+/// The strategy used for merging arrays in a MERGE clause
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Hash)]
+#[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
+#[non_exhaustive]
+pub enum MergeStrategy {
+	/// Arrays replace any existing arrays
+	#[default]
+	Replace,
+	/// Arrays are appended to any existing arrays
+	Append,
+	/// Values which are not in the existing arrays are appended, and
+	/// objects which match an existing object by key are merged into it
+	Union(Option<Idiom>),
+}
+
+impl Display for MergeStrategy {
+	fn fmt(&self, f: &mut Formatter) -> fmt::Result {
+		match self {
+			Self::Replace => f.write_str("REPLACE"),
+			Self::Append => f.write_str("APPEND"),
+			Self::Union(None) => f.write_str("UNION"),
+			Self::Union(Some(v)) => write!(f, "UNION BY {v}"),
+		}
+	}
+}
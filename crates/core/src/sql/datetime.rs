@@ -4,7 +4,7 @@ use crate::sql::strand::Strand;
 use crate::syn;
 use chrono::{offset::LocalResult, DateTime, SecondsFormat, TimeZone, Utc};
 use revision::revisioned;
-use serde::{Deserialize, Serialize};
+use serde::{Deserialize, Serialize, Serializer};
 use std::fmt::{self, Display, Formatter};
 use std::ops;
 use std::ops::Deref;
@@ -17,11 +17,23 @@ use super::value::TrySub;
 pub(crate) const TOKEN: &str = "$surrealdb::private::sql::Datetime";
 
 #[revisioned(revision = 1)]
-#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Ord, Serialize, Deserialize, Hash)]
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Ord, Deserialize, Hash)]
 #[serde(rename = "$surrealdb::private::sql::Datetime")]
 #[non_exhaustive]
 pub struct Datetime(pub DateTime<Utc>);
 
+impl Serialize for Datetime {
+	/// Serializes the datetime with a fixed number of fractional
+	/// digits, so that the encoded keys sort in chronological order.
+	fn serialize<S>(&self, serializer: S) -> Result<S::Ok, S::Error>
+	where
+		S: Serializer,
+	{
+		let v = self.0.to_rfc3339_opts(SecondsFormat::Nanos, true);
+		serializer.serialize_newtype_struct(TOKEN, &v)
+	}
+}
+
 impl Datetime {
 	pub const MIN_UTC: Self = Datetime(DateTime::<Utc>::MIN_UTC);
 	pub const MAX_UTC: Self = Datetime(DateTime::<Utc>::MAX_UTC);
@@ -121,6 +121,11 @@ impl Function {
 		matches!(self, Self::Script(_, _))
 	}
 
+	/// Check if this function writes to the datastore
+	pub fn is_writeable(&self) -> bool {
+		matches!(self, Self::Normal(n, _) if n == "http::webhook" || n == "sequence::nextval")
+	}
+
 	/// Check if all arguments are static values
 	pub fn is_static(&self) -> bool {
 		match self {
@@ -192,6 +192,17 @@ impl Id {
 	pub fn uuid() -> Self {
 		Self::Uuid(Uuid::new_v7())
 	}
+	/// Generate a new ID, using the generator of the context if one is set
+	pub(crate) fn generate(ctx: &Context, gen: &Gen) -> Self {
+		match (ctx.get_generator(), gen) {
+			(Some(v), Gen::Rand) => Self::String(v.rand()),
+			(Some(v), Gen::Ulid) => Self::String(v.ulid()),
+			(Some(v), Gen::Uuid) => Self::Uuid(v.uuid()),
+			(None, Gen::Rand) => Self::rand(),
+			(None, Gen::Ulid) => Self::ulid(),
+			(None, Gen::Uuid) => Self::uuid(),
+		}
+	}
 	/// Check if this Id matches a value
 	pub fn is(&self, val: &Value) -> bool {
 		match (self, val) {
@@ -261,11 +272,7 @@ impl Id {
 				Value::Object(v) => Ok(Id::Object(v)),
 				v => Err(fail!("Expected a Value::Object but found {v:?}")),
 			},
-			Id::Generate(v) => match v {
-				Gen::Rand => Ok(Self::rand()),
-				Gen::Ulid => Ok(Self::ulid()),
-				Gen::Uuid => Ok(Self::uuid()),
-			},
+			Id::Generate(v) => Ok(Self::generate(ctx, v)),
 			Id::Range(v) => Ok(Id::Range(Box::new(v.compute(stk, ctx, opt, doc).await?))),
 		}
 	}
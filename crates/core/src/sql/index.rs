@@ -12,7 +12,7 @@ use serde::{Deserialize, Serialize};
 use std::fmt;
 use std::fmt::{Display, Formatter};
 
-#[revisioned(revision = 2)]
+#[revisioned(revision = 3)]
 #[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -29,6 +29,9 @@ pub enum Index {
 	/// HNSW index for distance based metrics
 	#[revision(start = 2)]
 	Hnsw(HnswParams),
+	/// Unique index, where NONE and NULL values also conflict
+	#[revision(start = 3)]
+	UniqNullsNotDistinct,
 }
 
 #[revisioned(revision = 2)]
@@ -241,6 +244,7 @@ impl Display for Index {
 		match self {
 			Self::Idx => Ok(()),
 			Self::Uniq => f.write_str("UNIQUE"),
+			Self::UniqNullsNotDistinct => f.write_str("UNIQUE NULLS NOT DISTINCT"),
 			Self::Search(p) => {
 				write!(
 					f,
@@ -52,11 +52,33 @@ impl Kind {
 		matches!(self, Kind::Any)
 	}
 
+	/// Returns true if values of this type can never be arrays
+	pub(crate) fn is_scalar(&self) -> bool {
+		match self {
+			Kind::Any | Kind::Set(..) | Kind::Array(..) | Kind::Function(..) | Kind::Literal(_) => {
+				false
+			}
+			Kind::Option(k) => k.is_scalar(),
+			Kind::Either(k) => k.iter().all(Kind::is_scalar),
+			_ => true,
+		}
+	}
+
 	/// Returns true if this type is a record
 	pub(crate) fn is_record(&self) -> bool {
 		matches!(self, Kind::Record(_))
 	}
 
+	/// Returns true if this type is a record, or can contain records
+	pub(crate) fn is_record_link(&self) -> bool {
+		match self {
+			Kind::Record(_) => true,
+			Kind::Option(x) | Kind::Array(x, _) | Kind::Set(x, _) => x.is_record_link(),
+			Kind::Either(x) => x.iter().any(Kind::is_record_link),
+			_ => false,
+		}
+	}
+
 	/// Returns true if this type is optional
 	pub(crate) fn can_be_none(&self) -> bool {
 		matches!(self, Kind::Option(_) | Kind::Any)
@@ -50,8 +50,10 @@ pub(crate) mod param;
 pub(crate) mod part;
 pub(crate) mod paths;
 pub(crate) mod permission;
+pub(crate) mod qualified;
 pub(crate) mod query;
 pub(crate) mod range;
+pub(crate) mod reference;
 pub(crate) mod regex;
 pub(crate) mod scoring;
 pub(crate) mod script;
@@ -92,6 +94,7 @@ pub use self::closure::Closure;
 pub use self::cond::Cond;
 pub use self::constant::Constant;
 pub use self::data::Data;
+pub use self::data::MergeStrategy;
 pub use self::datetime::Datetime;
 pub use self::dir::Dir;
 pub use self::duration::Duration;
@@ -130,8 +133,11 @@ pub use self::param::Param;
 pub use self::part::Part;
 pub use self::permission::Permission;
 pub use self::permission::Permissions;
+pub use self::qualified::Qualified;
 pub use self::query::Query;
 pub use self::range::Range;
+pub use self::reference::Reference;
+pub use self::reference::ReferenceDeleteStrategy;
 pub use self::regex::Regex;
 pub use self::scoring::Scoring;
 pub use self::script::Script;
@@ -144,7 +150,7 @@ pub use self::strand::Strand;
 pub use self::subquery::Subquery;
 pub use self::table::Table;
 pub use self::table::Tables;
-pub use self::table_type::{Relation, TableType};
+pub use self::table_type::{Relation, TableType, TimeSeries};
 pub use self::thing::Thing;
 pub use self::timeout::Timeout;
 pub use self::tokenizer::Tokenizer;
@@ -162,4 +168,4 @@ mod parser {
 	pub use crate::syn::*;
 }
 
-pub use self::parser::{idiom, json, parse, subquery, thing, value};
+pub use self::parser::{format, idiom, json, parse, subquery, thing, value};
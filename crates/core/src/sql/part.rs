@@ -19,7 +19,7 @@ use super::{
 	value::idiom_recursion::{clean_iteration, compute_idiom_recursion, is_final, Recursion},
 };
 
-#[revisioned(revision = 3)]
+#[revisioned(revision = 4)]
 #[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -38,14 +38,30 @@ pub enum Part {
 	#[revision(start = 2)]
 	Destructure(Vec<DestructurePart>),
 	Optional,
-	#[revision(start = 3)]
+	#[revision(
+		start = 3,
+		end = 4,
+		convert_fn = "convert_old_recurse",
+		fields_name = "OldPartRecurseFields"
+	)]
 	Recurse(Recurse, Option<Idiom>),
+	#[revision(start = 4)]
+	Recurse(Recurse, Option<Idiom>, Option<RecurseInstruction>),
 	#[revision(start = 3)]
 	Doc,
 	#[revision(start = 3)]
 	RepeatRecurse,
 }
 
+impl Part {
+	fn convert_old_recurse(
+		fields: OldPartRecurseFields,
+		_revision: u16,
+	) -> Result<Self, revision::Error> {
+		Ok(Part::Recurse(fields.0, fields.1, None))
+	}
+}
+
 impl From<i32> for Part {
 	fn from(v: i32) -> Self {
 		Self::Index(v.into())
@@ -194,8 +210,11 @@ impl fmt::Display for Part {
 				}
 			}
 			Part::Optional => write!(f, "?"),
-			Part::Recurse(v, nest) => {
-				write!(f, ".{{{v}}}")?;
+			Part::Recurse(v, nest, instruction) => {
+				match instruction {
+					Some(instruction) => write!(f, ".{{{v}{instruction}}}")?,
+					None => write!(f, ".{{{v}}}")?,
+				}
 				if let Some(nest) = nest {
 					write!(f, "({nest})")?;
 				}
@@ -507,3 +526,22 @@ impl fmt::Display for Recurse {
 		}
 	}
 }
+
+// ------------------------------
+
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Hash)]
+#[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
+#[non_exhaustive]
+pub enum RecurseInstruction {
+	/// Output every traversed path, instead of the final values
+	Path,
+}
+
+impl fmt::Display for RecurseInstruction {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		match self {
+			RecurseInstruction::Path => write!(f, "+path"),
+		}
+	}
+}
@@ -0,0 +1,48 @@
+use crate::dbs::Options;
+use crate::err::Error;
+use crate::iam::{Action, ResourceKind};
+use crate::sql::{Base, Ident, Table};
+use revision::revisioned;
+use serde::{Deserialize, Serialize};
+use std::fmt;
+use std::sync::Arc;
+
+pub(crate) const TOKEN: &str = "$surrealdb::private::sql::Qualified";
+
+/// A table in another namespace and database, like `ns:acme.db:prod.person`
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Hash)]
+#[serde(rename = "$surrealdb::private::sql::Qualified")]
+#[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
+#[non_exhaustive]
+pub struct Qualified {
+	pub ns: Ident,
+	pub db: Ident,
+	pub what: Table,
+}
+
+impl Qualified {
+	/// The options with which the records of the table are selected,
+	/// with the permissions of the namespace and database
+	pub(crate) fn options(&self, opt: &Options) -> Result<Arc<Options>, Error> {
+		// Only root and namespace users can select from other databases
+		if opt.auth.is_db() || opt.auth.is_record() {
+			return Err(Error::QualifiedPermissions);
+		}
+		// Select the namespace and database of the table
+		let opt = opt
+			.clone()
+			.with_ns(Some(self.ns.as_str().into()))
+			.with_db(Some(self.db.as_str().into()));
+		// Allowed to run?
+		opt.is_allowed(Action::View, ResourceKind::Table, &Base::Db)?;
+		// Return the options
+		Ok(Arc::new(opt))
+	}
+}
+
+impl fmt::Display for Qualified {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		write!(f, "ns:{}.db:{}.{}", self.ns, self.db, self.what)
+	}
+}
@@ -0,0 +1,56 @@
+use crate::sql::statements::info::InfoStructure;
+use crate::sql::Value;
+use revision::revisioned;
+use serde::{Deserialize, Serialize};
+use std::fmt;
+
+/// The referential integrity settings of a record link field
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Hash)]
+#[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
+#[non_exhaustive]
+pub struct Reference {
+	pub on_delete: ReferenceDeleteStrategy,
+}
+
+impl fmt::Display for Reference {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		write!(f, "ON DELETE {}", self.on_delete)
+	}
+}
+
+impl InfoStructure for Reference {
+	fn structure(self) -> Value {
+		Value::from(map! {
+			"on_delete".to_string() => self.on_delete.to_string().into(),
+		})
+	}
+}
+
+/// What happens to a referencing record when the record it links to is deleted
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Hash)]
+#[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
+#[non_exhaustive]
+pub enum ReferenceDeleteStrategy {
+	/// The link is left in place, and will point to a missing record
+	#[default]
+	Ignore,
+	/// The deletion is rejected while the record is still referenced
+	Reject,
+	/// The referencing record is deleted too
+	Cascade,
+	/// The link is removed from the referencing record
+	Unset,
+}
+
+impl fmt::Display for ReferenceDeleteStrategy {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		f.write_str(match self {
+			Self::Ignore => "IGNORE",
+			Self::Reject => "REJECT",
+			Self::Cascade => "CASCADE",
+			Self::Unset => "UNSET",
+		})
+	}
+}
@@ -10,9 +10,10 @@ use crate::sql::{
 		AlterStatement, AnalyzeStatement, BeginStatement, BreakStatement, CancelStatement,
 		CommitStatement, ContinueStatement, CreateStatement, DefineStatement, DeleteStatement,
 		ForeachStatement, IfelseStatement, InfoStatement, InsertStatement, KillStatement,
-		LiveStatement, OptionStatement, OutputStatement, RelateStatement, RemoveStatement,
-		SelectStatement, SetStatement, ShowStatement, SleepStatement, ThrowStatement,
-		UpdateStatement, UpsertStatement, UseStatement,
+		LiveStatement, OptionStatement, OutputStatement, RelateStatement, ReleaseStatement,
+		RemoveStatement, RollbackStatement, SavepointStatement, SelectStatement, SetStatement,
+		ShowStatement, SleepStatement, ThrowStatement, UpdateStatement, UpsertStatement,
+		UseStatement,
 	},
 	value::Value,
 };
@@ -55,7 +56,7 @@ impl Display for Statements {
 	}
 }
 
-#[revisioned(revision = 5)]
+#[revisioned(revision = 6)]
 #[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -96,6 +97,12 @@ pub enum Statement {
 	// TODO(gguillemas): Document once bearer access is no longer experimental.
 	#[revision(start = 5)]
 	Access(AccessStatement),
+	#[revision(start = 6)]
+	Savepoint(SavepointStatement),
+	#[revision(start = 6)]
+	Rollback(RollbackStatement),
+	#[revision(start = 6)]
+	Release(ReleaseStatement),
 }
 
 impl Statement {
@@ -105,7 +112,7 @@ impl Statement {
 			Self::Value(v) => v.writeable(),
 			Self::Access(_) => true,
 			Self::Alter(_) => true,
-			Self::Analyze(_) => false,
+			Self::Analyze(v) => v.writeable(),
 			Self::Break(_) => false,
 			Self::Continue(_) => false,
 			Self::Create(v) => v.writeable(),
@@ -115,7 +122,7 @@ impl Statement {
 			Self::Ifelse(v) => v.writeable(),
 			Self::Info(_) => false,
 			Self::Insert(v) => v.writeable(),
-			Self::Kill(_) => true,
+			Self::Kill(v) => !v.connection,
 			Self::Live(_) => true,
 			Self::Output(v) => v.writeable(),
 			Self::Option(_) => false,
@@ -133,6 +140,64 @@ impl Statement {
 			_ => false,
 		}
 	}
+	/// Check if this statement can be run again, when its transaction
+	/// fails to commit due to a conflicting write. A statement which
+	/// performs a side effect which can not be undone, such as a HTTP
+	/// request, is not retried, which is checked once it has been run.
+	pub(crate) fn retryable(&self) -> bool {
+		matches!(
+			self,
+			Self::Alter(_)
+				| Self::Create(_)
+				| Self::Define(_)
+				| Self::Delete(_)
+				| Self::Insert(_)
+				| Self::Rebuild(_)
+				| Self::Relate(_)
+				| Self::Remove(_)
+				| Self::Update(_)
+				| Self::Upsert(_)
+		)
+	}
+	/// Returns the type of this statement, used when recording metrics
+	pub(crate) fn kind(&self) -> &'static str {
+		match self {
+			Self::Value(_) => "value",
+			Self::Access(_) => "access",
+			Self::Alter(_) => "alter",
+			Self::Analyze(_) => "analyze",
+			Self::Begin(_) => "begin",
+			Self::Break(_) => "break",
+			Self::Cancel(_) => "cancel",
+			Self::Commit(_) => "commit",
+			Self::Continue(_) => "continue",
+			Self::Create(_) => "create",
+			Self::Define(_) => "define",
+			Self::Delete(_) => "delete",
+			Self::Foreach(_) => "foreach",
+			Self::Ifelse(_) => "ifelse",
+			Self::Info(_) => "info",
+			Self::Insert(_) => "insert",
+			Self::Kill(_) => "kill",
+			Self::Live(_) => "live",
+			Self::Option(_) => "option",
+			Self::Output(_) => "output",
+			Self::Rebuild(_) => "rebuild",
+			Self::Relate(_) => "relate",
+			Self::Release(_) => "release",
+			Self::Remove(_) => "remove",
+			Self::Rollback(_) => "rollback",
+			Self::Savepoint(_) => "savepoint",
+			Self::Select(_) => "select",
+			Self::Set(_) => "set",
+			Self::Show(_) => "show",
+			Self::Sleep(_) => "sleep",
+			Self::Throw(_) => "throw",
+			Self::Update(_) => "update",
+			Self::Upsert(_) => "upsert",
+			Self::Use(_) => "use",
+		}
+	}
 	/// Process this type returning a computed simple Value
 	pub(crate) async fn compute(
 		&self,
@@ -175,7 +240,10 @@ impl Statement {
 			Self::Output(v) => v.compute(stk, ctx, opt, doc).await,
 			Self::Relate(v) => v.compute(stk, ctx, opt, doc).await,
 			Self::Rebuild(v) => v.compute(stk, ctx, opt, doc).await,
+			Self::Release(v) => v.compute(ctx, opt, doc).await,
 			Self::Remove(v) => v.compute(ctx, opt, doc).await,
+			Self::Rollback(v) => v.compute(ctx, opt, doc).await,
+			Self::Savepoint(v) => v.compute(ctx, opt, doc).await,
 			Self::Select(v) => v.compute(stk, ctx, opt, doc).await,
 			Self::Set(v) => v.compute(stk, ctx, opt, doc).await,
 			Self::Show(v) => v.compute(ctx, opt, doc).await,
@@ -219,7 +287,10 @@ impl Display for Statement {
 			Self::Output(v) => write!(Pretty::from(f), "{v}"),
 			Self::Rebuild(v) => write!(Pretty::from(f), "{v}"),
 			Self::Relate(v) => write!(Pretty::from(f), "{v}"),
+			Self::Release(v) => write!(Pretty::from(f), "{v}"),
 			Self::Remove(v) => write!(Pretty::from(f), "{v}"),
+			Self::Rollback(v) => write!(Pretty::from(f), "{v}"),
+			Self::Savepoint(v) => write!(Pretty::from(f), "{v}"),
 			Self::Select(v) => write!(Pretty::from(f), "{v}"),
 			Self::Set(v) => write!(Pretty::from(f), "{v}"),
 			Self::Show(v) => write!(Pretty::from(f), "{v}"),
@@ -0,0 +1,206 @@
+use crate::cnf::NORMAL_FETCH_SIZE;
+use crate::ctx::Context;
+use crate::dbs::Options;
+use crate::err::Error;
+use crate::iam::{Action, ResourceKind};
+use crate::kvs::Transaction;
+#[cfg(not(target_arch = "wasm32"))]
+use crate::kvs::{move_progress, MigrationStatus};
+use crate::sql::statements::DefineDatabaseStatement;
+use crate::sql::{Base, Ident, Strand, Value};
+use derive::Store;
+use revision::revisioned;
+use serde::{Deserialize, Serialize};
+use std::fmt::{self, Display};
+use std::ops::{Deref, Range};
+
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
+#[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
+#[non_exhaustive]
+pub struct AlterDatabaseStatement {
+	pub name: Ident,
+	pub if_exists: bool,
+	pub comment: Option<Option<Strand>>,
+	pub rename: Option<Ident>,
+}
+
+impl AlterDatabaseStatement {
+	pub(crate) async fn compute(&self, ctx: &Context, opt: &Options) -> Result<Value, Error> {
+		// Allowed to run?
+		opt.is_allowed(Action::Edit, ResourceKind::Database, &Base::Ns)?;
+		// Get the NS
+		let ns = opt.ns()?;
+		// Fetch the transaction
+		let txn = ctx.tx();
+		// Get the database definition
+		let mut db = match txn.get_db(ns, &self.name).await {
+			Ok(db) => db.deref().clone(),
+			Err(Error::DbNotFound {
+				..
+			}) if self.if_exists => return Ok(Value::None),
+			Err(v) => return Err(v),
+		};
+		// Process the statement
+		if let Some(ref comment) = &self.comment {
+			db.comment.clone_from(comment);
+		}
+		match &self.rename {
+			Some(name) => self.rename(ctx, opt, db, name).await?,
+			None => {
+				// Set the database definition
+				let key = crate::key::namespace::db::new(ns, &self.name);
+				txn.set(key, &db, None).await?;
+			}
+		}
+		// Clear the cache
+		txn.clear();
+		// Ok all good
+		Ok(Value::None)
+	}
+
+	/// Move all of the data of the database to a new database name. None of
+	/// the stored values refer to the database by name, so only the keys of
+	/// the database need to be changed. The definitions are moved within the
+	/// transaction, and the records and indexes are then moved in batches.
+	async fn rename(
+		&self,
+		ctx: &Context,
+		opt: &Options,
+		db: DefineDatabaseStatement,
+		name: &Ident,
+	) -> Result<(), Error> {
+		// Get the NS
+		let ns = opt.ns()?;
+		// Fetch the transaction
+		let txn = ctx.tx();
+		// The new database must not already exist
+		if txn.get_db(ns, name).await.is_ok() {
+			return Err(Error::DbAlreadyExists {
+				value: name.to_string(),
+			});
+		}
+		// The keys of either database may still be moving from a previous rename
+		#[cfg(not(target_arch = "wasm32"))]
+		for db in [self.name.as_str(), name.as_str()] {
+			let fm = ctx.get_field_migrator().ok_or_else(|| fail!("No Field Migrator"))?;
+			if fm.is_migrating_database(ns, db) {
+				return Err(Error::DbAlreadyMigrating {
+					db: db.to_owned(),
+				});
+			}
+		}
+		// Live queries are registered with the database name
+		let tbs = txn.all_tb(ns, &self.name, None).await?;
+		for tb in tbs.iter() {
+			if !txn.all_tb_lives(ns, &self.name, &tb.name).await?.is_empty() {
+				return Err(Error::Thrown(
+					"a database with live queries can not be renamed".into(),
+				));
+			}
+		}
+		// Set the new database definition
+		let key = crate::key::namespace::db::new(ns, name);
+		txn.set(
+			key,
+			DefineDatabaseStatement {
+				name: name.clone(),
+				..db
+			},
+			None,
+		)
+		.await?;
+		// Move the definitions of the database, and of its tables
+		let from = crate::key::database::all::new(ns, &self.name).encode()?;
+		let to = crate::key::database::all::new(ns, name).encode()?;
+		let mut rngs = vec![
+			crate::key::database::all::defprefix(ns, &self.name)
+				..crate::key::database::all::defsuffix(ns, &self.name),
+			crate::key::database::all::acprefix(ns, &self.name)
+				..crate::key::database::all::acsuffix(ns, &self.name),
+		];
+		for tb in tbs.iter() {
+			rngs.push(
+				crate::key::table::all::defprefix(ns, &self.name, &tb.name)
+					..crate::key::table::all::defsuffix(ns, &self.name, &tb.name),
+			);
+		}
+		for rng in rngs {
+			Self::move_keys(&txn, rng, &from, &to).await?;
+		}
+		// Remove the previous database
+		ctx.get_index_stores().database_removed(&txn, ns, &self.name).await?;
+		txn.del(crate::key::namespace::db::new(ns, &self.name)).await?;
+		// Move the remaining keys, once the new database is committed
+		self.migrate(ctx, ns, name, &from, &to).await
+	}
+
+	/// Move each key in a range of the previous database to the new database
+	async fn move_keys(
+		txn: &Transaction,
+		rng: Range<Vec<u8>>,
+		from: &[u8],
+		to: &[u8],
+	) -> Result<(), Error> {
+		let mut next = Some(rng.clone());
+		while let Some(rng) = next {
+			let batch = txn.batch(rng, *NORMAL_FETCH_SIZE, true, None).await?;
+			next = batch.next;
+			for (k, v) in batch.values.into_iter() {
+				let key = [to, &k[from.len()..]].concat();
+				txn.set(key, v, None).await?;
+			}
+		}
+		txn.delr(rng).await
+	}
+
+	#[cfg(target_arch = "wasm32")]
+	async fn migrate(
+		&self,
+		ctx: &Context,
+		ns: &str,
+		_name: &Ident,
+		from: &[u8],
+		to: &[u8],
+	) -> Result<(), Error> {
+		// There are no background tasks, so every key is moved now
+		let beg = crate::key::database::all::prefix(ns, &self.name);
+		let end = crate::key::database::all::suffix(ns, &self.name);
+		Self::move_keys(&ctx.tx(), beg..end, from, to).await
+	}
+
+	#[cfg(not(target_arch = "wasm32"))]
+	async fn migrate(
+		&self,
+		ctx: &Context,
+		ns: &str,
+		name: &Ident,
+		_from: &[u8],
+		_to: &[u8],
+	) -> Result<(), Error> {
+		let fm = ctx.get_field_migrator().ok_or_else(|| fail!("No Field Migrator"))?;
+		// Store the progress with the new database, so that the move is
+		// resumed when the datastore is restarted before it completes
+		let key = crate::key::database::mv::new(ns, name);
+		let val = move_progress(&self.name, MigrationStatus::Started);
+		ctx.tx().set(key, val, None).await?;
+		fm.migrate_database(ns, &self.name, name, 0)
+	}
+}
+
+impl Display for AlterDatabaseStatement {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		write!(f, "ALTER DATABASE")?;
+		if self.if_exists {
+			write!(f, " IF EXISTS")?
+		}
+		write!(f, " {}", self.name)?;
+		if let Some(comment) = &self.comment {
+			write!(f, " COMMENT {}", comment.clone().unwrap_or("NONE".into()))?
+		}
+		if let Some(rename) = &self.rename {
+			write!(f, " RENAME TO {rename}")?
+		}
+		Ok(())
+	}
+}
@@ -0,0 +1,186 @@
+use crate::ctx::Context;
+use crate::dbs::Options;
+use crate::doc::CursorDoc;
+use crate::err::Error;
+use crate::iam::{Action, ResourceKind};
+use crate::sql::statements::{DefineFieldStatement, DefineTableStatement};
+use crate::sql::{Base, Ident, Idiom, Kind, Strand, Value};
+use derive::Store;
+use reblessive::tree::Stk;
+use revision::revisioned;
+use serde::{Deserialize, Serialize};
+use std::fmt::{self, Display};
+use std::ops::Deref;
+use uuid::Uuid;
+
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
+#[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
+#[non_exhaustive]
+pub struct AlterFieldStatement {
+	pub name: Idiom,
+	pub what: Ident,
+	pub if_exists: bool,
+	pub rename: Option<Idiom>,
+	pub kind: Option<Kind>,
+	pub default: Option<Option<Value>>,
+	pub comment: Option<Option<Strand>>,
+	/// Whether the existing records are rewritten in the background
+	pub migrate: bool,
+}
+
+impl AlterFieldStatement {
+	pub(crate) async fn compute(
+		&self,
+		_stk: &mut Stk,
+		ctx: &Context,
+		opt: &Options,
+		_doc: Option<&CursorDoc>,
+	) -> Result<Value, Error> {
+		// Allowed to run?
+		opt.is_allowed(Action::Edit, ResourceKind::Field, &Base::Db)?;
+		// Get the NS and DB
+		let ns = opt.ns()?;
+		let db = opt.db()?;
+		// Fetch the transaction
+		let txn = ctx.tx();
+		// Get the field definition
+		let fd = self.name.to_string();
+		let mut df = match txn.get_tb_field(ns, db, &self.what, &fd).await {
+			Ok(fd) => fd.deref().clone(),
+			Err(Error::FdNotFound {
+				..
+			}) if self.if_exists => return Ok(Value::None),
+			Err(v) => return Err(v),
+		};
+		// Process the statement
+		if let Some(ref rename) = &self.rename {
+			// The new field name must not already be defined
+			let name = rename.to_string();
+			if txn.get_tb_field(ns, db, &self.what, &name).await.is_ok() {
+				return Err(Error::FdAlreadyExists {
+					value: name,
+				});
+			}
+			let key = crate::key::table::fd::new(ns, db, &self.what, &fd);
+			match self.migrate {
+				// Keep the previous field definition until the records are
+				// migrated, but allow the migration to remove the field
+				true => {
+					let previous = DefineFieldStatement {
+						kind: df.kind.clone().map(|k| match k.can_be_none() {
+							true => k,
+							false => Kind::Option(Box::new(k)),
+						}),
+						readonly: false,
+						value: None,
+						assert: None,
+						default: None,
+						..df.clone()
+					};
+					txn.set(key, previous, None).await?;
+				}
+				// Remove the previous field definition
+				false => txn.del(key).await?,
+			}
+			df.name = rename.clone();
+		}
+		if let Some(ref kind) = &self.kind {
+			df.kind = Some(kind.clone());
+		}
+		if let Some(ref default) = &self.default {
+			df.default.clone_from(default);
+		}
+		if let Some(ref comment) = &self.comment {
+			df.comment.clone_from(comment);
+		}
+		// Set the field definition
+		let key = crate::key::table::fd::new(ns, db, &self.what, &df.name.to_string());
+		txn.set(key, &df, None).await?;
+		// Refresh the table cache
+		let key = crate::key::database::tb::new(ns, db, &self.what);
+		let tb = txn.get_tb(ns, db, &self.what).await?;
+		txn.set(
+			key,
+			DefineTableStatement {
+				cache_fields_ts: Uuid::now_v7(),
+				..tb.as_ref().clone()
+			},
+			None,
+		)
+		.await?;
+		// Clear the cache
+		txn.clear();
+		// Rewrite the existing records
+		if self.migrate {
+			self.migrate(ctx, opt, &df)?;
+		}
+		// Ok all good
+		Ok(Value::None)
+	}
+
+	#[cfg(target_arch = "wasm32")]
+	fn migrate(
+		&self,
+		_ctx: &Context,
+		_opt: &Options,
+		_df: &DefineFieldStatement,
+	) -> Result<(), Error> {
+		Err(Error::Unimplemented("Field migration is not supported in WebAssembly".to_string()))
+	}
+
+	#[cfg(not(target_arch = "wasm32"))]
+	fn migrate(
+		&self,
+		ctx: &Context,
+		opt: &Options,
+		df: &DefineFieldStatement,
+	) -> Result<(), Error> {
+		use crate::kvs::FieldChanges;
+		// Only set a default on empty fields if one was specified
+		let default = match &self.default {
+			Some(Some(v)) => Some(v.clone()),
+			_ => None,
+		};
+		ctx.get_field_migrator().ok_or_else(|| fail!("No Field Migrator"))?.migrate(
+			ctx,
+			opt.clone(),
+			&self.what,
+			FieldChanges {
+				from: self.name.clone(),
+				name: df.name.clone(),
+				default,
+				field: df.clone(),
+			},
+		)
+	}
+}
+
+impl Display for AlterFieldStatement {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		write!(f, "ALTER FIELD")?;
+		if self.if_exists {
+			write!(f, " IF EXISTS")?
+		}
+		write!(f, " {} ON {}", self.name, self.what)?;
+		if let Some(rename) = &self.rename {
+			write!(f, " RENAME TO {rename}")?
+		}
+		if let Some(kind) = &self.kind {
+			write!(f, " TYPE {kind}")?
+		}
+		if let Some(default) = &self.default {
+			match default {
+				Some(v) => write!(f, " DEFAULT {v}")?,
+				None => write!(f, " DEFAULT NONE")?,
+			}
+		}
+		if let Some(comment) = &self.comment {
+			write!(f, " COMMENT {}", comment.clone().unwrap_or("NONE".into()))?
+		}
+		if self.migrate {
+			write!(f, " MIGRATE")?
+		}
+		Ok(())
+	}
+}
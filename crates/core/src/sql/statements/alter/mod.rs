@@ -1,6 +1,12 @@
+mod database;
+mod field;
 mod table;
+mod user;
 
+pub use database::AlterDatabaseStatement;
+pub use field::AlterFieldStatement;
 pub use table::AlterTableStatement;
+pub use user::AlterUserStatement;
 
 use crate::ctx::Context;
 use crate::dbs::Options;
@@ -13,12 +19,18 @@ use revision::revisioned;
 use serde::{Deserialize, Serialize};
 use std::fmt::{self, Display};
 
-#[revisioned(revision = 1)]
+#[revisioned(revision = 4)]
 #[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
 pub enum AlterStatement {
 	Table(AlterTableStatement),
+	#[revision(start = 2)]
+	Field(AlterFieldStatement),
+	#[revision(start = 3)]
+	Database(AlterDatabaseStatement),
+	#[revision(start = 4)]
+	User(AlterUserStatement),
 }
 
 impl AlterStatement {
@@ -36,6 +48,9 @@ impl AlterStatement {
 	) -> Result<Value, Error> {
 		match self {
 			Self::Table(ref v) => v.compute(stk, ctx, opt, doc).await,
+			Self::Field(ref v) => v.compute(stk, ctx, opt, doc).await,
+			Self::Database(ref v) => v.compute(ctx, opt).await,
+			Self::User(ref v) => v.compute(ctx, opt).await,
 		}
 	}
 }
@@ -44,6 +59,9 @@ impl Display for AlterStatement {
 	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
 		match self {
 			Self::Table(v) => Display::fmt(v, f),
+			Self::Field(v) => Display::fmt(v, f),
+			Self::Database(v) => Display::fmt(v, f),
+			Self::User(v) => Display::fmt(v, f),
 		}
 	}
 }
@@ -61,6 +79,6 @@ mod tests {
 			..Default::default()
 		});
 		let enc: Vec<u8> = stm.into();
-		assert_eq!(16, enc.len());
+		assert_eq!(17, enc.len());
 	}
 }
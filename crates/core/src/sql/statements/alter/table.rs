@@ -3,8 +3,12 @@ use crate::dbs::Options;
 use crate::doc::CursorDoc;
 use crate::err::Error;
 use crate::iam::{Action, ResourceKind};
+#[cfg(not(target_arch = "wasm32"))]
+use crate::kvs::{move_progress, MigrationStatus};
 use crate::sql::fmt::{is_pretty, pretty_indent};
-use crate::sql::statements::DefineTableStatement;
+use crate::sql::statements::{
+	DefineEventStatement, DefineFieldStatement, DefineIndexStatement, DefineTableStatement,
+};
 use crate::sql::{changefeed::ChangeFeed, Base, Ident, Permissions, Strand, Value};
 use crate::sql::{Kind, TableType};
 use derive::Store;
@@ -13,8 +17,9 @@ use revision::revisioned;
 use serde::{Deserialize, Serialize};
 use std::fmt::{self, Display, Write};
 use std::ops::Deref;
+use uuid::Uuid;
 
-#[revisioned(revision = 1)]
+#[revisioned(revision = 2)]
 #[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -27,6 +32,8 @@ pub struct AlterTableStatement {
 	pub changefeed: Option<Option<ChangeFeed>>,
 	pub comment: Option<Option<Strand>>,
 	pub kind: Option<TableType>,
+	#[revision(start = 2)]
+	pub rename: Option<Ident>,
 }
 
 impl AlterTableStatement {
@@ -82,9 +89,152 @@ impl AlterTableStatement {
 		}
 		// Clear the cache
 		txn.clear();
+		// Rename the table
+		if let Some(ref name) = &self.rename {
+			self.rename(ctx, opt, dt, name).await?;
+		}
 		// Ok all good
 		Ok(Value::None)
 	}
+
+	/// Move the definitions of the table to a new table name, and its records in the background
+	async fn rename(
+		&self,
+		ctx: &Context,
+		opt: &Options,
+		dt: DefineTableStatement,
+		name: &Ident,
+	) -> Result<(), Error> {
+		// Get the NS and DB
+		let ns = opt.ns()?;
+		let db = opt.db()?;
+		// Fetch the transaction
+		let txn = ctx.tx();
+		// The new table must not already exist
+		if txn.get_tb(ns, db, name).await.is_ok() {
+			return Err(Error::TbAlreadyExists {
+				value: name.to_string(),
+			});
+		}
+		// Views and live queries refer to the table by name
+		if dt.view.is_some()
+			|| !txn.all_tb_views(ns, db, &self.name).await?.is_empty()
+			|| !txn.all_tb_lives(ns, db, &self.name).await?.is_empty()
+		{
+			return Err(Error::Thrown(
+				"a table which is a view, has views, or has live queries can not be renamed".into(),
+			));
+		}
+		// Graph edges and references are stored on both of the linked records
+		let beg = crate::key::graph::tbprefix(ns, db, &self.name);
+		let end = crate::key::graph::tbsuffix(ns, db, &self.name);
+		let edges = !txn.keys(beg..end, 1, None).await?.is_empty();
+		let beg = crate::key::reference::tbprefix(ns, db, &self.name);
+		let end = crate::key::reference::tbsuffix(ns, db, &self.name);
+		if edges || !txn.keys(beg..end, 1, None).await?.is_empty() {
+			return Err(Error::Thrown(
+				"a table with graph edges or record references can not be renamed".into(),
+			));
+		}
+		// Encrypted values are bound to the id of their record
+		let fields = txn.all_tb_fields(ns, db, &self.name, None).await?;
+		if fields.iter().any(|fd| fd.encrypted) {
+			return Err(Error::Thrown("a table with ENCRYPTED fields can not be renamed".into()));
+		}
+		// Set the new table definition
+		let key = crate::key::database::tb::new(ns, db, name);
+		txn.set(
+			key,
+			DefineTableStatement {
+				name: name.clone(),
+				cache_fields_ts: Uuid::now_v7(),
+				cache_events_ts: Uuid::now_v7(),
+				cache_tables_ts: Uuid::now_v7(),
+				cache_indexes_ts: Uuid::now_v7(),
+				cache_lives_ts: Uuid::now_v7(),
+				..dt
+			},
+			None,
+		)
+		.await?;
+		// Move the field, event, and index definitions
+		for fd in fields.iter() {
+			let key = crate::key::table::fd::new(ns, db, name, &fd.name.to_string());
+			let val = DefineFieldStatement {
+				what: name.clone(),
+				..fd.clone()
+			};
+			txn.set(key, val, None).await?;
+		}
+		for ev in txn.all_tb_events(ns, db, &self.name).await?.iter() {
+			let key = crate::key::table::ev::new(ns, db, name, &ev.name);
+			let val = DefineEventStatement {
+				what: name.clone(),
+				..ev.clone()
+			};
+			txn.set(key, val, None).await?;
+			// Move the last run of the scheduled event
+			let key = crate::key::table::es::new(ns, db, &self.name, &ev.name);
+			if let Some(v) = txn.get(key.clone(), None).await? {
+				txn.set(crate::key::table::es::new(ns, db, name, &ev.name), v, None).await?;
+				txn.del(key).await?;
+			}
+		}
+		for ix in txn.all_tb_indexes(ns, db, &self.name).await?.iter() {
+			let key = crate::key::table::ix::new(ns, db, name, &ix.name);
+			let val = DefineIndexStatement {
+				what: name.clone(),
+				..ix.clone()
+			};
+			txn.set(key, val, None).await?;
+		}
+		// Move the table statistics
+		let key = crate::key::table::st::new(ns, db, &self.name);
+		if let Some(v) = txn.get(key.clone(), None).await? {
+			txn.set(crate::key::table::st::new(ns, db, name), v, None).await?;
+			txn.del(key).await?;
+		}
+		// Remove the previous table definitions
+		ctx.get_index_stores().table_removed(&txn, ns, db, &self.name).await?;
+		txn.del(crate::key::database::tb::new(ns, db, &self.name)).await?;
+		let tb = &self.name;
+		for rng in [
+			crate::key::table::fd::prefix(ns, db, tb)..crate::key::table::fd::suffix(ns, db, tb),
+			crate::key::table::ev::prefix(ns, db, tb)..crate::key::table::ev::suffix(ns, db, tb),
+			crate::key::table::ix::prefix(ns, db, tb)..crate::key::table::ix::suffix(ns, db, tb),
+			crate::key::table::fm::prefix(ns, db, tb)..crate::key::table::fm::suffix(ns, db, tb),
+		] {
+			txn.delr(rng).await?;
+		}
+		// Clear the cache
+		txn.clear();
+		// Move the records in batches, once the new table is committed
+		self.migrate(ctx, opt, name).await
+	}
+
+	#[cfg(target_arch = "wasm32")]
+	async fn migrate(&self, _ctx: &Context, _opt: &Options, _name: &Ident) -> Result<(), Error> {
+		Err(Error::Unimplemented("Renaming a table is not supported in WebAssembly".to_string()))
+	}
+
+	#[cfg(not(target_arch = "wasm32"))]
+	async fn migrate(&self, ctx: &Context, opt: &Options, name: &Ident) -> Result<(), Error> {
+		let fm = ctx.get_field_migrator().ok_or_else(|| fail!("No Field Migrator"))?;
+		// The records of either table may still be moving from a previous rename
+		for tb in [self.name.as_str(), name.as_str()] {
+			if fm.is_migrating_table(opt.ns()?, opt.db()?, tb) {
+				return Err(Error::TbAlreadyMigrating {
+					table: tb.to_owned(),
+				});
+			}
+		}
+		// Store the progress with the new table, so that the move is
+		// resumed when the datastore is restarted before it completes
+		let key = crate::key::table::mv::new(opt.ns()?, opt.db()?, name);
+		let val = move_progress(&self.name, MigrationStatus::Started);
+		ctx.tx().set(key, val, None).await?;
+		fm.migrate_table(ctx, opt.clone(), &self.name, name, 0)
+	}
 }
 
 impl Display for AlterTableStatement {
@@ -117,6 +267,9 @@ impl Display for AlterTableStatement {
 						)?;
 					}
 				}
+				TableType::TimeSeries(ts) => {
+					write!(f, " TIMESERIES TIMESTAMP {}", ts.timestamp)?;
+				}
 				TableType::Any => {
 					f.write_str(" ANY")?;
 				}
@@ -135,6 +288,9 @@ impl Display for AlterTableStatement {
 		if let Some(comment) = &self.comment {
 			write!(f, " COMMENT {}", comment.clone().unwrap_or("NONE".into()))?
 		}
+		if let Some(rename) = &self.rename {
+			write!(f, " RENAME TO {rename}")?
+		}
 		if let Some(changefeed) = &self.changefeed {
 			write!(f, " CHANGEFEED {}", changefeed.map_or("NONE".into(), |v| v.to_string()))?
 		}
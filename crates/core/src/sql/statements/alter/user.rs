@@ -0,0 +1,151 @@
+use crate::ctx::Context;
+use crate::dbs::Options;
+use crate::err::Error;
+use crate::iam::{Action, ResourceKind, Role};
+use crate::kvs::Key;
+use crate::sql::escape::quote_str;
+use crate::sql::fmt::Fmt;
+use crate::sql::{Base, Datetime, Duration, Ident, Strand, Value};
+use derive::Store;
+use rand::{distributions::Alphanumeric, Rng};
+use revision::revisioned;
+use serde::{Deserialize, Serialize};
+use std::fmt::{self, Display};
+use std::ops::Deref;
+
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
+#[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
+#[non_exhaustive]
+pub struct AlterUserStatement {
+	pub name: Ident,
+	pub base: Base,
+	pub if_exists: bool,
+	/// The new password hash of the user
+	pub hash: Option<String>,
+	pub roles: Option<Vec<Ident>>,
+	pub token_duration: Option<Duration>,
+	pub session_duration: Option<Option<Duration>>,
+	pub password_duration: Option<Option<Duration>>,
+	pub comment: Option<Option<Strand>>,
+}
+
+impl AlterUserStatement {
+	pub(crate) async fn compute(&self, ctx: &Context, opt: &Options) -> Result<Value, Error> {
+		// Allowed to run?
+		opt.is_allowed(Action::Edit, ResourceKind::Actor, &self.base)?;
+		// Fetch the transaction
+		let txn = ctx.tx();
+		// Get the user definition
+		let (key, user): (Key, _) = match self.base {
+			Base::Root => {
+				(crate::key::root::us::new(&self.name).into(), txn.get_root_user(&self.name).await)
+			}
+			Base::Ns => (
+				crate::key::namespace::us::new(opt.ns()?, &self.name).into(),
+				txn.get_ns_user(opt.ns()?, &self.name).await,
+			),
+			Base::Db => (
+				crate::key::database::us::new(opt.ns()?, opt.db()?, &self.name).into(),
+				txn.get_db_user(opt.ns()?, opt.db()?, &self.name).await,
+			),
+			_ => return Err(Error::InvalidLevel(self.base.to_string())),
+		};
+		let mut user = match user {
+			Ok(user) => user.deref().clone(),
+			Err(
+				Error::UserRootNotFound {
+					..
+				}
+				| Error::UserNsNotFound {
+					..
+				}
+				| Error::UserDbNotFound {
+					..
+				},
+			) if self.if_exists => return Ok(Value::None),
+			Err(v) => return Err(v),
+		};
+		// Process the statement
+		if let Some(ref hash) = &self.hash {
+			user.hash.clone_from(hash);
+			user.changed = Some(Datetime::default());
+			// Tokens issued before the password was changed are no longer valid
+			user.code = rand::thread_rng()
+				.sample_iter(&Alphanumeric)
+				.take(128)
+				.map(char::from)
+				.collect::<String>();
+		}
+		if let Some(ref roles) = &self.roles {
+			// Only the existing roles can be granted
+			for role in roles.iter() {
+				Role::try_from(role)?;
+			}
+			user.roles.clone_from(roles);
+		}
+		if let Some(duration) = self.token_duration {
+			user.duration.token = Some(duration);
+		}
+		if let Some(duration) = self.session_duration {
+			user.duration.session = duration;
+		}
+		if let Some(duration) = self.password_duration {
+			user.duration.password = duration;
+		}
+		if let Some(ref comment) = &self.comment {
+			user.comment.clone_from(comment);
+		}
+		// Set the user definition
+		txn.set(key, &user, None).await?;
+		// Clear the cache
+		txn.clear();
+		// Ok all good
+		Ok(Value::None)
+	}
+}
+
+impl Display for AlterUserStatement {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		write!(f, "ALTER USER")?;
+		if self.if_exists {
+			write!(f, " IF EXISTS")?
+		}
+		write!(f, " {} ON {}", self.name, self.base)?;
+		if let Some(ref hash) = self.hash {
+			write!(f, " PASSHASH {}", quote_str(hash))?
+		}
+		if let Some(ref roles) = self.roles {
+			write!(
+				f,
+				" ROLES {}",
+				Fmt::comma_separated(
+					&roles.iter().map(|r| r.to_string().to_uppercase()).collect::<Vec<String>>()
+				)
+			)?
+		}
+		let mut durations = vec![];
+		if let Some(dur) = self.token_duration {
+			durations.push(format!("FOR TOKEN {dur}"));
+		}
+		if let Some(dur) = self.session_duration {
+			durations.push(format!(
+				"FOR SESSION {}",
+				dur.map(|d| d.to_string()).unwrap_or("NONE".into())
+			));
+		}
+		if let Some(dur) = self.password_duration {
+			durations.push(format!(
+				"FOR PASSWORD {}",
+				dur.map(|d| d.to_string()).unwrap_or("NONE".into())
+			));
+		}
+		if !durations.is_empty() {
+			write!(f, " DURATION {}", durations.join(", "))?
+		}
+		if let Some(comment) = &self.comment {
+			write!(f, " COMMENT {}", comment.clone().unwrap_or("NONE".into()))?
+		}
+		Ok(())
+	}
+}
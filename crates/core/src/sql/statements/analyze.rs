@@ -4,6 +4,7 @@ use crate::doc::CursorDoc;
 use crate::err::Error;
 use crate::iam::{Action, ResourceKind};
 use crate::idx::ft::FtIndex;
+use crate::idx::planner::statistics::TableStatistics;
 use crate::idx::trees::mtree::MTreeIndex;
 use crate::idx::IndexKeyBase;
 use crate::kvs::TransactionType;
@@ -17,15 +18,22 @@ use serde::{Deserialize, Serialize};
 use std::fmt;
 use std::fmt::{Display, Formatter};
 
-#[revisioned(revision = 1)]
+#[revisioned(revision = 2)]
 #[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
 pub enum AnalyzeStatement {
 	Idx(Ident, Ident),
+	#[revision(start = 2)]
+	Tb(Ident),
 }
 
 impl AnalyzeStatement {
+	/// Check if we require a writeable transaction
+	pub(crate) fn writeable(&self) -> bool {
+		matches!(self, Self::Tb(_))
+	}
+
 	/// Process this type returning a computed simple Value
 	pub(crate) async fn compute(
 		&self,
@@ -69,6 +77,21 @@ impl AnalyzeStatement {
 				// Return the result object
 				Ok(value)
 			}
+			AnalyzeStatement::Tb(tb) => {
+				// Allowed to run?
+				opt.is_allowed(Action::Edit, ResourceKind::Table, &Base::Db)?;
+				// Get the NS and DB
+				let ns = opt.ns()?;
+				let db = opt.db()?;
+				// Check the table exists
+				let txn = ctx.tx();
+				txn.get_tb(ns, db, tb).await?;
+				// Collect and store the statistics
+				let st = TableStatistics::analyze(ctx, opt, tb).await?;
+				st.set(&txn, ns, db, tb).await?;
+				// Return the result object
+				Ok(st.into())
+			}
 		}
 	}
 }
@@ -77,6 +100,7 @@ impl Display for AnalyzeStatement {
 	fn fmt(&self, f: &mut Formatter) -> fmt::Result {
 		match self {
 			Self::Idx(tb, idx) => write!(f, "ANALYZE INDEX {idx} ON {tb}"),
+			Self::Tb(tb) => write!(f, "ANALYZE TABLE {tb}"),
 		}
 	}
 }
@@ -3,14 +3,22 @@ use revision::revisioned;
 use serde::{Deserialize, Serialize};
 use std::fmt;
 
-#[revisioned(revision = 1)]
+#[revisioned(revision = 2)]
 #[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
-pub struct BeginStatement;
+pub struct BeginStatement {
+	// Whether the transaction only reads data
+	#[revision(start = 2)]
+	pub readonly: bool,
+}
 
 impl fmt::Display for BeginStatement {
 	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
-		f.write_str("BEGIN TRANSACTION")
+		f.write_str("BEGIN TRANSACTION")?;
+		if self.readonly {
+			f.write_str(" READONLY")?;
+		}
+		Ok(())
 	}
 }
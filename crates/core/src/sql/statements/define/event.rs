@@ -12,7 +12,7 @@ use serde::{Deserialize, Serialize};
 use std::fmt::{self, Display};
 use uuid::Uuid;
 
-#[revisioned(revision = 3)]
+#[revisioned(revision = 4)]
 #[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -26,6 +26,9 @@ pub struct DefineEventStatement {
 	pub if_not_exists: bool,
 	#[revision(start = 3)]
 	pub overwrite: bool,
+	/// The cron schedule on which this event runs
+	#[revision(start = 4)]
+	pub schedule: Option<Strand>,
 }
 
 impl DefineEventStatement {
@@ -94,7 +97,13 @@ impl Display for DefineEventStatement {
 		if self.overwrite {
 			write!(f, " OVERWRITE")?
 		}
-		write!(f, " {} ON {} WHEN {} THEN {}", self.name, self.what, self.when, self.then)?;
+		write!(f, " {} ON {}", self.name, self.what)?;
+		if let Some(ref v) = self.schedule {
+			write!(f, " WHEN SCHEDULE {v}")?
+		} else {
+			write!(f, " WHEN {}", self.when)?
+		}
+		write!(f, " THEN {}", self.then)?;
 		if let Some(ref v) = self.comment {
 			write!(f, " COMMENT {v}")?
 		}
@@ -108,6 +117,7 @@ impl InfoStructure for DefineEventStatement {
 			"name".to_string() => self.name.structure(),
 			"what".to_string() => self.what.structure(),
 			"when".to_string() => self.when.structure(),
+			"schedule".to_string(), if let Some(v) = self.schedule => v.into(),
 			"then".to_string() => self.then.structure(),
 			"comment".to_string(), if let Some(v) = self.comment => v.into(),
 		})
@@ -1,5 +1,7 @@
+use crate::cnf::NORMAL_FETCH_SIZE;
 use crate::ctx::Context;
 use crate::dbs::Options;
+use crate::doc::reference::links;
 use crate::doc::CursorDoc;
 use crate::err::Error;
 use crate::iam::{Action, ResourceKind};
@@ -7,7 +9,7 @@ use crate::sql::fmt::{is_pretty, pretty_indent};
 use crate::sql::statements::info::InfoStructure;
 use crate::sql::statements::DefineTableStatement;
 use crate::sql::Part;
-use crate::sql::{Base, Ident, Idiom, Kind, Permissions, Strand, Value};
+use crate::sql::{Base, Ident, Idiom, Kind, Permissions, Reference, Strand, Thing, Value};
 use crate::sql::{Relation, TableType};
 use derive::Store;
 use revision::revisioned;
@@ -15,7 +17,7 @@ use serde::{Deserialize, Serialize};
 use std::fmt::{self, Display, Write};
 use uuid::Uuid;
 
-#[revisioned(revision = 4)]
+#[revisioned(revision = 6)]
 #[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -35,6 +37,11 @@ pub struct DefineFieldStatement {
 	pub if_not_exists: bool,
 	#[revision(start = 4)]
 	pub overwrite: bool,
+	#[revision(start = 5)]
+	pub reference: Option<Reference>,
+	/// Whether the value is encrypted before it is stored
+	#[revision(start = 6)]
+	pub encrypted: bool,
 }
 
 impl DefineFieldStatement {
@@ -55,7 +62,8 @@ impl DefineFieldStatement {
 		// Get the name of the field
 		let fd = self.name.to_string();
 		// Check if the definition exists
-		if txn.get_tb_field(ns, db, &self.what, &fd).await.is_ok() {
+		let old = txn.get_tb_field(ns, db, &self.what, &fd).await.ok();
+		if old.is_some() {
 			if self.if_not_exists {
 				return Ok(Value::None);
 			} else if !self.overwrite {
@@ -64,6 +72,29 @@ impl DefineFieldStatement {
 				});
 			}
 		}
+		// A reference can only be defined on a record link field
+		if self.reference.is_some() && !self.kind.as_ref().is_some_and(Kind::is_record_link) {
+			return Err(Error::Thrown("a field with a REFERENCE clause must be a record".into()));
+		}
+		// An encrypted value is stored in place of the whole field
+		if self.encrypted
+			&& (!self.name.iter().all(|p| matches!(p, Part::Field(_)))
+				|| matches!(fd.as_str(), "id" | "in" | "out"))
+		{
+			return Err(Error::Thrown(
+				"an ENCRYPTED field must be a field path without wildcards, and can not be the id, in, or out field".into(),
+			));
+		}
+		// An encrypted field would be stored in plaintext in an index
+		if self.encrypted {
+			let ixs = txn.all_tb_indexes(ns, db, &self.what).await?;
+			if let Some(ix) = ixs.iter().find(|ix| ix.cols.iter().any(|v| self.overlaps(v))) {
+				return Err(Error::Thrown(format!(
+					"the ENCRYPTED field '{}' can not be indexed, but is used by the index '{}'",
+					self.name, ix.name
+				)));
+			}
+		}
 		// Process the statement
 		let key = crate::key::table::fd::new(ns, db, &self.what, &fd);
 		txn.get_or_add_ns(ns, opt.strict).await?;
@@ -212,11 +243,48 @@ impl DefineFieldStatement {
 				}
 			}
 		}
+		// Store the reverse pointers of the existing records
+		if self.reference.is_some() && old.map_or(true, |v| v.reference.is_none()) {
+			self.store_references(ctx, ns, db).await?;
+		}
 		// Clear the cache
 		txn.clear();
 		// Ok all good
 		Ok(Value::None)
 	}
+	/// Checks whether an idiom refers to this field, or to a part of the
+	/// value of this field, or to a value which contains this field.
+	pub(crate) fn overlaps(&self, idiom: &Idiom) -> bool {
+		idiom.0.starts_with(&self.name.0) || self.name.0.starts_with(&idiom.0)
+	}
+	/// Stores a reverse pointer on every record which is linked to from
+	/// this field in the records which already exist in the table, as the
+	/// pointers are otherwise only stored when a record is created or updated.
+	async fn store_references(&self, ctx: &Context, ns: &str, db: &str) -> Result<(), Error> {
+		// Fetch the transaction
+		let txn = ctx.tx();
+		// Get the name of the field
+		let ff = self.name.to_string();
+		// Loop over the records in the table
+		let beg = crate::key::thing::prefix(ns, db, &self.what);
+		let end = crate::key::thing::suffix(ns, db, &self.what);
+		let mut next = Some(beg..end);
+		while let Some(rng) = next {
+			let batch = txn.batch(rng, *NORMAL_FETCH_SIZE, true, None).await?;
+			next = batch.next;
+			for (k, v) in batch.values.into_iter() {
+				let key: crate::key::thing::Thing = (&k).into();
+				let rid = Thing::from((key.tb, key.id));
+				let val: Value = (&v).into();
+				// Store the pointers for the linked records
+				for v in links(&val.pick(&self.name)) {
+					let key = crate::key::reference::new(ns, db, &v.tb, &v.id, &rid, &ff);
+					txn.set(key, Vec::<u8>::new(), None).await?;
+				}
+			}
+		}
+		Ok(())
+	}
 }
 
 impl Display for DefineFieldStatement {
@@ -247,6 +315,12 @@ impl Display for DefineFieldStatement {
 		if let Some(ref v) = self.assert {
 			write!(f, " ASSERT {v}")?
 		}
+		if let Some(ref v) = self.reference {
+			write!(f, " REFERENCE {v}")?
+		}
+		if self.encrypted {
+			write!(f, " ENCRYPTED")?
+		}
 		if let Some(ref v) = self.comment {
 			write!(f, " COMMENT {v}")?
 		}
@@ -276,6 +350,8 @@ impl InfoStructure for DefineFieldStatement {
 			"assert".to_string(), if let Some(v) = self.assert => v.structure(),
 			"default".to_string(), if let Some(v) = self.default => v.structure(),
 			"readonly".to_string() => self.readonly.into(),
+			"reference".to_string(), if let Some(v) = self.reference => v.structure(),
+			"encrypted".to_string(), if self.encrypted => true.into(),
 			"permissions".to_string() => self.permissions.structure(),
 			"comment".to_string(), if let Some(v) = self.comment => v.into(),
 		})
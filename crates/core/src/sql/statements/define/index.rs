@@ -15,7 +15,7 @@ use std::fmt::{self, Display};
 use std::sync::Arc;
 use uuid::Uuid;
 
-#[revisioned(revision = 4)]
+#[revisioned(revision = 5)]
 #[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -31,6 +31,9 @@ pub struct DefineIndexStatement {
 	pub overwrite: bool,
 	#[revision(start = 4)]
 	pub concurrently: bool,
+	/// Whether the entries are stored in descending order
+	#[revision(start = 5)]
+	pub desc: bool,
 }
 
 impl DefineIndexStatement {
@@ -47,7 +50,7 @@ impl DefineIndexStatement {
 		// Fetch the transaction
 		let txn = ctx.tx();
 		// Check if the definition exists
-		if txn.get_tb_index(opt.ns()?, opt.db()?, &self.what, &self.name).await.is_ok() {
+		if let Ok(ix) = txn.get_tb_index(opt.ns()?, opt.db()?, &self.what, &self.name).await {
 			if self.if_not_exists {
 				return Ok(Value::None);
 			} else if !self.overwrite {
@@ -55,6 +58,11 @@ impl DefineIndexStatement {
 					value: self.name.to_string(),
 				});
 			}
+			// The existing entries are stored in the other order
+			if ix.desc != self.desc {
+				let key = crate::key::index::all::new(opt.ns()?, opt.db()?, &self.what, &self.name);
+				txn.delp(key).await?;
+			}
 		}
 		// Does the table exists?
 		match txn.get_tb(opt.ns()?, opt.db()?, &self.what).await {
@@ -76,6 +84,16 @@ impl DefineIndexStatement {
 			// Any other error should be returned
 			Err(e) => return Err(e),
 		}
+		// An encrypted field would be stored in plaintext in the index
+		let fds = txn.all_tb_fields(opt.ns()?, opt.db()?, &self.what, None).await?;
+		for fd in fds.iter().filter(|fd| fd.encrypted) {
+			if self.cols.iter().any(|v| fd.overlaps(v)) {
+				return Err(Error::Thrown(format!(
+					"the ENCRYPTED field '{}' can not be indexed",
+					fd.name
+				)));
+			}
+		}
 		// Process the statement
 		let key = crate::key::table::ix::new(opt.ns()?, opt.db()?, &self.what, &self.name);
 		txn.get_or_add_ns(opt.ns()?, opt.strict).await?;
@@ -118,7 +136,7 @@ impl DefineIndexStatement {
 		Ok(Value::None)
 	}
 
-	async fn sync_index(
+	pub(crate) async fn sync_index(
 		&self,
 		stk: &mut Stk,
 		ctx: &Context,
@@ -157,6 +175,9 @@ impl Display for DefineIndexStatement {
 			write!(f, " OVERWRITE")?
 		}
 		write!(f, " {} ON {} FIELDS {}", self.name, self.what, self.cols)?;
+		if self.desc {
+			write!(f, " DESC")?;
+		}
 		if Index::Idx != self.index {
 			write!(f, " {}", self.index)?;
 		}
@@ -177,6 +198,7 @@ impl InfoStructure for DefineIndexStatement {
 			"what".to_string() => self.what.structure(),
 			"cols".to_string() => self.cols.structure(),
 			"index".to_string() => self.index.structure(),
+			"desc".to_string(), if self.desc => true.into(),
 			"comment".to_string(), if let Some(v) = self.comment => v.into(),
 		})
 	}
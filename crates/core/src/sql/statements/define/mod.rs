@@ -10,6 +10,7 @@ mod index;
 mod model;
 mod namespace;
 mod param;
+mod sequence;
 mod table;
 mod user;
 
@@ -22,8 +23,9 @@ pub use field::DefineFieldStatement;
 pub use function::DefineFunctionStatement;
 pub use index::DefineIndexStatement;
 pub use model::DefineModelStatement;
-pub use namespace::DefineNamespaceStatement;
+pub use namespace::{DefineNamespaceStatement, NamespaceLimits};
 pub use param::DefineParamStatement;
+pub use sequence::DefineSequenceStatement;
 pub use table::DefineTableStatement;
 pub use user::DefineUserStatement;
 
@@ -41,7 +43,7 @@ use revision::revisioned;
 use serde::{Deserialize, Serialize};
 use std::fmt::{self, Display};
 
-#[revisioned(revision = 2)]
+#[revisioned(revision = 3)]
 #[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -72,6 +74,8 @@ pub enum DefineStatement {
 	#[revision(start = 2)]
 	Access(DefineAccessStatement),
 	Config(DefineConfigStatement),
+	#[revision(start = 3)]
+	Sequence(DefineSequenceStatement),
 }
 
 // Revision implementations
@@ -118,6 +122,7 @@ impl DefineStatement {
 			Self::Model(ref v) => v.compute(ctx, opt, doc).await,
 			Self::Access(ref v) => v.compute(ctx, opt, doc).await,
 			Self::Config(ref v) => v.compute(ctx, opt, doc).await,
+			Self::Sequence(ref v) => v.compute(ctx, opt).await,
 		}
 	}
 }
@@ -138,6 +143,7 @@ impl Display for DefineStatement {
 			Self::Model(v) => Display::fmt(v, f),
 			Self::Access(v) => Display::fmt(v, f),
 			Self::Config(v) => Display::fmt(v, f),
+			Self::Sequence(v) => Display::fmt(v, f),
 		}
 	}
 }
@@ -155,6 +161,6 @@ mod tests {
 			..Default::default()
 		});
 		let enc: Vec<u8> = stm.into();
-		assert_eq!(13, enc.len());
+		assert_eq!(17, enc.len());
 	}
 }
@@ -10,7 +10,7 @@ use revision::revisioned;
 use serde::{Deserialize, Serialize};
 use std::fmt::{self, Display};
 
-#[revisioned(revision = 3)]
+#[revisioned(revision = 4)]
 #[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -22,6 +22,55 @@ pub struct DefineNamespaceStatement {
 	pub if_not_exists: bool,
 	#[revision(start = 3)]
 	pub overwrite: bool,
+	#[revision(start = 4)]
+	pub limits: NamespaceLimits,
+}
+
+/// The limits on the resources which a namespace can use
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Hash)]
+#[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
+#[non_exhaustive]
+pub struct NamespaceLimits {
+	/// The maximum number of queries running at once
+	pub queries: Option<u32>,
+	/// The maximum number of live queries
+	pub live: Option<u32>,
+	/// The maximum number of queries started each second
+	pub rate: Option<u32>,
+}
+
+impl NamespaceLimits {
+	/// Check if any of the limits are set
+	pub fn is_empty(&self) -> bool {
+		self.queries.is_none() && self.live.is_none() && self.rate.is_none()
+	}
+}
+
+impl Display for NamespaceLimits {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		let mut limits = vec![];
+		if let Some(v) = self.queries {
+			limits.push(format!("QUERIES {v}"));
+		}
+		if let Some(v) = self.live {
+			limits.push(format!("LIVE {v}"));
+		}
+		if let Some(v) = self.rate {
+			limits.push(format!("RATE {v}"));
+		}
+		write!(f, "LIMIT {}", limits.join(", "))
+	}
+}
+
+impl InfoStructure for NamespaceLimits {
+	fn structure(self) -> Value {
+		Value::from(map! {
+			"queries".to_string(), if let Some(v) = self.queries => v.into(),
+			"live".to_string(), if let Some(v) = self.live => v.into(),
+			"rate".to_string(), if let Some(v) = self.rate => v.into(),
+		})
+	}
 }
 
 impl DefineNamespaceStatement {
@@ -81,6 +130,9 @@ impl Display for DefineNamespaceStatement {
 			write!(f, " OVERWRITE")?
 		}
 		write!(f, " {}", self.name)?;
+		if !self.limits.is_empty() {
+			write!(f, " {}", self.limits)?
+		}
 		if let Some(ref v) = self.comment {
 			write!(f, " COMMENT {v}")?
 		}
@@ -92,6 +144,7 @@ impl InfoStructure for DefineNamespaceStatement {
 	fn structure(self) -> Value {
 		Value::from(map! {
 			"name".to_string() => self.name.structure(),
+			"limits".to_string(), if !self.limits.is_empty() => self.limits.structure(),
 			"comment".to_string(), if let Some(v) = self.comment => v.into(),
 		})
 	}
@@ -0,0 +1,92 @@
+use crate::ctx::Context;
+use crate::dbs::Options;
+use crate::err::Error;
+use crate::iam::{Action, ResourceKind};
+use crate::sql::statements::info::InfoStructure;
+use crate::sql::{Base, Ident, Strand, Value};
+use derive::Store;
+use revision::revisioned;
+use serde::{Deserialize, Serialize};
+use std::fmt::{self, Display};
+
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
+#[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
+#[non_exhaustive]
+pub struct DefineSequenceStatement {
+	pub name: Ident,
+	/// The first value which is returned by the sequence
+	pub start: i64,
+	pub comment: Option<Strand>,
+	pub if_not_exists: bool,
+	pub overwrite: bool,
+}
+
+impl DefineSequenceStatement {
+	/// Process this type returning a computed simple Value
+	pub(crate) async fn compute(&self, ctx: &Context, opt: &Options) -> Result<Value, Error> {
+		// Allowed to run?
+		opt.is_allowed(Action::Edit, ResourceKind::Sequence, &Base::Db)?;
+		// Fetch the transaction
+		let txn = ctx.tx();
+		// Check if the definition exists
+		if txn.get_db_sequence(opt.ns()?, opt.db()?, &self.name).await.is_ok() {
+			if self.if_not_exists {
+				return Ok(Value::None);
+			} else if !self.overwrite {
+				return Err(Error::SqAlreadyExists {
+					value: self.name.to_string(),
+				});
+			}
+		}
+		// Process the statement
+		let key = crate::key::database::sq::new(opt.ns()?, opt.db()?, &self.name);
+		txn.get_or_add_ns(opt.ns()?, opt.strict).await?;
+		txn.get_or_add_db(opt.ns()?, opt.db()?, opt.strict).await?;
+		txn.set(
+			key,
+			DefineSequenceStatement {
+				// Don't persist the `IF NOT EXISTS` clause to schema
+				if_not_exists: false,
+				overwrite: false,
+				..self.clone()
+			},
+			None,
+		)
+		.await?;
+		// Restart the sequence from the start value
+		let key = crate::key::database::sv::new(opt.ns()?, opt.db()?, &self.name);
+		txn.del(key).await?;
+		// Clear the cache
+		txn.clear();
+		// Ok all good
+		Ok(Value::None)
+	}
+}
+
+impl Display for DefineSequenceStatement {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		write!(f, "DEFINE SEQUENCE")?;
+		if self.if_not_exists {
+			write!(f, " IF NOT EXISTS")?
+		}
+		if self.overwrite {
+			write!(f, " OVERWRITE")?
+		}
+		write!(f, " {} START {}", self.name, self.start)?;
+		if let Some(ref v) = self.comment {
+			write!(f, " COMMENT {v}")?
+		}
+		Ok(())
+	}
+}
+
+impl InfoStructure for DefineSequenceStatement {
+	fn structure(self) -> Value {
+		Value::from(map! {
+			"name".to_string() => self.name.structure(),
+			"start".to_string() => self.start.into(),
+			"comment".to_string(), if let Some(v) = self.comment => v.into(),
+		})
+	}
+}
@@ -9,8 +9,8 @@ use crate::sql::fmt::{is_pretty, pretty_indent};
 use crate::sql::paths::{IN, OUT};
 use crate::sql::statements::info::InfoStructure;
 use crate::sql::{
-	changefeed::ChangeFeed, statements::UpdateStatement, Base, Ident, Output, Permissions, Strand,
-	Value, Values, View,
+	changefeed::ChangeFeed, statements::UpdateStatement, Base, Duration, Ident, Output,
+	Permissions, Strand, Value, Values, View,
 };
 use crate::sql::{Idiom, Kind, TableType};
 use derive::Store;
@@ -21,7 +21,7 @@ use std::fmt::{self, Display, Write};
 use std::sync::Arc;
 use uuid::Uuid;
 
-#[revisioned(revision = 5)]
+#[revisioned(revision = 7)]
 #[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -56,6 +56,13 @@ pub struct DefineTableStatement {
 	/// The last time that a LIVE query was added to this table
 	#[revision(start = 5)]
 	pub cache_lives_ts: Uuid,
+	/// The duration after which records in this table expire, measured
+	/// from the last time that each record was created or updated
+	#[revision(start = 6)]
+	pub expire: Option<Duration>,
+	/// A condition which every record in this table must satisfy
+	#[revision(start = 7)]
+	pub assert: Option<Value>,
 }
 
 impl DefineTableStatement {
@@ -158,7 +165,11 @@ impl DefineTableStatement {
 	}
 	/// Checks if this table allows normal records / documents
 	pub fn allows_normal(&self) -> bool {
-		matches!(self.kind, TableType::Normal | TableType::Any)
+		matches!(self.kind, TableType::Normal | TableType::TimeSeries(_) | TableType::Any)
+	}
+	/// Checks if this is a TYPE TIMESERIES table
+	pub fn is_timeseries(&self) -> bool {
+		matches!(self.kind, TableType::TimeSeries(_))
 	}
 	/// Used to add relational fields to existing table records
 	pub async fn add_in_out_fields(
@@ -242,6 +253,9 @@ impl Display for DefineTableStatement {
 					write!(f, " ENFORCED")?;
 				}
 			}
+			TableType::TimeSeries(ts) => {
+				write!(f, " TIMESERIES TIMESTAMP {}", ts.timestamp)?;
+			}
 			TableType::Any => {
 				f.write_str(" ANY")?;
 			}
@@ -263,6 +277,12 @@ impl Display for DefineTableStatement {
 		if let Some(ref v) = self.changefeed {
 			write!(f, " {v}")?;
 		}
+		if let Some(ref v) = self.expire {
+			write!(f, " EXPIRE {v}")?;
+		}
+		if let Some(ref v) = self.assert {
+			write!(f, " ASSERT {v}")?;
+		}
 		let _indent = if is_pretty() {
 			Some(pretty_indent())
 		} else {
@@ -283,6 +303,8 @@ impl InfoStructure for DefineTableStatement {
 			"kind".to_string() => self.kind.structure(),
 			"view".to_string(), if let Some(v) = self.view => v.structure(),
 			"changefeed".to_string(), if let Some(v) = self.changefeed => v.structure(),
+			"expire".to_string(), if let Some(v) = self.expire => v.into(),
+			"assert".to_string(), if let Some(v) = self.assert => v.structure(),
 			"permissions".to_string() => self.permissions.structure(),
 			"comment".to_string(), if let Some(v) = self.comment => v.into(),
 		})
@@ -4,8 +4,9 @@ use crate::doc::CursorDoc;
 use crate::err::Error;
 use crate::iam::{Action, ResourceKind};
 use crate::sql::statements::info::InfoStructure;
+use crate::sql::value::TryAdd;
 use crate::sql::{
-	escape::quote_str, fmt::Fmt, user::UserDuration, Base, Duration, Ident, Strand, Value,
+	escape::quote_str, fmt::Fmt, user::UserDuration, Base, Datetime, Duration, Ident, Strand, Value,
 };
 use argon2::{
 	password_hash::{PasswordHasher, SaltString},
@@ -17,7 +18,7 @@ use revision::revisioned;
 use serde::{Deserialize, Serialize};
 use std::fmt::{self, Display};
 
-#[revisioned(revision = 4)]
+#[revisioned(revision = 5)]
 #[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -34,6 +35,9 @@ pub struct DefineUserStatement {
 	pub if_not_exists: bool,
 	#[revision(start = 4)]
 	pub overwrite: bool,
+	/// When the password of the user was last changed
+	#[revision(start = 5)]
+	pub changed: Option<Datetime>,
 }
 
 impl From<(Base, &str, &str, &str)> for DefineUserStatement {
@@ -55,6 +59,7 @@ impl From<(Base, &str, &str, &str)> for DefineUserStatement {
 			comment: None,
 			if_not_exists: false,
 			overwrite: false,
+			changed: Some(Datetime::default()),
 		}
 	}
 }
@@ -99,6 +104,22 @@ impl DefineUserStatement {
 		self.duration.session = duration;
 	}
 
+	pub(crate) fn set_password_duration(&mut self, duration: Option<Duration>) {
+		self.duration.password = duration;
+	}
+
+	/// Check if the password of the user has expired
+	pub(crate) fn is_password_expired(&self) -> bool {
+		match (self.duration.password, &self.changed) {
+			// A password expiry which is out of range never expires
+			(Some(duration), Some(changed)) => match duration.try_add(changed.clone()) {
+				Ok(expiry) => expiry < Datetime::default(),
+				Err(_) => false,
+			},
+			_ => false,
+		}
+	}
+
 	/// Process this type returning a computed simple Value
 	pub(crate) async fn compute(
 		&self,
@@ -131,6 +152,8 @@ impl DefineUserStatement {
 						// Don't persist the `IF NOT EXISTS` clause to schema
 						if_not_exists: false,
 						overwrite: false,
+						// The password is set when the user is defined
+						changed: Some(Datetime::default()),
 						..self.clone()
 					},
 					None,
@@ -164,6 +187,8 @@ impl DefineUserStatement {
 						// Don't persist the `IF NOT EXISTS` clause to schema
 						if_not_exists: false,
 						overwrite: false,
+						// The password is set when the user is defined
+						changed: Some(Datetime::default()),
 						..self.clone()
 					},
 					None,
@@ -199,6 +224,8 @@ impl DefineUserStatement {
 						// Don't persist the `IF NOT EXISTS` clause to schema
 						if_not_exists: false,
 						overwrite: false,
+						// The password is set when the user is defined
+						changed: Some(Datetime::default()),
 						..self.clone()
 					},
 					None,
@@ -254,6 +281,9 @@ impl Display for DefineUserStatement {
 				None => "NONE".to_string(),
 			}
 		)?;
+		if let Some(dur) = self.duration.password {
+			write!(f, ", FOR PASSWORD {dur}")?
+		}
 		if let Some(ref v) = self.comment {
 			write!(f, " COMMENT {v}")?
 		}
@@ -271,6 +301,7 @@ impl InfoStructure for DefineUserStatement {
 			"duration".to_string() => Value::from(map! {
 				"token".to_string() => self.duration.token.into(),
 				"session".to_string() => self.duration.session.into(),
+				"password".to_string(), if let Some(v) = self.duration.password => v.into(),
 			}),
 			"comment".to_string(), if let Some(v) = self.comment => v.into(),
 		})
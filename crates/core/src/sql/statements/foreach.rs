@@ -104,6 +104,9 @@ impl ForeachStatement {
 					Entry::Alter(v) => v.compute(stk, &ctx, opt, doc).await,
 					Entry::Rebuild(v) => v.compute(stk, &ctx, opt, doc).await,
 					Entry::Remove(v) => v.compute(&ctx, opt, doc).await,
+					Entry::Savepoint(v) => v.compute(&ctx, opt, doc).await,
+					Entry::Rollback(v) => v.compute(&ctx, opt, doc).await,
+					Entry::Release(v) => v.compute(&ctx, opt, doc).await,
 					Entry::Output(v) => {
 						return stk.run(|stk| v.compute(stk, &ctx, opt, doc)).await;
 					}
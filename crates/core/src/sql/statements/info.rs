@@ -1,5 +1,6 @@
+use crate::cnf::{EXPORT_BATCH_SIZE, INFO_STATISTICS_SCAN_LIMIT};
 use crate::ctx::Context;
-use crate::dbs::Options;
+use crate::dbs::{quota, Options};
 use crate::doc::CursorDoc;
 use crate::err::Error;
 use crate::iam::Action;
@@ -13,7 +14,7 @@ use std::fmt;
 use std::sync::Arc;
 use std::thread::available_parallelism;
 
-#[revisioned(revision = 5)]
+#[revisioned(revision = 7)]
 #[derive(Clone, Debug, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -38,6 +39,12 @@ pub enum InfoStatement {
 	#[revision(start = 3)]
 	#[revision(override(revision = 3, discriminant = 10))]
 	Index(Ident, Ident, bool),
+
+	#[revision(start = 6)]
+	SlowQueries(bool),
+
+	#[revision(start = 7)]
+	Connections(bool),
 }
 
 impl InfoStatement {
@@ -106,12 +113,21 @@ impl InfoStatement {
 				let ns = opt.ns()?;
 				// Get the transaction
 				let txn = ctx.tx();
+				// Get the usage of the namespace, if it is limited
+				let usage = match txn.get_ns(ns).await {
+					Ok(v) if !v.limits.is_empty() => Some(Value::from(map! {
+						"queries".to_string() => ctx.get_namespaces().map(|n| n.active(ns)).unwrap_or_default().into(),
+						"live".to_string() => quota::live_queries(&txn, ns).await?.into(),
+					})),
+					_ => None,
+				};
 				// Create the result set
 				Ok(match structured {
 					true => Value::from(map! {
 						"accesses".to_string() => process(txn.all_ns_accesses(ns).await?.iter().map(|v| v.redacted()).collect()),
 						"databases".to_string() => process(txn.all_db(ns).await?),
 						"users".to_string() => process(txn.all_ns_users(ns).await?),
+						"usage".to_string(), if let Some(v) = usage => v,
 					}),
 					false => Value::from(map! {
 						"accesses".to_string() => {
@@ -135,6 +151,7 @@ impl InfoStatement {
 							}
 							out.into()
 						},
+						"usage".to_string(), if let Some(v) = usage => v,
 					}),
 				})
 			}
@@ -151,6 +168,9 @@ impl InfoStatement {
 				};
 				// Get the transaction
 				let txn = ctx.tx();
+				// Get the progress of moving the keys of a renamed database
+				let renamed = txn.get(crate::key::database::mv::new(ns, db), None).await?;
+				let renamed = renamed.map(|v| Value::from(&v));
 				// Create the result set
 				Ok(match structured {
 					true => Value::from(map! {
@@ -159,6 +179,8 @@ impl InfoStatement {
 						"functions".to_string() => process(txn.all_db_functions(ns, db).await?),
 						"models".to_string() => process(txn.all_db_models(ns, db).await?),
 						"params".to_string() => process(txn.all_db_params(ns, db).await?),
+						"renamed".to_string(), if let Some(v) = renamed => v,
+						"sequences".to_string() => process(txn.all_db_sequences(ns, db).await?),
 						"tables".to_string() => process(txn.all_tb(ns, db, version).await?),
 						"users".to_string() => process(txn.all_db_users(ns, db).await?),
 						"configs".to_string() => process(txn.all_db_configs(ns, db).await?),
@@ -199,6 +221,14 @@ impl InfoStatement {
 							}
 							out.into()
 						},
+						"renamed".to_string(), if let Some(v) = renamed => v,
+						"sequences".to_string() => {
+							let mut out = Object::default();
+							for v in txn.all_db_sequences(ns, db).await?.iter() {
+								out.insert(v.name.to_raw(), v.to_string().into());
+							}
+							out.into()
+						},
 						"tables".to_string() => {
 							let mut out = Object::default();
 							for v in txn.all_tb(ns, db, version).await?.iter() {
@@ -236,16 +266,51 @@ impl InfoStatement {
 				};
 				// Get the transaction
 				let txn = ctx.tx();
+				// Get the progress of any field migrations
+				let migrations = {
+					let beg = crate::key::table::fm::prefix(ns, db, tb);
+					let end = crate::key::table::fm::suffix(ns, db, tb);
+					let mut out = Object::default();
+					for (k, v) in txn.getr(beg..end, None).await? {
+						let fm = crate::key::table::fm::Fm::decode(&k)?;
+						out.insert(fm.fd.to_owned(), v.into());
+					}
+					(!out.is_empty()).then(|| Value::from(out))
+				};
+				// Get the progress of moving the records of a renamed table
+				let renamed = txn.get(crate::key::table::mv::new(ns, db, tb), None).await?;
+				let renamed = renamed.map(|v| Value::from(&v));
+				// Get the progress of any concurrent index builds
+				#[cfg(not(target_arch = "wasm32"))]
+				let building = match ctx.get_index_builder() {
+					Some(ib) => {
+						let mut out = Object::default();
+						for ix in txn.all_tb_indexes(ns, db, tb).await?.iter() {
+							if let Some(status) = ib.get_status(ix).await {
+								out.insert(ix.name.to_raw(), status.into());
+							}
+						}
+						(!out.is_empty()).then(|| out.into())
+					}
+					None => None,
+				};
+				#[cfg(target_arch = "wasm32")]
+				let building: Option<Value> = None;
 				// Create the result set
 				Ok(match structured {
 					true => Value::from(map! {
+						"building".to_string(), if let Some(v) = building => v,
 						"events".to_string() => process(txn.all_tb_events(ns, db, tb).await?),
 						"fields".to_string() => process(txn.all_tb_fields(ns, db, tb, version).await?),
 						"indexes".to_string() => process(txn.all_tb_indexes(ns, db, tb).await?),
 						"lives".to_string() => process(txn.all_tb_lives(ns, db, tb).await?),
+						"migrations".to_string(), if let Some(v) = migrations => v,
+						"renamed".to_string(), if let Some(v) = renamed => v,
+						"statistics".to_string() => Statistics::scan(ctx, ns, db, tb).await?.structure(),
 						"tables".to_string() => process(txn.all_tb_views(ns, db, tb).await?),
 					}),
 					false => Value::from(map! {
+						"building".to_string(), if let Some(v) = building => v,
 						"events".to_string() => {
 							let mut out = Object::default();
 							for v in txn.all_tb_events(ns, db, tb).await?.iter() {
@@ -274,6 +339,8 @@ impl InfoStatement {
 							}
 							out.into()
 						},
+						"migrations".to_string(), if let Some(v) = migrations => v,
+						"renamed".to_string(), if let Some(v) = renamed => v,
 						"tables".to_string() => {
 							let mut out = Object::default();
 							for v in txn.all_tb_views(ns, db, tb).await?.iter() {
@@ -304,24 +371,44 @@ impl InfoStatement {
 					false => Value::from(res.to_string()),
 				})
 			}
-			#[allow(unused_variables)]
-			InfoStatement::Index(index, table, _structured) => {
+			InfoStatement::Index(index, table, structured) => {
 				// Allowed to run?
 				opt.is_allowed(Action::View, ResourceKind::Actor, &Base::Db)?;
 				// Get the transaction
 				let txn = ctx.tx();
-				// Output
+				// Obtain the index
+				let res = txn.get_tb_index(opt.ns()?, opt.db()?, table, index).await?;
+				// Create the result set
+				let mut out = Object::default();
+				if *structured {
+					out.insert("definition".to_string(), res.as_ref().clone().structure());
+				}
+				// Get the progress of any concurrent index build
 				#[cfg(not(target_arch = "wasm32"))]
 				if let Some(ib) = ctx.get_index_builder() {
-					// Obtain the index
-					let res = txn.get_tb_index(opt.ns()?, opt.db()?, table, index).await?;
 					if let Some(status) = ib.get_status(&res).await {
-						let mut out = Object::default();
 						out.insert("building".to_string(), status.into());
-						return Ok(out.into());
 					}
 				}
-				Ok(Object::default().into())
+				Ok(out.into())
+			}
+			InfoStatement::SlowQueries(_) => {
+				// Allowed to run?
+				opt.is_allowed(Action::View, ResourceKind::Any, &Base::Root)?;
+				// Output the logged slow queries
+				Ok(match ctx.get_slow_log() {
+					Some(v) => v.log().entries(),
+					None => Value::Array(Default::default()),
+				})
+			}
+			InfoStatement::Connections(_) => {
+				// Allowed to run?
+				opt.is_allowed(Action::View, ResourceKind::Any, &Base::Root)?;
+				// Output the active connections
+				Ok(match ctx.get_connections() {
+					Some(v) => v.list(),
+					None => Value::Array(Default::default()),
+				})
 			}
 		}
 	}
@@ -361,6 +448,10 @@ impl fmt::Display for InfoStatement {
 			},
 			Self::Index(ref i, ref t, false) => write!(f, "INFO FOR INDEX {i} ON {t}"),
 			Self::Index(ref i, ref t, true) => write!(f, "INFO FOR INDEX {i} ON {t} STRUCTURE"),
+			Self::SlowQueries(false) => f.write_str("INFO FOR SLOW QUERIES"),
+			Self::SlowQueries(true) => f.write_str("INFO FOR SLOW QUERIES STRUCTURE"),
+			Self::Connections(false) => f.write_str("INFO FOR CONNECTIONS"),
+			Self::Connections(true) => f.write_str("INFO FOR CONNECTIONS STRUCTURE"),
 		}
 	}
 }
@@ -378,6 +469,8 @@ impl InfoStatement {
 			InfoStatement::Tb(t, _, v) => InfoStatement::Tb(t, true, v),
 			InfoStatement::User(u, b, _) => InfoStatement::User(u, b, true),
 			InfoStatement::Index(i, t, _) => InfoStatement::Index(i, t, true),
+			InfoStatement::SlowQueries(_) => InfoStatement::SlowQueries(true),
+			InfoStatement::Connections(_) => InfoStatement::Connections(true),
 		}
 	}
 
@@ -426,3 +519,54 @@ impl InfoStructure for System {
 		self.into()
 	}
 }
+
+/// The number and the size of the records in a table. Tables with more
+/// records than the scan limit only report the scanned records, and are
+/// marked as not exact.
+struct Statistics {
+	records: usize,
+	size: usize,
+	exact: bool,
+}
+
+impl Statistics {
+	async fn scan(ctx: &Context, ns: &str, db: &str, tb: &str) -> Result<Self, Error> {
+		let txn = ctx.tx();
+		let beg = crate::key::thing::prefix(ns, db, tb);
+		let end = crate::key::thing::suffix(ns, db, tb);
+		let mut next = Some(beg..end);
+		let mut records = 0;
+		let mut size = 0;
+		while let Some(rng) = next {
+			// Stop scanning once the scan limit is reached
+			if records >= *INFO_STATISTICS_SCAN_LIMIT || ctx.is_done() {
+				return Ok(Self {
+					records,
+					size,
+					exact: false,
+				});
+			}
+			let batch = txn.batch(rng, *EXPORT_BATCH_SIZE, true, None).await?;
+			next = batch.next;
+			for (k, v) in batch.values.iter() {
+				records += 1;
+				size += k.len() + v.len();
+			}
+		}
+		Ok(Self {
+			records,
+			size,
+			exact: true,
+		})
+	}
+}
+
+impl InfoStructure for Statistics {
+	fn structure(self) -> Value {
+		Value::from(map! {
+			"exact".to_string() => self.exact.into(),
+			"records".to_string() => self.records.into(),
+			"size".to_string() => self.size.into(),
+		})
+	}
+}
@@ -4,7 +4,7 @@ use crate::doc::CursorDoc;
 use crate::err::Error;
 use crate::sql::paths::IN;
 use crate::sql::paths::OUT;
-use crate::sql::{Data, Id, Output, Table, Thing, Timeout, Value, Version};
+use crate::sql::{Data, Id, Output, Table, Thing, TimeSeries, Timeout, Value, Version};
 use derive::Store;
 use reblessive::tree::Stk;
 use revision::revisioned;
@@ -74,6 +74,11 @@ impl InsertStatement {
 				}
 			},
 		};
+		// Time series tables generate ids from the record timestamp
+		let timeseries = match &into {
+			Some(into) => TimeSeries::fetch(&ctx, opt, into).await?,
+			None => None,
+		};
 		// Parse the data expression
 		match &self.data {
 			// Check if this is a traditional statement
@@ -87,7 +92,7 @@ impl InsertStatement {
 						o.set(stk, &ctx, opt, k, v).await?;
 					}
 					// Specify the new table record id
-					let id = gen_id(&o, &into)?;
+					let id = gen_id(&ctx, &o, &into, &timeseries)?;
 					// Pass the value to the iterator
 					i.ingest(iterable(id, o, self.relation)?)
 				}
@@ -99,14 +104,14 @@ impl InsertStatement {
 					Value::Array(v) => {
 						for v in v {
 							// Specify the new table record id
-							let id = gen_id(&v, &into)?;
+							let id = gen_id(&ctx, &v, &into, &timeseries)?;
 							// Pass the value to the iterator
 							i.ingest(iterable(id, v, self.relation)?)
 						}
 					}
 					Value::Object(_) => {
 						// Specify the new table record id
-						let id = gen_id(&v, &into)?;
+						let id = gen_id(&ctx, &v, &into, &timeseries)?;
 						// Pass the value to the iterator
 						i.ingest(iterable(id, v, self.relation)?)
 					}
@@ -189,9 +194,17 @@ fn iterable(id: Thing, v: Value, relation: bool) -> Result<Iterable, Error> {
 	}
 }
 
-fn gen_id(v: &Value, into: &Option<Table>) -> Result<Thing, Error> {
+fn gen_id(
+	ctx: &Context,
+	v: &Value,
+	into: &Option<Table>,
+	ts: &Option<TimeSeries>,
+) -> Result<Thing, Error> {
 	match into {
-		Some(into) => v.rid().generate(into, true),
+		Some(into) => match (v.rid(), ts) {
+			(Value::None, Some(ts)) => ts.generate(ctx, into, Some(v.pick(&ts.timestamp))),
+			(id, _) => id.generate(ctx, into, true),
+		},
 		None => match v.rid() {
 			Value::Thing(v) => match v {
 				Thing {
@@ -2,16 +2,17 @@ use crate::ctx::Context;
 use crate::dbs::Options;
 use crate::doc::CursorDoc;
 use crate::err::Error;
+use crate::iam::{Action, ResourceKind};
 use crate::kvs::Live;
 use crate::sql::statements::define::DefineTableStatement;
-use crate::sql::Value;
+use crate::sql::{Base, Value};
 use derive::Store;
 use reblessive::tree::Stk;
 use revision::revisioned;
 use serde::{Deserialize, Serialize};
 use std::fmt;
 
-#[revisioned(revision = 1)]
+#[revisioned(revision = 2)]
 #[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -19,6 +20,10 @@ pub struct KillStatement {
 	// Uuid of Live Query
 	// or Param resolving to Uuid of Live Query
 	pub id: Value,
+	// Whether this kills a client connection
+	// rather than a live query
+	#[revision(start = 2)]
+	pub connection: bool,
 }
 
 impl KillStatement {
@@ -30,6 +35,10 @@ impl KillStatement {
 		opt: &Options,
 		_doc: Option<&CursorDoc>,
 	) -> Result<Value, Error> {
+		// Is this killing a client connection?
+		if self.connection {
+			return self.kill_connection(stk, ctx, opt).await;
+		}
 		// Is realtime enabled?
 		opt.realtime()?;
 		// Valid options?
@@ -86,10 +95,42 @@ impl KillStatement {
 		// Return the query id
 		Ok(Value::None)
 	}
+
+	/// Cancel the running queries of a client connection, and close it
+	async fn kill_connection(
+		&self,
+		stk: &mut Stk,
+		ctx: &Context,
+		opt: &Options,
+	) -> Result<Value, Error> {
+		// Allowed to run?
+		opt.is_allowed(Action::Edit, ResourceKind::Any, &Base::Root)?;
+		// Resolve the connection id
+		let id = match self.id.compute(stk, ctx, opt, None).await? {
+			Value::Uuid(v) => v.to_raw(),
+			Value::Strand(v) => v.0,
+			_ => {
+				return Err(Error::KillStatement {
+					value: self.id.to_string(),
+				})
+			}
+		};
+		// Kill the connection if it exists
+		match ctx.get_connections() {
+			Some(v) if v.kill(&id) => Ok(Value::None),
+			_ => Err(Error::KillStatement {
+				value: self.id.to_string(),
+			}),
+		}
+	}
 }
 
 impl fmt::Display for KillStatement {
 	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
-		write!(f, "KILL {}", self.id)
+		if self.connection {
+			write!(f, "KILL CONNECTION {}", self.id)
+		} else {
+			write!(f, "KILL {}", self.id)
+		}
 	}
 }
@@ -1,5 +1,5 @@
 use crate::ctx::Context;
-use crate::dbs::Options;
+use crate::dbs::{quota, Options};
 use crate::doc::CursorDoc;
 use crate::err::Error;
 use crate::iam::Auth;
@@ -122,6 +122,15 @@ impl LiveStatement {
 				let txn = ctx.tx();
 				// Ensure that the table definition exists
 				txn.ensure_ns_db_tb(ns, db, &tb, opt.strict).await?;
+				// Check the live query limit of the namespace
+				if let Some(limit) = txn.get_ns(ns).await?.limits.live {
+					if quota::live_queries(&txn, ns).await? >= limit as usize {
+						return Err(Error::QuotaExceeded {
+							scope: ns.to_owned(),
+							message: format!("no more than {limit} live queries can be registered"),
+						});
+					}
+				}
 				// Insert the node live query
 				let key = crate::key::node::lq::new(nid, id);
 				txn.replace(key, lq).await?;
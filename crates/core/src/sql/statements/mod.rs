@@ -19,7 +19,10 @@ pub(crate) mod option;
 pub(crate) mod output;
 pub(crate) mod rebuild;
 pub(crate) mod relate;
+pub(crate) mod release;
 pub(crate) mod remove;
+pub(crate) mod rollback;
+pub(crate) mod savepoint;
 pub(crate) mod select;
 pub(crate) mod set;
 pub(crate) mod show;
@@ -49,6 +52,9 @@ pub use self::r#break::BreakStatement;
 pub use self::r#continue::ContinueStatement;
 pub use self::r#use::UseStatement;
 pub use self::relate::RelateStatement;
+pub use self::release::ReleaseStatement;
+pub use self::rollback::RollbackStatement;
+pub use self::savepoint::SavepointStatement;
 pub use self::select::SelectStatement;
 pub use self::set::SetStatement;
 pub use self::show::ShowStatement;
@@ -57,18 +63,21 @@ pub use self::throw::ThrowStatement;
 pub use self::update::UpdateStatement;
 pub use self::upsert::UpsertStatement;
 
-pub use self::alter::{AlterStatement, AlterTableStatement};
+pub use self::alter::{
+	AlterDatabaseStatement, AlterFieldStatement, AlterStatement, AlterTableStatement,
+	AlterUserStatement,
+};
 
 pub use self::define::{
 	DefineAccessStatement, DefineAnalyzerStatement, DefineDatabaseStatement, DefineEventStatement,
 	DefineFieldStatement, DefineFunctionStatement, DefineIndexStatement, DefineModelStatement,
-	DefineNamespaceStatement, DefineParamStatement, DefineStatement, DefineTableStatement,
-	DefineUserStatement,
+	DefineNamespaceStatement, DefineParamStatement, DefineSequenceStatement, DefineStatement,
+	DefineTableStatement, DefineUserStatement, NamespaceLimits,
 };
 
 pub use self::remove::{
 	RemoveAccessStatement, RemoveAnalyzerStatement, RemoveDatabaseStatement, RemoveEventStatement,
 	RemoveFieldStatement, RemoveFunctionStatement, RemoveIndexStatement, RemoveModelStatement,
-	RemoveNamespaceStatement, RemoveParamStatement, RemoveStatement, RemoveTableStatement,
-	RemoveUserStatement,
+	RemoveNamespaceStatement, RemoveParamStatement, RemoveSequenceStatement, RemoveStatement,
+	RemoveTableStatement, RemoveUserStatement,
 };
@@ -150,13 +150,13 @@ impl RelateStatement {
 						// There is a data clause so check for a record id
 						Some(data) => {
 							let id = match data.rid(stk, &ctx, opt).await? {
-								Some(id) => id.generate(tb, false)?,
-								None => tb.generate(),
+								Some(id) => id.generate(&ctx, tb, false)?,
+								None => tb.generate(&ctx),
 							};
 							i.ingest(Iterable::Relatable(f, id, w, None))
 						}
 						// There is no data clause so create a record id
-						None => i.ingest(Iterable::Relatable(f, tb.generate(), w, None)),
+						None => i.ingest(Iterable::Relatable(f, tb.generate(&ctx), w, None)),
 					},
 					// The relation can not be any other type
 					v => {
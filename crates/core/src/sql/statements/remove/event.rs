@@ -34,6 +34,11 @@ impl RemoveEventStatement {
 			// Delete the definition
 			let key = crate::key::table::ev::new(opt.ns()?, opt.db()?, &ev.what, &ev.name);
 			txn.del(key).await?;
+			// Delete the schedule state
+			if ev.schedule.is_some() {
+				let key = crate::key::table::es::new(opt.ns()?, opt.db()?, &ev.what, &ev.name);
+				txn.del(key).await?;
+			}
 			// Refresh the table cache for events
 			let key = crate::key::database::tb::new(opt.ns()?, opt.db()?, &self.what);
 			let tb = txn.get_tb(opt.ns()?, opt.db()?, &self.what).await?;
@@ -36,6 +36,9 @@ impl RemoveFieldStatement {
 			// Delete the definition
 			let key = crate::key::table::fd::new(opt.ns()?, opt.db()?, &fd.what, &na);
 			txn.del(key).await?;
+			// Delete the status of any migration of the field
+			let key = crate::key::table::fm::new(opt.ns()?, opt.db()?, &fd.what, &na);
+			txn.del(key).await?;
 			// Refresh the table cache for fields
 			let key = crate::key::database::tb::new(opt.ns()?, opt.db()?, &self.what);
 			let tb = txn.get_tb(opt.ns()?, opt.db()?, &self.what).await?;
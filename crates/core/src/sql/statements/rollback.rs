@@ -0,0 +1,41 @@
+use crate::ctx::Context;
+use crate::dbs::Options;
+use crate::doc::CursorDoc;
+use crate::err::Error;
+use crate::sql::ident::Ident;
+use crate::sql::value::Value;
+use derive::Store;
+use revision::revisioned;
+use serde::{Deserialize, Serialize};
+use std::fmt;
+
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
+#[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
+#[non_exhaustive]
+pub struct RollbackStatement {
+	pub name: Ident,
+}
+
+impl RollbackStatement {
+	/// Process this type returning a computed simple Value
+	pub(crate) async fn compute(
+		&self,
+		ctx: &Context,
+		_opt: &Options,
+		_doc: Option<&CursorDoc>,
+	) -> Result<Value, Error> {
+		// Revert the transaction to the savepoint
+		ctx.tx().lock().await.rollback_to_user_save_point(&self.name).await?;
+		// Any cached definitions may have been reverted
+		ctx.tx().clear();
+		// Ok all good
+		Ok(Value::None)
+	}
+}
+
+impl fmt::Display for RollbackStatement {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		write!(f, "ROLLBACK TO SAVEPOINT {}", self.name)
+	}
+}
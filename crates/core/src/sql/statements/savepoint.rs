@@ -0,0 +1,39 @@
+use crate::ctx::Context;
+use crate::dbs::Options;
+use crate::doc::CursorDoc;
+use crate::err::Error;
+use crate::sql::ident::Ident;
+use crate::sql::value::Value;
+use derive::Store;
+use revision::revisioned;
+use serde::{Deserialize, Serialize};
+use std::fmt;
+
+#[revisioned(revision = 1)]
+#[derive(Clone, Debug, Default, Eq, PartialEq, PartialOrd, Serialize, Deserialize, Store, Hash)]
+#[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
+#[non_exhaustive]
+pub struct SavepointStatement {
+	pub name: Ident,
+}
+
+impl SavepointStatement {
+	/// Process this type returning a computed simple Value
+	pub(crate) async fn compute(
+		&self,
+		ctx: &Context,
+		_opt: &Options,
+		_doc: Option<&CursorDoc>,
+	) -> Result<Value, Error> {
+		// Create the savepoint on the transaction
+		ctx.tx().lock().await.new_user_save_point(&self.name);
+		// Ok all good
+		Ok(Value::None)
+	}
+}
+
+impl fmt::Display for SavepointStatement {
+	fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+		write!(f, "SAVEPOINT {}", self.name)
+	}
+}
@@ -3,6 +3,7 @@ use crate::dbs::{Iterable, Iterator, Options, Statement};
 use crate::doc::CursorDoc;
 use crate::err::Error;
 use crate::idx::planner::{QueryPlanner, StatementContext};
+use crate::sql::id::range::IdRange;
 use crate::sql::{
 	order::{OldOrders, Order, OrderList, Ordering},
 	Cond, Explain, Fetchs, Field, Fields, Groups, Idioms, Limit, Splits, Start, Timeout, Value,
@@ -13,6 +14,7 @@ use reblessive::tree::Stk;
 use revision::revisioned;
 use serde::{Deserialize, Serialize};
 use std::fmt;
+use std::ops::Bound;
 use std::sync::Arc;
 
 #[revisioned(revision = 4)]
@@ -139,6 +141,39 @@ impl SelectStatement {
 		let mut stm_ctx = StatementContext::new(&ctx, &opt, &stm)?;
 		// Loop over the select targets
 		for w in self.what.0.iter() {
+			// Select from a table in another namespace and database
+			if let Value::Qualified(q) = w {
+				if self.only && !limit_is_one_or_zero {
+					return Err(Error::SingleOnlyOutput);
+				}
+				// Iterate with the options of the namespace and database
+				let qopt = q.options(&opt)?;
+				let mut qi = Iterator::new();
+				// The query executors are found by table name, so a table
+				// which is also selected from this database is scanned as
+				// a range, which does not use the query executor
+				let shared = self
+					.what
+					.iter()
+					.filter(|v| match v {
+						Value::Table(t) => t.0 == q.what.0,
+						Value::Qualified(v) => v.what.0 == q.what.0,
+						_ => false,
+					})
+					.count() > 1;
+				if shared {
+					let rng = IdRange {
+						beg: Bound::Unbounded,
+						end: Bound::Unbounded,
+					};
+					qi.ingest(Iterable::Range(q.what.0.clone(), rng, false));
+				} else {
+					let qctx = StatementContext::new(&ctx, &qopt, &stm)?;
+					planner.add_iterables(stk, &qctx, q.what.clone(), &mut qi).await?;
+				}
+				i.ingest_qualified(&qopt, qi);
+				continue;
+			}
 			let v = w.compute(stk, &ctx, &opt, doc).await?;
 			match v {
 				Value::Thing(v) => match v.is_range() {
@@ -26,6 +26,8 @@ impl SleepStatement {
 	) -> Result<Value, Error> {
 		// Allowed to run?
 		opt.is_allowed(Action::Edit, ResourceKind::Table, &Base::Root)?;
+		// The delay can not be undone if the statement is retried
+		ctx.add_side_effect();
 		// Calculate the sleep duration
 		let dur = match (ctx.timeout(), self.duration.0) {
 			(Some(t), d) if t < d => t,
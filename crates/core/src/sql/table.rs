@@ -1,3 +1,5 @@
+use crate::ctx::Context;
+use crate::sql::id::Gen;
 use crate::sql::{escape::escape_ident, fmt::Fmt, strand::no_nul_bytes, Id, Ident, Thing};
 use revision::revisioned;
 use serde::{Deserialize, Serialize};
@@ -65,10 +67,10 @@ impl Deref for Table {
 }
 
 impl Table {
-	pub fn generate(&self) -> Thing {
+	pub fn generate(&self, ctx: &Context) -> Thing {
 		Thing {
 			tb: self.0.to_owned(),
-			id: Id::rand(),
+			id: Id::generate(ctx, &Gen::Rand),
 		}
 	}
 }
@@ -1,12 +1,16 @@
+use crate::ctx::Context;
+use crate::dbs::Options;
+use crate::err::Error;
 use crate::sql::statements::info::InfoStructure;
-use crate::sql::{Kind, Value};
+use crate::sql::{Array, Datetime, Id, Idiom, Kind, Table, Thing, Value};
 use revision::revisioned;
 use serde::{Deserialize, Serialize};
 use std::fmt;
 use std::fmt::Display;
+use ulid::Ulid;
 
 /// The type of records stored by a table
-#[revisioned(revision = 1)]
+#[revisioned(revision = 2)]
 #[derive(Debug, Default, Serialize, Deserialize, Hash, Clone, Eq, PartialEq, PartialOrd)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 #[non_exhaustive]
@@ -15,6 +19,8 @@ pub enum TableType {
 	Any,
 	Normal,
 	Relation(Relation),
+	#[revision(start = 2)]
+	TimeSeries(TimeSeries),
 }
 
 impl Display for TableType {
@@ -35,6 +41,9 @@ impl Display for TableType {
 					write!(f, " ENFORCED")?;
 				}
 			}
+			TableType::TimeSeries(ts) => {
+				write!(f, " TIMESERIES TIMESTAMP {}", ts.timestamp)?;
+			}
 			TableType::Any => {
 				f.write_str(" ANY")?;
 			}
@@ -60,6 +69,10 @@ impl InfoStructure for TableType {
 					tables.into_iter().map(|t| t.0).collect::<Vec<_>>().into(),
 				"enforced".to_string() => rel.enforced.into()
 			}),
+			TableType::TimeSeries(ts) => Value::from(map! {
+				"kind".to_string() => "TIMESERIES".into(),
+				"timestamp".to_string() => ts.timestamp.to_string().into(),
+			}),
 		}
 	}
 }
@@ -74,3 +87,75 @@ pub struct Relation {
 	#[revision(start = 2)]
 	pub enforced: bool,
 }
+
+/// A time series table, whose record ids start with the
+/// timestamp of each record, so that records are ordered
+/// by time in storage
+#[revisioned(revision = 1)]
+#[derive(Debug, Default, Serialize, Deserialize, Hash, Clone, Eq, PartialEq, PartialOrd)]
+#[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
+#[non_exhaustive]
+pub struct TimeSeries {
+	/// The field which stores the timestamp of each record
+	pub timestamp: Idiom,
+}
+
+impl TimeSeries {
+	/// Fetches the time series definition of a table, if it is a time series table
+	pub(crate) async fn fetch(
+		ctx: &Context,
+		opt: &Options,
+		tb: &str,
+	) -> Result<Option<Self>, Error> {
+		match ctx.tx().get_tb(opt.ns()?, opt.db()?, tb).await {
+			Ok(tb) => match &tb.kind {
+				TableType::TimeSeries(v) => Ok(Some(v.clone())),
+				_ => Ok(None),
+			},
+			Err(Error::TbNotFound {
+				..
+			}) => Ok(None),
+			Err(e) => Err(e),
+		}
+	}
+	/// Generates the id of a record in this table, from the timestamp of the
+	/// record followed by a random suffix, which keeps the ids of records with
+	/// the same timestamp unique. Records without a timestamp are stored at the
+	/// current time.
+	pub(crate) fn generate(
+		&self,
+		ctx: &Context,
+		tb: &Table,
+		time: Option<Value>,
+	) -> Result<Thing, Error> {
+		let time = match time {
+			None | Some(Value::None) => match ctx.get_generator() {
+				Some(v) => v.now(),
+				None => Datetime::default(),
+			},
+			Some(Value::Datetime(v)) => v,
+			Some(v) => {
+				return Err(Error::TimeSeriesTimestamp {
+					field: self.timestamp.to_string(),
+					value: v.to_string(),
+				})
+			}
+		};
+		let ulid = match ctx.get_generator() {
+			Some(v) => v.ulid(),
+			None => Ulid::new().to_string(),
+		};
+		let id = Array::from(vec![Value::Datetime(time), Value::from(ulid)]);
+		Ok(Thing::from((tb.0.clone(), Id::Array(id))))
+	}
+	/// The timestamp of a record in this table, if its id starts with one
+	pub(crate) fn timestamp(id: &Id) -> Option<&Datetime> {
+		match id {
+			Id::Array(v) => match v.first() {
+				Some(Value::Datetime(v)) => Some(v),
+				_ => None,
+			},
+			_ => None,
+		}
+	}
+}
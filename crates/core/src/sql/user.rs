@@ -3,7 +3,7 @@ use revision::revisioned;
 use serde::{Deserialize, Serialize};
 use std::str;
 
-#[revisioned(revision = 1)]
+#[revisioned(revision = 2)]
 #[derive(Debug, Serialize, Deserialize, Hash, Clone, Eq, PartialEq, PartialOrd)]
 #[cfg_attr(feature = "arbitrary", derive(arbitrary::Arbitrary))]
 // Durations representing the expiration of different elements of user authentication
@@ -13,6 +13,9 @@ pub struct UserDuration {
 	pub token: Option<Duration>,
 	// Duration after which the session authenticated with user credentials or token expires
 	pub session: Option<Duration>,
+	// Duration after which the password of the user expires, and must be changed
+	#[revision(start = 2)]
+	pub password: Option<Duration>,
 }
 
 impl Default for UserDuration {
@@ -22,6 +25,8 @@ impl Default for UserDuration {
 			token: Some(Duration::from_hours(1)),
 			// By default, sessions do not expire
 			session: None,
+			// By default, passwords do not expire
+			password: None,
 		}
 	}
 }
@@ -7,8 +7,10 @@ use crate::sql::part::Next;
 use crate::sql::part::Part;
 use crate::sql::statements::select::SelectStatement;
 use crate::sql::value::{Value, Values};
+use crate::sql::Thing;
 use futures::future::try_join_all;
 use reblessive::tree::Stk;
+use std::collections::{HashMap, HashSet};
 
 impl Value {
 	pub(crate) async fn fetch(
@@ -250,4 +252,155 @@ impl Value {
 			_ => Ok(()),
 		}
 	}
+
+	/// Fetch the record links at the path of every value. The linked records
+	/// are selected with a single statement, instead of one for each link.
+	pub(crate) async fn fetch_all(
+		values: &mut [Value],
+		stk: &mut Stk,
+		ctx: &Context,
+		opt: &Options,
+		path: &[Part],
+	) -> Result<(), Error> {
+		// Collect the record links of the values which can be batched
+		let mut batched = Vec::with_capacity(values.len());
+		let mut things = Vec::new();
+		let mut seen = HashSet::new();
+		for v in values.iter_mut() {
+			let mut links = Vec::new();
+			let ok = v.visit_links(path, &mut |v| {
+				if let Value::Thing(t) = v {
+					links.push(t.clone());
+				}
+			});
+			if ok {
+				for t in links {
+					if seen.insert(t.clone()) {
+						things.push(Value::Thing(t));
+					}
+				}
+			}
+			batched.push(ok);
+		}
+		// Select all of the linked records at once
+		if !things.is_empty() {
+			let stm = SelectStatement {
+				expr: Fields(vec![Field::All], false),
+				what: Values(things),
+				..SelectStatement::default()
+			};
+			let mut records: HashMap<Thing, Value> = HashMap::new();
+			if let Value::Array(a) = stm.compute(stk, ctx, opt, None).await? {
+				for v in a {
+					if let Value::Thing(t) = v.rid() {
+						records.insert(t, v);
+					}
+				}
+			}
+			// Replace the record links with the records
+			for (v, ok) in values.iter_mut().zip(batched.iter()) {
+				if *ok {
+					v.visit_links(path, &mut |v| {
+						if let Value::Thing(t) = v {
+							*v = records.get(t).cloned().unwrap_or_default();
+						}
+					});
+				}
+			}
+		}
+		// Fetch the values which could not be batched
+		for (v, ok) in values.iter_mut().zip(batched.iter()) {
+			if !*ok {
+				stk.run(|stk| v.fetch(stk, ctx, opt, path)).await?;
+			}
+		}
+		Ok(())
+	}
+
+	/// Visit the values which `fetch` would resolve at the path, returning
+	/// false if the path contains parts which need to be computed.
+	fn visit_links(&mut self, path: &[Part], f: &mut impl FnMut(&mut Value)) -> bool {
+		let mut this = self;
+		let mut iter = path.iter();
+		let mut prev = path;
+		while let Some(p) = iter.next() {
+			match p {
+				Part::Field(n) => match this {
+					Value::Object(o) => {
+						let Some(x) = o.get_mut(n.0.as_str()) else {
+							return true;
+						};
+						this = x;
+					}
+					Value::Array(x) => return x.iter_mut().all(|v| v.visit_links(prev, f)),
+					_ => break,
+				},
+				Part::Index(i) => match this {
+					Value::Object(v) => {
+						let Some(x) = v.get_mut(&i.to_string()) else {
+							return true;
+						};
+						this = x;
+					}
+					Value::Array(v) => {
+						let Some(x) = v.get_mut(i.to_usize()) else {
+							return true;
+						};
+						this = x;
+					}
+					_ => break,
+				},
+				Part::All => {
+					let next = iter.as_slice();
+					if next.is_empty() {
+						break;
+					}
+					match this {
+						Value::Object(x) => {
+							return x.iter_mut().all(|(_, v)| v.visit_links(next, f));
+						}
+						Value::Array(x) => return x.iter_mut().all(|v| v.visit_links(next, f)),
+						_ => break,
+					}
+				}
+				Part::First => match this {
+					Value::Array(x) => {
+						let Some(x) = x.first_mut() else {
+							return true;
+						};
+						this = x;
+					}
+					_ => return true,
+				},
+				Part::Last => match this {
+					Value::Array(x) => {
+						let Some(x) = x.last_mut() else {
+							return true;
+						};
+						this = x;
+					}
+					_ => return true,
+				},
+				_ => return false,
+			}
+			prev = iter.as_slice();
+		}
+		match this {
+			Value::Array(v) => v.iter_mut().all(|v| match v {
+				Value::Thing(_) => {
+					f(v);
+					true
+				}
+				Value::Array(_) => v.visit_links(&[], f),
+				// The path is applied again to objects within arrays
+				Value::Object(_) => false,
+				_ => true,
+			}),
+			Value::Thing(_) => {
+				f(this);
+				true
+			}
+			_ => true,
+		}
+	}
 }
@@ -1,11 +1,12 @@
+use crate::ctx::Context;
 use crate::err::Error;
-use crate::sql::id::Id;
+use crate::sql::id::{Gen, Id};
 use crate::sql::table::Table;
 use crate::sql::thing::Thing;
 use crate::sql::value::Value;
 
 impl Value {
-	pub(crate) fn generate(self, tb: &Table, retable: bool) -> Result<Thing, Error> {
+	pub(crate) fn generate(self, ctx: &Context, tb: &Table, retable: bool) -> Result<Thing, Error> {
 		match self {
 			// There is a floating point number for the id field
 			Value::Number(id) if id.is_float() => Ok(Thing {
@@ -40,7 +41,7 @@ impl Value {
 			// There is no record id field
 			Value::None => Ok(Thing {
 				tb: tb.0.to_string(),
-				id: Id::rand(),
+				id: Id::generate(ctx, &Gen::Rand),
 			}),
 			// There is a record id defined
 			Value::Thing(id) => match retable {
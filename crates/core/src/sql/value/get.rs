@@ -11,7 +11,7 @@ use crate::fnc::idiom;
 use crate::sql::edges::Edges;
 use crate::sql::field::{Field, Fields};
 use crate::sql::part::{FindRecursionPlan, Next, NextMethod, SplitByRepeatRecurse};
-use crate::sql::part::{Part, Skip};
+use crate::sql::part::{Part, RecurseInstruction, Skip};
 use crate::sql::paths::ID;
 use crate::sql::statements::select::SelectStatement;
 use crate::sql::value::{Value, Values};
@@ -19,7 +19,7 @@ use crate::sql::Function;
 use futures::future::try_join_all;
 use reblessive::tree::Stk;
 
-use super::idiom_recursion::{compute_idiom_recursion, Recursion};
+use super::idiom_recursion::{compute_idiom_recursion, compute_idiom_recursion_paths, Recursion};
 
 impl Value {
 	/// Asynchronous method for getting a local or remote field from a `Value`
@@ -39,7 +39,7 @@ impl Value {
 		}
 		match path.first() {
 			// The knowledge of the current value is not relevant to Part::Recurse
-			Some(Part::Recurse(recurse, inner_path)) => {
+			Some(Part::Recurse(recurse, inner_path, instruction)) => {
 				// Find the path to recurse and what path to process after the recursion is finished
 				let (path, after) = match inner_path {
 					Some(p) => (p.0.as_slice(), path.next().to_vec()),
@@ -76,7 +76,16 @@ impl Value {
 				};
 
 				// Compute the recursion
-				let v = compute_idiom_recursion(stk, ctx, opt, doc, rec).await?;
+				let v = match instruction {
+					Some(RecurseInstruction::Path) => {
+						// Paths can not be collected through a repeat recurse symbol
+						if rec.plan.is_some() {
+							return Err(Error::UnsupportedRepeatRecurse);
+						}
+						compute_idiom_recursion_paths(stk, ctx, opt, doc, rec).await?
+					}
+					None => compute_idiom_recursion(stk, ctx, opt, doc, rec).await?,
+				};
 
 				// If we have a leftover path, process it
 				if !after.is_empty() {
@@ -154,3 +154,83 @@ pub(crate) async fn compute_idiom_recursion(
 		}
 	}
 }
+
+// Method used to compute a recursed idiom path, which
+// outputs every traversed path instead of the final
+// values. The paths are traversed breadth-first, and a
+// path ends when it yields no further values, or when it
+// only yields values which it has already visited, which
+// ensures that any cycles in the graph are not followed.
+pub(crate) async fn compute_idiom_recursion_paths(
+	stk: &mut Stk,
+	ctx: &Context,
+	opt: &Options,
+	doc: Option<&CursorDoc>,
+	rec: Recursion<'_>,
+) -> Result<Value, Error> {
+	// Find the recursion limit
+	let limit = *IDIOM_RECURSION_LIMIT as u32;
+
+	// The finished paths, and the paths still being traversed
+	let mut done: Vec<Value> = vec![];
+	let mut open: Vec<Vec<Value>> = vec![vec![]];
+
+	// Counter for the local loop
+	let mut i = 0;
+
+	while !open.is_empty() {
+		// If we have reached the maximum amount of iterations,
+		// we can return the paths which are still being traversed.
+		match rec.max {
+			Some(max) if i >= max => {
+				done.extend(open.into_iter().map(Value::from));
+				break;
+			}
+			None if i >= limit => {
+				return Err(Error::IdiomRecursionLimitExceeded {
+					limit,
+				});
+			}
+			_ => (),
+		}
+
+		// Bump iteration
+		i += 1;
+
+		let mut next = vec![];
+		for path in open {
+			// Process the path from the last value of this path
+			let current = path.last().unwrap_or(rec.current);
+			let v = stk.run(|stk| current.get(stk, ctx, opt, doc, rec.path)).await?;
+
+			// Only keep the values which this path has not yet visited
+			let values: Vec<Value> = match clean_iteration(v) {
+				Value::Array(v) => v.0,
+				v if is_final(&v) => vec![],
+				v => vec![v],
+			}
+			.into_iter()
+			.filter(|v| v != rec.current && !path.contains(v))
+			.collect();
+
+			// This path is a dead end, and is only returned if
+			// it reached the minimum amount of required iterations
+			if values.is_empty() {
+				if path.len() as u32 >= rec.min {
+					done.push(path.into());
+				}
+				continue;
+			}
+
+			// Otherwise we continue the path with each value
+			for v in values {
+				let mut path = path.clone();
+				path.push(v);
+				next.push(path);
+			}
+		}
+		open = next;
+	}
+
+	Ok(done.into())
+}
@@ -52,6 +52,7 @@ impl From<Value> for serde_json::Value {
 			Value::Subquery(subquery) => json!(subquery),
 			Value::Expression(expression) => json!(expression),
 			Value::Closure(closure) => json!(closure),
+			Value::Qualified(qualified) => json!(qualified),
 		}
 	}
 }
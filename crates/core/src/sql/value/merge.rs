@@ -1,8 +1,13 @@
 use crate::err::Error;
 use crate::sql::value::Value;
+use crate::sql::{Array, Idiom, MergeStrategy};
 
 impl Value {
 	pub(crate) fn merge(&mut self, val: Value) -> Result<(), Error> {
+		self.merge_with(val, &MergeStrategy::Replace)
+	}
+
+	pub(crate) fn merge_with(&mut self, val: Value, strategy: &MergeStrategy) -> Result<(), Error> {
 		// If this value is not an object, then error
 		if !val.is_object() {
 			return Err(Error::InvalidMerge {
@@ -11,15 +16,52 @@ impl Value {
 		}
 		// Otherwise loop through every object field
 		for k in val.every(None, false, false).iter() {
-			match val.pick(k) {
-				Value::None => self.cut(k),
-				v => self.put(k, v),
+			match (val.pick(k), strategy) {
+				(Value::None, _) => self.cut(k),
+				(Value::Array(v), MergeStrategy::Append) => match self.pick(k) {
+					Value::Array(mut a) => {
+						a.0.extend(v.0);
+						self.put(k, a.into())
+					}
+					_ => self.put(k, v.into()),
+				},
+				(Value::Array(v), MergeStrategy::Union(key)) => match self.pick(k) {
+					Value::Array(mut a) => {
+						union(&mut a, v, key.as_ref(), strategy)?;
+						self.put(k, a.into())
+					}
+					_ => self.put(k, v.into()),
+				},
+				(v, _) => self.put(k, v),
 			}
 		}
 		Ok(())
 	}
 }
 
+/// Appends the values which are not yet in the array, merging
+/// any objects into the existing object with the same key
+fn union(
+	arr: &mut Array,
+	val: Array,
+	key: Option<&Idiom>,
+	strategy: &MergeStrategy,
+) -> Result<(), Error> {
+	for v in val.0 {
+		match key.map(|key| (key, v.pick(key))) {
+			Some((key, id)) if v.is_object() && !id.is_none() => {
+				match arr.iter_mut().find(|x| x.is_object() && x.pick(key) == id) {
+					Some(x) => x.merge_with(v, strategy)?,
+					None => arr.push(v),
+				}
+			}
+			_ if arr.contains(&v) => (),
+			_ => arr.push(v),
+		}
+	}
+	Ok(())
+}
+
 #[cfg(test)]
 mod tests {
 
@@ -109,4 +151,35 @@ mod tests {
 		res.merge(mrg).unwrap();
 		assert_eq!(res, val);
 	}
+
+	#[tokio::test]
+	async fn merge_arrays_append() {
+		let mut res = Value::parse("{ tags: ['a', 'b'], name: { first: 'Tobie' } }");
+		let mrg = Value::parse("{ tags: ['b', 'c'], name: { last: 'Morgan Hitchcock' } }");
+		let val = Value::parse(
+			"{ tags: ['a', 'b', 'b', 'c'], name: { first: 'Tobie', last: 'Morgan Hitchcock' } }",
+		);
+		res.merge_with(mrg, &MergeStrategy::Append).unwrap();
+		assert_eq!(res, val);
+	}
+
+	#[tokio::test]
+	async fn merge_arrays_union() {
+		let mut res = Value::parse("{ tags: ['a', 'b'] }");
+		let mrg = Value::parse("{ tags: ['b', 'c'], other: [1] }");
+		let val = Value::parse("{ tags: ['a', 'b', 'c'], other: [1] }");
+		res.merge_with(mrg, &MergeStrategy::Union(None)).unwrap();
+		assert_eq!(res, val);
+	}
+
+	#[tokio::test]
+	async fn merge_arrays_union_by_key() {
+		let mut res = Value::parse("{ items: [{ id: 1, qty: 1 }, { id: 2, qty: 1 }] }");
+		let mrg = Value::parse("{ items: [{ id: 2, qty: 5, note: 'x' }, { id: 3, qty: 1 }] }");
+		let val = Value::parse(
+			"{ items: [{ id: 1, qty: 1 }, { id: 2, qty: 5, note: 'x' }, { id: 3, qty: 1 }] }",
+		);
+		res.merge_with(mrg, &MergeStrategy::Union(Some(Idiom::parse("id")))).unwrap();
+		assert_eq!(res, val);
+	}
 }
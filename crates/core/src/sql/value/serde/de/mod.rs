@@ -68,6 +68,7 @@ impl Value {
 			Value::Query(v) => serializer.serialize(v).map_err(Into::into),
 			Value::Model(v) => serializer.serialize(v).map_err(Into::into),
 			Value::Closure(v) => serializer.serialize(v).map_err(Into::into),
+			Value::Qualified(v) => serializer.serialize(v).map_err(Into::into),
 		}
 	}
 }
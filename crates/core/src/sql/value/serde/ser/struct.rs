@@ -72,6 +72,11 @@ pub(super) fn to_value(content: Content) -> Result<Value, Error> {
 					.map(Into::into)
 					.map_err(Into::into)
 			}
+			sql::qualified::TOKEN => {
+				sql::Qualified::deserialize(Content::Struct(v).into_deserializer())
+					.map(Into::into)
+					.map_err(Into::into)
+			}
 			_ => match v.data {
 				Data::Unit => Ok(Value::None),
 				Data::NewType {
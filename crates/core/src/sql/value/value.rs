@@ -16,8 +16,8 @@ use crate::sql::{
 	id::{Gen, Id},
 	model::Model,
 	Array, Block, Bytes, Cast, Constant, Datetime, Duration, Edges, Expression, Function, Future,
-	Geometry, Idiom, Kind, Mock, Number, Object, Operation, Param, Part, Query, Range, Regex,
-	Strand, Subquery, Table, Tables, Thing, Uuid,
+	Geometry, Idiom, Kind, Mock, Number, Object, Operation, Param, Part, Qualified, Query, Range,
+	Regex, Strand, Subquery, Table, Tables, Thing, Uuid,
 };
 use chrono::{DateTime, Utc};
 use derive::Store;
@@ -137,6 +137,7 @@ pub enum Value {
 	Query(Query),
 	Model(Box<Model>),
 	Closure(Box<Closure>),
+	Qualified(Box<Qualified>),
 	// Add new variants here
 }
 
@@ -320,6 +321,12 @@ impl From<Model> for Value {
 	}
 }
 
+impl From<Qualified> for Value {
+	fn from(v: Qualified) -> Self {
+		Value::Qualified(Box::new(v))
+	}
+}
+
 impl From<Subquery> for Value {
 	fn from(v: Subquery) -> Self {
 		Value::Subquery(Box::new(v))
@@ -2884,6 +2891,7 @@ impl fmt::Display for Value {
 			Value::Thing(v) => write!(f, "{v}"),
 			Value::Uuid(v) => write!(f, "{v}"),
 			Value::Closure(v) => write!(f, "{v}"),
+			Value::Qualified(v) => write!(f, "{v}"),
 		}
 	}
 }
@@ -2919,7 +2927,10 @@ impl Value {
 			Value::Array(v) => v.iter().any(Value::writeable),
 			Value::Object(v) => v.iter().any(|(_, v)| v.writeable()),
 			Value::Function(v) => {
-				v.is_custom() || v.is_script() || v.args().iter().any(Value::writeable)
+				v.is_custom()
+					|| v.is_script()
+					|| v.is_writeable()
+					|| v.args().iter().any(Value::writeable)
 			}
 			Value::Model(m) => m.args.iter().any(Value::writeable),
 			Value::Subquery(v) => v.writeable(),
@@ -2968,6 +2979,7 @@ impl Value {
 			Value::Model(v) => v.compute(stk, ctx, opt, doc).await,
 			Value::Subquery(v) => stk.run(|stk| v.compute(stk, ctx, opt, doc)).await,
 			Value::Expression(v) => stk.run(|stk| v.compute(stk, ctx, opt, doc)).await,
+			Value::Qualified(_) => Err(Error::QualifiedStatement),
 			_ => Ok(self.to_owned()),
 		}
 	}
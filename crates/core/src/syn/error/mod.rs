@@ -35,6 +35,8 @@ pub struct Diagnostic {
 pub struct SyntaxError {
 	diagnostic: Box<Diagnostic>,
 	data_pending: bool,
+	/// Errors found later in the source, after recovering from this error.
+	following: Vec<SyntaxError>,
 }
 
 impl SyntaxError {
@@ -51,9 +53,18 @@ impl SyntaxError {
 		Self {
 			diagnostic: Box::new(diagnostic),
 			data_pending: false,
+			following: Vec::new(),
 		}
 	}
 
+	/// Combine a list of errors, in the order they were found in the source, into a single error.
+	pub fn from_errors(errors: Vec<SyntaxError>) -> Option<Self> {
+		let mut errors = errors.into_iter();
+		let mut first = errors.next()?;
+		first.following.extend(errors);
+		Some(first)
+	}
+
 	/// Returns whether this error is possibly the result of missing data.
 	pub fn is_data_pending(&self) -> bool {
 		self.data_pending
@@ -107,8 +118,10 @@ impl SyntaxError {
 		let mut res = RenderedError {
 			errors: Vec::new(),
 			snippets: Vec::new(),
+			following: Vec::new(),
 		};
 		Self::render_on_inner(&self.diagnostic, source, &mut res);
+		res.following = self.following.iter().map(|e| e.render_on(source)).collect();
 		res
 	}
 
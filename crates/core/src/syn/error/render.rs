@@ -9,6 +9,8 @@ use super::{Location, MessageKind};
 pub struct RenderedError {
 	pub errors: Vec<String>,
 	pub snippets: Vec<Snippet>,
+	/// The errors found later in the source, after recovering from this error.
+	pub following: Vec<RenderedError>,
 }
 
 impl fmt::Display for RenderedError {
@@ -27,6 +29,10 @@ impl fmt::Display for RenderedError {
 		for s in &self.snippets {
 			writeln!(f, "{s}")?;
 		}
+		for e in &self.following {
+			writeln!(f)?;
+			write!(f, "{e}")?;
+		}
 		Ok(())
 	}
 }
@@ -329,6 +335,7 @@ mod test {
 				label: Some("this is wrong".to_owned()),
 				kind: MessageKind::Error,
 			}],
+			following: Vec::new(),
 		};
 
 		let error_string = format!("{}", error);
@@ -26,7 +26,10 @@ pub static RESERVED_KEYWORD: phf::Set<UniCase<&'static str>> = phf_set! {
 	UniCase::ascii("REBUILD"),
 	UniCase::ascii("RETURN"),
 	UniCase::ascii("RELATE"),
+	UniCase::ascii("RELEASE"),
 	UniCase::ascii("REMOVE"),
+	UniCase::ascii("ROLLBACK"),
+	UniCase::ascii("SAVEPOINT"),
 	UniCase::ascii("SELECT"),
 	UniCase::ascii("LET"),
 	UniCase::ascii("SHOW"),
@@ -64,6 +67,8 @@ pub(crate) static KEYWORDS: phf::Map<UniCase<&'static str>, TokenKind> = phf_map
 	UniCase::ascii("Alter") => TokenKind::Keyword(Keyword::Alter),
 	UniCase::ascii("ANALYZE") => TokenKind::Keyword(Keyword::Analyze),
 	UniCase::ascii("ANALYZER") => TokenKind::Keyword(Keyword::Analyzer),
+	UniCase::ascii("APPEND") => TokenKind::Keyword(Keyword::Append),
+	UniCase::ascii("ARRAYS") => TokenKind::Keyword(Keyword::Arrays),
 	UniCase::ascii("AS") => TokenKind::Keyword(Keyword::As),
 	UniCase::ascii("ASCENDING") => TokenKind::Keyword(Keyword::Ascending),
 	UniCase::ascii("ASC") => TokenKind::Keyword(Keyword::Ascending),
@@ -84,11 +89,14 @@ pub(crate) static KEYWORDS: phf::Map<UniCase<&'static str>, TokenKind> = phf_map
 	UniCase::ascii("CHANGEFEED") => TokenKind::Keyword(Keyword::ChangeFeed),
 	UniCase::ascii("CHANGES") => TokenKind::Keyword(Keyword::Changes),
 	UniCase::ascii("CAPACITY") => TokenKind::Keyword(Keyword::Capacity),
+	UniCase::ascii("CASCADE") => TokenKind::Keyword(Keyword::Cascade),
 	UniCase::ascii("CLASS") => TokenKind::Keyword(Keyword::Class),
 	UniCase::ascii("COMMENT") => TokenKind::Keyword(Keyword::Comment),
 	UniCase::ascii("COMMIT") => TokenKind::Keyword(Keyword::Commit),
 	UniCase::ascii("CONCURRENTLY") => TokenKind::Keyword(Keyword::Concurrently),
 	UniCase::ascii("CONFIG") => TokenKind::Keyword(Keyword::Config),
+	UniCase::ascii("CONNECTION") => TokenKind::Keyword(Keyword::Connection),
+	UniCase::ascii("CONNECTIONS") => TokenKind::Keyword(Keyword::Connections),
 	UniCase::ascii("CONTENT") => TokenKind::Keyword(Keyword::Content),
 	UniCase::ascii("CONTINUE") => TokenKind::Keyword(Keyword::Continue),
 	UniCase::ascii("CREATE") => TokenKind::Keyword(Keyword::Create),
@@ -103,6 +111,7 @@ pub(crate) static KEYWORDS: phf::Map<UniCase<&'static str>, TokenKind> = phf_map
 	UniCase::ascii("DIMENSION") => TokenKind::Keyword(Keyword::Dimension),
 	UniCase::ascii("DISTANCE") => TokenKind::Keyword(Keyword::Distance),
 	UniCase::ascii("DIST") => TokenKind::Keyword(Keyword::Distance),
+	UniCase::ascii("DISTINCT") => TokenKind::Keyword(Keyword::Distinct),
 	UniCase::ascii("DOC_IDS_CACHE") => TokenKind::Keyword(Keyword::DocIdsCache),
 	UniCase::ascii("DOC_IDS_ORDER") => TokenKind::Keyword(Keyword::DocIdsOrder),
 	UniCase::ascii("DOC_LENGTHS_CACHE") => TokenKind::Keyword(Keyword::DocLengthsCache),
@@ -113,10 +122,12 @@ pub(crate) static KEYWORDS: phf::Map<UniCase<&'static str>, TokenKind> = phf_map
 	UniCase::ascii("EFC") => TokenKind::Keyword(Keyword::Efc),
 	UniCase::ascii("EVENT") => TokenKind::Keyword(Keyword::Event),
 	UniCase::ascii("ELSE") => TokenKind::Keyword(Keyword::Else),
+	UniCase::ascii("ENCRYPTED") => TokenKind::Keyword(Keyword::Encrypted),
 	UniCase::ascii("END") => TokenKind::Keyword(Keyword::End),
 	UniCase::ascii("ENFORCED") => TokenKind::Keyword(Keyword::Enforced),
 	UniCase::ascii("EXCLUDE") => TokenKind::Keyword(Keyword::Exclude),
 	UniCase::ascii("EXISTS") => TokenKind::Keyword(Keyword::Exists),
+	UniCase::ascii("EXPIRE") => TokenKind::Keyword(Keyword::Expire),
 	UniCase::ascii("EXPIRED") => TokenKind::Keyword(Keyword::Expired),
 	UniCase::ascii("EXPLAIN") => TokenKind::Keyword(Keyword::Explain),
 	UniCase::ascii("EXPUNGE") => TokenKind::Keyword(Keyword::Expunge),
@@ -164,6 +175,7 @@ pub(crate) static KEYWORDS: phf::Map<UniCase<&'static str>, TokenKind> = phf_map
 	UniCase::ascii("MAPPER") => TokenKind::Keyword(Keyword::Mapper),
 	UniCase::ascii("ML") => TokenKind::Keyword(Keyword::ML),
 	UniCase::ascii("MERGE") => TokenKind::Keyword(Keyword::Merge),
+	UniCase::ascii("MIGRATE") => TokenKind::Keyword(Keyword::Migrate),
 	UniCase::ascii("MODEL") => TokenKind::Keyword(Keyword::Model),
 	UniCase::ascii("MTREE") => TokenKind::Keyword(Keyword::MTree),
 	UniCase::ascii("MTREE_CACHE") => TokenKind::Keyword(Keyword::MTreeCache),
@@ -174,6 +186,7 @@ pub(crate) static KEYWORDS: phf::Map<UniCase<&'static str>, TokenKind> = phf_map
 	UniCase::ascii("NOINDEX") => TokenKind::Keyword(Keyword::NoIndex),
 	UniCase::ascii("NONE") => TokenKind::Keyword(Keyword::None),
 	UniCase::ascii("NULL") => TokenKind::Keyword(Keyword::Null),
+	UniCase::ascii("NULLS") => TokenKind::Keyword(Keyword::Nulls),
 	UniCase::ascii("NUMERIC") => TokenKind::Keyword(Keyword::Numeric),
 	UniCase::ascii("OMIT") => TokenKind::Keyword(Keyword::Omit),
 	UniCase::ascii("ON") => TokenKind::Keyword(Keyword::On),
@@ -187,24 +200,34 @@ pub(crate) static KEYWORDS: phf::Map<UniCase<&'static str>, TokenKind> = phf_map
 	UniCase::ascii("PASSHASH") => TokenKind::Keyword(Keyword::Passhash),
 	UniCase::ascii("PASSWORD") => TokenKind::Keyword(Keyword::Password),
 	UniCase::ascii("PATCH") => TokenKind::Keyword(Keyword::Patch),
+	UniCase::ascii("PATH") => TokenKind::Keyword(Keyword::Path),
 	UniCase::ascii("PERMISSIONS") => TokenKind::Keyword(Keyword::Permissions),
 	UniCase::ascii("POSTINGS_CACHE") => TokenKind::Keyword(Keyword::PostingsCache),
 	UniCase::ascii("POSTINGS_ORDER") => TokenKind::Keyword(Keyword::PostingsOrder),
 	UniCase::ascii("PUNCT") => TokenKind::Keyword(Keyword::Punct),
 	UniCase::ascii("PURGE") => TokenKind::Keyword(Keyword::Purge),
+	UniCase::ascii("QUERIES") => TokenKind::Keyword(Keyword::Queries),
 	UniCase::ascii("RANGE") => TokenKind::Keyword(Keyword::Range),
+	UniCase::ascii("RATE") => TokenKind::Keyword(Keyword::Rate),
 	UniCase::ascii("READONLY") => TokenKind::Keyword(Keyword::Readonly),
 	UniCase::ascii("RELATE") => TokenKind::Keyword(Keyword::Relate),
 	UniCase::ascii("RELATION") => TokenKind::Keyword(Keyword::Relation),
+	UniCase::ascii("RELEASE") => TokenKind::Keyword(Keyword::Release),
 	UniCase::ascii("REBUILD") => TokenKind::Keyword(Keyword::Rebuild),
+	UniCase::ascii("REFERENCE") => TokenKind::Keyword(Keyword::Reference),
+	UniCase::ascii("REJECT") => TokenKind::Keyword(Keyword::Reject),
 	UniCase::ascii("REMOVE") => TokenKind::Keyword(Keyword::Remove),
+	UniCase::ascii("RENAME") => TokenKind::Keyword(Keyword::Rename),
 	UniCase::ascii("REPLACE") => TokenKind::Keyword(Keyword::Replace),
 	UniCase::ascii("RETURN") => TokenKind::Keyword(Keyword::Return),
 	UniCase::ascii("REVOKE") => TokenKind::Keyword(Keyword::Revoke),
 	UniCase::ascii("REVOKED") => TokenKind::Keyword(Keyword::Revoked),
 	UniCase::ascii("ROLES") => TokenKind::Keyword(Keyword::Roles),
+	UniCase::ascii("ROLLBACK") => TokenKind::Keyword(Keyword::Rollback),
 	UniCase::ascii("ROOT") => TokenKind::Keyword(Keyword::Root),
 	UniCase::ascii("KV") => TokenKind::Keyword(Keyword::Root),
+	UniCase::ascii("SAVEPOINT") => TokenKind::Keyword(Keyword::Savepoint),
+	UniCase::ascii("SCHEDULE") => TokenKind::Keyword(Keyword::Schedule),
 	UniCase::ascii("SCHEMAFULL") => TokenKind::Keyword(Keyword::Schemafull),
 	UniCase::ascii("SCHEMAFUL") => TokenKind::Keyword(Keyword::Schemafull),
 	UniCase::ascii("SCHEMALESS") => TokenKind::Keyword(Keyword::Schemaless),
@@ -212,6 +235,7 @@ pub(crate) static KEYWORDS: phf::Map<UniCase<&'static str>, TokenKind> = phf_map
 	UniCase::ascii("SC") => TokenKind::Keyword(Keyword::Scope),
 	UniCase::ascii("SEARCH") => TokenKind::Keyword(Keyword::Search),
 	UniCase::ascii("SELECT") => TokenKind::Keyword(Keyword::Select),
+	UniCase::ascii("SEQUENCE") => TokenKind::Keyword(Keyword::Sequence),
 	UniCase::ascii("SESSION") => TokenKind::Keyword(Keyword::Session),
 	UniCase::ascii("SET") => TokenKind::Keyword(Keyword::Set),
 	UniCase::ascii("SHOW") => TokenKind::Keyword(Keyword::Show),
@@ -219,6 +243,7 @@ pub(crate) static KEYWORDS: phf::Map<UniCase<&'static str>, TokenKind> = phf_map
 	UniCase::ascii("SIGNUP") => TokenKind::Keyword(Keyword::Signup),
 	UniCase::ascii("SINCE") => TokenKind::Keyword(Keyword::Since),
 	UniCase::ascii("SLEEP") => TokenKind::Keyword(Keyword::Sleep),
+	UniCase::ascii("SLOW") => TokenKind::Keyword(Keyword::Slow),
 	UniCase::ascii("SNOWBALL") => TokenKind::Keyword(Keyword::Snowball),
 	UniCase::ascii("SPLIT") => TokenKind::Keyword(Keyword::Split),
 	UniCase::ascii("START") => TokenKind::Keyword(Keyword::Start),
@@ -232,12 +257,15 @@ pub(crate) static KEYWORDS: phf::Map<UniCase<&'static str>, TokenKind> = phf_map
 	UniCase::ascii("THEN") => TokenKind::Keyword(Keyword::Then),
 	UniCase::ascii("THROW") => TokenKind::Keyword(Keyword::Throw),
 	UniCase::ascii("TIMEOUT") => TokenKind::Keyword(Keyword::Timeout),
+	UniCase::ascii("TIMESERIES") => TokenKind::Keyword(Keyword::Timeseries),
+	UniCase::ascii("TIMESTAMP") => TokenKind::Keyword(Keyword::Timestamp),
 	UniCase::ascii("TO") => TokenKind::Keyword(Keyword::To),
 	UniCase::ascii("TOKENIZERS") => TokenKind::Keyword(Keyword::Tokenizers),
 	UniCase::ascii("TOKEN") => TokenKind::Keyword(Keyword::Token),
 	UniCase::ascii("TRANSACTION") => TokenKind::Keyword(Keyword::Transaction),
 	UniCase::ascii("true") => TokenKind::Keyword(Keyword::True),
 	UniCase::ascii("TYPE") => TokenKind::Keyword(Keyword::Type),
+	UniCase::ascii("UNION") => TokenKind::Keyword(Keyword::Union),
 	UniCase::ascii("UNIQUE") => TokenKind::Keyword(Keyword::Unique),
 	UniCase::ascii("UNSET") => TokenKind::Keyword(Keyword::Unset),
 	UniCase::ascii("UPDATE") => TokenKind::Keyword(Keyword::Update),
@@ -3,7 +3,7 @@
 use crate::{
 	cnf::{MAX_OBJECT_PARSING_DEPTH, MAX_QUERY_PARSING_DEPTH},
 	err::Error,
-	sql::{Block, Datetime, Duration, Idiom, Query, Range, Subquery, Thing, Value},
+	sql::{Block, Datetime, Duration, Idiom, Kind, Query, Range, Subquery, Thing, Value},
 };
 
 pub mod error;
@@ -60,6 +60,14 @@ pub fn parse(input: &str) -> Result<Query, Error> {
 		.map_err(Error::InvalidQuery)
 }
 
+/// Formats a SurrealQL query, returning the canonical, indented form of each statement
+///
+/// The query is parsed, and then printed from its [`Query`] representation, so comments
+/// and the original layout of the query are not kept.
+pub fn format(input: &str) -> Result<String, Error> {
+	parse(input).map(|query| format!("{query:#}"))
+}
+
 /// Parses a SurrealQL [`Value`].
 #[instrument(level = "trace", target = "surrealdb::core::syn", fields(length = input.len()))]
 pub fn value(input: &str) -> Result<Value, Error> {
@@ -145,6 +153,27 @@ pub fn idiom(input: &str) -> Result<Idiom, Error> {
 		.map_err(Error::InvalidQuery)
 }
 
+/// Parses a SurrealQL [`Kind`], without the enclosing `<` and `>`
+#[instrument(level = "trace", target = "surrealdb::core::syn", fields(length = input.len()))]
+pub fn kind(input: &str) -> Result<Kind, Error> {
+	trace!(target: TARGET, "Parsing SurrealQL kind");
+
+	if input.len() > u32::MAX as usize {
+		return Err(Error::QueryTooLarge);
+	}
+
+	let mut parser = Parser::new(input.as_bytes())
+		.with_object_recursion_limit(*MAX_OBJECT_PARSING_DEPTH as usize)
+		.with_query_recursion_limit(*MAX_QUERY_PARSING_DEPTH as usize);
+	let mut stack = Stack::new();
+	stack
+		.enter(|stk| parser.parse_inner_kind(stk))
+		.finish()
+		.and_then(|e| parser.assert_finished().map(|_| e))
+		.map_err(|e| e.render_on(input))
+		.map_err(Error::InvalidQuery)
+}
+
 /// Parse a datetime without enclosing delimiters from a string.
 #[instrument(level = "trace", target = "surrealdb::core::syn", fields(length = input.len()))]
 pub fn datetime(input: &str) -> Result<Datetime, Error> {
@@ -39,6 +39,19 @@ impl TokenValue for u64 {
 	}
 }
 
+impl TokenValue for i64 {
+	fn from_token(parser: &mut Parser<'_>) -> ParseResult<Self> {
+		let token = parser.peek();
+		match token.kind {
+			t!("+") | t!("-") | TokenKind::Digits => {
+				parser.pop_peek();
+				Ok(parser.lexer.lex_compound(token, compound::integer)?.value)
+			}
+			_ => unexpected!(parser, token, "an integer"),
+		}
+	}
+}
+
 impl TokenValue for u32 {
 	fn from_token(parser: &mut Parser<'_>) -> ParseResult<Self> {
 		parse_integer(parser)
@@ -181,9 +181,11 @@ pub(crate) static PATHS: phf::Map<UniCase<&'static str>, PathKind> = phf_map! {
 		UniCase::ascii("geo::area") => PathKind::Function,
 		UniCase::ascii("geo::bearing") => PathKind::Function,
 		UniCase::ascii("geo::centroid") => PathKind::Function,
+		UniCase::ascii("geo::contains") => PathKind::Function,
 		UniCase::ascii("geo::distance") => PathKind::Function,
 		UniCase::ascii("geo::hash::decode") => PathKind::Function,
 		UniCase::ascii("geo::hash::encode") => PathKind::Function,
+		UniCase::ascii("geo::intersects") => PathKind::Function,
 		UniCase::ascii("geo::is::valid") => PathKind::Function,
 		//
 		UniCase::ascii("http::head") => PathKind::Function,
@@ -192,6 +194,7 @@ pub(crate) static PATHS: phf::Map<UniCase<&'static str>, PathKind> = phf_map! {
 		UniCase::ascii("http::post") => PathKind::Function,
 		UniCase::ascii("http::patch") => PathKind::Function,
 		UniCase::ascii("http::delete") => PathKind::Function,
+		UniCase::ascii("http::webhook") => PathKind::Function,
 		//
 		UniCase::ascii("math::abs") => PathKind::Function,
 		UniCase::ascii("math::acos") => PathKind::Function,
@@ -272,6 +275,7 @@ pub(crate) static PATHS: phf::Map<UniCase<&'static str>, PathKind> = phf_map! {
 		UniCase::ascii("rand::uuid") => PathKind::Function,
 		//
 		UniCase::ascii("record::exists") => PathKind::Function,
+		UniCase::ascii("record::history") => PathKind::Function,
 		UniCase::ascii("record::id") => PathKind::Function,
 		UniCase::ascii("record::table") => PathKind::Function,
 		UniCase::ascii("record::tb") => PathKind::Function,
@@ -281,6 +285,8 @@ pub(crate) static PATHS: phf::Map<UniCase<&'static str>, PathKind> = phf_map! {
 		UniCase::ascii("search::highlight") => PathKind::Function,
 		UniCase::ascii("search::offsets") => PathKind::Function,
 		//
+		UniCase::ascii("sequence::nextval") => PathKind::Function,
+		//
 		UniCase::ascii("session::ac") => PathKind::Function,
 		UniCase::ascii("session::db") => PathKind::Function,
 		UniCase::ascii("session::id") => PathKind::Function,
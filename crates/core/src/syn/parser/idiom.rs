@@ -2,7 +2,7 @@ use reblessive::Stk;
 
 use crate::{
 	sql::{
-		part::{DestructurePart, Recurse},
+		part::{DestructurePart, Recurse, RecurseInstruction},
 		Dir, Edges, Field, Fields, Graph, Ident, Idiom, Part, Table, Tables, Value,
 	},
 	syn::{
@@ -410,6 +410,7 @@ impl Parser<'_> {
 	pub(super) async fn parse_recurse_part(&mut self, ctx: &mut Stk) -> ParseResult<Part> {
 		let start = self.last_span();
 		let recurse = self.parse_recurse_inner()?;
+		let instruction = self.parse_recurse_instruction()?;
 		self.expect_closing_delimiter(t!("}"), start)?;
 
 		let nest = if self.eat(t!("(")) {
@@ -421,7 +422,15 @@ impl Parser<'_> {
 			None
 		};
 
-		Ok(Part::Recurse(recurse, nest))
+		Ok(Part::Recurse(recurse, nest, instruction))
+	}
+	/// Parse an optional recurse instruction, like `+path`
+	pub(super) fn parse_recurse_instruction(&mut self) -> ParseResult<Option<RecurseInstruction>> {
+		if !self.eat(t!("+")) {
+			return Ok(None);
+		}
+		expected!(self, t!("PATH"));
+		Ok(Some(RecurseInstruction::Path))
 	}
 	/// Parse the part after the `[` in a idiom
 	pub(super) async fn parse_bracket_part(
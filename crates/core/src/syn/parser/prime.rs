@@ -4,15 +4,15 @@ use reblessive::Stk;
 use super::{mac::pop_glued, ParseResult, Parser};
 use crate::{
 	sql::{
-		Array, Closure, Dir, Duration, Function, Geometry, Ident, Idiom, Kind, Mock, Number, Param,
-		Part, Script, Strand, Subquery, Table, Value,
+		Array, Closure, Dir, Duration, Function, Geometry, Id, Ident, Idiom, Kind, Mock, Number,
+		Param, Part, Qualified, Script, Strand, Subquery, Table, Thing, Value,
 	},
 	syn::{
 		error::bail,
 		lexer::compound,
 		parser::{
 			enter_object_recursion, enter_query_recursion,
-			mac::{expected, unexpected},
+			mac::{expected, expected_whitespace, unexpected},
 		},
 		token::{t, Glued, Span, TokenKind},
 	},
@@ -107,6 +107,9 @@ impl Parser<'_> {
 						self.pop_peek();
 						self.parse_builtin(ctx, token.span).await
 					}
+					t!(":") if token.kind == t!("NAMESPACE") => {
+						self.parse_qualified_or_thing(ctx).await
+					}
 					t!(":") => {
 						let str = self.next_token_value::<Ident>()?.0;
 						self.parse_thing_or_range(ctx, str).await.map(Value::Thing)
@@ -118,6 +121,40 @@ impl Parser<'_> {
 		}
 	}
 
+	/// Parse a table in another namespace and database, like `ns:acme.db:prod.person`,
+	/// or otherwise a record id on a table named `ns` or `namespace`.
+	async fn parse_qualified_or_thing(&mut self, ctx: &mut Stk) -> ParseResult<Value> {
+		if !Self::kind_is_identifier(self.peek_whitespace_token_at(2).kind)
+			|| self.peek_whitespace_token_at(3).kind != t!(".")
+		{
+			let str = self.next_token_value::<Ident>()?.0;
+			return self.parse_thing_or_range(ctx, str).await.map(Value::Thing);
+		}
+		let tb = self.next_token_value::<Ident>()?.0;
+		self.pop_peek();
+		let ns = self.next_token_value::<Ident>()?;
+		// Without a database this is a record id, followed by an idiom
+		if self.peek_whitespace_token_at(1).kind != t!("DATABASE")
+			|| self.peek_whitespace_token_at(2).kind != t!(":")
+		{
+			return Ok(Value::Thing(Thing {
+				tb,
+				id: Id::String(ns.0),
+			}));
+		}
+		self.pop_peek();
+		self.pop_peek();
+		self.pop_peek();
+		let db = self.next_token_value::<Ident>()?;
+		expected_whitespace!(self, t!("."));
+		let what = self.next_token_value::<Table>()?;
+		Ok(Value::Qualified(Box::new(Qualified {
+			ns,
+			db,
+			what,
+		})))
+	}
+
 	pub(super) async fn try_parse_inline(
 		&mut self,
 		ctx: &mut Stk,
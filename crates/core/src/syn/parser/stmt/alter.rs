@@ -2,8 +2,11 @@ use reblessive::Stk;
 
 use crate::{
 	sql::{
-		statements::{AlterStatement, AlterTableStatement},
-		TableType,
+		statements::{
+			AlterDatabaseStatement, AlterFieldStatement, AlterStatement, AlterTableStatement,
+			AlterUserStatement, DefineUserStatement,
+		},
+		Ident, Strand, TableType,
 	},
 	syn::{
 		parser::{
@@ -18,11 +21,155 @@ impl Parser<'_> {
 	pub(crate) async fn parse_alter_stmt(&mut self, ctx: &mut Stk) -> ParseResult<AlterStatement> {
 		let next = self.next();
 		match next.kind {
+			t!("DATABASE") => self.parse_alter_database().map(AlterStatement::Database),
 			t!("TABLE") => self.parse_alter_table(ctx).await.map(AlterStatement::Table),
+			t!("FIELD") => self.parse_alter_field(ctx).await.map(AlterStatement::Field),
+			t!("USER") => self.parse_alter_user().map(AlterStatement::User),
 			_ => unexpected!(self, next, "a alter statement keyword"),
 		}
 	}
 
+	pub(crate) fn parse_alter_database(&mut self) -> ParseResult<AlterDatabaseStatement> {
+		let if_exists = if self.eat(t!("IF")) {
+			expected!(self, t!("EXISTS"));
+			true
+		} else {
+			false
+		};
+		let name = self.next_token_value()?;
+		let mut res = AlterDatabaseStatement {
+			name,
+			if_exists,
+			..Default::default()
+		};
+
+		loop {
+			match self.peek_kind() {
+				t!("COMMENT") => {
+					self.pop_peek();
+					if self.eat(t!("NONE")) {
+						res.comment = Some(None);
+					} else {
+						res.comment = Some(Some(self.next_token_value()?));
+					}
+				}
+				t!("RENAME") => {
+					self.pop_peek();
+					expected!(self, t!("TO"));
+					res.rename = Some(self.next_token_value()?);
+				}
+				_ => break,
+			}
+		}
+
+		Ok(res)
+	}
+
+	pub(crate) fn parse_alter_user(&mut self) -> ParseResult<AlterUserStatement> {
+		let if_exists = if self.eat(t!("IF")) {
+			expected!(self, t!("EXISTS"));
+			true
+		} else {
+			false
+		};
+		let name = self.next_token_value()?;
+		expected!(self, t!("ON"));
+		let base = self.parse_base(false)?;
+		let mut res = AlterUserStatement {
+			name,
+			base,
+			if_exists,
+			..Default::default()
+		};
+
+		loop {
+			match self.peek_kind() {
+				t!("COMMENT") => {
+					self.pop_peek();
+					if self.eat(t!("NONE")) {
+						res.comment = Some(None);
+					} else {
+						res.comment = Some(Some(self.next_token_value()?));
+					}
+				}
+				t!("PASSWORD") => {
+					self.pop_peek();
+					let mut user = DefineUserStatement::default();
+					user.set_password(&self.next_token_value::<Strand>()?.0);
+					res.hash = Some(user.hash);
+				}
+				t!("PASSHASH") => {
+					self.pop_peek();
+					res.hash = Some(self.next_token_value::<Strand>()?.0);
+				}
+				t!("ROLES") => {
+					self.pop_peek();
+					let mut roles = Vec::new();
+					loop {
+						let token = self.peek();
+						let role = self.next_token_value::<Ident>()?;
+						if !matches!(role.to_lowercase().as_str(), "viewer" | "editor" | "owner") {
+							unexpected!(self, token, "an existent role");
+						}
+						roles.push(role);
+
+						if !self.eat(t!(",")) {
+							res.roles = Some(roles);
+							break;
+						}
+					}
+				}
+				t!("DURATION") => {
+					self.pop_peek();
+					while self.eat(t!("FOR")) {
+						match self.peek_kind() {
+							t!("TOKEN") => {
+								self.pop_peek();
+								let peek = self.peek();
+								match peek.kind {
+									t!("NONE") => {
+										// Tokens without expiration are not accepted
+										unexpected!(self, peek, "a token duration");
+									}
+									_ => res.token_duration = Some(self.next_token_value()?),
+								}
+							}
+							t!("SESSION") => {
+								self.pop_peek();
+								match self.peek_kind() {
+									t!("NONE") => {
+										self.pop_peek();
+										res.session_duration = Some(None)
+									}
+									_ => {
+										res.session_duration = Some(Some(self.next_token_value()?))
+									}
+								}
+							}
+							t!("PASSWORD") => {
+								self.pop_peek();
+								match self.peek_kind() {
+									t!("NONE") => {
+										self.pop_peek();
+										res.password_duration = Some(None)
+									}
+									_ => {
+										res.password_duration = Some(Some(self.next_token_value()?))
+									}
+								}
+							}
+							_ => break,
+						}
+						self.eat(t!(","));
+					}
+				}
+				_ => break,
+			}
+		}
+
+		Ok(res)
+	}
+
 	pub(crate) async fn parse_alter_table(
 		&mut self,
 		ctx: &mut Stk,
@@ -97,6 +244,70 @@ impl Parser<'_> {
 						res.changefeed = Some(Some(self.parse_changefeed()?));
 					}
 				}
+				t!("RENAME") => {
+					self.pop_peek();
+					expected!(self, t!("TO"));
+					res.rename = Some(self.next_token_value()?);
+				}
+				_ => break,
+			}
+		}
+
+		Ok(res)
+	}
+
+	pub(crate) async fn parse_alter_field(
+		&mut self,
+		ctx: &mut Stk,
+	) -> ParseResult<AlterFieldStatement> {
+		let if_exists = if self.eat(t!("IF")) {
+			expected!(self, t!("EXISTS"));
+			true
+		} else {
+			false
+		};
+		let name = self.parse_local_idiom(ctx).await?;
+		expected!(self, t!("ON"));
+		self.eat(t!("TABLE"));
+		let what = self.next_token_value()?;
+		let mut res = AlterFieldStatement {
+			name,
+			what,
+			if_exists,
+			..Default::default()
+		};
+
+		loop {
+			match self.peek_kind() {
+				t!("RENAME") => {
+					self.pop_peek();
+					expected!(self, t!("TO"));
+					res.rename = Some(self.parse_local_idiom(ctx).await?);
+				}
+				t!("TYPE") => {
+					self.pop_peek();
+					res.kind = Some(ctx.run(|ctx| self.parse_inner_kind(ctx)).await?);
+				}
+				t!("DEFAULT") => {
+					self.pop_peek();
+					if self.eat(t!("NONE")) {
+						res.default = Some(None);
+					} else {
+						res.default = Some(Some(ctx.run(|ctx| self.parse_value_field(ctx)).await?));
+					}
+				}
+				t!("COMMENT") => {
+					self.pop_peek();
+					if self.eat(t!("NONE")) {
+						res.comment = Some(None);
+					} else {
+						res.comment = Some(Some(self.next_token_value()?));
+					}
+				}
+				t!("MIGRATE") => {
+					self.pop_peek();
+					res.migrate = true;
+				}
 				_ => break,
 			}
 		}
@@ -1,6 +1,7 @@
 use reblessive::Stk;
 
 use crate::cnf::EXPERIMENTAL_BEARER_ACCESS;
+use crate::dbs::Schedule;
 use crate::sql::access_type::JwtAccessVerify;
 use crate::sql::index::HnswParams;
 use crate::sql::statements::define::config::graphql::{GraphQLConfig, TableConfig};
@@ -17,14 +18,16 @@ use crate::{
 			define::config::graphql, DefineAccessStatement, DefineAnalyzerStatement,
 			DefineDatabaseStatement, DefineEventStatement, DefineFieldStatement,
 			DefineFunctionStatement, DefineIndexStatement, DefineNamespaceStatement,
-			DefineParamStatement, DefineStatement, DefineTableStatement, DefineUserStatement,
+			DefineParamStatement, DefineSequenceStatement, DefineStatement, DefineTableStatement,
+			DefineUserStatement,
 		},
 		table_type,
 		tokenizer::Tokenizer,
-		user, AccessType, Ident, Idioms, Index, Kind, Param, Permissions, Scoring, Strand,
-		TableType, Values,
+		user, AccessType, Ident, Idioms, Index, Kind, Param, Permissions, Reference,
+		ReferenceDeleteStrategy, Scoring, Strand, TableType, Values,
 	},
 	syn::{
+		error::bail,
 		parser::{
 			mac::{expected, unexpected},
 			ParseResult, Parser,
@@ -60,6 +63,7 @@ impl Parser<'_> {
 			t!("ANALYZER") => self.parse_define_analyzer().map(DefineStatement::Analyzer),
 			t!("ACCESS") => self.parse_define_access(ctx).await.map(DefineStatement::Access),
 			t!("CONFIG") => self.parse_define_config().map(DefineStatement::Config),
+			t!("SEQUENCE") => self.parse_define_sequence().map(DefineStatement::Sequence),
 			_ => unexpected!(self, next, "a define statement keyword"),
 		}
 	}
@@ -83,9 +87,29 @@ impl Parser<'_> {
 			..Default::default()
 		};
 
-		while let t!("COMMENT") = self.peek_kind() {
-			self.pop_peek();
-			res.comment = Some(self.next_token_value()?);
+		loop {
+			match self.peek_kind() {
+				t!("COMMENT") => {
+					self.pop_peek();
+					res.comment = Some(self.next_token_value()?);
+				}
+				t!("LIMIT") => {
+					self.pop_peek();
+					loop {
+						let next = self.next();
+						match next.kind {
+							t!("QUERIES") => res.limits.queries = Some(self.next_token_value()?),
+							t!("LIVE") => res.limits.live = Some(self.next_token_value()?),
+							t!("RATE") => res.limits.rate = Some(self.next_token_value()?),
+							_ => unexpected!(self, next, "QUERIES, LIVE, or RATE"),
+						}
+						if !self.eat(t!(",")) {
+							break;
+						}
+					}
+				}
+				_ => break,
+			}
 		}
 
 		Ok(res)
@@ -283,6 +307,16 @@ impl Parser<'_> {
 									_ => res.set_session_duration(Some(self.next_token_value()?)),
 								}
 							}
+							t!("PASSWORD") => {
+								self.pop_peek();
+								match self.peek_kind() {
+									t!("NONE") => {
+										self.pop_peek();
+										res.set_password_duration(None)
+									}
+									_ => res.set_password_duration(Some(self.next_token_value()?)),
+								}
+							}
 							_ => break,
 						}
 						self.eat(t!(","));
@@ -669,6 +703,41 @@ impl Parser<'_> {
 		Ok(res)
 	}
 
+	pub fn parse_define_sequence(&mut self) -> ParseResult<DefineSequenceStatement> {
+		let (if_not_exists, overwrite) = if self.eat(t!("IF")) {
+			expected!(self, t!("NOT"));
+			expected!(self, t!("EXISTS"));
+			(true, false)
+		} else if self.eat(t!("OVERWRITE")) {
+			(false, true)
+		} else {
+			(false, false)
+		};
+		let name = self.next_token_value()?;
+		let mut res = DefineSequenceStatement {
+			name,
+			start: 1,
+			if_not_exists,
+			overwrite,
+			..Default::default()
+		};
+
+		loop {
+			match self.peek_kind() {
+				t!("START") => {
+					self.pop_peek();
+					res.start = self.next_token_value()?;
+				}
+				t!("COMMENT") => {
+					self.pop_peek();
+					res.comment = Some(self.next_token_value()?);
+				}
+				_ => break,
+			}
+		}
+		Ok(res)
+	}
+
 	pub async fn parse_define_table(&mut self, ctx: &mut Stk) -> ParseResult<DefineTableStatement> {
 		let (if_not_exists, overwrite) = if self.eat(t!("IF")) {
 			expected!(self, t!("NOT"));
@@ -712,11 +781,21 @@ impl Parser<'_> {
 							self.pop_peek();
 							kind = Some(TableType::Relation(self.parse_relation_schema()?));
 						}
+						t!("TIMESERIES") => {
+							self.pop_peek();
+							expected!(self, t!("TIMESTAMP"));
+							let timestamp = self.parse_local_idiom(ctx).await?;
+							kind = Some(TableType::TimeSeries(table_type::TimeSeries {
+								timestamp,
+							}));
+						}
 						t!("ANY") => {
 							self.pop_peek();
 							kind = Some(TableType::Any);
 						}
-						_ => unexpected!(self, peek, "`NORMAL`, `RELATION`, or `ANY`"),
+						_ => {
+							unexpected!(self, peek, "`NORMAL`, `RELATION`, `TIMESERIES`, or `ANY`")
+						}
 					}
 				}
 				t!("SCHEMALESS") => {
@@ -738,6 +817,14 @@ impl Parser<'_> {
 					self.pop_peek();
 					res.changefeed = Some(self.parse_changefeed()?);
 				}
+				t!("EXPIRE") => {
+					self.pop_peek();
+					res.expire = Some(self.next_token_value()?);
+				}
+				t!("ASSERT") => {
+					self.pop_peek();
+					res.assert = Some(ctx.run(|ctx| self.parse_value_field(ctx)).await?);
+				}
 				t!("AS") => {
 					self.pop_peek();
 					let peek = self.peek();
@@ -792,7 +879,19 @@ impl Parser<'_> {
 			match self.peek_kind() {
 				t!("WHEN") => {
 					self.pop_peek();
-					res.when = ctx.run(|ctx| self.parse_value_field(ctx)).await?;
+					if self.peek_kind() == t!("SCHEDULE")
+						&& matches!(self.peek1().kind, t!("'") | t!("\""))
+					{
+						self.pop_peek();
+						let span = self.peek().span;
+						let schedule: Strand = self.next_token_value()?;
+						if let Err(e) = schedule.parse::<Schedule>() {
+							bail!("Invalid schedule expression: {e}", @span);
+						}
+						res.schedule = Some(schedule);
+					} else {
+						res.when = ctx.run(|ctx| self.parse_value_field(ctx)).await?;
+					}
 				}
 				t!("THEN") => {
 					self.pop_peek();
@@ -849,6 +948,10 @@ impl Parser<'_> {
 					self.pop_peek();
 					res.readonly = true;
 				}
+				t!("ENCRYPTED") => {
+					self.pop_peek();
+					res.encrypted = true;
+				}
 				t!("VALUE") => {
 					self.pop_peek();
 					res.value = Some(ctx.run(|ctx| self.parse_value_field(ctx)).await?);
@@ -865,6 +968,10 @@ impl Parser<'_> {
 					self.pop_peek();
 					res.permissions = ctx.run(|ctx| self.parse_permission(ctx, true)).await?;
 				}
+				t!("REFERENCE") => {
+					self.pop_peek();
+					res.reference = Some(self.parse_reference()?);
+				}
 				t!("COMMENT") => {
 					self.pop_peek();
 					res.comment = Some(self.next_token_value()?);
@@ -876,6 +983,22 @@ impl Parser<'_> {
 		Ok(res)
 	}
 
+	pub fn parse_reference(&mut self) -> ParseResult<Reference> {
+		let mut res = Reference::default();
+		if self.eat(t!("ON")) {
+			expected!(self, t!("DELETE"));
+			let next = self.next();
+			res.on_delete = match next.kind {
+				t!("IGNORE") => ReferenceDeleteStrategy::Ignore,
+				t!("REJECT") => ReferenceDeleteStrategy::Reject,
+				t!("CASCADE") => ReferenceDeleteStrategy::Cascade,
+				t!("UNSET") => ReferenceDeleteStrategy::Unset,
+				_ => unexpected!(self, next, "`IGNORE`, `REJECT`, `CASCADE`, or `UNSET`"),
+			};
+		}
+		Ok(res)
+	}
+
 	pub async fn parse_define_index(&mut self, ctx: &mut Stk) -> ParseResult<DefineIndexStatement> {
 		let (if_not_exists, overwrite) = if self.eat(t!("IF")) {
 			expected!(self, t!("NOT"));
@@ -900,6 +1023,7 @@ impl Parser<'_> {
 			..Default::default()
 		};
 
+		let mut desc = None;
 		loop {
 			match self.peek_kind() {
 				// COLUMNS and FIELDS are the same tokenkind
@@ -909,10 +1033,20 @@ impl Parser<'_> {
 					while self.eat(t!(",")) {
 						res.cols.0.push(self.parse_local_idiom(ctx).await?);
 					}
+					if self.peek_kind() == t!("DESCENDING") {
+						desc = Some(self.pop_peek().span);
+						res.desc = true;
+					}
 				}
 				t!("UNIQUE") => {
 					self.pop_peek();
 					res.index = Index::Uniq;
+					if self.eat(t!("NULLS")) {
+						if self.eat(t!("NOT")) {
+							res.index = Index::UniqNullsNotDistinct;
+						}
+						expected!(self, t!("DISTINCT"));
+					}
 				}
 				t!("SEARCH") => {
 					self.pop_peek();
@@ -1136,6 +1270,12 @@ impl Parser<'_> {
 			}
 		}
 
+		if let Some(span) = desc {
+			if !matches!(res.index, Index::Idx | Index::Uniq | Index::UniqNullsNotDistinct) {
+				bail!("Only standard and unique indexes can be stored in descending order", @span);
+			}
+		}
+
 		Ok(res)
 	}
 
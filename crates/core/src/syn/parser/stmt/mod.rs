@@ -13,22 +13,23 @@ use crate::sql::statements::{
 	KillStatement, LiveStatement, OptionStatement, SetStatement, ThrowStatement,
 };
 use crate::sql::{Duration, Fields, Ident, Param};
+use crate::syn::error::SyntaxError;
 use crate::syn::lexer::compound;
 use crate::syn::parser::enter_query_recursion;
-use crate::syn::token::{t, Glued, TokenKind};
+use crate::syn::token::{t, Glued, Span, TokenKind};
 use crate::{
 	sql::{
 		statements::{
 			analyze::AnalyzeStatement, BeginStatement, BreakStatement, CancelStatement,
 			CommitStatement, ContinueStatement, ForeachStatement, InfoStatement, OutputStatement,
-			UseStatement,
+			ReleaseStatement, RollbackStatement, SavepointStatement, UseStatement,
 		},
 		Expression, Operator, Statement, Statements, Value,
 	},
 	syn::parser::mac::unexpected,
 };
 
-use super::{mac::expected, ParseResult, Parser};
+use super::{mac::expected, GluedValue, ParseResult, Parser};
 
 mod alter;
 mod create;
@@ -46,6 +47,7 @@ mod upsert;
 impl Parser<'_> {
 	pub(super) async fn parse_stmt_list(&mut self, ctx: &mut Stk) -> ParseResult<Statements> {
 		let mut res = Vec::new();
+		let mut errors = Vec::new();
 		loop {
 			match self.peek_kind() {
 				// consume any possible empty statements.
@@ -55,25 +57,68 @@ impl Parser<'_> {
 				}
 				t!("eof") => break,
 				_ => {
-					let stmt = ctx.run(|ctx| self.parse_stmt(ctx)).await?;
-					res.push(stmt);
-					if !self.eat(t!(";")) {
-						if self.eat(t!("eof")) {
-							break;
-						}
-
-						let token = self.peek();
-						if Self::kind_starts_statement(token.kind) {
-							// user likely forgot a semicolon.
-							unexpected!(self,token,"the query to end", => "maybe forgot a semicolon  after the previous statement?");
+					let start = self.peek().span;
+					match ctx.run(|ctx| self.parse_terminated_stmt(ctx)).await {
+						Ok(stmt) => res.push(stmt),
+						Err(e) => {
+							errors.push(e);
+							// Continue with the next statement, so that all errors are reported.
+							if !self.recover_stmt(start) {
+								break;
+							}
 						}
-
-						expected!(self, t!("eof"));
 					}
 				}
 			}
 		}
-		Ok(Statements(res))
+		match SyntaxError::from_errors(errors) {
+			Some(e) => Err(e),
+			None => Ok(Statements(res)),
+		}
+	}
+
+	/// Parse a statement which is followed by either a semicolon or the end of the query.
+	async fn parse_terminated_stmt(&mut self, ctx: &mut Stk) -> ParseResult<Statement> {
+		let stmt = self.parse_stmt(ctx).await?;
+		if !self.eat(t!(";")) {
+			let token = self.peek();
+			if token.kind == t!("eof") {
+				return Ok(stmt);
+			}
+
+			if Self::kind_starts_statement(token.kind) {
+				// user likely forgot a semicolon.
+				unexpected!(self,token,"the query to end", => "maybe forgot a semicolon  after the previous statement?");
+			}
+
+			expected!(self, t!("eof"));
+		}
+		Ok(stmt)
+	}
+
+	/// Move the parser past a statement which failed to parse, to the semicolon ending the
+	/// statement, so that parsing can continue with the next statement.
+	///
+	/// Returns false if the parser could not move past the statement.
+	fn recover_stmt(&mut self, start: Span) -> bool {
+		// Skip the statement from its start, so delimiters are matched from the same depth.
+		self.reset();
+		self.glued_value = GluedValue::None;
+		self.lexer.backup_before(start);
+		let mut depth = 0usize;
+		loop {
+			let token = self.next();
+			match token.kind {
+				TokenKind::OpenDelim(_) => depth += 1,
+				TokenKind::CloseDelim(_) => depth = depth.saturating_sub(1),
+				t!(";") if depth == 0 => return true,
+				t!("eof") => return true,
+				// The lexer didn't make progress, so neither would the parser.
+				TokenKind::Invalid if token.span.len == 0 => return false,
+				TokenKind::Invalid => self.lexer.error = None,
+				_ => {}
+			}
+		}
 	}
 
 	pub(super) async fn parse_stmt(&mut self, ctx: &mut Stk) -> ParseResult<Statement> {
@@ -177,10 +222,22 @@ impl Parser<'_> {
 				self.pop_peek();
 				ctx.run(|ctx| self.parse_relate_stmt(ctx)).await.map(Statement::Relate)
 			}
+			t!("RELEASE") => {
+				self.pop_peek();
+				self.parse_release().map(Statement::Release)
+			}
 			t!("REMOVE") => {
 				self.pop_peek();
 				ctx.run(|ctx| self.parse_remove_stmt(ctx)).await.map(Statement::Remove)
 			}
+			t!("ROLLBACK") => {
+				self.pop_peek();
+				self.parse_rollback().map(Statement::Rollback)
+			}
+			t!("SAVEPOINT") => {
+				self.pop_peek();
+				self.parse_savepoint().map(Statement::Savepoint)
+			}
 			t!("SELECT") => {
 				self.pop_peek();
 				ctx.run(|ctx| self.parse_select_stmt(ctx)).await.map(Statement::Select)
@@ -278,10 +335,22 @@ impl Parser<'_> {
 				self.pop_peek();
 				self.parse_relate_stmt(ctx).await.map(Entry::Relate)
 			}
+			t!("RELEASE") => {
+				self.pop_peek();
+				self.parse_release().map(Entry::Release)
+			}
 			t!("REMOVE") => {
 				self.pop_peek();
 				self.parse_remove_stmt(ctx).await.map(Entry::Remove)
 			}
+			t!("ROLLBACK") => {
+				self.pop_peek();
+				self.parse_rollback().map(Entry::Rollback)
+			}
+			t!("SAVEPOINT") => {
+				self.pop_peek();
+				self.parse_savepoint().map(Entry::Savepoint)
+			}
 			t!("SELECT") => {
 				self.pop_peek();
 				self.parse_select_stmt(ctx).await.map(Entry::Select)
@@ -492,6 +561,10 @@ impl Parser<'_> {
 
 	/// Parsers a analyze statement.
 	fn parse_analyze(&mut self) -> ParseResult<AnalyzeStatement> {
+		if self.eat(t!("TABLE")) {
+			let table = self.next_token_value()?;
+			return Ok(AnalyzeStatement::Tb(table));
+		}
 		expected!(self, t!("INDEX"));
 
 		let index = self.next_token_value()?;
@@ -506,10 +579,14 @@ impl Parser<'_> {
 	/// # Parser State
 	/// Expects `BEGIN` to already be consumed.
 	fn parse_begin(&mut self) -> ParseResult<BeginStatement> {
+		let readonly = self.eat(t!("READONLY"));
 		if let t!("TRANSACTION") = self.peek().kind {
 			self.next();
 		}
-		Ok(BeginStatement)
+		let readonly = readonly || self.eat(t!("READONLY"));
+		Ok(BeginStatement {
+			readonly,
+		})
 	}
 
 	/// Parsers a cancel statement.
@@ -534,6 +611,46 @@ impl Parser<'_> {
 		Ok(CommitStatement)
 	}
 
+	/// Parsers a savepoint statement.
+	///
+	/// # Parser State
+	/// Expects `SAVEPOINT` to already be consumed.
+	fn parse_savepoint(&mut self) -> ParseResult<SavepointStatement> {
+		let name = self.next_token_value()?;
+		Ok(SavepointStatement {
+			name,
+		})
+	}
+
+	/// Parsers a rollback statement.
+	///
+	/// # Parser State
+	/// Expects `ROLLBACK` to already be consumed.
+	fn parse_rollback(&mut self) -> ParseResult<RollbackStatement> {
+		expected!(self, t!("TO"));
+		if let t!("SAVEPOINT") = self.peek().kind {
+			self.next();
+		}
+		let name = self.next_token_value()?;
+		Ok(RollbackStatement {
+			name,
+		})
+	}
+
+	/// Parsers a release statement.
+	///
+	/// # Parser State
+	/// Expects `RELEASE` to already be consumed.
+	fn parse_release(&mut self) -> ParseResult<ReleaseStatement> {
+		if let t!("SAVEPOINT") = self.peek().kind {
+			self.next();
+		}
+		let name = self.next_token_value()?;
+		Ok(ReleaseStatement {
+			name,
+		})
+	}
+
 	/// Parsers a USE statement.
 	///
 	/// # Parser State
@@ -610,6 +727,11 @@ impl Parser<'_> {
 				let table = self.next_token_value()?;
 				InfoStatement::Index(index, table, false)
 			}
+			t!("SLOW") => {
+				expected!(self, t!("QUERIES"));
+				InfoStatement::SlowQueries(false)
+			}
+			t!("CONNECTIONS") => InfoStatement::Connections(false),
 			_ => unexpected!(self, next, "an info target"),
 		};
 
@@ -629,16 +751,22 @@ impl Parser<'_> {
 	/// # Parser State
 	/// Expects `KILL` to already be consumed.
 	pub(super) fn parse_kill_stmt(&mut self) -> ParseResult<KillStatement> {
+		let connection = self.eat(t!("CONNECTION"));
 		let peek = self.peek();
 		let id = match peek.kind {
 			t!("u\"") | t!("u'") | TokenKind::Glued(Glued::Uuid) => {
 				self.next_token_value().map(Value::Uuid)?
 			}
 			t!("$param") => self.next_token_value().map(Value::Param)?,
+			t!("\"") | t!("'") | TokenKind::Glued(Glued::Strand) if connection => {
+				self.next_token_value().map(Value::Strand)?
+			}
+			_ if connection => unexpected!(self, peek, "a UUID, a string, or a parameter"),
 			_ => unexpected!(self, peek, "a UUID or a parameter"),
 		};
 		Ok(KillStatement {
 			id,
+			connection,
 		})
 	}
 
@@ -8,8 +8,8 @@ use crate::{
 	sql::{
 		changefeed::ChangeFeed,
 		index::{Distance, VectorType},
-		Base, Cond, Data, Duration, Fetchs, Field, Fields, Group, Groups, Ident, Idiom, Output,
-		Permission, Permissions, Tables, Timeout, Value, View,
+		Base, Cond, Data, Duration, Fetchs, Field, Fields, Group, Groups, Ident, Idiom,
+		MergeStrategy, Output, Permission, Permissions, Tables, Timeout, Value, View,
 	},
 	syn::{
 		parser::{
@@ -56,7 +56,12 @@ impl Parser<'_> {
 			}
 			t!("MERGE") => {
 				self.pop_peek();
-				Data::MergeExpression(ctx.run(|ctx| self.parse_value_field(ctx)).await?)
+				let value = ctx.run(|ctx| self.parse_value_field(ctx)).await?;
+				if self.eat(t!("ARRAYS")) {
+					Data::MergeWithExpression(value, self.parse_merge_strategy(ctx).await?)
+				} else {
+					Data::MergeExpression(value)
+				}
 			}
 			t!("REPLACE") => {
 				self.pop_peek();
@@ -71,6 +76,21 @@ impl Parser<'_> {
 		Ok(Some(res))
 	}
 
+	/// Parses the strategy for merging arrays after the `ARRAYS` keyword.
+	pub async fn parse_merge_strategy(&mut self, ctx: &mut Stk) -> ParseResult<MergeStrategy> {
+		let next = self.next();
+		let res = match next.kind {
+			t!("REPLACE") => MergeStrategy::Replace,
+			t!("APPEND") => MergeStrategy::Append,
+			t!("UNION") => match self.eat(t!("BY")) {
+				true => MergeStrategy::Union(Some(self.parse_local_idiom(ctx).await?)),
+				false => MergeStrategy::Union(None),
+			},
+			_ => unexpected!(self, next, "`REPLACE`, `APPEND`, or `UNION`"),
+		};
+		Ok(res)
+	}
+
 	/// Parses a statement output if the next token is `return`.
 	pub async fn try_parse_output(&mut self, ctx: &mut Stk) -> ParseResult<Option<Output>> {
 		if !self.eat(t!("RETURN")) {
@@ -5,8 +5,8 @@ use crate::{
 		statements::{
 			remove::RemoveAnalyzerStatement, RemoveAccessStatement, RemoveDatabaseStatement,
 			RemoveEventStatement, RemoveFieldStatement, RemoveFunctionStatement,
-			RemoveIndexStatement, RemoveNamespaceStatement, RemoveParamStatement, RemoveStatement,
-			RemoveUserStatement,
+			RemoveIndexStatement, RemoveNamespaceStatement, RemoveParamStatement,
+			RemoveSequenceStatement, RemoveStatement, RemoveUserStatement,
 		},
 		Param,
 	},
@@ -118,6 +118,20 @@ impl Parser<'_> {
 					if_exists,
 				})
 			}
+			t!("SEQUENCE") => {
+				let if_exists = if self.eat(t!("IF")) {
+					expected!(self, t!("EXISTS"));
+					true
+				} else {
+					false
+				};
+				let name = self.next_token_value()?;
+
+				RemoveStatement::Sequence(RemoveSequenceStatement {
+					name,
+					if_exists,
+				})
+			}
 			t!("TABLE") => {
 				let expunge = if self.eat(t!("AND")) {
 					expected!(self, t!("EXPUNGE"));
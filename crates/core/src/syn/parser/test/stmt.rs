@@ -19,26 +19,29 @@ use crate::{
 			analyze::AnalyzeStatement,
 			show::{ShowSince, ShowStatement},
 			sleep::SleepStatement,
-			AccessStatement, BeginStatement, BreakStatement, CancelStatement, CommitStatement,
-			ContinueStatement, CreateStatement, DefineAccessStatement, DefineAnalyzerStatement,
-			DefineDatabaseStatement, DefineEventStatement, DefineFieldStatement,
-			DefineFunctionStatement, DefineIndexStatement, DefineNamespaceStatement,
-			DefineParamStatement, DefineStatement, DefineTableStatement, DeleteStatement,
-			ForeachStatement, IfelseStatement, InfoStatement, InsertStatement, KillStatement,
-			OptionStatement, OutputStatement, RelateStatement, RemoveAccessStatement,
-			RemoveAnalyzerStatement, RemoveDatabaseStatement, RemoveEventStatement,
-			RemoveFieldStatement, RemoveFunctionStatement, RemoveIndexStatement,
-			RemoveNamespaceStatement, RemoveParamStatement, RemoveStatement, RemoveTableStatement,
-			RemoveUserStatement, SelectStatement, SetStatement, ThrowStatement, UpdateStatement,
-			UpsertStatement, UseStatement,
+			AccessStatement, AlterStatement, BeginStatement, BreakStatement, CancelStatement,
+			CommitStatement, ContinueStatement, CreateStatement, DefineAccessStatement,
+			DefineAnalyzerStatement, DefineDatabaseStatement, DefineEventStatement,
+			DefineFieldStatement, DefineFunctionStatement, DefineIndexStatement,
+			DefineNamespaceStatement, DefineParamStatement, DefineSequenceStatement,
+			DefineStatement, DefineTableStatement, DeleteStatement, ForeachStatement,
+			IfelseStatement, InfoStatement, InsertStatement, KillStatement, NamespaceLimits,
+			OptionStatement, OutputStatement, RelateStatement, ReleaseStatement,
+			RemoveAccessStatement, RemoveAnalyzerStatement, RemoveDatabaseStatement,
+			RemoveEventStatement, RemoveFieldStatement, RemoveFunctionStatement,
+			RemoveIndexStatement, RemoveNamespaceStatement, RemoveParamStatement,
+			RemoveSequenceStatement, RemoveStatement, RemoveTableStatement, RemoveUserStatement,
+			RollbackStatement, SavepointStatement, SelectStatement, SetStatement, ThrowStatement,
+			UpdateStatement, UpsertStatement, UseStatement,
 		},
 		tokenizer::Tokenizer,
 		user::UserDuration,
 		Algorithm, Array, Base, Block, Cond, Data, Datetime, Dir, Duration, Edges, Explain,
 		Expression, Fetch, Fetchs, Field, Fields, Future, Graph, Group, Groups, Id, Ident, Idiom,
-		Idioms, Index, Kind, Limit, Number, Object, Operator, Order, Output, Param, Part,
-		Permission, Permissions, Scoring, Split, Splits, Start, Statement, Strand, Subquery, Table,
-		TableType, Tables, Thing, Timeout, Uuid, Value, Values, Version, With,
+		Idioms, Index, Kind, Limit, MergeStrategy, Number, Object, Operator, Order, Output, Param,
+		Part, Permission, Permissions, Reference, ReferenceDeleteStrategy, Scoring, Split, Splits,
+		Start, Statement, Strand, Subquery, Table, TableType, Tables, Thing, Timeout, Uuid, Value,
+		Values, Version, With,
 	},
 	syn::parser::mac::test_parse,
 };
@@ -54,15 +57,24 @@ pub fn parse_analyze() {
 	assert_eq!(
 		res,
 		Statement::Analyze(AnalyzeStatement::Idx(Ident("a".to_string()), Ident("b".to_string())))
-	)
+	);
+	let res = test_parse!(parse_stmt, r#"ANALYZE TABLE a"#).unwrap();
+	assert_eq!(res, Statement::Analyze(AnalyzeStatement::Tb(Ident("a".to_string()))))
 }
 
 #[test]
 pub fn parse_begin() {
 	let res = test_parse!(parse_stmt, r#"BEGIN"#).unwrap();
-	assert_eq!(res, Statement::Begin(BeginStatement));
+	assert_eq!(res, Statement::Begin(BeginStatement::default()));
 	let res = test_parse!(parse_stmt, r#"BEGIN TRANSACTION"#).unwrap();
-	assert_eq!(res, Statement::Begin(BeginStatement));
+	assert_eq!(res, Statement::Begin(BeginStatement::default()));
+	let readonly = Statement::Begin(BeginStatement {
+		readonly: true,
+	});
+	let res = test_parse!(parse_stmt, r#"BEGIN READONLY"#).unwrap();
+	assert_eq!(res, readonly);
+	let res = test_parse!(parse_stmt, r#"BEGIN TRANSACTION READONLY"#).unwrap();
+	assert_eq!(res, readonly);
 }
 
 #[test]
@@ -87,6 +99,53 @@ pub fn parse_commit() {
 	assert_eq!(res, Statement::Commit(CommitStatement));
 }
 
+#[test]
+pub fn parse_savepoint() {
+	let res = test_parse!(parse_stmt, r#"SAVEPOINT a"#).unwrap();
+	assert_eq!(
+		res,
+		Statement::Savepoint(SavepointStatement {
+			name: Ident("a".to_string()),
+		})
+	);
+}
+
+#[test]
+pub fn parse_rollback() {
+	let res = test_parse!(parse_stmt, r#"ROLLBACK TO a"#).unwrap();
+	assert_eq!(
+		res,
+		Statement::Rollback(RollbackStatement {
+			name: Ident("a".to_string()),
+		})
+	);
+	let res = test_parse!(parse_stmt, r#"ROLLBACK TO SAVEPOINT a"#).unwrap();
+	assert_eq!(
+		res,
+		Statement::Rollback(RollbackStatement {
+			name: Ident("a".to_string()),
+		})
+	);
+}
+
+#[test]
+pub fn parse_release() {
+	let res = test_parse!(parse_stmt, r#"RELEASE a"#).unwrap();
+	assert_eq!(
+		res,
+		Statement::Release(ReleaseStatement {
+			name: Ident("a".to_string()),
+		})
+	);
+	let res = test_parse!(parse_stmt, r#"RELEASE SAVEPOINT a"#).unwrap();
+	assert_eq!(
+		res,
+		Statement::Release(ReleaseStatement {
+			name: Ident("a".to_string()),
+		})
+	);
+}
+
 #[test]
 pub fn parse_continue() {
 	let res = test_parse!(parse_stmt, r#"CONTINUE"#).unwrap();
@@ -142,6 +201,7 @@ fn parse_define_namespace() {
 			comment: Some(Strand("test".to_string())),
 			if_not_exists: false,
 			overwrite: false,
+			limits: NamespaceLimits::default(),
 		}))
 	);
 
@@ -154,6 +214,24 @@ fn parse_define_namespace() {
 			comment: None,
 			if_not_exists: false,
 			overwrite: false,
+			limits: NamespaceLimits::default(),
+		}))
+	);
+
+	let res = test_parse!(parse_stmt, "DEFINE NS a LIMIT QUERIES 10, LIVE 100, RATE 50").unwrap();
+	assert_eq!(
+		res,
+		Statement::Define(DefineStatement::Namespace(DefineNamespaceStatement {
+			id: None,
+			name: Ident("a".to_string()),
+			comment: None,
+			if_not_exists: false,
+			overwrite: false,
+			limits: NamespaceLimits {
+				queries: Some(10),
+				live: Some(100),
+				rate: Some(50),
+			},
 		}))
 	)
 }
@@ -248,6 +326,7 @@ fn parse_define_user() {
 			UserDuration {
 				token: Some(Duration::from_hours(1)),
 				session: None,
+				password: None,
 			}
 		);
 	}
@@ -273,6 +352,7 @@ fn parse_define_user() {
 			UserDuration {
 				token: Some(Duration::from_hours(1)),
 				session: None,
+				password: None,
 			}
 		);
 	}
@@ -297,6 +377,7 @@ fn parse_define_user() {
 			UserDuration {
 				token: Some(Duration::from_hours(1)),
 				session: None,
+				password: None,
 			}
 		);
 	}
@@ -321,6 +402,7 @@ fn parse_define_user() {
 			UserDuration {
 				token: Some(Duration::from_hours(1)),
 				session: Some(Duration::from_hours(6)),
+				password: None,
 			}
 		);
 	}
@@ -345,6 +427,28 @@ fn parse_define_user() {
 			UserDuration {
 				token: Some(Duration::from_mins(15)),
 				session: Some(Duration::from_hours(6)),
+				password: None,
+			}
+		);
+	}
+	// With password duration.
+	{
+		let res = test_parse!(
+			parse_stmt,
+			r#"DEFINE USER user ON ROOT PASSHASH 'hunter2' DURATION FOR SESSION 6h, FOR PASSWORD 90d"#
+		)
+		.unwrap();
+
+		let Statement::Define(DefineStatement::User(stmt)) = res else {
+			panic!()
+		};
+
+		assert_eq!(
+			stmt.duration,
+			UserDuration {
+				token: Some(Duration::from_hours(1)),
+				session: Some(Duration::from_hours(6)),
+				password: Some(Duration::from_days(90)),
 			}
 		);
 	}
@@ -386,6 +490,33 @@ fn parse_define_user() {
 	}
 }
 
+#[test]
+fn parse_alter_user() {
+	// With existent roles.
+	{
+		let res =
+			test_parse!(parse_stmt, r#"ALTER USER user ON ROOT ROLES Viewer, editor"#).unwrap();
+
+		let Statement::Alter(AlterStatement::User(stmt)) = res else {
+			panic!()
+		};
+
+		assert_eq!(
+			stmt.roles,
+			Some(vec![Ident("Viewer".to_string()), Ident("editor".to_string())])
+		);
+	}
+	// With nonexistent role.
+	{
+		let res = test_parse!(parse_stmt, r#"ALTER USER user ON ROOT ROLES Viewer, foo"#);
+		assert!(
+			res.is_err(),
+			"Unexpected successful parsing of user with nonexistent role: {:?}",
+			res
+		);
+	}
+}
+
 // TODO(gguillemas): This test is kept in 2.0.0 for backward compatibility. Drop in 3.0.0.
 #[test]
 fn parse_define_token() {
@@ -1625,6 +1756,34 @@ fn parse_define_param() {
 	);
 }
 
+#[test]
+fn parse_define_sequence() {
+	let res =
+		test_parse!(parse_stmt, r#"DEFINE SEQUENCE invoice START -10 COMMENT "test""#).unwrap();
+	assert_eq!(
+		res,
+		Statement::Define(DefineStatement::Sequence(DefineSequenceStatement {
+			name: Ident("invoice".to_string()),
+			start: -10,
+			comment: Some(Strand("test".to_string())),
+			if_not_exists: false,
+			overwrite: false,
+		}))
+	);
+
+	let res = test_parse!(parse_stmt, r#"DEFINE SEQUENCE IF NOT EXISTS invoice"#).unwrap();
+	assert_eq!(
+		res,
+		Statement::Define(DefineStatement::Sequence(DefineSequenceStatement {
+			name: Ident("invoice".to_string()),
+			start: 1,
+			comment: None,
+			if_not_exists: true,
+			overwrite: false,
+		}))
+	);
+}
+
 #[test]
 fn parse_define_table() {
 	let res =
@@ -1675,6 +1834,28 @@ fn parse_define_table() {
 			cache_tables_ts: uuid::Uuid::default(),
 			cache_indexes_ts: uuid::Uuid::default(),
 			cache_lives_ts: uuid::Uuid::default(),
+			expire: None,
+			assert: None,
+		}))
+	);
+}
+
+#[test]
+fn parse_define_table_timeseries() {
+	let res =
+		test_parse!(parse_stmt, r#"DEFINE TABLE metric TYPE TIMESERIES TIMESTAMP ts EXPIRE 1d"#)
+			.unwrap();
+
+	assert_eq!(
+		res,
+		Statement::Define(DefineStatement::Table(DefineTableStatement {
+			name: Ident("metric".to_owned()),
+			kind: TableType::TimeSeries(crate::sql::TimeSeries {
+				timestamp: Idiom(vec![Part::Field(Ident("ts".to_owned()))]),
+			}),
+			expire: Some(Duration(std::time::Duration::from_secs(86400))),
+			permissions: Permissions::none(),
+			..Default::default()
 		}))
 	);
 }
@@ -1695,10 +1876,40 @@ fn parse_define_event() {
 			comment: None,
 			if_not_exists: false,
 			overwrite: false,
+			schedule: None,
 		}))
 	)
 }
 
+#[test]
+fn parse_define_event_schedule() {
+	let res = test_parse!(
+		parse_stmt,
+		r#"DEFINE EVENT event ON TABLE table WHEN SCHEDULE '*/5 * * * *' THEN null"#
+	)
+	.unwrap();
+
+	assert_eq!(
+		res,
+		Statement::Define(DefineStatement::Event(DefineEventStatement {
+			name: Ident("event".to_owned()),
+			what: Ident("table".to_owned()),
+			when: Value::Bool(true),
+			then: Values(vec![Value::Null]),
+			comment: None,
+			if_not_exists: false,
+			overwrite: false,
+			schedule: Some(Strand("*/5 * * * *".to_owned())),
+		}))
+	);
+
+	let res = test_parse!(
+		parse_stmt,
+		r#"DEFINE EVENT event ON TABLE table WHEN SCHEDULE '61 * * * *' THEN null"#
+	);
+	assert!(res.is_err(), "Unexpected successful parsing of invalid schedule: {:?}", res);
+}
+
 #[test]
 fn parse_define_field() {
 	// General
@@ -1736,6 +1947,8 @@ fn parse_define_field() {
 				comment: None,
 				if_not_exists: false,
 				overwrite: false,
+				reference: None,
+				encrypted: false,
 			}))
 		)
 	}
@@ -1768,6 +1981,57 @@ fn parse_define_field() {
 				comment: None,
 				if_not_exists: false,
 				overwrite: false,
+				reference: None,
+				encrypted: false,
+			}))
+		)
+	}
+
+	// Record references
+	{
+		let res = test_parse!(
+			parse_stmt,
+			r#"DEFINE FIELD author ON TABLE book TYPE record<person> REFERENCE ON DELETE CASCADE"#
+		)
+		.unwrap();
+
+		assert_eq!(
+			res,
+			Statement::Define(DefineStatement::Field(DefineFieldStatement {
+				name: Idiom(vec![Part::Field(Ident("author".to_owned()))]),
+				what: Ident("book".to_owned()),
+				kind: Some(Kind::Record(vec![Table("person".to_owned())])),
+				reference: Some(Reference {
+					on_delete: ReferenceDeleteStrategy::Cascade,
+				}),
+				..Default::default()
+			}))
+		)
+	}
+
+	// Invalid ON DELETE strategy
+	{
+		let res = test_parse!(
+			parse_stmt,
+			r#"DEFINE FIELD author ON TABLE book TYPE record<person> REFERENCE ON DELETE NOTHING"#
+		);
+		assert!(res.is_err(), "Unexpected successful parsing of invalid strategy: {:?}", res);
+	}
+
+	// Encrypted fields
+	{
+		let res =
+			test_parse!(parse_stmt, r#"DEFINE FIELD ssn ON TABLE person TYPE string ENCRYPTED"#)
+				.unwrap();
+
+		assert_eq!(
+			res,
+			Statement::Define(DefineStatement::Field(DefineFieldStatement {
+				name: Idiom(vec![Part::Field(Ident("ssn".to_owned()))]),
+				what: Ident("person".to_owned()),
+				kind: Some(Kind::String),
+				encrypted: true,
+				..Default::default()
 			}))
 		)
 	}
@@ -1818,7 +2082,8 @@ fn parse_define_index() {
 			comment: None,
 			if_not_exists: false,
 			overwrite: false,
-			concurrently: false
+			concurrently: false,
+			desc: false,
 		}))
 	);
 
@@ -1835,7 +2100,54 @@ fn parse_define_index() {
 			comment: None,
 			if_not_exists: false,
 			overwrite: false,
-			concurrently: false
+			concurrently: false,
+			desc: false,
+		}))
+	);
+
+	let res = test_parse!(parse_stmt, r#"DEFINE INDEX index ON TABLE table FIELDS a DESC UNIQUE"#)
+		.unwrap();
+
+	assert_eq!(
+		res,
+		Statement::Define(DefineStatement::Index(DefineIndexStatement {
+			name: Ident("index".to_owned()),
+			what: Ident("table".to_owned()),
+			cols: Idioms(vec![Idiom(vec![Part::Field(Ident("a".to_owned()))]),]),
+			index: Index::Uniq,
+			comment: None,
+			if_not_exists: false,
+			overwrite: false,
+			concurrently: false,
+			desc: true,
+		}))
+	);
+
+	// Only standard and unique indexes can be descending
+	let res = test_parse!(
+		parse_stmt,
+		r#"DEFINE INDEX index ON TABLE table FIELDS a DESC SEARCH ANALYZER ana"#
+	);
+	assert!(res.is_err(), "Unexpected successful parsing of descending search index: {:?}", res);
+
+	let res = test_parse!(
+		parse_stmt,
+		r#"DEFINE INDEX index ON TABLE table FIELDS a UNIQUE NULLS NOT DISTINCT"#
+	)
+	.unwrap();
+
+	assert_eq!(
+		res,
+		Statement::Define(DefineStatement::Index(DefineIndexStatement {
+			name: Ident("index".to_owned()),
+			what: Ident("table".to_owned()),
+			cols: Idioms(vec![Idiom(vec![Part::Field(Ident("a".to_owned()))]),]),
+			index: Index::UniqNullsNotDistinct,
+			comment: None,
+			if_not_exists: false,
+			overwrite: false,
+			concurrently: false,
+			desc: false,
 		}))
 	);
 
@@ -1860,7 +2172,8 @@ fn parse_define_index() {
 			comment: None,
 			if_not_exists: false,
 			overwrite: false,
-			concurrently: false
+			concurrently: false,
+			desc: false,
 		}))
 	);
 
@@ -1887,7 +2200,8 @@ fn parse_define_index() {
 			comment: None,
 			if_not_exists: false,
 			overwrite: false,
-			concurrently: false
+			concurrently: false,
+			desc: false,
 		}))
 	);
 }
@@ -2074,6 +2388,12 @@ fn parse_info() {
 		res,
 		Statement::Info(InfoStatement::User(Ident("user".to_owned()), Some(Base::Ns), false))
 	);
+
+	let res = test_parse!(parse_stmt, "INFO FOR SLOW QUERIES").unwrap();
+	assert_eq!(res, Statement::Info(InfoStatement::SlowQueries(false)));
+
+	let res = test_parse!(parse_stmt, "INFO FOR CONNECTIONS").unwrap();
+	assert_eq!(res, Statement::Info(InfoStatement::Connections(false)));
 }
 
 #[test]
@@ -2169,6 +2489,29 @@ SELECT bar as foo,[1,2],bar OMIT bar FROM ONLY a,1
 	);
 }
 
+#[test]
+fn parse_select_qualified() {
+	let res = test_parse!(parse_stmt, r#"SELECT * FROM ns:acme.db:prod.person, ns:acme"#).unwrap();
+	assert_eq!(
+		res,
+		Statement::Select(SelectStatement {
+			expr: Fields::all(),
+			what: Values(vec![
+				Value::Qualified(Box::new(crate::sql::Qualified {
+					ns: Ident("acme".to_owned()),
+					db: Ident("prod".to_owned()),
+					what: Table("person".to_owned()),
+				})),
+				Value::Thing(Thing {
+					tb: "ns".to_owned(),
+					id: Id::String("acme".to_owned()),
+				}),
+			]),
+			..Default::default()
+		}),
+	);
+}
+
 #[test]
 fn parse_let() {
 	let res = test_parse!(parse_stmt, r#"LET $param = 1"#).unwrap();
@@ -2426,7 +2769,8 @@ fn parse_kill() {
 	assert_eq!(
 		res,
 		Statement::Kill(KillStatement {
-			id: Value::Param(Param(Ident("param".to_owned())))
+			id: Value::Param(Param(Ident("param".to_owned()))),
+			connection: false,
 		})
 	);
 
@@ -2434,7 +2778,29 @@ fn parse_kill() {
 	assert_eq!(
 		res,
 		Statement::Kill(KillStatement {
-			id: Value::Uuid(Uuid(uuid::uuid!("e72bee20-f49b-11ec-b939-0242ac120002")))
+			id: Value::Uuid(Uuid(uuid::uuid!("e72bee20-f49b-11ec-b939-0242ac120002"))),
+			connection: false,
+		})
+	);
+}
+
+#[test]
+fn parse_kill_connection() {
+	let res = test_parse!(parse_stmt, r#"KILL CONNECTION "one""#).unwrap();
+	assert_eq!(
+		res,
+		Statement::Kill(KillStatement {
+			id: Value::Strand(Strand("one".to_owned())),
+			connection: true,
+		})
+	);
+
+	let res = test_parse!(parse_stmt, r#"KILL CONNECTION $id"#).unwrap();
+	assert_eq!(
+		res,
+		Statement::Kill(KillStatement {
+			id: Value::Param(Param(Ident("id".to_owned()))),
+			connection: true,
 		})
 	);
 }
@@ -2601,6 +2967,15 @@ fn parse_remove() {
 		}))
 	);
 
+	let res = test_parse!(parse_stmt, r#"REMOVE SEQUENCE IF EXISTS foo"#).unwrap();
+	assert_eq!(
+		res,
+		Statement::Remove(RemoveStatement::Sequence(RemoveSequenceStatement {
+			name: Ident("foo".to_owned()),
+			if_exists: true,
+		}))
+	);
+
 	let res = test_parse!(parse_stmt, r#"REMOVE TABLE foo"#).unwrap();
 	assert_eq!(
 		res,
@@ -2711,6 +3086,36 @@ fn parse_update() {
 	);
 }
 
+#[test]
+fn parse_update_merge_arrays() {
+	let res = test_parse!(parse_stmt, r#"UPDATE a MERGE { b: [] } ARRAYS UNION BY id"#).unwrap();
+	assert_eq!(
+		res,
+		Statement::Update(UpdateStatement {
+			what: Values(vec![Value::Table(Table("a".to_owned()))]),
+			data: Some(Data::MergeWithExpression(
+				Value::Object(Object(
+					[("b".to_owned(), Value::Array(Array(vec![])))].into_iter().collect()
+				)),
+				MergeStrategy::Union(Some(Idiom(vec![Part::Field(Ident("id".to_owned()))])))
+			)),
+			..Default::default()
+		})
+	);
+	let res = test_parse!(parse_stmt, r#"UPDATE a MERGE $data ARRAYS APPEND"#).unwrap();
+	assert_eq!(
+		res,
+		Statement::Update(UpdateStatement {
+			what: Values(vec![Value::Table(Table("a".to_owned()))]),
+			data: Some(Data::MergeWithExpression(
+				Value::Param(Param(Ident("data".to_owned()))),
+				MergeStrategy::Append
+			)),
+			..Default::default()
+		})
+	);
+}
+
 #[test]
 fn parse_upsert() {
 	let res = test_parse!(
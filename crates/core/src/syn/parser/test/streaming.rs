@@ -18,9 +18,9 @@ use crate::{
 			DefineFunctionStatement, DefineIndexStatement, DefineNamespaceStatement,
 			DefineParamStatement, DefineStatement, DefineTableStatement, DeleteStatement,
 			ForeachStatement, IfelseStatement, InfoStatement, InsertStatement, KillStatement,
-			OutputStatement, RelateStatement, RemoveFieldStatement, RemoveFunctionStatement,
-			RemoveStatement, SelectStatement, SetStatement, ThrowStatement, UpdateStatement,
-			UpsertStatement,
+			NamespaceLimits, OutputStatement, RelateStatement, RemoveFieldStatement,
+			RemoveFunctionStatement, RemoveStatement, SelectStatement, SetStatement,
+			ThrowStatement, UpdateStatement, UpsertStatement,
 		},
 		tokenizer::Tokenizer,
 		Algorithm, Array, Base, Block, Cond, Data, Datetime, Dir, Duration, Edges, Explain,
@@ -125,8 +125,8 @@ fn statements() -> Vec<Statement> {
 
 	vec![
 		Statement::Analyze(AnalyzeStatement::Idx(Ident("a".to_string()), Ident("b".to_string()))),
-		Statement::Begin(BeginStatement),
-		Statement::Begin(BeginStatement),
+		Statement::Begin(BeginStatement::default()),
+		Statement::Begin(BeginStatement::default()),
 		Statement::Break(BreakStatement),
 		Statement::Cancel(CancelStatement),
 		Statement::Cancel(CancelStatement),
@@ -165,6 +165,7 @@ fn statements() -> Vec<Statement> {
 			comment: Some(Strand("test".to_string())),
 			if_not_exists: false,
 			overwrite: false,
+			limits: NamespaceLimits::default(),
 		})),
 		Statement::Define(DefineStatement::Namespace(DefineNamespaceStatement {
 			id: None,
@@ -172,6 +173,7 @@ fn statements() -> Vec<Statement> {
 			comment: None,
 			if_not_exists: false,
 			overwrite: false,
+			limits: NamespaceLimits::default(),
 		})),
 		Statement::Define(DefineStatement::Database(DefineDatabaseStatement {
 			id: None,
@@ -290,6 +292,8 @@ fn statements() -> Vec<Statement> {
 			cache_tables_ts: uuid::Uuid::default(),
 			cache_indexes_ts: uuid::Uuid::default(),
 			cache_lives_ts: uuid::Uuid::default(),
+			expire: None,
+			assert: None,
 		})),
 		Statement::Define(DefineStatement::Event(DefineEventStatement {
 			name: Ident("event".to_owned()),
@@ -299,6 +303,7 @@ fn statements() -> Vec<Statement> {
 			comment: None,
 			if_not_exists: false,
 			overwrite: false,
+			schedule: None,
 		})),
 		Statement::Define(DefineStatement::Field(DefineFieldStatement {
 			name: Idiom(vec![
@@ -326,6 +331,8 @@ fn statements() -> Vec<Statement> {
 			comment: None,
 			if_not_exists: false,
 			overwrite: false,
+			reference: None,
+			encrypted: false,
 		})),
 		Statement::Define(DefineStatement::Index(DefineIndexStatement {
 			name: Ident("index".to_owned()),
@@ -354,6 +361,7 @@ fn statements() -> Vec<Statement> {
 			if_not_exists: false,
 			overwrite: false,
 			concurrently: false,
+			desc: false,
 		})),
 		Statement::Define(DefineStatement::Index(DefineIndexStatement {
 			name: Ident("index".to_owned()),
@@ -364,6 +372,7 @@ fn statements() -> Vec<Statement> {
 			if_not_exists: false,
 			overwrite: false,
 			concurrently: false,
+			desc: false,
 		})),
 		Statement::Define(DefineStatement::Index(DefineIndexStatement {
 			name: Ident("index".to_owned()),
@@ -382,6 +391,7 @@ fn statements() -> Vec<Statement> {
 			if_not_exists: false,
 			overwrite: false,
 			concurrently: false,
+			desc: false,
 		})),
 		Statement::Define(DefineStatement::Analyzer(DefineAnalyzerStatement {
 			name: Ident("ana".to_owned()),
@@ -609,6 +619,7 @@ fn statements() -> Vec<Statement> {
 		}),
 		Statement::Kill(KillStatement {
 			id: Value::Uuid(Uuid(uuid::uuid!("e72bee20-f49b-11ec-b939-0242ac120002"))),
+			connection: false,
 		}),
 		Statement::Output(OutputStatement {
 			what: ident_field("RETRUN"),
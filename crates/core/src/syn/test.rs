@@ -139,3 +139,37 @@ fn empty_subquery() {
 fn empty_json() {
 	super::json("").unwrap_err();
 }
+
+#[test]
+fn format_query() {
+	let sql = "define table person schemafull permissions for create, update, delete none for select where public = true";
+	let out = super::format(sql).unwrap();
+	assert_eq!(out, "DEFINE TABLE person TYPE NORMAL SCHEMAFULL\n\tPERMISSIONS\n\t\tFOR select\n\t\t\tWHERE public = true\n\t\tFOR create, update, delete NONE\n;");
+	// Formatting a query again does not change it
+	let sql = "LET $a = { foo: [1, 2] }; IF $a.foo { SELECT * FROM person WHERE age > 18 } ELSE { RETURN NONE }";
+	let out = super::format(sql).unwrap();
+	assert_eq!(parse(&out).unwrap(), parse(sql).unwrap());
+	assert_eq!(super::format(&out).unwrap(), out);
+}
+
+#[test]
+fn format_invalid_query() {
+	super::format("SELECT * FROM").unwrap_err();
+}
+
+#[test]
+fn parse_reports_all_errors() {
+	let sql = "SELECT * FROM; CREATE person:one; IF true { CREATE person SET a = ; }; SELECT * FROM person";
+	let Err(Error::InvalidQuery(err)) = parse(sql) else {
+		panic!("expected a syntax error");
+	};
+	// The semicolon inside the block doesn't end the statement
+	assert_eq!(err.following.len(), 1, "{err}");
+	assert!(err.following[0].following.is_empty(), "{err}");
+	// The valid statements after an error don't report errors
+	let Err(Error::InvalidQuery(err)) = parse("SELECT * FROM person WHERE; CREATE person:one; ;")
+	else {
+		panic!("expected a syntax error");
+	};
+	assert!(err.following.is_empty(), "{err}");
+}
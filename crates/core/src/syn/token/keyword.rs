@@ -31,6 +31,8 @@ keyword! {
 	Alter => "ALTER",
 	Analyze => "ANALYZE",
 	Analyzer => "ANALYZER",
+	Append => "APPEND",
+	Arrays => "ARRAYS",
 	As => "AS",
 	Ascending => "ASCENDING",
 	Ascii => "ASCII",
@@ -50,11 +52,14 @@ keyword! {
 	ChangeFeed => "CHANGEFEED",
 	Changes => "CHANGES",
 	Capacity => "CAPACITY",
+	Cascade => "CASCADE",
 	Class => "CLASS",
 	Comment => "COMMENT",
 	Commit => "COMMIT",
 	Concurrently => "CONCURRENTLY",
 	Config => "CONFIG",
+	Connection => "CONNECTION",
+	Connections => "CONNECTIONS",
 	Content => "CONTENT",
 	Continue => "CONTINUE",
 	Create => "CREATE",
@@ -66,6 +71,7 @@ keyword! {
 	Diff => "DIFF",
 	Dimension => "DIMENSION",
 	Distance => "DISTANCE",
+	Distinct => "DISTINCT",
 	DocIdsCache => "DOC_IDS_CACHE",
 	DocIdsOrder => "DOC_IDS_ORDER",
 	DocLengthsCache => "DOC_LENGTHS_CACHE",
@@ -76,10 +82,12 @@ keyword! {
 	Edgengram => "EDGENGRAM",
 	Event => "EVENT",
 	Else => "ELSE",
+	Encrypted => "ENCRYPTED",
 	End => "END",
 	Enforced => "ENFORCED",
 	Exclude => "EXCLUDE",
 	Exists => "EXISTS",
+	Expire => "EXPIRE",
 	Expired => "EXPIRED",
 	Explain => "EXPLAIN",
 	Expunge => "EXPUNGE",
@@ -123,6 +131,7 @@ keyword! {
 	M0 => "M0",
 	Mapper => "MAPPER",
 	Merge => "MERGE",
+	Migrate => "MIGRATE",
 	Model => "MODEL",
 	MTree => "MTREE",
 	MTreeCache => "MTREE_CACHE",
@@ -132,6 +141,7 @@ keyword! {
 	NoIndex => "NOINDEX",
 	None => "NONE",
 	Null => "NULL",
+	Nulls => "NULLS",
 	Numeric => "NUMERIC",
 	Omit => "OMIT",
 	On => "ON",
@@ -145,28 +155,39 @@ keyword! {
 	Passhash => "PASSHASH",
 	Password => "PASSWORD",
 	Patch => "PATCH",
+	Path => "PATH",
 	Permissions => "PERMISSIONS",
 	PostingsCache => "POSTINGS_CACHE",
 	PostingsOrder => "POSTINGS_ORDER",
 	Punct => "PUNCT",
 	Purge => "PURGE",
+	Queries => "QUERIES",
 	Range => "RANGE",
+	Rate => "RATE",
 	Readonly => "READONLY",
 	Rebuild => "REBUILD",
+	Reference => "REFERENCE",
+	Reject => "REJECT",
 	Relate => "RELATE",
 	Relation => "RELATION",
+	Release => "RELEASE",
 	Remove => "REMOVE",
+	Rename => "RENAME",
 	Replace => "REPLACE",
 	Return => "RETURN",
 	Revoke => "REVOKE",
 	Revoked => "REVOKED",
 	Roles => "ROLES",
+	Rollback => "ROLLBACK",
 	Root => "ROOT",
+	Savepoint => "SAVEPOINT",
+	Schedule => "SCHEDULE",
 	Schemafull => "SCHEMAFULL",
 	Schemaless => "SCHEMALESS",
 	Scope => "SCOPE",
 	Search => "SEARCH",
 	Select => "SELECT",
+	Sequence => "SEQUENCE",
 	Session => "SESSION",
 	Set => "SET",
 	Show => "SHOW",
@@ -174,6 +195,7 @@ keyword! {
 	Signup => "SIGNUP",
 	Since => "SINCE",
 	Sleep => "SLEEP",
+	Slow => "SLOW",
 	Snowball => "SNOWBALL",
 	Split => "SPLIT",
 	Start => "START",
@@ -186,12 +208,15 @@ keyword! {
 	Then => "THEN",
 	Throw => "THROW",
 	Timeout => "TIMEOUT",
+	Timeseries => "TIMESERIES",
+	Timestamp => "TIMESTAMP",
 	Tokenizers => "TOKENIZERS",
 	Token => "TOKEN",
 	To => "TO",
 	Transaction => "TRANSACTION",
 	True => "true",
 	Type => "TYPE",
+	Union => "UNION",
 	Unique => "UNIQUE",
 	Unset => "UNSET",
 	Update => "UPDATE",
@@ -0,0 +1,94 @@
+use crate::err::Error;
+use std::sync::atomic::{AtomicU64, AtomicUsize, Ordering};
+use std::time::Duration;
+
+/// Faults which are injected into the transactions of a datastore
+///
+/// The faults can be changed while the datastore is in use, so that
+/// a test can decide when the storage engine starts to misbehave.
+#[derive(Debug, Default)]
+pub struct Faults {
+	/// The number of commits which fail with a transaction conflict
+	conflicts: AtomicUsize,
+	/// The number of commits which fail with a transaction error
+	failures: AtomicUsize,
+	/// The delay added to each storage operation, in microseconds
+	latency: AtomicU64,
+	/// The number of commits which have been attempted
+	commits: AtomicUsize,
+}
+
+impl Faults {
+	/// Create a new set of faults, which initially injects nothing
+	pub fn new() -> Self {
+		Self::default()
+	}
+
+	/// Fail the next `count` commits with a retryable transaction conflict
+	pub fn conflict_commits(&self, count: usize) {
+		self.conflicts.store(count, Ordering::SeqCst);
+	}
+
+	/// Fail the next `count` commits with a transaction error
+	pub fn fail_commits(&self, count: usize) {
+		self.failures.store(count, Ordering::SeqCst);
+	}
+
+	/// Delay each read, write, and commit by the specified duration
+	pub fn set_latency(&self, latency: Option<Duration>) {
+		let latency = latency.map(|v| v.as_micros() as u64).unwrap_or_default();
+		self.latency.store(latency, Ordering::SeqCst);
+	}
+
+	/// The number of commits which have been attempted, including
+	/// the commits which failed because of an injected fault
+	pub fn commits(&self) -> usize {
+		self.commits.load(Ordering::SeqCst)
+	}
+
+	/// Wait for the delay of a storage operation
+	pub(crate) async fn delay(&self) {
+		let latency = self.latency.load(Ordering::Relaxed);
+		if latency > 0 {
+			let dur = Duration::from_micros(latency);
+			#[cfg(target_arch = "wasm32")]
+			wasmtimer::tokio::sleep(dur).await;
+			#[cfg(not(target_arch = "wasm32"))]
+			tokio::time::sleep(dur).await;
+		}
+	}
+
+	/// Check whether a commit should fail
+	pub(crate) fn commit(&self) -> Result<(), Error> {
+		self.commits.fetch_add(1, Ordering::SeqCst);
+		if Self::take(&self.conflicts) {
+			return Err(Error::TxRetryable);
+		}
+		if Self::take(&self.failures) {
+			return Err(Error::Tx("The commit failed because of an injected fault".to_owned()));
+		}
+		Ok(())
+	}
+
+	/// Decrement a fault counter, returning whether the fault applies
+	fn take(count: &AtomicUsize) -> bool {
+		count.fetch_update(Ordering::SeqCst, Ordering::SeqCst, |v| v.checked_sub(1)).is_ok()
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+
+	#[test]
+	fn injects_commit_faults() {
+		let faults = Faults::new();
+		assert!(faults.commit().is_ok());
+		faults.conflict_commits(1);
+		faults.fail_commits(1);
+		assert!(matches!(faults.commit(), Err(Error::TxRetryable)));
+		assert!(matches!(faults.commit(), Err(Error::Tx(_))));
+		assert!(faults.commit().is_ok());
+		assert_eq!(faults.commits(), 4);
+	}
+}
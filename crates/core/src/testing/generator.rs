@@ -0,0 +1,99 @@
+use crate::sql::{Datetime, Uuid};
+use chrono::{DateTime, Utc};
+use std::fmt::Debug;
+use std::sync::atomic::{AtomicU64, Ordering};
+use std::time::Duration;
+use ulid::Ulid;
+
+/// Provides the current time, and the random record ids, of statements
+///
+/// The generator is used by `time::now()`, `rand::ulid()`, and `rand::uuid()`,
+/// and when generating record ids with `rand()`, `ulid()`, and `uuid()`, or
+/// when creating a record without specifying an id.
+pub trait Generator: Debug + Send + Sync {
+	/// The current time
+	fn now(&self) -> Datetime;
+	/// A random record id string, as generated by `rand()`
+	fn rand(&self) -> String;
+	/// A new ULID, as generated by `ulid()`
+	fn ulid(&self) -> String;
+	/// A new UUID, as generated by `uuid()`
+	fn uuid(&self) -> Uuid;
+}
+
+/// A generator which returns the same sequence of values on every run
+///
+/// The time starts at the specified datetime, and advances by the specified
+/// step each time it is read. Identifiers are generated from a counter, so
+/// they are unique and sort in the order in which they were generated.
+#[derive(Debug)]
+pub struct Deterministic {
+	start: DateTime<Utc>,
+	step: Duration,
+	time: AtomicU64,
+	ids: AtomicU64,
+}
+
+impl Default for Deterministic {
+	/// Start at the UNIX epoch, advancing the time by one second
+	fn default() -> Self {
+		Self::new(Datetime(DateTime::UNIX_EPOCH), Duration::from_secs(1))
+	}
+}
+
+impl Deterministic {
+	/// Create a new generator, with a time starting at `start`
+	pub fn new(start: Datetime, step: Duration) -> Self {
+		Self {
+			start: start.0,
+			step,
+			time: AtomicU64::new(0),
+			ids: AtomicU64::new(0),
+		}
+	}
+
+	/// The next value of the identifier counter
+	fn next(&self) -> u64 {
+		self.ids.fetch_add(1, Ordering::SeqCst) + 1
+	}
+}
+
+impl Generator for Deterministic {
+	fn now(&self) -> Datetime {
+		let count = self.time.fetch_add(1, Ordering::SeqCst);
+		let step = chrono::Duration::from_std(self.step).unwrap_or_default();
+		Datetime(self.start + step * count as i32)
+	}
+
+	fn rand(&self) -> String {
+		format!("{:020}", self.next())
+	}
+
+	fn ulid(&self) -> String {
+		let time = self.start.timestamp_millis().max(0) as u64;
+		Ulid::from_parts(time, self.next() as u128).to_string()
+	}
+
+	fn uuid(&self) -> Uuid {
+		Uuid(uuid::Uuid::from_u128(self.next() as u128))
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+
+	#[test]
+	fn generates_the_same_values() {
+		let one = Deterministic::default();
+		let two = Deterministic::default();
+		assert_eq!(one.now(), two.now());
+		assert_eq!(one.rand(), two.rand());
+		assert_eq!(one.ulid(), two.ulid());
+		assert_eq!(one.uuid(), two.uuid());
+		// The time advances each time it is read
+		assert_eq!(one.now().0, DateTime::UNIX_EPOCH + chrono::Duration::seconds(1));
+		// Identifiers are unique and ordered
+		assert!(one.rand() < one.rand());
+	}
+}
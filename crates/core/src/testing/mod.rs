@@ -0,0 +1,29 @@
+//! Support for testing applications which embed the database.
+//!
+//! A datastore can be configured with [`Faults`], which are injected into the
+//! transactions of the underlying storage engine, and with a [`Generator`],
+//! which provides the current time and the random record ids of the statements
+//! which are run on the datastore, so that their results are deterministic.
+//!
+//! ```rust,no_run
+//! # use surrealdb_core::err::Error;
+//! # use surrealdb_core::kvs::Datastore;
+//! # use surrealdb_core::testing::{Deterministic, Faults};
+//! # use std::sync::Arc;
+//! # #[tokio::main]
+//! # async fn main() -> Result<(), Error> {
+//! let faults = Arc::new(Faults::new());
+//! let ds = Datastore::new("memory")
+//! 	.await?
+//! 	.with_faults(Some(faults.clone()))
+//! 	.with_generator(Some(Arc::new(Deterministic::default())));
+//! // Fail the next commit with a retryable conflict
+//! faults.conflict_commits(1);
+//! # Ok(())
+//! # }
+//! ```
+mod faults;
+mod generator;
+
+pub use self::faults::Faults;
+pub use self::generator::{Deterministic, Generator};
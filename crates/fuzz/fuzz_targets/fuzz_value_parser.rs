@@ -0,0 +1,11 @@
+#![no_main]
+
+use libfuzzer_sys::fuzz_target;
+
+fuzz_target!(|data: &str| {
+	// Don't crash.
+	_ = surrealdb::sql::value(data);
+	_ = surrealdb::sql::json(data);
+	_ = surrealdb::sql::idiom(data);
+	_ = surrealdb::sql::thing(data);
+});
@@ -107,7 +107,9 @@ pub(crate) async fn run_router(
 		.with_strict_mode(address.config.strict)
 		.with_query_timeout(address.config.query_timeout)
 		.with_transaction_timeout(address.config.transaction_timeout)
-		.with_capabilities(address.config.capabilities);
+		.with_capabilities(address.config.capabilities)
+		.with_faults(address.config.faults)
+		.with_generator(address.config.generator);
 
 	#[cfg(storage)]
 	let kvs = kvs.with_temporary_directory(address.config.temporary_directory);
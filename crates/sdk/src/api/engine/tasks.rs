@@ -47,7 +47,10 @@ pub fn init(dbs: Arc<Datastore>, canceller: CancellationToken, opts: &EngineOpti
 	let task2 = spawn_task_node_membership_check(dbs.clone(), canceller.clone(), opts);
 	let task3 = spawn_task_node_membership_cleanup(dbs.clone(), canceller.clone(), opts);
 	let task4 = spawn_task_changefeed_cleanup(dbs.clone(), canceller.clone(), opts);
-	Tasks(vec![task1, task2, task3, task4])
+	let task5 = spawn_task_record_expiry(dbs.clone(), canceller.clone(), opts);
+	let task6 = spawn_task_event_schedule(dbs.clone(), canceller.clone(), opts);
+	let task7 = spawn_task_webhook_delivery(dbs.clone(), canceller.clone(), opts);
+	Tasks(vec![task1, task2, task3, task4, task5, task6, task7])
 }
 
 fn spawn_task_node_membership_refresh(
@@ -174,6 +177,99 @@ fn spawn_task_changefeed_cleanup(
 	}))
 }
 
+fn spawn_task_record_expiry(
+	dbs: Arc<Datastore>,
+	canceller: CancellationToken,
+	opts: &EngineOptions,
+) -> Task {
+	// Get the delay interval from the config
+	let delay = opts.record_expiry_interval;
+	// Spawn a future
+	Box::pin(spawn(async move {
+		// Log the interval frequency
+		trace!("Deleting expired records every {delay:?}");
+		// Create a new time-based interval ticket
+		let mut ticker = interval_ticker(delay).await;
+		// Loop continuously until the task is cancelled
+		loop {
+			tokio::select! {
+				biased;
+				// Check if this has shutdown
+				_ = canceller.cancelled() => break,
+				// Receive a notification on the channel
+				Some(_) = ticker.next() => {
+					if let Err(e) = dbs.record_expiry_process().await {
+						error!("Error deleting expired records: {e}");
+					}
+				}
+			}
+		}
+		trace!("Background task exited: Deleting expired records");
+	}))
+}
+
+fn spawn_task_event_schedule(
+	dbs: Arc<Datastore>,
+	canceller: CancellationToken,
+	opts: &EngineOptions,
+) -> Task {
+	// Get the delay interval from the config
+	let delay = opts.event_schedule_interval;
+	// Spawn a future
+	Box::pin(spawn(async move {
+		// Log the interval frequency
+		trace!("Checking for scheduled events every {delay:?}");
+		// Create a new time-based interval ticket
+		let mut ticker = interval_ticker(delay).await;
+		// Loop continuously until the task is cancelled
+		loop {
+			tokio::select! {
+				biased;
+				// Check if this has shutdown
+				_ = canceller.cancelled() => break,
+				// Receive a notification on the channel
+				Some(_) = ticker.next() => {
+					if let Err(e) = dbs.event_schedule_process().await {
+						error!("Error running scheduled events: {e}");
+					}
+				}
+			}
+		}
+		trace!("Background task exited: Checking for scheduled events");
+	}))
+}
+
+fn spawn_task_webhook_delivery(
+	dbs: Arc<Datastore>,
+	canceller: CancellationToken,
+	opts: &EngineOptions,
+) -> Task {
+	// Get the delay interval from the config
+	let delay = opts.webhook_delivery_interval;
+	// Spawn a future
+	Box::pin(spawn(async move {
+		// Log the interval frequency
+		trace!("Delivering queued webhooks every {delay:?}");
+		// Create a new time-based interval ticket
+		let mut ticker = interval_ticker(delay).await;
+		// Loop continuously until the task is cancelled
+		loop {
+			tokio::select! {
+				biased;
+				// Check if this has shutdown
+				_ = canceller.cancelled() => break,
+				// Receive a notification on the channel
+				Some(_) = ticker.next() => {
+					if let Err(e) = dbs.webhook_delivery_process().await {
+						error!("Error delivering queued webhooks: {e}");
+					}
+				}
+			}
+		}
+		trace!("Background task exited: Delivering queued webhooks");
+	}))
+}
+
 async fn interval_ticker(interval: Duration) -> IntervalStream {
 	#[cfg(not(target_arch = "wasm32"))]
 	use tokio::{time, time::MissedTickBehavior};
@@ -8,3 +8,5 @@ pub static DB: HeaderName = HeaderName::from_static("surreal-db");
 pub static AUTH_NS: HeaderName = HeaderName::from_static("surreal-auth-ns");
 pub static AUTH_DB: HeaderName = HeaderName::from_static("surreal-auth-db");
 pub static VERSION: HeaderName = HeaderName::from_static("surreal-version");
+pub static IDEMPOTENCY_KEY: HeaderName = HeaderName::from_static("idempotency-key");
+pub static IDEMPOTENT_REPLAYED: HeaderName = HeaderName::from_static("idempotent-replayed");
@@ -8,6 +8,7 @@ use crate::api::Result;
 use crate::method::ExportConfig as Config;
 use crate::method::Model;
 use crate::method::OnceLockExt;
+use crate::Datetime;
 use crate::Surreal;
 use channel::Receiver;
 use futures::Stream;
@@ -95,6 +96,14 @@ where
 		self
 	}
 
+	/// Whether to export sequences from the database
+	pub fn sequences(mut self, sequences: bool) -> Self {
+		if let Some(cfg) = self.db_config.as_mut() {
+			cfg.sequences = sequences;
+		}
+		self
+	}
+
 	/// Whether to export functions from the database
 	pub fn functions(mut self, functions: bool) -> Self {
 		if let Some(cfg) = self.db_config.as_mut() {
@@ -119,6 +128,17 @@ where
 		self
 	}
 
+	/// Export the definitions and records as they were stored at a specific point in time
+	///
+	/// This requires a datastore which keeps record versions, such as SurrealKV
+	/// with versioning enabled, and can not be combined with exporting all versions.
+	pub fn at(mut self, at: impl Into<Datetime>) -> Self {
+		if let Some(cfg) = self.db_config.as_mut() {
+			cfg.at = Some(at.into().into_inner());
+		}
+		self
+	}
+
 	/// Whether to export tables or which ones from the database
 	///
 	/// We can pass a `bool` to export all tables or none at all:
@@ -1,7 +1,9 @@
 use crate::opt::capabilities::Capabilities;
 #[cfg(storage)]
 use std::path::PathBuf;
+use std::sync::Arc;
 use std::time::Duration;
+use surrealdb_core::testing::{Faults, Generator};
 use surrealdb_core::{dbs::Capabilities as CoreCapabilities, iam::Level};
 
 /// Configuration for server connection, including: strictness, notifications, query_timeout, transaction_timeout
@@ -24,6 +26,8 @@ pub struct Config {
 	pub(crate) node_membership_check_interval: Option<Duration>,
 	pub(crate) node_membership_cleanup_interval: Option<Duration>,
 	pub(crate) changefeed_gc_interval: Option<Duration>,
+	pub(crate) faults: Option<Arc<Faults>>,
+	pub(crate) generator: Option<Arc<dyn Generator>>,
 }
 
 impl Config {
@@ -127,4 +131,21 @@ impl Config {
 		self.changefeed_gc_interval = interval.into().filter(|x| !x.is_zero());
 		self
 	}
+
+	/// Inject faults into the transactions of the database, for testing
+	///
+	/// Only used by the local engines
+	pub fn faults(mut self, faults: Arc<Faults>) -> Self {
+		self.faults = Some(faults);
+		self
+	}
+
+	/// Set the generator of the current time and random ids, so that
+	/// the results of queries are deterministic, for testing
+	///
+	/// Only used by the local engines
+	pub fn generator(mut self, generator: Arc<dyn Generator>) -> Self {
+		self.generator = Some(generator);
+		self
+	}
 }
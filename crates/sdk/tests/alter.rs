@@ -49,6 +49,7 @@ async fn define_alter_table() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: { test: 'DEFINE TABLE test TYPE ANY SCHEMALESS PERMISSIONS NONE' },
 			users: {},
 		}",
@@ -67,6 +68,7 @@ async fn define_alter_table() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: { test: 'DEFINE TABLE test TYPE NORMAL DROP SCHEMALESS COMMENT \\'test\\' CHANGEFEED 1d PERMISSIONS FOR select, update, delete NONE, FOR create FULL' },
 			users: {},
 		}",
@@ -85,6 +87,7 @@ async fn define_alter_table() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: { test: 'DEFINE TABLE test TYPE ANY SCHEMAFULL PERMISSIONS NONE' },
 			users: {},
 		}",
@@ -124,6 +127,7 @@ async fn define_alter_table_if_exists() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: {},
 			users: {},
 		}",
@@ -132,3 +136,217 @@ async fn define_alter_table_if_exists() -> Result<(), Error> {
 	//
 	Ok(())
 }
+
+#[tokio::test]
+async fn define_alter_field() -> Result<(), Error> {
+	let sql = "
+		DEFINE FIELD years ON person;
+		CREATE person:one SET years = 20.0;
+		CREATE person:two;
+		ALTER FIELD years ON person RENAME TO age TYPE int DEFAULT 18 COMMENT 'test' MIGRATE;
+		SLEEP 1s;
+		INFO FOR TABLE person;
+		SELECT * FROM person;
+		ALTER FIELD years ON person COMMENT NONE;
+		ALTER FIELD IF EXISTS years ON person COMMENT NONE;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 9);
+	//
+	for _ in 0..5 {
+		res.remove(0).result?;
+	}
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"{
+			events: {},
+			fields: { age: 'DEFINE FIELD age ON person TYPE int DEFAULT 18 COMMENT \\'test\\' PERMISSIONS FULL' },
+			indexes: {},
+			lives: {},
+			migrations: { age: { count: 2, status: 'migrated' } },
+			tables: {},
+		}",
+	);
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"[
+			{ id: person:one, age: 20 },
+			{ id: person:two, age: 18 },
+		]",
+	);
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result;
+	assert!(matches!(tmp, Err(Error::FdNotFound { .. })));
+	//
+	let tmp = res.remove(0).result;
+	assert!(tmp.is_ok());
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn define_alter_field_rename_keeps_previous_field() -> Result<(), Error> {
+	let sql = "
+		DEFINE TABLE person SCHEMAFULL;
+		DEFINE FIELD years ON person TYPE float;
+		CREATE person:one SET years = 20.0;
+		BEGIN;
+		ALTER FIELD years ON person RENAME TO age TYPE int MIGRATE;
+		INFO FOR TABLE person;
+		COMMIT;
+		SLEEP 1s;
+		INFO FOR TABLE person;
+		SELECT * FROM person;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 7);
+	//
+	for _ in 0..4 {
+		res.remove(0).result?;
+	}
+	// The previous field is kept until the records are migrated
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"{
+			events: {},
+			fields: {
+				age: 'DEFINE FIELD age ON person TYPE int PERMISSIONS FULL',
+				years: 'DEFINE FIELD years ON person TYPE option<float> PERMISSIONS FULL'
+			},
+			indexes: {},
+			lives: {},
+			tables: {},
+		}",
+	);
+	assert_eq!(tmp, val);
+	//
+	res.remove(0).result?;
+	// The previous field is removed once the records are migrated
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"{
+			events: {},
+			fields: { age: 'DEFINE FIELD age ON person TYPE int PERMISSIONS FULL' },
+			indexes: {},
+			lives: {},
+			migrations: { age: { count: 1, status: 'migrated' } },
+			tables: {},
+		}",
+	);
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"[
+			{ id: person:one, age: 20 },
+		]",
+	);
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn define_alter_table_rename() -> Result<(), Error> {
+	let sql = "
+		DEFINE TABLE person SCHEMAFULL;
+		DEFINE FIELD name ON person TYPE string;
+		DEFINE INDEX name ON person FIELDS name UNIQUE;
+		CREATE person:tobie SET name = 'Tobie';
+		ALTER TABLE person RENAME TO user;
+		SLEEP 1s;
+		INFO FOR TABLE user;
+		SELECT * FROM user WHERE name = 'Tobie';
+		SELECT * FROM person;
+		CREATE user:jaime SET name = 'Tobie';
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 10);
+	//
+	for _ in 0..6 {
+		res.remove(0).result?;
+	}
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"{
+			events: {},
+			fields: { name: 'DEFINE FIELD name ON user TYPE string PERMISSIONS FULL' },
+			indexes: { name: 'DEFINE INDEX name ON user FIELDS name UNIQUE' },
+			lives: {},
+			renamed: { count: 1, from: 'person', status: 'migrated' },
+			tables: {},
+		}",
+	);
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[{ id: user:tobie, name: 'Tobie' }]");
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[]");
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result;
+	assert!(matches!(tmp, Err(Error::IndexExists { .. })));
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn define_alter_database_rename() -> Result<(), Error> {
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let sql = "
+		CREATE person:tobie SET name = 'Tobie';
+		ALTER DATABASE test RENAME TO other;
+		SLEEP 1s;
+		INFO FOR NS;
+	";
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 4);
+	//
+	for _ in 0..3 {
+		res.remove(0).result?;
+	}
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"{
+			accesses: {},
+			databases: { other: 'DEFINE DATABASE other' },
+			users: {},
+		}",
+	);
+	assert_eq!(tmp, val);
+	//
+	let ses = Session::owner().with_ns("test").with_db("other");
+	let res = &mut dbs.execute("SELECT * FROM person", &ses, None).await?;
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[{ id: person:tobie, name: 'Tobie' }]");
+	assert_eq!(tmp, val);
+	// The progress of the move is stored with the new database
+	let res = &mut dbs.execute("INFO FOR DB", &ses, None).await?;
+	let tmp = res.remove(0).result?;
+	let Value::Object(tmp) = tmp else {
+		panic!("Expected an object");
+	};
+	let Some(Value::Object(renamed)) = tmp.get("renamed") else {
+		panic!("Expected the progress of the rename");
+	};
+	assert_eq!(renamed.get("from"), Some(&Value::from("test")));
+	assert_eq!(renamed.get("status"), Some(&Value::from("migrated")));
+	//
+	Ok(())
+}
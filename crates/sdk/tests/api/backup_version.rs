@@ -422,3 +422,58 @@ async fn export_import_retrieve_specific_versions() {
     // Clean up: remove the export file
     remove_file(export_file).await.unwrap();
 }
+
+#[tokio::test]
+async fn export_import_at_point_in_time() {
+    let (_, db) = new_db().await;
+    let db_name = Ulid::new().to_string();
+    db.use_ns(NS).use_db(&db_name).await.unwrap();
+
+    // Create a record
+    db.query("CREATE user:user1 SET name = 'User 1'").await.unwrap().check().unwrap();
+
+    // Pick a point in time between the two sets of changes
+    tokio::time::sleep(Duration::from_millis(10)).await;
+    let at = chrono::Utc::now();
+    tokio::time::sleep(Duration::from_millis(10)).await;
+
+    // Update the record and define a field
+    db.query(
+        "
+        UPDATE user:user1 SET name = 'Updated User 1';
+        DEFINE FIELD name ON user TYPE string;
+        ",
+    )
+    .await
+    .unwrap()
+    .check()
+    .unwrap();
+
+    // The versions can not be exported at a point in time
+    let export_file = format!("{db_name}.sql");
+    assert!(db.export(&export_file).with_config().versions(true).at(at).await.is_err());
+
+    // Export the database as it was at the point in time
+    db.export(&export_file).with_config().at(at).await.unwrap();
+
+    // Remove the table to simulate a fresh import
+    db.query("REMOVE TABLE user").await.unwrap();
+
+    // Import the database from the file
+    db.import(&export_file).await.unwrap();
+
+    // Verify that the record has the value it had at the point in time
+    let mut response = db.query("SELECT name FROM user:user1").await.unwrap();
+    let Some(name): Option<String> = response.take("name").unwrap() else {
+        panic!("query returned no record");
+    };
+    assert_eq!(name, "User 1");
+
+    // Verify that the field, defined later, was not exported
+    let mut response = db.query("INFO FOR TABLE user").await.unwrap();
+    let info = response.take::<Value>(0).unwrap().to_string();
+    assert!(info.contains("fields: {  }"));
+
+    // Clean up: remove the export file
+    remove_file(export_file).await.unwrap();
+}
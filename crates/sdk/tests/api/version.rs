@@ -207,3 +207,35 @@ async fn info_for_table_with_versioned_fields() {
 		"fields: { firstName: 'DEFINE FIELD firstName ON person TYPE string PERMISSIONS FULL' }"
 	));
 }
+
+#[test_log::test(tokio::test)]
+async fn record_history() {
+	let (permit, db) = new_db().await;
+	db.use_ns(NS).use_db(Ulid::new().to_string()).await.unwrap();
+	drop(permit);
+
+	// Create several versions of the record.
+	let _ = db.query("CREATE user:john SET name = 'John v1'").await.unwrap().check().unwrap();
+	let _ = db.query("UPDATE user:john SET name = 'John v2'").await.unwrap().check().unwrap();
+	let _ = db.query("DELETE user:john").await.unwrap().check().unwrap();
+
+	// The history lists every version, from the oldest to the newest.
+	let mut response = db
+		.query("RETURN record::history(user:john).map(|$v| [$v.deleted, $v.value.name])")
+		.await
+		.unwrap()
+		.check()
+		.unwrap();
+	let history = response.take::<Value>(0).unwrap().to_string();
+	assert_eq!(history, "[[false, 'John v1'], [false, 'John v2'], [true, NONE]]");
+
+	// Each version records the time at which it was written.
+	let mut response = db
+		.query("RETURN record::history(user:john).map(|$v| type::is::datetime($v.at)).all()")
+		.await
+		.unwrap()
+		.check()
+		.unwrap();
+	let all = response.take::<Value>(0).unwrap().to_string();
+	assert_eq!(all, "true");
+}
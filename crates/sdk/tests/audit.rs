@@ -0,0 +1,84 @@
+mod helpers;
+use helpers::new_ds;
+use std::io::Write;
+use std::sync::{Arc, Mutex};
+use surrealdb::dbs::audit::{AuditLog, Category};
+use surrealdb::dbs::Session;
+use surrealdb::err::Error;
+use surrealdb::iam::{signin::signin, Level, Role};
+use surrealdb::sql::{Object, Value};
+
+#[derive(Clone, Default)]
+struct Buffer(Arc<Mutex<Vec<u8>>>);
+
+impl Write for Buffer {
+	fn write(&mut self, buf: &[u8]) -> std::io::Result<usize> {
+		self.0.lock().unwrap().write(buf)
+	}
+	fn flush(&mut self) -> std::io::Result<()> {
+		Ok(())
+	}
+}
+
+impl Buffer {
+	fn events(&self) -> Vec<serde_json::Value> {
+		let buf = self.0.lock().unwrap();
+		std::str::from_utf8(&buf)
+			.unwrap()
+			.lines()
+			.map(|v| serde_json::from_str(v).unwrap())
+			.collect()
+	}
+}
+
+#[tokio::test]
+async fn audit_log_records_events() -> Result<(), Error> {
+	let buf = Buffer::default();
+	let categories = vec![Category::Auth, Category::Permission, Category::Schema];
+	let ds = new_ds()
+		.await?
+		.with_auth_enabled(true)
+		.with_audit_log(Some(AuditLog::new(categories, buf.clone())));
+	// Schema changes are audited, but data changes are not
+	let mut ses = Session::owner().with_ns("test").with_db("test");
+	ses.ip = Some("127.0.0.1".to_owned());
+	let sql = "
+		DEFINE TABLE person;
+		CREATE person:one;
+		DEFINE USER tobie ON ROOT PASSWORD 'secret' ROLES OWNER;
+	";
+	for res in ds.execute(sql, &ses, None).await? {
+		res.result?;
+	}
+	// Statements denied due to permissions are audited
+	let ses = Session::for_level(Level::Database("test".into(), "test".into()), Role::Viewer)
+		.with_ns("test")
+		.with_db("test");
+	let res = ds.execute("REMOVE TABLE person", &ses, None).await?;
+	assert!(res[0].result.is_err());
+	// Authentication attempts are audited
+	let mut ses = Session::default();
+	let vars = Object::from(map! {
+		"user".to_string() => Value::from("tobie"),
+		"pass".to_string() => Value::from("invalid"),
+	});
+	assert!(signin(&ds, &mut ses, vars).await.is_err());
+	// Check the recorded events
+	let events = buf.events();
+	assert_eq!(events.len(), 4, "{events:#?}");
+	assert_eq!(events[0]["category"], "schema");
+	assert_eq!(events[0]["action"], "define");
+	assert!(events[0]["statement"].as_str().unwrap().starts_with("DEFINE TABLE person"));
+	assert_eq!(events[0]["ip"], "127.0.0.1");
+	assert_eq!(events[0]["ns"], "test");
+	assert_eq!(events[0]["success"], true);
+	assert_eq!(events[1]["category"], "schema");
+	assert_eq!(events[2]["category"], "permission");
+	assert_eq!(events[2]["action"], "remove");
+	assert_eq!(events[2]["actor"]["level"], "/ns:test/db:test/");
+	assert_eq!(events[2]["success"], false);
+	assert_eq!(events[3]["category"], "auth");
+	assert_eq!(events[3]["action"], "signin");
+	assert_eq!(events[3]["success"], false);
+	Ok(())
+}
@@ -11,9 +11,10 @@ use std::time::{Duration, SystemTime};
 use surrealdb::dbs::Session;
 use surrealdb::err::Error;
 use surrealdb::iam::Role;
+use surrealdb::kvs::encryption::Keyring;
 use surrealdb::kvs::{LockType, TransactionType};
 use surrealdb::sql::Idiom;
-use surrealdb::sql::{Part, Value};
+use surrealdb::sql::{Part, Thing, Value};
 use surrealdb_core::cnf::{INDEXING_BATCH_SIZE, NORMAL_FETCH_SIZE};
 use test_log::test;
 use tracing::info;
@@ -100,6 +101,7 @@ async fn define_statement_function() -> Result<(), Error> {
 			functions: { test: 'DEFINE FUNCTION fn::test($first: string, $last: string) { RETURN $first + $last; } PERMISSIONS FULL' },
 			models: {},
 			params: {},
+			sequences: {},
 			tables: {},
 			users: {},
 		}",
@@ -132,6 +134,7 @@ async fn define_statement_table_drop() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: { test: 'DEFINE TABLE test TYPE ANY DROP SCHEMALESS PERMISSIONS NONE' },
 			users: {},
 		}",
@@ -164,6 +167,7 @@ async fn define_statement_table_schemaless() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: { test: 'DEFINE TABLE test TYPE ANY SCHEMALESS PERMISSIONS NONE' },
 			users: {},
 		}",
@@ -191,6 +195,7 @@ async fn define_statement_table_schemafull() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: { test: 'DEFINE TABLE test TYPE NORMAL SCHEMAFULL PERMISSIONS NONE' },
 			users: {},
 		}",
@@ -221,6 +226,7 @@ async fn define_statement_table_schemaful() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: { test: 'DEFINE TABLE test TYPE NORMAL SCHEMAFULL PERMISSIONS NONE' },
 			users: {},
 		}",
@@ -261,6 +267,7 @@ async fn define_statement_table_foreigntable() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: {
 				test: 'DEFINE TABLE test TYPE NORMAL SCHEMAFULL PERMISSIONS NONE',
 				view: 'DEFINE TABLE view TYPE ANY SCHEMALESS AS SELECT count() FROM test GROUP ALL PERMISSIONS NONE',
@@ -294,6 +301,7 @@ async fn define_statement_table_foreigntable() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: {
 				test: 'DEFINE TABLE test TYPE NORMAL SCHEMAFULL PERMISSIONS NONE',
 			},
@@ -317,6 +325,210 @@ async fn define_statement_table_foreigntable() -> Result<(), Error> {
 	Ok(())
 }
 
+#[tokio::test]
+async fn define_statement_table_expire() -> Result<(), Error> {
+	let sql = "
+		DEFINE TABLE test EXPIRE 1h;
+		CREATE test:one, test:two;
+		INFO FOR DB;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 3);
+	//
+	let tmp = res.remove(0).result;
+	assert!(tmp.is_ok());
+	//
+	let tmp = res.remove(0).result;
+	assert!(tmp.is_ok());
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"{
+			accesses: {},
+			analyzers: {},
+			configs: {},
+			functions: {},
+			models: {},
+			params: {},
+			sequences: {},
+			tables: { test: 'DEFINE TABLE test TYPE ANY SCHEMALESS EXPIRE 1h PERMISSIONS NONE' },
+			users: {},
+		}",
+	);
+	assert_eq!(tmp, val);
+	// Records which have not yet expired are kept
+	let now = SystemTime::now().duration_since(SystemTime::UNIX_EPOCH).unwrap().as_secs();
+	dbs.record_expiry_process_at(now).await?;
+	let res = &mut dbs.execute("SELECT VALUE id FROM test", &ses, None).await?;
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[test:one, test:two]");
+	assert_eq!(tmp, val);
+	// Records which have expired are deleted
+	dbs.record_expiry_process_at(now + 7200).await?;
+	let res = &mut dbs.execute("SELECT VALUE id FROM test", &ses, None).await?;
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[]");
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn define_statement_table_timeseries() -> Result<(), Error> {
+	let sql = "
+		DEFINE TABLE metric TYPE TIMESERIES TIMESTAMP ts EXPIRE 1h;
+		DEFINE TABLE metric_hourly AS SELECT count() AS total, time::floor(ts, 1h) AS hour FROM metric GROUP BY hour;
+		CREATE metric SET ts = d'2020-01-01T10:15:00Z', value = 1;
+		CREATE metric SET ts = d'2020-01-01T10:45:00Z', value = 2;
+		CREATE metric SET ts = 'yesterday', value = 3;
+		CREATE metric SET ts = time::now(), value = 4;
+		CREATE metric:test SET ts = d'2020-01-01T10:00:00Z';
+		SELECT VALUE [record::id(id)[0] == ts, value] FROM metric ORDER BY id;
+		SELECT total, hour FROM metric_hourly WHERE hour = d'2020-01-01T10:00:00Z';
+		INFO FOR TABLE metric;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 10);
+	//
+	let tmp = res.remove(0).result;
+	assert!(tmp.is_ok());
+	//
+	let tmp = res.remove(0).result;
+	assert!(tmp.is_ok());
+	//
+	let tmp = res.remove(0).result;
+	assert!(tmp.is_ok());
+	//
+	let tmp = res.remove(0).result;
+	assert!(tmp.is_ok());
+	// The timestamp must be a datetime
+	let tmp = res.remove(0).result;
+	assert!(matches!(tmp, Err(Error::TimeSeriesTimestamp { .. })));
+	//
+	let tmp = res.remove(0).result;
+	assert!(tmp.is_ok());
+	// The record id must start with the timestamp
+	let tmp = res.remove(0).result;
+	assert!(matches!(tmp, Err(Error::TimeSeriesId { .. })));
+	// Records are ordered by their timestamp
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[[true, 1], [true, 2], [true, 4]]");
+	assert_eq!(tmp, val);
+	// Rollups are maintained incrementally
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[{ total: 2, hour: d'2020-01-01T10:00:00Z' }]");
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result;
+	assert!(tmp.is_ok());
+	// Records which are older than the retention period are deleted
+	let now = SystemTime::now().duration_since(SystemTime::UNIX_EPOCH).unwrap().as_secs();
+	dbs.record_expiry_process_at(now).await?;
+	let res = &mut dbs.execute("SELECT VALUE value FROM metric", &ses, None).await?;
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[4]");
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn define_statement_table_timeseries_expiry_fractional_seconds() -> Result<(), Error> {
+	let sql = "
+		DEFINE TABLE metric TYPE TIMESERIES TIMESTAMP ts EXPIRE 1h;
+		CREATE metric SET ts = d'2020-01-01T09:59:58.5Z', value = 1;
+		CREATE metric SET ts = d'2020-01-01T09:59:59.5Z', value = 2;
+		CREATE metric SET ts = d'2020-01-01T10:00:00Z', value = 3;
+		CREATE metric SET ts = d'2020-01-01T10:00:00.5Z', value = 4;
+		CREATE metric SET ts = d'2020-01-01T10:00:01Z', value = 5;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 6);
+	//
+	for _ in 0..6 {
+		let tmp = res.remove(0).result;
+		assert!(tmp.is_ok());
+	}
+	// Records with fractional seconds are selected by their timestamp
+	let sql = "SELECT VALUE value FROM metric:[d'2020-01-01T10:00:00Z']..[d'2020-01-01T10:00:01Z']";
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[3, 4]");
+	assert_eq!(tmp, val);
+	// Only the records older than the cutoff are deleted
+	dbs.record_expiry_process_at(1577876400).await?;
+	let res = &mut dbs.execute("SELECT VALUE value FROM metric", &ses, None).await?;
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[3, 4, 5]");
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn define_statement_table_assert() -> Result<(), Error> {
+	let sql = "
+		DEFINE TABLE purchase SCHEMALESS ASSERT total = math::sum(items.*.price);
+		CREATE purchase:one SET items = [{ price: 2 }, { price: 3 }], total = 5;
+		CREATE purchase:two SET items = [{ price: 2 }, { price: 3 }], total = 4;
+		UPDATE purchase:one SET items += { price: 1 };
+		UPDATE purchase:one SET items += { price: 1 }, total += 1;
+		INFO FOR DB;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 6);
+	//
+	let tmp = res.remove(0).result;
+	assert!(tmp.is_ok());
+	//
+	let tmp = res.remove(0).result;
+	assert!(tmp.is_ok());
+	//
+	let tmp = res.remove(0).result;
+	assert!(matches!(
+		tmp.err(),
+		Some(e) if e.to_string() == "Found record: `purchase:two`, but the record must conform to: total = math::sum(items.*.price)"
+	));
+	//
+	let tmp = res.remove(0).result;
+	assert!(matches!(
+		tmp.err(),
+		Some(e) if e.to_string() == "Found record: `purchase:one`, but the record must conform to: total = math::sum(items.*.price)"
+	));
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"[{ id: purchase:one, items: [{ price: 2 }, { price: 3 }, { price: 1 }], total: 6 }]",
+	);
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"{
+			accesses: {},
+			analyzers: {},
+			configs: {},
+			functions: {},
+			models: {},
+			params: {},
+			sequences: {},
+			tables: { purchase: 'DEFINE TABLE purchase TYPE ANY SCHEMALESS ASSERT total = math::sum(items.*.price) PERMISSIONS NONE' },
+			users: {},
+		}",
+	);
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
 #[tokio::test]
 async fn define_statement_event() -> Result<(), Error> {
 	let sql = "
@@ -477,6 +689,56 @@ async fn define_statement_event_check_doc_always_populated() -> Result<(), Error
 	Ok(())
 }
 
+#[tokio::test]
+async fn define_statement_event_schedule() -> Result<(), Error> {
+	let sql = "
+		DEFINE EVENT test ON TABLE user WHEN SCHEDULE '* * * * *' THEN (
+			CREATE report SET source = $event
+		);
+		INFO FOR TABLE user;
+		UPSERT user:test SET email = 'info@surrealdb.com';
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 3);
+	//
+	let tmp = res.remove(0).result;
+	assert!(tmp.is_ok());
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"{
+			events: { test: 'DEFINE EVENT test ON user WHEN SCHEDULE \\'* * * * *\\' THEN (CREATE report SET source = $event)' },
+			fields: {},
+			tables: {},
+			indexes: {},
+			lives: {},
+		}",
+	);
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result;
+	assert!(tmp.is_ok());
+	// The schedule starts when the event is first seen
+	let ts = 1_700_000_000;
+	dbs.event_schedule_process_at(ts).await?;
+	dbs.event_schedule_process_at(ts + 30).await?;
+	let res = &mut dbs.execute("SELECT VALUE source FROM report", &ses, None).await?;
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[]");
+	assert_eq!(tmp, val);
+	// The event runs once the next minute has started
+	dbs.event_schedule_process_at(ts + 60).await?;
+	dbs.event_schedule_process_at(ts + 70).await?;
+	let res = &mut dbs.execute("SELECT VALUE source FROM report", &ses, None).await?;
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("['SCHEDULE']");
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
 #[tokio::test]
 async fn define_statement_event_when_logic() -> Result<(), Error> {
 	let sql = "
@@ -754,6 +1016,7 @@ async fn define_statement_index_single_simple() -> Result<(), Error> {
 	t.skip_ok(5)?;
 	t.expect_val(
 		"{
+			building: { test: { status: 'built' } },
 			events: {},
 			fields: {},
 			tables: {},
@@ -1571,6 +1834,39 @@ async fn define_statement_index_multiple_unique() -> Result<(), Error> {
 	Ok(())
 }
 
+#[tokio::test]
+async fn define_statement_index_unique_nulls() -> Result<(), Error> {
+	let sql = "
+		DEFINE INDEX email ON user FIELDS email UNIQUE NULLS DISTINCT;
+		DEFINE INDEX email ON person FIELDS email UNIQUE NULLS NOT DISTINCT;
+		INFO FOR TABLE person;
+		CREATE user:1 SET email = NULL;
+		CREATE user:2 SET email = NULL;
+		CREATE person:1 SET email = NULL;
+		CREATE person:2 SET email = NULL;
+		CREATE person:3;
+		CREATE person:4;
+	";
+	let mut t = Test::new(sql).await?;
+	t.skip_ok(2)?;
+	t.expect_val(
+		"{
+			events: {},
+			fields: {},
+			tables: {},
+			indexes: { email: 'DEFINE INDEX email ON person FIELDS email UNIQUE NULLS NOT DISTINCT' },
+			lives: {},
+		}",
+	)?;
+	t.expect_val("[{ id: user:1, email: NULL }]")?;
+	t.expect_val("[{ id: user:2, email: NULL }]")?;
+	t.expect_val("[{ id: person:1, email: NULL }]")?;
+	t.expect_error("Database index `email` already contains NULL, with record `person:1`")?;
+	t.expect_val("[{ id: person:3 }]")?;
+	t.expect_error("Database index `email` already contains NONE, with record `person:3`")?;
+	Ok(())
+}
+
 #[tokio::test]
 async fn define_statement_index_single_unique_existing() -> Result<(), Error> {
 	let sql = "
@@ -1802,6 +2098,7 @@ async fn define_statement_analyzer() -> Result<(), Error> {
 			},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: {},
 			users: {},
 		}"#,
@@ -2121,8 +2418,8 @@ async fn permissions_checks_define_function() {
 
 	// Define the expected results for the check statement when the test statement succeeded and when it failed
 	let check_results = [
-        vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: { greet: \"DEFINE FUNCTION fn::greet() { RETURN 'Hello'; } PERMISSIONS FULL\" }, models: {  }, params: {  }, tables: {  }, users: {  } }"],
-		vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: {  }, users: {  } }"]
+        vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: { greet: \"DEFINE FUNCTION fn::greet() { RETURN 'Hello'; } PERMISSIONS FULL\" }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: {  } }"],
+		vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: {  } }"]
     ];
 
 	let test_cases = [
@@ -2163,8 +2460,8 @@ async fn permissions_checks_define_analyzer() {
 
 	// Define the expected results for the check statement when the test statement succeeded and when it failed
 	let check_results = [
-        vec!["{ accesses: {  }, analyzers: { analyzer: 'DEFINE ANALYZER analyzer TOKENIZERS BLANK' }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: {  }, users: {  } }"],
-		vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: {  }, users: {  } }"]
+        vec!["{ accesses: {  }, analyzers: { analyzer: 'DEFINE ANALYZER analyzer TOKENIZERS BLANK' }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: {  } }"],
+		vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: {  } }"]
     ];
 
 	let test_cases = [
@@ -2289,8 +2586,8 @@ async fn permissions_checks_define_access_db() {
 
 	// Define the expected results for the check statement when the test statement succeeded and when it failed
 	let check_results = [
-        vec!["{ accesses: { access: \"DEFINE ACCESS access ON DATABASE TYPE JWT ALGORITHM HS512 KEY '[REDACTED]' WITH ISSUER KEY '[REDACTED]' DURATION FOR TOKEN 1h, FOR SESSION NONE\" }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: {  }, users: {  } }"],
-		vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: {  }, users: {  } }"]
+        vec!["{ accesses: { access: \"DEFINE ACCESS access ON DATABASE TYPE JWT ALGORITHM HS512 KEY '[REDACTED]' WITH ISSUER KEY '[REDACTED]' DURATION FOR TOKEN 1h, FOR SESSION NONE\" }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: {  } }"],
+		vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: {  } }"]
     ];
 
 	let test_cases = [
@@ -2415,8 +2712,8 @@ async fn permissions_checks_define_user_db() {
 
 	// Define the expected results for the check statement when the test statement succeeded and when it failed
 	let check_results = [
-        vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: {  }, users: { user: \"DEFINE USER user ON DATABASE PASSHASH 'secret' ROLES VIEWER DURATION FOR TOKEN 15m, FOR SESSION 6h\" } }"],
-		vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: {  }, users: {  } }"]
+        vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: { user: \"DEFINE USER user ON DATABASE PASSHASH 'secret' ROLES VIEWER DURATION FOR TOKEN 15m, FOR SESSION 6h\" } }"],
+		vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: {  } }"]
     ];
 
 	let test_cases = [
@@ -2457,8 +2754,8 @@ async fn permissions_checks_define_access_record() {
 
 	// Define the expected results for the check statement when the test statement succeeded and when it failed
 	let check_results = [
-        vec!["{ accesses: { account: \"DEFINE ACCESS account ON DATABASE TYPE RECORD WITH JWT ALGORITHM HS512 KEY '[REDACTED]' WITH ISSUER KEY '[REDACTED]' DURATION FOR TOKEN 15m, FOR SESSION 12h\" }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: {  }, users: {  } }"],
-		vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: {  }, users: {  } }"]
+        vec!["{ accesses: { account: \"DEFINE ACCESS account ON DATABASE TYPE RECORD WITH JWT ALGORITHM HS512 KEY '[REDACTED]' WITH ISSUER KEY '[REDACTED]' DURATION FOR TOKEN 15m, FOR SESSION 12h\" }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: {  } }"],
+		vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: {  } }"]
     ];
 
 	let test_cases = [
@@ -2499,8 +2796,8 @@ async fn permissions_checks_define_param() {
 
 	// Define the expected results for the check statement when the test statement succeeded and when it failed
 	let check_results = [
-        vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: { param: \"DEFINE PARAM $param VALUE 'foo' PERMISSIONS FULL\" }, tables: {  }, users: {  } }"],
-		vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: {  }, users: {  } }"]
+        vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: { param: \"DEFINE PARAM $param VALUE 'foo' PERMISSIONS FULL\" }, sequences: {  }, tables: {  }, users: {  } }"],
+		vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: {  } }"]
     ];
 
 	let test_cases = [
@@ -2538,8 +2835,8 @@ async fn permissions_checks_define_table() {
 
 	// Define the expected results for the check statement when the test statement succeeded and when it failed
 	let check_results = [
-        vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: { TB: 'DEFINE TABLE TB TYPE ANY SCHEMALESS PERMISSIONS NONE' }, users: {  } }"],
-		vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: {  }, users: {  } }"]
+        vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: { TB: 'DEFINE TABLE TB TYPE ANY SCHEMALESS PERMISSIONS NONE' }, users: {  } }"],
+		vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: {  } }"]
     ];
 
 	let test_cases = [
@@ -2729,6 +3026,7 @@ async fn define_statement_table_permissions() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: {
 					default: 'DEFINE TABLE default TYPE ANY SCHEMALESS PERMISSIONS NONE',
 					full: 'DEFINE TABLE full TYPE ANY SCHEMALESS PERMISSIONS FULL',
@@ -2985,6 +3283,7 @@ async fn define_overwrite_tables() -> Result<(), Error> {
 				functions: {},
 				models: {},
 				params: {},
+				sequences: {},
 				tables: {
 					whatever: 'DEFINE TABLE whatever TYPE RELATION IN user OUT test SCHEMALESS PERMISSIONS NONE'
 				},
@@ -3000,6 +3299,7 @@ async fn define_overwrite_tables() -> Result<(), Error> {
 				functions: {},
 				models: {},
 				params: {},
+				sequences: {},
 				tables: {
 					whatever: 'DEFINE TABLE whatever TYPE RELATION IN user OUT test SCHEMALESS PERMISSIONS FULL'
 				},
@@ -3015,6 +3315,7 @@ async fn define_overwrite_tables() -> Result<(), Error> {
 				functions: {},
 				models: {},
 				params: {},
+				sequences: {},
 				tables: {
 					library: 'DEFINE TABLE library TYPE ANY SCHEMALESS PERMISSIONS NONE',
 					person: 'DEFINE TABLE person TYPE ANY SCHEMALESS PERMISSIONS NONE',
@@ -3203,6 +3504,7 @@ async fn define_table_relation_redefinition_info() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: {
 				likes: 'DEFINE TABLE likes TYPE RELATION IN person OUT person SCHEMALESS PERMISSIONS NONE'
 			},
@@ -3230,6 +3532,7 @@ async fn define_table_relation_redefinition_info() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: { likes: 'DEFINE TABLE likes TYPE RELATION IN person OUT person | thing SCHEMALESS PERMISSIONS NONE' },
 			users: {},
 		}",
@@ -3255,6 +3558,7 @@ async fn define_table_relation_redefinition_info() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: {},
+			sequences: {},
 			tables: {
 				likes: 'DEFINE TABLE likes TYPE RELATION IN person OUT person | thing | other SCHEMALESS PERMISSIONS NONE'
 			},
@@ -3312,6 +3616,85 @@ async fn define_table_type_any() -> Result<(), Error> {
 	Ok(())
 }
 
+#[tokio::test]
+async fn define_statement_sequence() -> Result<(), Error> {
+	let sql = "
+		DEFINE SEQUENCE invoice START 100;
+		CREATE invoice:[sequence::nextval('invoice')];
+		CREATE invoice:[sequence::nextval('invoice')];
+		RETURN sequence::nextval('invoice');
+		DEFINE SEQUENCE invoice;
+		DEFINE SEQUENCE OVERWRITE invoice START 100;
+		RETURN sequence::nextval('invoice');
+		INFO FOR DB;
+		REMOVE SEQUENCE invoice;
+		RETURN sequence::nextval('invoice');
+		REMOVE SEQUENCE IF EXISTS invoice;
+	";
+	let mut t = Test::new(sql).await?;
+	t.skip_ok(1)?;
+	t.expect_val("[{ id: invoice:[100] }]")?;
+	t.expect_val("[{ id: invoice:[101] }]")?;
+	t.expect_val("102")?;
+	t.expect_error("The sequence 'invoice' already exists")?;
+	t.skip_ok(1)?;
+	t.expect_val("100")?;
+	t.expect_val(
+		"{
+			accesses: {},
+			analyzers: {},
+			configs: {},
+			functions: {},
+			models: {},
+			params: {},
+			sequences: { invoice: 'DEFINE SEQUENCE invoice START 100' },
+			tables: { invoice: 'DEFINE TABLE invoice TYPE ANY SCHEMALESS PERMISSIONS NONE' },
+			users: {},
+		}",
+	)?;
+	t.skip_ok(1)?;
+	t.expect_error("The sequence 'invoice' does not exist")?;
+	t.skip_ok(1)?;
+	Ok(())
+}
+
+#[tokio::test]
+async fn define_statement_sequence_permissions() -> Result<(), Error> {
+	let ds = new_ds().await?.with_auth_enabled(true);
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut ds.execute("DEFINE SEQUENCE invoice", &ses, None).await?;
+	assert!(res.remove(0).result.is_ok());
+	// A viewer can not advance the sequence
+	let ses =
+		Session::for_level(("test", "test").into(), Role::Viewer).with_ns("test").with_db("test");
+	let res = &mut ds.execute("RETURN sequence::nextval('invoice')", &ses, None).await?;
+	let err = res.remove(0).result.unwrap_err();
+	assert!(err.to_string().contains("Not enough permissions"), "{err}");
+	// An editor can advance the sequence
+	let ses =
+		Session::for_level(("test", "test").into(), Role::Editor).with_ns("test").with_db("test");
+	let res = &mut ds.execute("RETURN sequence::nextval('invoice')", &ses, None).await?;
+	assert_eq!(res.remove(0).result?, Value::from(1));
+	// A record user can not advance the sequence
+	let ses = Session::for_record("test", "test", "test", Thing::from(("user", "one")).into());
+	let res = &mut ds.execute("RETURN sequence::nextval('invoice')", &ses, None).await?;
+	let err = res.remove(0).result.unwrap_err();
+	assert!(err.to_string().contains("Not enough permissions"), "{err}");
+	// A record user can advance the sequence through a field definition
+	let sql = "
+		DEFINE TABLE bill PERMISSIONS FOR select, create FULL;
+		DEFINE FIELD num ON bill DEFAULT sequence::nextval('invoice');
+	";
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut ds.execute(sql, &ses, None).await?;
+	assert!(res.remove(0).result.is_ok());
+	assert!(res.remove(0).result.is_ok());
+	let ses = Session::for_record("test", "test", "test", Thing::from(("user", "one")).into());
+	let res = &mut ds.execute("CREATE bill:one RETURN num", &ses, None).await?;
+	assert_eq!(res.remove(0).result?, Value::parse("[{ num: 2 }]"));
+	Ok(())
+}
+
 #[tokio::test]
 async fn cross_transaction_caching_uuids_updated() -> Result<(), Error> {
 	let ds = new_ds().await?;
@@ -3385,3 +3768,98 @@ async fn cross_transaction_caching_uuids_updated() -> Result<(), Error> {
 	//
 	Ok(())
 }
+
+#[tokio::test]
+async fn define_statement_field_encrypted() -> Result<(), Error> {
+	let key = "0123456789abcdef0123456789abcdef";
+	let ds = new_ds().await?.with_encryption(Some(Keyring::new(key, &[])?));
+	let sql = "
+		DEFINE FIELD ssn ON person TYPE string ENCRYPTED;
+		CREATE person:one SET ssn = '123-45-6789';
+		SELECT * FROM person WHERE ssn = '123-45-6789';
+		UPDATE person:one SET ssn = '987-65-4321';
+		REMOVE FIELD ssn ON person;
+		SELECT type::is::bytes(ssn) AS encrypted FROM person;
+		DEFINE FIELD id ON person ENCRYPTED;
+	";
+	let mut t = Test::new_ds(ds, sql).await?;
+	t.skip_ok(1)?;
+	t.expect_val("[{ id: person:one, ssn: '123-45-6789' }]")?;
+	t.expect_val("[{ id: person:one, ssn: '123-45-6789' }]")?;
+	t.expect_val("[{ id: person:one, ssn: '987-65-4321' }]")?;
+	t.skip_ok(1)?;
+	t.expect_val("[{ encrypted: true }]")?;
+	t.expect_error("An error occurred: an ENCRYPTED field must be a field path without wildcards, and can not be the id, in, or out field")?;
+	// Without an encryption key the field can not be written
+	let sql = "
+		DEFINE FIELD ssn ON person ENCRYPTED;
+		CREATE person:one SET ssn = '123-45-6789';
+	";
+	let mut t = Test::new(sql).await?;
+	t.skip_ok(1)?;
+	t.expect_error("The field 'ssn' is encrypted, but no encryption key has been configured")?;
+	Ok(())
+}
+
+#[tokio::test]
+async fn define_statement_field_encrypted_linked() -> Result<(), Error> {
+	let key = "0123456789abcdef0123456789abcdef";
+	let ds = new_ds().await?.with_encryption(Some(Keyring::new(key, &[])?));
+	let sql = "
+		DEFINE FIELD ssn ON person TYPE string ENCRYPTED;
+		CREATE person:one SET ssn = '123-45-6789';
+		CREATE account:one SET owner = person:one;
+		SELECT owner.ssn AS ssn FROM account;
+		SELECT * FROM account FETCH owner;
+		DEFINE INDEX ssn ON person FIELDS ssn;
+		DEFINE FIELD name ON person TYPE string;
+		DEFINE INDEX name ON person FIELDS name;
+		DEFINE FIELD OVERWRITE name ON person TYPE string ENCRYPTED;
+	";
+	let mut t = Test::new_ds(ds, sql).await?;
+	t.skip_ok(3)?;
+	t.expect_val("[{ ssn: '123-45-6789' }]")?;
+	t.expect_val("[{ id: account:one, owner: { id: person:one, ssn: '123-45-6789' } }]")?;
+	t.expect_error("An error occurred: the ENCRYPTED field 'ssn' can not be indexed")?;
+	t.skip_ok(2)?;
+	t.expect_error("An error occurred: the ENCRYPTED field 'name' can not be indexed, but is used by the index 'name'")?;
+	Ok(())
+}
+
+#[tokio::test]
+async fn define_statement_field_encrypted_rotation() -> Result<(), Error> {
+	let old = "fedcba9876543210fedcba9876543210";
+	let key = "0123456789abcdef0123456789abcdef";
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let ds = new_ds().await?.with_encryption(Some(Keyring::new(old, &[])?));
+	let sql = "
+		DEFINE FIELD ssn ON person TYPE string ENCRYPTED;
+		CREATE person:one SET ssn = '123-45-6789';
+		CREATE person:two SET ssn = '987-65-4321';
+	";
+	let res = &mut ds.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 3);
+	for r in res.drain(..) {
+		assert!(r.result.is_ok());
+	}
+	// The data keys can not be decrypted with another key
+	let ds = ds.with_encryption(Some(Keyring::new(key, &[])?));
+	let res = &mut ds.execute("SELECT * FROM person", &ses, None).await?;
+	let err = res.remove(0).result.unwrap_err();
+	assert!(err.to_string().contains("The data keys can not be decrypted"), "{err}");
+	// The values are re-encrypted when the key is rotated
+	let ds = ds.with_encryption(Some(Keyring::new(key, &[old.to_owned()])?));
+	assert_eq!(ds.reencrypt().await?, 2);
+	assert_eq!(ds.reencrypt().await?, 0);
+	// The previous key is no longer needed
+	let ds = ds.with_encryption(Some(Keyring::new(key, &[])?));
+	let res = &mut ds.execute("SELECT * FROM person", &ses, None).await?;
+	let val = Value::parse(
+		"[
+			{ id: person:one, ssn: '123-45-6789' },
+			{ id: person:two, ssn: '987-65-4321' },
+		]",
+	);
+	assert_eq!(res.remove(0).result?, val);
+	Ok(())
+}
@@ -2,7 +2,7 @@ mod parse;
 use parse::Parse;
 
 mod helpers;
-use helpers::new_ds;
+use helpers::{new_ds, Test};
 use surrealdb::dbs::{Action, Notification, Session};
 use surrealdb::err::Error;
 use surrealdb::iam::Role;
@@ -523,3 +523,137 @@ async fn delete_with_permissions_with_select() -> Result<(), Error> {
 	//
 	Ok(())
 }
+
+#[tokio::test]
+async fn delete_with_reference_cascade() -> Result<(), Error> {
+	let sql = "
+		DEFINE FIELD author ON book TYPE record<person> REFERENCE ON DELETE CASCADE;
+		CREATE person:tobie;
+		CREATE book:one SET author = person:tobie;
+		CREATE book:two SET author = person:tobie;
+		DELETE person:tobie;
+		SELECT * FROM book;
+	";
+	let mut t = Test::new(sql).await?;
+	t.skip_ok(5)?;
+	t.expect_val("[]")?;
+	Ok(())
+}
+
+#[tokio::test]
+async fn delete_with_reference_unset() -> Result<(), Error> {
+	let sql = "
+		DEFINE FIELD author ON book TYPE option<record<person>> REFERENCE ON DELETE UNSET;
+		DEFINE FIELD editors ON book TYPE array<record<person>> REFERENCE ON DELETE UNSET;
+		CREATE person:tobie, person:jaime;
+		CREATE book:one SET author = person:tobie, editors = [person:tobie, person:jaime];
+		DELETE person:tobie;
+		SELECT * FROM book;
+	";
+	let mut t = Test::new(sql).await?;
+	t.skip_ok(5)?;
+	t.expect_val(
+		"[
+			{
+				id: book:one,
+				editors: [person:jaime],
+			}
+		]",
+	)?;
+	Ok(())
+}
+
+#[tokio::test]
+async fn delete_with_reference_reject() -> Result<(), Error> {
+	let sql = "
+		DEFINE FIELD author ON book TYPE record<person> REFERENCE ON DELETE REJECT;
+		CREATE person:tobie, person:jaime;
+		CREATE book:one SET author = person:tobie;
+		DELETE person:tobie;
+		UPDATE book:one SET author = person:jaime;
+		DELETE person:tobie;
+		SELECT * FROM person;
+	";
+	let mut t = Test::new(sql).await?;
+	t.skip_ok(3)?;
+	t.expect_error(
+		"Cannot delete `person:tobie` as it is referenced by the field `author` on `book:one`",
+	)?;
+	t.skip_ok(2)?;
+	t.expect_val(
+		"[
+			{
+				id: person:jaime,
+			}
+		]",
+	)?;
+	Ok(())
+}
+
+#[tokio::test]
+async fn delete_with_reference_ignore() -> Result<(), Error> {
+	let sql = "
+		DEFINE FIELD author ON book TYPE record<person> REFERENCE;
+		CREATE person:tobie;
+		CREATE book:one SET author = person:tobie;
+		DELETE person:tobie;
+		SELECT * FROM book;
+	";
+	let mut t = Test::new(sql).await?;
+	t.skip_ok(4)?;
+	t.expect_val(
+		"[
+			{
+				id: book:one,
+				author: person:tobie,
+			}
+		]",
+	)?;
+	Ok(())
+}
+
+#[tokio::test]
+async fn delete_with_reference_defined_later() -> Result<(), Error> {
+	let sql = "
+		CREATE person:tobie;
+		CREATE book:one SET author = person:tobie;
+		DEFINE FIELD author ON book TYPE record<person> REFERENCE ON DELETE CASCADE;
+		DELETE person:tobie;
+		SELECT * FROM book;
+	";
+	let mut t = Test::new(sql).await?;
+	t.skip_ok(4)?;
+	t.expect_val("[]")?;
+	Ok(())
+}
+
+#[tokio::test]
+async fn delete_with_reference_without_permissions() -> Result<(), Error> {
+	let sql = "
+		DEFINE TABLE person PERMISSIONS FULL;
+		DEFINE TABLE book PERMISSIONS NONE;
+		DEFINE FIELD author ON book TYPE record<person> REFERENCE ON DELETE CASCADE;
+		CREATE person:tobie;
+		CREATE book:one SET author = person:tobie;
+	";
+	let dbs = new_ds().await?.with_auth_enabled(true);
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 5);
+	for res in res.drain(..) {
+		assert!(res.result.is_ok());
+	}
+	// The referencing record is deleted, even though
+	// the record user can not delete it directly
+	let ses = Session::for_record("test", "test", "test", Thing::from(("user", "john")).into());
+	let res = &mut dbs.execute("DELETE person:tobie", &ses, None).await?;
+	assert!(res.remove(0).result.is_ok());
+	//
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute("SELECT * FROM book", &ses, None).await?;
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[]");
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
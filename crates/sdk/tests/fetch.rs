@@ -346,3 +346,48 @@ async fn create_relate_select() -> Result<(), Error> {
 	//
 	Ok(())
 }
+
+#[tokio::test]
+async fn select_fetch_shared_record_links() -> Result<(), Error> {
+	let sql = "
+		CREATE brand:apple SET name = 'Apple';
+		CREATE product:phone SET brand = brand:apple;
+		CREATE product:laptop SET brand = brand:apple;
+		CREATE review:1 SET product = product:phone, related = [product:laptop, product:none];
+		CREATE review:2 SET product = product:phone;
+		SELECT * FROM review FETCH product, product.brand, related;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 6);
+	//
+	skip_ok(res, 5)?;
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"[
+			{
+				id: review:1,
+				product: {
+					brand: { id: brand:apple, name: 'Apple' },
+					id: product:phone
+				},
+				related: [
+					{ brand: brand:apple, id: product:laptop },
+					NONE
+				]
+			},
+			{
+				id: review:2,
+				product: {
+					brand: { id: brand:apple, name: 'Apple' },
+					id: product:phone
+				}
+			}
+		]",
+	);
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
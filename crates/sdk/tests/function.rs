@@ -1430,6 +1430,20 @@ async fn function_crypto_sha256() -> Result<(), Error> {
 	Ok(())
 }
 
+#[tokio::test]
+async fn function_crypto_sha256_bytes() -> Result<(), Error> {
+	let sql = r#"
+		RETURN crypto::sha256(<bytes> 'tobie');
+	"#;
+	let mut test = Test::new(sql).await?;
+	//
+	let tmp = test.next()?.result?;
+	let val = Value::from("33fe1859daba927ea5674813adc1cf34b9e2795f2b7e91602fae19c0d0c493af");
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
 #[tokio::test]
 async fn function_crypto_sha512() -> Result<(), Error> {
 	let sql = r#"
@@ -1937,6 +1951,34 @@ async fn function_parse_geo_centroid() -> Result<(), Error> {
 	Ok(())
 }
 
+#[tokio::test]
+async fn function_parse_geo_contains() -> Result<(), Error> {
+	let sql = r#"
+		LET $polygon = {
+			type: 'Polygon',
+			coordinates: [[
+				[-0.38314819, 51.37692386], [0.1785278, 51.37692386],
+				[0.1785278, 51.61460570], [-0.38314819, 51.61460570],
+				[-0.38314819, 51.37692386]
+			]]
+		};
+		RETURN geo::contains($polygon, (-0.118092, 51.509865));
+		RETURN geo::contains($polygon, (-73.971321, 40.776676));
+	"#;
+	let mut test = Test::new(sql).await?;
+	//
+	let tmp = test.next()?.result?;
+	assert_eq!(tmp, Value::None);
+	//
+	let tmp = test.next()?.result?;
+	assert_eq!(tmp, Value::Bool(true));
+	//
+	let tmp = test.next()?.result?;
+	assert_eq!(tmp, Value::Bool(false));
+	//
+	Ok(())
+}
+
 #[tokio::test]
 async fn function_parse_geo_distance() -> Result<(), Error> {
 	let sql = r#"
@@ -1999,6 +2041,37 @@ async fn function_parse_geo_hash_decode() -> Result<(), Error> {
 	Ok(())
 }
 
+#[tokio::test]
+async fn function_parse_geo_intersects() -> Result<(), Error> {
+	let sql = r#"
+		LET $polygon = {
+			type: 'Polygon',
+			coordinates: [[
+				[-0.38314819, 51.37692386], [0.1785278, 51.37692386],
+				[0.1785278, 51.61460570], [-0.38314819, 51.61460570],
+				[-0.38314819, 51.37692386]
+			]]
+		};
+		RETURN geo::intersects($polygon, {
+			type: 'LineString',
+			coordinates: [[-0.118092, 51.509865], [-73.971321, 40.776676]]
+		});
+		RETURN geo::intersects($polygon, (-73.971321, 40.776676));
+	"#;
+	let mut test = Test::new(sql).await?;
+	//
+	let tmp = test.next()?.result?;
+	assert_eq!(tmp, Value::None);
+	//
+	let tmp = test.next()?.result?;
+	assert_eq!(tmp, Value::Bool(true));
+	//
+	let tmp = test.next()?.result?;
+	assert_eq!(tmp, Value::Bool(false));
+	//
+	Ok(())
+}
+
 #[tokio::test]
 async fn function_geo_is_valid() -> Result<(), Error> {
 	let sql = r#"
@@ -7056,6 +7129,94 @@ pub async fn function_http_error() -> Result<(), Error> {
 	Ok(())
 }
 
+#[cfg(feature = "http")]
+#[tokio::test]
+pub async fn function_http_webhook() -> Result<(), Error> {
+	use wiremock::{
+		matchers::{body_json, header, method, path},
+		Mock, ResponseTemplate,
+	};
+
+	let server = wiremock::MockServer::start().await;
+	Mock::given(method("POST"))
+		.and(path("/some/path"))
+		.and(header("user-agent", "SurrealDB"))
+		.and(header("a-test-header", "with-a-test-value"))
+		.and(body_json(serde_json::json!({ "some-key": "committed" })))
+		.respond_with(ResponseTemplate::new(200))
+		.expect(1)
+		.mount(&server)
+		.await;
+
+	let sql = format!(
+		r#"
+		BEGIN;
+		RETURN http::webhook("{0}/some/path", {{ 'some-key': 'cancelled' }});
+		CANCEL;
+		RETURN type::is::uuid(http::webhook("{0}/some/path", {{ 'some-key': 'committed' }}, {{ 'a-test-header': 'with-a-test-value' }}));
+		"#,
+		server.uri()
+	);
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(&sql, &ses, None).await?;
+	assert_eq!(res.len(), 2);
+	//
+	let tmp = res.remove(0).result;
+	assert!(tmp.is_err());
+	//
+	let tmp = res.remove(0).result?;
+	assert_eq!(tmp, Value::Bool(true));
+	// The request is only sent in the background
+	assert!(server.received_requests().await.unwrap().is_empty());
+	dbs.webhook_delivery_process().await?;
+	dbs.webhook_delivery_process().await?;
+	server.verify().await;
+
+	Ok(())
+}
+
+#[cfg(feature = "http")]
+#[tokio::test]
+pub async fn function_http_webhook_retries() -> Result<(), Error> {
+	use wiremock::{
+		matchers::{method, path},
+		Mock, ResponseTemplate,
+	};
+
+	let server = wiremock::MockServer::start().await;
+	Mock::given(method("POST"))
+		.and(path("/some/path"))
+		.respond_with(ResponseTemplate::new(500))
+		.up_to_n_times(1)
+		.expect(1)
+		.mount(&server)
+		.await;
+	Mock::given(method("POST"))
+		.and(path("/some/path"))
+		.respond_with(ResponseTemplate::new(200))
+		.expect(1)
+		.mount(&server)
+		.await;
+
+	let sql = format!(r#"RETURN http::webhook("{}/some/path");"#, server.uri());
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(&sql, &ses, None).await?;
+	res.remove(0).result?;
+	// The first attempt fails
+	let ts = std::time::SystemTime::now().duration_since(std::time::UNIX_EPOCH).unwrap().as_secs();
+	dbs.webhook_delivery_process_at(ts).await?;
+	// The retry is not yet due
+	dbs.webhook_delivery_process_at(ts).await?;
+	// The retry succeeds once it is due
+	dbs.webhook_delivery_process_at(ts + 2).await?;
+	dbs.webhook_delivery_process_at(ts + 3600).await?;
+	server.verify().await;
+
+	Ok(())
+}
+
 #[cfg(all(feature = "http", feature = "scripting"))]
 #[tokio::test]
 pub async fn function_http_get_from_script() -> Result<(), Error> {
@@ -816,6 +816,92 @@ async fn select_count_group_all() -> Result<(), Error> {
 	Ok(())
 }
 
+#[tokio::test]
+async fn select_count_group_all_index() -> Result<(), Error> {
+	let sql = r#"
+		DEFINE FIELD bar ON table TYPE string;
+		DEFINE FIELD tags ON table TYPE array<string>;
+		DEFINE INDEX bar ON table FIELDS bar;
+		DEFINE INDEX tags ON table FIELDS tags;
+		CREATE table CONTENT { bar: "hello", tags: ["hello"] };
+		CREATE table CONTENT { bar: "hello", tags: ["hello", "world"] };
+		CREATE table CONTENT { bar: "world", tags: ["world"] };
+		SELECT COUNT() FROM table WHERE bar = "hello" GROUP ALL EXPLAIN;
+		SELECT COUNT() FROM table WHERE bar = "hello" GROUP ALL;
+		SELECT COUNT() FROM table WHERE tags = "hello" GROUP ALL EXPLAIN;
+		SELECT COUNT() FROM table WHERE tags = "hello" GROUP ALL;
+	"#;
+	let mut t = Test::new(sql).await?;
+	t.expect_size(11)?;
+	//
+	t.skip_ok(7)?;
+	// The records are counted from the index keys
+	t.expect_val(
+		r#"[
+				{
+					detail: {
+						plan: {
+							index: 'bar',
+							operator: '=',
+							value: 'hello'
+						},
+						table: 'table'
+					},
+					operation: 'Iterate Index Keys'
+				},
+				{
+					detail: {
+						idioms: {
+							count: [
+								'count'
+							]
+						},
+						type: 'Group'
+					},
+					operation: 'Collector'
+				}
+			]"#,
+	)?;
+	//
+	t.expect_val(
+		r#"[
+				{
+					count: 2
+				}
+			]"#,
+	)?;
+	// Array values are indexed separately, so the records are checked
+	t.expect_val(
+		r#"[
+				{
+					detail: {
+						plan: {
+							index: 'tags',
+							operator: '=',
+							value: 'hello'
+						},
+						table: 'table'
+					},
+					operation: 'Iterate Index'
+				},
+				{
+					detail: {
+						idioms: {
+							count: [
+								'count'
+							]
+						},
+						type: 'Group'
+					},
+					operation: 'Collector'
+				}
+			]"#,
+	)?;
+	//
+	t.expect_val("[]")?;
+	Ok(())
+}
+
 async fn select_count_group_all_permissions(
 	perm: &str,
 	expect_keys_only: bool,
@@ -933,6 +933,54 @@ async fn idiom_recursion_limits() -> Result<(), Error> {
 	Ok(())
 }
 
+#[tokio::test]
+async fn idiom_recursion_paths() -> Result<(), Error> {
+	let sql = r#"
+		INSERT INTO person [
+			{ id: person:tobie, name: 'Tobie' },
+			{ id: person:jaime, name: 'Jaime' },
+			{ id: person:micha, name: 'Micha' },
+			{ id: person:john, name: 'John' },
+			{ id: person:mary, name: 'Mary' },
+			{ id: person:tim, name: 'Tim' },
+		] RETURN NONE;
+
+		INSERT RELATION INTO knows [
+			{ id: knows:1, in: person:tobie, out: person:jaime },
+			{ id: knows:2, in: person:tobie, out: person:micha },
+			{ id: knows:3, in: person:micha, out: person:john },
+			{ id: knows:4, in: person:jaime, out: person:mary },
+			{ id: knows:5, in: person:mary, out: person:tim },
+		] RETURN NONE;
+
+		person:tobie.{1..3+path}(->knows->person);
+		person:tobie.{1..3+path}(->knows->person).name;
+		person:tobie.{3+path}(->knows->person);
+
+		UPSERT a:1 SET links = [a:2, a:3];
+		UPSERT a:2 SET links = [a:3];
+		UPSERT a:3 SET links = [a:1];
+
+		a:1.{..+path}.links;
+		a:1.{2+path}.links;
+		a:1.{..+path}.{ name, links: links.@ };
+	"#;
+	Test::new(sql)
+		.await?
+		.expect_val("[]")?
+		.expect_val("[]")?
+		.expect_val("[[person:micha, person:john], [person:jaime, person:mary, person:tim]]")?
+		.expect_val("[['Micha', 'John'], ['Jaime', 'Mary', 'Tim']]")?
+		.expect_val("[[person:jaime, person:mary, person:tim]]")?
+		.skip_ok(3)?
+		.expect_val("[[a:3], [a:2, a:3]]")?
+		.expect_val("[[a:2, a:3]]")?
+		.expect_error(
+			"Tried to use a `@` repeat recurse symbol in a position where it is not supported",
+		)?;
+	Ok(())
+}
+
 #[tokio::test]
 async fn idiom_object_dot_star() -> Result<(), Error> {
 	let sql = r#"
@@ -3,9 +3,14 @@ use helpers::*;
 
 use regex::Regex;
 use std::collections::HashMap;
+use std::sync::atomic::{AtomicBool, Ordering};
+use std::sync::Arc;
 use std::thread::available_parallelism;
+use std::time::Duration;
+use surrealdb::dbs::slowlog::SlowLog;
 use surrealdb::dbs::Session;
-use surrealdb::iam::Role;
+use surrealdb::err::Error;
+use surrealdb::iam::{Level, Role};
 
 #[tokio::test]
 async fn info_for_root() {
@@ -75,6 +80,24 @@ async fn info_for_table() {
 		.unwrap();
 }
 
+#[tokio::test]
+async fn info_for_table_structure() {
+	let sql = r#"
+        DEFINE TABLE TB;
+        DEFINE INDEX index ON TABLE TB FIELDS field;
+        CREATE |TB:3| SET field = 'value';
+        INFO FOR TABLE TB STRUCTURE;
+        INFO FOR INDEX index ON TB STRUCTURE;
+    "#;
+	let mut t = Test::new(sql).await.unwrap();
+	t.skip_ok(3).unwrap();
+	t.expect_regex(
+		r"\{ events: \[\], fields: \[\], indexes: \[\{ .* \}\], lives: \[\], statistics: \{ exact: true, records: 3, size: \d+ \}, tables: \[\] \}",
+	)
+	.unwrap();
+	t.expect_regex(r"\{ definition: \{ .*name: 'index'.* \} \}").unwrap();
+}
+
 #[tokio::test]
 async fn info_for_user() {
 	let sql = r#"
@@ -148,6 +171,82 @@ async fn info_for_user() {
 	);
 }
 
+#[tokio::test]
+async fn info_for_slow_queries() -> Result<(), Error> {
+	let sql = "
+		LET $name = 'Tobie';
+		CREATE person:one SET name = $name;
+		SELECT * FROM person;
+		INFO FOR SLOW QUERIES;
+	";
+	// Every statement is logged without a threshold
+	let ds = new_ds().await?.with_slow_log(Some(SlowLog::new(Duration::ZERO, 1.0)));
+	let mut t = Test::new_ds(ds, sql).await?;
+	t.skip_ok(3)?;
+	t.expect_regex(r"\[\{ db: 'test', duration: .+, ns: 'test', params: \['name'\], plan: \[\], rows: 0, statement: .+ \}, \{ .+ \}, \{ db: 'test', duration: .+, ns: 'test', params: \['name'\], plan: \['Iterate Table person'\], rows: 1, statement: 'SELECT \* FROM person', success: true, time: d'.+' \}\]")?;
+	// Statements which run quickly are not logged
+	let ds = new_ds().await?.with_slow_log(Some(SlowLog::new(Duration::from_secs(3600), 1.0)));
+	let mut t = Test::new_ds(ds, sql).await?;
+	t.skip_ok(3)?;
+	t.expect_val("[]")?;
+	// Statements which are not sampled are not logged
+	let ds = new_ds().await?.with_slow_log(Some(SlowLog::new(Duration::ZERO, 0.0)));
+	let mut t = Test::new_ds(ds, sql).await?;
+	t.skip_ok(3)?;
+	t.expect_val("[]")?;
+	// Without a slow query log nothing is logged
+	let mut t = Test::new(sql).await?;
+	t.skip_ok(3)?;
+	t.expect_val("[]")?;
+	Ok(())
+}
+
+#[tokio::test]
+async fn info_for_connections() -> Result<(), Error> {
+	let ds = new_ds().await?;
+	// Register a persistent connection
+	let closed = Arc::new(AtomicBool::new(false));
+	let flag = closed.clone();
+	ds.connections().register("socket", "ws", Some("127.0.0.1".to_owned()), move || {
+		flag.store(true, Ordering::Relaxed)
+	});
+	// The session running a query is listed while the query runs
+	let mut ses = Session::owner().with_ns("test").with_db("test");
+	ses.id = Some("request".to_owned());
+	let res = &mut ds.execute("INFO FOR CONNECTIONS", &ses, None).await?;
+	let val = res.remove(0).result?.to_string();
+	let socket = Regex::new(
+		r"\{ id: 'socket', ip: '127.0.0.1', protocol: 'ws', queries: 0, started: d'.+?' \}",
+	)
+	.unwrap();
+	let request = Regex::new(r"\{ active: d'.+?', actor: \{ id: 'system_auth', level: '/' \}, db: 'test', id: '[0-9a-f-]{36}', ns: 'test', protocol: 'http', queries: 1, started: d'.+?' \}").unwrap();
+	assert!(socket.is_match(&val), "{val}");
+	assert!(request.is_match(&val), "{val}");
+	// A request can not use the id of a persistent connection
+	let mut other = Session::owner().with_ns("other").with_db("other");
+	other.id = Some("socket".to_owned());
+	let res = &mut ds.execute("INFO FOR CONNECTIONS", &other, None).await?;
+	let val = res.remove(0).result?.to_string();
+	assert!(socket.is_match(&val), "{val}");
+	// Killing a connection closes it
+	let res = &mut ds.execute("KILL CONNECTION 'socket'; INFO FOR CONNECTIONS", &ses, None).await?;
+	res.remove(0).result?;
+	assert!(closed.load(Ordering::Relaxed));
+	let val = res.remove(0).result?.to_string();
+	assert!(!socket.is_match(&val), "{val}");
+	// Connections which do not exist can not be killed
+	let res = &mut ds.execute("KILL CONNECTION 'socket'", &ses, None).await?;
+	assert!(res.remove(0).result.is_err());
+	// Only root users can kill connections
+	let mut ses = Session::for_level(Level::Namespace("test".into()), Role::Owner).with_ns("test");
+	ses.id = Some("request".to_owned());
+	let res =
+		&mut ds.execute("KILL CONNECTION 'request'; INFO FOR CONNECTIONS", &ses, None).await?;
+	assert!(res.remove(0).result.is_err());
+	assert!(res.remove(0).result.is_err());
+	Ok(())
+}
+
 //
 // Permissions
 //
@@ -236,8 +335,8 @@ async fn permissions_checks_info_db() {
 
 	// Define the expected results for the check statement when the test statement succeeded and when it failed
 	let check_results = [
-        vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: {  }, users: {  } }"],
-        vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: {  }, users: {  } }"],
+        vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: {  } }"],
+        vec!["{ accesses: {  }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: {  } }"],
     ];
 
 	let test_cases = [
@@ -500,7 +599,7 @@ async fn access_info_redacted() {
 		assert!(out.is_ok(), "Unexpected error: {:?}", out);
 
 		let out_expected =
-            r#"{ accesses: { access: "DEFINE ACCESS access ON DATABASE TYPE RECORD WITH JWT ALGORITHM HS512 KEY '[REDACTED]' WITH ISSUER KEY '[REDACTED]' DURATION FOR TOKEN 1h, FOR SESSION NONE" }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, tables: {  }, users: {  } }"#.to_string();
+            r#"{ accesses: { access: "DEFINE ACCESS access ON DATABASE TYPE RECORD WITH JWT ALGORITHM HS512 KEY '[REDACTED]' WITH ISSUER KEY '[REDACTED]' DURATION FOR TOKEN 1h, FOR SESSION NONE" }, analyzers: {  }, configs: {  }, functions: {  }, models: {  }, params: {  }, sequences: {  }, tables: {  }, users: {  } }"#.to_string();
 		let out_str = out.unwrap().to_string();
 		assert_eq!(
 			out_str, out_expected,
@@ -573,7 +672,7 @@ async fn access_info_redacted_structure() {
 		assert!(out.is_ok(), "Unexpected error: {:?}", out);
 
 		let out_expected =
-            r#"{ accesses: [{ base: 'DATABASE', duration: { session: 6h, token: 15m }, kind: { jwt: { issuer: { alg: 'HS512', key: '[REDACTED]' }, verify: { alg: 'HS512', key: '[REDACTED]' } }, kind: 'RECORD' }, name: 'access' }], analyzers: [], configs: [], functions: [], models: [], params: [], tables: [], users: [] }"#.to_string();
+            r#"{ accesses: [{ base: 'DATABASE', duration: { session: 6h, token: 15m }, kind: { jwt: { issuer: { alg: 'HS512', key: '[REDACTED]' }, verify: { alg: 'HS512', key: '[REDACTED]' } }, kind: 'RECORD' }, name: 'access' }], analyzers: [], configs: [], functions: [], models: [], params: [], sequences: [], tables: [], users: [] }"#.to_string();
 		let out_str = out.unwrap().to_string();
 		assert_eq!(
 			out_str, out_expected,
@@ -598,7 +697,7 @@ async fn function_info_structure() {
 	assert!(out.is_ok(), "Unexpected error: {:?}", out);
 
 	let out_expected =
-        r#"{ accesses: [], analyzers: [], configs: [], functions: [{ args: [['name', 'string']], block: "{ RETURN 'Hello, ' + $name + '!'; }", name: 'example', permissions: true, returns: 'string' }], models: [], params: [], tables: [], users: [] }"#.to_string();
+        r#"{ accesses: [], analyzers: [], configs: [], functions: [{ args: [['name', 'string']], block: "{ RETURN 'Hello, ' + $name + '!'; }", name: 'example', permissions: true, returns: 'string' }], models: [], params: [], sequences: [], tables: [], users: [] }"#.to_string();
 	let out_str = out.unwrap().to_string();
 	assert_eq!(
 		out_str, out_expected,
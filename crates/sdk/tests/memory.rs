@@ -0,0 +1,74 @@
+mod parse;
+use parse::Parse;
+
+mod helpers;
+use helpers::new_ds;
+use surrealdb::dbs::memory::MemoryBudget;
+use surrealdb::dbs::Session;
+use surrealdb::err::Error;
+use surrealdb::sql::Value;
+
+#[tokio::test]
+async fn memory_budget_aborts_statements() -> Result<(), Error> {
+	let ds = new_ds().await?.with_memory_budget(Some(MemoryBudget::new(Some(64 * 1024), None)));
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let sql = "
+		CREATE |person:1..100| SET name = string::repeat('x', 1000) RETURN NONE;
+		SELECT * FROM person LIMIT 10;
+		SELECT * FROM person ORDER BY name;
+	";
+	let res = &mut ds.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 3);
+	assert!(res.remove(0).result.is_ok());
+	// Statements which stay within the budget are not affected
+	assert!(res.remove(0).result.is_ok());
+	// Statements which hold too many results in memory are aborted
+	assert!(matches!(res.remove(0).result, Err(Error::MemoryBudgetExceeded { .. })));
+	// The memory is released once the statements finish
+	let res = &mut ds.execute("SELECT * FROM person LIMIT 10", &ses, None).await?;
+	assert!(res.remove(0).result.is_ok());
+	Ok(())
+}
+
+#[tokio::test]
+async fn memory_budget_counts_only_retained_results() -> Result<(), Error> {
+	let ds = new_ds().await?.with_memory_budget(Some(MemoryBudget::new(Some(64 * 1024), None)));
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let sql = "
+		CREATE |person:1..100| SET name = string::repeat('x', 1000) RETURN NONE;
+		SELECT count() FROM person GROUP ALL;
+		SELECT name, count() FROM person GROUP BY name;
+		DELETE person RETURN NONE;
+		SELECT count() FROM person GROUP ALL;
+	";
+	let res = &mut ds.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 5);
+	assert!(res.remove(0).result.is_ok());
+	// Grouped records only hold the aggregated values
+	let tmp = res.remove(0).result?;
+	assert_eq!(tmp, Value::parse("[{ count: 100 }]"));
+	let tmp = res.remove(0).result?;
+	assert_eq!(tmp, Value::parse(&format!("[{{ count: 100, name: '{}' }}]", "x".repeat(1000))));
+	// Records which are not returned are not held in memory
+	let tmp = res.remove(0).result?;
+	assert_eq!(tmp, Value::parse("[]"));
+	let tmp = res.remove(0).result?;
+	assert_eq!(tmp, Value::parse("[]"));
+	Ok(())
+}
+
+#[tokio::test]
+async fn memory_budget_counts_grouped_results() -> Result<(), Error> {
+	let ds = new_ds().await?.with_memory_budget(Some(MemoryBudget::new(Some(64 * 1024), None)));
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let sql = "
+		CREATE |person:1..100| SET name = rand::string(1000) RETURN NONE;
+		SELECT count() FROM person GROUP BY name;
+	";
+	let res = &mut ds.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 2);
+	assert!(res.remove(0).result.is_ok());
+	// Each distinct group is held in memory until the statement completes
+	assert!(matches!(res.remove(0).result, Err(Error::MemoryBudgetExceeded { .. })));
+	Ok(())
+}
@@ -56,3 +56,57 @@ async fn merge_record() -> Result<(), Error> {
 	//
 	Ok(())
 }
+
+#[tokio::test]
+async fn merge_record_arrays() -> Result<(), Error> {
+	let sql = "
+		UPSERT person:test CONTENT { tags: ['a', 'b'], items: [{ id: 1, qty: 1 }, { id: 2, qty: 1 }] };
+		UPDATE person:test MERGE { tags: ['b', 'c'] } ARRAYS APPEND;
+		UPDATE person:test MERGE { tags: ['c', 'd'], items: [{ id: 2, qty: 5 }, { id: 3, qty: 1 }] } ARRAYS UNION BY id;
+		UPDATE person:test MERGE { tags: ['e'] } ARRAYS REPLACE;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 4);
+	//
+	res.remove(0).result?;
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"[
+			{
+				id: person:test,
+				items: [{ id: 1, qty: 1 }, { id: 2, qty: 1 }],
+				tags: ['a', 'b', 'b', 'c'],
+			}
+		]",
+	);
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"[
+			{
+				id: person:test,
+				items: [{ id: 1, qty: 1 }, { id: 2, qty: 5 }, { id: 3, qty: 1 }],
+				tags: ['a', 'b', 'b', 'c', 'd'],
+			}
+		]",
+	);
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"[
+			{
+				id: person:test,
+				items: [{ id: 1, qty: 1 }, { id: 2, qty: 5 }, { id: 3, qty: 1 }],
+				tags: ['e'],
+			}
+		]",
+	);
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
@@ -32,6 +32,7 @@ async fn define_global_param() -> Result<(), Error> {
 			functions: {},
 			models: {},
 			params: { test: 'DEFINE PARAM $test VALUE 12345 PERMISSIONS FULL' },
+			sequences: {},
 			tables: {},
 			users: {},
 		}",
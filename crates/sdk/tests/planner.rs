@@ -877,6 +877,74 @@ async fn select_unique_range_from_incl_to_incl() -> Result<(), Error> {
 	select_range(true, true, true, EXPLAIN_FROM_INCL_TO_INCL, RESULT_FROM_INCL_TO_INCL).await
 }
 
+fn inside_range_test(unique: bool, range: &str) -> String {
+	format!(
+		"DEFINE INDEX year ON TABLE test COLUMNS year {};
+	CREATE test:0 SET year = 2000;
+	CREATE test:10 SET year = 2010;
+	CREATE test:15 SET year = 2015;
+	CREATE test:16 SET year = {};
+	CREATE test:20 SET year = 2020;
+	SELECT id FROM test WHERE year INSIDE {} EXPLAIN;
+	SELECT id FROM test WHERE year INSIDE {};",
+		if unique {
+			"UNIQUE"
+		} else {
+			""
+		},
+		if unique {
+			"2016"
+		} else {
+			"2015"
+		},
+		range,
+		range,
+	)
+}
+
+async fn select_inside_range(
+	unique: bool,
+	range: &str,
+	explain: &str,
+	result: &str,
+) -> Result<(), Error> {
+	let dbs = new_ds().await?;
+	let mut res = execute_test(&dbs, &inside_range_test(unique, range), 8).await?;
+	skip_ok(&mut res, 6)?;
+	{
+		let tmp = res.remove(0).result?;
+		let val = Value::parse(explain);
+		assert_eq!(format!("{:#}", tmp), format!("{:#}", val));
+	}
+	{
+		let tmp = res.remove(0).result?;
+		let val = Value::parse(result);
+		assert_eq!(format!("{:#}", tmp), format!("{:#}", val));
+	}
+	Ok(())
+}
+
+#[tokio::test]
+async fn select_index_inside_range() -> Result<(), Error> {
+	select_inside_range(false, "2000..2020", EXPLAIN_FROM_INCL_TO, RESULT_FROM_INCL_TO).await
+}
+
+#[tokio::test]
+async fn select_unique_inside_range() -> Result<(), Error> {
+	select_inside_range(true, "2000..2020", EXPLAIN_FROM_INCL_TO, RESULT_FROM_INCL_TO).await
+}
+
+#[tokio::test]
+async fn select_index_inside_range_incl() -> Result<(), Error> {
+	select_inside_range(false, "2000..=2020", EXPLAIN_FROM_INCL_TO_INCL, RESULT_FROM_INCL_TO_INCL)
+		.await
+}
+
+#[tokio::test]
+async fn select_unique_inside_range_excl() -> Result<(), Error> {
+	select_inside_range(true, "2000>..2020", EXPLAIN_FROM_TO, RESULT_FROM_TO).await
+}
+
 fn single_range_operator_test(unique: bool, op: &str) -> String {
 	format!(
 		"DEFINE INDEX year ON TABLE test COLUMNS year {};
@@ -3078,6 +3146,97 @@ async fn select_from_unique_index_descending() -> Result<(), Error> {
 	Ok(())
 }
 
+#[tokio::test]
+async fn select_from_descending_index_descending() -> Result<(), Error> {
+	for index in ["DESC", "DESC UNIQUE"] {
+		let sql = format!(
+			"
+			DEFINE INDEX time ON TABLE session COLUMNS time {index};
+			CREATE session:1 SET time = d'2024-07-01T01:00:00Z';
+			CREATE session:2 SET time = d'2024-06-30T23:00:00Z';
+			CREATE session:3 SET other = 'test';
+			CREATE session:4 SET time = null;
+			CREATE session:5 SET time = d'2024-07-01T02:00:00Z';
+			CREATE session:6 SET time = d'2024-06-30T23:30:00Z';
+			SELECT * FROM session ORDER BY time DESC LIMIT 3 EXPLAIN;
+			SELECT * FROM session ORDER BY time DESC LIMIT 3;
+			SELECT * FROM session ORDER BY time ASC LIMIT 3 EXPLAIN;
+			SELECT * FROM session WHERE time = d'2024-07-01T01:00:00Z' EXPLAIN;
+		"
+		);
+		let mut t = Test::new(&sql).await?;
+		t.skip_ok(7)?;
+		// The descending index is scanned forwards
+		t.expect_vals(&[
+			"[
+				{
+					detail: {
+						plan: {
+							index: 'time',
+							operator: 'Order'
+						},
+						table: 'session'
+					},
+					operation: 'Iterate Index'
+				},
+				{
+					detail: {
+						type: 'MemoryOrdered'
+					},
+					operation: 'Collector'
+				}
+			]",
+			"[
+				{
+					id: session:5,
+					time: d'2024-07-01T02:00:00Z'
+				},
+				{
+					id: session:1,
+					time: d'2024-07-01T01:00:00Z'
+				},
+				{
+					id: session:6,
+					time: d'2024-06-30T23:30:00Z'
+				}
+			]",
+		])?;
+		// The descending index is only used to order the records
+		t.expect_vals(&[
+			"[
+				{
+					detail: {
+						table: 'session'
+					},
+					operation: 'Iterate Table'
+				},
+				{
+					detail: {
+						type: 'MemoryOrdered'
+					},
+					operation: 'Collector'
+				}
+			]",
+			"[
+				{
+					detail: {
+						table: 'session'
+					},
+					operation: 'Iterate Table'
+				},
+				{
+					detail: {
+						type: 'Memory'
+					},
+					operation: 'Collector'
+				}
+			]",
+		])?;
+	}
+	//
+	Ok(())
+}
+
 async fn select_composite_index(unique: bool) -> Result<(), Error> {
 	//
 	let sql = format!(
@@ -3320,3 +3479,77 @@ async fn select_limit_start_parallel() -> Result<(), Error> {
 	}
 	Ok(())
 }
+
+#[tokio::test]
+async fn select_with_analyzed_table_statistics() -> Result<(), Error> {
+	let sql = r"
+		DEFINE INDEX email ON person FIELDS email;
+		DEFINE INDEX genre ON person FIELDS genre;
+		CREATE person:1 SET email = 'a', genre = 'm';
+		CREATE person:2 SET email = 'b', genre = 'm';
+		CREATE person:3 SET email = 'c', genre = 'm';
+		CREATE person:4 SET email = 'd', genre = 'f';
+		SELECT id FROM person WHERE email = 'a' AND genre = 'm' EXPLAIN;
+		ANALYZE TABLE person;
+		SELECT id FROM person WHERE email = 'a' AND genre = 'm' EXPLAIN;
+		SELECT id FROM person WHERE email = 'a' AND genre = 'm';
+	";
+	let mut t = Test::new(sql).await?;
+	t.expect_size(10)?;
+	t.skip_ok(6)?;
+	// Without statistics, the last index is used
+	t.expect_val(
+		"[
+			{
+				detail: {
+					plan: {
+						index: 'genre',
+						operator: '=',
+						value: 'm'
+					},
+					table: 'person'
+				},
+				operation: 'Iterate Index'
+			},
+			{
+				detail: {
+					type: 'Memory'
+				},
+				operation: 'Collector'
+			}
+		]",
+	)?;
+	t.expect_val(
+		"{
+			count: 4,
+			indexes: {
+				email: { distinct: 4, entries: 4 },
+				genre: { distinct: 2, entries: 4 }
+			}
+		}",
+	)?;
+	// With statistics, the most selective index is used
+	t.expect_val(
+		"[
+			{
+				detail: {
+					plan: {
+						index: 'email',
+						operator: '=',
+						value: 'a'
+					},
+					table: 'person'
+				},
+				operation: 'Iterate Index'
+			},
+			{
+				detail: {
+					type: 'Memory'
+				},
+				operation: 'Collector'
+			}
+		]",
+	)?;
+	t.expect_val("[{ id: person:1 }]")?;
+	Ok(())
+}
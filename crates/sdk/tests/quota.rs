@@ -0,0 +1,74 @@
+mod helpers;
+use helpers::new_ds;
+use surrealdb::dbs::quota::Quotas;
+use surrealdb::dbs::Session;
+use surrealdb::err::Error;
+use surrealdb::sql::{Thing, Value};
+
+#[tokio::test]
+async fn quotas_limit_record_users() -> Result<(), Error> {
+	let ds = new_ds().await?.with_quotas(Some(Quotas::new(Some(1), Some(2))));
+	let owner = Session::owner().with_ns("test").with_db("test");
+	let sql = "
+		DEFINE TABLE person PERMISSIONS FULL;
+		CREATE person:one, person:two, person:three;
+	";
+	for res in ds.execute(sql, &owner, None).await? {
+		res.result?;
+	}
+	// Record users can not receive too many records
+	let user = Session::for_record("test", "test", "user", Thing::from(("user", "one")).into());
+	let res =
+		&mut ds.execute("SELECT * FROM person LIMIT 2; SELECT * FROM person", &user, None).await?;
+	assert!(res.remove(0).result.is_ok());
+	assert!(matches!(res.remove(0).result, Err(Error::QuotaExceeded { .. })));
+	// Record users can not run too many queries at once
+	let (one, two) = tokio::join!(
+		ds.execute("RETURN sleep(100ms)", &user, None),
+		ds.execute("RETURN sleep(100ms)", &user, None),
+	);
+	assert!(one.is_ok());
+	assert!(matches!(two, Err(Error::QuotaExceeded { .. })));
+	// System users are not affected by the quotas
+	let res = &mut ds.execute("SELECT * FROM person", &owner, None).await?;
+	assert!(res.remove(0).result.is_ok());
+	Ok(())
+}
+
+#[tokio::test]
+async fn namespace_limits() -> Result<(), Error> {
+	let ds = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test").with_rt(true);
+	let sql = "
+		DEFINE NAMESPACE test LIMIT QUERIES 1, LIVE 1;
+		DEFINE TABLE person;
+	";
+	for res in ds.execute(sql, &ses, None).await? {
+		res.result?;
+	}
+	// Only one query can run at once in the namespace
+	let (one, two) = tokio::join!(
+		ds.execute("RETURN sleep(100ms)", &ses, None),
+		ds.execute("RETURN sleep(100ms)", &ses, None),
+	);
+	assert!(one.is_ok());
+	assert!(matches!(two, Err(Error::QuotaExceeded { .. })));
+	// Queries in other namespaces are not affected
+	let other = Session::owner().with_ns("other").with_db("test");
+	let (one, two) = tokio::join!(
+		ds.execute("RETURN sleep(100ms)", &ses, None),
+		ds.execute("RETURN sleep(100ms)", &other, None),
+	);
+	assert!(one.is_ok());
+	assert!(two.is_ok());
+	// Only one live query can be registered in the namespace
+	let res = &mut ds.execute("LIVE SELECT * FROM person", &ses, None).await?;
+	assert!(res.remove(0).result.is_ok());
+	let res = &mut ds.execute("LIVE SELECT * FROM person", &ses, None).await?;
+	assert!(matches!(res.remove(0).result, Err(Error::QuotaExceeded { .. })));
+	// The usage of the namespace is shown
+	let res = &mut ds.execute("INFO FOR NS STRUCTURE", &ses, None).await?;
+	let val = res.remove(0).result?.pick(&["usage".into()]);
+	assert_eq!(val, Value::parse("{ live: 1, queries: 1 }"));
+	Ok(())
+}
@@ -1452,3 +1452,77 @@ async fn select_from_none() -> Result<(), Error> {
 	}
 	Ok(())
 }
+
+#[tokio::test]
+async fn select_from_qualified_table() -> Result<(), Error> {
+	let sql = "
+		USE NS test DB other;
+		CREATE person:tobie SET name = 'Tobie';
+		USE NS test DB test;
+		CREATE person:jaime SET name = 'Jaime';
+		SELECT * FROM person;
+		SELECT * FROM ns:test.db:other.person;
+		SELECT name FROM ns:test.db:other.person, person ORDER BY name;
+		SELECT * FROM ns:test.db:missing.person;
+		UPDATE ns:test.db:other.person SET name = 'Tobias';
+	";
+	let mut t = Test::new(sql).await?;
+	t.skip_ok(4)?;
+	t.expect_val("[{ id: person:jaime, name: 'Jaime' }]")?;
+	t.expect_val("[{ id: person:tobie, name: 'Tobie' }]")?;
+	t.expect_val("[{ name: 'Jaime' }, { name: 'Tobie' }]")?;
+	t.expect_val("[]")?;
+	t.expect_error(
+		"Tables in other databases can only be used as the target of a SELECT statement",
+	)?;
+	// Database users can not select from other databases
+	let ses =
+		Session::for_level(("test", "test").into(), Role::Owner).with_ns("test").with_db("test");
+	let ds = new_ds().await?;
+	let mut t = Test::new_ds_session(ds, ses, "SELECT * FROM ns:test.db:other.person").await?;
+	t.expect_error("Only root and namespace users can select from tables in other databases")?;
+	Ok(())
+}
+
+#[tokio::test]
+async fn select_from_qualified_table_with_index() -> Result<(), Error> {
+	let sql = "
+		USE NS test DB other;
+		DEFINE INDEX name ON person FIELDS name;
+		CREATE person:jaime SET name = 'Jaime';
+		CREATE person:tobias SET name = 'Tobias';
+		CREATE person:tobie SET name = 'Tobie';
+		USE NS test DB test;
+		SELECT * FROM ns:test.db:other.person WHERE name = 'Tobie';
+		SELECT * FROM ns:test.db:other.person WHERE name = 'Tobie' EXPLAIN;
+		SELECT VALUE name FROM ns:test.db:other.person START 1 LIMIT 1;
+	";
+	let mut t = Test::new(sql).await?;
+	t.skip_ok(6)?;
+	t.expect_val("[{ id: person:tobie, name: 'Tobie' }]")?;
+	t.expect_val(
+		"[
+			{
+				detail: {
+					database: 'other',
+					namespace: 'test',
+					plan: {
+						index: 'name',
+						operator: '=',
+						value: 'Tobie'
+					},
+					table: 'person',
+				},
+				operation: 'Iterate Index'
+			},
+			{
+				detail: {
+					type: 'Memory'
+				},
+				operation: 'Collector'
+			}
+		]",
+	)?;
+	t.expect_val("['Tobias']")?;
+	Ok(())
+}
@@ -0,0 +1,69 @@
+mod parse;
+use parse::Parse;
+mod helpers;
+use helpers::new_ds;
+use std::sync::Arc;
+use surrealdb::dbs::Session;
+use surrealdb::err::Error;
+use surrealdb::sql::Value;
+use surrealdb::testing::{Deterministic, Faults};
+
+#[tokio::test]
+async fn testing_faults_fail_commits() -> Result<(), Error> {
+	let faults = Arc::new(Faults::new());
+	let ds = new_ds().await?.with_faults(Some(faults.clone()));
+	let ses = Session::owner().with_ns("test").with_db("test");
+	// Statements are retried after a transaction conflict
+	faults.conflict_commits(1);
+	let res = &mut ds.execute("CREATE person:one", &ses, None).await?;
+	assert!(res.remove(0).result.is_ok());
+	assert_eq!(faults.commits(), 2);
+	// Conflicts are surfaced once the retries are used up
+	faults.conflict_commits(100);
+	let res = &mut ds.execute("CREATE person:two", &ses, None).await?;
+	assert!(matches!(res.remove(0).result, Err(Error::TxRetryable)));
+	faults.conflict_commits(0);
+	// Statements with side effects are not retried
+	faults.conflict_commits(1);
+	let commits = faults.commits();
+	let sql = "CREATE person:four SET slept = sleep(1ms)";
+	let res = &mut ds.execute(sql, &ses, None).await?;
+	assert!(matches!(res.remove(0).result, Err(Error::TxRetryable)));
+	assert_eq!(faults.commits(), commits + 1);
+	// Explicit transactions are not retried, but can be retried by the client
+	faults.conflict_commits(1);
+	let res = &mut ds.execute("BEGIN; CREATE person:five; COMMIT;", &ses, None).await?;
+	assert!(matches!(res.remove(0).result, Err(Error::TxRetryable)));
+	// Failed commits are not retried
+	faults.fail_commits(1);
+	let res = &mut ds.execute("CREATE person:three", &ses, None).await?;
+	assert!(matches!(res.remove(0).result, Err(Error::QueryNotExecutedDetail { .. })));
+	// The failed transactions did not write any data
+	let res = &mut ds.execute("SELECT * FROM person", &ses, None).await?;
+	assert_eq!(res.remove(0).result?, Value::parse("[{ id: person:one }]"));
+	Ok(())
+}
+
+#[tokio::test]
+async fn testing_deterministic_generator() -> Result<(), Error> {
+	let sql = "
+		CREATE person;
+		CREATE person:uuid();
+		CREATE person:ulid();
+		RETURN time::now();
+		RETURN rand::uuid();
+	";
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let mut runs = vec![];
+	for _ in 0..2 {
+		let ds = new_ds().await?.with_generator(Some(Arc::new(Deterministic::default())));
+		let res = ds.execute(sql, &ses, None).await?;
+		let res = res.into_iter().map(|v| v.result).collect::<Result<Vec<_>, _>>()?;
+		runs.push(res);
+	}
+	// Every run returns the same results
+	assert_eq!(runs[0], runs[1]);
+	assert_eq!(runs[0][0], Value::parse("[{ id: person:⟨00000000000000000001⟩ }]"));
+	assert_eq!(runs[0][3], Value::parse("d'1970-01-01T00:00:00Z'"));
+	Ok(())
+}
@@ -200,3 +200,391 @@ async fn transaction_with_throw_and_return() -> Result<(), Error> {
 	//
 	Ok(())
 }
+
+#[tokio::test]
+async fn transaction_with_savepoint_rollback() -> Result<(), Error> {
+	let sql = "
+		BEGIN;
+		CREATE person:tobie;
+		SAVEPOINT a;
+		CREATE person:jaime;
+		UPDATE person:tobie SET name = 'Tobie';
+		ROLLBACK TO SAVEPOINT a;
+		CREATE person:jaime SET name = 'Jaime';
+		COMMIT;
+		SELECT * FROM person;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 7);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[{ id: person:tobie }]");
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result?;
+	assert_eq!(tmp, Value::None);
+	//
+	let tmp = res.remove(0).result;
+	assert!(matches!(
+		tmp.err(),
+		Some(e) if e.to_string() == r#"The query was not executed due to a cancelled transaction"#
+	));
+	//
+	let tmp = res.remove(0).result;
+	assert!(matches!(
+		tmp.err(),
+		Some(e) if e.to_string() == r#"The query was not executed due to a cancelled transaction"#
+	));
+	//
+	let tmp = res.remove(0).result?;
+	assert_eq!(tmp, Value::None);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[{ id: person:jaime, name: 'Jaime' }]");
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse(
+		"[
+			{
+				id: person:jaime,
+				name: 'Jaime',
+			},
+			{
+				id: person:tobie,
+			}
+		]",
+	);
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn transaction_with_nested_savepoints() -> Result<(), Error> {
+	let sql = "
+		BEGIN;
+		SAVEPOINT a;
+		CREATE person:tobie;
+		SAVEPOINT b;
+		CREATE person:jaime;
+		RELEASE SAVEPOINT b;
+		SAVEPOINT c;
+		CREATE person:john;
+		ROLLBACK TO c;
+		COMMIT;
+		SELECT VALUE id FROM person;
+		BEGIN;
+		SAVEPOINT a;
+		CREATE person:mary;
+		SAVEPOINT b;
+		CREATE person:jane;
+		RELEASE b;
+		ROLLBACK TO a;
+		COMMIT;
+		SELECT VALUE id FROM person;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 16);
+	//
+	let tmp = res.remove(15).result?;
+	let val = Value::parse("[person:jaime, person:tobie]");
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(8).result?;
+	let val = Value::parse("[person:jaime, person:tobie]");
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn transaction_with_savepoint_rollback_after_failure() -> Result<(), Error> {
+	let sql = "
+		BEGIN;
+		CREATE person:tobie;
+		SAVEPOINT a;
+		CREATE person:jaime;
+		CREATE person:tobie;
+		SELECT * FROM person;
+		ROLLBACK TO SAVEPOINT a;
+		CREATE person:john;
+		COMMIT;
+		SELECT VALUE id FROM person;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 8);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[{ id: person:tobie }]");
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result?;
+	assert_eq!(tmp, Value::None);
+	//
+	for _ in 0..3 {
+		let tmp = res.remove(0).result;
+		assert!(matches!(
+			tmp.err(),
+			Some(e) if e.to_string() == r#"The query was not executed due to a cancelled transaction"#
+		));
+	}
+	//
+	let tmp = res.remove(0).result?;
+	assert_eq!(tmp, Value::None);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[{ id: person:john }]");
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[person:john, person:tobie]");
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn transaction_with_savepoint_and_failure() -> Result<(), Error> {
+	let sql = "
+		BEGIN;
+		CREATE person:tobie;
+		SAVEPOINT a;
+		CREATE person:tobie;
+		CREATE person:jaime;
+		COMMIT;
+		SELECT * FROM person;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 5);
+	//
+	for _ in 0..2 {
+		let tmp = res.remove(0).result;
+		assert!(matches!(
+			tmp.err(),
+			Some(e) if e.to_string() == r#"The query was not executed due to a failed transaction"#
+		));
+	}
+	//
+	let tmp = res.remove(0).result;
+	assert!(matches!(
+		tmp.err(),
+		Some(e) if e.to_string() == r#"Database record `person:tobie` already exists"#
+	));
+	//
+	let tmp = res.remove(0).result;
+	assert!(matches!(
+		tmp.err(),
+		Some(e) if e.to_string() == r#"The query was not executed due to a failed transaction"#
+	));
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[]");
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn transaction_with_savepoint_in_block() -> Result<(), Error> {
+	let sql = "
+		BEGIN;
+		CREATE person:tobie;
+		{
+			SAVEPOINT a;
+			CREATE person:jaime;
+			ROLLBACK TO SAVEPOINT a;
+			CREATE person:john;
+			RELEASE SAVEPOINT a;
+		};
+		COMMIT;
+		SELECT VALUE id FROM person;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 3);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[{ id: person:tobie }]");
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result?;
+	assert_eq!(tmp, Value::None);
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[person:john, person:tobie]");
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn transaction_with_missing_savepoint() -> Result<(), Error> {
+	let sql = "
+		BEGIN;
+		CREATE person:tobie;
+		SAVEPOINT a;
+		RELEASE SAVEPOINT a;
+		ROLLBACK TO SAVEPOINT a;
+		COMMIT;
+		SAVEPOINT a;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 5);
+	//
+	for _ in 0..3 {
+		let tmp = res.remove(0).result;
+		assert!(matches!(
+			tmp.err(),
+			Some(e) if e.to_string() == r#"The query was not executed due to a failed transaction"#
+		));
+	}
+	//
+	let tmp = res.remove(0).result;
+	assert!(matches!(
+		tmp.err(),
+		Some(e) if e.to_string() == r#"The savepoint 'a' does not exist"#
+	));
+	//
+	let tmp = res.remove(0).result;
+	assert!(matches!(
+		tmp.err(),
+		Some(e) if e.to_string() == r#"Savepoints can only be used within a transaction"#
+	));
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn transaction_readonly() -> Result<(), Error> {
+	let sql = "
+		CREATE person:tobie;
+		BEGIN READONLY;
+		SELECT * FROM person;
+		COMMIT;
+		BEGIN READONLY;
+		SELECT * FROM person;
+		CREATE person:jaime;
+		COMMIT;
+		SELECT * FROM person;
+	";
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 5);
+	//
+	res.remove(0).result?;
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[{ id: person:tobie }]");
+	assert_eq!(tmp, val);
+	//
+	let tmp = res.remove(0).result;
+	assert!(matches!(tmp, Err(Error::QueryNotExecuted)));
+	//
+	let tmp = res.remove(0).result;
+	assert!(matches!(tmp, Err(Error::ReadOnly)));
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[{ id: person:tobie }]");
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn transaction_read_only_datastore() -> Result<(), Error> {
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	dbs.execute("CREATE person:tobie", &ses, None).await?.remove(0).result?;
+	let dbs = dbs.with_read_only(true);
+	let sql = "
+		CREATE person:jaime;
+		BEGIN;
+		UPDATE person:tobie SET name = 'Tobie';
+		COMMIT;
+		SELECT * FROM person;
+	";
+	let res = &mut dbs.execute(sql, &ses, None).await?;
+	assert_eq!(res.len(), 3);
+	//
+	let tmp = res.remove(0).result;
+	assert!(matches!(tmp, Err(Error::ReadOnly)));
+	//
+	let tmp = res.remove(0).result;
+	assert!(matches!(tmp, Err(Error::ReadOnly)));
+	//
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[{ id: person:tobie }]");
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn transaction_conflicts_are_retried() -> Result<(), Error> {
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	dbs.execute("CREATE counter:one SET count = 0", &ses, None).await?.remove(0).result?;
+	// Concurrent updates of the same record conflict with each other
+	let sql = "UPDATE counter:one SET count += 1";
+	let (one, two, three, four) = tokio::join!(
+		dbs.execute(sql, &ses, None),
+		dbs.execute(sql, &ses, None),
+		dbs.execute(sql, &ses, None),
+		dbs.execute(sql, &ses, None),
+	);
+	for res in [one?, two?, three?, four?] {
+		for res in res {
+			res.result?;
+		}
+	}
+	let res = &mut dbs.execute("SELECT * FROM counter", &ses, None).await?;
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[{ id: counter:one, count: 4 }]");
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
+
+#[tokio::test]
+async fn transaction_commit_conflicts_are_retryable() -> Result<(), Error> {
+	let dbs = new_ds().await?;
+	let ses = Session::owner().with_ns("test").with_db("test");
+	dbs.execute("CREATE counter:one SET count = 0", &ses, None).await?.remove(0).result?;
+	// The slower transaction reads the record before the faster transaction commits
+	let slow = "BEGIN; UPDATE counter:one SET count += 1; SLEEP 100ms; COMMIT;";
+	let fast = "BEGIN; SLEEP 10ms; UPDATE counter:one SET count += 10; COMMIT;";
+	let (slow, fast) = tokio::join!(dbs.execute(slow, &ses, None), dbs.execute(fast, &ses, None));
+	for res in fast? {
+		res.result?;
+	}
+	// The statements of the conflicting transaction can be retried
+	let slow = slow?;
+	assert_eq!(slow.len(), 2);
+	for res in slow {
+		assert!(
+			matches!(res.result, Err(Error::TxRetryable)),
+			"Unexpected result: {:?}",
+			res.result
+		);
+	}
+	// Only the faster transaction was committed
+	let res = &mut dbs.execute("SELECT * FROM counter", &ses, None).await?;
+	let tmp = res.remove(0).result?;
+	let val = Value::parse("[{ id: counter:one, count: 10 }]");
+	assert_eq!(tmp, val);
+	//
+	Ok(())
+}
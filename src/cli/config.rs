@@ -1,5 +1,6 @@
 use crate::net::client_ip::ClientIp;
 use std::sync::OnceLock;
+use std::time::Duration;
 use std::{net::SocketAddr, path::PathBuf};
 use surrealdb::options::EngineOptions;
 
@@ -14,6 +15,8 @@ pub struct Config {
 	pub pass: Option<String>,
 	pub crt: Option<PathBuf>,
 	pub key: Option<PathBuf>,
+	pub client_ca: Option<PathBuf>,
 	pub engine: EngineOptions,
 	pub no_identification_headers: bool,
+	pub shutdown_grace_period: Duration,
 }
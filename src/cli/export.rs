@@ -6,6 +6,7 @@ use crate::err::Error;
 use clap::Args;
 use futures_util::StreamExt;
 use surrealdb::engine::any::{connect, IntoEndpoint};
+use surrealdb::kvs::export::TableConfig;
 use tokio::io::{self, AsyncWriteExt};
 
 #[derive(Args, Debug)]
@@ -14,6 +15,13 @@ pub struct ExportCommandArguments {
 	#[arg(default_value = "-")]
 	#[arg(index = 1)]
 	file: String,
+	#[arg(help = "Only export the specified tables, as a comma-separated list")]
+	#[arg(long, value_delimiter = ',')]
+	tables: Option<Vec<String>>,
+	#[arg(help = "Whether to export only the table definitions, without any records")]
+	#[arg(long)]
+	#[arg(default_value_t = false)]
+	no_records: bool,
 	#[command(flatten)]
 	conn: DatabaseConnectionArguments,
 	#[command(flatten)]
@@ -25,6 +33,8 @@ pub struct ExportCommandArguments {
 pub async fn init(
 	ExportCommandArguments {
 		file,
+		tables,
+		no_records,
 		conn: DatabaseConnectionArguments {
 			endpoint,
 		},
@@ -75,11 +85,17 @@ pub async fn init(
 
 	// Use the specified namespace / database
 	client.use_ns(namespace).use_db(database).await?;
+	// Select the tables to export
+	let tables = match tables {
+		Some(tables) => TableConfig::from(tables),
+		None => TableConfig::All,
+	};
 	// Export the data from the database
 	debug!("Exporting data from the database");
 	if file == "-" {
 		// Prepare the backup
-		let mut backup = client.export(()).await?;
+		let mut backup =
+			client.export(()).with_config().tables(tables).records(!no_records).await?;
 		// Get a handle to standard output
 		let mut stdout = io::stdout();
 		// Write the backup to standard output
@@ -87,7 +103,7 @@ pub async fn init(
 			stdout.write_all(&bytes?).await?;
 		}
 	} else {
-		client.export(file).await?;
+		client.export(file).with_config().tables(tables).records(!no_records).await?;
 	}
 	info!("The SurrealQL file was exported successfully");
 	// Everything OK
@@ -0,0 +1,98 @@
+use crate::err::Error;
+use clap::Args;
+use glob::glob;
+use std::io::{Error as IoError, ErrorKind};
+use surrealdb::sql::format;
+use tokio::io::{self, AsyncReadExt};
+
+#[derive(Args, Debug)]
+pub struct FmtCommandArguments {
+	#[arg(help = "Glob pattern for the files to format, or standard input if none are given")]
+	patterns: Vec<String>,
+	#[arg(help = "Only check whether the files are formatted, without changing them")]
+	#[arg(long)]
+	check: bool,
+}
+
+pub async fn init(args: FmtCommandArguments) -> Result<(), Error> {
+	let FmtCommandArguments {
+		patterns,
+		check,
+	} = args;
+	// Format the standard input when no files are specified
+	if patterns.is_empty() {
+		let mut input = String::new();
+		io::stdin().read_to_string(&mut input).await?;
+		let output = format(&input)?;
+		if check {
+			if output != input.trim_end() {
+				return Err(Error::Io(IoError::new(
+					ErrorKind::Other,
+					"The input is not formatted".to_string(),
+				)));
+			}
+		} else {
+			println!("{output}");
+		}
+		return Ok(());
+	}
+
+	let mut entries = vec![];
+
+	for pattern in patterns {
+		let pattern_entries = match glob(&pattern) {
+			Ok(entries) => entries,
+			Err(error) => {
+				eprintln!("Error parsing glob pattern {pattern}: {error}");
+
+				return Err(Error::Io(IoError::new(
+					ErrorKind::Other,
+					format!("Error parsing glob pattern {pattern}: {error}"),
+				)));
+			}
+		};
+
+		entries.extend(pattern_entries.flatten());
+	}
+
+	if entries.is_empty() {
+		eprintln!("No files found");
+		return Err(Error::Io(IoError::new(ErrorKind::NotFound, "No files found".to_string())));
+	}
+
+	let mut unformatted = 0;
+
+	for entry in entries {
+		let file_content = tokio::fs::read_to_string(&entry).await?;
+		let output = match format(&file_content) {
+			Ok(output) => output,
+			Err(error) => {
+				println!("{}: KO", entry.display());
+				eprintln!("{error}");
+
+				return Err(Error::from(error));
+			}
+		};
+
+		if output == file_content.trim_end() {
+			continue;
+		}
+
+		if check {
+			println!("{}: not formatted", entry.display());
+			unformatted += 1;
+		} else {
+			tokio::fs::write(&entry, format!("{output}\n")).await?;
+			println!("{}: formatted", entry.display());
+		}
+	}
+
+	if unformatted > 0 {
+		return Err(Error::Io(IoError::new(
+			ErrorKind::Other,
+			format!("{unformatted} files are not formatted"),
+		)));
+	}
+
+	Ok(())
+}
@@ -0,0 +1,152 @@
+use crate::cli::abstraction::auth::{CredentialsBuilder, CredentialsLevel};
+use crate::cli::abstraction::{
+	AuthArguments, DatabaseConnectionArguments, DatabaseSelectionArguments,
+};
+use crate::err::Error;
+use clap::Args;
+use surrealdb::engine::any::{connect, IntoEndpoint};
+use surrealdb::kvs::csv::{self, Importer};
+use surrealdb::opt::{capabilities::Capabilities, Config};
+use surrealdb::sql::Query;
+use tokio::fs::File;
+use tokio::io::AsyncReadExt;
+
+#[derive(Args, Debug)]
+pub struct ImportCsvCommandArguments {
+	#[arg(help = "Path to the CSV file to import")]
+	#[arg(index = 1)]
+	file: String,
+	#[arg(help = "The table into which the records are imported")]
+	#[arg(long)]
+	table: String,
+	#[arg(help = "The character which separates the fields in each record")]
+	#[arg(long)]
+	#[arg(default_value_t = ',')]
+	delimiter: char,
+	#[arg(help = "Whether the first record is data, rather than the column names")]
+	#[arg(long)]
+	#[arg(default_value_t = false)]
+	no_header: bool,
+	#[arg(help = "The column names, as a comma-separated list")]
+	#[arg(long, value_delimiter = ',')]
+	columns: Option<Vec<String>>,
+	#[arg(help = "The type of a column, specified as column:kind")]
+	#[arg(long = "type")]
+	types: Vec<String>,
+	#[arg(help = "An expression which is evaluated against each record before it is inserted")]
+	#[arg(long)]
+	transform: Option<String>,
+	#[command(flatten)]
+	conn: DatabaseConnectionArguments,
+	#[command(flatten)]
+	auth: AuthArguments,
+	#[command(flatten)]
+	sel: DatabaseSelectionArguments,
+}
+
+pub async fn init(
+	ImportCsvCommandArguments {
+		file,
+		table,
+		delimiter,
+		no_header,
+		columns,
+		types,
+		transform,
+		conn: DatabaseConnectionArguments {
+			endpoint,
+		},
+		auth: AuthArguments {
+			username,
+			password,
+			token,
+			auth_level,
+		},
+		sel: DatabaseSelectionArguments {
+			namespace,
+			database,
+		},
+	}: ImportCsvCommandArguments,
+) -> Result<(), Error> {
+	// Setup the import options
+	let mut cfg = csv::Config::new(table);
+	cfg.delimiter = match delimiter.is_ascii() {
+		true => delimiter as u8,
+		false => return Err(Error::Other("The delimiter must be an ASCII character".to_owned())),
+	};
+	cfg.header = !no_header;
+	cfg.columns = columns;
+	for v in types.iter() {
+		let (column, kind) = csv::column_type(v)?;
+		cfg.types.insert(column, kind);
+	}
+	if let Some(transform) = transform {
+		cfg.transform = Some(surrealdb::syn::value(&transform)?);
+	}
+	// Default datastore configuration for local engines
+	let config = Config::new().capabilities(Capabilities::all());
+	// If username and password are specified, and we are connecting to a remote SurrealDB server, then we need to authenticate.
+	// If we are connecting directly to a datastore (i.e. surrealkv://local.skv or tikv://...), then we don't need to authenticate because we use an embedded (local) SurrealDB instance with auth disabled.
+	let client = if username.is_some()
+		&& password.is_some()
+		&& !endpoint.clone().into_endpoint()?.parse_kind()?.is_local()
+	{
+		debug!("Connecting to the database engine with authentication");
+		let creds = CredentialsBuilder::default()
+			.with_username(username.as_deref())
+			.with_password(password.as_deref())
+			.with_namespace(namespace.as_str())
+			.with_database(database.as_str());
+
+		let client = connect(endpoint).await?;
+
+		debug!("Signing in to the database engine at '{:?}' level", auth_level);
+		match auth_level {
+			CredentialsLevel::Root => client.signin(creds.root()?).await?,
+			CredentialsLevel::Namespace => client.signin(creds.namespace()?).await?,
+			CredentialsLevel::Database => client.signin(creds.database()?).await?,
+		};
+
+		client
+	} else if token.is_some() && !endpoint.clone().into_endpoint()?.parse_kind()?.is_local() {
+		let client = connect(endpoint).await?;
+		client.authenticate(token.unwrap()).await?;
+
+		client
+	} else {
+		debug!("Connecting to the database engine without authentication");
+		connect((endpoint, config)).await?
+	};
+
+	// Use the specified namespace / database
+	client.use_ns(namespace).use_db(database).await?;
+	// Import the records into the table
+	let mut file = File::open(file).await?;
+	let mut importer = Importer::new(cfg);
+	let mut buffer = vec![0; 64 * 1024];
+	let mut records = Vec::new();
+	let mut count = 0;
+	loop {
+		let size = file.read(&mut buffer).await?;
+		match size {
+			0 => records.extend(importer.finish()?),
+			_ => records.extend(importer.push(&buffer[..size])?),
+		}
+		// Insert each full batch of records
+		while records.len() >= *surrealdb::cnf::CSV_IMPORT_BATCH_SIZE
+			|| (size == 0 && !records.is_empty())
+		{
+			let batch = records.len().min(*surrealdb::cnf::CSV_IMPORT_BATCH_SIZE);
+			let batch: Vec<_> = records.drain(..batch).collect();
+			count += batch.len();
+			client.query(Query::from(importer.statement(batch))).await?.check()?;
+			debug!("Imported {count} records into the table");
+		}
+		if size == 0 {
+			break;
+		}
+	}
+	info!("The CSV file was imported successfully, with {count} records");
+	// All ok
+	Ok(())
+}
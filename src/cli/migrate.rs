@@ -0,0 +1,315 @@
+use crate::cli::abstraction::auth::{CredentialsBuilder, CredentialsLevel};
+use crate::cli::abstraction::{
+	AuthArguments, DatabaseConnectionArguments, DatabaseSelectionArguments,
+};
+use crate::err::Error;
+use clap::Args;
+use glob::glob;
+use std::collections::BTreeMap;
+use std::io::{Error as IoError, ErrorKind};
+use std::path::{Path, PathBuf};
+use surrealdb::engine::any::{connect, Any, IntoEndpoint};
+use surrealdb::opt::{capabilities::Capabilities, Config};
+use surrealdb::sql::statements::DefineStatement;
+use surrealdb::sql::{parse, Base, Ident, Statement, Value as CoreValue};
+use surrealdb::{Surreal, Value};
+
+#[derive(Args, Debug)]
+pub struct MigrateCommandArguments {
+	#[arg(help = "Path to the directory containing the SurrealQL schema files")]
+	#[arg(index = 1)]
+	dir: PathBuf,
+	#[arg(help = "Only print the migration, without applying it to the database")]
+	#[arg(long)]
+	dry_run: bool,
+	#[command(flatten)]
+	conn: DatabaseConnectionArguments,
+	#[command(flatten)]
+	auth: AuthArguments,
+	#[command(flatten)]
+	sel: DatabaseSelectionArguments,
+}
+
+/// The kinds of definition which can be migrated, in the order they are applied
+#[derive(Clone, Copy, Debug, Eq, Ord, PartialEq, PartialOrd)]
+enum Kind {
+	Param,
+	Function,
+	Analyzer,
+	Access,
+	User,
+	Table,
+	Field,
+	Index,
+	Event,
+}
+
+impl Kind {
+	/// The key of the definitions of this kind in the output of the INFO statement
+	fn info_key(&self) -> &'static str {
+		match self {
+			Kind::Param => "params",
+			Kind::Function => "functions",
+			Kind::Analyzer => "analyzers",
+			Kind::Access => "accesses",
+			Kind::User => "users",
+			Kind::Table => "tables",
+			Kind::Field => "fields",
+			Kind::Index => "indexes",
+			Kind::Event => "events",
+		}
+	}
+
+	/// Whether the stored definition can be compared with the schema file.
+	/// The secrets of users and accesses are hashed or redacted once stored,
+	/// so these definitions are only created when they do not exist yet.
+	fn comparable(&self) -> bool {
+		!matches!(self, Kind::Access | Kind::User)
+	}
+}
+
+/// A definition from the schema files
+struct Definition {
+	kind: Kind,
+	/// The table of a field, index, or event
+	table: Option<String>,
+	name: String,
+	statement: DefineStatement,
+}
+
+impl Definition {
+	fn new(mut statement: DefineStatement) -> Result<Self, Error> {
+		let (kind, table, name) = match &statement {
+			DefineStatement::Param(s) => (Kind::Param, None, s.name.to_raw()),
+			DefineStatement::Function(s) => (Kind::Function, None, s.name.to_raw()),
+			DefineStatement::Analyzer(s) => (Kind::Analyzer, None, s.name.to_raw()),
+			DefineStatement::Access(s) if s.base == Base::Db => {
+				(Kind::Access, None, s.name.to_raw())
+			}
+			DefineStatement::User(s) if s.base == Base::Db => (Kind::User, None, s.name.to_raw()),
+			DefineStatement::Table(s) => (Kind::Table, None, s.name.to_raw()),
+			DefineStatement::Field(s) => (Kind::Field, Some(s.what.to_raw()), s.name.to_string()),
+			DefineStatement::Index(s) => (Kind::Index, Some(s.what.to_raw()), s.name.to_raw()),
+			DefineStatement::Event(s) => (Kind::Event, Some(s.what.to_raw()), s.name.to_raw()),
+			s => {
+				return Err(Error::Other(format!(
+					"Only database definitions can be migrated, found: {s}"
+				)))
+			}
+		};
+		// Compare the definition as it is stored
+		set_overwrite(&mut statement, false);
+		Ok(Self {
+			kind,
+			table,
+			name,
+			statement,
+		})
+	}
+}
+
+/// Set whether a definition replaces an existing definition, and remove any
+/// IF NOT EXISTS clause, which is not kept once a definition is stored
+fn set_overwrite(statement: &mut DefineStatement, overwrite: bool) {
+	macro_rules! set {
+		($s:expr) => {{
+			$s.if_not_exists = false;
+			$s.overwrite = overwrite;
+		}};
+	}
+	match statement {
+		DefineStatement::Param(s) => set!(s),
+		DefineStatement::Function(s) => set!(s),
+		DefineStatement::Analyzer(s) => set!(s),
+		DefineStatement::Access(s) => set!(s),
+		DefineStatement::User(s) => set!(s),
+		DefineStatement::Table(s) => set!(s),
+		DefineStatement::Field(s) => set!(s),
+		DefineStatement::Index(s) => set!(s),
+		DefineStatement::Event(s) => set!(s),
+		_ => {}
+	}
+}
+
+pub async fn init(
+	MigrateCommandArguments {
+		dir,
+		dry_run,
+		conn: DatabaseConnectionArguments {
+			endpoint,
+		},
+		auth: AuthArguments {
+			username,
+			password,
+			token,
+			auth_level,
+		},
+		sel: DatabaseSelectionArguments {
+			namespace,
+			database,
+		},
+	}: MigrateCommandArguments,
+) -> Result<(), Error> {
+	// Parse and validate the schema files, before connecting to the database
+	let definitions = schema(&dir).await?;
+	// Default datastore configuration for local engines
+	let config = Config::new().capabilities(Capabilities::all());
+	// If username and password are specified, and we are connecting to a remote SurrealDB server, then we need to authenticate.
+	// If we are connecting directly to a datastore (i.e. surrealkv://local.skv or tikv://...), then we don't need to authenticate because we use an embedded (local) SurrealDB instance with auth disabled.
+	let client = if username.is_some()
+		&& password.is_some()
+		&& !endpoint.clone().into_endpoint()?.parse_kind()?.is_local()
+	{
+		debug!("Connecting to the database engine with authentication");
+		let creds = CredentialsBuilder::default()
+			.with_username(username.as_deref())
+			.with_password(password.as_deref())
+			.with_namespace(namespace.as_str())
+			.with_database(database.as_str());
+
+		let client = connect(endpoint).await?;
+
+		debug!("Signing in to the database engine at '{:?}' level", auth_level);
+		match auth_level {
+			CredentialsLevel::Root => client.signin(creds.root()?).await?,
+			CredentialsLevel::Namespace => client.signin(creds.namespace()?).await?,
+			CredentialsLevel::Database => client.signin(creds.database()?).await?,
+		};
+
+		client
+	} else if token.is_some() && !endpoint.clone().into_endpoint()?.parse_kind()?.is_local() {
+		let client = connect(endpoint).await?;
+		client.authenticate(token.unwrap()).await?;
+
+		client
+	} else {
+		debug!("Connecting to the database engine without authentication");
+		connect((endpoint, config)).await?
+	};
+
+	// Use the specified namespace / database
+	client.use_ns(namespace).use_db(database).await?;
+	// Find the definitions which differ from the database
+	let migration = diff(&client, definitions).await?;
+	if migration.is_empty() {
+		info!("The database is already up to date with the schema files");
+		return Ok(());
+	}
+	let migration = migration.iter().map(|s| format!("{s};")).collect::<Vec<_>>().join("\n");
+	if dry_run {
+		println!("{migration}");
+		return Ok(());
+	}
+	// Apply the migration in a single transaction
+	client.query(format!("BEGIN TRANSACTION;\n{migration}\nCOMMIT TRANSACTION;")).await?.check()?;
+	info!("The schema files were migrated successfully");
+	// All ok
+	Ok(())
+}
+
+/// Parse the definitions of the SurrealQL schema files in a directory.
+/// The definitions are ordered by kind, and then by the order of the
+/// files and of the statements in each file.
+async fn schema(dir: &Path) -> Result<Vec<Definition>, Error> {
+	let pattern = dir.join("**").join("*.surql");
+	let pattern = pattern.to_string_lossy();
+	let mut entries = match glob(&pattern) {
+		Ok(entries) => entries.flatten().collect::<Vec<_>>(),
+		Err(error) => {
+			return Err(Error::Io(IoError::new(
+				ErrorKind::Other,
+				format!("Error parsing glob pattern {pattern}: {error}"),
+			)));
+		}
+	};
+	if entries.is_empty() {
+		return Err(Error::Io(IoError::new(ErrorKind::NotFound, "No files found".to_string())));
+	}
+	entries.sort();
+
+	let mut definitions = vec![];
+
+	for entry in entries {
+		let file_content = tokio::fs::read_to_string(&entry).await?;
+		let query = match parse(&file_content) {
+			Ok(query) => query,
+			Err(error) => {
+				eprintln!("{}: KO", entry.display());
+				return Err(Error::from(error));
+			}
+		};
+		for statement in query.iter() {
+			match statement {
+				Statement::Define(s) => definitions.push(Definition::new(s.clone())?),
+				s => {
+					return Err(Error::Other(format!(
+						"{}: Schema files can only contain DEFINE statements, found: {s}",
+						entry.display()
+					)))
+				}
+			}
+		}
+	}
+	// Define each kind of resource before the resources which depend on it
+	definitions.sort_by_key(|d| d.kind);
+
+	Ok(definitions)
+}
+
+/// The definitions which are missing from the database, or which are
+/// different in the database, as the statements which define them
+async fn diff(
+	client: &Surreal<Any>,
+	definitions: Vec<Definition>,
+) -> Result<Vec<DefineStatement>, Error> {
+	let db = info(client, "INFO FOR DB".to_string()).await?;
+	let mut tables = BTreeMap::new();
+	let mut migration = vec![];
+
+	for mut definition in definitions {
+		let current = match &definition.table {
+			None => defined(&db, definition.kind),
+			Some(tb) => {
+				if !tables.contains_key(tb) {
+					// Tables which are not defined yet have no definitions
+					let table = match defined(&db, Kind::Table).contains_key(tb) {
+						true => {
+							info(client, format!("INFO FOR TABLE {}", Ident::from(tb.as_str())))
+								.await?
+						}
+						false => CoreValue::None,
+					};
+					tables.insert(tb.clone(), table);
+				}
+				defined(&tables[tb], definition.kind)
+			}
+		};
+		match current.get(&definition.name) {
+			// The definition does not exist yet
+			None => migration.push(definition.statement),
+			// The definition has changed
+			Some(v) if definition.kind.comparable() && *v != definition.statement.to_string() => {
+				set_overwrite(&mut definition.statement, true);
+				migration.push(definition.statement);
+			}
+			// The definition is up to date
+			Some(_) => {}
+		}
+	}
+
+	Ok(migration)
+}
+
+/// Run an INFO statement
+async fn info(client: &Surreal<Any>, query: String) -> Result<CoreValue, Error> {
+	let value: Value = client.query(query).await?.take(0)?;
+	Ok(value.into_inner())
+}
+
+/// The definitions of a kind of resource in the output of an INFO statement
+fn defined(info: &CoreValue, kind: Kind) -> BTreeMap<String, String> {
+	match info.pick(&[kind.info_key().into()]) {
+		CoreValue::Object(v) => v.0.into_iter().map(|(k, v)| (k, v.as_raw_string())).collect(),
+		_ => BTreeMap::new(),
+	}
+}
@@ -2,9 +2,14 @@ pub(crate) mod abstraction;
 mod config;
 mod export;
 mod fix;
+mod fmt;
 mod import;
+mod import_csv;
 mod isready;
+mod migrate;
 mod ml;
+mod rekey;
+mod settings;
 mod sql;
 mod start;
 #[cfg(test)]
@@ -22,14 +27,18 @@ use crate::cli::version_client::VersionClient;
 use crate::cnf::DEBUG_BUILD_WARNING;
 use crate::cnf::{LOGO, PKG_VERSION};
 use crate::env::RELEASE;
-use clap::{Parser, Subcommand};
+use clap::{CommandFactory, Parser, Subcommand};
 pub use config::CF;
 use export::ExportCommandArguments;
 use fix::FixCommandArguments;
 use import::ImportCommandArguments;
+use import_csv::ImportCsvCommandArguments;
 use isready::IsReadyCommandArguments;
+use migrate::MigrateCommandArguments;
 use ml::MlCommand;
+use rekey::RekeyCommandArguments;
 use semver::Version;
+use settings::ConfigCommand;
 use sql::SqlCommandArguments;
 use start::StartCommandArguments;
 use std::ops::Deref;
@@ -65,6 +74,15 @@ struct Cli {
 	#[arg(default_value = "info")]
 	#[arg(value_parser = CustomEnvFilterParser::new())]
 	log: CustomEnvFilter,
+	#[arg(help = "The telemetry provider to which traces and metrics are sent")]
+	#[arg(env = "SURREAL_TELEMETRY_PROVIDER", long = "telemetry-provider")]
+	#[arg(global = true)]
+	#[arg(value_parser = ["otlp"])]
+	telemetry_provider: Option<String>,
+	#[arg(help = "The endpoint of the OTLP collector to which telemetry is sent")]
+	#[arg(env = "OTEL_EXPORTER_OTLP_ENDPOINT", long = "telemetry-endpoint")]
+	#[arg(global = true)]
+	telemetry_endpoint: Option<String>,
 	#[arg(help = "Whether to allow web check for client version upgrades at start")]
 	#[arg(env = "SURREAL_ONLINE_VERSION_CHECK", long)]
 	#[arg(default_value_t = true)]
@@ -82,13 +100,18 @@ enum Commands {
 	*/
 	#[command(about = "Import a SurrealQL script into an existing database")]
 	Import(ImportCommandArguments),
+	#[command(about = "Import a CSV file into a table of an existing database")]
+	ImportCsv(ImportCsvCommandArguments),
 	#[command(about = "Export an existing database as a SurrealQL script")]
 	Export(ExportCommandArguments),
 	#[command(about = "Output the command-line tool and remote server version information")]
 	Version(VersionCommandArguments),
 	#[command(about = "Upgrade to the latest stable version")]
 	Upgrade(UpgradeCommandArguments),
-	#[command(about = "Start an SQL REPL in your terminal with pipe support")]
+	#[command(
+		about = "Start an SQL REPL in your terminal with pipe support",
+		args_conflicts_with_subcommands = true
+	)]
 	Sql(SqlCommandArguments),
 	#[command(subcommand, about = "Manage SurrealML models within an existing database")]
 	Ml(MlCommand),
@@ -99,8 +122,14 @@ enum Commands {
 	IsReady(IsReadyCommandArguments),
 	#[command(about = "Validate SurrealQL query files")]
 	Validate(ValidateCommandArguments),
+	#[command(about = "Migrate an existing database to the definitions of SurrealQL schema files")]
+	Migrate(MigrateCommandArguments),
 	#[command(about = "Fix database storage issues")]
 	Fix(FixCommandArguments),
+	#[command(about = "Change the key used for on-disk encryption of an offline datastore")]
+	Rekey(RekeyCommandArguments),
+	#[command(subcommand, about = "Manage the configuration files of the database server")]
+	Config(ConfigCommand),
 }
 
 pub async fn init() -> ExitCode {
@@ -114,8 +143,16 @@ pub async fn init() -> ExitCode {
 		.blocklist(&["libc", "libgcc", "pthread", "vdso"])
 		.build()
 		.unwrap();
+	// Add the options of any server configuration file
+	let args = match settings::merge(Cli::command(), std::env::args_os().collect()) {
+		Ok(args) => args,
+		Err(e) => {
+			eprintln!("{e}");
+			return ExitCode::FAILURE;
+		}
+	};
 	// Parse the CLI arguments
-	let args = Cli::parse();
+	let args = Cli::parse_from(args);
 	// After parsing arguments, we check the version online
 	if args.online_version_check {
 		let client = version_client::new(Some(Duration::from_millis(500))).unwrap();
@@ -133,13 +170,17 @@ pub async fn init() -> ExitCode {
 		}
 	}
 	// Initialize opentelemetry and logging
-	let telemetry = crate::telemetry::builder().with_log_level("info").with_filter(args.log);
+	let telemetry = crate::telemetry::builder()
+		.with_log_level("info")
+		.with_filter(args.log)
+		.with_telemetry(args.telemetry_provider, args.telemetry_endpoint);
 	// Extract the telemetry log guards
 	let (outg, errg) = telemetry.init().expect("Unable to configure logs");
 	// After version warning we can run the respective command
 	let output = match args.command {
 		Commands::Start(args) => start::init(args).await,
 		Commands::Import(args) => import::init(args).await,
+		Commands::ImportCsv(args) => import_csv::init(args).await,
 		Commands::Export(args) => export::init(args).await,
 		Commands::Version(args) => version::init(args).await,
 		Commands::Upgrade(args) => upgrade::init(args).await,
@@ -147,7 +188,10 @@ pub async fn init() -> ExitCode {
 		Commands::Ml(args) => ml::init(args).await,
 		Commands::IsReady(args) => isready::init(args).await,
 		Commands::Validate(args) => validate::init(args).await,
+		Commands::Migrate(args) => migrate::init(args).await,
 		Commands::Fix(args) => fix::init(args).await,
+		Commands::Rekey(args) => rekey::init(args).await,
+		Commands::Config(args) => settings::init(Cli::command(), args).await,
 	};
 	// Save the flamegraph and profile
 	#[cfg(feature = "performance-profiler")]
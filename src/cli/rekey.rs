@@ -0,0 +1,40 @@
+use crate::dbs;
+use crate::err::Error;
+use clap::Args;
+use surrealdb::engine::any::IntoEndpoint;
+
+#[derive(Args, Debug)]
+pub struct RekeyCommandArguments {
+	#[arg(help = "Database path used for storing data")]
+	#[arg(env = "SURREAL_PATH", index = 1)]
+	#[arg(value_parser = super::validator::path_valid)]
+	path: String,
+	#[arg(help = "The encryption key currently used for on-disk encryption, if any")]
+	#[arg(env = "SURREAL_KEY", short = 'k', long = "key")]
+	#[arg(value_parser = super::validator::key_valid)]
+	key: Option<String>,
+	#[arg(help = "The new encryption key, or none to store the data unencrypted")]
+	#[arg(env = "SURREAL_NEW_KEY", long = "new-key")]
+	#[arg(value_parser = super::validator::key_valid)]
+	new_key: Option<String>,
+}
+
+pub async fn init(
+	RekeyCommandArguments {
+		path,
+		key,
+		new_key,
+	}: RekeyCommandArguments,
+) -> Result<(), Error> {
+	// Clean the path
+	let endpoint = path.into_endpoint()?;
+	let path = if endpoint.path.is_empty() {
+		endpoint.url.to_string()
+	} else {
+		endpoint.path
+	};
+	// Re-encrypt the datastore
+	dbs::rekey(path, key, new_key).await?;
+	// All ok
+	Ok(())
+}
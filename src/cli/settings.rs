@@ -0,0 +1,207 @@
+use crate::err::Error;
+use clap::parser::ValueSource;
+use clap::{ArgAction, Args, Command, Subcommand};
+use std::ffi::OsString;
+use std::path::{Path, PathBuf};
+use toml_edit::{Document, Item, Value};
+
+#[derive(Debug, Subcommand)]
+pub enum ConfigCommand {
+	#[command(about = "Check that a server configuration file is valid")]
+	Check(CheckCommandArguments),
+}
+
+#[derive(Args, Debug)]
+pub struct CheckCommandArguments {
+	#[arg(help = "Path to the TOML configuration file for the server")]
+	#[arg(index = 1)]
+	file: PathBuf,
+}
+
+pub async fn init(cmd: Command, command: ConfigCommand) -> Result<(), Error> {
+	match command {
+		ConfigCommand::Check(CheckCommandArguments {
+			file,
+		}) => {
+			// Check the file as it would be used to start the server
+			let args = vec!["surreal".into(), "start".into(), "--config".into(), file.into()];
+			let args = merge(cmd.clone(), args)?;
+			// Validate the merged options
+			if let Err(e) = cmd.try_get_matches_from(args) {
+				return Err(Error::Other(e.render().to_string()));
+			}
+			println!("The configuration file is valid");
+			Ok(())
+		}
+	}
+}
+
+/// Add the options of the server configuration file to the command-line
+/// arguments. Options which are also specified as command-line arguments
+/// or as environment variables take precedence over the configuration file.
+pub fn merge(cmd: Command, args: Vec<OsString>) -> Result<Vec<OsString>, Error> {
+	// Parse the arguments as they were specified, any
+	// errors are reported when the arguments are parsed
+	let Ok(matches) = cmd.clone().try_get_matches_from(&args) else {
+		return Ok(args);
+	};
+	// The configuration file only applies to the server
+	let Some(("start", matches)) = matches.subcommand() else {
+		return Ok(args);
+	};
+	let Some(file) = matches.get_one::<PathBuf>("config") else {
+		return Ok(args);
+	};
+	let Some(start) = cmd.find_subcommand("start") else {
+		return Ok(args);
+	};
+	let mut args = args;
+	let mut positional = vec![];
+	for (key, item) in read(file)?.iter() {
+		// Find the command-line argument for this option
+		let name = key.replace('_', "-");
+		let Some(arg) = start.get_arguments().find(|a| match a.get_long() {
+			Some(long) => long == name,
+			None => a.is_positional() && a.get_id().as_str() == key,
+		}) else {
+			return Err(Error::Other(format!(
+				"Unknown option '{key}' in the configuration file {}",
+				file.display()
+			)));
+		};
+		// Values from the command line or the environment take precedence
+		if matches!(
+			matches.value_source(arg.get_id().as_str()),
+			Some(ValueSource::CommandLine | ValueSource::EnvVariable)
+		) {
+			continue;
+		}
+		let values = values(key, item)?;
+		match arg.get_long() {
+			// Flags are only specified when they are enabled
+			Some(long) if !arg.get_action().takes_values() => match values.as_slice() {
+				[v] if v == "true" => args.push(format!("--{long}").into()),
+				[v] if v == "false" => {}
+				_ => {
+					return Err(Error::Other(format!(
+						"The option '{key}' in the configuration file must be a boolean"
+					)))
+				}
+			},
+			// Options which can be specified multiple times
+			Some(long) if matches!(arg.get_action(), ArgAction::Append) => {
+				for v in values {
+					args.push(format!("--{long}={v}").into());
+				}
+			}
+			// Options which take a comma-separated list
+			Some(long) => args.push(format!("--{long}={}", values.join(",")).into()),
+			// Positional arguments are added after all other options
+			None => positional.extend(values),
+		}
+	}
+	if !positional.is_empty() {
+		if !args.iter().any(|a| a == "--") {
+			args.push("--".into());
+		}
+		args.extend(positional.into_iter().map(OsString::from));
+	}
+	Ok(args)
+}
+
+/// Read and parse a TOML configuration file
+fn read(file: &Path) -> Result<Document, Error> {
+	let content = std::fs::read_to_string(file)?;
+	content.parse::<Document>().map_err(|e| {
+		Error::Other(format!("Unable to parse the configuration file {}: {e}", file.display()))
+	})
+}
+
+/// The command-line values of an option in the configuration file
+fn values(key: &str, item: &Item) -> Result<Vec<String>, Error> {
+	match item {
+		Item::Value(Value::Array(v)) => v.iter().map(|v| value(key, v)).collect(),
+		Item::Value(v) => Ok(vec![value(key, v)?]),
+		_ => Err(Error::Other(format!(
+			"The option '{key}' in the configuration file must be a value or an array of values"
+		))),
+	}
+}
+
+fn value(key: &str, value: &Value) -> Result<String, Error> {
+	match value {
+		Value::String(v) => Ok(v.value().clone()),
+		Value::Integer(v) => Ok(v.value().to_string()),
+		Value::Float(v) => Ok(v.value().to_string()),
+		Value::Boolean(v) => Ok(v.value().to_string()),
+		Value::Datetime(v) => Ok(v.value().to_string()),
+		_ => Err(Error::Other(format!(
+			"The option '{key}' in the configuration file must be a value or an array of values"
+		))),
+	}
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+	use crate::cli::Cli;
+	use clap::CommandFactory;
+
+	fn args(config: &str, extra: &[&str]) -> Vec<String> {
+		let dir = tempfile::tempdir().unwrap();
+		let file = dir.path().join("surreal.toml");
+		std::fs::write(&file, config).unwrap();
+		let mut args: Vec<OsString> =
+			vec!["surreal".into(), "start".into(), "--config".into(), file.into()];
+		args.extend(extra.iter().map(OsString::from));
+		merge(Cli::command(), args)
+			.unwrap()
+			.into_iter()
+			.skip(4)
+			.map(|v| v.into_string().unwrap())
+			.collect()
+	}
+
+	#[test]
+	fn test_merge() {
+		let config = r#"
+			path = "memory"
+			bind = ["127.0.0.1:8000", "127.0.0.1:8001"]
+			no-banner = true
+			no_identification_headers = false
+			allow-net = ["127.0.0.1", "example.com"]
+		"#;
+		assert_eq!(
+			args(config, &[]),
+			vec![
+				"--bind=127.0.0.1:8000",
+				"--bind=127.0.0.1:8001",
+				"--no-banner",
+				"--allow-net=127.0.0.1,example.com",
+				"--",
+				"memory",
+			]
+		);
+	}
+
+	#[test]
+	fn test_merge_precedence() {
+		let config = r#"
+			bind = "127.0.0.1:8000"
+			shutdown-grace-period = "10s"
+		"#;
+		assert_eq!(
+			args(config, &["--bind", "0.0.0.0:8000"]),
+			vec!["--bind", "0.0.0.0:8000", "--shutdown-grace-period=10s"]
+		);
+	}
+
+	#[test]
+	fn test_merge_unknown_option() {
+		let dir = tempfile::tempdir().unwrap();
+		let file = dir.path().join("surreal.toml");
+		std::fs::write(&file, "unknown = true").unwrap();
+		let args = vec!["surreal".into(), "start".into(), "--config".into(), file.into()];
+		assert!(merge(Cli::command(), args).is_err());
+	}
+}
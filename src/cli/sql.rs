@@ -2,9 +2,10 @@ use crate::cli::abstraction::auth::{CredentialsBuilder, CredentialsLevel};
 use crate::cli::abstraction::{
 	AuthArguments, DatabaseConnectionArguments, LevelSelectionArguments,
 };
+use crate::cli::fmt::{self, FmtCommandArguments};
 use crate::cnf::PKG_VERSION;
 use crate::err::Error;
-use clap::Args;
+use clap::{Args, Subcommand};
 use futures::StreamExt;
 use rustyline::error::ReadlineError;
 use rustyline::validate::{ValidationContext, ValidationResult, Validator};
@@ -37,6 +38,14 @@ pub struct SqlCommandArguments {
 	/// Whether to show welcome message
 	#[arg(long, env = "SURREAL_HIDE_WELCOME")]
 	hide_welcome: bool,
+	#[command(subcommand)]
+	command: Option<SqlCommand>,
+}
+
+#[derive(Debug, Subcommand)]
+pub enum SqlCommand {
+	#[command(about = "Format SurrealQL query files")]
+	Fmt(FmtCommandArguments),
 }
 
 pub async fn init(
@@ -58,9 +67,14 @@ pub async fn init(
 		json,
 		multi,
 		hide_welcome,
+		command,
 		..
 	}: SqlCommandArguments,
 ) -> Result<(), Error> {
+	// Run the subcommand instead of the REPL, if one was specified
+	if let Some(SqlCommand::Fmt(args)) = command {
+		return fmt::init(args).await;
+	}
 	// Default datastore configuration for local engines
 	let config = Config::new().capabilities(Capabilities::all());
 	// If username and password are specified, and we are connecting to a remote SurrealDB server, then we need to authenticate.
@@ -127,6 +141,8 @@ pub async fn init(
 		let hints = [
 			(true, "Different statements within a query should be separated by a (;) semicolon."),
 			(!multi, "To create a multi-line query, end your lines with a (\\) backslash, and press enter."),
+			(true, "Queries after a BEGIN statement are only sent once the transaction is committed or cancelled."),
+			(true, "To change the namespace and database, type \\use <namespace> [<database>]."),
 			(true, "To exit, send a SIGTERM or press CTRL+C")
 		]
 		.iter()
@@ -152,10 +168,18 @@ pub async fn init(
 		);
 	}
 
+	// The queries of a transaction which has not been committed yet
+	let mut transaction = String::new();
+
 	// Loop over each command-line input
 	loop {
+		// Show when the queries are part of an open transaction
+		let current = match transaction.is_empty() {
+			true => prompt.clone(),
+			false => format!("{}(txn)> ", prompt.trim_end_matches("> ")),
+		};
 		// Prompt the user to input SQL and check the input.
-		let line = match rl.readline(&prompt) {
+		let line = match rl.readline(&current) {
 			// The user typed a query
 			Ok(line) => {
 				// Filter out all new lines
@@ -180,9 +204,44 @@ pub async fn init(
 		if line.trim().is_empty() {
 			continue;
 		}
+		// Process the meta-commands of the shell
+		if let Some(command) = line.trim().strip_prefix('\\') {
+			if !transaction.is_empty() {
+				eprintln!("Commit or cancel the current transaction before using {line}\n");
+				continue;
+			}
+			let mut args = command.trim_end_matches(';').split_whitespace();
+			match (args.next(), args.next(), args.next(), args.next()) {
+				(Some("use"), Some(namespace), None, None) => {
+					match client.use_ns(namespace).await {
+						Ok(_) => prompt = format!("{namespace}> "),
+						Err(e) => print(Err(e.into())),
+					}
+				}
+				(Some("use"), Some(namespace), Some(database), None) => {
+					match client.use_ns(namespace).use_db(database).await {
+						Ok(_) => prompt = format!("{namespace}/{database}> "),
+						Err(e) => print(Err(e.into())),
+					}
+				}
+				(Some("use"), ..) => eprintln!("Usage: \\use <namespace> [<database>]\n"),
+				_ => eprintln!("Unknown command: {line}\n"),
+			}
+			continue;
+		}
+		// Add the line to the open transaction, if there is one
+		let line = match transaction.is_empty() {
+			true => line,
+			false => format!("{transaction}\n{line}"),
+		};
 		// Complete the request
 		match sql::parse(&line) {
+			// Wait until the transaction is committed or cancelled
+			Ok(query) if opens_transaction(&query) => {
+				transaction = line;
+			}
 			Ok(mut query) => {
+				transaction.clear();
 				let mut namespace = None;
 				let mut database = None;
 				let mut vars = Vec::new();
@@ -253,6 +312,7 @@ pub async fn init(
 				}
 			}
 			Err(e) => {
+				transaction.clear();
 				eprintln!("{e}\n");
 			}
 		}
@@ -410,7 +470,9 @@ impl Validator for InputValidator {
 		// Trim all whitespace from the user input
 		let input = input.trim();
 		// Process the input to check if we can send the query
-		let result = if self.multi && !input.ends_with(';') {
+		let result = if input.starts_with('\\') {
+			Valid(None) // The line is a meta-command
+		} else if self.multi && !input.ends_with(';') {
 			Incomplete // The line doesn't end with a ; and we are in multi mode
 		} else if self.multi && input.is_empty() {
 			Incomplete // The line was empty and we are in multi mode
@@ -428,6 +490,15 @@ impl Validator for InputValidator {
 	}
 }
 
+/// Whether a query leaves a transaction open, to be committed or cancelled by a later query
+fn opens_transaction(query: &sql::Query) -> bool {
+	query.iter().fold(false, |open, statement| match statement {
+		Statement::Begin(_) => true,
+		Statement::Commit(_) | Statement::Cancel(_) => false,
+		_ => open,
+	})
+}
+
 fn filter_line_continuations(line: &str) -> String {
 	line.replace("\\\n", "").replace("\\\r\n", "")
 }
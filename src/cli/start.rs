@@ -22,15 +22,13 @@ pub struct StartCommandArguments {
 	#[arg(default_value = "memory")]
 	#[arg(value_parser = super::validator::path_valid)]
 	path: String,
+	#[arg(help = "Path to a TOML configuration file with the options for the server")]
+	#[arg(env = "SURREAL_CONFIG", long = "config")]
+	config: Option<PathBuf>,
 	#[arg(help = "Whether to hide the startup banner")]
 	#[arg(env = "SURREAL_NO_BANNER", long)]
 	#[arg(default_value_t = false)]
 	no_banner: bool,
-	#[arg(help = "Encryption key to use for on-disk encryption")]
-	#[arg(env = "SURREAL_KEY", short = 'k', long = "key")]
-	#[arg(value_parser = super::validator::key_valid)]
-	#[arg(hide = true)] // Not currently in use
-	key: Option<String>,
 	//
 	// Tasks
 	//
@@ -62,6 +60,24 @@ pub struct StartCommandArguments {
 	#[arg(env = "SURREAL_CHANGEFEED_GC_INTERVAL", long = "changefeed-gc-interval", value_parser = super::validator::duration)]
 	#[arg(default_value = "10s")]
 	changefeed_gc_interval: Duration,
+	#[arg(
+		help = "The interval at which to delete expired records from tables",
+		help_heading = "Database"
+	)]
+	#[arg(env = "SURREAL_RECORD_EXPIRY_INTERVAL", long = "record-expiry-interval", value_parser = super::validator::duration)]
+	#[arg(default_value = "10s")]
+	record_expiry_interval: Duration,
+	#[arg(
+		help = "The interval at which to check for and run scheduled events",
+		help_heading = "Database"
+	)]
+	#[arg(env = "SURREAL_EVENT_SCHEDULE_INTERVAL", long = "event-schedule-interval", value_parser = super::validator::duration)]
+	#[arg(default_value = "10s")]
+	event_schedule_interval: Duration,
+	#[arg(help = "The interval at which to deliver queued webhooks", help_heading = "Database")]
+	#[arg(env = "SURREAL_WEBHOOK_DELIVERY_INTERVAL", long = "webhook-delivery-interval", value_parser = super::validator::duration)]
+	#[arg(default_value = "1s")]
+	webhook_delivery_interval: Duration,
 	//
 	// Authentication
 	//
@@ -113,6 +129,10 @@ pub struct StartCommandArguments {
 	#[arg(env = "SURREAL_NO_IDENTIFICATION_HEADERS", long)]
 	#[arg(default_value_t = false)]
 	no_identification_headers: bool,
+	#[arg(help = "The period to wait for in-flight queries to finish when shutting down")]
+	#[arg(env = "SURREAL_SHUTDOWN_GRACE_PERIOD", long = "shutdown-grace-period", value_parser = super::validator::duration)]
+	#[arg(default_value = "30s")]
+	shutdown_grace_period: Duration,
 	//
 	// Database options
 	//
@@ -144,6 +164,9 @@ struct StartCommandWebTlsOptions {
 	#[arg(help = "Path to the private key file for encrypted client connections")]
 	#[arg(env = "SURREAL_WEB_KEY", long = "web-key", value_parser = super::validator::file_exists)]
 	web_key: Option<PathBuf>,
+	#[arg(help = "Path to the certificate authority file used to verify client certificates")]
+	#[arg(env = "SURREAL_WEB_CLIENT_CA", long = "web-client-ca", value_parser = super::validator::file_exists)]
+	web_client_ca: Option<PathBuf>,
 }
 
 pub async fn init(
@@ -159,8 +182,12 @@ pub async fn init(
 		node_membership_check_interval,
 		node_membership_cleanup_interval,
 		changefeed_gc_interval,
+		record_expiry_interval,
+		event_schedule_interval,
+		webhook_delivery_interval,
 		no_banner,
 		no_identification_headers,
+		shutdown_grace_period,
 		..
 	}: StartCommandArguments,
 ) -> Result<(), Error> {
@@ -175,18 +202,21 @@ pub async fn init(
 	} else {
 		endpoint.path
 	};
-	// Extract the certificate and key
-	let (crt, key) = if let Some(val) = web {
-		(val.web_crt, val.web_key)
+	// Extract the certificate, key, and client certificate authority
+	let (crt, key, client_ca) = if let Some(val) = web {
+		(val.web_crt, val.web_key, val.web_client_ca)
 	} else {
-		(None, None)
+		(None, None, None)
 	};
 	// Configure the engine
 	let engine = EngineOptions::default()
 		.with_node_membership_refresh_interval(node_membership_refresh_interval)
 		.with_node_membership_check_interval(node_membership_check_interval)
 		.with_node_membership_cleanup_interval(node_membership_cleanup_interval)
-		.with_changefeed_gc_interval(changefeed_gc_interval);
+		.with_changefeed_gc_interval(changefeed_gc_interval)
+		.with_record_expiry_interval(record_expiry_interval)
+		.with_event_schedule_interval(event_schedule_interval)
+		.with_webhook_delivery_interval(webhook_delivery_interval);
 	// Configure the config
 	let config = Config {
 		bind: listen_addresses.first().cloned().unwrap(),
@@ -195,9 +225,11 @@ pub async fn init(
 		user,
 		pass,
 		no_identification_headers,
+		shutdown_grace_period,
 		engine,
 		crt,
 		key,
+		client_ca,
 	};
 	// Setup the command-line options
 	let _ = CF.set(config);
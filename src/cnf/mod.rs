@@ -56,6 +56,22 @@ pub static HTTP_MAX_SIGNIN_BODY_SIZE: LazyLock<usize> =
 pub static HTTP_MAX_IMPORT_BODY_SIZE: LazyLock<usize> =
 	lazy_env_parse!("SURREAL_HTTP_MAX_IMPORT_BODY_SIZE", usize, 4 << 30);
 
+/// The number of requests per second allowed from each client IP address (defaults to 0, unlimited)
+pub static HTTP_RATE_LIMIT_PER_IP: LazyLock<f64> =
+	lazy_env_parse!("SURREAL_HTTP_RATE_LIMIT_PER_IP", f64, 0.0);
+
+/// The number of requests per second allowed with each authentication token (defaults to 0, unlimited)
+pub static HTTP_RATE_LIMIT_PER_TOKEN: LazyLock<f64> =
+	lazy_env_parse!("SURREAL_HTTP_RATE_LIMIT_PER_TOKEN", f64, 0.0);
+
+/// The number of requests per second allowed on each connection (defaults to 0, unlimited)
+pub static HTTP_RATE_LIMIT_PER_CONNECTION: LazyLock<f64> =
+	lazy_env_parse!("SURREAL_HTTP_RATE_LIMIT_PER_CONNECTION", f64, 0.0);
+
+/// How long the responses of requests with an idempotency key are kept, in seconds (defaults to 0, disabled)
+pub static HTTP_IDEMPOTENCY_WINDOW: LazyLock<u64> =
+	lazy_env_parse!("SURREAL_HTTP_IDEMPOTENCY_WINDOW", u64, 0);
+
 /// Specifies the frequency with which ping messages should be sent to the client
 pub const WEBSOCKET_PING_FREQUENCY: Duration = Duration::from_secs(5);
 
@@ -5,10 +5,16 @@ use std::fs;
 use std::path::PathBuf;
 use std::sync::Arc;
 use std::time::Duration;
+use surrealdb::dbs::audit::{AuditLog, Category};
 use surrealdb::dbs::capabilities::{
 	Capabilities, FuncTarget, MethodTarget, NetTarget, RouteTarget, Targets,
 };
+use surrealdb::dbs::memory::MemoryBudget;
+use surrealdb::dbs::quota::Quotas;
+use surrealdb::dbs::slowlog::SlowLog;
 use surrealdb::dbs::Session;
+use surrealdb::kvs::compression::Compression;
+use surrealdb::kvs::encryption::{Keyring, StorageKey};
 use surrealdb::kvs::Datastore;
 
 #[derive(Args, Debug)]
@@ -25,6 +31,10 @@ pub struct StartCommandDbsOptions {
 	#[arg(env = "SURREAL_TRANSACTION_TIMEOUT", long)]
 	#[arg(value_parser = super::cli::validator::duration)]
 	transaction_timeout: Option<Duration>,
+	#[arg(help = "Whether queries are prevented from modifying data")]
+	#[arg(env = "SURREAL_READ_ONLY", long = "read-only")]
+	#[arg(default_value_t = false)]
+	read_only: bool,
 	#[arg(help = "Whether to allow unauthenticated access", help_heading = "Authentication")]
 	#[arg(env = "SURREAL_UNAUTHENTICATED", long = "unauthenticated")]
 	#[arg(default_value_t = false)]
@@ -40,6 +50,67 @@ pub struct StartCommandDbsOptions {
 	#[arg(env = "SURREAL_IMPORT_FILE", long = "import-file")]
 	#[arg(value_parser = super::cli::validator::file_exists)]
 	import_file: Option<PathBuf>,
+	#[arg(help = "The key used to encrypt field values", help_heading = "Encryption")]
+	#[arg(env = "SURREAL_ENCRYPTION_KEY", long = "encryption-key")]
+	#[arg(value_parser = super::cli::validator::key_valid)]
+	encryption_key: Option<String>,
+	#[arg(
+		help = "Previous keys, used to re-encrypt the stored data keys, and the values encrypted with them, when the key is rotated",
+		help_heading = "Encryption"
+	)]
+	#[arg(env = "SURREAL_ENCRYPTION_KEY_PREVIOUS", long = "encryption-key-previous")]
+	#[arg(value_delimiter = ',', requires = "encryption_key")]
+	#[arg(value_parser = super::cli::validator::key_valid)]
+	encryption_key_previous: Vec<String>,
+	#[arg(
+		help = "Encryption key to use for on-disk encryption of stored values. Keys, including record ids and indexed values, are not encrypted. Existing data must first be encrypted with the rekey command",
+		help_heading = "Encryption"
+	)]
+	#[arg(env = "SURREAL_KEY", short = 'k', long = "key")]
+	#[arg(value_parser = super::cli::validator::key_valid)]
+	key: Option<String>,
+	#[arg(
+		help = "The algorithm used to compress stored values. This can only be enabled when the storage is created",
+		help_heading = "Storage"
+	)]
+	#[arg(env = "SURREAL_STORAGE_COMPRESSION", long = "storage-compression")]
+	#[arg(value_parser = ["snappy", "zstd"])]
+	storage_compression: Option<String>,
+	#[arg(help = "The minimum size in bytes of a compressed value", help_heading = "Storage")]
+	#[arg(env = "SURREAL_STORAGE_COMPRESSION_THRESHOLD", long = "storage-compression-threshold")]
+	#[arg(default_value_t = 1024)]
+	storage_compression_threshold: usize,
+	#[arg(help = "The duration after which statements are logged as slow queries")]
+	#[arg(env = "SURREAL_SLOW_LOG_THRESHOLD", long = "slow-log-threshold")]
+	#[arg(value_parser = super::cli::validator::duration)]
+	slow_log_threshold: Option<Duration>,
+	#[arg(help = "The proportion of slow queries which are logged, from 0.0 to 1.0")]
+	#[arg(env = "SURREAL_SLOW_LOG_SAMPLE", long = "slow-log-sample")]
+	#[arg(default_value_t = 1.0)]
+	slow_log_sample: f64,
+	#[arg(help = "The file to which audited events are appended", help_heading = "Audit")]
+	#[arg(env = "SURREAL_AUDIT_LOG", long = "audit-log")]
+	audit_log: Option<PathBuf>,
+	#[arg(help = "The categories of events which are audited", help_heading = "Audit")]
+	#[arg(env = "SURREAL_AUDIT_LOG_CATEGORIES", long = "audit-log-categories")]
+	#[arg(value_delimiter = ',', default_value = "auth,permission,schema")]
+	#[arg(value_parser = ["auth", "permission", "schema", "data"])]
+	audit_log_categories: Vec<String>,
+	#[arg(help = "The maximum number of concurrent queries for each record access method")]
+	#[arg(env = "SURREAL_QUOTA_CONCURRENT_QUERIES", long = "quota-concurrent-queries")]
+	quota_concurrent_queries: Option<usize>,
+	#[arg(help = "The maximum number of records returned by each statement for record users")]
+	#[arg(env = "SURREAL_QUOTA_RESULT_SIZE", long = "quota-result-size")]
+	quota_result_size: Option<usize>,
+	#[arg(help = "The maximum memory in bytes used by the intermediate results of each query")]
+	#[arg(env = "SURREAL_MEMORY_QUERY_BUDGET", long = "memory-query-budget")]
+	memory_query_budget: Option<usize>,
+	#[arg(help = "The maximum memory in bytes used by the intermediate results of all queries")]
+	#[arg(env = "SURREAL_MEMORY_GLOBAL_BUDGET", long = "memory-global-budget")]
+	memory_global_budget: Option<usize>,
+	#[arg(help = "Spill sorts which exceed a memory budget to the temporary directory")]
+	#[arg(env = "SURREAL_MEMORY_SPILL", long = "memory-spill")]
+	memory_spill: bool,
 }
 
 #[derive(Args, Debug)]
@@ -382,10 +453,25 @@ pub async fn init(
 		strict_mode,
 		query_timeout,
 		transaction_timeout,
+		read_only,
 		unauthenticated,
 		capabilities,
 		temporary_directory,
 		import_file,
+		encryption_key,
+		encryption_key_previous,
+		key,
+		storage_compression,
+		storage_compression_threshold,
+		slow_log_threshold,
+		slow_log_sample,
+		audit_log,
+		audit_log_categories,
+		quota_concurrent_queries,
+		quota_result_size,
+		memory_query_budget,
+		memory_global_budget,
+		memory_spill,
 	}: StartCommandDbsOptions,
 ) -> Result<Datastore, Error> {
 	// Get local copy of options
@@ -408,10 +494,84 @@ pub async fn init(
 	if capabilities.get_deny_all() {
 		warn!("You are denying all capabilities by default. Although this is recommended, beware that any new capabilities will also be denied.");
 	}
+	// Setup the compression of stored values
+	let compression = match storage_compression {
+		Some(v) => {
+			debug!("Storage compression is enabled with {v}");
+			Some(Compression::new(v.parse()?, storage_compression_threshold))
+		}
+		None => None,
+	};
+	// Setup the slow query log
+	let slow_log = match slow_log_threshold {
+		Some(v) => {
+			debug!("Logging queries which run for longer than {v:?}");
+			Some(SlowLog::new(v, slow_log_sample))
+		}
+		None => None,
+	};
+	// Setup the audit log
+	let audit_log = match audit_log {
+		Some(path) => {
+			debug!("Audited events are appended to {}", path.display());
+			let categories = audit_log_categories
+				.iter()
+				.map(|v| v.parse())
+				.collect::<Result<Vec<Category>, _>>()?;
+			let file = fs::OpenOptions::new().create(true).append(true).open(path)?;
+			Some(AuditLog::new(categories, file))
+		}
+		None => None,
+	};
+	// Setup the query quotas for record users
+	let quotas = match (quota_concurrent_queries, quota_result_size) {
+		(None, None) => None,
+		(concurrent, result_size) => {
+			debug!("Query quotas are enabled for record users");
+			Some(Quotas::new(concurrent, result_size))
+		}
+	};
+	// Store temporary files in the data directory of local storage engines
+	let temporary_directory = match temporary_directory {
+		Some(v) => Some(v),
+		None => match data_directory(&opt.path) {
+			Some(v) => {
+				let v = v.join("tmp");
+				fs::create_dir_all(&v)?;
+				debug!("Temporary files are stored in {}", v.display());
+				Some(v)
+			}
+			None => None,
+		},
+	};
+	// Setup the memory budgets of the intermediate results
+	let memory_budget = match (memory_query_budget, memory_global_budget) {
+		(None, None) => None,
+		(query, global) => {
+			debug!("Memory budgets are enabled for query results");
+			Some(MemoryBudget::new(query, global).with_spill(memory_spill))
+		}
+	};
 	// Convert the capabilities
 	let capabilities = capabilities.into();
 	// Log the specified server capabilities
 	debug!("Server capabilities: {capabilities}");
+	// Setup the keys used for encrypted fields
+	let encryption = match encryption_key {
+		Some(key) => {
+			debug!("Field encryption is enabled");
+			Some(Keyring::new(&key, &encryption_key_previous)?)
+		}
+		None => None,
+	};
+	// Setup the key used for on-disk encryption
+	let key = match key {
+		Some(key) => {
+			debug!("On-disk encryption of stored values is enabled");
+			Some(StorageKey::new(&key)?)
+		}
+		None => None,
+	};
 	// Parse and setup the desired kv datastore
 	let dbs = Datastore::new(&opt.path)
 		.await?
@@ -419,9 +579,17 @@ pub async fn init(
 		.with_strict_mode(strict_mode)
 		.with_query_timeout(query_timeout)
 		.with_transaction_timeout(transaction_timeout)
+		.with_read_only(read_only)
 		.with_auth_enabled(!unauthenticated)
 		.with_temporary_directory(temporary_directory)
-		.with_capabilities(capabilities);
+		.with_capabilities(capabilities)
+		.with_encryption(encryption)
+		.with_storage_encryption(key)
+		.with_compression(compression)
+		.with_slow_log(slow_log)
+		.with_audit_log(audit_log)
+		.with_quotas(quotas)
+		.with_memory_budget(memory_budget);
 	// Ensure the storage version is up-to-date to prevent corruption
 	dbs.check_version().await?;
 	// Import file at start, if provided
@@ -440,6 +608,15 @@ pub async fn init(
 	Ok(dbs)
 }
 
+/// The directory in which a local storage engine stores its data
+fn data_directory(path: &str) -> Option<PathBuf> {
+	["file://", "file:", "rocksdb://", "rocksdb:", "surrealkv+versioned://", "surrealkv://"]
+		.iter()
+		.find_map(|prefix| path.strip_prefix(prefix))
+		.filter(|v| !v.is_empty())
+		.map(PathBuf::from)
+}
+
 pub async fn fix(path: String) -> Result<(), Error> {
 	// Parse and setup the desired kv datastore
 	let dbs = Arc::new(Datastore::new(&path).await?);
@@ -453,6 +630,24 @@ pub async fn fix(path: String) -> Result<(), Error> {
 	Ok(())
 }
 
+pub async fn rekey(
+	path: String,
+	key: Option<String>,
+	new_key: Option<String>,
+) -> Result<(), Error> {
+	// Setup the current and new encryption keys
+	let key = key.map(|k| StorageKey::new(&k)).transpose()?;
+	let new_key = new_key.map(|k| StorageKey::new(&k)).transpose()?;
+	// Parse and setup the desired kv datastore
+	let dbs = Datastore::new(&path).await?.with_storage_encryption(key);
+	// Rewrite all values with the new key, continuing any interrupted rekey
+	let count = dbs.rekey(new_key).await?;
+	// Log success
+	println!("Database storage was re-encrypted successfully, with {count} values rewritten");
+	// All ok
+	Ok(())
+}
+
 #[cfg(test)]
 mod tests {
 	use std::str::FromStr;
@@ -465,6 +660,14 @@ mod tests {
 	use super::*;
 	use surrealdb::opt::auth::Root;
 
+	#[test]
+	fn test_data_directory() {
+		assert_eq!(data_directory("rocksdb://data/db"), Some(PathBuf::from("data/db")));
+		assert_eq!(data_directory("surrealkv+versioned://db"), Some(PathBuf::from("db")));
+		assert_eq!(data_directory("memory"), None);
+		assert_eq!(data_directory("tikv://127.0.0.1:2379"), None);
+	}
+
 	#[test(tokio::test)]
 	async fn test_setup_superuser() {
 		let ds = Datastore::new("memory").await.unwrap();
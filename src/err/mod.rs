@@ -3,12 +3,14 @@ use axum::response::{IntoResponse, Response};
 use axum::Error as AxumError;
 use axum::Json;
 use base64::DecodeError as Base64Error;
+use http::header::RETRY_AFTER;
 use http::{HeaderName, StatusCode};
 use opentelemetry::global::Error as OpentelemetryError;
 use reqwest::Error as ReqwestError;
 use serde::Serialize;
 use std::io::Error as IoError;
 use std::string::FromUtf8Error as Utf8Error;
+use std::time::Duration;
 use surrealdb::error::Db as SurrealDbError;
 use surrealdb::iam::Error as SurrealIamError;
 use surrealdb::Error as SurrealError;
@@ -76,6 +78,15 @@ pub enum Error {
 
 	#[error("The HTTP route '{0}' is forbidden")]
 	ForbiddenRoute(String),
+
+	#[error("Too many requests have been made, retry after {0:?}")]
+	TooManyRequests(Duration),
+
+	#[error("A request with the same idempotency key is still being processed")]
+	DuplicateRequest,
+
+	#[error("The idempotency key was already used for a request with a different body")]
+	IdempotencyKeyReused,
 }
 
 impl From<Error> for String {
@@ -191,8 +202,22 @@ pub(super) struct Message {
 
 impl IntoResponse for Error {
 	fn into_response(self) -> Response {
+		// Tell rate limited clients when to retry
+		if let Error::TooManyRequests(wait) = self {
+			return (
+				StatusCode::TOO_MANY_REQUESTS,
+				[(RETRY_AFTER, wait.as_secs_f64().ceil().max(1.0).to_string())],
+				Json(Message {
+					code: StatusCode::TOO_MANY_REQUESTS.as_u16(),
+					details: Some("Too many requests".to_string()),
+					description: Some("The rate limit for this client has been exceeded. Wait before retrying the request.".to_string()),
+					information: Some(self.to_string()),
+				}),
+			)
+				.into_response();
+		}
 		match self {
-			err @ Error::InvalidAuth | err @ Error::Db(SurrealError::Db(SurrealDbError::InvalidAuth)) => (
+			err @ Error::InvalidAuth | err @ Error::Db(SurrealError::Db(SurrealDbError::InvalidAuth)) | err @ Error::Db(SurrealError::Db(SurrealDbError::ExpiredPassword)) => (
 				StatusCode::UNAUTHORIZED,
 				Json(Message {
 					code: StatusCode::UNAUTHORIZED.as_u16(),
@@ -210,6 +235,33 @@ impl IntoResponse for Error {
 					information: Some(err.to_string()),
 				})
 			),
+			err @ Error::Db(SurrealError::Db(SurrealDbError::QuotaExceeded { .. })) => (
+				StatusCode::TOO_MANY_REQUESTS,
+				Json(Message {
+					code: StatusCode::TOO_MANY_REQUESTS.as_u16(),
+					details: Some("Quota exceeded".to_string()),
+					description: Some("A query quota, or a namespace limit, has been exceeded. Wait for running queries to finish before retrying the request.".to_string()),
+					information: Some(err.to_string()),
+				})
+			),
+			Error::DuplicateRequest => (
+				StatusCode::CONFLICT,
+				Json(Message {
+					code: StatusCode::CONFLICT.as_u16(),
+					details: Some("Duplicate request".to_string()),
+					description: Some("A request with the same idempotency key has not finished yet. Wait before retrying the request.".to_string()),
+					information: Some(self.to_string()),
+				}),
+			),
+			Error::IdempotencyKeyReused => (
+				StatusCode::UNPROCESSABLE_ENTITY,
+				Json(Message {
+					code: StatusCode::UNPROCESSABLE_ENTITY.as_u16(),
+					details: Some("Idempotency key reused".to_string()),
+					description: Some("A request with the same idempotency key had a different body. Use a new idempotency key for each request.".to_string()),
+					information: Some(self.to_string()),
+				}),
+			),
 			Error::InvalidType => (
 				StatusCode::UNSUPPORTED_MEDIA_TYPE,
 				Json(Message {
@@ -10,7 +10,7 @@ use http::{request::Parts, StatusCode};
 use hyper::{Request, Response};
 use surrealdb::{
 	dbs::Session,
-	iam::verify::{basic, token},
+	iam::verify::{basic, certificate, token},
 };
 use tower_http::auth::AsyncAuthorizeRequest;
 use uuid::Uuid;
@@ -23,6 +23,7 @@ use super::{
 		parse_typed_header, SurrealAuthDatabase, SurrealAuthNamespace, SurrealDatabase, SurrealId,
 		SurrealNamespace,
 	},
+	tls::ClientCertificate,
 	AppState,
 };
 
@@ -134,6 +135,20 @@ async fn check_auth(parts: &mut Parts) -> Result<Session, Error> {
 	session.ns = ns;
 	session.db = db;
 
+	// If a verified client certificate was supplied without other credentials
+	if !parts.headers.contains_key(http::header::AUTHORIZATION) {
+		if let Some(ClientCertificate(Some(name))) = parts.extensions.get::<ClientCertificate>() {
+			// A certificate which is not issued to a system user continues as anonymous
+			match certificate(kvs, &mut session, name, auth_ns.as_deref(), auth_db.as_deref()).await
+			{
+				Err(surrealdb::error::Db::InvalidAuth) => {
+					debug!("The client certificate for '{name}' is not for a system user, continuing as anonymous");
+				}
+				res => res?,
+			}
+		}
+	};
+
 	// If Basic authentication data was supplied
 	if let Ok(au) = parts.extract::<TypedHeader<Authorization<Basic>>>().await {
 		basic(
@@ -5,7 +5,8 @@ use axum::routing::get;
 use axum::Extension;
 use axum::Router;
 use surrealdb::dbs::capabilities::RouteTarget;
-use surrealdb::kvs::{LockType::*, TransactionType::*};
+use surrealdb::kvs::{Datastore, LockType::*, TransactionType::*};
+use uuid::Uuid;
 
 pub(super) fn router<S>() -> Router<S>
 where
@@ -22,6 +23,15 @@ async fn handler(Extension(state): Extension<AppState>) -> impl IntoResponse {
 		warn!("Capabilities denied HTTP route request attempt, target: '{}'", &RouteTarget::Health);
 		return Err(Error::ForbiddenRoute(RouteTarget::Health.to_string()));
 	}
+	// Check that the storage engine can be read
+	readable(db).await?;
+	// Check that the storage engine can be written
+	writable(db).await?;
+	// Return success for this endpoint
+	Ok(())
+}
+
+async fn readable(db: &Datastore) -> Result<(), Error> {
 	// Attempt to open a transaction
 	match db.transaction(Read, Optimistic).await {
 		// The transaction failed to start
@@ -48,3 +58,35 @@ async fn handler(Extension(state): Extension<AppState>) -> impl IntoResponse {
 		}
 	}
 }
+
+async fn writable(db: &Datastore) -> Result<(), Error> {
+	// Use a unique key, so that concurrent checks do not conflict
+	let key = [&[0x00][..], Uuid::new_v4().as_bytes()].concat();
+	// Attempt to open a transaction
+	match db.transaction(Write, Optimistic).await {
+		// The transaction failed to start
+		Err(_) => Err(Error::InvalidStorage),
+		// The transaction was successful
+		Ok(tx) => {
+			// Write and remove the key, leaving no data behind
+			trace!("Health endpoint committing transaction");
+			let res = match tx.set(key.clone(), Vec::<u8>::new(), None).await {
+				Ok(_) => tx.del(key).await,
+				Err(e) => Err(e),
+			};
+			match res {
+				Err(_) => {
+					// Ensure the transaction is cancelled
+					let _ = tx.cancel().await;
+					// Return an error for this endpoint
+					Err(Error::InvalidStorage)
+				}
+				// Attempt to commit the transaction
+				Ok(_) => match tx.commit().await {
+					Err(_) => Err(Error::InvalidStorage),
+					Ok(_) => Ok(()),
+				},
+			}
+		}
+	}
+}
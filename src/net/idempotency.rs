@@ -0,0 +1,363 @@
+use crate::cnf::HTTP_IDEMPOTENCY_WINDOW;
+use crate::err::Error;
+use crate::net::client_ip::ExtractClientIP;
+use axum::body::Body;
+use axum::extract::Request;
+use axum::middleware::Next;
+use axum::response::{IntoResponse, Response};
+use bytes::{Bytes, BytesMut};
+use futures_util::stream::{self, StreamExt};
+use http::header::{AUTHORIZATION, CONTENT_LENGTH};
+use http::{HeaderMap, HeaderValue, Method, StatusCode};
+use http_body_util::BodyExt;
+use std::collections::hash_map::DefaultHasher;
+use std::collections::{HashMap, VecDeque};
+use std::hash::{Hash, Hasher};
+use std::sync::{LazyLock, Mutex};
+use std::time::{Duration, Instant};
+use surrealdb::headers::{DB, IDEMPOTENCY_KEY, IDEMPOTENT_REPLAYED, NS};
+
+/// The number of requests tracked, after which new requests are not tracked
+const MAX_TRACKED_REQUESTS: usize = 10_000;
+
+/// The largest request or response body which is recorded for retried requests
+const MAX_RECORDED_BODY_SIZE: usize = 1 << 20;
+
+/// The total size of the recorded responses, after which responses are not recorded
+const MAX_RECORDED_SIZE: usize = 64 << 20;
+
+/// The requests which have been made with an idempotency key
+pub(crate) static REQUESTS: LazyLock<Option<Requests>> =
+	LazyLock::new(|| Requests::new(Duration::from_secs(*HTTP_IDEMPOTENCY_WINDOW)));
+
+/// The completed requests of each idempotency key, which are kept for a
+/// window of time, so that retried requests receive the original response
+pub(crate) struct Requests {
+	/// How long the response of each request is kept
+	window: Duration,
+	/// The state of each request
+	state: Mutex<State>,
+}
+
+#[derive(Default)]
+struct State {
+	/// The state of each request
+	entries: HashMap<u64, Entry>,
+	/// Each request, and when it was started, in the order they were started
+	order: VecDeque<(u64, Instant)>,
+	/// The total size of the recorded responses
+	recorded: usize,
+}
+
+impl State {
+	/// Forget the requests which were started before the window
+	fn expire(&mut self, now: Instant, window: Duration) {
+		while let Some(&(key, started)) = self.order.front() {
+			if now.duration_since(started) < window {
+				break;
+			}
+			self.order.pop_front();
+			// The request may have been forgotten, or started again
+			if self.entries.get(&key).is_some_and(|e| e.started == started) {
+				self.remove(key);
+			}
+		}
+	}
+
+	/// Forget a request, and the size of its recorded response
+	fn remove(&mut self, key: u64) {
+		if let Some(Some(res)) = self.entries.remove(&key).map(|e| e.response) {
+			self.recorded -= res.size();
+		}
+	}
+}
+
+/// How a request is processed when it starts
+enum Started {
+	/// The request is processed, and its response is recorded
+	Tracked,
+	/// The request is processed, but is not tracked, as too many requests are tracked
+	Untracked,
+	/// The request has already completed, with the recorded response
+	Completed(Recorded),
+}
+
+struct Entry {
+	/// When the request was started
+	started: Instant,
+	/// The hash of the request body
+	body: u64,
+	/// The response of the request, once it has completed
+	response: Option<Recorded>,
+}
+
+#[derive(Clone)]
+struct Recorded {
+	status: StatusCode,
+	headers: HeaderMap,
+	body: Bytes,
+}
+
+impl Recorded {
+	/// The approximate number of bytes used by the response
+	fn size(&self) -> usize {
+		self.body.len()
+			+ self.headers.iter().map(|(k, v)| k.as_str().len() + v.len()).sum::<usize>()
+	}
+}
+
+impl IntoResponse for Recorded {
+	fn into_response(self) -> Response {
+		let mut res = (self.status, self.headers, self.body).into_response();
+		res.headers_mut().insert(IDEMPOTENT_REPLAYED.clone(), HeaderValue::from_static("true"));
+		res
+	}
+}
+
+impl Requests {
+	/// Create the request tracker, returning None if the window is empty
+	pub(crate) fn new(window: Duration) -> Option<Self> {
+		if window.is_zero() {
+			return None;
+		}
+		Some(Self {
+			window,
+			state: Mutex::default(),
+		})
+	}
+
+	/// Start a request, returning the original response if the request
+	/// has already completed, or an error if it is still being processed,
+	/// or if the key was used for a request with a different body
+	fn start(&self, key: u64, body: u64) -> Result<Started, Error> {
+		let now = Instant::now();
+		let mut state = self.state.lock().unwrap_or_else(|e| e.into_inner());
+		// Forget the requests which have expired
+		state.expire(now, self.window);
+		match state.entries.get(&key) {
+			Some(e) if e.body != body => Err(Error::IdempotencyKeyReused),
+			Some(e) => match &e.response {
+				Some(res) => Ok(Started::Completed(res.clone())),
+				None => Err(Error::DuplicateRequest),
+			},
+			// New requests are not tracked once too many requests are tracked
+			None if state.entries.len() >= MAX_TRACKED_REQUESTS => Ok(Started::Untracked),
+			None => {
+				state.order.push_back((key, now));
+				state.entries.insert(
+					key,
+					Entry {
+						started: now,
+						body,
+						response: None,
+					},
+				);
+				Ok(Started::Tracked)
+			}
+		}
+	}
+
+	/// Record the response of a request, or forget the request so that it can be
+	/// retried. Responses are not recorded once too many responses are recorded.
+	fn finish(&self, key: u64, response: Option<Recorded>) {
+		let mut state = self.state.lock().unwrap_or_else(|e| e.into_inner());
+		match response {
+			Some(res) if state.recorded + res.size() <= MAX_RECORDED_SIZE => {
+				let size = res.size();
+				if let Some(e) = state.entries.get_mut(&key) {
+					e.response = Some(res);
+					state.recorded += size;
+				}
+			}
+			_ => state.remove(key),
+		}
+	}
+}
+
+/// A request body, which is read into memory unless it is too large to be recorded
+enum RequestBody {
+	/// The request body, which was read in full
+	Complete(Bytes),
+	/// The request body, which streams the data already read, followed by the rest
+	Partial(Body),
+}
+
+/// Read a request body, unless it is larger than the largest recorded body
+async fn read_body(body: Body) -> Result<RequestBody, Error> {
+	let mut data = BytesMut::new();
+	let mut body = body.into_data_stream();
+	while let Some(chunk) = body.next().await {
+		let chunk = chunk.map_err(|err| Error::Other(err.to_string()))?;
+		data.extend_from_slice(&chunk);
+		if data.len() > MAX_RECORDED_BODY_SIZE {
+			let head = stream::once(async move { Ok(data.freeze()) });
+			return Ok(RequestBody::Partial(Body::from_stream(head.chain(body))));
+		}
+	}
+	Ok(RequestBody::Complete(data.freeze()))
+}
+
+/// The key of a request, which is only shared by retries of the same
+/// request, from the same client, with the same idempotency key
+fn request_key(request: &Request, key: &HeaderValue) -> u64 {
+	// Avoid holding on to the credentials themselves
+	let mut hasher = DefaultHasher::new();
+	key.as_bytes().hash(&mut hasher);
+	request.method().hash(&mut hasher);
+	request.uri().path().hash(&mut hasher);
+	match request.headers().get(AUTHORIZATION) {
+		Some(auth) => auth.as_bytes().hash(&mut hasher),
+		// Anonymous requests are only shared by the same client
+		None => match request.extensions().get::<ExtractClientIP>() {
+			Some(ExtractClientIP(ip)) => ip.hash(&mut hasher),
+			None => None::<String>.hash(&mut hasher),
+		},
+	}
+	request.headers().get(&NS).map(|v| v.as_bytes()).hash(&mut hasher);
+	request.headers().get(&DB).map(|v| v.as_bytes()).hash(&mut hasher);
+	hasher.finish()
+}
+
+/// Returns the original response to write requests which are retried
+/// with the same idempotency key, instead of processing them again
+pub(super) async fn idempotency_middleware(
+	request: Request,
+	next: Next,
+) -> Result<Response, Error> {
+	let Some(requests) = REQUESTS.as_ref() else {
+		return Ok(next.run(request).await);
+	};
+	// Requests which only read data can already be retried
+	if matches!(*request.method(), Method::GET | Method::HEAD | Method::OPTIONS) {
+		return Ok(next.run(request).await);
+	}
+	let Some(key) = request.headers().get(&IDEMPOTENCY_KEY) else {
+		return Ok(next.run(request).await);
+	};
+	// Large requests are not tracked, so that they are not kept in memory
+	let size = request.headers().get(CONTENT_LENGTH).and_then(|v| v.to_str().ok());
+	if size.and_then(|v| v.parse::<usize>().ok()).is_some_and(|v| v > MAX_RECORDED_BODY_SIZE) {
+		return Ok(next.run(request).await);
+	}
+	let key = request_key(&request, key);
+	// Retries must have the same body as the original request
+	let (parts, body) = request.into_parts();
+	let body = match read_body(body).await? {
+		RequestBody::Complete(body) => body,
+		// Large requests without a length are not tracked either
+		RequestBody::Partial(body) => return Ok(next.run(Request::from_parts(parts, body)).await),
+	};
+	let mut hasher = DefaultHasher::new();
+	body.hash(&mut hasher);
+	let request = Request::from_parts(parts, Body::from(body));
+	match requests.start(key, hasher.finish())? {
+		Started::Tracked => {}
+		Started::Untracked => return Ok(next.run(request).await),
+		Started::Completed(res) => return Ok(res.into_response()),
+	}
+	let res = next.run(request).await;
+	// Server errors are not recorded, so that the request can be retried
+	if res.status().is_server_error() {
+		requests.finish(key, None);
+		return Ok(res);
+	}
+	let (parts, body) = res.into_parts();
+	let body = match body.collect().await {
+		Ok(body) => body.to_bytes(),
+		Err(err) => {
+			requests.finish(key, None);
+			return Err(Error::Other(err.to_string()));
+		}
+	};
+	// Large responses are not recorded, so that they are not kept in memory
+	if body.len() > MAX_RECORDED_BODY_SIZE {
+		requests.finish(key, None);
+	} else {
+		let recorded = Recorded {
+			status: parts.status,
+			headers: parts.headers.clone(),
+			body: body.clone(),
+		};
+		requests.finish(key, Some(recorded));
+	}
+	Ok(Response::from_parts(parts, Body::from(body)))
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+
+	#[test]
+	fn records_each_request() {
+		assert!(Requests::new(Duration::ZERO).is_none());
+		let requests = Requests::new(Duration::from_secs(60)).unwrap();
+		// The first request is processed
+		assert!(matches!(requests.start(1, 0), Ok(Started::Tracked)));
+		// A retry is rejected while the request is processed
+		assert!(matches!(requests.start(1, 0), Err(Error::DuplicateRequest)));
+		// A retry receives the original response once it has completed
+		let recorded = Recorded {
+			status: StatusCode::OK,
+			headers: HeaderMap::new(),
+			body: Bytes::from_static(b"[]"),
+		};
+		requests.finish(1, Some(recorded));
+		let Ok(Started::Completed(res)) = requests.start(1, 0) else {
+			panic!("Expected the recorded response");
+		};
+		assert_eq!(res.body, Bytes::from_static(b"[]"));
+		// A forgotten request can be processed again
+		assert!(matches!(requests.start(2, 0), Ok(Started::Tracked)));
+		requests.finish(2, None);
+		assert!(matches!(requests.start(2, 0), Ok(Started::Tracked)));
+		// A retry with a different body is rejected
+		assert!(matches!(requests.start(1, 1), Err(Error::IdempotencyKeyReused)));
+		assert!(matches!(requests.start(2, 1), Err(Error::IdempotencyKeyReused)));
+	}
+
+	#[test]
+	fn expires_requests() {
+		let requests = Requests::new(Duration::from_millis(1)).unwrap();
+		assert!(matches!(requests.start(1, 0), Ok(Started::Tracked)));
+		std::thread::sleep(Duration::from_millis(5));
+		assert!(matches!(requests.start(1, 0), Ok(Started::Tracked)));
+	}
+
+	#[test]
+	fn limits_tracked_requests() {
+		let requests = Requests::new(Duration::from_secs(60)).unwrap();
+		for key in 0..MAX_TRACKED_REQUESTS as u64 {
+			assert!(matches!(requests.start(key, 0), Ok(Started::Tracked)));
+		}
+		// New requests are not tracked once too many requests are tracked
+		let key = MAX_TRACKED_REQUESTS as u64;
+		assert!(matches!(requests.start(key, 0), Ok(Started::Untracked)));
+		// Responses are not recorded once too many responses are recorded
+		let recorded = Recorded {
+			status: StatusCode::OK,
+			headers: HeaderMap::new(),
+			body: Bytes::from(vec![0; MAX_RECORDED_BODY_SIZE]),
+		};
+		for key in 0..64 {
+			requests.finish(key, Some(recorded.clone()));
+		}
+		assert!(matches!(requests.start(0, 0), Ok(Started::Completed(_))));
+		// The request is forgotten instead, so that it can be retried
+		let key = MAX_TRACKED_REQUESTS as u64 - 1;
+		requests.finish(key, Some(recorded));
+		assert!(matches!(requests.start(key, 0), Ok(Started::Tracked)));
+	}
+
+	#[tokio::test]
+	async fn streams_large_request_bodies() {
+		let data = vec![0; MAX_RECORDED_BODY_SIZE + 1];
+		let chunks = data.chunks(1024).map(|c| Ok::<_, std::io::Error>(Bytes::copy_from_slice(c)));
+		let body = Body::from_stream(stream::iter(chunks.collect::<Vec<_>>()));
+		let RequestBody::Partial(body) = read_body(body).await.unwrap() else {
+			panic!("Expected the body to be streamed");
+		};
+		// The whole body is still received
+		let body = body.collect().await.unwrap().to_bytes();
+		assert_eq!(body.len(), data.len());
+	}
+}
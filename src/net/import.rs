@@ -4,17 +4,21 @@ use crate::cnf::HTTP_MAX_IMPORT_BODY_SIZE;
 use crate::err::Error;
 use crate::net::output;
 use axum::extract::DefaultBodyLimit;
+use axum::extract::Path;
 use axum::extract::Request;
 use axum::response::IntoResponse;
 use axum::routing::post;
 use axum::Extension;
 use axum::Router;
+use axum_extra::extract::Query;
 use axum_extra::TypedHeader;
 use futures::TryStreamExt;
+use serde::Deserialize;
 use surrealdb::dbs::capabilities::RouteTarget;
 use surrealdb::dbs::Session;
 use surrealdb::iam::Action::Edit;
 use surrealdb::iam::ResourceKind::Any;
+use surrealdb::kvs::csv;
 use tower_http::limit::RequestBodyLimitLayer;
 
 pub(super) fn router<S>() -> Router<S>
@@ -23,10 +27,48 @@ where
 {
 	Router::new()
 		.route("/import", post(handler))
+		.route("/import/csv/:table", post(csv_handler))
 		.route_layer(DefaultBodyLimit::disable())
 		.layer(RequestBodyLimitLayer::new(*HTTP_MAX_IMPORT_BODY_SIZE))
 }
 
+#[derive(Default, Deserialize, Debug, Clone)]
+struct CsvOptions {
+	pub delimiter: Option<String>,
+	pub header: Option<bool>,
+	pub columns: Option<String>,
+	#[serde(default, rename = "type")]
+	pub types: Vec<String>,
+	pub transform: Option<String>,
+}
+
+impl CsvOptions {
+	fn config(self, table: String) -> Result<csv::Config, Error> {
+		let mut cfg = csv::Config::new(table);
+		if let Some(delimiter) = self.delimiter {
+			// The delimiter must be a single ASCII character
+			cfg.delimiter = match delimiter.as_bytes() {
+				[b] if b.is_ascii() => *b,
+				_ => return Err(Error::Request),
+			};
+		}
+		if let Some(header) = self.header {
+			cfg.header = header;
+		}
+		if let Some(columns) = self.columns {
+			cfg.columns = Some(columns.split(',').map(|v| v.trim().to_owned()).collect());
+		}
+		for v in self.types.iter() {
+			let (column, kind) = csv::column_type(v)?;
+			cfg.types.insert(column, kind);
+		}
+		if let Some(transform) = self.transform {
+			cfg.transform = Some(surrealdb::syn::value(&transform)?);
+		}
+		Ok(cfg)
+	}
+}
+
 async fn handler(
 	Extension(state): Extension<AppState>,
 	Extension(session): Extension<Session>,
@@ -66,3 +108,47 @@ async fn handler(
 		Err(err) => Err(Error::from(err)),
 	}
 }
+
+async fn csv_handler(
+	Extension(state): Extension<AppState>,
+	Extension(session): Extension<Session>,
+	accept: Option<TypedHeader<Accept>>,
+	Path(table): Path<String>,
+	Query(options): Query<CsvOptions>,
+	request: Request,
+) -> Result<impl IntoResponse, impl IntoResponse> {
+	// Get the datastore reference
+	let db = &state.datastore;
+	// Check if capabilities allow querying the requested HTTP route
+	if !db.allows_http_route(&RouteTarget::Import) {
+		warn!("Capabilities denied HTTP route request attempt, target: '{}'", &RouteTarget::Import);
+		return Err(Error::ForbiddenRoute(RouteTarget::Import.to_string()));
+	}
+	// Check the permissions level
+	db.check(&session, Edit, Any.on_level(session.au.level().to_owned()))?;
+	// Parse the import options
+	let cfg = options.config(table)?;
+
+	let body_stream = request
+		.into_body()
+		.into_data_stream()
+		.map_err(|e| surrealdb_core::err::Error::QueryStream(e.to_string()));
+
+	// Insert the records into the table
+	match db.import_csv(&session, cfg, body_stream).await {
+		Ok(res) => match accept.as_deref() {
+			// Simple serialization
+			Some(Accept::ApplicationJson) => Ok(output::json(&output::simplify(res))),
+			Some(Accept::ApplicationCbor) => Ok(output::cbor(&output::simplify(res))),
+			Some(Accept::ApplicationPack) => Ok(output::pack(&output::simplify(res))),
+			// Return nothing
+			Some(Accept::ApplicationOctetStream) => Ok(output::none()),
+			// Internal serialization
+			Some(Accept::Surrealdb) => Ok(output::full(&res)),
+			// An incorrect content-type was requested
+			_ => Err(Error::InvalidType),
+		},
+		// There was an error when importing the records
+		Err(err) => Err(Error::from(err)),
+	}
+}
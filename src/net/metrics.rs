@@ -0,0 +1,172 @@
+use super::AppState;
+use crate::cli::CF;
+use crate::err::Error;
+use crate::rpc::RpcState;
+use axum::extract::State;
+use axum::response::IntoResponse;
+use axum::routing::get;
+use axum::{Extension, Router};
+use http::header::CONTENT_TYPE;
+use prometheus::{
+	Encoder, HistogramOpts, HistogramVec, IntCounter, IntGauge, Opts, Registry, TextEncoder,
+};
+use std::path::Path;
+use std::sync::{Arc, LazyLock};
+use surrealdb::dbs::capabilities::RouteTarget;
+use surrealdb::dbs::metrics::{self as events, Event};
+
+// Histogram buckets in seconds
+static HISTOGRAM_BUCKETS_SECS: &[f64] =
+	&[0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1.0, 2.5, 5.0, 10.0, 30.0];
+
+/// The metrics which are exposed on the /metrics endpoint
+struct Metrics {
+	registry: Registry,
+	statement_duration: HistogramVec,
+	transaction_commits: IntCounter,
+	transaction_conflicts: IntCounter,
+	cache_hits: IntCounter,
+	cache_misses: IntCounter,
+	live_queries: IntGauge,
+	connections: IntGauge,
+	storage_size: IntGauge,
+}
+
+static METRICS: LazyLock<Metrics> = LazyLock::new(|| {
+	let registry = Registry::new_custom(Some("surrealdb".to_owned()), None).unwrap();
+	let statement_duration = HistogramVec::new(
+		HistogramOpts::new("statement_duration_seconds", "The time taken to process statements")
+			.buckets(HISTOGRAM_BUCKETS_SECS.to_vec()),
+		&["statement", "status"],
+	)
+	.unwrap();
+	let transaction_commits =
+		IntCounter::new("transaction_commits_total", "The number of committed transactions")
+			.unwrap();
+	let transaction_conflicts = IntCounter::new(
+		"transaction_conflicts_total",
+		"The number of transactions which failed to commit due to a conflict",
+	)
+	.unwrap();
+	let cache_hits =
+		IntCounter::new("cache_hits_total", "The number of definitions found in the cache")
+			.unwrap();
+	let cache_misses =
+		IntCounter::new("cache_misses_total", "The number of definitions not found in the cache")
+			.unwrap();
+	let live_queries =
+		IntGauge::with_opts(Opts::new("live_queries", "The number of active live queries"))
+			.unwrap();
+	let connections =
+		IntGauge::with_opts(Opts::new("connections", "The number of active WebSocket connections"))
+			.unwrap();
+	let storage_size = IntGauge::with_opts(Opts::new(
+		"storage_size_bytes",
+		"The size on disk of the storage engine files",
+	))
+	.unwrap();
+	registry.register(Box::new(statement_duration.clone())).unwrap();
+	registry.register(Box::new(transaction_commits.clone())).unwrap();
+	registry.register(Box::new(transaction_conflicts.clone())).unwrap();
+	registry.register(Box::new(cache_hits.clone())).unwrap();
+	registry.register(Box::new(cache_misses.clone())).unwrap();
+	registry.register(Box::new(live_queries.clone())).unwrap();
+	registry.register(Box::new(connections.clone())).unwrap();
+	registry.register(Box::new(storage_size.clone())).unwrap();
+	Metrics {
+		registry,
+		statement_duration,
+		transaction_commits,
+		transaction_conflicts,
+		cache_hits,
+		cache_misses,
+		live_queries,
+		connections,
+		storage_size,
+	}
+});
+
+/// Records the events observed by the datastore
+pub(super) fn init() {
+	events::observe(|event| match event {
+		Event::Statement {
+			kind,
+			duration,
+			success,
+		} => {
+			let status = if *success {
+				"ok"
+			} else {
+				"error"
+			};
+			METRICS
+				.statement_duration
+				.with_label_values(&[*kind, status])
+				.observe(duration.as_secs_f64());
+		}
+		Event::Commit => METRICS.transaction_commits.inc(),
+		Event::Conflict => METRICS.transaction_conflicts.inc(),
+		Event::CacheHit => METRICS.cache_hits.inc(),
+		Event::CacheMiss => METRICS.cache_misses.inc(),
+		_ => (),
+	});
+}
+
+pub(super) fn router() -> Router<Arc<RpcState>> {
+	Router::new().route("/metrics", get(handler))
+}
+
+async fn handler(
+	Extension(state): Extension<AppState>,
+	State(rpc_state): State<Arc<RpcState>>,
+) -> Result<impl IntoResponse, Error> {
+	// Get the datastore reference
+	let db = &state.datastore;
+	// Check if capabilities allow querying the requested HTTP route
+	if !db.allows_http_route(&RouteTarget::Metrics) {
+		warn!(
+			"Capabilities denied HTTP route request attempt, target: '{}'",
+			&RouteTarget::Metrics
+		);
+		return Err(Error::ForbiddenRoute(RouteTarget::Metrics.to_string()));
+	}
+	// Update the current values of the gauges
+	METRICS.live_queries.set(rpc_state.live_queries.read().await.len() as i64);
+	METRICS.connections.set(rpc_state.web_sockets.read().await.len() as i64);
+	if let Some(path) = storage_path(&CF.get().unwrap().path) {
+		let size = tokio::task::spawn_blocking(move || dir_size(Path::new(&path)))
+			.await
+			.unwrap_or_default();
+		METRICS.storage_size.set(size as i64);
+	}
+	// Encode the metrics in the text exposition format
+	let encoder = TextEncoder::new();
+	let mut buffer = Vec::new();
+	encoder
+		.encode(&METRICS.registry.gather(), &mut buffer)
+		.map_err(|e| Error::Other(e.to_string()))?;
+	Ok(([(CONTENT_TYPE, encoder.format_type().to_owned())], buffer))
+}
+
+/// Returns the directory of a storage engine which stores data on disk
+fn storage_path(path: &str) -> Option<String> {
+	["rocksdb", "surrealkv", "surrealkv+versioned", "file"].iter().find_map(|scheme| {
+		let rest = path.strip_prefix(scheme)?.strip_prefix(':')?;
+		Some(rest.trim_start_matches("//").to_owned())
+	})
+}
+
+/// Calculates the total size of the files within a directory
+fn dir_size(path: &Path) -> u64 {
+	let Ok(entries) = std::fs::read_dir(path) else {
+		return 0;
+	};
+	entries
+		.filter_map(Result::ok)
+		.map(|entry| match entry.metadata() {
+			Ok(meta) if meta.is_dir() => dir_size(&entry.path()),
+			Ok(meta) => meta.len(),
+			Err(_) => 0,
+		})
+		.sum()
+}
@@ -5,18 +5,23 @@ mod export;
 mod gql;
 pub(crate) mod headers;
 mod health;
+mod idempotency;
 mod import;
 mod input;
 mod key;
+mod metrics;
 mod ml;
 pub(crate) mod output;
 mod params;
+pub(crate) mod ratelimit;
+mod refresh;
 mod rpc;
 mod signals;
 mod signin;
 mod signup;
 mod sql;
 mod sync;
+mod tls;
 mod tracer;
 mod version;
 
@@ -36,7 +41,7 @@ use std::io;
 use std::net::SocketAddr;
 use std::sync::Arc;
 use std::time::Duration;
-use surrealdb::headers::{AUTH_DB, AUTH_NS, DB, ID, NS};
+use surrealdb::headers::{AUTH_DB, AUTH_NS, DB, ID, IDEMPOTENCY_KEY, NS};
 use surrealdb::kvs::Datastore;
 use tokio_util::sync::CancellationToken;
 use tower::ServiceBuilder;
@@ -69,6 +74,9 @@ pub async fn init(ds: Arc<Datastore>, ct: CancellationToken) -> Result<(), Error
 	// Get local copy of options
 	let opt = CF.get().unwrap();
 
+	// Record datastore events for the metrics endpoint
+	metrics::init();
+
 	let app_state = AppState {
 		client_ip: opt.client_ip,
 		datastore: ds.clone(),
@@ -112,6 +120,7 @@ pub async fn init(ds: Arc<Datastore>, ct: CancellationToken) -> Result<(), Error
 		ID.clone(),
 		AUTH_NS.clone(),
 		AUTH_DB.clone(),
+		IDEMPOTENCY_KEY.clone(),
 	];
 
 	#[cfg(not(feature = "http-compression"))]
@@ -125,11 +134,13 @@ pub async fn init(ds: Arc<Datastore>, ct: CancellationToken) -> Result<(), Error
 		ID.clone(),
 		AUTH_NS.clone(),
 		AUTH_DB.clone(),
+		IDEMPOTENCY_KEY.clone(),
 	];
 
 	let service = service
 		.layer(AddExtensionLayer::new(app_state))
 		.layer(middleware::from_fn(client_ip::client_ip_middleware))
+		.layer(middleware::from_fn(ratelimit::ratelimit_middleware))
 		.layer(SetSensitiveRequestHeadersLayer::from_shared(Arc::clone(&headers)))
 		.layer(
 			TraceLayer::new_for_http()
@@ -141,6 +152,7 @@ pub async fn init(ds: Arc<Datastore>, ct: CancellationToken) -> Result<(), Error
 		.layer(HttpMetricsLayer)
 		.layer(SetSensitiveResponseHeadersLayer::from_shared(headers))
 		.layer(AsyncRequireAuthorizationLayer::new(auth::SurrealAuth))
+		.layer(middleware::from_fn(idempotency::idempotency_middleware))
 		.layer(headers::add_server_header(!opt.no_identification_headers))
 		.layer(headers::add_version_header(!opt.no_identification_headers))
 		.layer(
@@ -171,8 +183,10 @@ pub async fn init(ds: Arc<Datastore>, ct: CancellationToken) -> Result<(), Error
 		.merge(sync::router())
 		.merge(sql::router())
 		.merge(signin::router())
+		.merge(refresh::router())
 		.merge(signup::router())
 		.merge(key::router())
+		.merge(metrics::router())
 		.merge(ml::router());
 
 	let axum_app = if *GRAPHQL_ENABLE {
@@ -198,14 +212,29 @@ pub async fn init(ds: Arc<Datastore>, ct: CancellationToken) -> Result<(), Error
 	let rpc_state = Arc::new(RpcState::new());
 
 	// Setup the graceful shutdown handler
-	let shutdown_handler = graceful_shutdown(rpc_state.clone(), ct.clone(), handle.clone());
+	let shutdown_handler =
+		graceful_shutdown(ds.clone(), rpc_state.clone(), ct.clone(), handle.clone());
 
 	let axum_app = axum_app.with_state(rpc_state.clone());
 
 	// Spawn a task to handle notifications
 	tokio::spawn(async move { notifications(ds, rpc_state, ct.clone()).await });
+	// If a client certificate authority is specified, then setup TLS with client certificates
+	let res = if let (Some(cert), Some(key), Some(ca)) = (&opt.crt, &opt.key, &opt.client_ca) {
+		// Configure certificate, private key, and client certificate verification used by https
+		let tls = tls::config(cert, key, ca)?;
+		// Setup the Axum server with TLS and client certificates
+		let server = axum_server::bind(opt.bind).acceptor(tls::ClientCertAcceptor::new(tls));
+		// Log the server startup to the CLI
+		info!(target: LOG, "Started web server on {} with client certificates", &opt.bind);
+		// Start the server and listen for connections
+		server
+			.handle(handle)
+			.serve(axum_app.into_make_service_with_connect_info::<SocketAddr>())
+			.await
+	}
 	// If a certificate and key are specified, then setup TLS
-	let res = if let (Some(cert), Some(key)) = (&opt.crt, &opt.key) {
+	else if let (Some(cert), Some(key)) = (&opt.crt, &opt.key) {
 		// Configure certificate and private key used by https
 		let tls = RustlsConfig::from_pem_file(cert, key).await?;
 		// Setup the Axum server with TLS
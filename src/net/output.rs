@@ -1,20 +1,29 @@
+use axum::body::Body;
 use axum::response::{IntoResponse, Response};
+use futures::stream;
 use http::header::{HeaderValue, CONTENT_TYPE};
 use http::StatusCode;
 use serde::Serialize;
 use serde_json::Value as Json;
+use std::io;
+use std::marker::PhantomData;
+use surrealdb::dbs::Response as QueryResponse;
 use surrealdb::sql;
 
 use super::headers::Accept;
 
+/// The number of records which are encoded into each chunk of a streamed response
+const STREAM_CHUNK_SIZE: usize = 1_000;
+
 pub enum Output {
 	None,
 	Fail,
 	Text(String),
-	Json(Vec<u8>), // JSON
-	Cbor(Vec<u8>), // CBOR
-	Pack(Vec<u8>), // MessagePack
-	Full(Vec<u8>), // Full type serialization
+	Json(Vec<u8>),             // JSON
+	Cbor(Vec<u8>),             // CBOR
+	Pack(Vec<u8>),             // MessagePack
+	Full(Vec<u8>),             // Full type serialization
+	Stream(HeaderValue, Body), // Incrementally encoded body
 }
 
 pub fn none() -> Output {
@@ -66,6 +75,18 @@ where
 	}
 }
 
+/// Encode the query responses as JSON, one chunk at a time, as the client reads the body
+pub fn json_stream(res: Vec<QueryResponse>) -> Output {
+	let body = Body::from_stream(stream::iter(Encoder::<JsonFormat>::new(res)));
+	Output::Stream(HeaderValue::from(Accept::ApplicationJson), body)
+}
+
+/// Encode the query responses as CBOR, one chunk at a time, as the client reads the body
+pub fn cbor_stream(res: Vec<QueryResponse>) -> Output {
+	let body = Body::from_stream(stream::iter(Encoder::<CborFormat>::new(res)));
+	Output::Stream(HeaderValue::from(Accept::ApplicationCbor), body)
+}
+
 /// Convert and simplify the value into JSON
 pub fn simplify<T: Serialize + 'static>(v: T) -> Json {
 	sql::to_value(v).unwrap().into()
@@ -89,8 +110,227 @@ impl IntoResponse for Output {
 			Output::Full(v) => {
 				([(CONTENT_TYPE, HeaderValue::from(Accept::Surrealdb))], v).into_response()
 			}
+			Output::Stream(t, v) => ([(CONTENT_TYPE, t)], v).into_response(),
 			Output::None => StatusCode::OK.into_response(),
 			Output::Fail => StatusCode::INTERNAL_SERVER_ERROR.into_response(),
 		}
 	}
 }
+
+/// The structure of an incrementally encoded list of query responses. Each
+/// response is encoded as an object with its keys in the same order as the
+/// simplified output, and the records of array results are encoded in chunks.
+trait Format {
+	/// Encode the start of an array with the specified length
+	fn array(len: usize, out: &mut Vec<u8>);
+	/// Encode the end of an array
+	fn array_end(out: &mut Vec<u8>);
+	/// Encode the separator between the elements of an array
+	fn separator(out: &mut Vec<u8>);
+	/// Encode the start of a response, up to its result
+	fn response(out: &mut Vec<u8>);
+	/// Encode the end of a response, after its result
+	fn response_end(status: &str, time: &str, out: &mut Vec<u8>);
+	/// Encode a simplified value
+	fn value(val: &Json, out: &mut Vec<u8>) -> io::Result<()>;
+}
+
+struct JsonFormat;
+
+impl Format for JsonFormat {
+	fn array(_: usize, out: &mut Vec<u8>) {
+		out.push(b'[');
+	}
+
+	fn array_end(out: &mut Vec<u8>) {
+		out.push(b']');
+	}
+
+	fn separator(out: &mut Vec<u8>) {
+		out.push(b',');
+	}
+
+	fn response(out: &mut Vec<u8>) {
+		out.extend_from_slice(br#"{"result":"#);
+	}
+
+	fn response_end(status: &str, time: &str, out: &mut Vec<u8>) {
+		out.extend_from_slice(br#","status":"#);
+		out.extend_from_slice(Json::from(status).to_string().as_bytes());
+		out.extend_from_slice(br#","time":"#);
+		out.extend_from_slice(Json::from(time).to_string().as_bytes());
+		out.push(b'}');
+	}
+
+	fn value(val: &Json, out: &mut Vec<u8>) -> io::Result<()> {
+		Ok(serde_json::to_writer(out, val)?)
+	}
+}
+
+struct CborFormat;
+
+impl CborFormat {
+	/// Encode the header of a data item with the specified major type
+	fn header(major: u8, len: usize, out: &mut Vec<u8>) {
+		let major = major << 5;
+		match len {
+			0..=23 => out.push(major | len as u8),
+			24..=0xff => out.extend_from_slice(&[major | 24, len as u8]),
+			0x100..=0xffff => {
+				out.push(major | 25);
+				out.extend_from_slice(&(len as u16).to_be_bytes());
+			}
+			0x10000..=0xffff_ffff => {
+				out.push(major | 26);
+				out.extend_from_slice(&(len as u32).to_be_bytes());
+			}
+			_ => {
+				out.push(major | 27);
+				out.extend_from_slice(&(len as u64).to_be_bytes());
+			}
+		}
+	}
+
+	/// Encode a text string
+	fn text(val: &str, out: &mut Vec<u8>) {
+		Self::header(3, val.len(), out);
+		out.extend_from_slice(val.as_bytes());
+	}
+}
+
+impl Format for CborFormat {
+	fn array(len: usize, out: &mut Vec<u8>) {
+		Self::header(4, len, out);
+	}
+
+	fn array_end(_: &mut Vec<u8>) {}
+
+	fn separator(_: &mut Vec<u8>) {}
+
+	fn response(out: &mut Vec<u8>) {
+		Self::header(5, 3, out);
+		Self::text("result", out);
+	}
+
+	fn response_end(status: &str, time: &str, out: &mut Vec<u8>) {
+		Self::text("status", out);
+		Self::text(status, out);
+		Self::text("time", out);
+		Self::text(time, out);
+	}
+
+	fn value(val: &Json, out: &mut Vec<u8>) -> io::Result<()> {
+		ciborium::into_writer(val, out).map_err(|e| io::Error::new(io::ErrorKind::Other, e))
+	}
+}
+
+/// An iterator which encodes the next chunk of the query responses each
+/// time that it is polled, so that the responses are not encoded at once
+struct Encoder<F> {
+	/// The responses which have not been encoded yet
+	responses: std::vec::IntoIter<QueryResponse>,
+	/// The records of the array result which is being encoded
+	records: Option<(std::vec::IntoIter<sql::Value>, String)>,
+	/// Whether the start of the list of responses has been encoded
+	started: bool,
+	/// Whether the end of the list of responses has been encoded
+	finished: bool,
+	format: PhantomData<F>,
+}
+
+impl<F: Format> Encoder<F> {
+	fn new(res: Vec<QueryResponse>) -> Self {
+		Self {
+			responses: res.into_iter(),
+			records: None,
+			started: false,
+			finished: false,
+			format: PhantomData,
+		}
+	}
+
+	/// Encode the next chunk of records of the current array result
+	fn records(
+		records: &mut std::vec::IntoIter<sql::Value>,
+		first: bool,
+		out: &mut Vec<u8>,
+	) -> io::Result<()> {
+		for (i, v) in records.by_ref().take(STREAM_CHUNK_SIZE).enumerate() {
+			if !first || i > 0 {
+				F::separator(out);
+			}
+			F::value(&simplify(v), out)?;
+		}
+		Ok(())
+	}
+
+	fn chunk(&mut self, out: &mut Vec<u8>) -> io::Result<()> {
+		// Continue with the records of the current result
+		if let Some((records, time)) = &mut self.records {
+			Self::records(records, false, out)?;
+			if records.as_slice().is_empty() {
+				F::array_end(out);
+				F::response_end("OK", time, out);
+				self.records = None;
+			}
+			return Ok(());
+		}
+		// Otherwise continue with the next response
+		let first = !self.started;
+		if first {
+			self.started = true;
+			F::array(self.responses.len(), out);
+		}
+		let Some(res) = self.responses.next() else {
+			self.finished = true;
+			F::array_end(out);
+			return Ok(());
+		};
+		if !first {
+			F::separator(out);
+		}
+		let time = res.speed();
+		F::response(out);
+		match res.result {
+			Ok(sql::Value::Array(v)) => {
+				let mut records = v.0.into_iter();
+				F::array(records.len(), out);
+				Self::records(&mut records, true, out)?;
+				if records.as_slice().is_empty() {
+					F::array_end(out);
+					F::response_end("OK", &time, out);
+				} else {
+					self.records = Some((records, time));
+				}
+			}
+			Ok(v) => {
+				F::value(&simplify(v), out)?;
+				F::response_end("OK", &time, out);
+			}
+			Err(e) => {
+				F::value(&Json::from(e.to_string()), out)?;
+				F::response_end("ERR", &time, out);
+			}
+		}
+		Ok(())
+	}
+}
+
+impl<F: Format> Iterator for Encoder<F> {
+	type Item = io::Result<Vec<u8>>;
+
+	fn next(&mut self) -> Option<Self::Item> {
+		if self.finished {
+			return None;
+		}
+		let mut out = Vec::new();
+		match self.chunk(&mut out) {
+			Ok(()) => Some(Ok(out)),
+			Err(e) => {
+				// The body can not be completed after an error
+				self.finished = true;
+				Some(Err(e))
+			}
+		}
+	}
+}
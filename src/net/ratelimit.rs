@@ -0,0 +1,192 @@
+use crate::cnf::{
+	HTTP_RATE_LIMIT_PER_CONNECTION, HTTP_RATE_LIMIT_PER_IP, HTTP_RATE_LIMIT_PER_TOKEN,
+};
+use crate::err::Error;
+use crate::net::client_ip::ExtractClientIP;
+use axum::extract::{ConnectInfo, Request};
+use axum::middleware::Next;
+use axum::response::Response;
+use http::header::AUTHORIZATION;
+use std::collections::hash_map::{DefaultHasher, Entry};
+use std::collections::{HashMap, VecDeque};
+use std::hash::{Hash, Hasher};
+use std::net::SocketAddr;
+use std::sync::{LazyLock, Mutex};
+use std::time::{Duration, Instant};
+
+/// The number of clients tracked before the least recent clients are forgotten
+const MAX_TRACKED_CLIENTS: usize = 10_000;
+
+/// The rate limit applied to each client IP address
+pub(crate) static PER_IP: LazyLock<Option<RateLimiter>> =
+	LazyLock::new(|| RateLimiter::new(*HTTP_RATE_LIMIT_PER_IP));
+
+/// The rate limit applied to each authentication token
+pub(crate) static PER_TOKEN: LazyLock<Option<RateLimiter>> =
+	LazyLock::new(|| RateLimiter::new(*HTTP_RATE_LIMIT_PER_TOKEN));
+
+/// The rate limit applied to each connection
+pub(crate) static PER_CONNECTION: LazyLock<Option<RateLimiter>> =
+	LazyLock::new(|| RateLimiter::new(*HTTP_RATE_LIMIT_PER_CONNECTION));
+
+/// A token bucket rate limiter, which tracks each client separately
+pub(crate) struct RateLimiter {
+	/// The number of requests allowed per second
+	rate: f64,
+	/// The number of requests which can be made at once
+	burst: f64,
+	/// The remaining requests of each client
+	buckets: Mutex<Buckets>,
+}
+
+struct Bucket {
+	/// The number of requests which can currently be made
+	tokens: f64,
+	/// When the number of tokens was last updated
+	updated: Instant,
+}
+
+/// The buckets of each client, along with the order in which they were
+/// last seen, so that the least recent client can be forgotten at once
+#[derive(Default)]
+struct Buckets {
+	/// The bucket of each client
+	clients: HashMap<String, Bucket>,
+	/// Each client, and when it was seen, in the order that it was seen
+	order: VecDeque<(String, Instant)>,
+}
+
+impl Buckets {
+	/// Forget the client which has gone the longest without a request.
+	/// Clients which have made a request since they were queued are queued
+	/// again, so each request causes at most one client to be queued again.
+	fn evict(&mut self) {
+		while let Some((key, seen)) = self.order.pop_front() {
+			match self.clients.get(&key) {
+				Some(b) if b.updated > seen => {
+					let updated = b.updated;
+					self.order.push_back((key, updated));
+				}
+				_ => {
+					self.clients.remove(&key);
+					return;
+				}
+			}
+		}
+	}
+}
+
+impl RateLimiter {
+	/// Create a rate limiter, returning None if the rate is unlimited
+	pub(crate) fn new(rate: f64) -> Option<Self> {
+		if !rate.is_finite() || rate <= 0.0 {
+			return None;
+		}
+		Some(Self {
+			rate,
+			burst: rate.max(1.0),
+			buckets: Mutex::default(),
+		})
+	}
+
+	/// Record a request from a client, returning how long the
+	/// client must wait if it has made too many requests
+	pub(crate) fn check(&self, key: &str) -> Result<(), Duration> {
+		let now = Instant::now();
+		let mut buckets = self.buckets.lock().unwrap_or_else(|e| e.into_inner());
+		// Forget the least recent client, when tracking too many clients
+		if buckets.clients.len() >= MAX_TRACKED_CLIENTS && !buckets.clients.contains_key(key) {
+			buckets.evict();
+		}
+		let Buckets {
+			clients,
+			order,
+		} = &mut *buckets;
+		let bucket = match clients.entry(key.to_owned()) {
+			Entry::Occupied(e) => e.into_mut(),
+			Entry::Vacant(e) => {
+				order.push_back((key.to_owned(), now));
+				e.insert(Bucket {
+					tokens: self.burst,
+					updated: now,
+				})
+			}
+		};
+		let tokens = self.refill(bucket, now);
+		if tokens < 1.0 {
+			bucket.tokens = tokens;
+			bucket.updated = now;
+			return Err(Duration::from_secs_f64((1.0 - tokens) / self.rate));
+		}
+		bucket.tokens = tokens - 1.0;
+		bucket.updated = now;
+		Ok(())
+	}
+
+	/// The number of tokens in a bucket at the specified time
+	fn refill(&self, bucket: &Bucket, now: Instant) -> f64 {
+		let elapsed = now.duration_since(bucket.updated).as_secs_f64();
+		(bucket.tokens + elapsed * self.rate).min(self.burst)
+	}
+}
+
+/// Rejects requests from clients which exceed the configured rate limits
+pub(super) async fn ratelimit_middleware(request: Request, next: Next) -> Result<Response, Error> {
+	// Limit the requests from each IP address
+	if let Some(limiter) = PER_IP.as_ref() {
+		if let Some(ExtractClientIP(Some(ip))) = request.extensions().get::<ExtractClientIP>() {
+			limiter.check(ip).map_err(Error::TooManyRequests)?;
+		}
+	}
+	// Limit the requests made with each token
+	if let Some(limiter) = PER_TOKEN.as_ref() {
+		if let Some(auth) = request.headers().get(AUTHORIZATION) {
+			// Avoid holding on to the credentials themselves
+			let mut hasher = DefaultHasher::new();
+			auth.as_bytes().hash(&mut hasher);
+			limiter.check(&hasher.finish().to_string()).map_err(Error::TooManyRequests)?;
+		}
+	}
+	// Limit the requests made on each connection, which is identified by
+	// the address of the peer, rather than by any header sent by the client
+	if let Some(limiter) = PER_CONNECTION.as_ref() {
+		if let Some(ConnectInfo(addr)) = request.extensions().get::<ConnectInfo<SocketAddr>>() {
+			limiter.check(&addr.to_string()).map_err(Error::TooManyRequests)?;
+		}
+	}
+	Ok(next.run(request).await)
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+
+	#[test]
+	fn limits_each_client() {
+		assert!(RateLimiter::new(0.0).is_none());
+		let limiter = RateLimiter::new(2.0).unwrap();
+		assert!(limiter.check("one").is_ok());
+		assert!(limiter.check("one").is_ok());
+		let wait = limiter.check("one").unwrap_err();
+		assert!(wait > Duration::ZERO && wait <= Duration::from_millis(500));
+		assert!(limiter.check("two").is_ok());
+	}
+
+	#[test]
+	fn forgets_the_least_recent_clients() {
+		let limiter = RateLimiter::new(1.0).unwrap();
+		assert!(limiter.check("first").is_ok());
+		assert!(limiter.check("active").is_ok());
+		for i in 0..MAX_TRACKED_CLIENTS {
+			// The active client keeps making requests
+			if i == MAX_TRACKED_CLIENTS / 2 {
+				assert!(limiter.check("active").is_err());
+			}
+			assert!(limiter.check(&i.to_string()).is_ok());
+		}
+		let buckets = limiter.buckets.lock().unwrap();
+		assert_eq!(buckets.clients.len(), MAX_TRACKED_CLIENTS);
+		assert!(!buckets.clients.contains_key("first"));
+		assert!(buckets.clients.contains_key("active"));
+	}
+}
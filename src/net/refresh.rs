@@ -0,0 +1,73 @@
+use super::headers::Accept;
+use super::AppState;
+use crate::err::Error;
+use crate::net::output;
+use axum::response::IntoResponse;
+use axum::routing::options;
+use axum::Extension;
+use axum::Router;
+use axum_extra::TypedHeader;
+use serde::Serialize;
+use surrealdb::dbs::capabilities::RouteTarget;
+use surrealdb::dbs::Session;
+
+#[derive(Serialize)]
+struct Success {
+	code: u16,
+	details: String,
+	token: Option<String>,
+}
+
+impl Success {
+	fn new(token: String) -> Success {
+		Success {
+			token: Some(token),
+			code: 200,
+			details: String::from("Authentication refreshed"),
+		}
+	}
+}
+
+pub(super) fn router<S>() -> Router<S>
+where
+	S: Clone + Send + Sync + 'static,
+{
+	Router::new().route("/refresh", options(|| async {}).post(handler))
+}
+
+async fn handler(
+	Extension(state): Extension<AppState>,
+	Extension(mut session): Extension<Session>,
+	accept: Option<TypedHeader<Accept>>,
+) -> Result<impl IntoResponse, impl IntoResponse> {
+	// Get a database reference
+	let kvs = &state.datastore;
+	// Check if capabilities allow querying the requested HTTP route
+	if !kvs.allows_http_route(&RouteTarget::Refresh) {
+		warn!(
+			"Capabilities denied HTTP route request attempt, target: '{}'",
+			&RouteTarget::Refresh
+		);
+		return Err(Error::ForbiddenRoute(RouteTarget::Refresh.to_string()));
+	}
+	// Issue a new token for the authenticated user
+	match surrealdb::iam::signin::refresh(kvs, &mut session).await.map_err(Error::from) {
+		// Authentication was refreshed successfully
+		Ok(v) => match accept.as_deref() {
+			// Simple serialization
+			Some(Accept::ApplicationJson) => Ok(output::json(&Success::new(v))),
+			Some(Accept::ApplicationCbor) => Ok(output::cbor(&Success::new(v))),
+			Some(Accept::ApplicationPack) => Ok(output::pack(&Success::new(v))),
+			// Text serialization
+			Some(Accept::TextPlain) => Ok(output::text(v)),
+			// Internal serialization
+			Some(Accept::Surrealdb) => Ok(output::full(&Success::new(v))),
+			// Return nothing
+			None => Ok(output::none()),
+			// An incorrect content-type was requested
+			_ => Err(Error::InvalidType),
+		},
+		// There was an error with authentication
+		Err(err) => Err(err),
+	}
+}
@@ -1,18 +1,22 @@
+use crate::cli::CF;
 use crate::err::Error;
 use crate::rpc::{self, RpcState};
 use crate::telemetry;
 use axum_server::Handle;
 use std::sync::Arc;
+use surrealdb::kvs::Datastore;
 use tokio::task::JoinHandle;
 use tokio_util::sync::CancellationToken;
 
 /// Start a graceful shutdown:
 /// * Signal the Axum Handle when a shutdown signal is received.
 /// * Stop all WebSocket connections.
+/// * Cancel any queries still running after the grace period.
 /// * Flush all telemetry data.
 ///
 /// A second signal will force an immediate shutdown.
 pub fn graceful_shutdown(
+	datastore: Arc<Datastore>,
 	state: Arc<RpcState>,
 	canceller: CancellationToken,
 	http_handle: Handle,
@@ -32,16 +36,28 @@ pub fn graceful_shutdown(
 			let http_handle = http_handle.clone();
 			let canceller = canceller.clone();
 			let state = state.clone();
+			let datastore = datastore.clone();
+			// Get the shutdown grace period
+			let grace = CF.get().unwrap().shutdown_grace_period;
 			// Spawn a background task
 			tokio::spawn(async move {
 				// Stop accepting new HTTP connections
-				http_handle.graceful_shutdown(None);
-				// Wait for all connections to close
-				while http_handle.connection_count() > 0 {
-					tokio::time::sleep(tokio::time::Duration::from_millis(100)).await;
+				http_handle.graceful_shutdown(Some(grace));
+				// Wait for in-flight requests to finish
+				let drain = async {
+					// Wait for all connections to close
+					while http_handle.connection_count() > 0 {
+						tokio::time::sleep(tokio::time::Duration::from_millis(100)).await;
+					}
+					// Stop accepting new WebSocket connections
+					rpc::graceful_shutdown(state.clone()).await;
+				};
+				// Cancel any queries which outlast the grace period
+				if tokio::time::timeout(grace, drain).await.is_err() {
+					warn!(target: super::LOG, "Shutdown grace period elapsed. Cancelling the remaining queries.");
+					datastore.connections().kill_all();
+					rpc::graceful_shutdown(state).await;
 				}
-				// Stop accepting new WebSocket connections
-				rpc::graceful_shutdown(state).await;
 				// Cancel the cancellation token
 				canceller.cancel();
 				// Flush all telemetry data
@@ -58,6 +74,8 @@ pub fn graceful_shutdown(
 			_ = shutdown => (),
 			// Check if this has shutdown
 			_ = canceller.cancelled() => {
+				// Cancel all running queries
+				datastore.connections().kill_all();
 				// Close all HTTP connections immediately
 				http_handle.shutdown();
 				// Close all WebSocket connections immediately
@@ -77,6 +95,8 @@ pub fn graceful_shutdown(
 				} else {
 					error!(target: super::LOG, "Failed to listen to shutdown signal. Terminating immediately.");
 				}
+				// Cancel all running queries
+				datastore.connections().kill_all();
 				// Close all HTTP connections immediately
 				http_handle.shutdown();
 				// Close all WebSocket connections immediately
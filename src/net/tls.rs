@@ -0,0 +1,213 @@
+use axum_server::accept::Accept;
+use axum_server::tls_rustls::{RustlsAcceptor, RustlsConfig};
+use futures_util::future::BoxFuture;
+use rustls::server::WebPkiClientVerifier;
+use rustls::{RootCertStore, ServerConfig};
+use std::fs::File;
+use std::io::{self, BufReader};
+use std::path::Path;
+use std::sync::Arc;
+use tokio::io::{AsyncRead, AsyncWrite};
+use tokio_rustls::server::TlsStream;
+use tower_http::add_extension::AddExtension;
+
+/// The object identifier of the common name attribute (2.5.4.3)
+const OID_COMMON_NAME: &[u8] = &[0x55, 0x04, 0x03];
+/// The object identifier of the subject alternative name extension (2.5.29.17)
+const OID_SUBJECT_ALT_NAME: &[u8] = &[0x55, 0x1d, 0x11];
+
+/// The name of the verified client certificate of a connection, if one was presented
+#[derive(Clone, Debug, Default)]
+pub(super) struct ClientCertificate(pub(super) Option<String>);
+
+/// Configure TLS with client certificates which are verified against the
+/// specified certificate authority. Clients without a certificate are
+/// still accepted, and authenticate with other credentials.
+pub(super) fn config(crt: &Path, key: &Path, ca: &Path) -> io::Result<RustlsConfig> {
+	let invalid = |e| io::Error::new(io::ErrorKind::InvalidInput, e);
+	// Load the certificate authority used to verify client certificates
+	let mut roots = RootCertStore::empty();
+	for cert in rustls_pemfile::certs(&mut BufReader::new(File::open(ca)?)) {
+		roots.add(cert?).map_err(invalid)?;
+	}
+	let verifier = WebPkiClientVerifier::builder(Arc::new(roots))
+		.allow_unauthenticated()
+		.build()
+		.map_err(|e| io::Error::new(io::ErrorKind::InvalidInput, e))?;
+	// Load the certificate and private key used by https
+	let certs = rustls_pemfile::certs(&mut BufReader::new(File::open(crt)?))
+		.collect::<Result<Vec<_>, _>>()?;
+	let key = rustls_pemfile::private_key(&mut BufReader::new(File::open(key)?))?
+		.ok_or_else(|| io::Error::new(io::ErrorKind::InvalidInput, "no private key found"))?;
+	let mut config = ServerConfig::builder()
+		.with_client_cert_verifier(verifier)
+		.with_single_cert(certs, key)
+		.map_err(invalid)?;
+	config.alpn_protocols = vec![b"h2".to_vec(), b"http/1.1".to_vec()];
+	Ok(RustlsConfig::from_config(Arc::new(config)))
+}
+
+/// A TLS acceptor which adds the name of the verified client certificate
+/// to the requests of each connection
+#[derive(Clone)]
+pub(super) struct ClientCertAcceptor {
+	inner: RustlsAcceptor,
+}
+
+impl ClientCertAcceptor {
+	pub(super) fn new(config: RustlsConfig) -> Self {
+		Self {
+			inner: RustlsAcceptor::new(config),
+		}
+	}
+}
+
+impl<I, S> Accept<I, S> for ClientCertAcceptor
+where
+	I: AsyncRead + AsyncWrite + Unpin + Send + 'static,
+	S: Send + 'static,
+{
+	type Stream = TlsStream<I>;
+	type Service = AddExtension<S, ClientCertificate>;
+	type Future = BoxFuture<'static, io::Result<(Self::Stream, Self::Service)>>;
+
+	fn accept(&self, stream: I, service: S) -> Self::Future {
+		let acceptor = self.inner.clone();
+		Box::pin(async move {
+			let (stream, service) = acceptor.accept(stream, service).await?;
+			// The certificate has already been verified during the handshake
+			let name = stream
+				.get_ref()
+				.1
+				.peer_certificates()
+				.and_then(|certs| certs.first())
+				.and_then(|cert| certificate_name(cert));
+			Ok((stream, AddExtension::new(service, ClientCertificate(name))))
+		})
+	}
+}
+
+/// Read a DER encoded element, returning its tag, its contents, and the remaining input
+fn der(input: &[u8]) -> Option<(u8, &[u8], &[u8])> {
+	let (&tag, rest) = input.split_first()?;
+	let (&len, rest) = rest.split_first()?;
+	let (len, rest) = match len {
+		0x00..=0x7f => (len as usize, rest),
+		0x81..=0x84 => {
+			let n = (len & 0x7f) as usize;
+			if rest.len() < n {
+				return None;
+			}
+			let (bytes, rest) = rest.split_at(n);
+			(bytes.iter().fold(0, |acc, b| (acc << 8) | *b as usize), rest)
+		}
+		_ => return None,
+	};
+	if rest.len() < len {
+		return None;
+	}
+	let (val, rest) = rest.split_at(len);
+	Some((tag, val, rest))
+}
+
+/// The name of a certificate, which is the common name of the subject, or
+/// otherwise the first DNS name of the subject alternative name extension
+pub(super) fn certificate_name(cert: &[u8]) -> Option<String> {
+	let (_, cert, _) = der(cert)?;
+	let (_, tbs, _) = der(cert)?;
+	// Skip the optional version
+	let (tag, _, next) = der(tbs)?;
+	let mut rest = if tag == 0xa0 {
+		next
+	} else {
+		tbs
+	};
+	// Skip the serial number, signature, issuer, and validity
+	for _ in 0..4 {
+		rest = der(rest)?.2;
+	}
+	let (_, subject, rest) = der(rest)?;
+	if let Some(name) = common_name(subject) {
+		return Some(name);
+	}
+	// Skip the subject public key info
+	let mut rest = der(rest)?.2;
+	while let Some((tag, val, next)) = der(rest) {
+		if tag == 0xa3 {
+			return dns_name(val);
+		}
+		rest = next;
+	}
+	None
+}
+
+/// The common name attribute of a distinguished name
+fn common_name(mut name: &[u8]) -> Option<String> {
+	while let Some((_, mut set, next)) = der(name) {
+		while let Some((_, attr, rest)) = der(set) {
+			let (_, oid, val) = der(attr)?;
+			if oid == OID_COMMON_NAME {
+				let (_, val, _) = der(val)?;
+				return String::from_utf8(val.to_vec()).ok();
+			}
+			set = rest;
+		}
+		name = next;
+	}
+	None
+}
+
+/// The first DNS name of the subject alternative name extension
+fn dns_name(exts: &[u8]) -> Option<String> {
+	let (_, mut exts, _) = der(exts)?;
+	while let Some((_, ext, next)) = der(exts) {
+		let (_, oid, mut rest) = der(ext)?;
+		if oid == OID_SUBJECT_ALT_NAME {
+			// Skip the optional critical flag
+			if let Some((0x01, _, next)) = der(rest) {
+				rest = next;
+			}
+			let (_, val, _) = der(rest)?;
+			let (_, mut names, _) = der(val)?;
+			while let Some((tag, name, next)) = der(names) {
+				if tag == 0x82 {
+					return String::from_utf8(name.to_vec()).ok();
+				}
+				names = next;
+			}
+			return None;
+		}
+		exts = next;
+	}
+	None
+}
+
+#[cfg(test)]
+mod tests {
+	use super::*;
+	use rcgen::{Certificate, CertificateParams, DistinguishedName, DnType};
+
+	#[test]
+	fn certificate_common_name() {
+		let mut params = CertificateParams::new(vec!["service.example.com".to_string()]);
+		params.distinguished_name = DistinguishedName::new();
+		params.distinguished_name.push(DnType::OrganizationName, "SurrealDB");
+		params.distinguished_name.push(DnType::CommonName, "service");
+		let cert = Certificate::from_params(params).unwrap().serialize_der().unwrap();
+		assert_eq!(certificate_name(&cert), Some("service".to_string()));
+	}
+
+	#[test]
+	fn certificate_dns_name() {
+		let mut params = CertificateParams::new(vec!["service.example.com".to_string()]);
+		params.distinguished_name = DistinguishedName::new();
+		let cert = Certificate::from_params(params).unwrap().serialize_der().unwrap();
+		assert_eq!(certificate_name(&cert), Some("service.example.com".to_string()));
+	}
+
+	#[test]
+	fn certificate_invalid() {
+		assert_eq!(certificate_name(&[]), None);
+		assert_eq!(certificate_name(&[0x30, 0x05, 0x01]), None);
+	}
+}
@@ -4,8 +4,13 @@ use crate::err::Error;
 use axum::response::IntoResponse;
 use axum::routing::get;
 use axum::{Extension, Router};
+use axum_extra::TypedHeader;
+use serde_json::json;
 use surrealdb::dbs::capabilities::RouteTarget;
+use surrealdb::env::{arch, os};
 
+use super::headers::Accept;
+use super::output;
 use super::AppState;
 
 pub(super) fn router<S>() -> Router<S>
@@ -17,6 +22,7 @@ where
 
 async fn handler(
 	Extension(state): Extension<AppState>,
+	accept: Option<TypedHeader<Accept>>,
 ) -> Result<impl IntoResponse, impl IntoResponse> {
 	// Get the datastore reference
 	let db = &state.datastore;
@@ -29,5 +35,22 @@ async fn handler(
 		return Err(Error::ForbiddenRoute(RouteTarget::Version.to_string()));
 	}
 
-	Ok(format!("{PKG_NAME}-{}", *PKG_VERSION))
+	match accept.as_deref() {
+		// Return the build information as an object
+		Some(Accept::ApplicationJson) => {
+			let (version, build) = match PKG_VERSION.split_once('+') {
+				Some((version, build)) => (version, Some(build)),
+				None => (PKG_VERSION.as_str(), None),
+			};
+			Ok(output::json(&json!({
+				"name": PKG_NAME,
+				"version": version,
+				"build": build,
+				"os": os(),
+				"arch": arch(),
+			})))
+		}
+		// Return the version as plain text
+		_ => Ok(output::text(format!("{PKG_NAME}-{}", *PKG_VERSION))),
+	}
 }
@@ -1,6 +1,7 @@
 use crate::cnf::{
 	PKG_NAME, PKG_VERSION, WEBSOCKET_MAX_CONCURRENT_REQUESTS, WEBSOCKET_PING_FREQUENCY,
 };
+use crate::net::ratelimit::PER_CONNECTION;
 use crate::rpc::failure::Failure;
 use crate::rpc::format::WsFormat;
 use crate::rpc::response::{failure, IntoRpcResponse};
@@ -8,18 +9,22 @@ use crate::rpc::CONN_CLOSED_ERR;
 use crate::telemetry;
 use crate::telemetry::metrics::ws::RequestContext;
 use crate::telemetry::traces::rpc::span_for_request;
-use axum::extract::ws::{close_code::AGAIN, CloseFrame, Message, WebSocket};
+use axum::extract::ws::{
+	close_code::{AGAIN, AWAY},
+	CloseFrame, Message, WebSocket,
+};
 use futures_util::stream::{SplitSink, SplitStream};
 use futures_util::{SinkExt, StreamExt};
 use opentelemetry::trace::FutureExt;
 use opentelemetry::Context as TelemetryContext;
-use std::collections::BTreeMap;
-use std::sync::Arc;
+use std::collections::{BTreeMap, HashMap};
+use std::sync::{Arc, Mutex};
 use surrealdb::channel::{self, Receiver, Sender};
 use surrealdb::dbs::Session;
 #[cfg(surrealdb_unstable)]
 use surrealdb::gql::{Pessimistic, SchemaCache};
 use surrealdb::kvs::Datastore;
+use surrealdb::rpc::cursor::Cursors;
 use surrealdb::rpc::format::Format;
 use surrealdb::rpc::method::Method;
 use surrealdb::rpc::Data;
@@ -37,9 +42,15 @@ use uuid::Uuid;
 /// An error string sent when the server is out of memory
 const SERVER_OVERLOADED: &str = "The server is unable to handle the request";
 
+/// An error string sent when the connection exceeds its rate limit
+const TOO_MANY_REQUESTS: &str = "Too many requests have been made on this connection";
+
 /// An error string sent when the server is gracefully shutting down
 const SERVER_SHUTTING_DOWN: &str = "The server is gracefully shutting down";
 
+/// An error string sent when a request is cancelled by the client
+const REQUEST_CANCELLED: &str = "The request was cancelled";
+
 use super::RpcState;
 
 pub struct Connection {
@@ -55,10 +66,14 @@ pub struct Connection {
 	pub(crate) datastore: Arc<Datastore>,
 	/// The persistent parameters for this WebSocket connection
 	pub(crate) vars: BTreeMap<String, Value>,
+	/// The query cursors which are open on this WebSocket connection
+	pub(crate) cursors: Cursors,
 	/// A cancellation token called when shutting down the server
 	pub(crate) shutdown: CancellationToken,
 	/// A cancellation token for cancelling all spawned tasks
 	pub(crate) canceller: CancellationToken,
+	/// The cancellation tokens of the in-flight requests, keyed by request id
+	pub(crate) requests: Arc<Mutex<HashMap<String, CancellationToken>>>,
 	/// A semaphore for limiting the number of concurrent calls
 	pub(crate) semaphore: Arc<Semaphore>,
 	/// The channels used to send and receive WebSocket messages
@@ -86,8 +101,10 @@ impl Connection {
 			format,
 			session,
 			vars: BTreeMap::new(),
+			cursors: Cursors::default(),
 			shutdown: CancellationToken::new(),
 			canceller: CancellationToken::new(),
+			requests: Arc::default(),
 			semaphore: Arc::new(Semaphore::new(*WEBSOCKET_MAX_CONCURRENT_REQUESTS)),
 			channel: channel::bounded(*WEBSOCKET_MAX_CONCURRENT_REQUESTS),
 			#[cfg(surrealdb_unstable)]
@@ -104,6 +121,10 @@ impl Connection {
 		let id = rpc_lock.id;
 		// Get the WebSocket state
 		let state = rpc_lock.state.clone();
+		// Get the datastore and session details
+		let datastore = rpc_lock.datastore.clone();
+		let ip = rpc_lock.session.ip.clone();
+		let canceller = rpc_lock.canceller.clone();
 		// Log the succesful WebSocket connection
 		trace!("WebSocket {} connected", id);
 		// Split the socket into sending and receiving streams
@@ -115,6 +136,8 @@ impl Connection {
 		std::mem::drop(rpc_lock);
 		// Add this WebSocket to the list
 		state.web_sockets.write().await.insert(id, rpc.clone());
+		// Register this WebSocket so that it can be killed
+		datastore.connections().register(&id.to_string(), "ws", ip, move || canceller.cancel());
 		// Start telemetry metrics for this connection
 		if let Err(err) = telemetry::metrics::ws::on_connect() {
 			error!("Error running metrics::ws::on_connect hook: {err}");
@@ -138,6 +161,8 @@ impl Connection {
 		rpc.read().await.cleanup_lqs().await;
 		// Remove this WebSocket from the list
 		state.web_sockets.write().await.remove(&id);
+		// Unregister this WebSocket from the datastore
+		datastore.connections().unregister(&id.to_string());
 		// Stop telemetry metrics for this connection
 		if let Err(err) = telemetry::metrics::ws::on_disconnect() {
 			error!("Error running metrics::ws::on_disconnect hook: {err}");
@@ -181,15 +206,29 @@ impl Connection {
 	) {
 		// Pin the internal receiving channel
 		let mut internal_receiver = Box::pin(internal_receiver);
-		// Clone the WebSocket cancellation token
-		let canceller = rpc.read().await.canceller.clone();
+		// Clone the WebSocket cancellation and shutdown tokens
+		let (canceller, shutdown) = {
+			let rpc = rpc.read().await;
+			(rpc.canceller.clone(), rpc.shutdown.clone())
+		};
 		// Loop, and listen for messages to write
 		loop {
 			tokio::select! {
 				//
 				biased;
 				// Check if we should teardown
-				_ = canceller.cancelled() => break,
+				_ = canceller.cancelled() => {
+					// Send any queued close message when shutting down
+					if shutdown.is_cancelled() {
+						while let Ok(msg) = internal_receiver.try_recv() {
+							if sender.send(msg).await.is_err() {
+								break;
+							}
+						}
+					}
+					// Exit out of the loop
+					break;
+				},
 				// Wait for the next message to send
 				Some(res) = internal_receiver.next() => {
 					// Send the message to the client
@@ -233,7 +272,18 @@ impl Connection {
 				//
 				biased;
 				// Check if we are shutting down
-				_ = shutdown.cancelled(), if tasks.is_empty() => break,
+				_ = shutdown.cancelled(), if tasks.is_empty() => {
+					// Notify the client that the connection is closing
+					let frame = CloseFrame {
+						code: AWAY,
+						reason: SERVER_SHUTTING_DOWN.into(),
+					};
+					if let Err(err) = internal_sender.send(Message::Close(Some(frame))).await {
+						trace!("WebSocket error when sending close message: {err:?}");
+					};
+					// Exit out of the loop
+					break;
+				},
 				// Check if we should teardown
 				_ = canceller.cancelled() => break,
 				// Remove any completed tasks
@@ -331,7 +381,7 @@ impl Connection {
 	/// Handle an individual WebSocket message
 	async fn handle_message(rpc: Arc<RwLock<Connection>>, msg: Message, chn: Sender<Message>) {
 		// Get all required values
-		let (id, fmt, shutdown, canceller, semaphore) = {
+		let (id, fmt, shutdown, canceller, requests, semaphore) = {
 			// Read the connection state
 			let rpc = rpc.read().await;
 			// Fetch the connection id
@@ -342,10 +392,12 @@ impl Connection {
 			let shutdown = rpc.shutdown.clone();
 			// Clone the WebSocket cancellation token
 			let canceller = rpc.canceller.clone();
+			// Clone the in-flight request cancellation tokens
+			let requests = rpc.requests.clone();
 			// Clone the request limiter
 			let semaphore = rpc.semaphore.clone();
 			// Return the required values
-			(id, format, shutdown, canceller, semaphore)
+			(id, format, shutdown, canceller, requests, semaphore)
 		};
 		// Calculate the message lenght and format
 		let (len, fmt) = match msg {
@@ -387,12 +439,40 @@ impl Connection {
 					));
 					// Parse the request RPC method type
 					let method = Method::parse(&req.method);
+					// Allow the request to be cancelled by its id
+					let cancel = canceller.child_token();
+					let rid = match (&req.id, method) {
+						(_, Method::Ping | Method::Cancel) => None,
+						(Some(rid), _) if rid.is_strand() || rid.is_number() => {
+							let rid = rid.clone().as_string();
+							let mut requests = requests.lock().unwrap_or_else(|e| e.into_inner());
+							// Requests which reuse the id of an in-flight request can't be cancelled
+							match requests.contains_key(&rid) {
+								true => None,
+								false => {
+									requests.insert(rid.clone(), cancel.clone());
+									Some(rid)
+								}
+							}
+						}
+						_ => None,
+					};
+					let (cancel_id, cancel_cx, cancel_chn) =
+						(req.id.clone(), otel_cx.clone(), chn.clone());
 					// Process the message
 					tokio::select! {
 						//
 						biased;
 						// Check if we should teardown
 						_ = canceller.cancelled() => (),
+						// Check if the request was cancelled
+						_ = cancel.cancelled() => {
+							// Process the response
+							failure(cancel_id, Failure::custom(REQUEST_CANCELLED))
+								.send(cancel_cx.clone(), fmt, &cancel_chn)
+								.with_context(cancel_cx.as_ref().clone())
+								.await;
+						},
 						// Wait for the message to be processed
 						_ = async move {
 							// Ping messages should be responded to immediately
@@ -415,6 +495,14 @@ impl Connection {
 										.with_context(otel_cx.as_ref().clone())
 										.await;
 								}
+								// Check to see whether the connection has exceeded its rate limit
+								else if PER_CONNECTION.as_ref().is_some_and(|v| v.check(&id.to_string()).is_err()) {
+									// Process the response
+									failure(req.id, Failure::custom(TOO_MANY_REQUESTS))
+										.send(otel_cx.clone(), fmt, &chn)
+										.with_context(otel_cx.as_ref().clone())
+										.await;
+								}
 								// Check to see whether we have available memory
 								else if sys::is_beyond_threshold().await {
 									// Process the response
@@ -448,6 +536,10 @@ impl Connection {
 							}
 						} => (),
 					}
+					// The request has finished, so can no longer be cancelled
+					if let Some(rid) = rid {
+						requests.lock().unwrap_or_else(|e| e.into_inner()).remove(&rid);
+					}
 				}
 				Err(err) => {
 					// Process the response
@@ -562,6 +654,35 @@ impl RpcContext for Connection {
 		}
 	}
 
+	// ------------------------------
+	// Cursors
+	// ------------------------------
+
+	/// Query cursors are enabled on WebSockets
+	const CURSOR_SUPPORT: bool = true;
+
+	fn cursors(&self) -> &Cursors {
+		&self.cursors
+	}
+
+	// ------------------------------
+	// Cancellation
+	// ------------------------------
+
+	/// Request cancellation is enabled on WebSockets
+	const CANCEL_SUPPORT: bool = true;
+
+	fn cancel_request(&self, id: &Value) -> bool {
+		let id = id.clone().as_string();
+		match self.requests.lock().unwrap_or_else(|e| e.into_inner()).remove(&id) {
+			Some(token) => {
+				token.cancel();
+				true
+			}
+			None => false,
+		}
+	}
+
 	// ------------------------------
 	// GraphQL
 	// ------------------------------
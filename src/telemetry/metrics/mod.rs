@@ -1,9 +1,9 @@
 pub mod http;
 pub mod ws;
 
-use crate::cnf::{TELEMETRY_DISABLE_METRICS, TELEMETRY_PROVIDER};
+use crate::cnf::TELEMETRY_DISABLE_METRICS;
 use opentelemetry::metrics::MetricsError;
-use opentelemetry_otlp::MetricsExporterBuilder;
+use opentelemetry_otlp::{MetricsExporterBuilder, WithExportConfig};
 use opentelemetry_sdk::metrics::reader::{DefaultAggregationSelector, DefaultTemporalitySelector};
 use opentelemetry_sdk::metrics::{
 	Aggregation, Instrument, PeriodicReader, SdkMeterProvider, Stream,
@@ -39,13 +39,22 @@ const HISTOGRAM_BUCKETS_BYTES: &[f64] = &[
 	100.0 * MB,
 ];
 
-// Returns a metrics configuration based on the SURREAL_TELEMETRY_PROVIDER environment variable
-pub fn init() -> Result<Option<SdkMeterProvider>, MetricsError> {
-	match TELEMETRY_PROVIDER.trim() {
+// Returns a metrics configuration based on the configured telemetry provider
+pub fn init(
+	provider: Option<&str>,
+	endpoint: Option<&str>,
+) -> Result<Option<SdkMeterProvider>, MetricsError> {
+	match provider.unwrap_or_default().trim() {
 		// The OTLP telemetry provider has been specified
 		s if s.eq_ignore_ascii_case("otlp") && !*TELEMETRY_DISABLE_METRICS => {
+			// Create a new OTLP exporter using gRPC
+			let mut exporter = opentelemetry_otlp::new_exporter().tonic();
+			// Send the metrics to the specified collector
+			if let Some(endpoint) = endpoint {
+				exporter = exporter.with_endpoint(endpoint);
+			}
 			// Create a new metrics exporter using tonic
-			let exporter = MetricsExporterBuilder::from(opentelemetry_otlp::new_exporter().tonic())
+			let exporter = MetricsExporterBuilder::from(exporter)
 				.build_metrics_exporter(
 					Box::new(DefaultTemporalitySelector::new()),
 					Box::new(DefaultAggregationSelector::new()),
@@ -3,6 +3,7 @@ pub mod metrics;
 pub mod traces;
 
 use crate::cli::validator::parser::env_filter::CustomEnvFilter;
+use crate::cnf::TELEMETRY_PROVIDER;
 use crate::err::Error;
 use opentelemetry::global;
 use opentelemetry::KeyValue;
@@ -43,6 +44,8 @@ pub static OTEL_DEFAULT_RESOURCE: LazyLock<Resource> = LazyLock::new(|| {
 #[derive(Debug, Clone)]
 pub struct Builder {
 	filter: CustomEnvFilter,
+	provider: Option<String>,
+	endpoint: Option<String>,
 }
 
 pub fn builder() -> Builder {
@@ -53,6 +56,8 @@ impl Default for Builder {
 	fn default() -> Self {
 		Self {
 			filter: CustomEnvFilter(EnvFilter::default()),
+			provider: Some(TELEMETRY_PROVIDER.trim().to_owned()).filter(|v| !v.is_empty()),
+			endpoint: None,
 		}
 	}
 }
@@ -82,6 +87,15 @@ impl Builder {
 		self
 	}
 
+	/// Set the telemetry provider, and the collector endpoint, on the builder
+	pub fn with_telemetry(mut self, provider: Option<String>, endpoint: Option<String>) -> Self {
+		if provider.is_some() {
+			self.provider = provider;
+		}
+		self.endpoint = endpoint;
+		self
+	}
+
 	/// Build a tracing dispatcher with the logs and tracer subscriber
 	pub fn build(
 		&self,
@@ -99,7 +113,8 @@ impl Builder {
 		// Create the logging destination layer
 		let log_layer = logs::new(self.filter.clone(), stdout, stderr)?;
 		// Create the trace destination layer
-		let trace_layer = traces::new(self.filter.clone())?;
+		let trace_layer =
+			traces::new(self.filter.clone(), self.provider.as_deref(), self.endpoint.as_deref())?;
 		// Setup a registry for composing layers
 		let registry = tracing_subscriber::registry();
 		// Setup logging layer
@@ -107,7 +122,7 @@ impl Builder {
 		// Setup tracing layer
 		let registry = registry.with(trace_layer);
 		// Setup the metrics layer
-		if let Some(provider) = metrics::init()? {
+		if let Some(provider) = metrics::init(self.provider.as_deref(), self.endpoint.as_deref())? {
 			global::set_meter_provider(provider);
 		}
 		// Return the registry
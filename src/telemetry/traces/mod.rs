@@ -1,25 +1,33 @@
 pub mod rpc;
 
 use crate::cli::validator::parser::env_filter::CustomEnvFilter;
-use crate::cnf::{TELEMETRY_DISABLE_TRACING, TELEMETRY_PROVIDER};
+use crate::cnf::TELEMETRY_DISABLE_TRACING;
 use crate::err::Error;
 use crate::telemetry::OTEL_DEFAULT_RESOURCE;
 use opentelemetry::trace::TracerProvider as _;
-use opentelemetry_otlp::SpanExporterBuilder;
+use opentelemetry_otlp::{SpanExporterBuilder, WithExportConfig};
 use opentelemetry_sdk::trace::{Config, TracerProvider};
 use tracing::Subscriber;
 use tracing_subscriber::Layer;
 
-// Returns a tracer provider based on the SURREAL_TELEMETRY_PROVIDER environment variable
-pub fn new<S>(filter: CustomEnvFilter) -> Result<Option<Box<dyn Layer<S> + Send + Sync>>, Error>
+// Returns a tracer provider based on the configured telemetry provider
+pub fn new<S>(
+	filter: CustomEnvFilter,
+	provider: Option<&str>,
+	endpoint: Option<&str>,
+) -> Result<Option<Box<dyn Layer<S> + Send + Sync>>, Error>
 where
 	S: Subscriber + for<'a> tracing_subscriber::registry::LookupSpan<'a> + Send + Sync,
 {
-	match TELEMETRY_PROVIDER.trim() {
+	match provider.unwrap_or_default().trim() {
 		// The OTLP telemetry provider has been specified
 		s if s.eq_ignore_ascii_case("otlp") && !*TELEMETRY_DISABLE_TRACING => {
 			// Create a new OTLP exporter using gRPC
-			let exporter = opentelemetry_otlp::new_exporter().tonic();
+			let mut exporter = opentelemetry_otlp::new_exporter().tonic();
+			// Send the spans to the specified collector
+			if let Some(endpoint) = endpoint {
+				exporter = exporter.with_endpoint(endpoint);
+			}
 			// Build a new span exporter which uses gRPC
 			let span_exporter = SpanExporterBuilder::Tonic(exporter).build_span_exporter()?;
 			// Define the OTEL metadata configuration
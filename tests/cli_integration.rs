@@ -108,6 +108,16 @@ mod cli_integration {
 			assert!(output.contains("INSERT [ { id: thing:one } ];"));
 		}
 
+		info!("* Export selected tables without records");
+		{
+			let args = format!(
+				"export --conn http://{addr} {creds} --ns {ns} --db {db} --tables thing,other --no-records -"
+			);
+			let output = common::run(&args).output().expect("failed to run stdout export: {args}");
+			assert!(output.contains("DEFINE TABLE thing TYPE ANY SCHEMALESS PERMISSIONS NONE;"));
+			assert!(!output.contains("INSERT [ { id: thing:one } ];"));
+		}
+
 		info!("* Export to file");
 		let exported = {
 			let exported = common::tmp_file("exported.surql");
@@ -139,6 +149,54 @@ mod cli_integration {
 			assert_eq!(rest, "[\n\t{\n\t\tid: thing:one\n\t}\n]\n\n", "failed to send sql: {args}");
 		}
 
+		info!("* Import a CSV file into a table");
+		{
+			let csv_file = common::tmp_file("import.csv");
+			std::fs::write(&csv_file, "id,name,age\none,Tobie,33\ntwo,\"Doe, John\",\n").unwrap();
+			let args = format!(
+				"import-csv --conn http://{addr} {creds} --ns {ns} --db {db2} --table person --type age:int {csv_file}"
+			);
+			common::run(&args).output().expect("failed to run csv import: {args}");
+			let args =
+				format!("sql --conn http://{addr} {creds} --ns {ns} --db {db2} --hide-welcome");
+			let output = common::run(&args)
+				.input("SELECT * FROM person ORDER BY id;\n")
+				.output()
+				.expect("failed to query the csv import: {args}");
+			assert!(
+				output.contains("[{ age: 33, id: person:one, name: 'Tobie' }, { id: person:two, name: 'Doe, John' }]"),
+				"unexpected output: {output}"
+			);
+		}
+
+		info!("* Migrate a database to a directory of schema files");
+		{
+			let schema = common::tmp_file("schema");
+			std::fs::create_dir(&schema).unwrap();
+			std::fs::write(
+				format!("{schema}/person.surql"),
+				"DEFINE FIELD name ON person TYPE string;\nDEFINE TABLE person SCHEMAFULL;\n",
+			)
+			.unwrap();
+			let args = format!(
+				"migrate --conn http://{addr} {creds} --ns {ns} --db {db2} --dry-run {schema}"
+			);
+			let output = common::run(&args).output().expect("failed to run dry-run migration");
+			// The table is defined before its fields
+			let table = output.find("DEFINE TABLE person").expect("missing table definition");
+			let field =
+				output.find("DEFINE FIELD name ON person").expect("missing field definition");
+			assert!(table < field, "unexpected migration: {output}");
+			let args =
+				format!("migrate --conn http://{addr} {creds} --ns {ns} --db {db2} {schema}");
+			common::run(&args).output().expect("failed to run migration");
+			let args = format!(
+				"migrate --conn http://{addr} {creds} --ns {ns} --db {db2} --dry-run {schema}"
+			);
+			let output = common::run(&args).output().expect("failed to run dry-run migration");
+			assert!(!output.contains("DEFINE"), "unexpected migration: {output}");
+		}
+
 		info!("* Advanced uncomputed variable to be computed before saving");
 		{
 			let args = format!(
@@ -233,6 +291,26 @@ mod cli_integration {
 			assert!(output.contains("thing:one"), "missing thing:one in {output}");
 		}
 
+		info!("* Change the namespace and database with a meta-command");
+		{
+			let args = format!("sql --conn http://{addr} {creds}");
+			let output = common::run(&args)
+				.input(&format!("\\use {ns} {db}\nSELECT * FROM thing:one;\n"))
+				.output()
+				.expect("use meta-command");
+			assert!(output.contains("thing:one"), "missing thing:one in {output}");
+		}
+
+		info!("* Send a transaction over multiple lines");
+		{
+			let args = format!("sql --conn http://{addr} {creds} --ns {ns} --db {db}");
+			let output = common::run(&args)
+				.input("BEGIN;\nCREATE thing:txn;\nCOMMIT;\nSELECT * FROM thing:txn;\n")
+				.output()
+				.expect("transaction");
+			assert!(output.contains("thing:txn"), "missing thing:txn in {output}");
+		}
+
 		info!("* Pass only db and expect an error");
 		{
 			let args = format!(
@@ -1168,6 +1246,32 @@ mod cli_integration {
 		assert!(common::run_in_dir("validate", &temp_dir).output().is_err());
 	}
 
+	#[test]
+	fn config_check_succeeds_for_valid_files() {
+		let temp_dir = assert_fs::TempDir::new().unwrap();
+
+		let config_file = temp_dir.child("surreal.toml");
+
+		config_file
+			.write_str("path = \"memory\"\nbind = \"127.0.0.1:8000\"\nno-banner = true")
+			.unwrap();
+
+		assert!(common::run_in_dir("config check surreal.toml", &temp_dir).output().is_ok());
+	}
+
+	#[test]
+	fn config_check_fails_for_invalid_files() {
+		let temp_dir = assert_fs::TempDir::new().unwrap();
+
+		let unknown_file = temp_dir.child("unknown.toml");
+		unknown_file.write_str("unknown = true").unwrap();
+		assert!(common::run_in_dir("config check unknown.toml", &temp_dir).output().is_err());
+
+		let invalid_file = temp_dir.child("invalid.toml");
+		invalid_file.write_str("bind = \"not an address\"").unwrap();
+		assert!(common::run_in_dir("config check invalid.toml", &temp_dir).output().is_err());
+	}
+
 	#[cfg(unix)]
 	#[test(tokio::test)]
 	async fn test_server_graceful_shutdown() {
@@ -2,6 +2,7 @@ use super::common::{self, Format, Socket, StartServerArguments, DB, NS, PASS, US
 use assert_fs::TempDir;
 use http::header::{HeaderMap, HeaderValue};
 use serde_json::json;
+use std::collections::HashMap;
 use std::future::Future;
 use std::pin::Pin;
 use std::time::Duration;
@@ -622,6 +623,153 @@ async fn query() -> Result<(), Box<dyn std::error::Error>> {
 	Ok(())
 }
 
+#[test(tokio::test)]
+async fn prepare_and_execute() -> Result<(), Box<dyn std::error::Error>> {
+	// Setup database server
+	let (addr, mut server) = common::start_server_with_defaults().await.unwrap();
+	// Connect to WebSocket
+	let mut socket = Socket::connect(&addr, SERVER, FORMAT).await?;
+	// Authenticate the connection
+	socket.send_message_signin(USER, PASS, None, None, None).await?;
+	// Specify a namespace and database
+	socket.send_message_use(Some(NS), Some(DB)).await?;
+	// Send PREPARE command
+	let res = socket.send_request("prepare", json!(["CREATE tester SET name = $name"])).await?;
+	assert!(res["result"].is_string(), "result: {res:?}");
+	let id = res["result"].as_str().unwrap().to_owned();
+	// Send EXECUTE command with different parameters
+	for name in ["one", "two"] {
+		let res = socket.send_request("execute", json!([id, { "name": name }])).await?;
+		assert!(res["result"].is_array(), "result: {res:?}");
+		let res = res["result"].as_array().unwrap();
+		assert_eq!(res[0]["result"][0]["name"], name, "result: {res:?}");
+	}
+	// Verify the data was created
+	let res = socket.send_message_query("SELECT * FROM tester").await?;
+	assert!(res[0]["result"].is_array(), "result: {res:?}");
+	let res = res[0]["result"].as_array().unwrap();
+	assert_eq!(res.len(), 2, "result: {res:?}");
+	// Send EXECUTE command with an unknown identifier
+	let res = socket.send_request("execute", json!(["unknown"])).await?;
+	assert_eq!(res["error"]["message"], "The prepared statement was not found", "result: {res:?}");
+	// Test passed
+	server.finish().unwrap();
+	Ok(())
+}
+
+#[test(tokio::test)]
+async fn cursor_and_fetch() -> Result<(), Box<dyn std::error::Error>> {
+	// Setup database server
+	let (addr, mut server) = common::start_server_with_defaults().await.unwrap();
+	// Connect to WebSocket
+	let mut socket = Socket::connect(&addr, SERVER, FORMAT).await?;
+	// Authenticate the connection
+	socket.send_message_signin(USER, PASS, None, None, None).await?;
+	// Specify a namespace and database
+	socket.send_message_use(Some(NS), Some(DB)).await?;
+	// Create some test data
+	socket.send_message_query("CREATE |tester:1..5|").await?;
+	// Send CURSOR command
+	let res =
+		socket.send_request("cursor", json!(["SELECT * FROM tester ORDER BY id", null, 2])).await?;
+	assert!(res["result"]["cursor"].is_string(), "result: {res:?}");
+	assert_eq!(res["result"]["result"].as_array().unwrap().len(), 2, "result: {res:?}");
+	let id = res["result"]["cursor"].as_str().unwrap().to_owned();
+	// Send FETCH command
+	let res = socket.send_request("fetch", json!([id])).await?;
+	assert_eq!(res["result"]["cursor"], id, "result: {res:?}");
+	assert_eq!(res["result"]["result"].as_array().unwrap().len(), 2, "result: {res:?}");
+	// Send FETCH command for the last batch
+	let res = socket.send_request("fetch", json!([id])).await?;
+	assert!(res["result"]["cursor"].is_null(), "result: {res:?}");
+	assert_eq!(res["result"]["result"].as_array().unwrap().len(), 1, "result: {res:?}");
+	// The cursor is closed once all records are fetched
+	let res = socket.send_request("fetch", json!([id])).await?;
+	assert_eq!(res["error"]["message"], "The query cursor was not found", "result: {res:?}");
+	// Send CLOSE_CURSOR command for an open cursor
+	let res = socket.send_request("cursor", json!(["SELECT * FROM tester", null, 2])).await?;
+	let id = res["result"]["cursor"].as_str().unwrap().to_owned();
+	let res = socket.send_request("close_cursor", json!([id])).await?;
+	assert!(res["error"].is_null(), "result: {res:?}");
+	let res = socket.send_request("fetch", json!([id])).await?;
+	assert_eq!(res["error"]["message"], "The query cursor was not found", "result: {res:?}");
+	// Test passed
+	server.finish().unwrap();
+	Ok(())
+}
+
+#[test(tokio::test)]
+async fn changes() -> Result<(), Box<dyn std::error::Error>> {
+	// Setup database server
+	let (addr, mut server) = common::start_server_with_defaults().await.unwrap();
+	// Connect to WebSocket
+	let mut socket = Socket::connect(&addr, SERVER, FORMAT).await?;
+	// Authenticate the connection
+	socket.send_message_signin(USER, PASS, None, None, None).await?;
+	// Specify a namespace and database
+	socket.send_message_use(Some(NS), Some(DB)).await?;
+	// Create a table with a change feed
+	socket.send_message_query("DEFINE TABLE tester CHANGEFEED 1h").await?;
+	socket.send_message_query("CREATE tester:one SET name = 'foo'").await?;
+	socket.send_message_query("CREATE tester:two SET name = 'bar'").await?;
+	// Send CHANGES command
+	let res = socket.send_request("changes", json!(["tester", 0])).await?;
+	assert!(res["result"].is_array(), "result: {res:?}");
+	let res = res["result"].as_array().unwrap();
+	let names: Vec<_> = res
+		.iter()
+		.flat_map(|v| v["changes"].as_array().unwrap())
+		.filter_map(|v| v["update"]["name"].as_str())
+		.collect();
+	assert_eq!(names, vec!["foo", "bar"], "result: {res:?}");
+	// Send CHANGES command with a limit
+	let res = socket.send_request("changes", json!(["tester", 0, 1])).await?;
+	assert_eq!(res["result"].as_array().unwrap().len(), 1, "result: {res:?}");
+	// Send CHANGES command with an invalid starting point
+	let res = socket.send_request("changes", json!(["tester", "invalid"])).await?;
+	assert!(res["error"].is_object(), "result: {res:?}");
+	// Send CHANGES command with a limit which is too large
+	let res = socket.send_request("changes", json!(["tester", 0, 4294967296u64])).await?;
+	assert!(res["error"].is_object(), "result: {res:?}");
+	// Test passed
+	server.finish().unwrap();
+	Ok(())
+}
+
+#[test(tokio::test)]
+async fn cancel() -> Result<(), Box<dyn std::error::Error>> {
+	// Setup database server
+	let (addr, mut server) = common::start_server_with_defaults().await.unwrap();
+	// Connect to WebSocket
+	let mut socket = Socket::connect(&addr, SERVER, FORMAT).await?;
+	// Authenticate the connection
+	socket.send_message_signin(USER, PASS, None, None, None).await?;
+	// Specify a namespace and database
+	socket.send_message_use(Some(NS), Some(DB)).await?;
+	// Send CANCEL command for a request which does not exist
+	let res = socket.send_request("cancel", json!([100])).await?;
+	assert_eq!(
+		res["error"]["message"], "The request was not found, or has already finished",
+		"result: {res:?}"
+	);
+	// Send a long running query, which is the fourth request on this socket
+	let (res, cancelled) =
+		tokio::join!(socket.send_request("query", json!(["SLEEP 10s"])), async {
+			// Wait for the query to start
+			tokio::time::sleep(Duration::from_millis(500)).await;
+			// Send CANCEL command for the running query
+			socket.send_request("cancel", json!([3])).await
+		});
+	let (res, cancelled) = (res?, cancelled?);
+	assert!(cancelled["error"].is_null(), "result: {cancelled:?}");
+	assert_eq!(res["error"]["message"], "The request was cancelled", "result: {res:?}");
+	// The connection can still be used after cancelling a request
+	socket.send_message_query("INFO FOR DB").await?;
+	// Test passed
+	server.finish().unwrap();
+	Ok(())
+}
+
 #[test(tokio::test)]
 async fn version() -> Result<(), Box<dyn std::error::Error>> {
 	// Setup database server
@@ -1907,6 +2055,41 @@ async fn temporary_directory() {
 	temp_dir.close().unwrap();
 }
 
+#[test(tokio::test)]
+async fn temporary_directory_external_sort() {
+	// Setup database server, which sorts more than two records in the temporary directory
+	let temp_dir = TempDir::new().unwrap();
+	let (addr, mut server) = common::start_server(StartServerArguments {
+		temporary_directory: Some(temp_dir.to_string_lossy().to_string()),
+		vars: Some(HashMap::from([(
+			"SURREAL_EXTERNAL_SORTING_ROW_THRESHOLD".to_string(),
+			"2".to_string(),
+		)])),
+		..Default::default()
+	})
+	.await
+	.unwrap();
+	// Connect to WebSocket
+	let mut socket = Socket::connect(&addr, SERVER, FORMAT).await.unwrap();
+	// Authenticate the connection
+	socket.send_message_signin(USER, PASS, None, None, None).await.unwrap();
+	// Specify a namespace and database
+	socket.send_message_use(Some(NS), Some(DB)).await.unwrap();
+	// create records
+	socket.send_message_query("CREATE test:a, test:b, test:c, test:d").await.unwrap();
+	// The sort is moved to the temporary directory, and returns the correct result
+	let mut res = socket
+		.send_message_query("SELECT * FROM test ORDER BY id DESC START 1 LIMIT 2")
+		.await
+		.unwrap();
+	let expected = json!([{"id": "test:c" }, { "id": "test:b" }]);
+	assert_eq!(res.remove(0)["result"], expected);
+	// Test passed
+	server.finish().unwrap();
+	// Cleanup
+	temp_dir.close().unwrap();
+}
+
 #[test(tokio::test)]
 async fn session_id_defined() {
 	// Setup database server
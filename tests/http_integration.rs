@@ -428,6 +428,42 @@ mod http_integration {
 		Ok(())
 	}
 
+	#[test(tokio::test)]
+	async fn metrics_endpoint() -> Result<(), Box<dyn std::error::Error>> {
+		let (addr, _server) = common::start_server_with_defaults().await.unwrap();
+		let client = Client::default();
+
+		// Run a query, so that statement metrics are recorded
+		{
+			let res = client
+				.post(format!("http://{addr}/sql"))
+				.basic_auth(USER, Some(PASS))
+				.header("surreal-ns", Ulid::new().to_string())
+				.header("surreal-db", Ulid::new().to_string())
+				.header(header::ACCEPT, "application/json")
+				.body("CREATE foo; SELECT * FROM foo;")
+				.send()
+				.await?;
+			assert_eq!(res.status(), 200, "body: {}", res.text().await?);
+		}
+
+		// The metrics are exposed in the Prometheus text format
+		{
+			let res = client.get(format!("http://{addr}/metrics")).send().await?;
+			assert_eq!(res.status(), 200, "response: {res:#?}");
+			let body = res.text().await?;
+			assert!(
+				body.contains("# TYPE surrealdb_statement_duration_seconds histogram"),
+				"body: {body}"
+			);
+			assert!(body.contains(r#"statement="select""#), "body: {body}");
+			assert!(body.contains("surrealdb_transaction_commits_total"), "body: {body}");
+			assert!(body.contains("surrealdb_connections 0"), "body: {body}");
+		}
+
+		Ok(())
+	}
+
 	#[test(tokio::test)]
 	async fn no_server_id_headers() -> Result<(), Box<dyn std::error::Error>> {
 		// default server has the id headers
@@ -904,6 +940,34 @@ mod http_integration {
 			let _: ciborium::Value = ciborium::from_reader(res.as_slice()).unwrap();
 		}
 
+		// Results with more records than a single chunk are encoded incrementally
+		{
+			let sql = "CREATE |bar:2500| RETURN NONE; SELECT id FROM bar; RETURN 1; THROW 'fail'";
+			let res = client.post(url).basic_auth(USER, Some(PASS)).body(sql).send().await?;
+			assert_eq!(res.status(), 200);
+			let body: serde_json::Value = serde_json::from_str(&res.text().await?).unwrap();
+			assert_eq!(body.as_array().unwrap().len(), 4, "body: {body}");
+			assert_eq!(body[1]["status"], "OK", "body: {body}");
+			assert_eq!(body[1]["result"].as_array().unwrap().len(), 2500);
+			assert_eq!(body[2]["result"], 1, "body: {body}");
+			assert_eq!(body[3]["status"], "ERR", "body: {body}");
+			assert_eq!(body[3]["result"], "An error occurred: fail", "body: {body}");
+			// The same responses are encoded as CBOR
+			let res = client
+				.post(url)
+				.basic_auth(USER, Some(PASS))
+				.header(header::ACCEPT, "application/cbor")
+				.body("SELECT id FROM bar; RETURN 1")
+				.send()
+				.await?;
+			assert_eq!(res.status(), 200);
+			let res = res.bytes().await?.to_vec();
+			let body: serde_json::Value = ciborium::from_reader(res.as_slice()).unwrap();
+			assert_eq!(body[0]["result"].as_array().unwrap().len(), 2500);
+			assert_eq!(body[1]["status"], "OK", "body: {body}");
+			assert_eq!(body[1]["result"], 1, "body: {body}");
+		}
+
 		// Creating a record with Accept PACK encoding is allowed
 		{
 			let res = client
@@ -1039,6 +1103,16 @@ mod http_integration {
 		let body = res.text().await?;
 		assert!(body.starts_with("surrealdb-"), "body: {body}");
 
+		// The build information is returned as JSON
+		let res =
+			Client::default().get(url).header(header::ACCEPT, "application/json").send().await?;
+		assert_eq!(res.status(), 200, "response: {res:#?}");
+		let body: serde_json::Value = serde_json::from_str(&res.text().await?)?;
+		assert_eq!(body["name"], "surrealdb", "body: {body}");
+		assert!(body["version"].is_string(), "body: {body}");
+		assert!(body["os"].is_string(), "body: {body}");
+		assert!(body["arch"].is_string(), "body: {body}");
+
 		Ok(())
 	}
 